@@ -22,6 +22,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/bmizerany/perks/quantile"
 	"github.com/spf13/cobra"
 
 	log "github.com/sirupsen/logrus"
@@ -74,8 +75,9 @@ func consume(consumeArgs *ConsumeArgs, stop <-chan struct{}) {
 	defer client.Close()
 
 	consumer, err := client.Subscribe(pulsar.ConsumerOptions{
-		Topic:            consumeArgs.Topic,
-		SubscriptionName: consumeArgs.SubscriptionName,
+		Topic:             consumeArgs.Topic,
+		SubscriptionName:  consumeArgs.SubscriptionName,
+		ReceiverQueueSize: consumeArgs.ReceiverQueueSize,
 	})
 
 	if err != nil {
@@ -88,9 +90,10 @@ func consume(consumeArgs *ConsumeArgs, stop <-chan struct{}) {
 	msgReceived := int64(0)
 	bytesReceived := int64(0)
 
-	// Print stats of the consume rate
+	// Print stats of the consume rate and of the end to end latencies
 	tick := time.NewTicker(10 * time.Second)
 	defer tick.Stop()
+	q := quantile.NewTargeted(0.50, 0.95, 0.99, 0.999, 1.0)
 
 	for {
 		select {
@@ -100,6 +103,7 @@ func consume(consumeArgs *ConsumeArgs, stop <-chan struct{}) {
 			}
 			msgReceived++
 			bytesReceived += int64(len(cm.Message.Payload()))
+			q.Insert(time.Since(cm.Message.PublishTime()).Seconds())
 			consumer.Ack(cm.Message)
 		case <-tick.C:
 			currentMsgReceived := atomic.SwapInt64(&msgReceived, 0)
@@ -107,8 +111,16 @@ func consume(consumeArgs *ConsumeArgs, stop <-chan struct{}) {
 			msgRate := float64(currentMsgReceived) / float64(10)
 			bytesRate := float64(currentBytesReceived) / float64(10)
 
-			log.Infof(`Stats - Consume rate: %6.1f msg/s - %6.1f Mbps`,
-				msgRate, bytesRate*8/1024/1024)
+			log.Infof(`Stats - Consume rate: %6.1f msg/s - %6.1f Mbps - 
+				Latency ms: 50%% %5.1f -95%% %5.1f - 99%% %5.1f - 99.9%% %5.1f - max %6.1f`,
+				msgRate, bytesRate*8/1024/1024,
+				q.Query(0.5)*1000,
+				q.Query(0.95)*1000,
+				q.Query(0.99)*1000,
+				q.Query(0.999)*1000,
+				q.Query(1.0)*1000,
+			)
+			q.Reset()
 		case <-stop:
 			return
 		}
@@ -20,6 +20,11 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/beefsack/go-rate"
@@ -33,12 +38,18 @@ import (
 
 // ProduceArgs define the parameters required by produce
 type ProduceArgs struct {
-	Topic              string
-	Rate               int
-	BatchingTimeMillis int
-	BatchingMaxSize    int
-	MessageSize        int
-	ProducerQueueSize  int
+	Topic               string
+	Rate                int
+	BatchingTimeMillis  int
+	BatchingMaxSize     int
+	BatchingMaxMessages int
+	DisableBatching     bool
+	Compression         string
+	MessageSize         int
+	ProducerQueueSize   int
+	NumMessages         int
+	Partitions          int
+	AdminURL            string
 }
 
 func newProducerCommand() *cobra.Command {
@@ -65,10 +76,22 @@ func newProducerCommand() *cobra.Command {
 		"Batching grouping time in millis")
 	flags.IntVarP(&produceArgs.BatchingMaxSize, "batching-max-size", "", 128,
 		"Max size of a batch (in KB)")
+	flags.IntVarP(&produceArgs.BatchingMaxMessages, "batching-max-messages", "", 1000,
+		"Max number of messages in a batch")
+	flags.BoolVar(&produceArgs.DisableBatching, "disable-batching", false,
+		"Send the messages individually")
+	flags.StringVarP(&produceArgs.Compression, "compression", "z", "none",
+		"Compression type: none, lz4, zlib or zstd")
 	flags.IntVarP(&produceArgs.MessageSize, "size", "s", 1024,
 		"Message size")
 	flags.IntVarP(&produceArgs.ProducerQueueSize, "queue-size", "q", 1000,
 		"Produce queue size")
+	flags.IntVarP(&produceArgs.NumMessages, "num-messages", "m", 0,
+		"Number of messages to publish before exiting. Set to 0 to publish until interrupted")
+	flags.IntVarP(&produceArgs.Partitions, "partitions", "p", 0,
+		"Create the topic with the given number of partitions first, through the admin API")
+	flags.StringVar(&produceArgs.AdminURL, "admin-url", "http://localhost:8080",
+		"The Pulsar admin URL, used to create the partitioned topic")
 
 	return cmd
 }
@@ -79,6 +102,17 @@ func produce(produceArgs *ProduceArgs, stop <-chan struct{}) {
 	b, _ = json.MarshalIndent(produceArgs, "", "  ")
 	log.Info("Producer config: ", string(b))
 
+	compressionType, err := parseCompressionType(produceArgs.Compression)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if produceArgs.Partitions > 0 {
+		if err := createPartitionedTopic(produceArgs.AdminURL, produceArgs.Topic, produceArgs.Partitions); err != nil {
+			log.Fatal(err)
+		}
+	}
+
 	client, err := NewClient()
 	if err != nil {
 		log.Fatal(err)
@@ -90,6 +124,9 @@ func produce(produceArgs *ProduceArgs, stop <-chan struct{}) {
 		MaxPendingMessages:      produceArgs.ProducerQueueSize,
 		BatchingMaxPublishDelay: time.Millisecond * time.Duration(produceArgs.BatchingTimeMillis),
 		BatchingMaxSize:         uint(produceArgs.BatchingMaxSize * 1024),
+		BatchingMaxMessages:     uint(produceArgs.BatchingMaxMessages),
+		DisableBatching:         produceArgs.DisableBatching,
+		CompressionType:         compressionType,
 	})
 	if err != nil {
 		log.Fatal(err)
@@ -108,7 +145,7 @@ func produce(produceArgs *ProduceArgs, stop <-chan struct{}) {
 			rateLimiter = rate.New(produceArgs.Rate, time.Second)
 		}
 
-		for {
+		for sent := 0; produceArgs.NumMessages <= 0 || sent < produceArgs.NumMessages; sent++ {
 			select {
 			case <-stopCh:
 				return
@@ -139,6 +176,7 @@ func produce(produceArgs *ProduceArgs, stop <-chan struct{}) {
 	defer tick.Stop()
 	q := quantile.NewTargeted(0.50, 0.95, 0.99, 0.999, 1.0)
 	messagesPublished := 0
+	totalPublished := 0
 
 	for {
 		select {
@@ -161,7 +199,71 @@ func produce(produceArgs *ProduceArgs, stop <-chan struct{}) {
 			messagesPublished = 0
 		case latency := <-ch:
 			messagesPublished++
+			totalPublished++
 			q.Insert(latency)
+			if totalPublished == produceArgs.NumMessages {
+				log.Infof("Published %d messages", totalPublished)
+				return
+			}
 		}
 	}
 }
+
+func parseCompressionType(compression string) (pulsar.CompressionType, error) {
+	switch strings.ToLower(compression) {
+	case "", "none":
+		return pulsar.NoCompression, nil
+	case "lz4":
+		return pulsar.LZ4, nil
+	case "zlib":
+		return pulsar.ZLib, nil
+	case "zstd":
+		return pulsar.ZSTD, nil
+	default:
+		return pulsar.NoCompression, fmt.Errorf("unknown compression type %q", compression)
+	}
+}
+
+// createPartitionedTopic creates the topic with the given number of partitions, a topic which already exists is
+// left as is
+func createPartitionedTopic(adminURL, topic string, partitions int) error {
+	name := topic
+	if !strings.Contains(name, "://") {
+		if !strings.Contains(name, "/") {
+			name = "public/default/" + name
+		}
+		name = "persistent://" + name
+	}
+	url := fmt.Sprintf("%s/admin/v2/%s/partitions", strings.TrimSuffix(adminURL, "/"),
+		strings.Replace(name, "://", "/", 1))
+
+	req, err := http.NewRequest(http.MethodPut, url, strings.NewReader(strconv.Itoa(partitions)))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if clientArgs.TokenFile != "" {
+		token, err := ioutil.ReadFile(clientArgs.TokenFile)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusConflict:
+		log.Infof("Topic %s already exists", name)
+	case resp.StatusCode >= 300:
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("failed to create the topic %s with %d partitions: %s %s", name, partitions,
+			resp.Status, body)
+	default:
+		log.Infof("Created the topic %s with %d partitions", name, partitions)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package pulsar
+
+import (
+	"sync"
+	"time"
+)
+
+// activeConsumerTracker records the changes of the active consumer of a Failover subscription that the broker
+// notifies to a partition consumer
+type activeConsumerTracker struct {
+	lock       sync.RWMutex
+	notified   bool
+	active     bool
+	changes    int
+	lastChange time.Time
+}
+
+// changed records the notification, it returns false when the partition consumer kept its state
+func (t *activeConsumerTracker) changed(isActive bool) bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.notified && t.active == isActive {
+		return false
+	}
+	t.notified = true
+	t.active = isActive
+	t.changes++
+	t.lastChange = time.Now()
+	return true
+}
+
+// ActiveConsumerChanges returns how many times the partition consumer became active or inactive
+func (t *activeConsumerTracker) ActiveConsumerChanges() int {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	return t.changes
+}
+
+// LastActiveConsumerChange returns when the partition consumer became active or inactive for the last time,
+// or the zero time if the broker never notified it
+func (t *activeConsumerTracker) LastActiveConsumerChange() time.Time {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	return t.lastChange
+}
@@ -0,0 +1,43 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package pulsar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActiveConsumerTracker(t *testing.T) {
+	tracker := &activeConsumerTracker{}
+	assert.Equal(t, 0, tracker.ActiveConsumerChanges())
+	assert.True(t, tracker.LastActiveConsumerChange().IsZero())
+
+	// the first notification assigns the partition, whatever its state
+	before := time.Now()
+	assert.True(t, tracker.changed(false))
+	assert.Equal(t, 1, tracker.ActiveConsumerChanges())
+	assert.False(t, tracker.LastActiveConsumerChange().Before(before))
+
+	assert.False(t, tracker.changed(false))
+	assert.True(t, tracker.changed(true))
+	assert.False(t, tracker.changed(true))
+	assert.True(t, tracker.changed(false))
+	assert.Equal(t, 3, tracker.ActiveConsumerChanges())
+}
@@ -0,0 +1,207 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package pulsar
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/apache/pulsar-client-go/pulsar/internal"
+	"github.com/apache/pulsar-client-go/pulsar/log"
+)
+
+const (
+	defaultFailoverCheckInterval = time.Second
+	defaultFailoverProbeTimeout  = 5 * time.Second
+)
+
+// AutoClusterFailoverOptions configures the failover between a primary and a secondary cluster
+type AutoClusterFailoverOptions struct {
+	// The cluster the client connects to while it's available. This parameter is required
+	Primary ClusterConfig
+
+	// The cluster the client switches to when the primary one is unavailable. This parameter is required
+	Secondary ClusterConfig
+
+	// How long the primary cluster has to be unreachable before the client switches to the secondary one
+	FailoverDelay time.Duration
+
+	// How long the primary cluster has to be reachable again before the client switches back to it
+	SwitchBackDelay time.Duration
+
+	// The interval between the probes of the primary cluster (default: 1 second)
+	CheckInterval time.Duration
+
+	// Configure the logger used by the failover.
+	// By default, a wrapped logrus.StandardLogger will be used
+	Logger log.Logger
+}
+
+type autoClusterFailover struct {
+	sync.Mutex
+	options AutoClusterFailoverOptions
+	log     log.Logger
+
+	// onPrimary is guarded by the mutex, the other fields are only used by the goroutine running the checks
+	onPrimary      bool
+	failedSince    time.Time
+	recoveredSince time.Time
+
+	probe         func(cluster ClusterConfig) bool
+	switchCluster func(ClusterConfig) error
+
+	closeCh   chan struct{}
+	closeOnce sync.Once
+}
+
+// NewAutoClusterFailover returns a ServiceURLProvider probing the primary cluster, the client is switched
+// to the secondary cluster once the primary has been unreachable for the FailoverDelay and switched back
+// once it has been reachable again for the SwitchBackDelay.
+func NewAutoClusterFailover(options AutoClusterFailoverOptions) (ServiceURLProvider, error) {
+	if options.Primary.URL == "" || options.Secondary.URL == "" {
+		return nil, newError(InvalidConfiguration, "primary and secondary service URLs are required")
+	}
+	if options.FailoverDelay < 0 || options.SwitchBackDelay < 0 {
+		return nil, newError(InvalidConfiguration, "failover delays cannot be negative")
+	}
+	if options.CheckInterval <= 0 {
+		options.CheckInterval = defaultFailoverCheckInterval
+	}
+
+	logger := options.Logger
+	if logger == nil {
+		logger = log.NewLoggerWithLogrus(logrus.StandardLogger())
+	}
+
+	return &autoClusterFailover{
+		options:   options,
+		log:       logger.SubLogger(log.Fields{"primary": options.Primary.URL}),
+		onPrimary: true,
+		probe:     probeCluster,
+		closeCh:   make(chan struct{}),
+	}, nil
+}
+
+func (f *autoClusterFailover) ServiceCluster() ClusterConfig {
+	f.Lock()
+	defer f.Unlock()
+	if f.onPrimary {
+		return f.options.Primary
+	}
+	return f.options.Secondary
+}
+
+func (f *autoClusterFailover) Initialize(switchCluster func(ClusterConfig) error) error {
+	f.switchCluster = switchCluster
+	go f.run()
+	return nil
+}
+
+func (f *autoClusterFailover) Close() {
+	f.closeOnce.Do(func() {
+		close(f.closeCh)
+	})
+}
+
+func (f *autoClusterFailover) run() {
+	ticker := time.NewTicker(f.options.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-f.closeCh:
+			return
+		case <-ticker.C:
+			f.check(time.Now())
+		}
+	}
+}
+
+// check probes the primary cluster and switches the client when it has been in the same state for long enough,
+// the probes and the switch run without the lock so that ServiceCluster isn't blocked by the dials
+func (f *autoClusterFailover) check(now time.Time) {
+	primaryAvailable := f.probe(f.options.Primary)
+
+	if f.onPrimary {
+		if primaryAvailable {
+			f.failedSince = time.Time{}
+			return
+		}
+		if f.failedSince.IsZero() {
+			f.failedSince = now
+		}
+		if now.Sub(f.failedSince) < f.options.FailoverDelay || !f.probe(f.options.Secondary) {
+			return
+		}
+
+		f.log.Warnf("Primary cluster unavailable since %v, switching to %s", f.failedSince,
+			f.options.Secondary.URL)
+		if err := f.switchCluster(f.options.Secondary); err != nil {
+			f.log.WithError(err).Error("Failed to switch to the secondary cluster")
+			return
+		}
+		f.setOnPrimary(false)
+		f.failedSince = time.Time{}
+		return
+	}
+
+	if !primaryAvailable {
+		f.recoveredSince = time.Time{}
+		return
+	}
+	if f.recoveredSince.IsZero() {
+		f.recoveredSince = now
+	}
+	if now.Sub(f.recoveredSince) < f.options.SwitchBackDelay {
+		return
+	}
+
+	f.log.Infof("Primary cluster available since %v, switching back to it", f.recoveredSince)
+	if err := f.switchCluster(f.options.Primary); err != nil {
+		f.log.WithError(err).Error("Failed to switch back to the primary cluster")
+		return
+	}
+	f.setOnPrimary(true)
+	f.recoveredSince = time.Time{}
+}
+
+func (f *autoClusterFailover) setOnPrimary(onPrimary bool) {
+	f.Lock()
+	defer f.Unlock()
+	f.onPrimary = onPrimary
+}
+
+// probeCluster checks whether any of the hosts of the cluster accepts TCP connections
+func probeCluster(cluster ClusterConfig) bool {
+	uri, err := internal.NewPulsarServiceURIFromURIString(cluster.URL)
+	if err != nil {
+		return false
+	}
+
+	for _, host := range uri.ServiceHosts {
+		conn, err := net.DialTimeout("tcp", host, defaultFailoverProbeTimeout)
+		if err == nil {
+			conn.Close()
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,165 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package pulsar
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/pulsar-client-go/pulsar/log"
+)
+
+func TestAutoClusterFailoverOptions(t *testing.T) {
+	_, err := NewAutoClusterFailover(AutoClusterFailoverOptions{
+		Primary: ClusterConfig{URL: "pulsar://primary:6650"},
+	})
+	assert.Equal(t, InvalidConfiguration, err.(*Error).Result())
+
+	_, err = NewAutoClusterFailover(AutoClusterFailoverOptions{
+		Primary:       ClusterConfig{URL: "pulsar://primary:6650"},
+		Secondary:     ClusterConfig{URL: "pulsar://secondary:6650"},
+		FailoverDelay: -time.Second,
+	})
+	assert.Equal(t, InvalidConfiguration, err.(*Error).Result())
+}
+
+func TestAutoClusterFailoverSwitch(t *testing.T) {
+	provider, err := NewAutoClusterFailover(AutoClusterFailoverOptions{
+		Primary:         ClusterConfig{URL: "pulsar://primary:6650"},
+		Secondary:       ClusterConfig{URL: "pulsar://secondary:6650"},
+		FailoverDelay:   10 * time.Second,
+		SwitchBackDelay: 20 * time.Second,
+		Logger:          log.DefaultNopLogger(),
+	})
+	assert.Nil(t, err)
+	f := provider.(*autoClusterFailover)
+
+	available := map[string]bool{
+		"pulsar://primary:6650":   true,
+		"pulsar://secondary:6650": true,
+	}
+	f.probe = func(cluster ClusterConfig) bool {
+		return available[cluster.URL]
+	}
+	var switches []string
+	f.switchCluster = func(cluster ClusterConfig) error {
+		switches = append(switches, cluster.URL)
+		return nil
+	}
+
+	assert.Equal(t, "pulsar://primary:6650", f.ServiceCluster().URL)
+	start := time.Now()
+
+	f.check(start)
+	assert.Empty(t, switches)
+
+	// the primary has to be unavailable for the whole failover delay
+	available["pulsar://primary:6650"] = false
+	f.check(start.Add(time.Second))
+	f.check(start.Add(5 * time.Second))
+	assert.Empty(t, switches)
+	f.check(start.Add(11 * time.Second))
+	assert.Equal(t, []string{"pulsar://secondary:6650"}, switches)
+	assert.Equal(t, "pulsar://secondary:6650", f.ServiceCluster().URL)
+
+	// flapping primary resets the switch back delay
+	available["pulsar://primary:6650"] = true
+	f.check(start.Add(12 * time.Second))
+	available["pulsar://primary:6650"] = false
+	f.check(start.Add(20 * time.Second))
+	available["pulsar://primary:6650"] = true
+	f.check(start.Add(21 * time.Second))
+	f.check(start.Add(35 * time.Second))
+	assert.Equal(t, []string{"pulsar://secondary:6650"}, switches)
+
+	f.check(start.Add(41 * time.Second))
+	assert.Equal(t, []string{"pulsar://secondary:6650", "pulsar://primary:6650"}, switches)
+	assert.Equal(t, "pulsar://primary:6650", f.ServiceCluster().URL)
+}
+
+func TestAutoClusterFailoverSecondaryUnavailable(t *testing.T) {
+	provider, err := NewAutoClusterFailover(AutoClusterFailoverOptions{
+		Primary:   ClusterConfig{URL: "pulsar://primary:6650"},
+		Secondary: ClusterConfig{URL: "pulsar://secondary:6650"},
+		Logger:    log.DefaultNopLogger(),
+	})
+	assert.Nil(t, err)
+	f := provider.(*autoClusterFailover)
+	f.probe = func(cluster ClusterConfig) bool {
+		return false
+	}
+	f.switchCluster = func(cluster ClusterConfig) error {
+		assert.Fail(t, "Shouldn't switch to an unavailable cluster")
+		return nil
+	}
+
+	f.check(time.Now())
+	assert.Equal(t, "pulsar://primary:6650", f.ServiceCluster().URL)
+}
+
+func TestAutoClusterFailoverProbeWithoutLock(t *testing.T) {
+	provider, err := NewAutoClusterFailover(AutoClusterFailoverOptions{
+		Primary:   ClusterConfig{URL: "pulsar://primary:6650"},
+		Secondary: ClusterConfig{URL: "pulsar://secondary:6650"},
+		Logger:    log.DefaultNopLogger(),
+	})
+	assert.Nil(t, err)
+	f := provider.(*autoClusterFailover)
+	probing := make(chan struct{})
+	release := make(chan struct{})
+	f.probe = func(cluster ClusterConfig) bool {
+		close(probing)
+		<-release
+		return true
+	}
+
+	checked := make(chan struct{})
+	go func() {
+		f.check(time.Now())
+		close(checked)
+	}()
+	<-probing
+
+	// the service cluster is available while the primary cluster is probed
+	served := make(chan string, 1)
+	go func() {
+		served <- f.ServiceCluster().URL
+	}()
+	select {
+	case url := <-served:
+		assert.Equal(t, "pulsar://primary:6650", url)
+	case <-time.After(time.Second):
+		t.Fatal("the service cluster is blocked by the probe")
+	}
+	close(release)
+	<-checked
+}
+
+func TestProbeCluster(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	addr := l.Addr().String()
+
+	assert.True(t, probeCluster(ClusterConfig{URL: "pulsar://" + addr}))
+
+	l.Close()
+	assert.False(t, probeCluster(ClusterConfig{URL: "pulsar://" + addr}))
+}
@@ -0,0 +1,47 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package pulsar
+
+import (
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar/internal"
+)
+
+// BackoffPolicy computes the delays between the attempts to reconnect to a broker or to retry an operation.
+// A new instance is created for every retry loop, so implementations can keep the state of the loop.
+type BackoffPolicy interface {
+	// Next returns the delay to wait before the next attempt
+	Next() time.Duration
+}
+
+// NewExponentialBackoff returns a BackoffPolicy starting with the initial delay and multiplying it after each
+// attempt, up to the max delay. Each delay is randomly shortened or lengthened by up to the jitter factor,
+// between 0 and 1, of the delay. It fails with InvalidConfiguration when the initial delay isn't positive or is
+// above the max delay, when the multiplier is below 1 or when the jitter isn't between 0 and 1.
+func NewExponentialBackoff(initialDelay, maxDelay time.Duration, multiplier, jitter float64) (BackoffPolicy, error) {
+	backoff, err := internal.NewExponentialBackoff(initialDelay, maxDelay, multiplier, jitter)
+	if err != nil {
+		return nil, newError(InvalidConfiguration, err.Error())
+	}
+	return backoff, nil
+}
+
+func newDefaultBackoff() BackoffPolicy {
+	return &internal.Backoff{}
+}
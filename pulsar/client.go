@@ -18,7 +18,10 @@
 package pulsar
 
 import (
+	"context"
 	"crypto/tls"
+	"fmt"
+	"net"
 	"time"
 
 	"github.com/apache/pulsar-client-go/pulsar/internal/auth"
@@ -29,7 +32,9 @@ func NewClient(options ClientOptions) (Client, error) {
 	return newClient(options)
 }
 
-// Opaque interface that represents the authentication credentials
+// Opaque interface that represents the authentication credentials.
+// Authentication providers supply the method name and data sent in the CONNECT command, and
+// answer the AUTH_CHALLENGE commands issued by the broker.
 type Authentication interface{}
 
 func NewAuthentication(name string, params string) (Authentication, error) {
@@ -74,9 +79,40 @@ func NewAuthenticationOAuth2(authParams map[string]string) Authentication {
 	return oauth
 }
 
+// SaslClient performs the client side of a SASL mechanism exchange, eg. the GSSAPI (Kerberos) handshake.
+// A client implementing io.Closer is closed with the connection it authenticated.
+type SaslClient interface {
+	// InitialResponse returns the data sent to the broker to start the exchange.
+	InitialResponse() ([]byte, error)
+
+	// EvaluateChallenge evaluates the challenge sent by the broker and returns the response.
+	EvaluateChallenge(challenge []byte) ([]byte, error)
+}
+
+// NewAuthenticationSasl creates a SASL authentication provider, configured with the `keytab`, `principal`,
+// `serverType` (default: "broker") and `kerberosConfPath` (default: $KRB5_CONFIG or /etc/krb5.conf) params.
+// The clientFactory is invoked for each new connection to create the SASL client negotiating the security
+// context with the broker server principal `<serverType>/<host>`. When it's nil, the client uses the GSSAPI
+// mechanism: the principal logs in to Kerberos with its keytab. NewAuthentication("sasl", params) creates
+// the same GSSAPI provider.
+func NewAuthenticationSasl(authParams map[string]string,
+	clientFactory func(keytabPath, principal, serverPrincipal string) (SaslClient, error)) Authentication {
+	if clientFactory == nil {
+		return auth.NewAuthenticationSaslWithParams(authParams, nil)
+	}
+	return auth.NewAuthenticationSaslWithParams(authParams,
+		func(keytabPath, principal, serverPrincipal string) (auth.SaslClient, error) {
+			return clientFactory(keytabPath, principal, serverPrincipal)
+		})
+}
+
 // Builder interface that is used to construct a Pulsar Client instance.
 type ClientOptions struct {
 	// Configure the service URL for the Pulsar service.
+	// The binary protocol ("pulsar://", "pulsar+ssl://") and the HTTP service ("http://", "https://") URLs
+	// are supported, the latter being used for the topic lookups only.
+	// A "pulsar+unix:///path/to/socket" URL connects to a local broker, or proxy, through the unix socket:
+	// all the connections to the brokers are made to the socket.
 	// This parameter is required
 	URL string
 
@@ -85,9 +121,19 @@ type ClientOptions struct {
 
 	// Set the operation timeout (default: 30 seconds)
 	// Producer-create, subscribe and unsubscribe operations will be retried until this interval, after which the
-	// operation will be marked as failed
+	// operation will be marked as failed with a `TimeoutError`
 	OperationTimeout time.Duration
 
+	// Set the timeout of the creation of the partitions of a producer or consumer, once reached the creation fails
+	// with a `TimeoutError` and the partitions created meanwhile are closed (default: no timeout besides the
+	// OperationTimeout of each partition)
+	CreationTimeout time.Duration
+
+	// Set the interval between the keep-alive PING commands sent to the broker (default: 30 seconds).
+	// A connection which doesn't receive any data from the broker for twice this interval is
+	// considered stale and gets closed, so that producers and consumers reconnect.
+	KeepAliveInterval time.Duration
+
 	// Configure the authentication provider. (default: no authentication)
 	// Example: `Authentication: NewAuthenticationTLS("my-cert.pem", "my-key.pem")`
 	Authentication
@@ -99,19 +145,181 @@ type ClientOptions struct {
 	TLSAllowInsecureConnection bool
 
 	// Configure whether the Pulsar client verify the validity of the host name from broker (default: false)
+	// When disabled, the certificate chain presented by the broker is still verified against the trusted
+	// certificates, unless TLSAllowInsecureConnection is set.
 	TLSValidateHostname bool
 
 	// Max number of connections to a single broker that will kept in the pool. (Default: 1 connection)
 	MaxConnectionsPerBroker int
 
+	// Disable TCP_NODELAY on the connections to the brokers, letting the OS delay and coalesce the small writes
+	// (default: false, TCP_NODELAY is set)
+	DisableTCPNoDelay bool
+
+	// Set the size of the OS send buffer (SO_SNDBUF) of the connections to the brokers, eg. to increase the
+	// throughput on high latency links (default: 0, the OS default)
+	TCPSendBufferSize int
+
+	// Set the size of the OS receive buffer (SO_RCVBUF) of the connections to the brokers
+	// (default: 0, the OS default)
+	TCPReceiveBufferSize int
+
+	// Set the period of the TCP keep-alive probes of the connections to the brokers, a negative period disables
+	// them (default: 15 seconds)
+	TCPKeepAlive time.Duration
+
+	// Dialer replaces the TCP dialer of the connections to the brokers, eg. to go through a SOCKS proxy or a
+	// service mesh. It is called with the "tcp" network and the host:port address of the broker, and is given
+	// the ConnectionTimeout through the context. The TCP socket options are applied to the *net.TCPConn it returns.
+	Dialer func(ctx context.Context, network, addr string) (net.Conn, error)
+
+	// ConnectionListener receives the lifecycle events of the connections, lookups and reconnections of the
+	// client, eg. to emit the telemetry of the application
+	ConnectionListener ConnectionListener
+
+	// Set how long the frames written to a connection, eg. the messages of the producers, wait for more frames
+	// to be coalesced into a single write (default: 0, only the frames already queued are coalesced)
+	ConnectionWriteFlushInterval time.Duration
+
+	// Set the size up to which the frames written to a connection are coalesced into a single write
+	// (default: 64KB)
+	ConnectionWriteMaxPendingBytes int
+
+	// Configure how long the topic lookup results are cached (default: 0, the results are not cached).
+	// A cached result is dropped as soon as the connection to its broker fails or the broker closes a
+	// producer or consumer of the topic, so that they reconnect to the new owner of the topic.
+	LookupCacheTTL time.Duration
+
+	// Configure the name of the broker advertised listener the lookups are answered with, so that a client in
+	// another network than the brokers' internal one gets addresses it can reach. (default: the broker's
+	// default advertised address)
+	ListenerName string
+
+	// Configure the URL of a proxy all the connections to the brokers go through, eg. "pulsar+ssl://proxy:4443".
+	// It requires ProxyProtocol to be set.
+	ProxyURL string
+
+	// Configure the protocol used to reach the brokers behind the ProxyURL
+	ProxyProtocol ProxyProtocol
+
 	// Configure the logger used by the client.
 	// By default, a wrapped logrus.StandardLogger will be used, namely,
-	// log.NewLoggerWithLogrus(logrus.StandardLogger())
+	// log.NewLoggerWithLogrus(logrus.StandardLogger()).
+	// Any implementation of log.Logger can be used to plug in another logging library,
+	// and log.DefaultNopLogger() silences the client entirely.
 	// FIXME: use `logger` as internal field name instead of `log` as it's more idiomatic
 	Logger log.Logger
 
+	// Configure the minimum level of the messages written by the client logger, eg. log.WarnLevel
+	// to drop the reconnection messages of the producers and consumers. (default: log.DebugLevel,
+	// which leaves the filtering to the Logger)
+	LogLevel log.Level
+
 	// Add custom labels to all the metrics reported by this client instance
 	CustomMetricsLabels map[string]string
+
+	// Configure a provider of the cluster the client connects to, which can switch the client to another
+	// cluster at runtime. When set, the URL, Authentication and TLS options are taken from the cluster it
+	// provides. Example: `ServiceURLProvider: NewAutoClusterFailover(...)`
+	ServiceURLProvider ServiceURLProvider
+
+	// Configure the backoff between the attempts to reconnect to the brokers and to retry the operations
+	// failing with a retriable error. It's called to create the policy of every retry loop.
+	// By default, the delay starts at 100ms and doubles up to 60s.
+	// Example, the arguments being checked once beforehand:
+	// `func() BackoffPolicy { b, _ := NewExponentialBackoff(time.Second, time.Minute, 1.5, 0.2); return b }`
+	BackoffPolicy func() BackoffPolicy
+
+	// Enable the transactions, the client discovers the transaction coordinators of the cluster when it's
+	// created. (default: false)
+	EnableTransaction bool
+}
+
+// ClusterConfig defines the service URL of a cluster and the security settings used to connect to it
+type ClusterConfig struct {
+	// The service URL of the cluster, only the binary protocol URLs are supported
+	URL string
+
+	// The authentication provider used with the cluster (default: no authentication)
+	Authentication Authentication
+
+	// Set the path to the trusted TLS certificate file
+	TLSTrustCertsFilePath string
+
+	// Configure whether the Pulsar client accept untrusted TLS certificate from broker (default: false)
+	TLSAllowInsecureConnection bool
+
+	// Configure whether the Pulsar client verify the validity of the host name from broker (default: false)
+	TLSValidateHostname bool
+}
+
+// ServiceURLProvider provides the cluster a client connects to and can switch the client to another one
+// at runtime. The connections are then recreated transparently, producers and consumers reconnecting to
+// the new cluster. All the clusters must use the same URL scheme.
+type ServiceURLProvider interface {
+	// ServiceCluster returns the cluster the client connects to when it's created
+	ServiceCluster() ClusterConfig
+
+	// Initialize is called once the client is created, the provider calls switchCluster
+	// every time the client should connect to another cluster
+	Initialize(switchCluster func(ClusterConfig) error) error
+
+	// Close the provider, it's called when the client is closed
+	Close()
+}
+
+// ProxyProtocol is the protocol used to reach the brokers through the proxy configured in ClientOptions.ProxyURL
+type ProxyProtocol int
+
+const (
+	// ProxyProtocolSNI connects over TLS to the proxy, which forwards the connection to the broker named by
+	// the SNI host name. The URL scheme has to be "pulsar+ssl" and the broker certificate is the one verified.
+	ProxyProtocolSNI ProxyProtocol = iota + 1
+)
+
+// TopicsMode selects the topics of a namespace by their persistence
+type TopicsMode int
+
+const (
+	// PersistentTopics selects the persistent topics
+	PersistentTopics TopicsMode = iota
+	// NonPersistentTopics selects the non-persistent topics
+	NonPersistentTopics
+	// AllTopics selects both the persistent and non-persistent topics
+	AllTopics
+)
+
+// PartitionedTopicMetadata describes how a topic is partitioned
+type PartitionedTopicMetadata struct {
+	// The fully qualified name of the topic
+	Topic string
+
+	// The number of partitions of the topic, 0 when the topic is not partitioned
+	Partitions int
+}
+
+// IsPartitioned returns whether the topic is a partitioned topic
+func (m *PartitionedTopicMetadata) IsPartitioned() bool {
+	return m.Partitions > 0
+}
+
+// PartitionNames returns the fully qualified names of the partitions of the topic, or the topic name itself
+// when the topic is not partitioned
+func (m *PartitionedTopicMetadata) PartitionNames() []string {
+	return m.partitionNames(m.Topic)
+}
+
+// partitionNames names the partitions after the given topic name
+func (m *PartitionedTopicMetadata) partitionNames(topic string) []string {
+	if !m.IsPartitioned() {
+		return []string{m.Topic}
+	}
+
+	partitions := make([]string, m.Partitions)
+	for i := 0; i < m.Partitions; i++ {
+		partitions[i] = fmt.Sprintf("%s-partition-%d", topic, i)
+	}
+	return partitions
 }
 
 type Client interface {
@@ -119,19 +327,33 @@ type Client interface {
 	// This method will block until the producer is created successfully
 	CreateProducer(ProducerOptions) (Producer, error)
 
+	// CreateProducerWithCtx creates the producer like CreateProducer, it returns the context error if the context
+	// is done before the producer is created, eg. while a producer in ProducerAccessModeWaitForExclusive waits for
+	// the exclusive access to the topic
+	CreateProducerWithCtx(context.Context, ProducerOptions) (Producer, error)
+
 	// Create a `Consumer` by subscribing to a topic.
 	//
 	// If the subscription does not exist, a new subscription will be created and all messages published after the
 	// creation will be retained until acknowledged, even if the consumer is not connected
 	Subscribe(ConsumerOptions) (Consumer, error)
 
+	// SubscribeWithCtx subscribes like Subscribe, it returns the context error if the context is done before the
+	// consumer is created. The subscribe requests still waiting for the broker are then abandoned.
+	SubscribeWithCtx(context.Context, ConsumerOptions) (Consumer, error)
+
 	// Create a Reader instance.
 	// This method will block until the reader is created successfully.
 	CreateReader(ReaderOptions) (Reader, error)
 
+	// Create a TableView instance.
+	// This method will block until the table view has read all the existing messages of the topic.
+	CreateTableView(TableViewOptions) (TableView, error)
+
 	// Fetch the list of partitions for a given topic
 	//
-	// If the topic is partitioned, this will return a list of partition names.
+	// If the topic is partitioned, this will return a list of partition names, named after the topic as given,
+	// see PartitionedTopicMetadata.PartitionNames for the fully qualified names.
 	// If the topic is not partitioned, the returned list will contain the topic
 	// name itself.
 	//
@@ -139,6 +361,32 @@ type Client interface {
 	// {@link Consumer} or {@link Producer} instances directly on a particular partition.
 	TopicPartitions(topic string) ([]string, error)
 
+	// Fetch the partitioned topic metadata of a given topic
+	//
+	// The number of partitions is 0 when the topic is not partitioned.
+	GetPartitionedTopicMetadata(topic string) (*PartitionedTopicMetadata, error)
+
+	// Fetch the topics of a namespace, eg. "public/default", selected by their persistence
+	//
+	// The partitioned topics are listed by the names of their partitions.
+	GetTopicsOfNamespace(namespace string, mode TopicsMode) ([]string, error)
+
+	// Open a new transaction, which is aborted by the transaction coordinator if it isn't committed
+	// or aborted within the timeout (default: 1 minute).
+	// It requires the EnableTransaction option.
+	NewTransaction(timeout time.Duration) (Transaction, error)
+
+	// Ping checks the connectivity to the brokers by sending a PING command to one of the service hosts and
+	// returns the round-trip time of the PING, eg. to implement readiness probes.
+	// It requires a pulsar:// or pulsar+ssl:// service URL.
+	Ping(ctx context.Context) (time.Duration, error)
+
 	// Close the Client and free associated resources
 	Close()
+
+	// CloseWithCtx closes the producers, consumers, readers and table views created by the client, after flushing
+	// the messages pending in the producers, then frees the resources of the client. The errors of the producers
+	// and consumers are aggregated in the returned error. The context bounds the time spent flushing and closing
+	// them, the resources of the client are freed anyway.
+	CloseWithCtx(ctx context.Context) error
 }
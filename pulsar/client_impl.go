@@ -18,12 +18,15 @@
 package pulsar
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net"
 	"net/url"
+	"strings"
+	"sync"
 	"time"
 
-	"github.com/gogo/protobuf/proto"
-
 	"github.com/sirupsen/logrus"
 
 	"github.com/apache/pulsar-client-go/pulsar/internal"
@@ -35,14 +38,36 @@ import (
 const (
 	defaultConnectionTimeout = 5 * time.Second
 	defaultOperationTimeout  = 30 * time.Second
+
+	// the number of topic schema versions kept by the client
+	defaultSchemaCacheSize = 1000
 )
 
 type client struct {
-	cnxPool       internal.ConnectionPool
-	rpcClient     internal.RPCClient
-	handlers      internal.ClientHandlers
-	lookupService internal.LookupService
-	metrics       *internal.Metrics
+	cnxPool        internal.ConnectionPool
+	rpcClient      internal.RPCClient
+	handlers       internal.ClientHandlers
+	producers      internal.IDHandlers // the partition producers by id
+	consumers      internal.IDHandlers // the partition consumers by id
+	lookupService  internal.CachedLookupService
+	schemaRegistry internal.SchemaRegistry
+	httpClient     internal.HTTPClient
+	metrics        *internal.Metrics
+
+	operationTimeout time.Duration
+	creationTimeout  time.Duration
+	backoffPolicy    func() BackoffPolicy
+	tcClient         internal.TransactionCoordinatorClient
+
+	connectionListener ConnectionListener
+
+	serviceNameResolver internal.ServiceNameResolver
+	serviceURLProvider  ServiceURLProvider
+	switchLock          sync.Mutex
+	auth                auth.Provider
+	// authProviders are the initialized providers of the clusters the client connected to, they are kept
+	// until the client is closed since it may switch back to their cluster
+	authProviders map[Authentication]auth.Provider
 
 	log log.Logger
 }
@@ -54,43 +79,72 @@ func newClient(options ClientOptions) (Client, error) {
 	} else {
 		logger = log.NewLoggerWithLogrus(logrus.StandardLogger())
 	}
+	logger = log.NewLoggerWithLevel(logger, options.LogLevel)
+
+	if options.ServiceURLProvider != nil {
+		options = options.ServiceURLProvider.ServiceCluster().clientOptions(options)
+	}
 
 	if options.URL == "" {
 		return nil, newError(InvalidConfiguration, "URL is required for client")
 	}
 
-	url, err := url.Parse(options.URL)
+	serviceURL, err := url.Parse(options.URL)
 	if err != nil {
 		logger.WithError(err).Error("Failed to parse service URL")
 		return nil, newError(InvalidConfiguration, "Invalid service URL")
 	}
 
+	dialer := options.Dialer
+	if serviceURL.Scheme == "pulsar+unix" {
+		if serviceURL.Path == "" {
+			return nil, newError(InvalidConfiguration, "pulsar+unix service URL requires the path of the socket")
+		}
+		if dialer != nil || options.ServiceURLProvider != nil {
+			return nil, newError(InvalidConfiguration,
+				"pulsar+unix service URL can't be used with a Dialer or a ServiceURLProvider")
+		}
+		// the lookups are made to the local broker, whose connections all go through the socket
+		dialer = unixSocketDialer(serviceURL.Path)
+		serviceURL = &url.URL{Scheme: "pulsar", Host: "localhost"}
+	}
+
 	var tlsConfig *internal.TLSOptions
-	switch url.Scheme {
-	case "pulsar":
+	switch serviceURL.Scheme {
+	case "pulsar", "http":
 		tlsConfig = nil
-	case "pulsar+ssl":
-		tlsConfig = &internal.TLSOptions{
-			AllowInsecureConnection: options.TLSAllowInsecureConnection,
-			TrustCertsFilePath:      options.TLSTrustCertsFilePath,
-			ValidateHostname:        options.TLSValidateHostname,
-		}
+	case "pulsar+ssl", "https":
+		tlsConfig = newTLSOptions(options)
 	default:
-		return nil, newError(InvalidConfiguration, fmt.Sprintf("Invalid URL scheme '%s'", url.Scheme))
+		return nil, newError(InvalidConfiguration, fmt.Sprintf("Invalid URL scheme '%s'", serviceURL.Scheme))
 	}
 
-	var authProvider auth.Provider
-	var ok bool
-
-	if options.Authentication == nil {
-		authProvider = auth.NewAuthDisabled()
-	} else {
-		authProvider, ok = options.Authentication.(auth.Provider)
-		if !ok {
-			return nil, newError(AuthenticationError, "invalid auth provider interface")
+	if options.ProxyURL != "" {
+		if options.ProxyProtocol != ProxyProtocolSNI {
+			return nil, newError(InvalidConfiguration, "ProxyProtocol is required when ProxyURL is set")
+		}
+		if tlsConfig == nil {
+			return nil, newError(InvalidConfiguration, "SNI proxy requires a pulsar+ssl service URL")
 		}
+		proxyURL, err := url.Parse(options.ProxyURL)
+		if err != nil || proxyURL.Host == "" {
+			logger.WithError(err).Error("Failed to parse proxy URL")
+			return nil, newError(InvalidConfiguration, "Invalid proxy URL")
+		}
+		tlsConfig.SNIProxyURL = proxyURL
+	}
+
+	if options.ServiceURLProvider != nil && (options.ProxyURL != "" || !strings.HasPrefix(serviceURL.Scheme, "pulsar")) {
+		return nil, newError(InvalidConfiguration,
+			"ServiceURLProvider requires a binary protocol service URL without proxy")
 	}
-	err = authProvider.Init()
+
+	serviceNameResolver, err := internal.NewPulsarServiceNameResolver(serviceURL)
+	if err != nil {
+		return nil, newError(InvalidConfiguration, err.Error())
+	}
+
+	authProvider, err := newAuthProvider(options.Authentication)
 	if err != nil {
 		return nil, err
 	}
@@ -105,6 +159,11 @@ func newClient(options ClientOptions) (Client, error) {
 		operationTimeout = defaultOperationTimeout
 	}
 
+	keepAliveInterval := options.KeepAliveInterval
+	if keepAliveInterval.Nanoseconds() == 0 {
+		keepAliveInterval = internal.DefaultKeepAliveInterval
+	}
+
 	maxConnectionsPerHost := options.MaxConnectionsPerBroker
 	if maxConnectionsPerHost <= 0 {
 		maxConnectionsPerHost = 1
@@ -117,33 +176,207 @@ func newClient(options ClientOptions) (Client, error) {
 		metrics = internal.NewMetricsProvider(map[string]string{})
 	}
 
+	writeBatching := internal.WriteBatching{
+		FlushInterval:   options.ConnectionWriteFlushInterval,
+		MaxPendingBytes: options.ConnectionWriteMaxPendingBytes,
+	}
+
+	socketOptions := internal.SocketOptions{
+		DisableNoDelay:    options.DisableTCPNoDelay,
+		SendBufferSize:    options.TCPSendBufferSize,
+		ReceiveBufferSize: options.TCPReceiveBufferSize,
+		KeepAlive:         options.TCPKeepAlive,
+		Dial:              dialer,
+	}
+
+	var connectionEvents internal.ConnectionEvents
+	if options.ConnectionListener != nil {
+		connectionEvents = connectionEventsListener{options.ConnectionListener}
+	}
+
 	c := &client{
-		cnxPool: internal.NewConnectionPool(tlsConfig, authProvider, connectionTimeout, maxConnectionsPerHost, logger,
-			metrics),
-		log:     logger,
-		metrics: metrics,
+		cnxPool: internal.NewConnectionPool(tlsConfig, authProvider, connectionTimeout, keepAliveInterval,
+			writeBatching, socketOptions, connectionEvents, maxConnectionsPerHost, logger, metrics),
+		log:              logger,
+		metrics:          metrics,
+		operationTimeout: operationTimeout,
+		creationTimeout:  options.CreationTimeout,
+		backoffPolicy:    options.BackoffPolicy,
+
+		connectionListener: options.ConnectionListener,
+	}
+	if c.backoffPolicy == nil {
+		c.backoffPolicy = newDefaultBackoff
 	}
-	serviceNameResolver := internal.NewPulsarServiceNameResolver(url)
 
-	c.rpcClient = internal.NewRPCClient(url, serviceNameResolver, c.cnxPool, operationTimeout, logger, metrics)
-	c.lookupService = internal.NewLookupService(c.rpcClient, url, serviceNameResolver, tlsConfig != nil, logger, metrics)
+	c.rpcClient = internal.NewRPCClient(serviceURL, serviceNameResolver, c.cnxPool, operationTimeout, logger, metrics)
+	var lookupService internal.LookupService
+	switch serviceURL.Scheme {
+	case "pulsar", "pulsar+ssl":
+		lookupService = internal.NewLookupService(c.rpcClient, serviceURL, serviceNameResolver, tlsConfig != nil,
+			options.ListenerName, logger, metrics)
+	case "http", "https":
+		httpClient, err := internal.NewHTTPClient(serviceURL, serviceNameResolver, tlsConfig, authProvider,
+			operationTimeout, logger)
+		if err != nil {
+			return nil, newError(InvalidConfiguration, fmt.Sprintf("Failed to init http client with err: '%s'",
+				err.Error()))
+		}
+		c.httpClient = httpClient
+		lookupService = internal.NewHTTPLookupService(httpClient, serviceURL, tlsConfig != nil, logger, metrics)
+	}
+	if options.ConnectionListener != nil {
+		// only the lookups sent to the cluster are reported, not the cached results
+		lookupService = &listenedLookupService{lookupService, options.ConnectionListener}
+	}
+	c.lookupService = internal.NewCachedLookupService(lookupService, options.LookupCacheTTL)
+	c.schemaRegistry = internal.NewSchemaRegistry(c.lookupService, c.rpcClient, defaultSchemaCacheSize)
 	c.handlers = internal.NewClientHandlers()
+	c.producers = internal.NewIDHandlers()
+	c.consumers = internal.NewIDHandlers()
+	c.serviceNameResolver = serviceNameResolver
+	c.auth = authProvider
+	c.authProviders = make(map[Authentication]auth.Provider)
+	if options.Authentication != nil {
+		c.authProviders[options.Authentication] = authProvider
+	}
+
+	if options.EnableTransaction {
+		c.tcClient = internal.NewTransactionCoordinatorClient(c.lookupService, c.rpcClient, logger)
+		if err := c.tcClient.Start(); err != nil {
+			c.Close()
+			return nil, toClientError(err, "start transaction coordinator client")
+		}
+	}
+
+	if options.ServiceURLProvider != nil {
+		c.serviceURLProvider = options.ServiceURLProvider
+		if err := c.serviceURLProvider.Initialize(c.switchCluster); err != nil {
+			c.Close()
+			return nil, newError(InvalidConfiguration, fmt.Sprintf("Failed to init service url provider: '%s'",
+				err.Error()))
+		}
+	}
 
 	return c, nil
 }
 
+// unixSocketDialer dials the unix socket whatever the address of the broker
+func unixSocketDialer(path string) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, _, _ string) (net.Conn, error) {
+		var d net.Dialer
+		return d.DialContext(ctx, "unix", path)
+	}
+}
+
+// newTLSOptions returns the TLS options of the connections to a TLS service URL
+func newTLSOptions(options ClientOptions) *internal.TLSOptions {
+	return &internal.TLSOptions{
+		AllowInsecureConnection: options.TLSAllowInsecureConnection,
+		TrustCertsFilePath:      options.TLSTrustCertsFilePath,
+		ValidateHostname:        options.TLSValidateHostname,
+	}
+}
+
+// clientOptions returns the client options connecting to the cluster, the other options are the given ones
+func (cluster ClusterConfig) clientOptions(options ClientOptions) ClientOptions {
+	options.URL = cluster.URL
+	options.Authentication = cluster.Authentication
+	options.TLSTrustCertsFilePath = cluster.TLSTrustCertsFilePath
+	options.TLSAllowInsecureConnection = cluster.TLSAllowInsecureConnection
+	options.TLSValidateHostname = cluster.TLSValidateHostname
+	return options
+}
+
+// newAuthProvider returns the initialized provider of the given authentication
+func newAuthProvider(authentication Authentication) (auth.Provider, error) {
+	if authentication == nil {
+		return auth.NewAuthDisabled(), nil
+	}
+
+	authProvider, ok := authentication.(auth.Provider)
+	if !ok {
+		return nil, newError(AuthenticationError, "invalid auth provider interface")
+	}
+	if err := authProvider.Init(); err != nil {
+		return nil, err
+	}
+	return authProvider, nil
+}
+
+// switchCluster points the client to the given cluster, the current connections are closed and the
+// producers and consumers reconnect to the new cluster
+func (c *client) switchCluster(cluster ClusterConfig) error {
+	c.switchLock.Lock()
+	defer c.switchLock.Unlock()
+
+	serviceURL, err := url.Parse(cluster.URL)
+	if err != nil {
+		return newError(InvalidConfiguration, "Invalid service URL")
+	}
+	if serviceURL.Scheme != c.serviceNameResolver.GetServiceURL().Scheme {
+		return newError(InvalidConfiguration, fmt.Sprintf("Cannot switch to a service URL with scheme '%s'",
+			serviceURL.Scheme))
+	}
+
+	var tlsConfig *internal.TLSOptions
+	if serviceURL.Scheme == "pulsar+ssl" {
+		tlsConfig = newTLSOptions(cluster.clientOptions(ClientOptions{}))
+	}
+
+	authProvider, err := c.clusterAuthProvider(cluster.Authentication)
+	if err != nil {
+		return err
+	}
+
+	if err := c.serviceNameResolver.UpdateServiceURL(serviceURL); err != nil {
+		return newError(InvalidConfiguration, fmt.Sprintf("Invalid service URL: '%s'", err.Error()))
+	}
+	c.log.Infof("Switching to service URL %s", serviceURL)
+	c.lookupService.InvalidateAll()
+	c.cnxPool.Reset(tlsConfig, authProvider)
+	c.auth = authProvider
+	return nil
+}
+
+// clusterAuthProvider returns the provider of the cluster authentication, it's initialized the first time
+// the client connects to the cluster
+func (c *client) clusterAuthProvider(authentication Authentication) (auth.Provider, error) {
+	if authentication == nil {
+		return auth.NewAuthDisabled(), nil
+	}
+	if authProvider, ok := c.authProviders[authentication]; ok {
+		return authProvider, nil
+	}
+	authProvider, err := newAuthProvider(authentication)
+	if err != nil {
+		return nil, err
+	}
+	c.authProviders[authentication] = authProvider
+	return authProvider, nil
+}
+
 func (c *client) CreateProducer(options ProducerOptions) (Producer, error) {
-	producer, err := newProducer(c, &options)
-	if err == nil {
-		c.handlers.Add(producer)
+	return c.CreateProducerWithCtx(context.Background(), options)
+}
+
+func (c *client) CreateProducerWithCtx(ctx context.Context, options ProducerOptions) (Producer, error) {
+	producer, err := newProducer(ctx, c, &options)
+	if err != nil {
+		return nil, toClientError(err, "create producer")
 	}
-	return producer, err
+	c.handlers.Add(producer)
+	return producer, nil
 }
 
 func (c *client) Subscribe(options ConsumerOptions) (Consumer, error) {
-	consumer, err := newConsumer(c, options)
+	return c.SubscribeWithCtx(context.Background(), options)
+}
+
+func (c *client) SubscribeWithCtx(ctx context.Context, options ConsumerOptions) (Consumer, error) {
+	consumer, err := newConsumer(ctx, c, options)
 	if err != nil {
-		return nil, err
+		return nil, toClientError(err, "subscribe")
 	}
 	c.handlers.Add(consumer)
 	return consumer, nil
@@ -152,59 +385,329 @@ func (c *client) Subscribe(options ConsumerOptions) (Consumer, error) {
 func (c *client) CreateReader(options ReaderOptions) (Reader, error) {
 	reader, err := newReader(c, options)
 	if err != nil {
-		return nil, err
+		return nil, toClientError(err, "create reader")
 	}
 	c.handlers.Add(reader)
 	return reader, nil
 }
 
+func (c *client) CreateTableView(options TableViewOptions) (TableView, error) {
+	tableView, err := newTableView(c, options)
+	if err != nil {
+		return nil, toClientError(err, "create table view")
+	}
+	c.handlers.Add(tableView)
+	return tableView, nil
+}
+
 func (c *client) TopicPartitions(topic string) ([]string, error) {
-	topicName, err := internal.ParseTopicName(topic)
+	metadata, err := c.GetPartitionedTopicMetadata(topic)
 	if err != nil {
 		return nil, err
 	}
+	// the partitions keep the topic name as given, unlike the fully qualified PartitionNames
+	return metadata.partitionNames(topic), nil
+}
 
-	r, err := c.lookupService.GetPartitionedTopicMetadata(topic)
+func (c *client) GetPartitionedTopicMetadata(topic string) (*PartitionedTopicMetadata, error) {
+	topicName, err := internal.ParseTopicName(topic)
 	if err != nil {
 		return nil, err
 	}
+
+	var r *pb.CommandPartitionedTopicMetadataResponse
+	err = c.retryOnRetriableErrors(context.Background(), func() error {
+		r, err = c.lookupService.GetPartitionedTopicMetadata(topic)
+		if err == nil && r != nil && r.Error != nil {
+			err = &internal.LookupError{ServerError: r.GetError(), Message: r.GetMessage()}
+		}
+		return err
+	})
+	if err != nil {
+		return nil, toClientError(err, "get partitioned topic metadata")
+	}
+
+	metadata := &PartitionedTopicMetadata{
+		Topic: topicName.Name,
+	}
 	if r != nil {
-		if r.Error != nil {
-			return nil, newError(LookupError, r.GetError().String())
+		metadata.Partitions = int(r.GetPartitions())
+	}
+	return metadata, nil
+}
+
+func (c *client) GetTopicsOfNamespace(namespace string, mode TopicsMode) ([]string, error) {
+	if err := validateNamespace(namespace); err != nil {
+		return nil, err
+	}
+
+	var pbMode pb.CommandGetTopicsOfNamespace_Mode
+	switch mode {
+	case PersistentTopics:
+		pbMode = pb.CommandGetTopicsOfNamespace_PERSISTENT
+	case NonPersistentTopics:
+		pbMode = pb.CommandGetTopicsOfNamespace_NON_PERSISTENT
+	case AllTopics:
+		pbMode = pb.CommandGetTopicsOfNamespace_ALL
+	default:
+		return nil, newError(InvalidConfiguration, fmt.Sprintf("unknown topics mode %d", mode))
+	}
+
+	var topics []string
+	err := c.retryOnRetriableErrors(context.Background(), func() (err error) {
+		topics, err = c.lookupService.GetTopicsOfNamespace(namespace, pbMode)
+		return err
+	})
+	if err != nil {
+		return nil, toClientError(err, "get topics of namespace")
+	}
+	return topics, nil
+}
+
+// validateNamespace checks the namespace is named "tenant/namespace", or "tenant/cluster/namespace" for the
+// legacy namespaces
+func validateNamespace(namespace string) error {
+	parts := strings.Split(namespace, "/")
+	if len(parts) < 2 || len(parts) > 3 {
+		return newError(InvalidConfiguration, fmt.Sprintf("invalid namespace %q", namespace))
+	}
+	for _, part := range parts {
+		if part == "" {
+			return newError(InvalidConfiguration, fmt.Sprintf("invalid namespace %q", namespace))
 		}
+	}
+	return nil
+}
 
-		if r.GetPartitions() > 0 {
-			partitions := make([]string, r.GetPartitions())
-			for i := 0; i < int(r.GetPartitions()); i++ {
-				partitions[i] = fmt.Sprintf("%s-partition-%d", topic, i)
-			}
-			return partitions, nil
+// checkDeduplication returns ErrDeduplicationNotEnabled unless the deduplication applied to the topic, by its
+// own policy, its namespace or the broker configuration, is enabled. It's queried through the admin API, the
+// binary protocol doesn't expose it.
+func (c *client) checkDeduplication(tn *internal.TopicName) error {
+	if tn.Domain != "persistent" {
+		return ErrDeduplicationNotEnabled
+	}
+	if c.httpClient == nil {
+		return newError(InvalidConfiguration, "the message deduplication can't be checked for a producer name "+
+			"that never published on the topic with a pulsar:// or pulsar+ssl:// service URL, it requires an "+
+			"http:// or https:// service URL")
+	}
+
+	var enabled *bool
+	endpoint := fmt.Sprintf("/admin/v2/persistent/%s/%s/deduplicationEnabled?applied=true",
+		tn.Namespace, url.PathEscape(tn.LocalName))
+	if err := c.httpClient.Get(endpoint, &enabled); err != nil {
+		return wrapError(LookupError, "failed to get the message deduplication applied to the topic", err)
+	}
+	if enabled == nil {
+		return newError(LookupError, "the broker did not return the message deduplication applied to the topic")
+	}
+	if !*enabled {
+		return ErrDeduplicationNotEnabled
+	}
+	return nil
+}
+
+func (c *client) Ping(ctx context.Context) (time.Duration, error) {
+	if c.httpClient != nil {
+		return 0, newError(InvalidConfiguration, "ping requires a pulsar:// or pulsar+ssl:// service URL")
+	}
+
+	host, err := c.serviceNameResolver.ResolveHost()
+	if err != nil {
+		return 0, wrapError(ConnectError, "failed to resolve the service host", err)
+	}
+	cnx, err := c.cnxPool.GetConnection(host, host)
+	if err != nil {
+		return 0, wrapError(ConnectError, "failed to connect to the broker", err)
+	}
+
+	start := time.Now()
+	if err := cnx.Ping(ctx); err != nil {
+		if err == ctx.Err() {
+			return 0, err
 		}
+		return 0, wrapError(ConnectError, "failed to ping the broker", err)
 	}
+	return time.Since(start), nil
+}
 
-	// Non-partitioned topic
-	return []string{topicName.Name}, nil
+func (c *client) NewTransaction(timeout time.Duration) (Transaction, error) {
+	if c.tcClient == nil {
+		return nil, newError(InvalidConfiguration, "transactions are not enabled on the client")
+	}
+	if timeout <= 0 {
+		timeout = defaultTransactionTimeout
+	}
+
+	id, err := c.tcClient.NewTransaction(context.Background(), timeout)
+	if err != nil {
+		return nil, toClientError(err, "new transaction")
+	}
+	return newTransaction(id, c.tcClient, c.log), nil
 }
 
 func (c *client) Close() {
-	c.handlers.Close()
+	// the producers are flushed within the operation timeout, a flush never completes while the brokers
+	// are unreachable
+	flushCtx, cancel := context.WithTimeout(context.Background(), c.operationTimeout)
+	defer cancel()
+	_ = c.closeWithCtx(flushCtx, context.Background())
+}
+
+func (c *client) CloseWithCtx(ctx context.Context) error {
+	return c.closeWithCtx(ctx, ctx)
+}
+
+// closeWithCtx flushes the producers within flushCtx, then closes everything within ctx
+func (c *client) closeWithCtx(flushCtx, ctx context.Context) error {
+	if c.serviceURLProvider != nil {
+		c.serviceURLProvider.Close()
+	}
+
+	handlers := c.handlers.Values()
+	var errMsgs []string
+	// the pending messages are flushed before anything is closed, as they may be acked by consumers of the client
+	for _, handler := range handlers {
+		if producer, ok := handler.(Producer); ok {
+			if err := producer.FlushWithCtx(flushCtx); err != nil {
+				errMsgs = append(errMsgs, fmt.Sprintf("flush producer of topic %s: %s", producer.Topic(), err))
+			}
+		}
+	}
+	for _, handler := range handlers {
+		if err := closeHandler(ctx, handler); err != nil {
+			errMsgs = append(errMsgs, err.Error())
+		}
+	}
+	// the partitions left, eg. the ones created after their producer or consumer gave up waiting for them
+	for _, handler := range append(c.producers.Values(), c.consumers.Values()...) {
+		if err := runWithCtx(ctx, handler.Close); err != nil {
+			errMsgs = append(errMsgs, err.Error())
+		}
+	}
+
 	c.cnxPool.Close()
+	if c.httpClient != nil {
+		c.httpClient.Close()
+	}
+	c.switchLock.Lock()
+	for _, authProvider := range c.authProviders {
+		authProvider.Close()
+	}
+	c.authProviders = nil
+	c.switchLock.Unlock()
+
+	if len(errMsgs) > 0 {
+		return fmt.Errorf("failed to close the client: %s", strings.Join(errMsgs, "; "))
+	}
+	return nil
 }
 
-func (c *client) namespaceTopics(namespace string) ([]string, error) {
-	id := c.rpcClient.NewRequestID()
-	req := &pb.CommandGetTopicsOfNamespace{
-		RequestId: proto.Uint64(id),
-		Namespace: proto.String(namespace),
-		Mode:      pb.CommandGetTopicsOfNamespace_PERSISTENT.Enum(),
+// closeHandler closes the producer, consumer, reader or table view within the context
+func closeHandler(ctx context.Context, handler internal.Closable) error {
+	switch h := handler.(type) {
+	case Producer:
+		if err := h.CloseWithCtx(ctx); err != nil {
+			return fmt.Errorf("close producer of topic %s: %s", h.Topic(), err)
+		}
+	case Consumer:
+		if err := h.CloseWithCtx(ctx); err != nil {
+			return fmt.Errorf("close consumer of subscription %s: %s", h.Subscription(), err)
+		}
+	default:
+		return runWithCtx(ctx, handler.Close)
 	}
-	res, err := c.rpcClient.RequestToAnyBroker(id, pb.BaseCommand_GET_TOPICS_OF_NAMESPACE, req)
+	return nil
+}
+
+// getSchema returns the schema of the topic at the given version, or its latest schema when the version is empty
+func (c *client) getSchema(topic string, schemaVersion []byte) (*SchemaInfo, error) {
+	schema, err := c.schemaRegistry.GetSchema(topic, schemaVersion)
 	if err != nil {
-		return nil, err
+		return nil, toClientError(err, "get schema")
+	}
+	return &SchemaInfo{
+		Name:       schema.GetName(),
+		Schema:     string(schema.GetSchemaData()),
+		Type:       SchemaType(schema.GetType()),
+		Properties: internal.ConvertToStringMap(schema.GetProperties()),
+	}, nil
+}
+
+func (c *client) namespaceTopics(namespace string) ([]string, error) {
+	return c.lookupService.GetTopicsOfNamespace(namespace, pb.CommandGetTopicsOfNamespace_PERSISTENT)
+}
+
+// retryOnRetriableErrors runs the operation until it succeeds, fails with an error which isn't retriable,
+// the operation timeout is reached or ctx is done
+func (c *client) retryOnRetriableErrors(ctx context.Context, operation func() error) error {
+	backoff := c.backoffPolicy()
+	startTime := time.Now()
+	for {
+		err := operation()
+		if !isRetriableError(err) {
+			return err
+		}
+
+		d := backoff.Next()
+		if time.Since(startTime)+d > c.operationTimeout {
+			return err
+		}
+		c.log.WithError(err).Warnf("Retrying the operation in %v", d)
+		select {
+		case <-time.After(d):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
 	}
-	if res.Response.Error != nil {
-		return []string{}, newError(LookupError, res.Response.GetError().String())
+}
+
+// closeLatePartitions closes the partitions still being created once they are, next waits for the creation of the
+// next partition and returns its Close function, or nil when the creation failed
+func closeLatePartitions(pending int, next func() func()) {
+	for ; pending > 0; pending-- {
+		if closePartition := next(); closePartition != nil {
+			closePartition()
+		}
 	}
+}
 
-	return res.Response.GetTopicsOfNamespaceResponse.GetTopics(), nil
+// creationTimeoutCh returns the channel signaling that the creation of the partitions timed out, which never
+// fires when no creation timeout is set, and the function releasing its timer
+func (c *client) creationTimeoutCh() (<-chan time.Time, func()) {
+	if c.creationTimeout <= 0 {
+		return nil, func() {}
+	}
+	timer := time.NewTimer(c.creationTimeout)
+	return timer.C, func() { timer.Stop() }
+}
+
+// toClientError converts the errors of the internal requests into typed errors, the errors may be wrapped,
+// eg. by the consumers of several topics
+func toClientError(err error, operation string) error {
+	var (
+		clientErr *Error
+		lookupErr *internal.LookupError
+		serverErr *internal.ServerError
+		netErr    net.Error
+	)
+	switch {
+	case err == nil, errors.As(err, &clientErr):
+		return err
+	case errors.As(err, &lookupErr):
+		return wrapError(LookupError, fmt.Sprintf("%s lookup failed", operation), err)
+	case errors.As(err, &serverErr):
+		return wrapError(serverErrorResult(serverErr.Code), fmt.Sprintf("%s failed", operation), err)
+	case errors.As(err, &netErr):
+		return wrapError(ConnectError, fmt.Sprintf("%s failed", operation), err)
+	case errors.Is(err, internal.ErrRequestTimeOut):
+		return wrapError(TimeoutError, fmt.Sprintf("%s operation timed out", operation), err)
+	case errors.Is(err, internal.ErrMaxLookupRedirect):
+		return wrapError(LookupError, fmt.Sprintf("%s lookup failed", operation), err)
+	case errors.Is(err, internal.ErrConnectionFailed), errors.Is(err, internal.ErrConnectionClosed):
+		return wrapError(ConnectError, fmt.Sprintf("%s failed", operation), err)
+	case errors.Is(err, internal.ErrTransactionCoordinatorNotEnabled):
+		return wrapError(TransactionCoordinatorNotFound, fmt.Sprintf("%s failed", operation), err)
+	}
+	return err
 }
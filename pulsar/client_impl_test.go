@@ -20,16 +20,23 @@ package pulsar
 import (
 	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
 	"testing"
 	"time"
 
+	"github.com/apache/pulsar-client-go/pulsar/internal"
 	"github.com/apache/pulsar-client-go/pulsar/internal/auth"
+	"github.com/apache/pulsar-client-go/pulsar/log"
+	"github.com/apache/pulsar-client-go/pulsar/pulsartest"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestClient(t *testing.T) {
@@ -39,6 +46,102 @@ func TestClient(t *testing.T) {
 	assert.Equal(t, InvalidConfiguration, err.(*Error).Result())
 }
 
+func TestClientInvalidServiceURL(t *testing.T) {
+	client, err := NewClient(ClientOptions{URL: "pulsar:///"})
+	assert.Nil(t, client)
+	assert.Equal(t, InvalidConfiguration, err.(*Error).Result())
+}
+
+func TestClientPing(t *testing.T) {
+	client, err := NewClient(ClientOptions{
+		URL: serviceURL,
+	})
+	assert.NoError(t, err)
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	latency, err := client.Ping(ctx)
+	assert.NoError(t, err)
+	assert.True(t, latency > 0)
+
+	httpClient, err := NewClient(ClientOptions{
+		URL: webServiceURL,
+	})
+	assert.NoError(t, err)
+	defer httpClient.Close()
+
+	_, err = httpClient.Ping(ctx)
+	assert.Error(t, err)
+}
+
+func TestClientCloseWithCtx(t *testing.T) {
+	client, err := NewClient(ClientOptions{
+		URL: serviceURL,
+	})
+	require.NoError(t, err)
+
+	topic := newTopicName()
+	consumer, err := client.Subscribe(ConsumerOptions{
+		Topic:            topic,
+		SubscriptionName: "my-sub",
+	})
+	require.NoError(t, err)
+
+	producer, err := client.CreateProducer(ProducerOptions{
+		Topic:                   topic,
+		BatchingMaxPublishDelay: time.Minute,
+	})
+	require.NoError(t, err)
+
+	// the message stays in the batch until the client flushes it while closing
+	var sendErr error
+	sent := make(chan struct{})
+	producer.SendAsync(context.Background(), &ProducerMessage{Payload: []byte("hello")},
+		func(id MessageID, message *ProducerMessage, err error) {
+			sendErr = err
+			close(sent)
+		})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	assert.NoError(t, client.CloseWithCtx(ctx))
+
+	<-sent
+	assert.NoError(t, sendErr)
+
+	_, err = consumer.Receive(context.Background())
+	assert.Error(t, err)
+}
+
+func TestClientCloseUnreachableBroker(t *testing.T) {
+	broker, err := pulsartest.NewBroker()
+	require.NoError(t, err)
+	client, err := NewClient(ClientOptions{URL: broker.URL(), OperationTimeout: time.Second})
+	require.NoError(t, err)
+
+	producer, err := client.CreateProducer(ProducerOptions{
+		Topic:                   newTopicName(),
+		BatchingMaxPublishDelay: time.Minute,
+		SendTimeout:             time.Second,
+	})
+	require.NoError(t, err)
+	producer.SendAsync(context.Background(), &ProducerMessage{Payload: []byte("hello")}, nil)
+	broker.Close()
+
+	// the pending message is given up within the timeouts once the broker is gone
+	closed := make(chan struct{})
+	go func() {
+		client.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(10 * time.Second):
+		t.Fatal("the client wasn't closed")
+	}
+}
+
 func TestTLSConnectionCAError(t *testing.T) {
 	client, err := NewClient(ClientOptions{
 		URL:              serviceURLTLS,
@@ -129,6 +232,25 @@ func TestTLSConnectionHostNameVerificationError(t *testing.T) {
 	client.Close()
 }
 
+func TestTLSConnectionWithoutHostNameVerification(t *testing.T) {
+	client, err := NewClient(ClientOptions{
+		URL:                   "pulsar+ssl://127.0.0.1:6651",
+		OperationTimeout:      5 * time.Second,
+		TLSTrustCertsFilePath: caCertsPath,
+	})
+	assert.NoError(t, err)
+
+	// The host name doesn't match the broker certificate, but the chain is trusted
+	producer, err := client.CreateProducer(ProducerOptions{
+		Topic: newTopicName(),
+	})
+
+	assert.NoError(t, err)
+	assert.NotNil(t, producer)
+
+	client.Close()
+}
+
 func TestTLSAuthError(t *testing.T) {
 	client, err := NewClient(ClientOptions{
 		URL:                   serviceURLTLS,
@@ -303,6 +425,54 @@ func mockKeyFile(server string) (string, error) {
 	return kf.Name(), nil
 }
 
+type closingSaslClient struct {
+	closed chan struct{}
+}
+
+func (c *closingSaslClient) InitialResponse() ([]byte, error) {
+	return []byte("initial"), nil
+}
+
+func (c *closingSaslClient) EvaluateChallenge(challenge []byte) ([]byte, error) {
+	return nil, errors.New("unexpected challenge")
+}
+
+func (c *closingSaslClient) Close() error {
+	close(c.closed)
+	return nil
+}
+
+func TestSaslAuthSessionClosedWithConnection(t *testing.T) {
+	broker, err := pulsartest.NewBroker()
+	require.NoError(t, err)
+	defer broker.Close()
+
+	saslClient := &closingSaslClient{closed: make(chan struct{})}
+	client, err := NewClient(ClientOptions{
+		URL: broker.URL(),
+		Authentication: NewAuthenticationSasl(map[string]string{"principal": "client@EXAMPLE.COM"},
+			func(keytabPath, principal, serverPrincipal string) (SaslClient, error) {
+				return saslClient, nil
+			}),
+	})
+	require.NoError(t, err)
+
+	_, err = client.Ping(context.Background())
+	require.NoError(t, err)
+	select {
+	case <-saslClient.closed:
+		t.Fatal("the SASL client is closed while the connection is open")
+	default:
+	}
+
+	client.Close()
+	select {
+	case <-saslClient.closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the SASL client isn't closed with the connection")
+	}
+}
+
 func TestOAuth2Auth(t *testing.T) {
 	server := mockOAuthServer()
 	defer server.Close()
@@ -368,6 +538,42 @@ func TestTopicPartitions(t *testing.T) {
 	assert.Equal(t, partitions[0], topic)
 }
 
+func TestGetPartitionedTopicMetadata(t *testing.T) {
+	client, err := NewClient(ClientOptions{
+		URL: lookupURL,
+	})
+	assert.Nil(t, err)
+	defer client.Close()
+
+	err = httpPut("admin/v2/persistent/public/default/TestGetPartitionedTopicMetadata/partitions", 3)
+	assert.Nil(t, err)
+
+	metadata, err := client.GetPartitionedTopicMetadata("TestGetPartitionedTopicMetadata")
+	assert.Nil(t, err)
+	assert.Equal(t, "persistent://public/default/TestGetPartitionedTopicMetadata", metadata.Topic)
+	assert.Equal(t, 3, metadata.Partitions)
+	assert.True(t, metadata.IsPartitioned())
+
+	metadata, err = client.GetPartitionedTopicMetadata(newTopicName())
+	assert.Nil(t, err)
+	assert.Equal(t, 0, metadata.Partitions)
+	assert.False(t, metadata.IsPartitioned())
+}
+
+func TestPartitionedTopicMetadataPartitionNames(t *testing.T) {
+	metadata := &PartitionedTopicMetadata{Topic: "persistent://public/default/my-topic"}
+	assert.Equal(t, []string{"persistent://public/default/my-topic"}, metadata.PartitionNames())
+
+	metadata.Partitions = 2
+	assert.Equal(t, []string{
+		"persistent://public/default/my-topic-partition-0",
+		"persistent://public/default/my-topic-partition-1",
+	}, metadata.PartitionNames())
+
+	// the partitions are named after the topic name as given
+	assert.Equal(t, []string{"my-topic-partition-0", "my-topic-partition-1"}, metadata.partitionNames("my-topic"))
+}
+
 func TestNamespaceTopicsNamespaceDoesNotExit(t *testing.T) {
 	c, err := NewClient(ClientOptions{
 		URL: serviceURL,
@@ -447,6 +653,78 @@ func TestNamespaceTopics(t *testing.T) {
 		t.Fatal(err)
 	}
 	assert.Equal(t, 2, len(topics))
+
+	topics, err = c.GetTopicsOfNamespace(namespace, NonPersistentTopics)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{topicName}, topics)
+
+	topics, err = c.GetTopicsOfNamespace(namespace, AllTopics)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(topics))
+}
+
+func TestGetTopicsOfNamespaceInvalid(t *testing.T) {
+	c := &client{}
+	for _, namespace := range []string{"", "public", "public/", "/default", "a/b/c/d"} {
+		_, err := c.GetTopicsOfNamespace(namespace, PersistentTopics)
+		var e *Error
+		assert.True(t, errors.As(err, &e), namespace)
+		assert.Equal(t, InvalidConfiguration, e.Result())
+	}
+
+	_, err := c.GetTopicsOfNamespace("public/default", TopicsMode(3))
+	assert.NotNil(t, err)
+}
+
+func TestCheckDeduplication(t *testing.T) {
+	response := "true"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/admin/v2/persistent/public/default/my-topic/deduplicationEnabled" ||
+			r.URL.Query().Get("applied") != "true" {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, response)
+	}))
+	defer server.Close()
+
+	serviceURL, err := url.Parse(server.URL)
+	assert.Nil(t, err)
+	resolver, err := internal.NewPulsarServiceNameResolver(serviceURL)
+	assert.Nil(t, err)
+	httpClient, err := internal.NewHTTPClient(serviceURL, resolver, nil, auth.NewAuthDisabled(), 5*time.Second,
+		log.DefaultNopLogger())
+	assert.Nil(t, err)
+	defer httpClient.Close()
+	c := &client{httpClient: httpClient}
+
+	tn, err := internal.ParseTopicName("my-topic")
+	assert.Nil(t, err)
+	assert.Nil(t, c.checkDeduplication(tn))
+
+	response = "false"
+	assert.Equal(t, ErrDeduplicationNotEnabled, c.checkDeduplication(tn))
+
+	// the applied deduplication falls back to the broker configuration, it is never null
+	var e *Error
+	response = "null"
+	assert.True(t, errors.As(c.checkDeduplication(tn), &e))
+	assert.Equal(t, LookupError, e.Result())
+
+	tn, err = internal.ParseTopicName("non-persistent://public/default/my-topic")
+	assert.Nil(t, err)
+	assert.Equal(t, ErrDeduplicationNotEnabled, c.checkDeduplication(tn))
+
+	tn, err = internal.ParseTopicName("persistent://my-tenant/my-ns/my-topic")
+	assert.Nil(t, err)
+	assert.True(t, errors.As(c.checkDeduplication(tn), &e))
+	assert.Equal(t, LookupError, e.Result())
+
+	// the binary protocol doesn't expose the deduplication of the topic
+	c = &client{}
+	assert.True(t, errors.As(c.checkDeduplication(tn), &e))
+	assert.Equal(t, InvalidConfiguration, e.Result())
+	assert.Contains(t, e.Error(), "requires an http:// or https:// service URL")
 }
 
 func anonymousNamespacePolicy() map[string]interface{} {
@@ -514,3 +792,286 @@ func TestRetryWithMultipleHosts(t *testing.T) {
 	assert.Nil(t, err)
 
 }
+
+func TestKeepAliveInterval(t *testing.T) {
+	client, err := NewClient(ClientOptions{
+		URL:               lookupURL,
+		KeepAliveInterval: 500 * time.Millisecond,
+	})
+	assert.Nil(t, err)
+	defer client.Close()
+
+	producer, err := client.CreateProducer(ProducerOptions{
+		Topic: newTopicName(),
+	})
+	assert.Nil(t, err)
+	defer producer.Close()
+
+	// the connection is kept alive by the PING/PONG exchanges
+	time.Sleep(2 * time.Second)
+
+	_, err = producer.Send(context.Background(), &ProducerMessage{
+		Payload: []byte("hello"),
+	})
+	assert.Nil(t, err)
+}
+
+func TestCreationTimeoutCh(t *testing.T) {
+	c := &client{}
+	timeoutCh, stop := c.creationTimeoutCh()
+	assert.Nil(t, timeoutCh)
+	stop()
+
+	c.creationTimeout = 10 * time.Millisecond
+	timeoutCh, stop = c.creationTimeoutCh()
+	defer stop()
+	select {
+	case <-timeoutCh:
+	case <-time.After(time.Second):
+		t.Error("Expected the creation to time out!")
+	}
+}
+
+func TestClientDialer(t *testing.T) {
+	dialed := make(chan string, 100)
+	client, err := NewClient(ClientOptions{
+		URL:              "pulsar://broker.example.com:6650",
+		OperationTimeout: time.Second,
+		Dialer: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			select {
+			case dialed <- network + "://" + addr:
+			default:
+			}
+			return nil, errors.New("dial refused")
+		},
+	})
+	assert.Nil(t, err)
+	defer client.Close()
+
+	_, err = client.CreateProducer(ProducerOptions{
+		Topic: newTopicName(),
+	})
+	assert.NotNil(t, err)
+	assert.Equal(t, "tcp://broker.example.com:6650", <-dialed)
+}
+
+func TestClientUnixSocket(t *testing.T) {
+	_, err := NewClient(ClientOptions{
+		URL: "pulsar+unix://",
+	})
+	assert.Equal(t, InvalidConfiguration, err.(*Error).Result())
+
+	dir, err := ioutil.TempDir("", "pulsar")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	socketPath := dir + "/broker.sock"
+	listener, err := net.Listen("unix", socketPath)
+	assert.Nil(t, err)
+	defer listener.Close()
+
+	// the connections are accepted and closed right away, before the handshake
+	accepted := make(chan struct{}, 100)
+	go func() {
+		for {
+			cnx, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			cnx.Close()
+			select {
+			case accepted <- struct{}{}:
+			default:
+			}
+		}
+	}()
+
+	client, err := NewClient(ClientOptions{
+		URL:              "pulsar+unix://" + socketPath,
+		OperationTimeout: time.Second,
+	})
+	assert.Nil(t, err)
+	defer client.Close()
+
+	_, err = client.CreateProducer(ProducerOptions{
+		Topic: newTopicName(),
+	})
+	assert.NotNil(t, err)
+	select {
+	case <-accepted:
+	case <-time.After(time.Second):
+		t.Error("Expected a connection to the unix socket!")
+	}
+}
+
+func TestProxyOptionsValidation(t *testing.T) {
+	_, err := NewClient(ClientOptions{
+		URL:      serviceURLTLS,
+		ProxyURL: "pulsar+ssl://localhost:4443",
+	})
+	assert.Equal(t, InvalidConfiguration, err.(*Error).Result())
+
+	_, err = NewClient(ClientOptions{
+		URL:           serviceURL,
+		ProxyURL:      "pulsar+ssl://localhost:4443",
+		ProxyProtocol: ProxyProtocolSNI,
+	})
+	assert.Equal(t, InvalidConfiguration, err.(*Error).Result())
+
+	_, err = NewClient(ClientOptions{
+		URL:           serviceURLTLS,
+		ProxyURL:      "localhost",
+		ProxyProtocol: ProxyProtocolSNI,
+	})
+	assert.Equal(t, InvalidConfiguration, err.(*Error).Result())
+
+	client, err := NewClient(ClientOptions{
+		URL:           serviceURLTLS,
+		ProxyURL:      "pulsar+ssl://localhost:4443",
+		ProxyProtocol: ProxyProtocolSNI,
+	})
+	assert.Nil(t, err)
+	client.Close()
+}
+
+func TestHTTPLookup(t *testing.T) {
+	client, err := NewClient(ClientOptions{
+		URL: adminURL,
+	})
+	assert.Nil(t, err)
+	defer client.Close()
+
+	topic := newTopicName()
+	consumer, err := client.Subscribe(ConsumerOptions{
+		Topic:            topic,
+		SubscriptionName: "my-sub",
+	})
+	assert.Nil(t, err)
+	defer consumer.Close()
+
+	producer, err := client.CreateProducer(ProducerOptions{
+		Topic: topic,
+	})
+	assert.Nil(t, err)
+	defer producer.Close()
+
+	_, err = producer.Send(context.Background(), &ProducerMessage{
+		Payload: []byte("hello"),
+	})
+	assert.Nil(t, err)
+
+	msg, err := consumer.Receive(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("hello"), msg.Payload())
+
+	partitions, err := client.TopicPartitions(topic)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{topic}, partitions)
+}
+
+type manualServiceURLProvider struct {
+	cluster       ClusterConfig
+	switchCluster func(ClusterConfig) error
+}
+
+func (p *manualServiceURLProvider) ServiceCluster() ClusterConfig {
+	return p.cluster
+}
+
+func (p *manualServiceURLProvider) Initialize(switchCluster func(ClusterConfig) error) error {
+	p.switchCluster = switchCluster
+	return nil
+}
+
+func (p *manualServiceURLProvider) Close() {}
+
+type countingAuthProvider struct {
+	auth.Provider
+	inits, closes int
+}
+
+func (p *countingAuthProvider) Init() error {
+	p.inits++
+	return nil
+}
+
+func (p *countingAuthProvider) Close() error {
+	p.closes++
+	return nil
+}
+
+func TestServiceURLProviderSwitchKeepsAuthProviders(t *testing.T) {
+	primaryAuth := &countingAuthProvider{Provider: auth.NewAuthDisabled()}
+	secondaryAuth := &countingAuthProvider{Provider: auth.NewAuthDisabled()}
+	primary := ClusterConfig{URL: "pulsar://primary:6650", Authentication: primaryAuth}
+	secondary := ClusterConfig{URL: "pulsar://secondary:6650", Authentication: secondaryAuth}
+	provider := &manualServiceURLProvider{cluster: primary}
+	c, err := NewClient(ClientOptions{ServiceURLProvider: provider})
+	require.NoError(t, err)
+
+	// the providers are initialized once and stay open while the client may switch back to their cluster
+	assert.NoError(t, provider.switchCluster(secondary))
+	assert.NoError(t, provider.switchCluster(primary))
+	assert.NoError(t, provider.switchCluster(secondary))
+	assert.Equal(t, 1, primaryAuth.inits)
+	assert.Equal(t, 1, secondaryAuth.inits)
+	assert.Equal(t, 0, primaryAuth.closes)
+	assert.Equal(t, 0, secondaryAuth.closes)
+
+	c.Close()
+	assert.Equal(t, 1, primaryAuth.closes)
+	assert.Equal(t, 1, secondaryAuth.closes)
+}
+
+type failingServiceURLProvider struct {
+	manualServiceURLProvider
+}
+
+func (p *failingServiceURLProvider) Initialize(switchCluster func(ClusterConfig) error) error {
+	return errors.New("initialize failed")
+}
+
+func TestServiceURLProviderInitializeError(t *testing.T) {
+	authProvider := &countingAuthProvider{Provider: auth.NewAuthDisabled()}
+	provider := &failingServiceURLProvider{manualServiceURLProvider{
+		cluster: ClusterConfig{URL: "pulsar://primary:6650", Authentication: authProvider},
+	}}
+	_, err := NewClient(ClientOptions{ServiceURLProvider: provider})
+	assert.Equal(t, InvalidConfiguration, err.(*Error).Result())
+	// the client created before the provider failed is closed
+	assert.Equal(t, 1, authProvider.closes)
+}
+
+func TestServiceURLProviderSwitch(t *testing.T) {
+	provider := &manualServiceURLProvider{
+		cluster: ClusterConfig{URL: lookupURL},
+	}
+	c, err := NewClient(ClientOptions{
+		ServiceURLProvider: provider,
+	})
+	assert.Nil(t, err)
+	defer c.Close()
+
+	producer, err := c.CreateProducer(ProducerOptions{
+		Topic: newTopicName(),
+	})
+	assert.Nil(t, err)
+	defer producer.Close()
+
+	_, err = producer.Send(context.Background(), &ProducerMessage{
+		Payload: []byte("hello"),
+	})
+	assert.Nil(t, err)
+
+	// switching closes the connections, the producer reconnects through the new service URL
+	assert.Nil(t, provider.switchCluster(ClusterConfig{URL: "pulsar://127.0.0.1:6650"}))
+	assert.Equal(t, "pulsar://127.0.0.1:6650", c.(*client).serviceNameResolver.GetServiceURL().String())
+
+	_, err = producer.Send(context.Background(), &ProducerMessage{
+		Payload: []byte("hello"),
+	})
+	assert.Nil(t, err)
+
+	err = provider.switchCluster(ClusterConfig{URL: "pulsar+ssl://localhost:6651"})
+	assert.Equal(t, InvalidConfiguration, err.(*Error).Result())
+}
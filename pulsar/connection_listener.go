@@ -0,0 +1,74 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package pulsar
+
+import (
+	"github.com/apache/pulsar-client-go/pulsar/internal"
+)
+
+// ConnectionListener receives the lifecycle events of the connections of a client, eg. to emit the telemetry of
+// the application. The methods are called synchronously from the client goroutines and must not block.
+type ConnectionListener interface {
+	// OnConnectionCreated is called once a connection to the broker is established
+	OnConnectionCreated(broker string)
+
+	// OnConnectionClosed is called once an established connection to the broker is closed
+	OnConnectionClosed(broker string)
+
+	// OnLookup is called for each topic lookup sent to the cluster, with the broker serving the topic
+	// or the error of the lookup
+	OnLookup(topic, broker string, err error)
+
+	// OnReconnectAttempt is called before each attempt of a producer or consumer to reconnect to the broker
+	OnReconnectAttempt(topic string, attempt int)
+}
+
+// connectionEventsListener forwards the connection events of the pool to the ConnectionListener
+type connectionEventsListener struct {
+	listener ConnectionListener
+}
+
+func (l connectionEventsListener) ConnectionCreated(broker string) {
+	l.listener.OnConnectionCreated(broker)
+}
+
+func (l connectionEventsListener) ConnectionClosed(broker string) {
+	l.listener.OnConnectionClosed(broker)
+}
+
+// listenedLookupService reports the lookups of the topics to the ConnectionListener
+type listenedLookupService struct {
+	internal.LookupService
+	listener ConnectionListener
+}
+
+func (l *listenedLookupService) Lookup(topic string) (*internal.LookupResult, error) {
+	result, err := l.LookupService.Lookup(topic)
+	if err != nil {
+		l.listener.OnLookup(topic, "", err)
+		return nil, err
+	}
+	l.listener.OnLookup(topic, result.LogicalAddr.String(), nil)
+	return result, nil
+}
+
+func (c *client) reconnectAttempt(topic string, attempt int) {
+	if c.connectionListener != nil {
+		c.connectionListener.OnReconnectAttempt(topic, attempt)
+	}
+}
@@ -0,0 +1,85 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package pulsar
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/pulsar-client-go/pulsar/internal"
+)
+
+type recordingConnectionListener struct {
+	lookups  []string
+	attempts []int
+}
+
+func (l *recordingConnectionListener) OnConnectionCreated(broker string) {}
+
+func (l *recordingConnectionListener) OnConnectionClosed(broker string) {}
+
+func (l *recordingConnectionListener) OnLookup(topic, broker string, err error) {
+	if err != nil {
+		l.lookups = append(l.lookups, topic+" "+err.Error())
+		return
+	}
+	l.lookups = append(l.lookups, topic+" "+broker)
+}
+
+func (l *recordingConnectionListener) OnReconnectAttempt(topic string, attempt int) {
+	l.attempts = append(l.attempts, attempt)
+}
+
+type fixedLookupService struct {
+	internal.LookupService
+	result *internal.LookupResult
+	err    error
+}
+
+func (s *fixedLookupService) Lookup(topic string) (*internal.LookupResult, error) {
+	return s.result, s.err
+}
+
+func TestListenedLookupService(t *testing.T) {
+	listener := &recordingConnectionListener{}
+	broker, err := url.Parse("pulsar://broker-1:6650")
+	assert.Nil(t, err)
+
+	lookupService := &listenedLookupService{
+		LookupService: &fixedLookupService{result: &internal.LookupResult{LogicalAddr: broker, PhysicalAddr: broker}},
+		listener:      listener,
+	}
+	result, err := lookupService.Lookup("my-topic")
+	assert.Nil(t, err)
+	assert.Equal(t, broker, result.LogicalAddr)
+
+	lookupService.LookupService = &fixedLookupService{err: errors.New("lookup failed")}
+	_, err = lookupService.Lookup("other-topic")
+	assert.NotNil(t, err)
+
+	assert.Equal(t, []string{"my-topic pulsar://broker-1:6650", "other-topic lookup failed"}, listener.lookups)
+
+	c := &client{}
+	c.reconnectAttempt("my-topic", 1)
+	c.connectionListener = listener
+	c.reconnectAttempt("my-topic", 2)
+	assert.Equal(t, []int{2}, listener.attempts)
+}
@@ -0,0 +1,118 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package pulsar
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// connectionHolder is a producer or consumer attached to brokers
+type connectionHolder interface {
+	BrokerAddress() string
+	ConnectedSince() time.Time
+	LastDisconnectedTimestamp() time.Time
+}
+
+// connectionTracker records the connections of a partition producer or consumer to its broker
+type connectionTracker struct {
+	lock             sync.RWMutex
+	brokerAddress    string
+	connectedSince   time.Time
+	lastDisconnected time.Time
+}
+
+func (t *connectionTracker) connected(brokerAddress string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.brokerAddress = brokerAddress
+	t.connectedSince = time.Now()
+}
+
+func (t *connectionTracker) disconnected() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.connectedSince = time.Time{}
+	t.lastDisconnected = time.Now()
+}
+
+// BrokerAddress returns the address of the broker the partition was last connected to
+func (t *connectionTracker) BrokerAddress() string {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	return t.brokerAddress
+}
+
+// ConnectedSince returns when the partition connected to the broker, or the zero time while it is disconnected
+func (t *connectionTracker) ConnectedSince() time.Time {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	return t.connectedSince
+}
+
+// LastDisconnectedTimestamp returns when the partition lost its connection for the last time, or the zero time
+// if it never did
+func (t *connectionTracker) LastDisconnectedTimestamp() time.Time {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	return t.lastDisconnected
+}
+
+// brokerAddresses returns the distinct broker addresses of the holders, sorted and comma separated
+func brokerAddresses(holders []connectionHolder) string {
+	seen := make(map[string]bool)
+	var addresses []string
+	for _, h := range holders {
+		for _, addr := range strings.Split(h.BrokerAddress(), ",") {
+			if addr != "" && !seen[addr] {
+				seen[addr] = true
+				addresses = append(addresses, addr)
+			}
+		}
+	}
+	sort.Strings(addresses)
+	return strings.Join(addresses, ",")
+}
+
+// connectedSince returns since when all the holders are connected, or the zero time if any is disconnected
+func connectedSince(holders []connectionHolder) time.Time {
+	var since time.Time
+	for _, h := range holders {
+		t := h.ConnectedSince()
+		if t.IsZero() {
+			return time.Time{}
+		}
+		if t.After(since) {
+			since = t
+		}
+	}
+	return since
+}
+
+// lastDisconnectedTimestamp returns when any of the holders lost its connection for the last time
+func lastDisconnectedTimestamp(holders []connectionHolder) time.Time {
+	var last time.Time
+	for _, h := range holders {
+		if t := h.LastDisconnectedTimestamp(); t.After(last) {
+			last = t
+		}
+	}
+	return last
+}
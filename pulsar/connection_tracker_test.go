@@ -0,0 +1,59 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package pulsar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConnectionTracker(t *testing.T) {
+	tracker := &connectionTracker{}
+	assert.Empty(t, tracker.BrokerAddress())
+	assert.True(t, tracker.ConnectedSince().IsZero())
+	assert.True(t, tracker.LastDisconnectedTimestamp().IsZero())
+
+	tracker.connected("pulsar://broker-1:6650")
+	assert.Equal(t, "pulsar://broker-1:6650", tracker.BrokerAddress())
+	assert.False(t, tracker.ConnectedSince().IsZero())
+
+	tracker.disconnected()
+	assert.Equal(t, "pulsar://broker-1:6650", tracker.BrokerAddress())
+	assert.True(t, tracker.ConnectedSince().IsZero())
+	assert.False(t, tracker.LastDisconnectedTimestamp().IsZero())
+}
+
+func TestConnectionHoldersAggregation(t *testing.T) {
+	now := time.Now()
+	p1 := &connectionTracker{brokerAddress: "pulsar://broker-2:6650", connectedSince: now.Add(-time.Minute)}
+	p2 := &connectionTracker{brokerAddress: "pulsar://broker-1:6650", connectedSince: now,
+		lastDisconnected: now.Add(-time.Second)}
+	p3 := &connectionTracker{brokerAddress: "pulsar://broker-2:6650", connectedSince: now.Add(-time.Hour)}
+	holders := []connectionHolder{p1, p2, p3}
+
+	assert.Equal(t, "pulsar://broker-1:6650,pulsar://broker-2:6650", brokerAddresses(holders))
+	assert.Equal(t, now, connectedSince(holders))
+	assert.Equal(t, now.Add(-time.Second), lastDisconnectedTimestamp(holders))
+
+	// the holders are not all connected while one of them is disconnected
+	p3.disconnected()
+	assert.True(t, connectedSince(holders).IsZero())
+	assert.Equal(t, p3.LastDisconnectedTimestamp(), lastDisconnectedTimestamp(holders))
+}
@@ -19,9 +19,35 @@ package pulsar
 
 import (
 	"context"
+	"fmt"
+	"math"
 	"time"
 )
 
+// ErrConsumerClosed is returned by the operations made on a consumer once it is closed
+var ErrConsumerClosed = newError(ConsumerClosed, "consumer closed")
+
+// ErrCumulativeAckNotAllowed is returned by the cumulative acks of the consumers of Shared and KeyShared subscriptions
+var ErrCumulativeAckNotAllowed = newError(OperationNotSupported,
+	"cumulative ack is not allowed on Shared and KeyShared subscriptions")
+
+// AckError is the error of Consumer.AckIDList, it gives the error of each message id that was not acknowledged,
+// in the order of the ids given
+type AckError []MessageIDError
+
+// MessageIDError is the error of a message id that was not acknowledged
+type MessageIDError struct {
+	ID  MessageID
+	Err error
+}
+
+func (e AckError) Error() string {
+	if len(e) == 0 {
+		return "failed to ack the messages"
+	}
+	return fmt.Sprintf("failed to ack %d messages: %v", len(e), e[0].Err)
+}
+
 // Pair of a Consumer and Message
 type ConsumerMessage struct {
 	Consumer
@@ -70,6 +96,49 @@ type DLQPolicy struct {
 
 	// Name of the topic where the retry messages will be sent.
 	RetryLetterTopic string
+
+	// InitialSubscriptionName is the name of a subscription created on the dead letter and retry topics before
+	// the first message is sent to them, so that the messages are retained until a consumer subscribes.
+	// No subscription is created when empty.
+	InitialSubscriptionName string
+}
+
+// FlowPermitStrategy decides whether a consumer gives the permits of the messages already passed to the application
+// back to the broker, with a FLOW command, or keeps accumulating them. It is called with the permits accumulated so
+// far and the receiver queue size, each partition accounts for its own permits. Fewer permits per FLOW command mean
+// more commands but the broker refills the queue sooner. It runs on the goroutine dispatching the messages of the
+// partition and must not block.
+type FlowPermitStrategy func(availablePermits, receiverQueueSize int) bool
+
+// HalfQueueFlowPermits gives the permits back once they add up to half the receiver queue
+func HalfQueueFlowPermits() FlowPermitStrategy {
+	return func(availablePermits, receiverQueueSize int) bool {
+		return availablePermits >= receiverQueueSize/2
+	}
+}
+
+// PerMessageFlowPermits gives the permit of each message back as soon as the application receives it, for the
+// lowest delivery latency
+func PerMessageFlowPermits() FlowPermitStrategy {
+	return func(availablePermits, receiverQueueSize int) bool {
+		return availablePermits > 0
+	}
+}
+
+// RedeliveryBackoff returns the delay before redelivering a message from the number of times it was already
+// redelivered
+type RedeliveryBackoff func(redeliveryCount uint32) time.Duration
+
+// NewExponentialRedeliveryBackoff returns a RedeliveryBackoff starting with the min delay for the messages never
+// redelivered yet and multiplying it for every redelivery, up to the max delay
+func NewExponentialRedeliveryBackoff(minDelay, maxDelay time.Duration, multiplier float64) RedeliveryBackoff {
+	return func(redeliveryCount uint32) time.Duration {
+		delay := float64(minDelay) * math.Pow(multiplier, float64(redeliveryCount))
+		if delay > float64(maxDelay) {
+			return maxDelay
+		}
+		return time.Duration(delay)
+	}
 }
 
 // ConsumerOptions is used to configure and create instances of Consumer
@@ -89,6 +158,11 @@ type ConsumerOptions struct {
 	// Specify the interval in which to poll for new partitions or new topics if using a TopicsPattern.
 	AutoDiscoveryPeriod time.Duration
 
+	// PartitionsChangedHandler is called with the topic partitions the auto-discovery subscribed to and the ones
+	// it unsubscribed from, which only happens to the topics no longer matching the TopicsPattern.
+	// See Consumer.Partitions.
+	PartitionsChangedHandler func(added, removed []string)
+
 	// Specify the subscription name for this consumer
 	// This argument is required when subscribing
 	SubscriptionName string
@@ -102,9 +176,16 @@ type ConsumerOptions struct {
 	Type SubscriptionType
 
 	// InitialPosition at which the cursor will be set when subscribe
+	// The position only applies when the subscription is created, the cursor of an existing durable
+	// subscription is kept, see ResetSubscriptionPosition.
 	// Default is `Latest`
 	SubscriptionInitialPosition
 
+	// Move the cursor of the subscription to SubscriptionInitialPosition once subscribed, even when the
+	// subscription already exists. The consumer creation fails with a SeekFailed error if it can't.
+	// Default is false
+	ResetSubscriptionPosition bool
+
 	// Configuration for Dead Letter Queue consumer policy.
 	// eg. route the message to topic X after N failed attempts at processing it
 	// By default is nil and there's no DLQ
@@ -119,6 +200,8 @@ type ConsumerOptions struct {
 
 	// Sets a `MessageChannel` for the consumer
 	// When a message is received, it will be pushed to the channel for consumption
+	// The channel belongs to the application, which may share it between consumers, and is left open when the
+	// consumer is closed. The channel the consumer creates when it is not set is closed with the consumer.
 	MessageChannel chan ConsumerMessage
 
 	// Sets the size of the consumer receive queue.
@@ -128,11 +211,32 @@ type ConsumerOptions struct {
 	// Default value is `1000` messages and should be good for most use cases.
 	ReceiverQueueSize int
 
+	// Sets the maximum number of messages prefetched by all the partitions of a partitioned topic, the receiver
+	// queue of each partition is reduced to share it and shrinks again when partitions are added.
+	// Default is 0, the receiver queue size then applies to every partition.
+	MaxTotalReceiverQueueSizeAcrossPartitions int
+
+	// FlowPermitStrategy decides when the permits of the messages received by the application, whether with
+	// Receive or from Chan, are given back to the broker. The consumer always starts with the permits of the whole
+	// receiver queue. Default is HalfQueueFlowPermits.
+	FlowPermitStrategy FlowPermitStrategy
+
 	// The delay after which to redeliver the messages that failed to be
 	// processed. Default is 1min. (See `Consumer.Nack()`)
 	NackRedeliveryDelay time.Duration
 
-	// Set the consumer name.
+	// The time after which the messages that were neither acked nor nacked are redelivered, it can be
+	// postponed per message with Consumer.ExtendAckDeadline. Default is 0, which disables the ack timeout,
+	// otherwise it must be at least 1s.
+	AckTimeout time.Duration
+
+	// AckTimeoutRedeliveryBackoff delays the redelivery of the messages reaching the ack timeout by the duration it
+	// returns for their redelivery count, so that a message failing again and again isn't redelivered in a tight
+	// loop. The message can still be acked during the delay. Default is nil, the messages are then redelivered as
+	// soon as they reach the ack timeout.
+	AckTimeoutRedeliveryBackoff RedeliveryBackoff
+
+	// Set the consumer name. A random name is generated when it is not set.
 	Name string
 
 	// If enabled, the consumer will read messages from the compacted topic rather than reading the full message backlog
@@ -151,10 +255,77 @@ type ConsumerOptions struct {
 	// A chain of interceptors, These interceptors will be called at some points defined in ConsumerInterceptor interface.
 	Interceptors ConsumerInterceptors
 
+	// MessageFilter selects the messages delivered to the application, eg. by their properties, until the
+	// broker can filter them. The messages it returns false for are acknowledged without being delivered.
+	// Default is nil, all the messages are delivered.
+	MessageFilter func(Message) bool
+
+	// PayloadProcessor decodes the payload of the entries received from the broker instead of the standard
+	// Pulsar batch format, eg. to consume the topics written in the Kafka entry format through KoP.
+	// Default is nil, the entries are decoded as Pulsar batches.
+	PayloadProcessor MessagePayloadProcessor
+
+	// PayloadCodecs decode the payloads of the messages encoded by the producers with a PayloadCodec, by the
+	// name in their PayloadCodecProperty. The messages which can't be decoded are delivered as received,
+	// with the property still set.
+	// Default is nil, the payloads are delivered as received.
+	PayloadCodecs []PayloadCodec
+
+	// NackFilteredMessages negatively acknowledges the messages rejected by MessageFilter instead of
+	// acknowledging them, so that they are redelivered, eg. to the other consumers of a shared subscription.
+	// Default is false
+	NackFilteredMessages bool
+
+	// AutoAck acknowledges the messages as soon as they are delivered to the application, by Receive or
+	// through Chan, for at-most-once processing: the messages being processed when the application stops are
+	// lost rather than redelivered. Ack and Nack have no effect on the delivered messages then.
+	// Default is false
+	AutoAck bool
+
 	Schema Schema
 
+	// TopicSchemas sets the schema of some of the topics of a multi-topic consumer, keyed by topic name, when
+	// they differ from Schema which applies to the other topics.
+	TopicSchemas map[string]Schema
+
 	// MaxReconnectToBroker set the maximum retry number of reconnectToBroker. (default: ultimate)
+	// Once the attempts are exhausted the consumer of the partition is closed and ReconnectFailedHandler is called.
 	MaxReconnectToBroker *uint
+
+	// ReconnectFailedHandler is called with the topic partition and the last error when the consumer gave up
+	// reconnecting to the broker after MaxReconnectToBroker attempts
+	ReconnectFailedHandler func(topic string, err error)
+
+	// BackoffPolicy creates the backoff policy between the attempts to reconnect the consumer to the broker.
+	// (default: the BackoffPolicy of the client)
+	BackoffPolicy func() BackoffPolicy
+
+	// CopyPayload copies the payload of each received message out of the buffer the message was read into.
+	// By default the payloads of uncompressed messages are slices of that buffer, which is then kept in memory
+	// as long as any message of the batch is referenced by the application. (default: false)
+	CopyPayload bool
+
+	// ReleasePayload hands out the payloads of the received messages as slices of pooled buffers. The application
+	// calls Message.Release once it is done with a message, Ack releases the message as well, and the buffer goes
+	// back to the pool once all the messages sliced from it are released. This cuts the allocations of consumers
+	// with a high throughput but the payload must not be used after the release. A message which is not released
+	// is simply garbage collected. It cannot be combined with CopyPayload. (default: false)
+	ReleasePayload bool
+
+	// SyncAck makes Ack and AckID block until the ack was handed to the connection. By default the acks are
+	// queued without blocking and sent in batches by the consumer. (default: false)
+	SyncAck bool
+
+	// EventsQueueSize is the size of the queue of the requests made to each partition consumer and handled in
+	// order, like the acks within a transaction, the seeks and the redeliveries. The callers wait while it is full,
+	// which the pulsar_client_consumer_events_queue_full metric counts. (default: 10)
+	EventsQueueSize int
+
+	// DeduplicationWindow is the number of the last messages delivered by each partition whose ids are remembered
+	// to drop the messages the broker delivers again, eg. the unacked messages redelivered after a reconnection.
+	// The messages redelivered on purpose, after a nack, an ack timeout or a seek, aren't dropped. The dropped
+	// messages are counted by the pulsar_client_consumer_duplicates_dropped metric. (default: 0, disabled)
+	DeduplicationWindow int
 }
 
 // Consumer is an interface that abstracts behavior of Pulsar's consumer
@@ -165,19 +336,48 @@ type Consumer interface {
 	// Unsubscribe the consumer
 	Unsubscribe() error
 
+	// UnsubscribeWithCtx unsubscribes the consumer, it returns the context error if the context is done before the
+	// unsubscribe completes
+	UnsubscribeWithCtx(context.Context) error
+
 	// Receive a single message.
 	// This calls blocks until a message is available.
 	Receive(context.Context) (Message, error)
 
 	// Chan returns a channel to consume messages from
+	// The channel is closed once the consumer is closed, unless it was set with ConsumerOptions.MessageChannel.
+	// Unlike Receive, which drops them, the messages received before a seek and still waiting in the channel
+	// are delivered.
 	Chan() <-chan ConsumerMessage
 
-	// Ack the consumption of a single message
+	// Ack the consumption of a single message, the message is released (see ConsumerOptions.ReleasePayload)
 	Ack(Message)
 
 	// AckID the consumption of a single message, identified by its MessageID
 	AckID(MessageID)
 
+	// AckIDList acknowledges the consumption of the messages, identified by their MessageID, eg. after processing
+	// them in bulk. The acks of each topic partition are sent in a single command, AckIDList returns once they
+	// were handed to the connection. The ids which could not be acked are given in an AckError.
+	AckIDList([]MessageID) error
+
+	// AckWithTxn acknowledges the consumption of a single message within the transaction, the
+	// acknowledgment only takes effect once the transaction is committed
+	AckWithTxn(Message, Transaction) error
+
+	// AckCumulative acknowledges the consumption of all the messages of the topic partition of the message
+	// up to and including it. It returns once the ack was handed to the connection, and fails with
+	// ErrCumulativeAckNotAllowed on Shared and KeyShared subscriptions.
+	AckCumulative(Message) error
+
+	// AckIDCumulative is AckCumulative for a message identified by its MessageID
+	AckIDCumulative(MessageID) error
+
+	// ExtendAckDeadline postpones until the given duration from now the redelivery of the message after
+	// ConsumerOptions.AckTimeout, eg. while its processing takes longer than usual. It fails when the ack
+	// timeout is disabled or the message was already acked, nacked or redelivered.
+	ExtendAckDeadline(MessageID, time.Duration) error
+
 	// ReconsumeLater mark a message for redelivery after custom delay
 	ReconsumeLater(msg Message, delay time.Duration)
 
@@ -202,13 +402,22 @@ type Consumer interface {
 	// Close the consumer and stop the broker to push more messages
 	Close()
 
+	// CloseWithCtx closes the consumer, it returns the context error if the context is done before the close
+	// completes. The consumer is closed anyway.
+	CloseWithCtx(context.Context) error
+
 	// Reset the subscription associated with this consumer to a specific message id.
 	// The message id can either be a specific message or represent the first or last messages in the topic.
 	//
 	// Note: this operation can only be done on non-partitioned topics. For these, one can rather perform the
-	//       seek() on the individual partitions.
+	//       seek() on the individual partitions. A consumer of several topics seeks the topic partition of
+	//       the received message given. EarliestMessageID() and LatestMessageID() reset every partition of
+	//       every topic of the consumer.
 	Seek(MessageID) error
 
+	// SeekWithCtx is Seek returning the context error if the context is done before the seek completes
+	SeekWithCtx(context.Context, MessageID) error
+
 	// Reset the subscription associated with this consumer to a specific message publish time.
 	//
 	// Note: this operation can only be done on non-partitioned topics. For these, one can rather perform the seek() on
@@ -219,6 +428,41 @@ type Consumer interface {
 	//
 	SeekByTime(time time.Time) error
 
+	// SeekByTimeWithCtx is SeekByTime returning the context error if the context is done before the seek completes
+	SeekByTimeWithCtx(ctx context.Context, time time.Time) error
+
 	// Name returns the name of consumer.
 	Name() string
+
+	// BrokerAddress returns the address of the broker the consumer is connected to, the distinct addresses
+	// of the brokers are comma separated when the consumer has several partitions or topics.
+	BrokerAddress() string
+
+	// ConnectedSince returns since when the consumer is connected to its brokers, or the zero time while any of
+	// its partitions is disconnected.
+	ConnectedSince() time.Time
+
+	// LastDisconnectedTimestamp returns when the consumer lost a connection to a broker for the last time,
+	// or the zero time if it never did.
+	LastDisconnectedTimestamp() time.Time
+
+	// UnackedMessages returns the number of messages received by the consumer that weren't acked or nacked yet,
+	// including the messages still sitting in the receiver queue.
+	UnackedMessages() int
+
+	// OldestUnackedMessageAge returns for how long the oldest unacked message has been received by the consumer,
+	// or 0 when all the messages were acked or nacked.
+	OldestUnackedMessageAge() time.Duration
+
+	// ActiveConsumerChanges returns how many times the broker made the consumer active or inactive on its
+	// Failover subscription, summed over its partitions. A count growing quickly reveals a flapping ownership.
+	ActiveConsumerChanges() int
+
+	// LastActiveConsumerChange returns when the broker made the consumer active or inactive for the last time
+	// on any of its partitions, or the zero time if it never did.
+	LastActiveConsumerChange() time.Time
+
+	// Partitions returns the sorted names of the topic partitions the consumer is subscribed to, including the
+	// ones found by the auto-discovery. A non-partitioned topic is returned by its name.
+	Partitions() []string
 }
@@ -21,6 +21,7 @@ import (
 	"context"
 	"fmt"
 	"math/rand"
+	"sort"
 	"strconv"
 	"sync"
 	"time"
@@ -32,9 +33,15 @@ import (
 
 const defaultNackRedeliveryDelay = 1 * time.Minute
 
+const minAckTimeout = 1 * time.Second
+
 type acker interface {
 	AckID(id trackingMessageID)
+	AckIDList(ids []trackingMessageID) error
+	AckIDWithTxn(id trackingMessageID, txn Transaction) error
+	AckIDCumulative(id trackingMessageID) error
 	NackID(id trackingMessageID)
+	ExtendAckDeadline(id trackingMessageID, d time.Duration) error
 }
 
 type consumer struct {
@@ -46,8 +53,9 @@ type consumer struct {
 	consumerName              string
 	disableForceTopicCreation bool
 
-	// channel used to deliver message to clients
-	messageCh chan ConsumerMessage
+	// channel used to deliver message to clients, it is closed with the consumer when the consumer created it
+	messageCh      chan ConsumerMessage
+	closeMessageCh bool
 
 	dlq       *dlqRouter
 	rlq       *retryRouter
@@ -60,7 +68,7 @@ type consumer struct {
 	metrics *internal.TopicMetrics
 }
 
-func newConsumer(client *client, options ConsumerOptions) (Consumer, error) {
+func newConsumer(ctx context.Context, client *client, options ConsumerOptions) (Consumer, error) {
 	if options.Topic == "" && options.Topics == nil && options.TopicsPattern == "" {
 		return nil, newError(TopicNotFound, "topic is required")
 	}
@@ -69,6 +77,23 @@ func newConsumer(client *client, options ConsumerOptions) (Consumer, error) {
 		return nil, newError(SubscriptionNotFound, "subscription name is required for consumer")
 	}
 
+	if options.SubscriptionInitialPosition != SubscriptionPositionLatest &&
+		options.SubscriptionInitialPosition != SubscriptionPositionEarliest {
+		return nil, newError(InvalidConfiguration, "invalid subscription initial position")
+	}
+
+	if options.AckTimeout < 0 || (options.AckTimeout > 0 && options.AckTimeout < minAckTimeout) {
+		return nil, newError(InvalidConfiguration, "the ack timeout must be at least 1s")
+	}
+
+	if options.CopyPayload && options.ReleasePayload {
+		return nil, newError(InvalidConfiguration, "the payloads cannot be both copied and released")
+	}
+
+	if options.DeduplicationWindow < 0 {
+		return nil, newError(InvalidConfiguration, "the deduplication window cannot be negative")
+	}
+
 	if options.ReceiverQueueSize <= 0 {
 		options.ReceiverQueueSize = defaultReceiverQueueSize
 	}
@@ -81,10 +106,13 @@ func newConsumer(client *client, options ConsumerOptions) (Consumer, error) {
 		options.Name = generateRandomName()
 	}
 
-	if options.Schema != nil && options.Schema.GetSchemaInfo() != nil {
-		if options.Schema.GetSchemaInfo().Type == NONE {
-			options.Schema = NewBytesSchema(nil)
+	options.Schema = consumerSchema(options.Schema)
+	if len(options.TopicSchemas) > 0 {
+		schemas, err := topicSchemas(options.TopicSchemas)
+		if err != nil {
+			return nil, err
 		}
+		options.TopicSchemas = schemas
 	}
 
 	// did the user pass in a message channel?
@@ -151,7 +179,7 @@ func newConsumer(client *client, options ConsumerOptions) (Consumer, error) {
 			return nil, err
 		}
 		topic = tns[0].Name
-		return topicSubscribe(client, options, topic, messageCh, dlq, rlq)
+		return topicSubscribe(ctx, client, options, topic, messageCh, dlq, rlq)
 	}
 
 	if len(options.Topics) > 1 {
@@ -163,7 +191,7 @@ func newConsumer(client *client, options ConsumerOptions) (Consumer, error) {
 		}
 		options.Topics = distinct(options.Topics)
 
-		return newMultiTopicConsumer(client, options, options.Topics, messageCh, dlq, rlq)
+		return newMultiTopicConsumer(ctx, client, options, options.Topics, messageCh, dlq, rlq)
 	}
 
 	if options.TopicsPattern != "" {
@@ -176,14 +204,38 @@ func newConsumer(client *client, options ConsumerOptions) (Consumer, error) {
 		if err != nil {
 			return nil, err
 		}
-		return newRegexConsumer(client, options, tn, pattern, messageCh, dlq, rlq)
+		return newRegexConsumer(ctx, client, options, tn, pattern, messageCh, dlq, rlq)
 	}
 
 	return nil, newError(InvalidTopicName, "topic name is required for consumer")
 }
 
-func newInternalConsumer(client *client, options ConsumerOptions, topic string,
+// consumerSchema returns the schema to decode the messages with, the bytes schema replaces the NONE one
+func consumerSchema(schema Schema) Schema {
+	if schema != nil && schema.GetSchemaInfo() != nil && schema.GetSchemaInfo().Type == NONE {
+		return NewBytesSchema(nil)
+	}
+	return schema
+}
+
+// topicSchemas returns the schemas of the topics keyed by their fully qualified name
+func topicSchemas(schemas map[string]Schema) (map[string]Schema, error) {
+	normalized := make(map[string]Schema, len(schemas))
+	for topic, schema := range schemas {
+		tn, err := internal.ParseTopicName(topic)
+		if err != nil {
+			return nil, wrapError(InvalidTopicName, fmt.Sprintf("invalid topic name %q of the schema", topic), err)
+		}
+		normalized[tn.Name] = consumerSchema(schema)
+	}
+	return normalized, nil
+}
+
+func newInternalConsumer(ctx context.Context, client *client, options ConsumerOptions, topic string,
 	messageCh chan ConsumerMessage, dlq *dlqRouter, rlq *retryRouter, disableForceTopicCreation bool) (*consumer, error) {
+	if schema, ok := options.TopicSchemas[topic]; ok {
+		options.Schema = schema
+	}
 
 	consumer := &consumer{
 		topic:                     topic,
@@ -200,7 +252,7 @@ func newInternalConsumer(client *client, options ConsumerOptions, topic string,
 		metrics:                   client.metrics.GetTopicMetrics(topic),
 	}
 
-	err := consumer.internalTopicSubscribeToPartitions()
+	err := consumer.internalTopicSubscribeToPartitions(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -219,7 +271,7 @@ func newInternalConsumer(client *client, options ConsumerOptions, topic string,
 				return
 			case <-consumer.ticker.C:
 				consumer.log.Debug("Auto discovering new partitions")
-				consumer.internalTopicSubscribeToPartitions()
+				consumer.discoverPartitions()
 			}
 		}
 	}()
@@ -227,12 +279,129 @@ func newInternalConsumer(client *client, options ConsumerOptions, topic string,
 	return consumer, nil
 }
 
-// Name returns the name of consumer.
+// discoverPartitions subscribes to the partitions added to the topic and passes them to the
+// PartitionsChangedHandler
+func (c *consumer) discoverPartitions() {
+	old := make(map[string]bool)
+	for _, partition := range c.Partitions() {
+		old[partition] = true
+	}
+	if err := c.internalTopicSubscribeToPartitions(context.Background()); err != nil {
+		c.log.WithError(err).Warn("Failed to subscribe to the new partitions")
+		return
+	}
+
+	// the partitions are sorted by name, the added ones aren't necessarily the last ones
+	var added []string
+	for _, partition := range c.Partitions() {
+		if !old[partition] {
+			added = append(added, partition)
+		}
+	}
+	if len(added) > 0 && c.options.PartitionsChangedHandler != nil {
+		c.options.PartitionsChangedHandler(added, nil)
+	}
+}
+
+// Name returns the name of consumer, either the one set in the options or the generated one. The broker
+// doesn't assign names, the consumers of every partition subscribe with this one.
 func (c *consumer) Name() string {
 	return c.consumerName
 }
 
-func (c *consumer) internalTopicSubscribeToPartitions() error {
+func (c *consumer) connectionHolders() []connectionHolder {
+	c.Lock()
+	defer c.Unlock()
+	holders := make([]connectionHolder, len(c.consumers))
+	for i, pc := range c.consumers {
+		holders[i] = pc
+	}
+	return holders
+}
+
+func (c *consumer) BrokerAddress() string {
+	return brokerAddresses(c.connectionHolders())
+}
+
+func (c *consumer) ConnectedSince() time.Time {
+	return connectedSince(c.connectionHolders())
+}
+
+func (c *consumer) LastDisconnectedTimestamp() time.Time {
+	return lastDisconnectedTimestamp(c.connectionHolders())
+}
+
+func (c *consumer) UnackedMessages() int {
+	c.Lock()
+	defer c.Unlock()
+	n := 0
+	for _, pc := range c.consumers {
+		n += pc.UnackedMessages()
+	}
+	return n
+}
+
+func (c *consumer) OldestUnackedMessageAge() time.Duration {
+	c.Lock()
+	defer c.Unlock()
+	var oldest time.Duration
+	for _, pc := range c.consumers {
+		if age := pc.OldestUnackedMessageAge(); age > oldest {
+			oldest = age
+		}
+	}
+	return oldest
+}
+
+func (c *consumer) ActiveConsumerChanges() int {
+	c.Lock()
+	defer c.Unlock()
+	n := 0
+	for _, pc := range c.consumers {
+		n += pc.ActiveConsumerChanges()
+	}
+	return n
+}
+
+func (c *consumer) LastActiveConsumerChange() time.Time {
+	c.Lock()
+	defer c.Unlock()
+	var last time.Time
+	for _, pc := range c.consumers {
+		if t := pc.LastActiveConsumerChange(); t.After(last) {
+			last = t
+		}
+	}
+	return last
+}
+
+func (c *consumer) Partitions() []string {
+	c.Lock()
+	defer c.Unlock()
+	partitions := make([]string, len(c.consumers))
+	for i, pc := range c.consumers {
+		partitions[i] = pc.topic
+	}
+	sort.Strings(partitions)
+	return partitions
+}
+
+// partitionReceiverQueueSize returns the receiver queue size of each partition, so that the messages
+// prefetched by all the partitions stay within MaxTotalReceiverQueueSizeAcrossPartitions
+func (c *consumer) partitionReceiverQueueSize(numPartitions int) int {
+	size := c.options.ReceiverQueueSize
+	if maxTotal := c.options.MaxTotalReceiverQueueSizeAcrossPartitions; maxTotal > 0 && numPartitions > 1 {
+		if shared := maxTotal / numPartitions; shared < size {
+			size = shared
+		}
+		if size < 1 {
+			size = 1
+		}
+	}
+	return size
+}
+
+func (c *consumer) internalTopicSubscribeToPartitions(ctx context.Context) error {
 	partitions, err := c.client.TopicPartitions(c.topic)
 	if err != nil {
 		return err
@@ -259,9 +428,12 @@ func (c *consumer) internalTopicSubscribeToPartitions() error {
 
 	c.consumers = make([]*partitionConsumer, newNumPartitions)
 
+	receiverQueueSize := c.partitionReceiverQueueSize(newNumPartitions)
+
 	// Copy over the existing consumer instances
 	for i := 0; i < oldNumPartitions; i++ {
 		c.consumers[i] = oldConsumers[i]
+		c.consumers[i].setReceiverQueueSize(receiverQueueSize)
 	}
 
 	type ConsumerError struct {
@@ -270,20 +442,15 @@ func (c *consumer) internalTopicSubscribeToPartitions() error {
 		consumer  *partitionConsumer
 	}
 
-	receiverQueueSize := c.options.ReceiverQueueSize
 	metadata := c.options.Properties
 
 	partitionsToAdd := newNumPartitions - oldNumPartitions
-	var wg sync.WaitGroup
 	ch := make(chan ConsumerError, partitionsToAdd)
-	wg.Add(partitionsToAdd)
 
 	for partitionIdx := oldNumPartitions; partitionIdx < newNumPartitions; partitionIdx++ {
 		partitionTopic := partitions[partitionIdx]
 
 		go func(idx int, pt string) {
-			defer wg.Done()
-
 			var nackRedeliveryDelay time.Duration
 			if c.options.NackRedeliveryDelay == 0 {
 				nackRedeliveryDelay = defaultNackRedeliveryDelay
@@ -291,25 +458,41 @@ func (c *consumer) internalTopicSubscribeToPartitions() error {
 				nackRedeliveryDelay = c.options.NackRedeliveryDelay
 			}
 			opts := &partitionConsumerOpts{
-				topic:                      pt,
-				consumerName:               c.consumerName,
-				subscription:               c.options.SubscriptionName,
-				subscriptionType:           c.options.Type,
-				subscriptionInitPos:        c.options.SubscriptionInitialPosition,
-				partitionIdx:               idx,
-				receiverQueueSize:          receiverQueueSize,
-				nackRedeliveryDelay:        nackRedeliveryDelay,
-				metadata:                   metadata,
-				replicateSubscriptionState: c.options.ReplicateSubscriptionState,
-				startMessageID:             trackingMessageID{},
-				subscriptionMode:           durable,
-				readCompacted:              c.options.ReadCompacted,
-				interceptors:               c.options.Interceptors,
-				maxReconnectToBroker:       c.options.MaxReconnectToBroker,
-				keySharedPolicy:            c.options.KeySharedPolicy,
-				schema:                     c.options.Schema,
+				topic:                       pt,
+				consumerName:                c.consumerName,
+				subscription:                c.options.SubscriptionName,
+				subscriptionType:            c.options.Type,
+				subscriptionInitPos:         c.options.SubscriptionInitialPosition,
+				resetSubscriptionPosition:   c.options.ResetSubscriptionPosition,
+				partitionIdx:                idx,
+				receiverQueueSize:           receiverQueueSize,
+				flowPermitStrategy:          c.options.FlowPermitStrategy,
+				nackRedeliveryDelay:         nackRedeliveryDelay,
+				ackTimeout:                  c.options.AckTimeout,
+				ackTimeoutRedeliveryBackoff: c.options.AckTimeoutRedeliveryBackoff,
+				metadata:                    metadata,
+				replicateSubscriptionState:  c.options.ReplicateSubscriptionState,
+				startMessageID:              trackingMessageID{},
+				subscriptionMode:            durable,
+				readCompacted:               c.options.ReadCompacted,
+				interceptors:                c.options.Interceptors,
+				maxReconnectToBroker:        c.options.MaxReconnectToBroker,
+				reconnectFailedHandler:      c.options.ReconnectFailedHandler,
+				backoffPolicy:               c.options.BackoffPolicy,
+				keySharedPolicy:             c.options.KeySharedPolicy,
+				schema:                      c.options.Schema,
+				copyPayload:                 c.options.CopyPayload,
+				releasePayload:              c.options.ReleasePayload,
+				messageFilter:               c.options.MessageFilter,
+				payloadProcessor:            c.options.PayloadProcessor,
+				payloadCodecs:               c.options.PayloadCodecs,
+				nackFilteredMessages:        c.options.NackFilteredMessages,
+				autoAck:                     c.options.AutoAck,
+				syncAck:                     c.options.SyncAck,
+				eventsQueueSize:             c.options.EventsQueueSize,
+				dedupWindow:                 c.options.DeduplicationWindow,
 			}
-			cons, err := newPartitionConsumer(c, c.client, opts, c.messageCh, c.dlq, c.metrics)
+			cons, err := newPartitionConsumer(ctx, c, c.client, opts, c.messageCh, c.dlq, c.metrics)
 			ch <- ConsumerError{
 				err:       err,
 				partition: idx,
@@ -318,27 +501,48 @@ func (c *consumer) internalTopicSubscribeToPartitions() error {
 		}(partitionIdx, partitionTopic)
 	}
 
-	go func() {
-		wg.Wait()
-		close(ch)
-	}()
+	timeoutCh, stopTimer := c.client.creationTimeoutCh()
+	defer stopTimer()
 
-	for ce := range ch {
-		if ce.err != nil {
-			err = ce.err
-		} else {
-			c.consumers[ce.partition] = ce.consumer
+	nextConsumer := func() func() {
+		if ce := <-ch; ce.err == nil {
+			return ce.consumer.Close
+		}
+		return nil
+	}
+
+wait:
+	for pending := partitionsToAdd; pending > 0; pending-- {
+		select {
+		case ce := <-ch:
+			if ce.err != nil {
+				err = ce.err
+			} else {
+				c.consumers[ce.partition] = ce.consumer
+			}
+		case <-timeoutCh:
+			err = newError(TimeoutError, fmt.Sprintf("timed out creating the consumers of %d partitions", pending))
+			go closeLatePartitions(pending, nextConsumer)
+			break wait
+		case <-ctx.Done():
+			err = ctx.Err()
+			go closeLatePartitions(pending, nextConsumer)
+			break wait
 		}
 	}
 
 	if err != nil {
-		// Since there were some failures,
-		// cleanup all the partitions that succeeded in creating the consumer
-		for _, c := range c.consumers {
-			if c != nil {
-				c.Close()
+		// Since there were some failures, cleanup the partitions that succeeded in creating the consumer
+		// and keep consuming from the partitions which already existed
+		for _, pc := range c.consumers[oldNumPartitions:] {
+			if pc != nil {
+				pc.Close()
 			}
 		}
+		c.consumers = oldConsumers
+		for _, pc := range oldConsumers {
+			pc.setReceiverQueueSize(c.partitionReceiverQueueSize(oldNumPartitions))
+		}
 		return err
 	}
 
@@ -346,10 +550,11 @@ func (c *consumer) internalTopicSubscribeToPartitions() error {
 	return nil
 }
 
-func topicSubscribe(client *client, options ConsumerOptions, topic string,
+func topicSubscribe(ctx context.Context, client *client, options ConsumerOptions, topic string,
 	messageCh chan ConsumerMessage, dlqRouter *dlqRouter, retryRouter *retryRouter) (Consumer, error) {
-	c, err := newInternalConsumer(client, options, topic, messageCh, dlqRouter, retryRouter, false)
+	c, err := newInternalConsumer(ctx, client, options, topic, messageCh, dlqRouter, retryRouter, false)
 	if err == nil {
+		c.closeMessageCh = options.MessageChannel == nil
 		c.metrics.ConsumersOpened.Inc()
 	}
 	return c, err
@@ -360,12 +565,16 @@ func (c *consumer) Subscription() string {
 }
 
 func (c *consumer) Unsubscribe() error {
+	return c.UnsubscribeWithCtx(context.Background())
+}
+
+func (c *consumer) UnsubscribeWithCtx(ctx context.Context) error {
 	c.Lock()
 	defer c.Unlock()
 
 	var errMsg string
 	for _, consumer := range c.consumers {
-		if err := consumer.Unsubscribe(); err != nil {
+		if err := consumer.Unsubscribe(ctx); err != nil {
 			errMsg += fmt.Sprintf("topic %s, subscription %s: %s", consumer.topic, c.Subscription(), err)
 		}
 	}
@@ -379,10 +588,13 @@ func (c *consumer) Receive(ctx context.Context) (message Message, err error) {
 	for {
 		select {
 		case <-c.closeCh:
-			return nil, newError(ConsumerClosed, "consumer closed")
+			return nil, ErrConsumerClosed
 		case cm, ok := <-c.messageCh:
 			if !ok {
-				return nil, newError(ConsumerClosed, "consumer closed")
+				return nil, ErrConsumerClosed
+			}
+			if seekedPast(cm) {
+				continue
 			}
 			return cm.Message, nil
 		case <-ctx.Done():
@@ -399,6 +611,7 @@ func (c *consumer) Chan() <-chan ConsumerMessage {
 // Ack the consumption of a single message
 func (c *consumer) Ack(msg Message) {
 	c.AckID(msg.ID())
+	msg.Release()
 }
 
 // Ack the consumption of a single message, identified by its MessageID
@@ -416,6 +629,108 @@ func (c *consumer) AckID(msgID MessageID) {
 	c.consumers[mid.partitionIdx].AckID(mid)
 }
 
+// AckIDList acknowledges the consumption of the messages, the acks of each partition are sent together
+func (c *consumer) AckIDList(msgIDs []MessageID) error {
+	return ackIDList(msgIDs, func(msgID MessageID) (trackingMessageID, error) {
+		mid, ok := c.messageID(msgID)
+		if !ok {
+			return mid, newError(InvalidMessage, "invalid message id")
+		}
+		if mid.consumer == nil {
+			mid.consumer = c.consumers[mid.partitionIdx]
+		}
+		return mid, nil
+	})
+}
+
+// ackIDList groups the message ids by the partition consumer acking them, resolve returns the tracking id of a
+// message, with its consumer, or the error of an invalid id
+func ackIDList(msgIDs []MessageID, resolve func(MessageID) (trackingMessageID, error)) error {
+	errs := make([]error, len(msgIDs))
+	var ackers []acker
+	indexes := make(map[acker][]int)
+	mids := make(map[acker][]trackingMessageID)
+	for i, msgID := range msgIDs {
+		mid, err := resolve(msgID)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		if _, ok := mids[mid.consumer]; !ok {
+			ackers = append(ackers, mid.consumer)
+		}
+		indexes[mid.consumer] = append(indexes[mid.consumer], i)
+		mids[mid.consumer] = append(mids[mid.consumer], mid)
+	}
+
+	for _, a := range ackers {
+		if err := a.AckIDList(mids[a]); err != nil {
+			for _, i := range indexes[a] {
+				errs[i] = err
+			}
+		}
+	}
+
+	var ackErr AckError
+	for i, err := range errs {
+		if err != nil {
+			ackErr = append(ackErr, MessageIDError{ID: msgIDs[i], Err: err})
+		}
+	}
+	if len(ackErr) > 0 {
+		return ackErr
+	}
+	return nil
+}
+
+// AckWithTxn acknowledges the consumption of a single message within the transaction
+func (c *consumer) AckWithTxn(msg Message, txn Transaction) error {
+	mid, ok := c.messageID(msg.ID())
+	if !ok {
+		return newError(InvalidMessage, "invalid message id")
+	}
+
+	if mid.consumer != nil {
+		return mid.consumer.AckIDWithTxn(mid, txn)
+	}
+
+	return c.consumers[mid.partitionIdx].AckIDWithTxn(mid, txn)
+}
+
+// AckCumulative acknowledges the consumption of all the messages of the partition up to and including the message
+func (c *consumer) AckCumulative(msg Message) error {
+	return c.AckIDCumulative(msg.ID())
+}
+
+// AckIDCumulative acknowledges the consumption of all the messages of the partition up to and including the
+// message identified by its MessageID
+func (c *consumer) AckIDCumulative(msgID MessageID) error {
+	mid, ok := c.messageID(msgID)
+	if !ok {
+		return newError(InvalidMessage, "invalid message id")
+	}
+
+	if mid.consumer != nil {
+		return mid.consumer.AckIDCumulative(mid)
+	}
+
+	return c.consumers[mid.partitionIdx].AckIDCumulative(mid)
+}
+
+// ExtendAckDeadline postpones until d from now the redelivery of the unacked message after the ack timeout
+func (c *consumer) ExtendAckDeadline(msgID MessageID, d time.Duration) error {
+	mid, ok := c.messageID(msgID)
+	if !ok {
+		return newError(InvalidMessage, "invalid message id")
+	}
+
+	if mid.consumer != nil {
+		return mid.consumer.ExtendAckDeadline(mid, d)
+	}
+
+	return c.consumers[mid.partitionIdx].ExtendAckDeadline(mid, d)
+}
+
 // ReconsumeLater mark a message for redelivery after custom delay
 func (c *consumer) ReconsumeLater(msg Message, delay time.Duration) {
 	if delay < 0 {
@@ -498,6 +813,9 @@ func (c *consumer) Close() {
 		}
 		wg.Wait()
 		close(c.closeCh)
+		if c.closeMessageCh {
+			close(c.messageCh)
+		}
 		c.ticker.Stop()
 		c.client.handlers.Del(c)
 		c.dlq.close()
@@ -507,10 +825,28 @@ func (c *consumer) Close() {
 	})
 }
 
+func (c *consumer) CloseWithCtx(ctx context.Context) error {
+	return runWithCtx(ctx, c.Close)
+}
+
 func (c *consumer) Seek(msgID MessageID) error {
+	return c.SeekWithCtx(context.Background(), msgID)
+}
+
+func (c *consumer) SeekWithCtx(ctx context.Context, msgID MessageID) error {
 	c.Lock()
 	defer c.Unlock()
 
+	// the earliest and latest positions apply to every partition
+	if mid, ok := toTrackingMessageID(msgID); ok && mid.isSentinel() {
+		for _, pc := range c.consumers {
+			if err := pc.Seek(ctx, mid); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
 	if len(c.consumers) > 1 {
 		return newError(SeekFailed, "for partition topic, seek command should perform on the individual partitions")
 	}
@@ -520,17 +856,21 @@ func (c *consumer) Seek(msgID MessageID) error {
 		return nil
 	}
 
-	return c.consumers[mid.partitionIdx].Seek(mid)
+	return c.consumers[mid.partitionIdx].Seek(ctx, mid)
 }
 
 func (c *consumer) SeekByTime(time time.Time) error {
+	return c.SeekByTimeWithCtx(context.Background(), time)
+}
+
+func (c *consumer) SeekByTimeWithCtx(ctx context.Context, time time.Time) error {
 	c.Lock()
 	defer c.Unlock()
 	if len(c.consumers) > 1 {
 		return newError(SeekFailed, "for partition topic, seek command should perform on the individual partitions")
 	}
 
-	return c.consumers[0].SeekByTime(time)
+	return c.consumers[0].SeekByTime(ctx, time)
 }
 
 var r = &random{
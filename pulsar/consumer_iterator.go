@@ -0,0 +1,118 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package pulsar
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrIteratorDone is returned by ConsumerIterator.Next once the iteration is done. Like io.EOF, it isn't a
+// failure and carries no Result, compare it with ==.
+var ErrIteratorDone = errors.New("consumer iterator done")
+
+// ConsumerIterator receives the messages of a consumer one at a time, as an alternative to Receive and Chan
+// for the loops which stop from another goroutine, eg.
+//
+//	it := pulsar.NewConsumerIterator(consumer)
+//	defer it.Done()
+//	for {
+//	    msg, err := it.Next(ctx)
+//	    if err != nil {
+//	        break
+//	    }
+//	    ...
+//	}
+//
+// With Go 1.23 or later, the function returned by All can be ranged over as an iter.Seq2[Message, error].
+// Done stops the iteration without closing the consumer.
+type ConsumerIterator interface {
+	// Next blocks until a message is received and returns it. It fails with the context error when the context
+	// is done, ErrConsumerClosed once the channel of the consumer is closed (see Consumer.Chan) and
+	// ErrIteratorDone once Done is called.
+	Next(ctx context.Context) (Message, error)
+
+	// All returns a function yielding the messages received until the yield function returns false, the context
+	// is done or the iteration ends. The error ending the iteration is yielded last, except ErrIteratorDone.
+	All(ctx context.Context) func(yield func(Message, error) bool)
+
+	// Done ends the iteration, the calls of Next blocked waiting for a message return. It may be called
+	// several times.
+	Done()
+}
+
+type consumerIterator struct {
+	messageCh <-chan ConsumerMessage
+	doneCh    chan struct{}
+	doneOnce  sync.Once
+}
+
+// NewConsumerIterator returns an iterator over the messages received by the consumer
+func NewConsumerIterator(consumer Consumer) ConsumerIterator {
+	return &consumerIterator{
+		messageCh: consumer.Chan(),
+		doneCh:    make(chan struct{}),
+	}
+}
+
+func (it *consumerIterator) Next(ctx context.Context) (Message, error) {
+	// the iteration ends as soon as it is done, even when messages are available
+	select {
+	case <-it.doneCh:
+		return nil, ErrIteratorDone
+	default:
+	}
+
+	for {
+		select {
+		case cm, ok := <-it.messageCh:
+			if !ok {
+				return nil, ErrConsumerClosed
+			}
+			if seekedPast(cm) {
+				continue
+			}
+			return cm.Message, nil
+		case <-it.doneCh:
+			return nil, ErrIteratorDone
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func (it *consumerIterator) All(ctx context.Context) func(yield func(Message, error) bool) {
+	return func(yield func(Message, error) bool) {
+		for {
+			msg, err := it.Next(ctx)
+			if err == ErrIteratorDone {
+				return
+			}
+			if !yield(msg, err) || err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (it *consumerIterator) Done() {
+	it.doneOnce.Do(func() {
+		close(it.doneCh)
+	})
+}
@@ -0,0 +1,79 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package pulsar
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConsumerIterator(t *testing.T) {
+	messageCh := make(chan ConsumerMessage, 3)
+	it := NewConsumerIterator(&consumer{messageCh: messageCh})
+	for i := 0; i < 3; i++ {
+		messageCh <- ConsumerMessage{Message: &message{key: string(rune('a' + i))}}
+	}
+
+	msg, err := it.Next(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, "a", msg.Key())
+
+	var keys []string
+	it.All(context.Background())(func(msg Message, err error) bool {
+		assert.Nil(t, err)
+		keys = append(keys, msg.Key())
+		return len(keys) < 2
+	})
+	assert.Equal(t, []string{"b", "c"}, keys)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = it.Next(ctx)
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	// Done returns the calls of Next waiting for a message
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		it.Done()
+	}()
+	_, err = it.Next(context.Background())
+	assert.Equal(t, ErrIteratorDone, err)
+
+	// the iteration ends silently once done
+	it.Done()
+	it.All(context.Background())(func(msg Message, err error) bool {
+		t.Error("unexpected iteration")
+		return true
+	})
+}
+
+func TestConsumerIteratorClosedConsumer(t *testing.T) {
+	messageCh := make(chan ConsumerMessage)
+	it := NewConsumerIterator(&consumer{messageCh: messageCh})
+	close(messageCh)
+
+	var errs []error
+	it.All(context.Background())(func(msg Message, err error) bool {
+		errs = append(errs, err)
+		return true
+	})
+	assert.Equal(t, []error{ErrConsumerClosed}, errs)
+}
@@ -19,7 +19,7 @@ package pulsar
 
 import (
 	"context"
-	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -32,8 +32,9 @@ import (
 type multiTopicConsumer struct {
 	options ConsumerOptions
 
-	consumerName string
-	messageCh    chan ConsumerMessage
+	consumerName   string
+	messageCh      chan ConsumerMessage
+	closeMessageCh bool
 
 	consumers map[string]Consumer
 
@@ -45,21 +46,22 @@ type multiTopicConsumer struct {
 	log log.Logger
 }
 
-func newMultiTopicConsumer(client *client, options ConsumerOptions, topics []string,
+func newMultiTopicConsumer(ctx context.Context, client *client, options ConsumerOptions, topics []string,
 	messageCh chan ConsumerMessage, dlq *dlqRouter, rlq *retryRouter) (Consumer, error) {
 	mtc := &multiTopicConsumer{
-		options:      options,
-		messageCh:    messageCh,
-		consumers:    make(map[string]Consumer, len(topics)),
-		closeCh:      make(chan struct{}),
-		dlq:          dlq,
-		rlq:          rlq,
-		log:          client.log.SubLogger(log.Fields{"topic": topics}),
-		consumerName: options.Name,
+		options:        options,
+		messageCh:      messageCh,
+		closeMessageCh: options.MessageChannel == nil,
+		consumers:      make(map[string]Consumer, len(topics)),
+		closeCh:        make(chan struct{}),
+		dlq:            dlq,
+		rlq:            rlq,
+		log:            client.log.SubLogger(log.Fields{"topic": topics}),
+		consumerName:   options.Name,
 	}
 
 	var errs error
-	for ce := range subscriber(client, topics, options, messageCh, dlq, rlq) {
+	for ce := range subscriber(ctx, client, topics, options, messageCh, dlq, rlq) {
 		if ce.err != nil {
 			errs = pkgerrors.Wrapf(ce.err, "unable to subscribe to topic=%s", ce.topic)
 		} else {
@@ -82,25 +84,32 @@ func (c *multiTopicConsumer) Subscription() string {
 }
 
 func (c *multiTopicConsumer) Unsubscribe() error {
-	var errs error
+	return c.UnsubscribeWithCtx(context.Background())
+}
+
+// UnsubscribeWithCtx unsubscribes from all the topics, it returns a *TopicsError when it failed on some of them
+func (c *multiTopicConsumer) UnsubscribeWithCtx(ctx context.Context) error {
+	errs := make(map[string]error)
 	for t, consumer := range c.consumers {
-		if err := consumer.Unsubscribe(); err != nil {
-			msg := fmt.Sprintf("unable to unsubscribe from topic=%s subscription=%s",
-				t, c.Subscription())
-			errs = pkgerrors.Wrap(err, msg)
+		if err := consumer.UnsubscribeWithCtx(ctx); err != nil {
+			c.log.WithError(err).Warnf("unable to unsubscribe from topic=%s subscription=%s", t, c.Subscription())
+			errs[t] = err
 		}
 	}
-	return errs
+	return newTopicsError("unsubscribe from", len(c.consumers), errs)
 }
 
 func (c *multiTopicConsumer) Receive(ctx context.Context) (message Message, err error) {
 	for {
 		select {
 		case <-c.closeCh:
-			return nil, newError(ConsumerClosed, "consumer closed")
+			return nil, ErrConsumerClosed
 		case cm, ok := <-c.messageCh:
 			if !ok {
-				return nil, newError(ConsumerClosed, "consumer closed")
+				return nil, ErrConsumerClosed
+			}
+			if seekedPast(cm) {
+				continue
 			}
 			return cm.Message, nil
 		case <-ctx.Done():
@@ -117,6 +126,7 @@ func (c *multiTopicConsumer) Chan() <-chan ConsumerMessage {
 // Ack the consumption of a single message
 func (c *multiTopicConsumer) Ack(msg Message) {
 	c.AckID(msg.ID())
+	msg.Release()
 }
 
 // Ack the consumption of a single message, identified by its MessageID
@@ -135,6 +145,79 @@ func (c *multiTopicConsumer) AckID(msgID MessageID) {
 	mid.Ack()
 }
 
+// AckIDList acknowledges the consumption of the messages, the acks of each topic partition are sent together
+func (c *multiTopicConsumer) AckIDList(msgIDs []MessageID) error {
+	return ackIDList(msgIDs, c.trackingMessageID)
+}
+
+// trackingMessageID returns the tracking id of a message received by the consumer
+func (c *multiTopicConsumer) trackingMessageID(msgID MessageID) (trackingMessageID, error) {
+	mid, ok := toTrackingMessageID(msgID)
+	if !ok {
+		c.log.Warnf("invalid message id type %T", msgID)
+		return mid, newError(InvalidMessage, "invalid message id")
+	}
+	if mid.consumer == nil {
+		c.log.Warnf("unable to ack messageID=%+v can not determine topic", msgID)
+		return mid, newError(InvalidMessage, "unable to determine the topic of the message")
+	}
+	return mid, nil
+}
+
+// AckWithTxn acknowledges the consumption of a single message within the transaction
+func (c *multiTopicConsumer) AckWithTxn(msg Message, txn Transaction) error {
+	mid, ok := toTrackingMessageID(msg.ID())
+	if !ok {
+		c.log.Warnf("invalid message id type %T", msg.ID())
+		return newError(InvalidMessage, "invalid message id")
+	}
+
+	if mid.consumer == nil {
+		c.log.Warnf("unable to ack messageID=%+v can not determine topic", msg.ID())
+		return newError(InvalidMessage, "unable to determine the topic of the message")
+	}
+
+	return mid.consumer.AckIDWithTxn(mid, txn)
+}
+
+// AckCumulative acknowledges the consumption of all the messages of the topic partition up to and including the message
+func (c *multiTopicConsumer) AckCumulative(msg Message) error {
+	return c.AckIDCumulative(msg.ID())
+}
+
+// AckIDCumulative acknowledges the consumption of all the messages of the topic partition up to and including the
+// message identified by its MessageID
+func (c *multiTopicConsumer) AckIDCumulative(msgID MessageID) error {
+	mid, ok := toTrackingMessageID(msgID)
+	if !ok {
+		c.log.Warnf("invalid message id type %T", msgID)
+		return newError(InvalidMessage, "invalid message id")
+	}
+
+	if mid.consumer == nil {
+		c.log.Warnf("unable to ack messageID=%+v can not determine topic", msgID)
+		return newError(InvalidMessage, "unable to determine the topic of the message")
+	}
+
+	return mid.consumer.AckIDCumulative(mid)
+}
+
+// ExtendAckDeadline postpones until d from now the redelivery of the unacked message after the ack timeout
+func (c *multiTopicConsumer) ExtendAckDeadline(msgID MessageID, d time.Duration) error {
+	mid, ok := toTrackingMessageID(msgID)
+	if !ok {
+		c.log.Warnf("invalid message id type %T", msgID)
+		return newError(InvalidMessage, "invalid message id")
+	}
+
+	if mid.consumer == nil {
+		c.log.Warnf("unable to extend the ack deadline of messageID=%+v can not determine topic", msgID)
+		return newError(InvalidMessage, "unable to determine the topic of the message")
+	}
+
+	return mid.consumer.ExtendAckDeadline(mid, d)
+}
+
 func (c *multiTopicConsumer) ReconsumeLater(msg Message, delay time.Duration) {
 	names, err := validateTopicNames(msg.Topic())
 	if err != nil {
@@ -186,20 +269,128 @@ func (c *multiTopicConsumer) Close() {
 		}
 		wg.Wait()
 		close(c.closeCh)
+		if c.closeMessageCh {
+			close(c.messageCh)
+		}
 		c.dlq.close()
 		c.rlq.close()
 	})
 }
 
+func (c *multiTopicConsumer) CloseWithCtx(ctx context.Context) error {
+	return runWithCtx(ctx, c.Close)
+}
+
 func (c *multiTopicConsumer) Seek(msgID MessageID) error {
-	return newError(SeekFailed, "seek command not allowed for multi topic consumer")
+	return c.SeekWithCtx(context.Background(), msgID)
 }
 
 func (c *multiTopicConsumer) SeekByTime(time time.Time) error {
 	return newError(SeekFailed, "seek command not allowed for multi topic consumer")
 }
 
+// SeekWithCtx moves the subscription of the topic partition of a received message, the other topics and
+// partitions keep their position. The earliest and latest message ids move the subscriptions of all of them.
+func (c *multiTopicConsumer) SeekWithCtx(ctx context.Context, msgID MessageID) error {
+	mid, ok := toTrackingMessageID(msgID)
+	if !ok {
+		c.log.Warnf("invalid message id type %T", msgID)
+		return newError(InvalidMessage, "invalid message id")
+	}
+
+	if mid.isSentinel() {
+		for _, consumer := range c.consumers {
+			if err := consumer.SeekWithCtx(ctx, mid); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	pc, ok := mid.consumer.(*partitionConsumer)
+	if !ok {
+		c.log.Warnf("unable to seek to messageID=%+v can not determine topic", msgID)
+		return newError(SeekFailed, "unable to determine the topic of the message")
+	}
+
+	return pc.Seek(ctx, mid)
+}
+
+func (c *multiTopicConsumer) SeekByTimeWithCtx(ctx context.Context, time time.Time) error {
+	return c.SeekByTime(time)
+}
+
 // Name returns the name of consumer.
 func (c *multiTopicConsumer) Name() string {
 	return c.consumerName
 }
+
+func (c *multiTopicConsumer) connectionHolders() []connectionHolder {
+	holders := make([]connectionHolder, 0, len(c.consumers))
+	for _, consumer := range c.consumers {
+		holders = append(holders, consumer)
+	}
+	return holders
+}
+
+func (c *multiTopicConsumer) BrokerAddress() string {
+	return brokerAddresses(c.connectionHolders())
+}
+
+func (c *multiTopicConsumer) ConnectedSince() time.Time {
+	return connectedSince(c.connectionHolders())
+}
+
+func (c *multiTopicConsumer) LastDisconnectedTimestamp() time.Time {
+	return lastDisconnectedTimestamp(c.connectionHolders())
+}
+
+func (c *multiTopicConsumer) UnackedMessages() int {
+	n := 0
+	for _, consumer := range c.consumers {
+		n += consumer.UnackedMessages()
+	}
+	return n
+}
+
+func (c *multiTopicConsumer) OldestUnackedMessageAge() time.Duration {
+	var oldest time.Duration
+	for _, consumer := range c.consumers {
+		if age := consumer.OldestUnackedMessageAge(); age > oldest {
+			oldest = age
+		}
+	}
+	return oldest
+}
+
+func (c *multiTopicConsumer) ActiveConsumerChanges() int {
+	n := 0
+	for _, consumer := range c.consumers {
+		n += consumer.ActiveConsumerChanges()
+	}
+	return n
+}
+
+func (c *multiTopicConsumer) LastActiveConsumerChange() time.Time {
+	var last time.Time
+	for _, consumer := range c.consumers {
+		if t := consumer.LastActiveConsumerChange(); t.After(last) {
+			last = t
+		}
+	}
+	return last
+}
+
+func (c *multiTopicConsumer) Partitions() []string {
+	return consumersPartitions(c.consumers)
+}
+
+// consumersPartitions returns the sorted partitions of the consumers of several topics
+func consumersPartitions(consumers map[string]Consumer) []string {
+	var partitions []string
+	for _, consumer := range consumers {
+		partitions = append(partitions, consumer.Partitions()...)
+	}
+	sort.Strings(partitions)
+	return partitions
+}
@@ -18,11 +18,17 @@
 package pulsar
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/pulsar-client-go/pulsar/log"
+	"github.com/apache/pulsar-client-go/pulsar/pulsartest"
 )
 
 func TestMultiTopicConsumerReceive(t *testing.T) {
@@ -84,3 +90,141 @@ func TestMultiTopicConsumerReceive(t *testing.T) {
 	}
 	assert.Equal(t, receivedTopic1, receivedTopic2)
 }
+
+func TestMultiTopicConsumerTopicSchemas(t *testing.T) {
+	topic1 := newTopicName()
+	topic2 := newTopicName()
+
+	client, err := NewClient(ClientOptions{
+		URL: "pulsar://localhost:6650",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	consumer, err := client.Subscribe(ConsumerOptions{
+		Topics:           []string{topic1, topic2},
+		SubscriptionName: "multi-topic-schemas-sub",
+		Schema:           NewStringSchema(nil),
+		TopicSchemas:     map[string]Schema{topic2: NewInt64Schema(nil)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer consumer.Close()
+
+	p1, err := client.CreateProducer(ProducerOptions{Topic: topic1, Schema: NewStringSchema(nil)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p1.Close()
+	p2, err := client.CreateProducer(ProducerOptions{Topic: topic2, Schema: NewInt64Schema(nil)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p2.Close()
+
+	ctx := context.Background()
+	_, err = p1.Send(ctx, &ProducerMessage{Value: "hello"})
+	assert.Nil(t, err)
+	_, err = p2.Send(ctx, &ProducerMessage{Value: int64(42)})
+	assert.Nil(t, err)
+
+	var received []Message
+	for len(received) < 2 {
+		msg, err := consumer.Receive(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		received = append(received, msg)
+		if strings.HasSuffix(msg.Topic(), topic1) {
+			var s string
+			assert.Nil(t, msg.GetSchemaValue(&s))
+			assert.Equal(t, "hello", s)
+		} else {
+			var n int64
+			assert.Nil(t, msg.GetSchemaValue(&n))
+			assert.Equal(t, int64(42), n)
+		}
+	}
+
+	// the seek only rewinds the topic of the message
+	assert.Nil(t, consumer.Seek(received[0].ID()))
+	msg, err := consumer.Receive(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, received[0].Topic(), msg.Topic())
+
+	assert.Nil(t, consumer.Unsubscribe())
+}
+
+func TestMultiTopicConsumerSeekKeepsOtherTopicsOrder(t *testing.T) {
+	broker, err := pulsartest.NewBroker()
+	assert.Nil(t, err)
+	defer broker.Close()
+	client, err := NewClient(ClientOptions{URL: broker.URL()})
+	assert.Nil(t, err)
+	defer client.Close()
+
+	topics := []string{newTopicName(), newTopicName()}
+	consumer, err := client.Subscribe(ConsumerOptions{
+		Topics:           topics,
+		SubscriptionName: "my-sub",
+	})
+	assert.Nil(t, err)
+	defer consumer.Close()
+
+	ctx := context.Background()
+	for i, topic := range topics {
+		producer, err := client.CreateProducer(ProducerOptions{Topic: topic, DisableBatching: true})
+		assert.Nil(t, err)
+		for j := 0; j < 4; j++ {
+			_, err := producer.Send(ctx, &ProducerMessage{Payload: []byte(fmt.Sprintf("%d-%d", i, j))})
+			assert.Nil(t, err)
+		}
+		producer.Close()
+	}
+
+	received := map[string][]string{}
+	receive := func() Message {
+		timeoutCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+		msg, err := consumer.Receive(timeoutCtx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, topic := range topics {
+			if strings.HasSuffix(msg.Topic(), topic) {
+				received[topic] = append(received[topic], string(msg.Payload()))
+			}
+		}
+		consumer.Ack(msg)
+		return msg
+	}
+
+	// seek the first topic back to its first message while the messages of both topics wait in the channel
+	first := receive()
+	for string(first.Payload()) != "0-0" {
+		first = receive()
+	}
+	time.Sleep(100 * time.Millisecond)
+	assert.Nil(t, consumer.Seek(first.ID()))
+	received[topics[0]] = nil
+
+	// each topic is received in order, the messages of the first topic from the seek target
+	for len(received[topics[0]]) < 4 || len(received[topics[1]]) < 4 {
+		receive()
+	}
+	assert.Equal(t, []string{"0-0", "0-1", "0-2", "0-3"}, received[topics[0]])
+	assert.Equal(t, []string{"1-0", "1-1", "1-2", "1-3"}, received[topics[1]])
+}
+
+func TestMultiTopicConsumerSeekUnknownTopic(t *testing.T) {
+	c := &multiTopicConsumer{log: log.DefaultNopLogger()}
+	err := c.Seek(messageID{ledgerID: 1, entryID: 2})
+
+	var e *Error
+	if assert.True(t, errors.As(err, &e)) {
+		assert.Equal(t, SeekFailed, e.Result())
+	}
+}
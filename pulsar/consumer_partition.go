@@ -18,8 +18,9 @@
 package pulsar
 
 import (
+	"context"
 	"fmt"
-	"math"
+	"net/url"
 	"sync"
 	"time"
 
@@ -33,10 +34,6 @@ import (
 	"go.uber.org/atomic"
 )
 
-var (
-	lastestMessageID = LatestMessageID()
-)
-
 type consumerState int
 
 const (
@@ -75,28 +72,50 @@ const (
 
 const (
 	noMessageEntry = -1
+
+	// the maximum number of message ids sent in a single redelivery request
+	maxRedeliverUnacknowledged = 1000
+
+	// the default size of the queue of the requests handled by the events loop
+	defaultEventsQueueSize = 10
 )
 
 type partitionConsumerOpts struct {
-	topic                      string
-	consumerName               string
-	subscription               string
-	subscriptionType           SubscriptionType
-	subscriptionInitPos        SubscriptionInitialPosition
-	partitionIdx               int
-	receiverQueueSize          int
-	nackRedeliveryDelay        time.Duration
-	metadata                   map[string]string
-	replicateSubscriptionState bool
-	startMessageID             trackingMessageID
-	startMessageIDInclusive    bool
-	subscriptionMode           subscriptionMode
-	readCompacted              bool
-	disableForceTopicCreation  bool
-	interceptors               ConsumerInterceptors
-	maxReconnectToBroker       *uint
-	keySharedPolicy            *KeySharedPolicy
-	schema                     Schema
+	topic                       string
+	consumerName                string
+	subscription                string
+	subscriptionType            SubscriptionType
+	subscriptionInitPos         SubscriptionInitialPosition
+	resetSubscriptionPosition   bool
+	partitionIdx                int
+	receiverQueueSize           int
+	flowPermitStrategy          FlowPermitStrategy
+	nackRedeliveryDelay         time.Duration
+	ackTimeout                  time.Duration
+	ackTimeoutRedeliveryBackoff RedeliveryBackoff
+	metadata                    map[string]string
+	replicateSubscriptionState  bool
+	startMessageID              trackingMessageID
+	startMessageIDInclusive     bool
+	subscriptionMode            subscriptionMode
+	readCompacted               bool
+	disableForceTopicCreation   bool
+	interceptors                ConsumerInterceptors
+	maxReconnectToBroker        *uint
+	reconnectFailedHandler      func(topic string, err error)
+	backoffPolicy               func() BackoffPolicy
+	keySharedPolicy             *KeySharedPolicy
+	schema                      Schema
+	copyPayload                 bool
+	releasePayload              bool
+	messageFilter               func(Message) bool
+	payloadProcessor            MessagePayloadProcessor
+	payloadCodecs               []PayloadCodec
+	nackFilteredMessages        bool
+	autoAck                     bool
+	syncAck                     bool
+	eventsQueueSize             int
+	dedupWindow                 int
 }
 
 type partitionConsumer struct {
@@ -109,6 +128,11 @@ type partitionConsumer struct {
 
 	conn internal.Connection
 
+	// the address of the broker the consumer is connected to, as returned by the lookup
+	brokerAddr *url.URL
+	connectionTracker
+	activeConsumerTracker
+
 	topic        string
 	name         string
 	consumerID   uint64
@@ -117,24 +141,50 @@ type partitionConsumer struct {
 	// shared channel
 	messageCh chan ConsumerMessage
 
-	// the number of message slots available
-	availablePermits int32
+	// the permits of the messages dropped before reaching the dispatcher, eg. discarded or corrupted ones
+	releasedPermitsCh chan int32
+	// set while the messages pile up in the dispatch queue of the connection, the permits are then kept
+	withholdPermits atomic.Bool
 
-	// the size of the queue channel for buffering messages
-	queueSize       int32
+	// the number of messages the broker is allowed to push ahead, it is at most the queue channel size
+	queueSize       atomic.Int32
 	queueCh         chan []*message
 	startMessageID  trackingMessageID
 	lastDequeuedMsg trackingMessageID
+	// startLock guards the changes of the start message id against the messages being received
+	startLock sync.RWMutex
 
 	eventsCh             chan interface{}
 	connectedCh          chan struct{}
 	connectClosedCh      chan connectionClosed
 	closeCh              chan struct{}
+	dispatcherDoneCh     chan struct{}
 	clearQueueCh         chan func(id trackingMessageID)
 	clearMessageQueuesCh chan chan struct{}
 
 	nackTracker *negativeAcksTracker
 	dlq         *dlqRouter
+	// the ids of the last delivered messages, nil when the duplicates aren't dropped
+	dedup *dedupWindow
+
+	// the acks are queued without blocking the application and sent together by the events loop,
+	// ackNotifyCh wakes up the loop when the queue was empty
+	pendingAcksLock sync.Mutex
+	pendingAcks     []*pb.MessageIdData
+	ackNotifyCh     chan struct{}
+
+	// the transactions of the acks waiting for the broker response, which comes in the order of the acks
+	pendingTxnAcksLock sync.Mutex
+	pendingTxnAcks     []pendingTxnAck
+
+	// the messages received from the broker that the application didn't ack or nack yet
+	unacked unackedTracker
+
+	// the target of the last seek, until the messages pushed before the broker moved the cursor are dropped
+	seek seekState
+	// the number of times the queues were cleared by a seek, the messages received before are dropped when
+	// the application receives them from the channel shared with the other partitions
+	seeks atomic.Int64
 
 	log log.Logger
 
@@ -142,9 +192,13 @@ type partitionConsumer struct {
 	metrics              *internal.TopicMetrics
 }
 
-func newPartitionConsumer(parent Consumer, client *client, options *partitionConsumerOpts,
+func newPartitionConsumer(ctx context.Context, parent Consumer, client *client, options *partitionConsumerOpts,
 	messageCh chan ConsumerMessage, dlq *dlqRouter,
 	metrics *internal.TopicMetrics) (*partitionConsumer, error) {
+	eventsQueueSize := options.eventsQueueSize
+	if eventsQueueSize <= 0 {
+		eventsQueueSize = defaultEventsQueueSize
+	}
 	pc := &partitionConsumer{
 		parentConsumer:       parent,
 		client:               client,
@@ -153,30 +207,43 @@ func newPartitionConsumer(parent Consumer, client *client, options *partitionCon
 		name:                 options.consumerName,
 		consumerID:           client.rpcClient.NewConsumerID(),
 		partitionIdx:         int32(options.partitionIdx),
-		eventsCh:             make(chan interface{}, 10),
-		queueSize:            int32(options.receiverQueueSize),
+		eventsCh:             make(chan interface{}, eventsQueueSize),
+		ackNotifyCh:          make(chan struct{}, 1),
 		queueCh:              make(chan []*message, options.receiverQueueSize),
 		startMessageID:       options.startMessageID,
 		connectedCh:          make(chan struct{}),
 		messageCh:            messageCh,
 		connectClosedCh:      make(chan connectionClosed, 10),
 		closeCh:              make(chan struct{}),
+		dispatcherDoneCh:     make(chan struct{}),
+		releasedPermitsCh:    make(chan int32, 10),
 		clearQueueCh:         make(chan func(id trackingMessageID)),
 		clearMessageQueuesCh: make(chan chan struct{}),
 		compressionProviders: make(map[pb.CompressionType]compression.Provider),
 		dlq:                  dlq,
+		unacked:              unackedTracker{timeout: options.ackTimeout, backoff: options.ackTimeoutRedeliveryBackoff},
 		metrics:              metrics,
 	}
+	if pc.options.backoffPolicy == nil {
+		pc.options.backoffPolicy = client.backoffPolicy
+	}
+	pc.queueSize.Store(int32(options.receiverQueueSize))
 	pc.setConsumerState(consumerInit)
 	pc.log = client.log.SubLogger(log.Fields{
 		"name":         pc.name,
 		"topic":        options.topic,
 		"subscription": options.subscription,
 		"consumerID":   pc.consumerID,
+		"partition":    options.partitionIdx,
 	})
 	pc.nackTracker = newNegativeAcksTracker(pc, options.nackRedeliveryDelay, pc.log)
+	if options.dedupWindow > 0 {
+		pc.dedup = newDedupWindow(options.dedupWindow)
+	}
 
-	err := pc.grabConn()
+	err := client.retryOnRetriableErrors(ctx, func() error {
+		return pc.grabConn(ctx)
+	})
 	if err != nil {
 		pc.log.WithError(err).Error("Failed to create consumer")
 		pc.nackTracker.Close()
@@ -185,17 +252,17 @@ func newPartitionConsumer(parent Consumer, client *client, options *partitionCon
 	pc.log.Info("Created consumer")
 	pc.setConsumerState(consumerReady)
 
-	if pc.options.startMessageIDInclusive && pc.startMessageID.equal(lastestMessageID.(messageID)) {
+	if pc.options.startMessageIDInclusive && pc.startMessageID.equal(latestMessageID.(messageID)) {
 		msgID, err := pc.requestGetLastMessageID()
 		if err != nil {
 			pc.nackTracker.Close()
 			return nil, err
 		}
 		if msgID.entryID != noMessageEntry {
-			pc.startMessageID = msgID
+			pc.setStartMessageID(msgID)
 
 			// use the WithoutClear version because the dispatcher is not started yet
-			err = pc.requestSeekWithoutClear(msgID.messageID)
+			err = pc.requestSeekWithoutClear(ctx, msgID.messageID)
 			if err != nil {
 				pc.nackTracker.Close()
 				return nil, err
@@ -203,65 +270,81 @@ func newPartitionConsumer(parent Consumer, client *client, options *partitionCon
 		}
 	}
 
+	if pc.options.resetSubscriptionPosition {
+		if err := pc.resetToInitialPosition(ctx); err != nil {
+			pc.nackTracker.Close()
+			return nil, err
+		}
+	}
+
+	client.consumers.Add(pc.consumerID, pc)
 	go pc.dispatcher()
 
 	go pc.runEventsLoop()
 
+	if pc.options.ackTimeout > 0 {
+		go pc.runAckTimeoutLoop()
+	}
+
 	return pc, nil
 }
 
-func (pc *partitionConsumer) Unsubscribe() error {
-	if state := pc.getConsumerState(); state == consumerClosed || state == consumerClosing {
-		pc.log.WithField("state", state).Error("Failed to unsubscribe closing or closed consumer")
-		return nil
-	}
-
+func (pc *partitionConsumer) Unsubscribe(ctx context.Context) error {
 	req := &unsubscribeRequest{doneCh: make(chan struct{})}
-	pc.eventsCh <- req
-
-	// wait for the request to complete
-	<-req.doneCh
+	if err := pc.runRequest(ctx, req, req.doneCh); err != nil {
+		pc.log.WithField("state", pc.getConsumerState()).Error("Failed to unsubscribe closing or closed consumer")
+		return err
+	}
+	if req.err == nil {
+		// the parent closes the message channel once unsubscribed, the dispatcher mustn't push to it anymore
+		<-pc.dispatcherDoneCh
+	}
 	return req.err
 }
 
 func (pc *partitionConsumer) internalUnsubscribe(unsub *unsubscribeRequest) {
-	defer close(unsub.doneCh)
-
-	if state := pc.getConsumerState(); state == consumerClosed || state == consumerClosing {
-		pc.log.WithField("state", state).Error("Failed to unsubscribe closing or closed consumer")
+	if !pc.casConsumerState(consumerReady, consumerClosing) {
+		pc.log.WithField("state", pc.getConsumerState()).Error("Failed to unsubscribe closing or closed consumer")
+		unsub.err = ErrConsumerClosed
+		close(unsub.doneCh)
 		return
 	}
 
-	pc.setConsumerState(consumerClosing)
 	requestID := pc.client.rpcClient.NewRequestID()
 	cmdUnsubscribe := &pb.CommandUnsubscribe{
 		RequestId:  proto.Uint64(requestID),
 		ConsumerId: proto.Uint64(pc.consumerID),
 	}
-	_, err := pc.client.rpcClient.RequestOnCnx(pc.conn, requestID, pb.BaseCommand_UNSUBSCRIBE, cmdUnsubscribe)
+	_, err := pc.client.rpcClient.RequestOnCnx(context.Background(), pc.conn, requestID,
+		pb.BaseCommand_UNSUBSCRIBE, cmdUnsubscribe)
 	if err != nil {
 		pc.log.WithError(err).Error("Failed to unsubscribe consumer")
-		unsub.err = err
+		unsub.err = toClientError(err, "unsubscribe")
 		// Set the state to ready for closing the consumer
 		pc.setConsumerState(consumerReady)
 		// Should'nt remove the consumer handler
+		close(unsub.doneCh)
 		return
 	}
 
 	pc.conn.DeleteConsumeHandler(pc.consumerID)
+	pc.client.consumers.Del(pc.consumerID)
 	if pc.nackTracker != nil {
 		pc.nackTracker.Close()
 	}
 	pc.log.Infof("The consumer[%d] successfully unsubscribed", pc.consumerID)
 	pc.setConsumerState(consumerClosed)
+	pc.failPendingTxnAcks(ErrConsumerClosed)
+	// the request is done before the consumer is closed, so that Unsubscribe doesn't report it closed
+	close(unsub.doneCh)
+	close(pc.closeCh)
 }
 
 func (pc *partitionConsumer) getLastMessageID() (trackingMessageID, error) {
 	req := &getLastMsgIDRequest{doneCh: make(chan struct{})}
-	pc.eventsCh <- req
-
-	// wait for the request to complete
-	<-req.doneCh
+	if err := pc.runRequest(context.Background(), req, req.doneCh); err != nil {
+		return trackingMessageID{}, err
+	}
 	return req.msgID, req.err
 }
 
@@ -276,35 +359,324 @@ func (pc *partitionConsumer) requestGetLastMessageID() (trackingMessageID, error
 		RequestId:  proto.Uint64(requestID),
 		ConsumerId: proto.Uint64(pc.consumerID),
 	}
-	res, err := pc.client.rpcClient.RequestOnCnx(pc.conn, requestID,
+	res, err := pc.client.rpcClient.RequestOnCnx(context.Background(), pc.conn, requestID,
 		pb.BaseCommand_GET_LAST_MESSAGE_ID, cmdGetLastMessageID)
 	if err != nil {
 		pc.log.WithError(err).Error("Failed to get last message id")
-		return trackingMessageID{}, err
+		return trackingMessageID{}, toClientError(err, "get last message id")
 	}
 	id := res.Response.GetLastMessageIdResponse.GetLastMessageId()
 	return convertToMessageID(id), nil
 }
 
 func (pc *partitionConsumer) AckID(msgID trackingMessageID) {
+	pc.ackID(msgID, pc.options.syncAck)
+}
+
+// ackID acks the message, waiting for the ack to be handed to the connection when wait is set
+func (pc *partitionConsumer) ackID(msgID trackingMessageID, wait bool) {
+	if !msgID.Undefined() {
+		pc.untrackUnacked(msgID.messageID)
+	}
 	if !msgID.Undefined() && msgID.ack() {
 		pc.metrics.AcksCounter.Inc()
 		pc.metrics.ProcessingTime.Observe(float64(time.Now().UnixNano()-msgID.receivedTime.UnixNano()) / 1.0e9)
-		req := &ackRequest{
-			msgID: msgID,
+		pc.queueAck(msgID)
+
+		if wait {
+			pc.waitAcksFlushed()
+		}
+
+		pc.options.interceptors.OnAcknowledge(pc.parentConsumer, msgID)
+	}
+}
+
+// AckIDList acks the messages together, it returns once their acks were handed to the connection in a single
+// command
+func (pc *partitionConsumer) AckIDList(msgIDs []trackingMessageID) error {
+	for _, msgID := range msgIDs {
+		if msgID.Undefined() {
+			return newError(InvalidMessage, "invalid message id")
 		}
-		pc.eventsCh <- req
+	}
+
+	// the events loop untracks and acks the messages, nothing changes when the consumer is closed first
+	req := &ackListRequest{
+		doneCh: make(chan struct{}),
+		msgIDs: msgIDs,
+	}
+	if err := pc.runRequest(context.Background(), req, req.doneCh); err != nil {
+		return err
+	}
 
+	for _, msgID := range req.acked {
+		pc.metrics.AcksCounter.Inc()
+		pc.metrics.ProcessingTime.Observe(float64(time.Now().UnixNano()-msgID.receivedTime.UnixNano()) / 1.0e9)
 		pc.options.interceptors.OnAcknowledge(pc.parentConsumer, msgID)
 	}
+	return nil
+}
+
+// internalAckList acks the messages of the request with the acks pending to be flushed
+func (pc *partitionConsumer) internalAckList(req *ackListRequest) {
+	defer close(req.doneCh)
+	for _, msgID := range req.msgIDs {
+		pc.untrackUnacked(msgID.messageID)
+		if msgID.ack() {
+			req.acked = append(req.acked, msgID)
+		}
+	}
+	pc.queueAck(req.acked...)
+	pc.flushAcks()
+}
+
+// queueAck adds the message ids to the acks sent with the next flush of the events loop, without blocking
+func (pc *partitionConsumer) queueAck(msgIDs ...trackingMessageID) {
+	pc.pendingAcksLock.Lock()
+	for _, msgID := range msgIDs {
+		pc.pendingAcks = append(pc.pendingAcks, &pb.MessageIdData{
+			LedgerId: proto.Uint64(uint64(msgID.ledgerID)),
+			EntryId:  proto.Uint64(uint64(msgID.entryID)),
+		})
+	}
+	pc.pendingAcksLock.Unlock()
+
+	select {
+	case pc.ackNotifyCh <- struct{}{}:
+	default:
+		// a flush is already pending
+	}
+}
+
+// waitAcksFlushed blocks until the acks queued so far were handed to the connection
+func (pc *partitionConsumer) waitAcksFlushed() {
+	req := &flushAcksRequest{doneCh: make(chan struct{})}
+	_ = pc.runRequest(context.Background(), req, req.doneCh)
+}
+
+// flushAcks sends all the queued acks in a single command
+func (pc *partitionConsumer) flushAcks() {
+	pc.pendingAcksLock.Lock()
+	messageIDs := pc.pendingAcks
+	pc.pendingAcks = nil
+	pc.pendingAcksLock.Unlock()
+
+	if len(messageIDs) == 0 {
+		return
+	}
+	cmdAck := &pb.CommandAck{
+		ConsumerId: proto.Uint64(pc.consumerID),
+		MessageId:  messageIDs,
+		AckType:    pb.CommandAck_Individual.Enum(),
+	}
+	if err := pc.client.rpcClient.RequestOnCnxNoWait(pc.conn, pb.BaseCommand_ACK, cmdAck); err != nil {
+		pc.log.WithError(err).Warnf("Failed to send %d acks", len(messageIDs))
+	}
+}
+
+// AckIDCumulative acks all the messages of the partition up to and including the given one,
+// it returns once the ack was handed to the connection
+func (pc *partitionConsumer) AckIDCumulative(msgID trackingMessageID) error {
+	if pc.options.subscriptionType == Shared || pc.options.subscriptionType == KeyShared {
+		return ErrCumulativeAckNotAllowed
+	}
+	if msgID.Undefined() {
+		return newError(InvalidMessage, "invalid message id")
+	}
+
+	req := &ackCumulativeRequest{
+		doneCh: make(chan struct{}),
+		msgID:  msgID,
+	}
+	if err := pc.runRequest(context.Background(), req, req.doneCh); err != nil {
+		return err
+	}
+	if req.err != nil {
+		return req.err
+	}
+
+	pc.metrics.UnackedMessages.Sub(float64(pc.unacked.removeUpTo(msgID.messageID)))
+	pc.metrics.AcksCounter.Inc()
+	pc.metrics.ProcessingTime.Observe(float64(time.Now().UnixNano()-msgID.receivedTime.UnixNano()) / 1.0e9)
+	pc.options.interceptors.OnAcknowledge(pc.parentConsumer, msgID)
+	return nil
+}
+
+func (pc *partitionConsumer) internalAckCumulative(req *ackCumulativeRequest) {
+	defer close(req.doneCh)
+
+	// the individual acks queued before must not reach the broker after the cumulative ack
+	pc.flushAcks()
+
+	entryID := req.msgID.entryID
+	if tracker := req.msgID.tracker; tracker != nil {
+		for i := 0; i <= int(req.msgID.batchIdx); i++ {
+			tracker.ack(i)
+		}
+		// the entry is only acked with the whole batch, the previous entries are acked meanwhile
+		if !tracker.completed() {
+			entryID--
+		}
+	}
+	if entryID < 0 {
+		return
+	}
+
+	cmdAck := &pb.CommandAck{
+		ConsumerId: proto.Uint64(pc.consumerID),
+		MessageId: []*pb.MessageIdData{{
+			LedgerId: proto.Uint64(uint64(req.msgID.ledgerID)),
+			EntryId:  proto.Uint64(uint64(entryID)),
+		}},
+		AckType: pb.CommandAck_Cumulative.Enum(),
+	}
+	if err := pc.client.rpcClient.RequestOnCnxNoWait(pc.conn, pb.BaseCommand_ACK, cmdAck); err != nil {
+		pc.log.WithError(err).Warn("Failed to send cumulative ack")
+		req.err = toClientError(err, "cumulative ack")
+	}
+}
+
+// AckIDWithTxn registers the subscription with the transaction and sends the ack carrying the
+// transaction id, the ack of a batched message is only sent once the whole batch is acked
+func (pc *partitionConsumer) AckIDWithTxn(msgID trackingMessageID, txn Transaction) error {
+	t, ok := txn.(*transaction)
+	if !ok {
+		return newError(InvalidConfiguration, "the transaction was not created by the client")
+	}
+	if msgID.Undefined() {
+		return newError(InvalidMessage, "invalid message id")
+	}
+	if pc.isClosed() {
+		return ErrConsumerClosed
+	}
+	if err := t.registerSubscription(context.Background(), pc.topic, pc.options.subscription); err != nil {
+		return err
+	}
+	if err := t.registerOp(); err != nil {
+		return err
+	}
+
+	if !msgID.ack() {
+		// the batch is acked with its last message
+		pc.untrackUnacked(msgID.messageID)
+		t.endOp(nil)
+		return nil
+	}
+
+	req := &ackRequest{
+		doneCh: make(chan struct{}),
+		msgID:  msgID,
+		txn:    t,
+	}
+	if err := pc.runRequest(context.Background(), req, req.doneCh); err != nil {
+		// the events loop exited without sending the ack, the broker won't answer it
+		t.endOp(err)
+		return err
+	}
+
+	pc.metrics.AcksCounter.Inc()
+	pc.metrics.ProcessingTime.Observe(float64(time.Now().UnixNano()-msgID.receivedTime.UnixNano()) / 1.0e9)
+	pc.options.interceptors.OnAcknowledge(pc.parentConsumer, msgID)
+	return nil
 }
 
 func (pc *partitionConsumer) NackID(msgID trackingMessageID) {
+	pc.untrackUnacked(msgID.messageID)
+	pc.forgetDelivered(msgID.messageID)
 	pc.nackTracker.Add(msgID.messageID)
 	pc.metrics.NacksCounter.Inc()
 }
 
+// trackUnacked records the message as received and not acked yet
+func (pc *partitionConsumer) trackUnacked(msg *message) {
+	msgID := msg.msgID.(trackingMessageID)
+	if pc.unacked.add(msgID.messageID, msgID.receivedTime, msg.redeliveryCount) {
+		pc.metrics.UnackedMessages.Inc()
+	}
+}
+
+func (pc *partitionConsumer) untrackUnacked(msgID messageID) {
+	if pc.unacked.remove(msgID) {
+		pc.metrics.UnackedMessages.Dec()
+	}
+}
+
+// untrackDropped stops tracking the messages dropped from the queues, the broker redelivers them
+func (pc *partitionConsumer) untrackDropped(messages []*message) {
+	for _, m := range messages {
+		pc.untrackUnacked(m.msgID.(trackingMessageID).messageID)
+		pc.forgetDelivered(m.msgID.(trackingMessageID).messageID)
+	}
+}
+
+// forgetDelivered removes the messages from the deduplication window when the broker is to deliver them again
+func (pc *partitionConsumer) forgetDelivered(msgIDs ...messageID) {
+	if pc.dedup != nil {
+		pc.dedup.forget(msgIDs...)
+	}
+}
+
+func (pc *partitionConsumer) clearUnacked() {
+	pc.metrics.UnackedMessages.Sub(float64(pc.unacked.clear()))
+}
+
+// UnackedMessages returns the number of messages received from the broker and not acked or nacked yet
+func (pc *partitionConsumer) UnackedMessages() int {
+	return pc.unacked.size()
+}
+
+// OldestUnackedMessageAge returns for how long the oldest unacked message has been received
+func (pc *partitionConsumer) OldestUnackedMessageAge() time.Duration {
+	return pc.unacked.oldestAge(time.Now())
+}
+
+// ExtendAckDeadline postpones until d from now the redelivery of the unacked message after the ack timeout
+func (pc *partitionConsumer) ExtendAckDeadline(msgID trackingMessageID, d time.Duration) error {
+	if pc.options.ackTimeout <= 0 {
+		return newError(InvalidConfiguration, "the ack timeout of the consumer is disabled")
+	}
+	if d <= 0 {
+		return newError(InvalidConfiguration, "the ack deadline extension must be positive")
+	}
+	if !pc.unacked.extend(msgID.messageID, time.Now().Add(d)) {
+		return newError(InvalidMessage, "the message is not waiting for an ack")
+	}
+	return nil
+}
+
+// runAckTimeoutLoop asks the broker to redeliver the messages that weren't acked before their deadline
+func (pc *partitionConsumer) runAckTimeoutLoop() {
+	tick := pc.options.ackTimeout
+	if tick > time.Second {
+		tick = time.Second
+	}
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-pc.closeCh:
+			return
+		case now := <-ticker.C:
+			msgIds := pc.unacked.expired(now)
+			if len(msgIds) == 0 {
+				continue
+			}
+			pc.metrics.UnackedMessages.Sub(float64(len(msgIds)))
+			pc.forgetDelivered(msgIds...)
+			pc.log.Debugf("Redelivering %d messages after the ack timeout", len(msgIds))
+			pc.countEventsQueueFull()
+			select {
+			case pc.eventsCh <- &redeliveryRequest{msgIds}:
+			case <-pc.closeCh:
+				return
+			}
+		}
+	}
+}
+
 func (pc *partitionConsumer) Redeliver(msgIds []messageID) {
+	pc.countEventsQueueFull()
 	pc.eventsCh <- &redeliveryRequest{msgIds}
 
 	iMsgIds := make([]MessageID, len(msgIds))
@@ -315,22 +687,50 @@ func (pc *partitionConsumer) Redeliver(msgIds []messageID) {
 }
 
 func (pc *partitionConsumer) internalRedeliver(req *redeliveryRequest) {
-	msgIds := req.msgIds
-	pc.log.Debug("Request redelivery after negative ack for messages", msgIds)
-
-	msgIDDataList := make([]*pb.MessageIdData, len(msgIds))
-	for i := 0; i < len(msgIds); i++ {
-		msgIDDataList[i] = &pb.MessageIdData{
-			LedgerId: proto.Uint64(uint64(msgIds[i].ledgerID)),
-			EntryId:  proto.Uint64(uint64(msgIds[i].entryID)),
-		}
+	pc.log.Debug("Request redelivery of messages", req.msgIds)
+
+	for _, cmd := range redeliverCommands(pc.consumerID, req.msgIds) {
+		pc.client.rpcClient.RequestOnCnxNoWait(pc.conn, pb.BaseCommand_REDELIVER_UNACKNOWLEDGED_MESSAGES, cmd)
 	}
+}
 
-	pc.client.rpcClient.RequestOnCnxNoWait(pc.conn,
-		pb.BaseCommand_REDELIVER_UNACKNOWLEDGED_MESSAGES, &pb.CommandRedeliverUnacknowledgedMessages{
-			ConsumerId: proto.Uint64(pc.consumerID),
+// redeliverCommands builds the requests to redeliver exactly the entries of the given message ids,
+// the messages of a batch share their entry which is only requested once
+func redeliverCommands(consumerID uint64, msgIds []messageID) []*pb.CommandRedeliverUnacknowledgedMessages {
+	type entry struct {
+		ledgerID int64
+		entryID  int64
+	}
+	requested := make(map[entry]struct{}, len(msgIds))
+
+	var cmds []*pb.CommandRedeliverUnacknowledgedMessages
+	var msgIDDataList []*pb.MessageIdData
+	for _, id := range msgIds {
+		e := entry{ledgerID: id.ledgerID, entryID: id.entryID}
+		if _, ok := requested[e]; ok {
+			continue
+		}
+		requested[e] = struct{}{}
+
+		msgIDDataList = append(msgIDDataList, &pb.MessageIdData{
+			LedgerId: proto.Uint64(uint64(id.ledgerID)),
+			EntryId:  proto.Uint64(uint64(id.entryID)),
+		})
+		if len(msgIDDataList) == maxRedeliverUnacknowledged {
+			cmds = append(cmds, &pb.CommandRedeliverUnacknowledgedMessages{
+				ConsumerId: proto.Uint64(consumerID),
+				MessageIds: msgIDDataList,
+			})
+			msgIDDataList = nil
+		}
+	}
+	if len(msgIDDataList) > 0 {
+		cmds = append(cmds, &pb.CommandRedeliverUnacknowledgedMessages{
+			ConsumerId: proto.Uint64(consumerID),
 			MessageIds: msgIDDataList,
 		})
+	}
+	return cmds
 }
 
 func (pc *partitionConsumer) getConsumerState() consumerState {
@@ -341,9 +741,65 @@ func (pc *partitionConsumer) setConsumerState(state consumerState) {
 	pc.state.Store(int32(state))
 }
 
-func (pc *partitionConsumer) Close() {
+// casConsumerState moves the consumer to the new state only if it is in the expected one,
+// so that only one of concurrent transitions out of a state succeeds
+func (pc *partitionConsumer) casConsumerState(expected, state consumerState) bool {
+	return pc.state.CAS(int32(expected), int32(state))
+}
 
-	if pc.getConsumerState() != consumerReady {
+func (pc *partitionConsumer) isClosed() bool {
+	state := pc.getConsumerState()
+	return state == consumerClosing || state == consumerClosed
+}
+
+// runRequest hands the request to the events loop and waits until it was processed, it fails with
+// ErrConsumerClosed instead of blocking when the consumer is closed before the request gets processed.
+// When the context is done first, the context error is returned while the request is still processed.
+func (pc *partitionConsumer) runRequest(ctx context.Context, req interface{}, doneCh chan struct{}) error {
+	if pc.isClosed() {
+		return ErrConsumerClosed
+	}
+
+	pc.countEventsQueueFull()
+	select {
+	case pc.eventsCh <- req:
+	case <-pc.closeCh:
+		return ErrConsumerClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case <-doneCh:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-pc.closeCh:
+		// the request may have been processed just before the loop exited
+		select {
+		case <-doneCh:
+			return nil
+		default:
+			return ErrConsumerClosed
+		}
+	}
+}
+
+// countEventsQueueFull counts the requests about to wait because the events queue is full
+func (pc *partitionConsumer) countEventsQueueFull() {
+	if len(pc.eventsCh) == cap(pc.eventsCh) {
+		pc.metrics.EventsQueueFull.Inc()
+	}
+}
+
+func (pc *partitionConsumer) Close() {
+	// only the first call closes the consumer, the state is moved to closing right away
+	// so that the requests made from now on are rejected
+	if !pc.casConsumerState(consumerReady, consumerClosing) {
+		// the consumer may have been unsubscribed, no message is pushed to the message channel once it is closed
+		if pc.getConsumerState() == consumerClosed {
+			<-pc.dispatcherDoneCh
+		}
 		return
 	}
 
@@ -352,79 +808,126 @@ func (pc *partitionConsumer) Close() {
 
 	// wait for request to finish
 	<-req.doneCh
+
+	// no message is pushed to the message channel once the consumer is closed
+	<-pc.dispatcherDoneCh
 }
 
-func (pc *partitionConsumer) Seek(msgID trackingMessageID) error {
+func (pc *partitionConsumer) Seek(ctx context.Context, msgID trackingMessageID) error {
 	req := &seekRequest{
+		ctx:    ctx,
 		doneCh: make(chan struct{}),
 		msgID:  msgID,
 	}
-	pc.eventsCh <- req
-
-	// wait for the request to complete
-	<-req.doneCh
+	if err := pc.runRequest(ctx, req, req.doneCh); err != nil {
+		return err
+	}
 	return req.err
 }
 
 func (pc *partitionConsumer) internalSeek(seek *seekRequest) {
 	defer close(seek.doneCh)
-	seek.err = pc.requestSeek(seek.msgID.messageID)
+	if seek.msgID.isSentinel() {
+		seek.err = pc.requestSeekSentinel(seek.ctx, seek.msgID.messageID)
+		return
+	}
+	seek.err = pc.requestSeek(seek.ctx, seek.msgID.messageID)
 }
-func (pc *partitionConsumer) requestSeek(msgID messageID) error {
-	if err := pc.requestSeekWithoutClear(msgID); err != nil {
+
+// requestSeekSentinel moves the cursor to the earliest or the latest position of the partition. The start of a
+// reader follows, so that it neither drops the messages preceding its former start nor resumes from its last
+// message on reconnection, the latest position being resolved to the last message id of the partition.
+func (pc *partitionConsumer) requestSeekSentinel(ctx context.Context, target messageID) error {
+	start := trackingMessageID{messageID: target}
+	if !pc.startMessageID.Undefined() && target.equal(latestMessageID.(messageID)) {
+		lastMsgID, err := pc.requestGetLastMessageID()
+		if err != nil {
+			return err
+		}
+		start = lastMsgID
+	}
+
+	if err := pc.requestSeek(ctx, target); err != nil {
 		return err
 	}
-	pc.clearMessageChannels()
+
+	// the consumers of a durable subscription have no start, the broker keeps their position
+	if !pc.startMessageID.Undefined() {
+		pc.setStartMessageID(start)
+		if target.equal(latestMessageID.(messageID)) {
+			pc.lastDequeuedMsg = start
+		} else {
+			pc.lastDequeuedMsg = trackingMessageID{}
+		}
+	}
 	return nil
 }
 
-func (pc *partitionConsumer) requestSeekWithoutClear(msgID messageID) error {
-	state := pc.getConsumerState()
-	if state == consumerClosing || state == consumerClosed {
-		pc.log.WithField("state", state).Error("Consumer is closing or has closed")
-		return nil
+// requestSeek moves the cursor of the subscription and, once the broker succeeded, drops the messages it pushed
+// before: the queued ones right away and the ones preceding the target still in flight as they are received
+func (pc *partitionConsumer) requestSeek(ctx context.Context, msgID messageID) error {
+	if err := pc.requestSeekWithoutClear(ctx, msgID); err != nil {
+		return err
 	}
+	pc.seek.start(msgID)
+	pc.clearMessageChannels()
+	return nil
+}
 
-	id := &pb.MessageIdData{}
-	err := proto.Unmarshal(msgID.Serialize(), id)
-	if err != nil {
-		pc.log.WithError(err).Errorf("deserialize message id error: %s", err.Error())
-		return err
+func (pc *partitionConsumer) requestSeekWithoutClear(ctx context.Context, msgID messageID) error {
+	if state := pc.getConsumerState(); state == consumerClosing || state == consumerClosed {
+		pc.log.WithField("state", state).Error("Consumer is closing or has closed")
+		return ErrConsumerClosed
 	}
 
 	requestID := pc.client.rpcClient.NewRequestID()
 	cmdSeek := &pb.CommandSeek{
 		ConsumerId: proto.Uint64(pc.consumerID),
 		RequestId:  proto.Uint64(requestID),
-		MessageId:  id,
+		MessageId:  msgID.toMessageIDData(),
 	}
 
-	_, err = pc.client.rpcClient.RequestOnCnx(pc.conn, requestID, pb.BaseCommand_SEEK, cmdSeek)
+	_, err := pc.client.rpcClient.RequestOnCnx(ctx, pc.conn, requestID, pb.BaseCommand_SEEK, cmdSeek)
 	if err != nil {
 		pc.log.WithError(err).Error("Failed to reset to message id")
-		return err
+		return toClientError(err, "seek")
+	}
+	return nil
+}
+
+// resetToInitialPosition moves the cursor of the subscription to its initial position, which the broker
+// ignores when the subscription already exists
+func (pc *partitionConsumer) resetToInitialPosition(ctx context.Context) error {
+	initialPosition := latestMessageID
+	if pc.options.subscriptionInitPos == SubscriptionPositionEarliest {
+		initialPosition = earliestMessageID
+	}
+
+	// use the WithoutClear version because the dispatcher is not started yet
+	if err := pc.requestSeekWithoutClear(ctx, initialPosition.(messageID)); err != nil {
+		return wrapError(SeekFailed, "failed to reset the subscription to its initial position", err)
 	}
 	return nil
 }
 
-func (pc *partitionConsumer) SeekByTime(time time.Time) error {
+func (pc *partitionConsumer) SeekByTime(ctx context.Context, time time.Time) error {
 	req := &seekByTimeRequest{
+		ctx:         ctx,
 		doneCh:      make(chan struct{}),
 		publishTime: time,
 	}
-	pc.eventsCh <- req
-
-	// wait for the request to complete
-	<-req.doneCh
+	if err := pc.runRequest(ctx, req, req.doneCh); err != nil {
+		return err
+	}
 	return req.err
 }
 
 func (pc *partitionConsumer) internalSeekByTime(seek *seekByTimeRequest) {
 	defer close(seek.doneCh)
 
-	state := pc.getConsumerState()
-	if state == consumerClosing || state == consumerClosed {
-		pc.log.WithField("state", pc.state).Error("Consumer is closing or has closed")
+	if state := pc.getConsumerState(); state == consumerClosing || state == consumerClosed {
+		pc.log.WithField("state", state).Error("Consumer is closing or has closed")
+		seek.err = ErrConsumerClosed
 		return
 	}
 
@@ -435,7 +938,7 @@ func (pc *partitionConsumer) internalSeekByTime(seek *seekByTimeRequest) {
 		MessagePublishTime: proto.Uint64(uint64(seek.publishTime.UnixNano() / int64(time.Millisecond))),
 	}
 
-	_, err := pc.client.rpcClient.RequestOnCnx(pc.conn, requestID, pb.BaseCommand_SEEK, cmdSeek)
+	_, err := pc.client.rpcClient.RequestOnCnx(seek.ctx, pc.conn, requestID, pb.BaseCommand_SEEK, cmdSeek)
 	if err != nil {
 		pc.log.WithError(err).Error("Failed to reset to message publish time")
 		seek.err = err
@@ -451,6 +954,7 @@ func (pc *partitionConsumer) clearMessageChannels() {
 }
 
 func (pc *partitionConsumer) internalAck(req *ackRequest) {
+	defer close(req.doneCh)
 	msgID := req.msgID
 
 	messageIDs := make([]*pb.MessageIdData, 1)
@@ -464,114 +968,311 @@ func (pc *partitionConsumer) internalAck(req *ackRequest) {
 		MessageId:  messageIDs,
 		AckType:    pb.CommandAck_Individual.Enum(),
 	}
+	if req.txn != nil {
+		cmdAck.TxnidMostBits = proto.Uint64(req.txn.id.MostSigBits)
+		cmdAck.TxnidLeastBits = proto.Uint64(req.txn.id.LeastSigBits)
+	}
+
+	if req.txn == nil {
+		pc.client.rpcClient.RequestOnCnxNoWait(pc.conn, pb.BaseCommand_ACK, cmdAck)
+		return
+	}
+
+	// the transaction waits for the broker to answer the ack
+	pc.pendingTxnAcksLock.Lock()
+	pc.pendingTxnAcks = append(pc.pendingTxnAcks, pendingTxnAck{txn: req.txn, msgID: msgID})
+	pc.pendingTxnAcksLock.Unlock()
+	if err := pc.client.rpcClient.RequestOnCnxNoWait(pc.conn, pb.BaseCommand_ACK, cmdAck); err != nil {
+		if ack, ok := pc.popPendingTxnAck(req.txn.id); ok {
+			ack.txn.endOp(toClientError(err, "ack"))
+		}
+	}
+}
+
+// ActiveConsumerChange records that the consumer became, or stopped being, the active one of its subscription
+func (pc *partitionConsumer) ActiveConsumerChange(isActive bool) {
+	if !pc.activeConsumerTracker.changed(isActive) {
+		return
+	}
+	pc.metrics.ActiveChanges.Inc()
+	pc.log.Infof("The consumer is active=%t on the subscription", isActive)
+}
+
+// WithholdPermits stops, or resumes, giving permits to the broker while the messages already pushed wait in the
+// dispatch queue of the connection
+func (pc *partitionConsumer) WithholdPermits(withhold bool) {
+	pc.withholdPermits.Store(withhold)
+	pc.log.Debugf("The consumer withholds its permits=%t", withhold)
+	if !withhold {
+		// the dispatcher gives the permits it kept back, unless it is busy and will do so on the next release
+		select {
+		case pc.releasedPermitsCh <- 0:
+		default:
+		}
+	}
+}
+
+// AckResponse completes the ack of a transaction, or reports the failure of the ack to the broker
+func (pc *partitionConsumer) AckResponse(response *pb.CommandAckResponse) {
+	var err error
+	if response.Error != nil {
+		pc.metrics.AcksFailed.Inc()
+		err = toClientError(&internal.ServerError{Code: response.GetError(), Message: response.GetMessage()}, "ack")
+		pc.log.WithError(err).Warn("The broker failed to ack the messages")
+	}
+
+	txnID := internal.TxnID{MostSigBits: response.GetTxnidMostBits(), LeastSigBits: response.GetTxnidLeastBits()}
+	if ack, ok := pc.popPendingTxnAck(txnID); ok {
+		if err == nil {
+			// the message is acked, it mustn't be redelivered on the ack timeout
+			pc.untrackUnacked(ack.msgID.messageID)
+		}
+		ack.txn.endOp(err)
+	}
+}
 
-	pc.client.rpcClient.RequestOnCnxNoWait(pc.conn, pb.BaseCommand_ACK, cmdAck)
+// popPendingTxnAck removes the oldest ack of the transaction waiting for the broker response
+func (pc *partitionConsumer) popPendingTxnAck(txnID internal.TxnID) (pendingTxnAck, bool) {
+	pc.pendingTxnAcksLock.Lock()
+	defer pc.pendingTxnAcksLock.Unlock()
+	for i, ack := range pc.pendingTxnAcks {
+		if ack.txn.id == txnID {
+			pc.pendingTxnAcks = append(pc.pendingTxnAcks[:i], pc.pendingTxnAcks[i+1:]...)
+			return ack, true
+		}
+	}
+	return pendingTxnAck{}, false
+}
+
+// failPendingTxnAcks fails the acks the broker won't answer anymore, once the consumer lost its connection
+func (pc *partitionConsumer) failPendingTxnAcks(err error) {
+	pc.pendingTxnAcksLock.Lock()
+	acks := pc.pendingTxnAcks
+	pc.pendingTxnAcks = nil
+	pc.pendingTxnAcksLock.Unlock()
+	for _, ack := range acks {
+		ack.txn.endOp(err)
+	}
+}
+
+// messageSchema returns the schema decoding the messages published with the given schema version
+func (pc *partitionConsumer) messageSchema(schemaVersion []byte) Schema {
+	if s, ok := pc.options.schema.(*AutoConsumeSchema); ok {
+		return s.atVersion(pc.topic, schemaVersion, pc.client.getSchema)
+	}
+	return pc.options.schema
 }
 
 func (pc *partitionConsumer) MessageReceived(response *pb.CommandMessage, headersAndPayload internal.Buffer) error {
 	pbMsgID := response.GetMessageId()
 
-	reader := internal.NewMessageReader(headersAndPayload)
+	reader := internal.AcquireMessageReader(headersAndPayload)
+	defer reader.Release()
 	msgMeta, err := reader.ReadMessageMetadata()
 	if err != nil {
 		pc.discardCorruptedMessage(pbMsgID, pb.CommandAck_ChecksumMismatch)
+		pc.releasePermits(1)
+		internal.PutReadBuffer(headersAndPayload)
 		return err
 	}
 
+	numMsgs := 1
+	if msgMeta.NumMessagesInBatch != nil {
+		numMsgs = int(msgMeta.GetNumMessagesInBatch())
+	}
+
 	uncompressedHeadersAndPayload, err := pc.Decompress(msgMeta, headersAndPayload)
 	if err != nil {
 		pc.discardCorruptedMessage(pbMsgID, pb.CommandAck_DecompressionError)
+		pc.releasePermits(int32(numMsgs))
+		internal.PutReadBuffer(headersAndPayload)
 		return err
 	}
 
+	// the payloads are only slices of the frame buffer when the messages weren't compressed,
+	// the buffer can be reused right away otherwise or when the payloads are copied
+	if uncompressedHeadersAndPayload != headersAndPayload {
+		internal.PutReadBuffer(headersAndPayload)
+	} else if pc.options.copyPayload {
+		defer internal.PutReadBuffer(headersAndPayload)
+	}
+
+	var payload *sharedPayload
+	if pc.options.releasePayload {
+		// each message delivered holds a reference on the buffer, the one held while the batch is processed is
+		// released on return
+		payload = &sharedPayload{buffer: uncompressedHeadersAndPayload, refs: 1}
+		defer payload.release()
+	}
+
 	// Reset the reader on the uncompressed buffer
 	reader.ResetBuffer(uncompressedHeadersAndPayload)
 
-	numMsgs := 1
-	if msgMeta.NumMessagesInBatch != nil {
-		numMsgs = int(msgMeta.GetNumMessagesInBatch())
-	}
-	messages := make([]*message, 0)
-	var ackTracker *ackTracker
 	// are there multiple messages in this batch?
+	var ackTracker *ackTracker
 	if numMsgs > 1 {
 		ackTracker = newAckTracker(numMsgs)
 	}
+	ctx := &payloadContext{
+		pc:          pc,
+		reader:      reader,
+		response:    response,
+		msgMeta:     msgMeta,
+		schema:      pc.messageSchema(msgMeta.GetSchemaVersion()),
+		numMessages: numMsgs,
+		ackTracker:  ackTracker,
+		payload:     payload,
+	}
 
 	pc.metrics.MessagesReceived.Add(float64(numMsgs))
-	pc.metrics.PrefetchedMessages.Add(float64(numMsgs))
 
-	for i := 0; i < numMsgs; i++ {
-		smm, payload, err := reader.ReadMessage()
-		if err != nil {
-			pc.discardCorruptedMessage(pbMsgID, pb.CommandAck_BatchDeSerializeError)
-			return err
+	messages := make([]*message, 0)
+	var prefetchedBytes int
+	consume := func(m Message) {
+		msg, ok := m.(*message)
+		if !ok || !ctx.owns(msg) {
+			pc.log.Warn("Dropping a message which wasn't built by the payload context")
+			return
+		}
+		if !ctx.markConsumed(msg) {
+			pc.log.WithField("index", msg.msgID.(trackingMessageID).batchIdx).
+				Warn("Dropping a message of the entry consumed twice")
+			msg.Release()
+			return
 		}
+		if pc.dispatchable(msg) {
+			messages = append(messages, msg)
+			prefetchedBytes += len(msg.payLoad)
+		} else {
+			msg.Release()
+		}
+	}
 
-		pc.metrics.BytesReceived.Add(float64(len(payload)))
-		pc.metrics.PrefetchedBytes.Add(float64(len(payload)))
+	if pc.options.payloadProcessor != nil {
+		err = pc.options.payloadProcessor.Process(uncompressedHeadersAndPayload.ReadableSlice(), ctx, consume)
+	} else {
+		err = ctx.DefaultProcess(consume)
+	}
+	if err != nil {
+		pc.discardCorruptedMessage(pbMsgID, pb.CommandAck_BatchDeSerializeError)
+		// none of the messages of the batch reach the dispatcher
+		pc.untrackDropped(messages)
+		for _, msg := range messages {
+			msg.Release()
+		}
+		pc.releasePermits(int32(numMsgs))
+		return err
+	}
+	ctx.ackNotConsumed()
+
+	pc.metrics.PrefetchedMessages.Add(float64(len(messages)))
+	pc.metrics.PrefetchedBytes.Add(float64(prefetchedBytes))
+	if discarded := numMsgs - len(messages); discarded > 0 {
+		pc.releasePermits(int32(discarded))
+	}
 
-		msgID := newTrackingMessageID(
-			int64(pbMsgID.GetLedgerId()),
-			int64(pbMsgID.GetEntryId()),
-			int32(i),
-			pc.partitionIdx,
-			ackTracker)
+	// send messages to the dispatcher
+	pc.queueCh <- messages
+	return nil
+}
 
-		if pc.messageShouldBeDiscarded(msgID) {
-			pc.AckID(msgID)
-			continue
+// dispatchable decodes and filters the received message and tracks it when it is to be queued for the
+// application, the messages before the start message or rejected by the filter are acknowledged right away.
+// It runs on the connection goroutine, the acks are queued without waiting for them to be flushed.
+func (pc *partitionConsumer) dispatchable(msg *message) bool {
+	msgID := msg.msgID.(trackingMessageID)
+	if pc.messageShouldBeDiscarded(msgID) {
+		pc.ackID(msgID, false)
+		return false
+	}
+
+	// the message was pushed before the seek and precedes the cursor now, acking it completes its batch
+	if pc.seek.stale(msgID.messageID) {
+		pc.ackID(msgID, false)
+		return false
+	}
+
+	if pc.dedup != nil && !pc.dedup.add(msgID.messageID) {
+		pc.metrics.DuplicatesDropped.Inc()
+		// the ack of the delivered message covers the duplicate until it is acked, acking the duplicate
+		// afterwards lets the broker forget the redelivery
+		if !pc.unacked.tracked(msgID.messageID) {
+			pc.ackID(msgID, false)
 		}
+		return false
+	}
 
-		// set the consumer so we know how to ack the message id
-		msgID.consumer = pc
-		var msg *message
-		if smm != nil {
-			msg = &message{
-				publishTime:         timeFromUnixTimestampMillis(msgMeta.GetPublishTime()),
-				eventTime:           timeFromUnixTimestampMillis(smm.GetEventTime()),
-				key:                 smm.GetPartitionKey(),
-				producerName:        msgMeta.GetProducerName(),
-				properties:          internal.ConvertToStringMap(smm.GetProperties()),
-				topic:               pc.topic,
-				msgID:               msgID,
-				payLoad:             payload,
-				schema:              pc.options.schema,
-				replicationClusters: msgMeta.GetReplicateTo(),
-				replicatedFrom:      msgMeta.GetReplicatedFrom(),
-				redeliveryCount:     response.GetRedeliveryCount(),
-			}
+	if err := decodePayload(pc.options.payloadCodecs, msg); err != nil {
+		pc.log.WithError(err).WithField("msgID", msgID).Warn("Delivering the message with its encoded payload")
+	}
+
+	if pc.options.messageFilter != nil && !pc.options.messageFilter(msg) {
+		if pc.options.nackFilteredMessages {
+			pc.NackID(msgID)
 		} else {
-			msg = &message{
-				publishTime:         timeFromUnixTimestampMillis(msgMeta.GetPublishTime()),
-				eventTime:           timeFromUnixTimestampMillis(msgMeta.GetEventTime()),
-				key:                 msgMeta.GetPartitionKey(),
-				producerName:        msgMeta.GetProducerName(),
-				properties:          internal.ConvertToStringMap(msgMeta.GetProperties()),
-				topic:               pc.topic,
-				msgID:               msgID,
-				payLoad:             payload,
-				schema:              pc.options.schema,
-				replicationClusters: msgMeta.GetReplicateTo(),
-				replicatedFrom:      msgMeta.GetReplicatedFrom(),
-				redeliveryCount:     response.GetRedeliveryCount(),
-			}
+			pc.ackID(msgID, false)
 		}
+		return false
+	}
 
-		pc.options.interceptors.BeforeConsume(ConsumerMessage{
-			Consumer: pc.parentConsumer,
-			Message:  msg,
-		})
+	pc.options.interceptors.BeforeConsume(ConsumerMessage{
+		Consumer: pc.parentConsumer,
+		Message:  msg,
+	})
+
+	msg.seeks = pc.seeks.Load()
+	pc.trackUnacked(msg)
+	return true
+}
+
+// seekState tracks the target of a seek until a message at or after it is received, the messages preceding it
+// are the ones the broker pushed before moving the cursor
+type seekState struct {
+	sync.Mutex
+	target  messageID
+	seeking bool
+}
+
+func (s *seekState) start(target messageID) {
+	s.Lock()
+	defer s.Unlock()
+	// every message follows the earliest position and none reaches the latest one, nothing can be told stale
+	s.seeking = !target.equal(earliestMessageID.(messageID)) && !target.equal(latestMessageID.(messageID))
+	s.target = target
+}
 
-		messages = append(messages, msg)
+// stale returns true if the message precedes the target of the seek in progress, which ends with the first
+// message at or after the target
+func (s *seekState) stale(msgID messageID) bool {
+	s.Lock()
+	defer s.Unlock()
+	if !s.seeking {
+		return false
 	}
+	if s.target.greater(msgID) {
+		return true
+	}
+	s.seeking = false
+	return false
+}
 
-	// send messages to the dispatcher
-	pc.queueCh <- messages
-	return nil
+// releasePermits gives the permits of the messages dropped before reaching the queue back to the dispatcher
+func (pc *partitionConsumer) releasePermits(permits int32) {
+	select {
+	case pc.releasedPermitsCh <- permits:
+	case <-pc.closeCh:
+	}
+}
+
+func (pc *partitionConsumer) setStartMessageID(msgID trackingMessageID) {
+	pc.startLock.Lock()
+	defer pc.startLock.Unlock()
+	pc.startMessageID = msgID
 }
 
 func (pc *partitionConsumer) messageShouldBeDiscarded(msgID trackingMessageID) bool {
+	pc.startLock.RLock()
+	defer pc.startLock.RUnlock()
 	if pc.startMessageID.Undefined() {
 		return false
 	}
@@ -584,9 +1285,19 @@ func (pc *partitionConsumer) messageShouldBeDiscarded(msgID trackingMessageID) b
 	return pc.startMessageID.greaterEqual(msgID.messageID)
 }
 
+// setReceiverQueueSize changes the number of messages the broker may push ahead, the permits already given
+// to the broker are adjusted as the messages get dispatched
+func (pc *partitionConsumer) setReceiverQueueSize(size int) {
+	pc.queueSize.Store(int32(size))
+}
+
 func (pc *partitionConsumer) ConnectionClosed() {
+	cnxID := pc.conn.ID()
+	pc.disconnected()
+	pc.failPendingTxnAcks(newError(ConnectError, "connection closed before the ack was answered"))
+
 	// Trigger reconnection in the consumer goroutine
-	pc.log.Debug("connection closed and send to connectClosedCh")
+	pc.log.WithField("cnx", cnxID).Debug("connection closed and send to connectClosedCh")
 	pc.connectClosedCh <- connectionClosed{}
 }
 
@@ -613,8 +1324,53 @@ func (pc *partitionConsumer) internalFlow(permits uint32) error {
 func (pc *partitionConsumer) dispatcher() {
 	defer func() {
 		pc.log.Debug("exiting dispatch loop")
+		close(pc.dispatcherDoneCh)
 	}()
 	var messages []*message
+
+	// the dispatcher alone accounts for the permits, they are given back to the broker once the messages
+	// are passed to the application, whether it calls Receive or reads Chan, or are dropped
+	var availablePermits int32
+	// the queue size the permits given to the broker add up to, it follows the queue size when it changes
+	grantedQueueSize := pc.queueSize.Load()
+	resetPermits := func() {
+		availablePermits = 0
+		grantedQueueSize = pc.queueSize.Load()
+		initialPermits := uint32(grantedQueueSize)
+
+		pc.log.Debugf("dispatcher requesting initial permits=%d", initialPermits)
+		// send initial permits
+		if err := pc.internalFlow(initialPermits); err != nil {
+			pc.log.WithError(err).Error("unable to send initial permits to broker")
+		}
+	}
+	flowPermitStrategy := pc.options.flowPermitStrategy
+	if flowPermitStrategy == nil {
+		flowPermitStrategy = HalfQueueFlowPermits()
+	}
+	releasePermits := func(permits int32) {
+		// send more permits if needed
+		availablePermits += permits
+		if availablePermits <= 0 || !flowPermitStrategy(int(availablePermits), int(grantedQueueSize)) ||
+			pc.withholdPermits.Load() {
+			return
+		}
+		// withhold or add the permits the queue size changed by since they were granted
+		requestedPermits := availablePermits + pc.queueSize.Load() - grantedQueueSize
+		if requestedPermits < 0 {
+			requestedPermits = 0
+		}
+		grantedQueueSize += requestedPermits - availablePermits
+
+		pc.log.Debugf("requesting more permits=%d available=%d", requestedPermits, availablePermits)
+		availablePermits = 0
+		if requestedPermits > 0 {
+			if err := pc.internalFlow(uint32(requestedPermits)); err != nil {
+				pc.log.WithError(err).Error("unable to send permits")
+			}
+		}
+	}
+
 	for {
 		var queueCh chan []*message
 		var messageCh chan ConsumerMessage
@@ -635,9 +1391,6 @@ func (pc *partitionConsumer) dispatcher() {
 				// pass the message to application channel
 				messageCh = pc.messageCh
 			}
-
-			pc.metrics.PrefetchedMessages.Dec()
-			pc.metrics.PrefetchedBytes.Sub(float64(len(messages[0].payLoad)))
 		} else {
 			// we are ready for more messages
 			queueCh = pc.queueCh
@@ -653,17 +1406,9 @@ func (pc *partitionConsumer) dispatcher() {
 			}
 			pc.log.Debug("dispatcher received connection event")
 
+			pc.untrackDropped(messages)
 			messages = nil
-
-			// reset available permits
-			pc.availablePermits = 0
-			initialPermits := uint32(pc.queueSize)
-
-			pc.log.Debugf("dispatcher requesting initial permits=%d", initialPermits)
-			// send initial permits
-			if err := pc.internalFlow(initialPermits); err != nil {
-				pc.log.WithError(err).Error("unable to send initial permits to broker")
-			}
+			resetPermits()
 
 		case msgs, ok := <-queueCh:
 			if !ok {
@@ -675,24 +1420,22 @@ func (pc *partitionConsumer) dispatcher() {
 
 		// if the messageCh is nil or the messageCh is full this will not be selected
 		case messageCh <- nextMessage:
+			pc.metrics.PrefetchedMessages.Dec()
+			pc.metrics.PrefetchedBytes.Sub(float64(len(messages[0].payLoad)))
+
+			// the dispatcher doesn't wait for the ack, it would block the events loop requests clearing the queue
+			if pc.options.autoAck && messageCh == pc.messageCh {
+				pc.ackID(messages[0].msgID.(trackingMessageID), false)
+			}
+
 			// allow this message to be garbage collected
 			messages[0] = nil
 			messages = messages[1:]
 
-			// TODO implement a better flow controller
-			// send more permits if needed
-			pc.availablePermits++
-			flowThreshold := int32(math.Max(float64(pc.queueSize/2), 1))
-			if pc.availablePermits >= flowThreshold {
-				availablePermits := pc.availablePermits
-				requestedPermits := availablePermits
-				pc.availablePermits = 0
-
-				pc.log.Debugf("requesting more permits=%d available=%d", requestedPermits, availablePermits)
-				if err := pc.internalFlow(uint32(requestedPermits)); err != nil {
-					pc.log.WithError(err).Error("unable to send permits")
-				}
-			}
+			releasePermits(1)
+
+		case permits := <-pc.releasedPermitsCh:
+			releasePermits(permits)
 
 		case clearQueueCb := <-pc.clearQueueCh:
 			// drain the message queue on any new connection by sending a
@@ -708,6 +1451,7 @@ func (pc *partitionConsumer) dispatcher() {
 				} else if nextMessageInQueue.Undefined() {
 					nextMessageInQueue = m[0].msgID.(trackingMessageID)
 				}
+				pc.untrackDropped(m)
 			}
 
 			clearQueueCb(nextMessageInQueue)
@@ -716,19 +1460,14 @@ func (pc *partitionConsumer) dispatcher() {
 			for len(pc.queueCh) > 0 {
 				<-pc.queueCh
 			}
-			for len(pc.messageCh) > 0 {
-				<-pc.messageCh
-			}
+			// the messages already in the channel of the application are dropped as they are received, the
+			// channel is shared with the other partitions whose messages keep their order
+			pc.seeks.Inc()
 			messages = nil
-
-			// reset available permits
-			pc.availablePermits = 0
-			initialPermits := uint32(pc.queueSize)
-
-			pc.log.Debugf("dispatcher requesting initial permits=%d", initialPermits)
-			// send initial permits
-			if err := pc.internalFlow(initialPermits); err != nil {
-				pc.log.WithError(err).Error("unable to send initial permits to broker")
+			resetPermits()
+			pc.clearUnacked()
+			if pc.dedup != nil {
+				pc.dedup.clear()
 			}
 
 			close(doneCh)
@@ -736,10 +1475,54 @@ func (pc *partitionConsumer) dispatcher() {
 	}
 }
 
-type ackRequest struct {
+// seekedPast returns true if the message was queued for the application before its partition was seeked, it
+// is then released and must not be delivered
+func seekedPast(cm ConsumerMessage) bool {
+	msg, ok := cm.Message.(*message)
+	if !ok {
+		return false
+	}
+	mid, ok := msg.msgID.(trackingMessageID)
+	if !ok {
+		return false
+	}
+	pc, ok := mid.consumer.(*partitionConsumer)
+	if !ok || msg.seeks == pc.seeks.Load() {
+		return false
+	}
+	msg.Release()
+	return true
+}
+
+// pendingTxnAck is the ack of a transaction waiting for the broker response
+type pendingTxnAck struct {
+	txn   *transaction
 	msgID trackingMessageID
 }
 
+type ackRequest struct {
+	doneCh chan struct{}
+	msgID  trackingMessageID
+	txn    *transaction
+}
+
+type ackListRequest struct {
+	doneCh chan struct{}
+	msgIDs []trackingMessageID
+	// the messages acked, a batch message is acked with the last message of its batch
+	acked []trackingMessageID
+}
+
+type flushAcksRequest struct {
+	doneCh chan struct{}
+}
+
+type ackCumulativeRequest struct {
+	doneCh chan struct{}
+	msgID  trackingMessageID
+	err    error
+}
+
 type unsubscribeRequest struct {
 	doneCh chan struct{}
 	err    error
@@ -760,12 +1543,16 @@ type getLastMsgIDRequest struct {
 }
 
 type seekRequest struct {
+	// ctx bounds the seek request to the broker
+	ctx    context.Context
 	doneCh chan struct{}
 	msgID  trackingMessageID
 	err    error
 }
 
 type seekByTimeRequest struct {
+	// ctx bounds the seek request to the broker
+	ctx         context.Context
 	doneCh      chan struct{}
 	publishTime time.Time
 	err         error
@@ -784,20 +1571,41 @@ func (pc *partitionConsumer) runEventsLoop() {
 				return
 			case <-pc.connectClosedCh:
 				pc.log.Debug("runEventsLoop will reconnect")
-				pc.reconnectToBroker()
+				if err := pc.reconnectToBroker(); err != nil {
+					pc.log.WithError(err).Error("Closing the consumer which gave up reconnecting")
+					pc.Close()
+					if pc.options.reconnectFailedHandler != nil {
+						pc.options.reconnectFailedHandler(pc.topic, err)
+					}
+				}
 			}
 		}
 	}()
 
 	for {
-		for i := range pc.eventsCh {
+		select {
+		case <-pc.ackNotifyCh:
+			pc.flushAcks()
+		case i := <-pc.eventsCh:
 			switch v := i.(type) {
 			case *ackRequest:
 				pc.internalAck(v)
+			case *ackListRequest:
+				pc.internalAckList(v)
+			case *flushAcksRequest:
+				pc.flushAcks()
+				close(v.doneCh)
+			case *ackCumulativeRequest:
+				pc.internalAckCumulative(v)
 			case *redeliveryRequest:
 				pc.internalRedeliver(v)
 			case *unsubscribeRequest:
+				// the acks must reach the broker before the subscription is removed
+				pc.flushAcks()
 				pc.internalUnsubscribe(v)
+				if pc.getConsumerState() == consumerClosed {
+					return
+				}
 			case *getLastMsgIDRequest:
 				pc.internalGetLastMessageID(v)
 			case *seekRequest:
@@ -805,6 +1613,7 @@ func (pc *partitionConsumer) runEventsLoop() {
 			case *seekByTimeRequest:
 				pc.internalSeekByTime(v)
 			case *closeRequest:
+				pc.flushAcks()
 				pc.internalClose(v)
 				return
 			}
@@ -812,26 +1621,9 @@ func (pc *partitionConsumer) runEventsLoop() {
 	}
 }
 
+// internalClose completes the close started by Close, which already moved the consumer to the closing state
 func (pc *partitionConsumer) internalClose(req *closeRequest) {
 	defer close(req.doneCh)
-	state := pc.getConsumerState()
-	if state != consumerReady {
-		// this might be redundant but to ensure nack tracker is closed
-		if pc.nackTracker != nil {
-			pc.nackTracker.Close()
-		}
-		return
-	}
-
-	if state == consumerClosed || state == consumerClosing {
-		pc.log.WithField("state", state).Error("Consumer is closing or has closed")
-		if pc.nackTracker != nil {
-			pc.nackTracker.Close()
-		}
-		return
-	}
-
-	pc.setConsumerState(consumerClosing)
 	pc.log.Infof("Closing consumer=%d", pc.consumerID)
 
 	requestID := pc.client.rpcClient.NewRequestID()
@@ -839,7 +1631,8 @@ func (pc *partitionConsumer) internalClose(req *closeRequest) {
 		ConsumerId: proto.Uint64(pc.consumerID),
 		RequestId:  proto.Uint64(requestID),
 	}
-	_, err := pc.client.rpcClient.RequestOnCnx(pc.conn, requestID, pb.BaseCommand_CLOSE_CONSUMER, cmdClose)
+	_, err := pc.client.rpcClient.RequestOnCnx(context.Background(), pc.conn, requestID,
+		pb.BaseCommand_CLOSE_CONSUMER, cmdClose)
 	if err != nil {
 		pc.log.WithError(err).Warn("Failed to close consumer")
 	} else {
@@ -852,16 +1645,22 @@ func (pc *partitionConsumer) internalClose(req *closeRequest) {
 
 	pc.setConsumerState(consumerClosed)
 	pc.conn.DeleteConsumeHandler(pc.consumerID)
+	pc.client.consumers.Del(pc.consumerID)
+	pc.failPendingTxnAcks(ErrConsumerClosed)
+	pc.clearUnacked()
 	if pc.nackTracker != nil {
 		pc.nackTracker.Close()
 	}
 	close(pc.closeCh)
 }
 
-func (pc *partitionConsumer) reconnectToBroker() {
+// reconnectToBroker retries to connect to the broker, it fails once the reconnection attempts are exhausted
+func (pc *partitionConsumer) reconnectToBroker() error {
 	var (
 		maxRetry int
-		backoff  = internal.Backoff{}
+		attempt  int
+		backoff  = pc.options.backoffPolicy()
+		err      error
 	)
 
 	if pc.options.maxReconnectToBroker == nil {
@@ -870,30 +1669,38 @@ func (pc *partitionConsumer) reconnectToBroker() {
 		maxRetry = int(*pc.options.maxReconnectToBroker)
 	}
 
+	// the topic may have moved to another broker
+	pc.client.lookupService.Invalidate(pc.topic, pc.brokerAddr)
+
 	for maxRetry != 0 {
 		if pc.getConsumerState() != consumerReady {
 			// Consumer is already closing
-			return
+			return nil
 		}
 
 		d := backoff.Next()
 		pc.log.Info("Reconnecting to broker in ", d)
 		time.Sleep(d)
 
-		err := pc.grabConn()
+		attempt++
+		pc.client.reconnectAttempt(pc.topic, attempt)
+
+		err = pc.grabConn(context.Background())
 		if err == nil {
 			// Successfully reconnected
-			pc.log.Info("Reconnected consumer to broker")
-			return
+			pc.log.WithField("cnx", pc.conn.ID()).Info("Reconnected consumer to broker")
+			return nil
 		}
 
 		if maxRetry > 0 {
 			maxRetry--
 		}
 	}
+	return reconnectFailedError(err)
 }
 
-func (pc *partitionConsumer) grabConn() error {
+// grabConn subscribes the consumer on the broker serving its topic, ctx bounds the subscribe request
+func (pc *partitionConsumer) grabConn(ctx context.Context) error {
 	lr, err := pc.client.lookupService.Lookup(pc.topic)
 	if err != nil {
 		pc.log.WithError(err).Warn("Failed to lookup topic")
@@ -908,7 +1715,9 @@ func (pc *partitionConsumer) grabConn() error {
 
 	pbSchema := new(pb.Schema)
 
-	if pc.options.schema != nil && pc.options.schema.GetSchemaInfo() != nil {
+	// the topic schema isn't checked when consuming with the schema of each message
+	if pc.options.schema != nil && pc.options.schema.GetSchemaInfo() != nil &&
+		pc.options.schema.GetSchemaInfo().Type != AutoConsume {
 		tmpSchemaType := pb.Schema_Type(int32(pc.options.schema.GetSchemaInfo().Type))
 		pbSchema = &pb.Schema{
 			Name:       proto.String(pc.options.schema.GetSchemaInfo().Name),
@@ -939,7 +1748,7 @@ func (pc *partitionConsumer) grabConn() error {
 		KeySharedMeta:              keySharedMeta,
 	}
 
-	pc.startMessageID = pc.clearReceiverQueue()
+	pc.setStartMessageID(pc.clearReceiverQueue())
 	if pc.options.subscriptionMode != durable {
 		// For regular subscriptions the broker will determine the restarting point
 		cmdSubscribe.StartMessageId = convertToMessageIDData(pc.startMessageID)
@@ -955,21 +1764,27 @@ func (pc *partitionConsumer) grabConn() error {
 		cmdSubscribe.ForceTopicCreation = proto.Bool(false)
 	}
 
-	res, err := pc.client.rpcClient.Request(lr.LogicalAddr, lr.PhysicalAddr, requestID,
+	res, err := pc.client.rpcClient.Request(ctx, lr.LogicalAddr, lr.PhysicalAddr, requestID,
 		pb.BaseCommand_SUBSCRIBE, cmdSubscribe)
 
 	if err != nil {
 		pc.log.WithError(err).Error("Failed to create consumer")
+		if ctx.Err() != nil {
+			// the broker may still subscribe the consumer the request was abandoned for
+			pc.closeAbandonedConsumer(lr)
+		}
+		pc.client.lookupService.Invalidate(pc.topic, lr.LogicalAddr)
 		return err
 	}
 
-	if res.Response.ConsumerStatsResponse != nil {
-		pc.name = res.Response.ConsumerStatsResponse.GetConsumerName()
-	}
-
 	pc.conn = res.Cnx
-	pc.log.Info("Connected consumer")
-	pc.conn.AddConsumeHandler(pc.consumerID, pc)
+	pc.brokerAddr = lr.LogicalAddr
+	if err := pc.conn.AddConsumeHandler(pc.consumerID, pc); err != nil {
+		pc.log.WithError(err).Warn("Connection closed while creating the consumer")
+		return err
+	}
+	pc.connected(lr.LogicalAddr.String())
+	pc.log.WithField("cnx", pc.conn.ID()).Info("Connected consumer")
 
 	msgType := res.Response.GetType()
 
@@ -982,12 +1797,27 @@ func (pc *partitionConsumer) grabConn() error {
 		return nil
 	case pb.BaseCommand_ERROR:
 		errMsg := res.Response.GetError()
-		return fmt.Errorf("%s: %s", errMsg.GetError().String(), errMsg.GetMessage())
+		return &internal.ServerError{Code: errMsg.GetError(), Message: errMsg.GetMessage()}
 	default:
 		return newUnexpectedErrMsg(msgType, requestID)
 	}
 }
 
+// closeAbandonedConsumer closes on the broker the consumer whose subscribe request was abandoned
+func (pc *partitionConsumer) closeAbandonedConsumer(lr *internal.LookupResult) {
+	cnx, err := pc.client.cnxPool.GetConnection(lr.LogicalAddr, lr.PhysicalAddr)
+	if err != nil {
+		return
+	}
+	cmdClose := &pb.CommandCloseConsumer{
+		ConsumerId: proto.Uint64(pc.consumerID),
+		RequestId:  proto.Uint64(pc.client.rpcClient.NewRequestID()),
+	}
+	if err := pc.client.rpcClient.RequestOnCnxNoWait(cnx, pb.BaseCommand_CLOSE_CONSUMER, cmdClose); err != nil {
+		pc.log.WithError(err).Warn("Failed to close the abandoned consumer")
+	}
+}
+
 func (pc *partitionConsumer) clearQueueAndGetNextMessage() trackingMessageID {
 	if pc.getConsumerState() != consumerReady {
 		return trackingMessageID{}
@@ -1058,6 +1888,11 @@ func getPreviousMessage(mid trackingMessageID) trackingMessageID {
 }
 
 func (pc *partitionConsumer) Decompress(msgMeta *pb.MessageMetadata, payload internal.Buffer) (internal.Buffer, error) {
+	if msgMeta.GetCompression() == pb.CompressionType_NONE {
+		// nothing to decompress, avoid copying the payload
+		return payload, nil
+	}
+
 	provider, ok := pc.compressionProviders[msgMeta.GetCompression()]
 	if !ok {
 		var err error
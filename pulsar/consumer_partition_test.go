@@ -18,21 +18,26 @@
 package pulsar
 
 import (
+	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/apache/pulsar-client-go/pulsar/internal/compression"
 	pb "github.com/apache/pulsar-client-go/pulsar/internal/pulsar_proto"
 
+	"github.com/gogo/protobuf/proto"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/apache/pulsar-client-go/pulsar/internal"
+	"github.com/apache/pulsar-client-go/pulsar/log"
 )
 
 func TestSingleMessageIDNoAckTracker(t *testing.T) {
-	eventsCh := make(chan interface{}, 1)
+	ackNotifyCh := make(chan struct{}, 1)
 	pc := partitionConsumer{
 		queueCh:              make(chan []*message, 1),
-		eventsCh:             eventsCh,
+		ackNotifyCh:          ackNotifyCh,
 		compressionProviders: make(map[pb.CompressionType]compression.Provider),
 		options:              &partitionConsumerOpts{},
 		metrics:              internal.NewMetricsProvider(map[string]string{}).GetTopicMetrics("topic"),
@@ -53,18 +58,685 @@ func TestSingleMessageIDNoAckTracker(t *testing.T) {
 	pc.AckID(messages[0].msgID.(trackingMessageID))
 
 	select {
-	case <-eventsCh:
+	case <-ackNotifyCh:
 	default:
-		t.Error("Expected an ack request to be triggered!")
+		t.Error("Expected an ack to be queued!")
 	}
 }
 
-func TestBatchMessageIDNoAckTracker(t *testing.T) {
+func TestAckIDWithTxn(t *testing.T) {
 	eventsCh := make(chan interface{}, 1)
 	pc := partitionConsumer{
+		topic:                "topic",
 		queueCh:              make(chan []*message, 1),
 		eventsCh:             eventsCh,
 		compressionProviders: make(map[pb.CompressionType]compression.Provider),
+		options:              &partitionConsumerOpts{subscription: "sub"},
+		metrics:              internal.NewMetricsProvider(map[string]string{}).GetTopicMetrics("topic"),
+	}
+
+	headersAndPayload := internal.NewBufferWrapper(rawCompatSingleMessage)
+	if err := pc.MessageReceived(nil, headersAndPayload); err != nil {
+		t.Fatal(err)
+	}
+	messages := <-pc.queueCh
+
+	tc := &mockedTCClient{}
+	txn := newTransaction(internal.TxnID{MostSigBits: 1, LeastSigBits: 2}, tc, log.DefaultNopLogger())
+	reqCh := make(chan *ackRequest, 1)
+	go func() {
+		// the events loop takes the ack
+		req := (<-eventsCh).(*ackRequest)
+		close(req.doneCh)
+		reqCh <- req
+	}()
+	assert.Nil(t, pc.AckIDWithTxn(messages[0].msgID.(trackingMessageID), txn))
+	assert.Equal(t, []string{"topic/sub"}, tc.subscriptions)
+	assert.Equal(t, txn, (<-reqCh).txn)
+}
+
+func TestAckIDWithTxnClosedConsumer(t *testing.T) {
+	pc := partitionConsumer{
+		topic:                "topic",
+		queueCh:              make(chan []*message, 1),
+		eventsCh:             make(chan interface{}),
+		closeCh:              make(chan struct{}),
+		compressionProviders: make(map[pb.CompressionType]compression.Provider),
+		options:              &partitionConsumerOpts{subscription: "sub"},
+		metrics:              internal.NewMetricsProvider(map[string]string{}).GetTopicMetrics("topic"),
+	}
+
+	headersAndPayload := internal.NewBufferWrapper(rawCompatSingleMessage)
+	if err := pc.MessageReceived(nil, headersAndPayload); err != nil {
+		t.Fatal(err)
+	}
+	messages := <-pc.queueCh
+
+	// the events loop exited, nothing takes the ack anymore
+	close(pc.closeCh)
+	txn := newTransaction(internal.TxnID{MostSigBits: 1, LeastSigBits: 2}, &mockedTCClient{}, log.DefaultNopLogger())
+	assert.Equal(t, ErrConsumerClosed, pc.AckIDWithTxn(messages[0].msgID.(trackingMessageID), txn))
+
+	// the ack doesn't hold the transaction, which is aborted
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := txn.Commit(ctx)
+	assert.True(t, errors.Is(err, ErrConsumerClosed))
+	assert.Equal(t, TxnAborted, txn.State())
+}
+
+func TestAckIDListChangesNothingWhenRejected(t *testing.T) {
+	pc := partitionConsumer{
+		queueCh:              make(chan []*message, 1),
+		eventsCh:             make(chan interface{}),
+		closeCh:              make(chan struct{}),
+		compressionProviders: make(map[pb.CompressionType]compression.Provider),
+		options:              &partitionConsumerOpts{},
+		metrics:              internal.NewMetricsProvider(map[string]string{}).GetTopicMetrics("topic"),
+	}
+
+	headersAndPayload := internal.NewBufferWrapper(rawCompatSingleMessage)
+	if err := pc.MessageReceived(nil, headersAndPayload); err != nil {
+		t.Fatal(err)
+	}
+	msgID := (<-pc.queueCh)[0].msgID.(trackingMessageID)
+	assert.True(t, pc.unacked.tracked(msgID.messageID))
+
+	err := pc.AckIDList([]trackingMessageID{msgID, {}})
+	assert.Equal(t, InvalidMessage, err.(*Error).Result())
+	assert.True(t, pc.unacked.tracked(msgID.messageID))
+
+	// the events loop exited, the message stays tracked and isn't acked
+	close(pc.closeCh)
+	assert.Equal(t, ErrConsumerClosed, pc.AckIDList([]trackingMessageID{msgID}))
+	assert.True(t, pc.unacked.tracked(msgID.messageID))
+	assert.Empty(t, pc.pendingAcks)
+}
+
+func TestAckResponseEndsTxnAck(t *testing.T) {
+	tc := &mockedTCClient{}
+	txn1 := newTransaction(internal.TxnID{MostSigBits: 1, LeastSigBits: 1}, tc, log.DefaultNopLogger())
+	txn2 := newTransaction(internal.TxnID{MostSigBits: 1, LeastSigBits: 2}, tc, log.DefaultNopLogger())
+	assert.Nil(t, txn1.registerOp())
+	assert.Nil(t, txn2.registerOp())
+
+	msgID1 := trackingMessageID{messageID: messageID{ledgerID: 1, entryID: 1}}
+	msgID2 := trackingMessageID{messageID: messageID{ledgerID: 1, entryID: 2}}
+	pc := partitionConsumer{
+		pendingTxnAcks: []pendingTxnAck{{txn: txn1, msgID: msgID1}, {txn: txn2, msgID: msgID2}},
+		metrics:        internal.NewMetricsProvider(map[string]string{}).GetTopicMetrics("topic"),
+		log:            log.DefaultNopLogger(),
+	}
+	pc.unacked.add(msgID1.messageID, time.Now(), 0)
+	pc.unacked.add(msgID2.messageID, time.Now(), 0)
+
+	// the broker answers the ack of the second transaction with an error
+	pc.AckResponse(&pb.CommandAckResponse{
+		ConsumerId:     proto.Uint64(0),
+		TxnidMostBits:  proto.Uint64(1),
+		TxnidLeastBits: proto.Uint64(2),
+		Error:          pb.ServerError_InvalidTxnStatus.Enum(),
+		Message:        proto.String("invalid message id"),
+	})
+	assert.Equal(t, []pendingTxnAck{{txn: txn1, msgID: msgID1}}, pc.pendingTxnAcks)
+	// the message wasn't acked, it is still redelivered on the ack timeout
+	assert.True(t, pc.unacked.tracked(msgID2.messageID))
+
+	err := txn2.Commit(context.Background())
+	assert.Equal(t, InvalidTxnStatus, err.(*Error).Result())
+	assert.Equal(t, TxnAborted, txn2.State())
+
+	pc.AckResponse(&pb.CommandAckResponse{
+		ConsumerId:     proto.Uint64(0),
+		TxnidMostBits:  proto.Uint64(1),
+		TxnidLeastBits: proto.Uint64(1),
+	})
+	assert.Empty(t, pc.pendingTxnAcks)
+	assert.False(t, pc.unacked.tracked(msgID1.messageID))
+	assert.Nil(t, txn1.Commit(context.Background()))
+}
+
+func TestMessageReceivedCopyPayload(t *testing.T) {
+	pc := partitionConsumer{
+		queueCh:              make(chan []*message, 1),
+		compressionProviders: make(map[pb.CompressionType]compression.Provider),
+		options:              &partitionConsumerOpts{copyPayload: true},
+		metrics:              internal.NewMetricsProvider(map[string]string{}).GetTopicMetrics("topic"),
+	}
+
+	data := append([]byte(nil), rawBatchMessage10...)
+	if err := pc.MessageReceived(nil, internal.NewBufferWrapper(data)); err != nil {
+		t.Fatal(err)
+	}
+
+	// the payloads must not refer to the buffer the batch was read into
+	for i := range data {
+		data[i] = 0
+	}
+	messages := <-pc.queueCh
+	assert.Equal(t, 10, len(messages))
+	for _, m := range messages {
+		assert.Equal(t, "hello", string(m.Payload()))
+	}
+}
+
+func TestMessageReceivedReleasePayload(t *testing.T) {
+	pc := partitionConsumer{
+		queueCh:              make(chan []*message, 1),
+		ackNotifyCh:          make(chan struct{}, 1),
+		releasedPermitsCh:    make(chan int32, 1),
+		compressionProviders: make(map[pb.CompressionType]compression.Provider),
+		options:              &partitionConsumerOpts{releasePayload: true},
+		startMessageID:       trackingMessageID{messageID: newMessageID(0, 0, 4, 0).(messageID)},
+		metrics:              internal.NewMetricsProvider(map[string]string{}).GetTopicMetrics("topic"),
+	}
+
+	if err := pc.MessageReceived(nil, internal.NewBufferWrapper(rawBatchMessage10)); err != nil {
+		t.Fatal(err)
+	}
+	<-pc.releasedPermitsCh
+
+	// only the delivered messages reference the buffer of the batch
+	messages := <-pc.queueCh
+	assert.Equal(t, 5, len(messages))
+	payload := messages[0].sharedPayload
+	assert.Equal(t, int32(5), payload.refs)
+
+	for _, m := range messages {
+		assert.Equal(t, "hello", string(m.Payload()))
+		m.Release()
+		m.Release()
+		assert.Nil(t, m.Payload())
+	}
+	assert.Equal(t, int32(0), payload.refs)
+}
+
+func TestDiscardedMessagesReleasePermits(t *testing.T) {
+	pc := partitionConsumer{
+		queueCh:              make(chan []*message, 1),
+		ackNotifyCh:          make(chan struct{}, 1),
+		releasedPermitsCh:    make(chan int32, 1),
+		compressionProviders: make(map[pb.CompressionType]compression.Provider),
+		options:              &partitionConsumerOpts{},
+		startMessageID:       trackingMessageID{messageID: newMessageID(0, 0, 4, 0).(messageID)},
+		metrics:              internal.NewMetricsProvider(map[string]string{}).GetTopicMetrics("topic"),
+	}
+
+	if err := pc.MessageReceived(nil, internal.NewBufferWrapper(rawBatchMessage10)); err != nil {
+		t.Fatal(err)
+	}
+
+	// the messages before the start message are dropped and their permits given back to the dispatcher
+	messages := <-pc.queueCh
+	assert.Equal(t, 5, len(messages))
+	assert.Equal(t, int32(5), <-pc.releasedPermitsCh)
+}
+
+func TestFilteredMessagesSyncAck(t *testing.T) {
+	pc := partitionConsumer{
+		queueCh:              make(chan []*message, 1),
+		ackNotifyCh:          make(chan struct{}, 1),
+		releasedPermitsCh:    make(chan int32, 1),
+		compressionProviders: make(map[pb.CompressionType]compression.Provider),
+		options: &partitionConsumerOpts{
+			syncAck:       true,
+			messageFilter: func(m Message) bool { return false },
+		},
+		metrics: internal.NewMetricsProvider(map[string]string{}).GetTopicMetrics("topic"),
+	}
+
+	// the messages dropped on the connection goroutine don't wait for the ack of the batch to be flushed
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- pc.MessageReceived(nil, internal.NewBufferWrapper(rawBatchMessage10))
+	}()
+	select {
+	case err := <-errCh:
+		assert.Nil(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for the message to be received")
+	}
+	assert.Equal(t, 0, len(<-pc.queueCh))
+	assert.Equal(t, 1, len(pc.pendingAcks))
+}
+
+func TestMessageFilter(t *testing.T) {
+	pc := partitionConsumer{
+		queueCh:              make(chan []*message, 1),
+		ackNotifyCh:          make(chan struct{}, 1),
+		releasedPermitsCh:    make(chan int32, 1),
+		compressionProviders: make(map[pb.CompressionType]compression.Provider),
+		options: &partitionConsumerOpts{
+			messageFilter: func(m Message) bool {
+				return m.ID().BatchIdx()%2 == 0
+			},
+		},
+		metrics: internal.NewMetricsProvider(map[string]string{}).GetTopicMetrics("topic"),
+	}
+
+	if err := pc.MessageReceived(nil, internal.NewBufferWrapper(rawBatchMessage10)); err != nil {
+		t.Fatal(err)
+	}
+
+	// the filtered messages are not delivered and their permits are given back to the dispatcher
+	messages := <-pc.queueCh
+	assert.Equal(t, 5, len(messages))
+	for _, m := range messages {
+		assert.Equal(t, int32(0), m.ID().BatchIdx()%2)
+	}
+	assert.Equal(t, int32(5), <-pc.releasedPermitsCh)
+
+	// acking the delivered messages completes the batch
+	for _, m := range messages {
+		pc.AckID(m.msgID.(trackingMessageID))
+	}
+	select {
+	case <-pc.ackNotifyCh:
+	default:
+		t.Error("Expected an ack to be queued!")
+	}
+}
+
+type funcPayloadProcessor func(payload []byte, ctx MessagePayloadContext, consume func(Message)) error
+
+func (f funcPayloadProcessor) Process(payload []byte, ctx MessagePayloadContext, consume func(Message)) error {
+	return f(payload, ctx, consume)
+}
+
+func TestPayloadProcessor(t *testing.T) {
+	pc := partitionConsumer{
+		queueCh:              make(chan []*message, 1),
+		releasedPermitsCh:    make(chan int32, 1),
+		compressionProviders: make(map[pb.CompressionType]compression.Provider),
+		options:              &partitionConsumerOpts{},
+		metrics:              internal.NewMetricsProvider(map[string]string{}).GetTopicMetrics("topic"),
+	}
+
+	// the processor splits the entry in its own format, here one message per 2 messages of the batch
+	pc.options.payloadProcessor = funcPayloadProcessor(func(payload []byte, ctx MessagePayloadContext,
+		consume func(Message)) error {
+		assert.True(t, ctx.IsBatch())
+		assert.Equal(t, 10, ctx.NumMessages())
+		_, err := ctx.NewMessage(ctx.NumMessages(), payload, "", nil)
+		assert.NotNil(t, err)
+
+		for i := 0; i < ctx.NumMessages(); i += 2 {
+			msg, err := ctx.NewMessage(i, []byte("custom"), "key", map[string]string{"format": "custom"})
+			if err != nil {
+				return err
+			}
+			consume(msg)
+		}
+		return nil
+	})
+	if err := pc.MessageReceived(nil, internal.NewBufferWrapper(rawBatchMessage10)); err != nil {
+		t.Fatal(err)
+	}
+	messages := <-pc.queueCh
+	assert.Equal(t, 5, len(messages))
+	for i, m := range messages {
+		assert.Equal(t, int32(i*2), m.ID().BatchIdx())
+		assert.Equal(t, []byte("custom"), m.Payload())
+		assert.Equal(t, "key", m.Key())
+		assert.Equal(t, "custom", m.Properties()["format"])
+	}
+	assert.Equal(t, int32(5), <-pc.releasedPermitsCh)
+	assert.Equal(t, 5, pc.UnackedMessages())
+
+	// the entries the processor doesn't handle are decoded as Pulsar batches
+	pc.options.payloadProcessor = funcPayloadProcessor(func(payload []byte, ctx MessagePayloadContext,
+		consume func(Message)) error {
+		return ctx.DefaultProcess(consume)
+	})
+	if err := pc.MessageReceived(nil, internal.NewBufferWrapper(rawBatchMessage10)); err != nil {
+		t.Fatal(err)
+	}
+	messages = <-pc.queueCh
+	assert.Equal(t, 10, len(messages))
+	// the same entry was received again, its messages are tracked once
+	assert.Equal(t, 10, pc.UnackedMessages())
+
+}
+
+func TestPayloadProcessorNotConsumed(t *testing.T) {
+	pc := partitionConsumer{
+		queueCh:              make(chan []*message, 1),
+		ackNotifyCh:          make(chan struct{}, 1),
+		releasedPermitsCh:    make(chan int32, 1),
+		compressionProviders: make(map[pb.CompressionType]compression.Provider),
+		options:              &partitionConsumerOpts{},
+		metrics:              internal.NewMetricsProvider(map[string]string{}).GetTopicMetrics("topic"),
+		log:                  log.DefaultNopLogger(),
+	}
+
+	// the processor passes the first message twice and skips the others but the third
+	pc.options.payloadProcessor = funcPayloadProcessor(func(payload []byte, ctx MessagePayloadContext,
+		consume func(Message)) error {
+		for _, i := range []int{0, 0, 2} {
+			msg, err := ctx.NewMessage(i, []byte("custom"), "", nil)
+			if err != nil {
+				return err
+			}
+			consume(msg)
+		}
+		return nil
+	})
+	if err := pc.MessageReceived(nil, internal.NewBufferWrapper(rawBatchMessage10)); err != nil {
+		t.Fatal(err)
+	}
+	messages := <-pc.queueCh
+	assert.Equal(t, 2, len(messages))
+	assert.Equal(t, int32(0), messages[0].ID().BatchIdx())
+	assert.Equal(t, int32(2), messages[1].ID().BatchIdx())
+	assert.Equal(t, int32(8), <-pc.releasedPermitsCh)
+
+	// the messages which weren't consumed are acked, the entry is acked with the consumed ones
+	pc.AckID(messages[0].msgID.(trackingMessageID))
+	select {
+	case <-pc.ackNotifyCh:
+		t.Fatal("Expected the entry to wait for the ack of all the consumed messages")
+	default:
+	}
+	pc.AckID(messages[1].msgID.(trackingMessageID))
+	select {
+	case <-pc.ackNotifyCh:
+	default:
+		t.Error("Expected an ack to be queued!")
+	}
+	assert.Equal(t, 1, len(pc.pendingAcks))
+}
+
+func TestAutoAckOnDelivery(t *testing.T) {
+	pc := &partitionConsumer{
+		queueCh:              make(chan []*message, 1),
+		messageCh:            make(chan ConsumerMessage, 10),
+		ackNotifyCh:          make(chan struct{}, 1),
+		closeCh:              make(chan struct{}),
+		dispatcherDoneCh:     make(chan struct{}),
+		compressionProviders: make(map[pb.CompressionType]compression.Provider),
+		options:              &partitionConsumerOpts{autoAck: true},
+		dlq:                  &dlqRouter{},
+		metrics:              internal.NewMetricsProvider(map[string]string{}).GetTopicMetrics("topic"),
+		log:                  log.DefaultNopLogger(),
+	}
+	pc.queueSize.Store(1000)
+	go pc.dispatcher()
+	defer func() {
+		close(pc.closeCh)
+		<-pc.dispatcherDoneCh
+	}()
+
+	if err := pc.MessageReceived(nil, internal.NewBufferWrapper(rawCompatSingleMessage)); err != nil {
+		t.Fatal(err)
+	}
+
+	// the message is acked once delivered, before the application acks it
+	<-pc.messageCh
+	select {
+	case <-pc.ackNotifyCh:
+	case <-time.After(time.Second):
+		t.Error("Expected an ack to be queued!")
+	}
+	assert.Equal(t, 1, len(pc.pendingAcks))
+}
+
+func TestUnackedMessages(t *testing.T) {
+	pc := partitionConsumer{
+		queueCh:              make(chan []*message, 1),
+		ackNotifyCh:          make(chan struct{}, 10),
+		compressionProviders: make(map[pb.CompressionType]compression.Provider),
+		options:              &partitionConsumerOpts{},
+		metrics:              internal.NewMetricsProvider(map[string]string{}).GetTopicMetrics("topic"),
+	}
+	assert.Equal(t, 0, pc.UnackedMessages())
+	assert.Equal(t, time.Duration(0), pc.OldestUnackedMessageAge())
+
+	if err := pc.MessageReceived(nil, internal.NewBufferWrapper(rawBatchMessage10)); err != nil {
+		t.Fatal(err)
+	}
+	messages := <-pc.queueCh
+	assert.Equal(t, len(messages), pc.UnackedMessages())
+	assert.True(t, pc.OldestUnackedMessageAge() >= 0)
+
+	for i, m := range messages {
+		pc.AckID(m.msgID.(trackingMessageID))
+		assert.Equal(t, len(messages)-i-1, pc.UnackedMessages())
+	}
+	assert.Equal(t, time.Duration(0), pc.OldestUnackedMessageAge())
+}
+
+func TestExtendAckDeadline(t *testing.T) {
+	pc := partitionConsumer{
+		queueCh:              make(chan []*message, 1),
+		ackNotifyCh:          make(chan struct{}, 10),
+		eventsCh:             make(chan interface{}, 1),
+		closeCh:              make(chan struct{}),
+		compressionProviders: make(map[pb.CompressionType]compression.Provider),
+		options:              &partitionConsumerOpts{},
+		log:                  log.DefaultNopLogger(),
+		metrics:              internal.NewMetricsProvider(map[string]string{}).GetTopicMetrics("topic"),
+	}
+	assert.Error(t, pc.ExtendAckDeadline(trackingMessageID{}, time.Minute))
+
+	pc.options.ackTimeout = 50 * time.Millisecond
+	pc.unacked.timeout = pc.options.ackTimeout
+	if err := pc.MessageReceived(nil, internal.NewBufferWrapper(rawBatchMessage10)); err != nil {
+		t.Fatal(err)
+	}
+	messages := <-pc.queueCh
+	assert.Equal(t, 10, pc.UnackedMessages())
+
+	extended := messages[0].msgID.(trackingMessageID)
+	assert.Error(t, pc.ExtendAckDeadline(extended, 0))
+	assert.NoError(t, pc.ExtendAckDeadline(extended, time.Hour))
+	assert.Error(t, pc.ExtendAckDeadline(trackingMessageID{messageID: messageID{entryID: 42}}, time.Hour))
+
+	// all the other messages are redelivered after the ack timeout
+	go pc.runAckTimeoutLoop()
+	defer close(pc.closeCh)
+	req := (<-pc.eventsCh).(*redeliveryRequest)
+	assert.Equal(t, 9, len(req.msgIds))
+	assert.NotContains(t, req.msgIds, extended.messageID)
+	assert.Equal(t, 1, pc.UnackedMessages())
+
+	pc.AckID(extended)
+	assert.Error(t, pc.ExtendAckDeadline(extended, time.Hour))
+}
+
+func TestSeekedPastKeepsOtherPartitions(t *testing.T) {
+	pc := &partitionConsumer{}
+	other := &partitionConsumer{}
+	var messages []ConsumerMessage
+	for i := 0; i < 4; i++ {
+		owner := pc
+		if i%2 == 1 {
+			owner = other
+		}
+		msgID := trackingMessageID{messageID: messageID{entryID: int64(i)}, consumer: owner}
+		messages = append(messages, ConsumerMessage{Message: &message{msgID: msgID}})
+	}
+
+	// only the messages of the partition received before its seek are dropped
+	pc.seeks.Inc()
+	var kept []int64
+	for _, cm := range messages {
+		if !seekedPast(cm) {
+			kept = append(kept, cm.ID().EntryID())
+		}
+	}
+	assert.Equal(t, []int64{1, 3}, kept)
+
+	// the messages received after the seek are delivered
+	msgID := trackingMessageID{messageID: messageID{entryID: 4}, consumer: pc}
+	assert.False(t, seekedPast(ConsumerMessage{Message: &message{msgID: msgID, seeks: pc.seeks.Load()}}))
+}
+
+func TestSeekDropsStaleMessages(t *testing.T) {
+	pc := partitionConsumer{
+		queueCh:              make(chan []*message, 1),
+		ackNotifyCh:          make(chan struct{}, 1),
+		releasedPermitsCh:    make(chan int32, 1),
+		compressionProviders: make(map[pb.CompressionType]compression.Provider),
+		options:              &partitionConsumerOpts{},
+		metrics:              internal.NewMetricsProvider(map[string]string{}).GetTopicMetrics("topic"),
+	}
+
+	// the messages of the batch preceding the seek target are acked and not delivered
+	pc.seek.start(messageID{entryID: 0, batchIdx: 5})
+	if err := pc.MessageReceived(nil, internal.NewBufferWrapper(rawBatchMessage10)); err != nil {
+		t.Fatal(err)
+	}
+	messages := <-pc.queueCh
+	assert.Equal(t, 5, len(messages))
+	assert.Equal(t, int32(5), messages[0].ID().BatchIdx())
+	assert.Equal(t, int32(5), <-pc.releasedPermitsCh)
+
+	// the seek ended with the first message at the target
+	if err := pc.MessageReceived(nil, internal.NewBufferWrapper(rawBatchMessage10)); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 10, len(<-pc.queueCh))
+
+	// nothing is stale after a seek to the latest position
+	pc.seek.start(latestMessageID.(messageID))
+	assert.False(t, pc.seek.stale(messageID{}))
+}
+
+// seekRPCClient answers the seeks after the broker pushed the messages preceding them
+type seekRPCClient struct {
+	internal.RPCClient
+	beforeSeek func()
+}
+
+func (c *seekRPCClient) NewRequestID() uint64 {
+	return 1
+}
+
+func (c *seekRPCClient) RequestOnCnx(ctx context.Context, cnx internal.Connection, requestID uint64,
+	cmdType pb.BaseCommand_Type, message proto.Message) (*internal.RPCResult, error) {
+	if cmdType == pb.BaseCommand_SEEK {
+		c.beforeSeek()
+	}
+	return &internal.RPCResult{}, nil
+}
+
+func (c *seekRPCClient) RequestOnCnxNoWait(cnx internal.Connection, cmdType pb.BaseCommand_Type,
+	message proto.Message) error {
+	return nil
+}
+
+func TestSeekWhileReceiving(t *testing.T) {
+	entry := func(entryID uint64) *pb.CommandMessage {
+		return &pb.CommandMessage{MessageId: &pb.MessageIdData{LedgerId: proto.Uint64(0), EntryId: &entryID}}
+	}
+	pc := &partitionConsumer{
+		client:               &client{},
+		queueCh:              make(chan []*message, 1),
+		messageCh:            make(chan ConsumerMessage, 5),
+		ackNotifyCh:          make(chan struct{}, 1),
+		releasedPermitsCh:    make(chan int32, 10),
+		connectedCh:          make(chan struct{}),
+		closeCh:              make(chan struct{}),
+		dispatcherDoneCh:     make(chan struct{}),
+		clearMessageQueuesCh: make(chan chan struct{}),
+		compressionProviders: make(map[pb.CompressionType]compression.Provider),
+		options:              &partitionConsumerOpts{},
+		dlq:                  &dlqRouter{},
+		log:                  log.DefaultNopLogger(),
+		metrics:              internal.NewMetricsProvider(map[string]string{}).GetTopicMetrics("topic"),
+	}
+	pc.queueSize.Store(10)
+	// the broker pushes the entry 0 until it moves the cursor to the entry 1
+	pc.client.rpcClient = &seekRPCClient{beforeSeek: func() {
+		assert.Nil(t, pc.MessageReceived(entry(0), internal.NewBufferWrapper(rawBatchMessage10)))
+	}}
+	go pc.dispatcher()
+	defer close(pc.closeCh)
+
+	received := make(chan []messageID)
+	go func() {
+		var ids []messageID
+		for len(ids) == 0 || ids[len(ids)-1] != (messageID{entryID: 1, batchIdx: 9}) {
+			m := <-pc.messageCh
+			ids = append(ids, m.ID().(trackingMessageID).messageID)
+		}
+		received <- ids
+	}()
+
+	assert.Nil(t, pc.MessageReceived(entry(0), internal.NewBufferWrapper(rawBatchMessage10)))
+	assert.Nil(t, pc.requestSeek(context.Background(), messageID{entryID: 1, batchIdx: -1}))
+	// a message of the entry 0 was still in flight when the broker answered
+	assert.Nil(t, pc.MessageReceived(entry(0), internal.NewBufferWrapper(rawBatchMessage10)))
+	assert.Nil(t, pc.MessageReceived(entry(1), internal.NewBufferWrapper(rawBatchMessage10)))
+
+	// the messages received before the seek may have reached the application, none after the entry 1 did
+	ids := <-received
+	first := len(ids) - 10
+	assert.True(t, first >= 0)
+	for i, id := range ids[first:] {
+		assert.Equal(t, messageID{entryID: 1, batchIdx: int32(i)}, id)
+	}
+	for _, id := range ids[:first] {
+		assert.Equal(t, int64(0), id.entryID)
+	}
+}
+
+func TestAckIDCumulativeNotAllowed(t *testing.T) {
+	for _, subType := range []SubscriptionType{Shared, KeyShared} {
+		pc := partitionConsumer{
+			options: &partitionConsumerOpts{subscriptionType: subType},
+		}
+		err := pc.AckIDCumulative(newTrackingMessageID(1, 1, -1, 0, nil))
+		assert.Equal(t, ErrCumulativeAckNotAllowed, err)
+		assert.Equal(t, OperationNotSupported, err.(*Error).Result())
+	}
+}
+
+func TestReconnectToBrokerExhausted(t *testing.T) {
+	maxReconnect := uint(0)
+	pc := partitionConsumer{
+		client: &client{lookupService: internal.NewCachedLookupService(nil, 0)},
+		options: &partitionConsumerOpts{
+			maxReconnectToBroker: &maxReconnect,
+			backoffPolicy:        newDefaultBackoff,
+		},
+	}
+	pc.setConsumerState(consumerReady)
+
+	err := pc.reconnectToBroker()
+	assert.NotNil(t, err)
+	assert.Equal(t, NotConnectedError, err.(*Error).Result())
+}
+
+func TestRedeliverCommands(t *testing.T) {
+	assert.Empty(t, redeliverCommands(1, nil))
+
+	msgIds := make([]messageID, 0, 2*maxRedeliverUnacknowledged+10)
+	for i := 0; i < cap(msgIds)-10; i++ {
+		msgIds = append(msgIds, messageID{ledgerID: 1, entryID: int64(i)})
+	}
+	// the messages of a batch are only requested once
+	for i := 0; i < 10; i++ {
+		msgIds = append(msgIds, messageID{ledgerID: 2, entryID: 0, batchIdx: int32(i)})
+	}
+
+	cmds := redeliverCommands(1, msgIds)
+	assert.Equal(t, 3, len(cmds))
+	assert.Equal(t, maxRedeliverUnacknowledged, len(cmds[0].MessageIds))
+	assert.Equal(t, maxRedeliverUnacknowledged, len(cmds[1].MessageIds))
+	assert.Equal(t, 1, len(cmds[2].MessageIds))
+	assert.Equal(t, uint64(2), cmds[2].MessageIds[0].GetLedgerId())
+	for _, cmd := range cmds {
+		assert.Equal(t, uint64(1), cmd.GetConsumerId())
+	}
+}
+
+func TestBatchMessageIDNoAckTracker(t *testing.T) {
+	ackNotifyCh := make(chan struct{}, 1)
+	pc := partitionConsumer{
+		queueCh:              make(chan []*message, 1),
+		ackNotifyCh:          ackNotifyCh,
+		compressionProviders: make(map[pb.CompressionType]compression.Provider),
 		options:              &partitionConsumerOpts{},
 		metrics:              internal.NewMetricsProvider(map[string]string{}).GetTopicMetrics("topic"),
 	}
@@ -84,17 +756,17 @@ func TestBatchMessageIDNoAckTracker(t *testing.T) {
 	pc.AckID(messages[0].msgID.(trackingMessageID))
 
 	select {
-	case <-eventsCh:
+	case <-ackNotifyCh:
 	default:
-		t.Error("Expected an ack request to be triggered!")
+		t.Error("Expected an ack to be queued!")
 	}
 }
 
 func TestBatchMessageIDWithAckTracker(t *testing.T) {
-	eventsCh := make(chan interface{}, 1)
+	ackNotifyCh := make(chan struct{}, 1)
 	pc := partitionConsumer{
 		queueCh:              make(chan []*message, 1),
-		eventsCh:             eventsCh,
+		ackNotifyCh:          ackNotifyCh,
 		compressionProviders: make(map[pb.CompressionType]compression.Provider),
 		options:              &partitionConsumerOpts{},
 		metrics:              internal.NewMetricsProvider(map[string]string{}).GetTopicMetrics("topic"),
@@ -117,7 +789,7 @@ func TestBatchMessageIDWithAckTracker(t *testing.T) {
 	}
 
 	select {
-	case <-eventsCh:
+	case <-ackNotifyCh:
 		t.Error("The message id should not be acked!")
 	default:
 	}
@@ -126,10 +798,80 @@ func TestBatchMessageIDWithAckTracker(t *testing.T) {
 	pc.AckID(messages[9].msgID.(trackingMessageID))
 
 	select {
-	case <-eventsCh:
+	case <-ackNotifyCh:
 	default:
-		t.Error("Expected an ack request to be triggered!")
+		t.Error("Expected an ack to be queued!")
+	}
+}
+
+func TestAcksAreQueuedWithoutBlocking(t *testing.T) {
+	ackNotifyCh := make(chan struct{}, 1)
+	pc := partitionConsumer{
+		queueCh:              make(chan []*message, 1),
+		ackNotifyCh:          ackNotifyCh,
+		compressionProviders: make(map[pb.CompressionType]compression.Provider),
+		options:              &partitionConsumerOpts{},
+		metrics:              internal.NewMetricsProvider(map[string]string{}).GetTopicMetrics("topic"),
 	}
+
+	// nothing is consuming the notifications, the acks must not block anyway
+	for i := 0; i < 100; i++ {
+		pc.AckID(newTrackingMessageID(1, int64(i), 0, 0, nil))
+	}
+
+	assert.Equal(t, 1, len(ackNotifyCh))
+	assert.Equal(t, 100, len(pc.pendingAcks))
+	assert.Equal(t, uint64(99), pc.pendingAcks[99].GetEntryId())
+}
+
+func TestConsumerStateTransitions(t *testing.T) {
+	pc := partitionConsumer{}
+	pc.setConsumerState(consumerReady)
+
+	assert.True(t, pc.casConsumerState(consumerReady, consumerClosing))
+	assert.False(t, pc.casConsumerState(consumerReady, consumerClosing))
+	assert.True(t, pc.isClosed())
+}
+
+func TestClosedConsumerRejectsRequests(t *testing.T) {
+	pc := partitionConsumer{
+		eventsCh: make(chan interface{}),
+		closeCh:  make(chan struct{}),
+		log:      log.DefaultNopLogger(),
+		metrics:  internal.NewMetricsProvider(map[string]string{}).GetTopicMetrics("topic"),
+	}
+	pc.setConsumerState(consumerClosed)
+
+	ctx := context.Background()
+	assert.Equal(t, ErrConsumerClosed, pc.Seek(ctx, newTrackingMessageID(1, 2, 0, 0, nil)))
+	assert.Equal(t, ErrConsumerClosed, pc.SeekByTime(ctx, time.Now()))
+	assert.Equal(t, ErrConsumerClosed, pc.Unsubscribe(ctx))
+	_, err := pc.getLastMessageID()
+	assert.Equal(t, ErrConsumerClosed, err)
+
+	// nothing processes the events, the request must not block once the consumer gets closed
+	pc.setConsumerState(consumerReady)
+	close(pc.closeCh)
+	assert.Equal(t, ErrConsumerClosed, pc.Seek(ctx, newTrackingMessageID(1, 2, 0, 0, nil)))
+}
+
+func TestRequestsReturnOnContextDone(t *testing.T) {
+	pc := partitionConsumer{
+		eventsCh: make(chan interface{}, 1),
+		closeCh:  make(chan struct{}),
+		log:      log.DefaultNopLogger(),
+		metrics:  internal.NewMetricsProvider(map[string]string{}).GetTopicMetrics("topic"),
+	}
+	pc.setConsumerState(consumerReady)
+
+	// the request is queued but nothing processes it
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, pc.Seek(ctx, newTrackingMessageID(1, 2, 0, 0, nil)))
+	assert.Equal(t, 1, len(pc.eventsCh))
+
+	// the events channel is now full
+	assert.Equal(t, context.DeadlineExceeded, pc.Unsubscribe(ctx))
 }
 
 // Raw single message in old format
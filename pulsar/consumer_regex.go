@@ -42,7 +42,8 @@ type regexConsumer struct {
 
 	options ConsumerOptions
 
-	messageCh chan ConsumerMessage
+	messageCh      chan ConsumerMessage
+	closeMessageCh bool
 
 	namespace string
 	pattern   *regexp.Regexp
@@ -52,8 +53,9 @@ type regexConsumer struct {
 	subscribeCh   chan []string
 	unsubscribeCh chan []string
 
-	closeOnce sync.Once
-	closeCh   chan struct{}
+	closeOnce     sync.Once
+	closeCh       chan struct{}
+	monitorDoneCh chan struct{}
 
 	ticker *time.Ticker
 
@@ -62,14 +64,15 @@ type regexConsumer struct {
 	consumerName string
 }
 
-func newRegexConsumer(c *client, opts ConsumerOptions, tn *internal.TopicName, pattern *regexp.Regexp,
-	msgCh chan ConsumerMessage, dlq *dlqRouter, rlq *retryRouter) (Consumer, error) {
+func newRegexConsumer(ctx context.Context, c *client, opts ConsumerOptions, tn *internal.TopicName,
+	pattern *regexp.Regexp, msgCh chan ConsumerMessage, dlq *dlqRouter, rlq *retryRouter) (Consumer, error) {
 	rc := &regexConsumer{
-		client:    c,
-		dlq:       dlq,
-		rlq:       rlq,
-		options:   opts,
-		messageCh: msgCh,
+		client:         c,
+		dlq:            dlq,
+		rlq:            rlq,
+		options:        opts,
+		messageCh:      msgCh,
+		closeMessageCh: opts.MessageChannel == nil,
 
 		namespace: tn.Namespace,
 		pattern:   pattern,
@@ -78,7 +81,8 @@ func newRegexConsumer(c *client, opts ConsumerOptions, tn *internal.TopicName, p
 		subscribeCh:   make(chan []string, 1),
 		unsubscribeCh: make(chan []string, 1),
 
-		closeCh: make(chan struct{}),
+		closeCh:       make(chan struct{}),
+		monitorDoneCh: make(chan struct{}),
 
 		log:          c.log.SubLogger(log.Fields{"topic": tn.Name}),
 		consumerName: opts.Name,
@@ -90,7 +94,7 @@ func newRegexConsumer(c *client, opts ConsumerOptions, tn *internal.TopicName, p
 	}
 
 	var errs error
-	for ce := range subscriber(c, topics, opts, msgCh, dlq, rlq) {
+	for ce := range subscriber(ctx, c, topics, opts, msgCh, dlq, rlq) {
 		if ce.err != nil {
 			errs = pkgerrors.Wrapf(ce.err, "unable to subscribe to topic=%s", ce.topic)
 		} else {
@@ -122,12 +126,16 @@ func (c *regexConsumer) Subscription() string {
 }
 
 func (c *regexConsumer) Unsubscribe() error {
+	return c.UnsubscribeWithCtx(context.Background())
+}
+
+func (c *regexConsumer) UnsubscribeWithCtx(ctx context.Context) error {
 	var errs error
 	c.consumersLock.Lock()
 	defer c.consumersLock.Unlock()
 
 	for topic, consumer := range c.consumers {
-		if err := consumer.Unsubscribe(); err != nil {
+		if err := consumer.UnsubscribeWithCtx(ctx); err != nil {
 			msg := fmt.Sprintf("unable to unsubscribe from topic=%s subscription=%s",
 				topic, c.Subscription())
 			errs = pkgerrors.Wrap(err, msg)
@@ -140,10 +148,13 @@ func (c *regexConsumer) Receive(ctx context.Context) (message Message, err error
 	for {
 		select {
 		case <-c.closeCh:
-			return nil, newError(ConsumerClosed, "consumer closed")
+			return nil, ErrConsumerClosed
 		case cm, ok := <-c.messageCh:
 			if !ok {
-				return nil, newError(ConsumerClosed, "consumer closed")
+				return nil, ErrConsumerClosed
+			}
+			if seekedPast(cm) {
+				continue
 			}
 			return cm.Message, nil
 		case <-ctx.Done():
@@ -160,6 +171,23 @@ func (c *regexConsumer) Chan() <-chan ConsumerMessage {
 // Ack the consumption of a single message
 func (c *regexConsumer) Ack(msg Message) {
 	c.AckID(msg.ID())
+	msg.Release()
+}
+
+// ExtendAckDeadline postpones until d from now the redelivery of the unacked message after the ack timeout
+func (c *regexConsumer) ExtendAckDeadline(msgID MessageID, d time.Duration) error {
+	mid, ok := toTrackingMessageID(msgID)
+	if !ok {
+		c.log.Warnf("invalid message id type %T", msgID)
+		return newError(InvalidMessage, "invalid message id")
+	}
+
+	if mid.consumer == nil {
+		c.log.Warnf("unable to extend the ack deadline of messageID=%+v can not determine topic", msgID)
+		return newError(InvalidMessage, "unable to determine the topic of the message")
+	}
+
+	return mid.consumer.ExtendAckDeadline(mid, d)
 }
 
 func (c *regexConsumer) ReconsumeLater(msg Message, delay time.Duration) {
@@ -182,6 +210,63 @@ func (c *regexConsumer) AckID(msgID MessageID) {
 	mid.Ack()
 }
 
+// AckIDList acknowledges the consumption of the messages, the acks of each topic partition are sent together
+func (c *regexConsumer) AckIDList(msgIDs []MessageID) error {
+	return ackIDList(msgIDs, c.trackingMessageID)
+}
+
+// trackingMessageID returns the tracking id of a message received by the consumer
+func (c *regexConsumer) trackingMessageID(msgID MessageID) (trackingMessageID, error) {
+	mid, ok := toTrackingMessageID(msgID)
+	if !ok {
+		c.log.Warnf("invalid message id type %T", msgID)
+		return mid, newError(InvalidMessage, "invalid message id")
+	}
+	if mid.consumer == nil {
+		c.log.Warnf("unable to ack messageID=%+v can not determine topic", msgID)
+		return mid, newError(InvalidMessage, "unable to determine the topic of the message")
+	}
+	return mid, nil
+}
+
+// AckWithTxn acknowledges the consumption of a single message within the transaction
+func (c *regexConsumer) AckWithTxn(msg Message, txn Transaction) error {
+	mid, ok := toTrackingMessageID(msg.ID())
+	if !ok {
+		c.log.Warnf("invalid message id type %T", msg.ID())
+		return newError(InvalidMessage, "invalid message id")
+	}
+
+	if mid.consumer == nil {
+		c.log.Warnf("unable to ack messageID=%+v can not determine topic", msg.ID())
+		return newError(InvalidMessage, "unable to determine the topic of the message")
+	}
+
+	return mid.consumer.AckIDWithTxn(mid, txn)
+}
+
+// AckCumulative acknowledges the consumption of all the messages of the topic partition up to and including the message
+func (c *regexConsumer) AckCumulative(msg Message) error {
+	return c.AckIDCumulative(msg.ID())
+}
+
+// AckIDCumulative acknowledges the consumption of all the messages of the topic partition up to and including the
+// message identified by its MessageID
+func (c *regexConsumer) AckIDCumulative(msgID MessageID) error {
+	mid, ok := toTrackingMessageID(msgID)
+	if !ok {
+		c.log.Warnf("invalid message id type %T", msgID)
+		return newError(InvalidMessage, "invalid message id")
+	}
+
+	if mid.consumer == nil {
+		c.log.Warnf("unable to ack messageID=%+v can not determine topic", msgID)
+		return newError(InvalidMessage, "unable to determine the topic of the message")
+	}
+
+	return mid.consumer.AckIDCumulative(mid)
+}
+
 func (c *regexConsumer) Nack(msg Message) {
 	c.NackID(msg.ID())
 }
@@ -205,6 +290,8 @@ func (c *regexConsumer) Close() {
 	c.closeOnce.Do(func() {
 		c.ticker.Stop()
 		close(c.closeCh)
+		// the consumers subscribed by the monitor are known once it returns
+		<-c.monitorDoneCh
 
 		var wg sync.WaitGroup
 		c.consumersLock.Lock()
@@ -217,11 +304,18 @@ func (c *regexConsumer) Close() {
 			}(con)
 		}
 		wg.Wait()
+		if c.closeMessageCh {
+			close(c.messageCh)
+		}
 		c.dlq.close()
 		c.rlq.close()
 	})
 }
 
+func (c *regexConsumer) CloseWithCtx(ctx context.Context) error {
+	return runWithCtx(ctx, c.Close)
+}
+
 func (c *regexConsumer) Seek(msgID MessageID) error {
 	return newError(SeekFailed, "seek command not allowed for regex consumer")
 }
@@ -230,11 +324,91 @@ func (c *regexConsumer) SeekByTime(time time.Time) error {
 	return newError(SeekFailed, "seek command not allowed for regex consumer")
 }
 
+func (c *regexConsumer) SeekWithCtx(ctx context.Context, msgID MessageID) error {
+	return c.Seek(msgID)
+}
+
+func (c *regexConsumer) SeekByTimeWithCtx(ctx context.Context, time time.Time) error {
+	return c.SeekByTime(time)
+}
+
 // Name returns the name of consumer.
 func (c *regexConsumer) Name() string {
 	return c.consumerName
 }
 
+func (c *regexConsumer) connectionHolders() []connectionHolder {
+	c.consumersLock.Lock()
+	defer c.consumersLock.Unlock()
+	holders := make([]connectionHolder, 0, len(c.consumers))
+	for _, consumer := range c.consumers {
+		holders = append(holders, consumer)
+	}
+	return holders
+}
+
+func (c *regexConsumer) BrokerAddress() string {
+	return brokerAddresses(c.connectionHolders())
+}
+
+func (c *regexConsumer) ConnectedSince() time.Time {
+	return connectedSince(c.connectionHolders())
+}
+
+func (c *regexConsumer) LastDisconnectedTimestamp() time.Time {
+	return lastDisconnectedTimestamp(c.connectionHolders())
+}
+
+func (c *regexConsumer) UnackedMessages() int {
+	c.consumersLock.Lock()
+	defer c.consumersLock.Unlock()
+	n := 0
+	for _, consumer := range c.consumers {
+		n += consumer.UnackedMessages()
+	}
+	return n
+}
+
+func (c *regexConsumer) OldestUnackedMessageAge() time.Duration {
+	c.consumersLock.Lock()
+	defer c.consumersLock.Unlock()
+	var oldest time.Duration
+	for _, consumer := range c.consumers {
+		if age := consumer.OldestUnackedMessageAge(); age > oldest {
+			oldest = age
+		}
+	}
+	return oldest
+}
+
+func (c *regexConsumer) ActiveConsumerChanges() int {
+	c.consumersLock.Lock()
+	defer c.consumersLock.Unlock()
+	n := 0
+	for _, consumer := range c.consumers {
+		n += consumer.ActiveConsumerChanges()
+	}
+	return n
+}
+
+func (c *regexConsumer) LastActiveConsumerChange() time.Time {
+	c.consumersLock.Lock()
+	defer c.consumersLock.Unlock()
+	var last time.Time
+	for _, consumer := range c.consumers {
+		if t := consumer.LastActiveConsumerChange(); t.After(last) {
+			last = t
+		}
+	}
+	return last
+}
+
+func (c *regexConsumer) Partitions() []string {
+	c.consumersLock.Lock()
+	defer c.consumersLock.Unlock()
+	return consumersPartitions(c.consumers)
+}
+
 func (c *regexConsumer) closed() bool {
 	select {
 	case <-c.closeCh:
@@ -245,6 +419,7 @@ func (c *regexConsumer) closed() bool {
 }
 
 func (c *regexConsumer) monitor() {
+	defer close(c.monitorDoneCh)
 	for {
 		select {
 		case <-c.closeCh:
@@ -303,7 +478,7 @@ func (c *regexConsumer) knownTopics() []string {
 func (c *regexConsumer) subscribe(topics []string, dlq *dlqRouter, rlq *retryRouter) {
 	c.log.WithField("topics", topics).Debug("subscribe")
 	consumers := make(map[string]Consumer, len(topics))
-	for ce := range subscriber(c.client, topics, c.options, c.messageCh, dlq, rlq) {
+	for ce := range subscriber(context.Background(), c.client, topics, c.options, c.messageCh, dlq, rlq) {
 		if ce.err != nil {
 			c.log.Warnf("Failed to subscribe to topic=%s", ce.topic)
 		} else {
@@ -312,10 +487,14 @@ func (c *regexConsumer) subscribe(topics []string, dlq *dlqRouter, rlq *retryRou
 	}
 
 	c.consumersLock.Lock()
-	defer c.consumersLock.Unlock()
 	for t, consumer := range consumers {
 		c.consumers[t] = consumer
 	}
+	c.consumersLock.Unlock()
+
+	if added := consumersPartitions(consumers); len(added) > 0 && c.options.PartitionsChangedHandler != nil {
+		c.options.PartitionsChangedHandler(added, nil)
+	}
 }
 
 func (c *regexConsumer) unsubscribe(topics []string) {
@@ -332,6 +511,7 @@ func (c *regexConsumer) unsubscribe(topics []string) {
 	}
 	c.consumersLock.Unlock()
 
+	removed := consumersPartitions(consumers)
 	for t, consumer := range consumers {
 		c.log.Debugf("unsubscribe from topic=%s subscription=%s", t, c.options.SubscriptionName)
 		if err := consumer.Unsubscribe(); err != nil {
@@ -340,6 +520,10 @@ func (c *regexConsumer) unsubscribe(topics []string) {
 		}
 		consumer.Close()
 	}
+
+	if len(removed) > 0 && c.options.PartitionsChangedHandler != nil {
+		c.options.PartitionsChangedHandler(nil, removed)
+	}
 }
 
 func (c *regexConsumer) topics() ([]string, error) {
@@ -358,7 +542,7 @@ type consumerError struct {
 	consumer Consumer
 }
 
-func subscriber(c *client, topics []string, opts ConsumerOptions, ch chan ConsumerMessage,
+func subscriber(ctx context.Context, c *client, topics []string, opts ConsumerOptions, ch chan ConsumerMessage,
 	dlq *dlqRouter, rlq *retryRouter) <-chan consumerError {
 	consumerErrorCh := make(chan consumerError, len(topics))
 	var wg sync.WaitGroup
@@ -371,7 +555,7 @@ func subscriber(c *client, topics []string, opts ConsumerOptions, ch chan Consum
 	for _, t := range topics {
 		go func(topic string) {
 			defer wg.Done()
-			c, err := newInternalConsumer(c, opts, topic, ch, dlq, rlq, true)
+			c, err := newInternalConsumer(ctx, c, opts, topic, ch, dlq, rlq, true)
 			consumerErrorCh <- consumerError{
 				err:      err,
 				topic:    topic,
@@ -19,15 +19,18 @@ package pulsar
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"sort"
 	"strconv"
 	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/apache/pulsar-client-go/pulsar/internal"
+	"github.com/apache/pulsar-client-go/pulsar/pulsartest"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 )
@@ -116,7 +119,37 @@ func TestConsumerConnectError(t *testing.T) {
 	assert.Nil(t, consumer)
 	assert.NotNil(t, err)
 
-	assert.Equal(t, err.Error(), "connection error")
+	var e *Error
+	if assert.True(t, errors.As(err, &e)) {
+		assert.Equal(t, ConnectError, e.Result())
+	}
+	assert.True(t, errors.Is(err, internal.ErrConnectionFailed))
+}
+
+func TestConsumerGeneratedName(t *testing.T) {
+	client, err := NewClient(ClientOptions{
+		URL: lookupURL,
+	})
+	assert.Nil(t, err)
+	defer client.Close()
+
+	topic := newTopicName()
+	consumer1, err := client.Subscribe(ConsumerOptions{
+		Topic:            topic,
+		SubscriptionName: "sub-1",
+	})
+	assert.Nil(t, err)
+	defer consumer1.Close()
+
+	consumer2, err := client.Subscribe(ConsumerOptions{
+		Topic:            topic,
+		SubscriptionName: "sub-2",
+	})
+	assert.Nil(t, err)
+	defer consumer2.Close()
+
+	assert.NotEmpty(t, consumer1.Name())
+	assert.NotEqual(t, consumer1.Name(), consumer2.Name())
 }
 
 func TestBatchMessageReceive(t *testing.T) {
@@ -204,6 +237,73 @@ func TestConsumerWithInvalidConf(t *testing.T) {
 	assert.NotNil(t, err)
 
 	assert.Equal(t, err.(*Error).Result(), TopicNotFound)
+
+	consumer, err = client.Subscribe(ConsumerOptions{
+		Topic:                       "my-topic",
+		SubscriptionName:            "my-subscription",
+		SubscriptionInitialPosition: SubscriptionInitialPosition(10),
+	})
+
+	// Expect error in creating consumer
+	assert.Nil(t, consumer)
+	assert.NotNil(t, err)
+
+	assert.Equal(t, err.(*Error).Result(), InvalidConfiguration)
+}
+
+func TestConsumerResetSubscriptionPosition(t *testing.T) {
+	client, err := NewClient(ClientOptions{
+		URL: lookupURL,
+	})
+	assert.Nil(t, err)
+	defer client.Close()
+
+	topicName := newTopicName()
+	subName := "test-subscription-reset-position"
+
+	// create the subscription at the latest position before producing
+	consumer, err := client.Subscribe(ConsumerOptions{
+		Topic:            topicName,
+		SubscriptionName: subName,
+	})
+	assert.Nil(t, err)
+
+	producer, err := client.CreateProducer(ProducerOptions{
+		Topic: topicName,
+	})
+	assert.Nil(t, err)
+	defer producer.Close()
+
+	for i := 0; i < 10; i++ {
+		_, err := producer.Send(context.Background(), &ProducerMessage{
+			Payload: []byte(fmt.Sprintf("msg-content-%d", i)),
+		})
+		assert.Nil(t, err)
+	}
+
+	// acknowledge everything so that the existing cursor is at the end of the topic
+	for i := 0; i < 10; i++ {
+		msg, err := consumer.Receive(context.Background())
+		assert.Nil(t, err)
+		consumer.Ack(msg)
+	}
+	consumer.Close()
+
+	// the earliest position moves the existing cursor back to the first message
+	consumer, err = client.Subscribe(ConsumerOptions{
+		Topic:                       topicName,
+		SubscriptionName:            subName,
+		SubscriptionInitialPosition: SubscriptionPositionEarliest,
+		ResetSubscriptionPosition:   true,
+	})
+	assert.Nil(t, err)
+	defer consumer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	msg, err := consumer.Receive(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, "msg-content-0", string(msg.Payload()))
 }
 
 func TestConsumerSubscriptionEarliestPosition(t *testing.T) {
@@ -622,6 +722,69 @@ func TestConsumerAck(t *testing.T) {
 	}
 }
 
+func TestConsumerAckCumulative(t *testing.T) {
+	client, err := NewClient(ClientOptions{
+		URL: lookupURL,
+	})
+	assert.Nil(t, err)
+	defer client.Close()
+
+	topicName := newTopicName()
+	subName := "test-subscription-ack-cumulative"
+
+	consumer, err := client.Subscribe(ConsumerOptions{
+		Topic:            topicName,
+		SubscriptionName: subName,
+	})
+	assert.Nil(t, err)
+
+	producer, err := client.CreateProducer(ProducerOptions{
+		Topic:           topicName,
+		DisableBatching: true,
+	})
+	assert.Nil(t, err)
+	defer producer.Close()
+
+	for i := 0; i < 10; i++ {
+		_, err := producer.Send(context.Background(), &ProducerMessage{
+			Payload: []byte(fmt.Sprintf("msg-content-%d", i)),
+		})
+		assert.Nil(t, err)
+	}
+
+	// ack the first 5 messages at once
+	for i := 0; i < 5; i++ {
+		msg, err := consumer.Receive(context.Background())
+		assert.Nil(t, err)
+		if i == 4 {
+			assert.Nil(t, consumer.AckCumulative(msg))
+		}
+	}
+	consumer.Close()
+
+	consumer, err = client.Subscribe(ConsumerOptions{
+		Topic:            topicName,
+		SubscriptionName: subName,
+	})
+	assert.Nil(t, err)
+	defer consumer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	msg, err := consumer.Receive(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, "msg-content-5", string(msg.Payload()))
+
+	shared, err := client.Subscribe(ConsumerOptions{
+		Topic:            topicName,
+		SubscriptionName: "test-subscription-ack-cumulative-shared",
+		Type:             Shared,
+	})
+	assert.Nil(t, err)
+	defer shared.Close()
+	assert.Equal(t, ErrCumulativeAckNotAllowed, shared.AckCumulative(msg))
+}
+
 func TestConsumerNack(t *testing.T) {
 	client, err := NewClient(ClientOptions{
 		URL: lookupURL,
@@ -1048,6 +1211,65 @@ func TestDLQ(t *testing.T) {
 	assert.Nil(t, msg)
 }
 
+func TestDLQInitialSubscription(t *testing.T) {
+	client, err := NewClient(ClientOptions{
+		URL: lookupURL,
+	})
+	assert.Nil(t, err)
+	defer client.Close()
+
+	dlqTopic := newTopicName()
+	topic := newTopicName()
+	ctx := context.Background()
+
+	consumer, err := client.Subscribe(ConsumerOptions{
+		Topic:               topic,
+		SubscriptionName:    "my-sub",
+		NackRedeliveryDelay: 1 * time.Second,
+		Type:                Shared,
+		DLQ: &DLQPolicy{
+			MaxDeliveries:           1,
+			DeadLetterTopic:         dlqTopic,
+			InitialSubscriptionName: "dlq-init",
+		},
+	})
+	assert.Nil(t, err)
+	defer consumer.Close()
+
+	producer, err := client.CreateProducer(ProducerOptions{
+		Topic: topic,
+	})
+	assert.Nil(t, err)
+	defer producer.Close()
+
+	_, err = producer.Send(ctx, &ProducerMessage{
+		Payload: []byte("hello"),
+	})
+	assert.Nil(t, err)
+
+	msg, err := consumer.Receive(ctx)
+	assert.Nil(t, err)
+	consumer.Nack(msg)
+
+	// wait for the message to be routed to the DLQ before any consumer subscribes to it
+	time.Sleep(3 * time.Second)
+
+	// the initial subscription retained the message even though it was subscribed from the latest position
+	dlqConsumer, err := client.Subscribe(ConsumerOptions{
+		Topic:                       dlqTopic,
+		SubscriptionName:            "dlq-init",
+		SubscriptionInitialPosition: SubscriptionPositionLatest,
+	})
+	assert.Nil(t, err)
+	defer dlqConsumer.Close()
+
+	receiveCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	msg, err = dlqConsumer.Receive(receiveCtx)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("hello"), msg.Payload())
+}
+
 func TestDLQMultiTopics(t *testing.T) {
 	client, err := NewClient(ClientOptions{
 		URL: lookupURL,
@@ -2003,3 +2225,495 @@ func TestConsumerKeySharedWithOrderingKey(t *testing.T) {
 	)
 	assert.Equal(t, 100, receivedConsumer1+receivedConsumer2)
 }
+
+func TestPartitionReceiverQueueSize(t *testing.T) {
+	c := &consumer{options: ConsumerOptions{ReceiverQueueSize: 1000}}
+	assert.Equal(t, 1000, c.partitionReceiverQueueSize(100))
+
+	c.options.MaxTotalReceiverQueueSizeAcrossPartitions = 50000
+	assert.Equal(t, 1000, c.partitionReceiverQueueSize(1))
+	assert.Equal(t, 1000, c.partitionReceiverQueueSize(10))
+	assert.Equal(t, 500, c.partitionReceiverQueueSize(100))
+
+	// every partition keeps receiving messages
+	c.options.MaxTotalReceiverQueueSizeAcrossPartitions = 10
+	assert.Equal(t, 1, c.partitionReceiverQueueSize(100))
+}
+
+func TestConsumerChanClosedOnClose(t *testing.T) {
+	client, err := NewClient(ClientOptions{
+		URL: lookupURL,
+	})
+	assert.Nil(t, err)
+	defer client.Close()
+
+	topicName := newTopicName()
+	consumer, err := client.Subscribe(ConsumerOptions{
+		Topic:            topicName,
+		SubscriptionName: "my-sub",
+	})
+	assert.Nil(t, err)
+
+	appCh := make(chan ConsumerMessage, 1)
+	appConsumer, err := client.Subscribe(ConsumerOptions{
+		Topic:            topicName,
+		SubscriptionName: "my-other-sub",
+		MessageChannel:   appCh,
+	})
+	assert.Nil(t, err)
+
+	consumer.Close()
+	appConsumer.Close()
+
+	// the channel created by the consumer is closed with it
+	_, ok := <-consumer.Chan()
+	assert.False(t, ok)
+	_, err = consumer.Receive(context.Background())
+	assert.Equal(t, ErrConsumerClosed, err)
+
+	// the channel of the application is left open
+	select {
+	case _, ok := <-appCh:
+		assert.True(t, ok)
+	default:
+	}
+}
+
+func TestTopicSchemas(t *testing.T) {
+	schemas, err := topicSchemas(map[string]Schema{
+		"my-topic": NewStringSchema(nil),
+		"persistent://my-tenant/my-ns/my-topic-2": NewBytesSchema(nil),
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(schemas))
+	assert.IsType(t, &StringSchema{}, schemas["persistent://public/default/my-topic"])
+	assert.IsType(t, &BytesSchema{}, schemas["persistent://my-tenant/my-ns/my-topic-2"])
+
+	_, err = topicSchemas(map[string]Schema{"invalid://topic": NewStringSchema(nil)})
+	assert.NotNil(t, err)
+}
+
+func TestConsumerSeekSentinels(t *testing.T) {
+	broker, err := pulsartest.NewBroker()
+	assert.Nil(t, err)
+	defer broker.Close()
+	client, err := NewClient(ClientOptions{URL: broker.URL()})
+	assert.Nil(t, err)
+	defer client.Close()
+
+	topic := newTopicName()
+	assert.Nil(t, broker.CreatePartitionedTopic(topic, 2))
+	producer, err := client.CreateProducer(ProducerOptions{Topic: topic, DisableBatching: true})
+	assert.Nil(t, err)
+	defer producer.Close()
+	consumer, err := client.Subscribe(ConsumerOptions{
+		Topic:                       topic,
+		SubscriptionName:            "my-sub",
+		SubscriptionInitialPosition: SubscriptionPositionEarliest,
+	})
+	assert.Nil(t, err)
+	defer consumer.Close()
+
+	ctx := context.Background()
+	receiveAll := func(n int) {
+		for i := 0; i < n; i++ {
+			ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+			msg, err := consumer.Receive(ctx)
+			cancel()
+			if !assert.Nil(t, err) {
+				return
+			}
+			consumer.Ack(msg)
+		}
+	}
+	for i := 0; i < 6; i++ {
+		_, err := producer.Send(ctx, &ProducerMessage{Payload: []byte(fmt.Sprintf("msg-%d", i))})
+		assert.Nil(t, err)
+	}
+	receiveAll(6)
+
+	// the earliest id moves every partition back to its first message
+	assert.Nil(t, consumer.Seek(EarliestMessageID()))
+	receiveAll(6)
+
+	// nothing is received after seeking the latest id but the messages published later
+	assert.Nil(t, consumer.Seek(LatestMessageID()))
+	timeoutCtx, cancel := context.WithTimeout(ctx, 200*time.Millisecond)
+	_, err = consumer.Receive(timeoutCtx)
+	cancel()
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	_, err = producer.Send(ctx, &ProducerMessage{Payload: []byte("msg-6")})
+	assert.Nil(t, err)
+	timeoutCtx, cancel = context.WithTimeout(ctx, 5*time.Second)
+	msg, err := consumer.Receive(timeoutCtx)
+	cancel()
+	if assert.Nil(t, err) {
+		assert.Equal(t, "msg-6", string(msg.Payload()))
+	}
+}
+
+func TestConsumerFlowPermitStrategy(t *testing.T) {
+	assert.False(t, HalfQueueFlowPermits()(4, 10))
+	assert.True(t, HalfQueueFlowPermits()(5, 10))
+	assert.True(t, PerMessageFlowPermits()(1, 10))
+
+	broker, err := pulsartest.NewBroker()
+	assert.Nil(t, err)
+	defer broker.Close()
+	client, err := NewClient(ClientOptions{URL: broker.URL()})
+	assert.Nil(t, err)
+	defer client.Close()
+
+	topic := newTopicName()
+	var flows int32
+	consumer, err := client.Subscribe(ConsumerOptions{
+		Topic:             topic,
+		SubscriptionName:  "my-sub",
+		ReceiverQueueSize: 5,
+		FlowPermitStrategy: func(availablePermits, receiverQueueSize int) bool {
+			assert.Equal(t, 5, receiverQueueSize)
+			atomic.AddInt32(&flows, 1)
+			return PerMessageFlowPermits()(availablePermits, receiverQueueSize)
+		},
+	})
+	assert.Nil(t, err)
+	defer consumer.Close()
+
+	producer, err := client.CreateProducer(ProducerOptions{Topic: topic, DisableBatching: true})
+	assert.Nil(t, err)
+	defer producer.Close()
+	for i := 0; i < 20; i++ {
+		producer.SendAsync(context.Background(), &ProducerMessage{Payload: []byte(fmt.Sprintf("msg-%d", i))}, nil)
+	}
+	assert.Nil(t, producer.Flush())
+
+	// the queue is refilled message by message
+	for i := 0; i < 20; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		msg, err := consumer.Receive(ctx)
+		cancel()
+		if !assert.Nil(t, err) {
+			return
+		}
+		assert.Equal(t, fmt.Sprintf("msg-%d", i), string(msg.Payload()))
+		consumer.Ack(msg)
+	}
+	assert.True(t, atomic.LoadInt32(&flows) >= 20)
+}
+
+func TestConsumerAckIDList(t *testing.T) {
+	broker, err := pulsartest.NewBroker()
+	assert.Nil(t, err)
+	defer broker.Close()
+	client, err := NewClient(ClientOptions{URL: broker.URL()})
+	assert.Nil(t, err)
+	defer client.Close()
+
+	topic := newTopicName()
+	options := ConsumerOptions{
+		Topic:            topic,
+		SubscriptionName: "my-sub",
+		Type:             Shared,
+	}
+	consumer, err := client.Subscribe(options)
+	assert.Nil(t, err)
+
+	producer, err := client.CreateProducer(ProducerOptions{Topic: topic, DisableBatching: true})
+	assert.Nil(t, err)
+	defer producer.Close()
+	for i := 0; i < 10; i++ {
+		_, err := producer.Send(context.Background(), &ProducerMessage{Payload: []byte(fmt.Sprintf("msg-%d", i))})
+		assert.Nil(t, err)
+	}
+
+	receive := func(consumer Consumer, n int) []MessageID {
+		var ids []MessageID
+		for i := 0; i < n; i++ {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			msg, err := consumer.Receive(ctx)
+			cancel()
+			if !assert.Nil(t, err) {
+				break
+			}
+			ids = append(ids, msg.ID())
+		}
+		return ids
+	}
+
+	ids := receive(consumer, 10)
+	assert.Nil(t, consumer.AckIDList(ids[:5]))
+
+	// the invalid ids are reported, the others are acked
+	err = consumer.AckIDList([]MessageID{EarliestMessageID(), ids[5]})
+	ackErr, ok := err.(AckError)
+	assert.True(t, ok)
+	if assert.Equal(t, 1, len(ackErr)) {
+		assert.Equal(t, EarliestMessageID(), ackErr[0].ID)
+		assert.Equal(t, InvalidMessage, ackErr[0].Err.(*Error).Result())
+		assert.Equal(t, "failed to ack 1 messages: invalid message id: InvalidMessage", ackErr.Error())
+	}
+	consumer.Close()
+
+	// the messages not acked are redelivered
+	consumer, err = client.Subscribe(options)
+	assert.Nil(t, err)
+	defer consumer.Close()
+	ids = receive(consumer, 4)
+	for i, id := range ids {
+		assert.Equal(t, int64(i+6), id.EntryID())
+	}
+	assert.Nil(t, consumer.AckIDList(ids))
+}
+
+func TestConsumerSubscribeWithCtx(t *testing.T) {
+	broker, err := pulsartest.NewBroker()
+	assert.Nil(t, err)
+	defer broker.Close()
+	client, err := NewClient(ClientOptions{URL: broker.URL()})
+	assert.Nil(t, err)
+	defer client.Close()
+
+	topic := newTopicName()
+	assert.Nil(t, broker.CreatePartitionedTopic(topic, 3))
+	options := ConsumerOptions{
+		Topic:            topic,
+		SubscriptionName: "my-sub",
+		Type:             Exclusive,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = client.SubscribeWithCtx(ctx, options)
+	assert.Equal(t, context.Canceled, err)
+
+	// the consumers abandoned on the broker are closed, they don't hold the exclusive subscription
+	assert.Eventually(t, func() bool {
+		consumer, err := client.Subscribe(options)
+		if err != nil {
+			return false
+		}
+		consumer.Close()
+		return true
+	}, 5*time.Second, 100*time.Millisecond)
+}
+
+func TestConsumerEventsQueueSize(t *testing.T) {
+	broker, err := pulsartest.NewBroker()
+	assert.Nil(t, err)
+	defer broker.Close()
+	client, err := NewClient(ClientOptions{URL: broker.URL()})
+	assert.Nil(t, err)
+	defer client.Close()
+
+	topic := newTopicName()
+	c, err := client.Subscribe(ConsumerOptions{Topic: topic, SubscriptionName: "default"})
+	assert.Nil(t, err)
+	defer c.Close()
+	assert.Equal(t, defaultEventsQueueSize, cap(c.(*consumer).consumers[0].eventsCh))
+
+	c, err = client.Subscribe(ConsumerOptions{Topic: topic, SubscriptionName: "sized", EventsQueueSize: 100})
+	assert.Nil(t, err)
+	defer c.Close()
+	assert.Equal(t, 100, cap(c.(*consumer).consumers[0].eventsCh))
+
+	// the seeks go through the events queue
+	assert.Nil(t, c.Seek(EarliestMessageID()))
+}
+
+func TestConsumerDeduplicationWindow(t *testing.T) {
+	broker, err := pulsartest.NewBroker()
+	assert.Nil(t, err)
+	defer broker.Close()
+	client, err := NewClient(ClientOptions{URL: broker.URL()})
+	assert.Nil(t, err)
+	defer client.Close()
+
+	topic := newTopicName()
+	_, err = client.Subscribe(ConsumerOptions{Topic: topic, SubscriptionName: "dedup", DeduplicationWindow: -1})
+	assert.NotNil(t, err)
+
+	c, err := client.Subscribe(ConsumerOptions{
+		Topic:               topic,
+		SubscriptionName:    "dedup",
+		DeduplicationWindow: 10,
+		NackRedeliveryDelay: 100 * time.Millisecond,
+	})
+	assert.Nil(t, err)
+	defer c.Close()
+
+	producer, err := client.CreateProducer(ProducerOptions{Topic: topic, DisableBatching: true})
+	assert.Nil(t, err)
+	defer producer.Close()
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		_, err := producer.Send(ctx, &ProducerMessage{Payload: []byte(fmt.Sprintf("hello-%d", i))})
+		assert.Nil(t, err)
+	}
+
+	msg, err := c.Receive(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, "hello-0", string(msg.Payload()))
+
+	// the broker redelivers the unacked messages after the reconnection before the new one, the first message is
+	// still being processed
+	c.(*consumer).consumers[0].conn.Close()
+	for i := 1; i < 3; i++ {
+		msg, err := c.Receive(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, fmt.Sprintf("hello-%d", i), string(msg.Payload()))
+		c.Ack(msg)
+	}
+	_, err = producer.Send(ctx, &ProducerMessage{Payload: []byte("hello-3")})
+	assert.Nil(t, err)
+	next, err := c.Receive(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, "hello-3", string(next.Payload()))
+	c.Ack(next)
+
+	// the nacked messages are redelivered
+	c.Nack(msg)
+	msg, err = c.Receive(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, "hello-0", string(msg.Payload()))
+	assert.Equal(t, uint32(2), msg.RedeliveryCount())
+}
+
+func TestConsumerPartitions(t *testing.T) {
+	broker, err := pulsartest.NewBroker()
+	assert.Nil(t, err)
+	defer broker.Close()
+	client, err := NewClient(ClientOptions{URL: broker.URL()})
+	assert.Nil(t, err)
+	defer client.Close()
+
+	topic := "persistent://public/default/" + newTopicName()
+	assert.Nil(t, broker.CreatePartitionedTopic(topic, 2))
+	other := "persistent://public/default/" + newTopicName()
+
+	changes := make(chan []string, 10)
+	c, err := client.Subscribe(ConsumerOptions{
+		Topics:              []string{topic, other},
+		SubscriptionName:    "partitions",
+		AutoDiscoveryPeriod: 100 * time.Millisecond,
+		PartitionsChangedHandler: func(added, removed []string) {
+			assert.Empty(t, removed)
+			changes <- added
+		},
+	})
+	assert.Nil(t, err)
+	defer c.Close()
+	partitions := []string{other, topic + "-partition-0", topic + "-partition-1"}
+	sort.Strings(partitions)
+	assert.Equal(t, partitions, c.Partitions())
+
+	// the partitions added to the topic are notified once the consumer subscribed to them
+	assert.Nil(t, broker.UpdatePartitionedTopic(topic, 3))
+	select {
+	case added := <-changes:
+		assert.Equal(t, []string{topic + "-partition-2"}, added)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the added partition wasn't notified")
+	}
+	partitions = append(partitions, topic+"-partition-2")
+	sort.Strings(partitions)
+	assert.Equal(t, partitions, c.Partitions())
+}
+
+func TestConsumerPartitionsSorted(t *testing.T) {
+	broker, err := pulsartest.NewBroker()
+	assert.Nil(t, err)
+	defer broker.Close()
+	client, err := NewClient(ClientOptions{URL: broker.URL()})
+	assert.Nil(t, err)
+	defer client.Close()
+
+	topic := "persistent://public/default/" + newTopicName()
+	assert.Nil(t, broker.CreatePartitionedTopic(topic, 11))
+	changes := make(chan []string, 10)
+	c, err := client.Subscribe(ConsumerOptions{
+		Topic:               topic,
+		SubscriptionName:    "partitions",
+		AutoDiscoveryPeriod: 100 * time.Millisecond,
+		PartitionsChangedHandler: func(added, removed []string) {
+			changes <- added
+		},
+	})
+	assert.Nil(t, err)
+	defer c.Close()
+
+	// "-partition-10" sorts before "-partition-2"
+	var partitions []string
+	for i := 0; i < 11; i++ {
+		partitions = append(partitions, fmt.Sprintf("%s-partition-%d", topic, i))
+	}
+	sort.Strings(partitions)
+	assert.Equal(t, partitions, c.Partitions())
+
+	assert.Nil(t, broker.UpdatePartitionedTopic(topic, 12))
+	select {
+	case added := <-changes:
+		assert.Equal(t, []string{topic + "-partition-11"}, added)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the added partition wasn't notified")
+	}
+}
+
+func TestConsumerNameOfPartitions(t *testing.T) {
+	broker, err := pulsartest.NewBroker()
+	assert.Nil(t, err)
+	defer broker.Close()
+	client, err := NewClient(ClientOptions{URL: broker.URL()})
+	assert.Nil(t, err)
+	defer client.Close()
+
+	topic := newTopicName()
+	assert.Nil(t, broker.CreatePartitionedTopic(topic, 2))
+
+	// every partition subscribes with the name of the consumer
+	c, err := client.Subscribe(ConsumerOptions{Topic: topic, SubscriptionName: "named", Name: "my-consumer"})
+	assert.Nil(t, err)
+	defer c.Close()
+	assert.Equal(t, "my-consumer", c.Name())
+	for _, pc := range c.(*consumer).consumers {
+		assert.Equal(t, "my-consumer", pc.name)
+	}
+
+	c, err = client.Subscribe(ConsumerOptions{Topics: []string{topic, newTopicName()}, SubscriptionName: "generated"})
+	assert.Nil(t, err)
+	defer c.Close()
+	assert.NotEmpty(t, c.Name())
+	for _, tc := range c.(*multiTopicConsumer).consumers {
+		assert.Equal(t, c.Name(), tc.Name())
+	}
+}
+
+func TestConsumerCloseAfterUnsubscribe(t *testing.T) {
+	broker, err := pulsartest.NewBroker()
+	assert.Nil(t, err)
+	defer broker.Close()
+	client, err := NewClient(ClientOptions{URL: broker.URL()})
+	assert.Nil(t, err)
+	defer client.Close()
+
+	topic := newTopicName()
+	producer, err := client.CreateProducer(ProducerOptions{Topic: topic})
+	assert.Nil(t, err)
+	defer producer.Close()
+
+	for i := 0; i < 10; i++ {
+		c, err := client.Subscribe(ConsumerOptions{
+			Topic:                       topic,
+			SubscriptionName:            fmt.Sprintf("sub-%d", i),
+			SubscriptionInitialPosition: SubscriptionPositionEarliest,
+		})
+		assert.Nil(t, err)
+		// the dispatcher is still pushing the messages to the full channel when the consumer is unsubscribed
+		for j := 0; j < 20; j++ {
+			producer.SendAsync(context.Background(), &ProducerMessage{Payload: []byte("hello")}, nil)
+		}
+		assert.Nil(t, producer.Flush())
+		assert.Nil(t, c.Unsubscribe())
+		c.Close()
+	}
+}
@@ -0,0 +1,80 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package pulsar
+
+import (
+	"sync"
+)
+
+// dedupWindow keeps the ids of the last messages delivered by a partition to drop the duplicates the broker pushes
+// again, eg. the unacked messages redelivered after a reconnection
+type dedupWindow struct {
+	sync.Mutex
+	size int
+	// the ids in delivery order, the oldest one is replaced once the window is full
+	ids  []messageID
+	next int
+	// the position in ids of the messages in the window, a forgotten message can be added again at another one
+	set map[messageID]int
+}
+
+func newDedupWindow(size int) *dedupWindow {
+	return &dedupWindow{
+		size: size,
+		set:  make(map[messageID]int, size),
+	}
+}
+
+// add records the delivered message, it returns false when the message is already in the window
+func (w *dedupWindow) add(msgID messageID) bool {
+	w.Lock()
+	defer w.Unlock()
+	if _, dup := w.set[msgID]; dup {
+		return false
+	}
+	pos := w.next
+	if len(w.ids) < w.size {
+		w.ids = append(w.ids, msgID)
+	} else {
+		if evicted := w.ids[pos]; w.set[evicted] == pos {
+			delete(w.set, evicted)
+		}
+		w.ids[pos] = msgID
+	}
+	w.next = (pos + 1) % w.size
+	w.set[msgID] = pos
+	return true
+}
+
+// forget removes the messages whose redelivery was requested by the consumer, so that they aren't dropped
+func (w *dedupWindow) forget(msgIDs ...messageID) {
+	w.Lock()
+	defer w.Unlock()
+	for _, id := range msgIDs {
+		delete(w.set, id)
+	}
+}
+
+// clear empties the window, eg. after a seek when the messages are delivered again on purpose
+func (w *dedupWindow) clear() {
+	w.Lock()
+	defer w.Unlock()
+	w.ids = nil
+	w.next = 0
+	w.set = make(map[messageID]int, w.size)
+}
@@ -0,0 +1,47 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package pulsar
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDedupWindow(t *testing.T) {
+	w := newDedupWindow(3)
+	for i := 0; i < 3; i++ {
+		assert.True(t, w.add(messageID{ledgerID: 1, entryID: int64(i)}))
+	}
+	assert.False(t, w.add(messageID{ledgerID: 1, entryID: 0}))
+
+	// the oldest message leaves the window once it is full
+	assert.True(t, w.add(messageID{ledgerID: 1, entryID: 3}))
+	assert.True(t, w.add(messageID{ledgerID: 1, entryID: 0}))
+	assert.False(t, w.add(messageID{ledgerID: 1, entryID: 3}))
+
+	// a forgotten message added again stays until its new position is replaced
+	w.forget(messageID{ledgerID: 1, entryID: 2})
+	assert.True(t, w.add(messageID{ledgerID: 1, entryID: 2}))
+	assert.False(t, w.add(messageID{ledgerID: 1, entryID: 2}))
+	assert.True(t, w.add(messageID{ledgerID: 1, entryID: 4}))
+	assert.False(t, w.add(messageID{ledgerID: 1, entryID: 2}))
+
+	w.clear()
+	assert.True(t, w.add(messageID{ledgerID: 1, entryID: 4}))
+}
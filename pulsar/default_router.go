@@ -18,7 +18,6 @@
 package pulsar
 
 import (
-	"math"
 	"math/rand"
 	"sync/atomic"
 	"time"
@@ -36,8 +35,20 @@ type defaultRouter struct {
 // Default routing mode is round-robin routing if no partition key is specified.
 // If the batching is enabled, it honors the different thresholds for batching i.e. maximum batch size,
 // maximum number of messages, maximum delay to publish a batch. When one of the threshold is reached the next partition
-// is used.
+// is used. The delay is counted since the router switched to the current partition, a delay spanning several
+// publish delays keeps the partition for several batches.
 func NewDefaultRouter(
+	hashFunc func(string) uint32,
+	maxBatchingMessages uint,
+	maxBatchingSize uint,
+	maxBatchingDelay time.Duration,
+	disableBatching bool) func(*ProducerMessage, uint32) int {
+	return newDefaultRouter(time.Now, hashFunc, maxBatchingMessages, maxBatchingSize, maxBatchingDelay, disableBatching)
+}
+
+// newDefaultRouter returns the default router reading the time of the batching delay from the clock
+func newDefaultRouter(
+	clock func() time.Time,
 	hashFunc func(string) uint32,
 	maxBatchingMessages uint,
 	maxBatchingSize uint,
@@ -45,7 +56,7 @@ func NewDefaultRouter(
 	disableBatching bool) func(*ProducerMessage, uint32) int {
 	state := &defaultRouter{
 		currentPartitionCursor: rand.Uint32(),
-		lastChangeTimestamp:    math.MinInt64,
+		lastChangeTimestamp:    clock().UnixNano(),
 	}
 
 	readClockAfterNumMessages := uint32(maxBatchingMessages / 10)
@@ -88,24 +99,22 @@ func NewDefaultRouter(
 		sizeReached := (size >= uint32(maxBatchingSize)-previousBatchingMaxSize)
 		durationReached := false
 		if readClockAfterNumMessages == 0 || previousMessageCount%readClockAfterNumMessages == 0 {
-			now = time.Now().UnixNano()
+			now = clock().UnixNano()
 			durationReached = now-previousLastChange >= maxBatchingDelay.Nanoseconds()
 		}
 		if messageCountReached || sizeReached || durationReached {
 			atomic.AddUint32(&state.currentPartitionCursor, 1)
 			atomic.StoreUint32(&state.msgCounter, 0)
 			atomic.StoreUint32(&state.cumulativeBatchSize, 0)
-			if now != 0 {
-				atomic.StoreInt64(&state.lastChangeTimestamp, now)
+			if now == 0 {
+				now = clock().UnixNano()
 			}
+			atomic.StoreInt64(&state.lastChangeTimestamp, now)
 			return int(state.currentPartitionCursor % numPartitions)
 		}
 
 		atomic.AddUint32(&state.msgCounter, 1)
 		atomic.AddUint32(&state.cumulativeBatchSize, size)
-		if now != 0 {
-			atomic.StoreInt64(&state.lastChangeTimestamp, now)
-		}
 		return int(state.currentPartitionCursor % numPartitions)
 	}
 }
@@ -66,6 +66,26 @@ func TestDefaultRouterRoutingBecauseMaxPublishDelayReached(t *testing.T) {
 	}
 }
 
+func TestDefaultRouterStickyForTheWholeDelay(t *testing.T) {
+	// the messages come faster than the delay, the partition is switched once the delay since the previous
+	// switch is reached
+	maxPublishDelay := 200 * time.Millisecond
+	now := time.Now()
+	clock := func() time.Time {
+		return now
+	}
+	router := newDefaultRouter(clock, internal.JavaStringHash, 10, 1000, maxPublishDelay, false)
+	const numPartitions = uint32(3)
+	p1 := router(&ProducerMessage{Payload: []byte("message 1")}, numPartitions)
+
+	now = now.Add(maxPublishDelay * 2 / 5)
+	assert.Equal(t, p1, router(&ProducerMessage{Payload: []byte("message 2")}, numPartitions))
+	now = now.Add(maxPublishDelay * 2 / 5)
+	assert.Equal(t, p1, router(&ProducerMessage{Payload: []byte("message 3")}, numPartitions))
+	now = now.Add(maxPublishDelay * 2 / 5)
+	assert.NotEqual(t, p1, router(&ProducerMessage{Payload: []byte("message 4")}, numPartitions))
+}
+
 func TestDefaultRouterRoutingBecauseMaxNumberOfMessagesReached(t *testing.T) {
 	router := NewDefaultRouter(internal.JavaStringHash, 2, 100, oneHourPublishMaxDelay, false)
 	const numPartitions = uint32(3)
@@ -108,7 +128,8 @@ func TestDefaultRouterNoRoutingBecausePartitionKeyIsSpecified(t *testing.T) {
 		Key:     "my-key",
 		Payload: []byte("message 1"),
 	}, 3)
-	assert.Equal(t, 1, p1)
+	// the partition chosen by the Java client for the key
+	assert.Equal(t, 2, p1)
 
 	p2 := router(&ProducerMessage{
 		Key:     "my-key",
@@ -133,6 +133,8 @@ func (r *dlqRouter) getProducer() Producer {
 		return r.producer
 	}
 
+	createInitialSubscription(r.client, r.policy.DeadLetterTopic, r.policy.InitialSubscriptionName, r.log)
+
 	// Retry to create producer indefinitely
 	backoff := &internal.Backoff{}
 	for {
@@ -152,3 +154,23 @@ func (r *dlqRouter) getProducer() Producer {
 		}
 	}
 }
+
+// createInitialSubscription subscribes once to the topic so that the subscription retains the messages sent to the
+// topic until a consumer subscribes to it. It is done on a best effort basis, the failures are only logged.
+func createInitialSubscription(client Client, topic, subscription string, logger log.Logger) {
+	if subscription == "" {
+		return
+	}
+
+	consumer, err := client.Subscribe(ConsumerOptions{
+		Topic:                       topic,
+		SubscriptionName:            subscription,
+		Type:                        Shared,
+		SubscriptionInitialPosition: SubscriptionPositionEarliest,
+	})
+	if err != nil {
+		logger.WithError(err).WithField("subscription", subscription).Warn("Failed to create the initial subscription")
+		return
+	}
+	consumer.Close()
+}
@@ -17,7 +17,14 @@
 
 package pulsar
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/apache/pulsar-client-go/pulsar/internal"
+	pb "github.com/apache/pulsar-client-go/pulsar/internal/pulsar_proto"
+)
 
 // Result used to represent pulsar processing is an alias of type int.
 type Result int
@@ -99,12 +106,35 @@ const (
 	AddToBatchFailed
 	// SeekFailed seek failed
 	SeekFailed
+	// ProducerBusy means a producer with the same name is already connected to the topic
+	ProducerBusy
+	// IncompatibleSchema means the schema is not compatible with the schema of the topic
+	IncompatibleSchema
+	// ConsumerAssignError means the broker failed to assign the consumer
+	ConsumerAssignError
+	// TransactionCoordinatorNotFound means the transaction coordinator is not available
+	TransactionCoordinatorNotFound
+	// InvalidTxnStatus means the transaction is not in the expected status
+	InvalidTxnStatus
+	// SchemaSerializationError means the message value could not be encoded with the producer schema
+	SchemaSerializationError
+	// PayloadCodecError means the message payload could not be encoded or decoded with the payload codec
+	PayloadCodecError
+	// ProducerFenced means the producer lost, or failed to get, the exclusive access to the topic
+	ProducerFenced
 )
 
 // Error implement error interface, composed of two parts: msg and result.
+// Callers can branch on the failure type with errors.As and Result:
+//
+//	var e *pulsar.Error
+//	if errors.As(err, &e) && e.Result() == pulsar.TopicNotFound {
+//	    ...
+//	}
 type Error struct {
 	msg    string
 	result Result
+	err    error
 }
 
 // Result get error's original result.
@@ -116,6 +146,11 @@ func (e *Error) Error() string {
 	return e.msg
 }
 
+// Unwrap returns the underlying error which caused this error, if any.
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
 func newError(result Result, msg string) error {
 	return &Error{
 		msg:    fmt.Sprintf("%s: %s", msg, getResultStr(result)),
@@ -123,6 +158,128 @@ func newError(result Result, msg string) error {
 	}
 }
 
+// wrapError returns an error of the given result caused by err
+func wrapError(result Result, msg string, err error) error {
+	return &Error{
+		msg:    fmt.Sprintf("%s: %s: %s", msg, getResultStr(result), err.Error()),
+		result: result,
+		err:    err,
+	}
+}
+
+// TopicsError is the error of an operation of a multi-topic consumer which failed on some of its topics,
+// the operation took effect on the other topics.
+type TopicsError struct {
+	msg string
+
+	// Errors maps the topics on which the operation failed to their error
+	Errors map[string]error
+}
+
+func (e *TopicsError) Error() string {
+	return e.msg
+}
+
+// newTopicsError returns the error of the operation which failed on some of the topics, or nil if it never did
+func newTopicsError(op string, total int, errs map[string]error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	topics := make([]string, 0, len(errs))
+	for topic := range errs {
+		topics = append(topics, topic)
+	}
+	sort.Strings(topics)
+	msg := fmt.Sprintf("unable to %s %d of %d topics", op, len(errs), total)
+	for _, topic := range topics {
+		msg += fmt.Sprintf("; topic=%s: %v", topic, errs[topic])
+	}
+	return &TopicsError{msg: msg, Errors: errs}
+}
+
+// reconnectFailedError is the error of a producer or consumer which gave up reconnecting to the broker
+func reconnectFailedError(lastErr error) error {
+	if lastErr == nil {
+		return newError(NotConnectedError, "no reconnection to the broker allowed")
+	}
+	return wrapError(NotConnectedError, "gave up reconnecting to the broker", lastErr)
+}
+
+// isRetriableError reports whether the broker failed the request with a transient error,
+// eg. while the topic is being moved to another broker, so that the request may succeed later
+func isRetriableError(err error) bool {
+	if errors.Is(err, internal.ErrConnectionClosed) {
+		// the connection was closed while creating the producer or consumer, the retry uses a new one
+		return true
+	}
+
+	// the error may be wrapped, eg. by the producer or consumer creation
+	var code pb.ServerError
+	var serverErr *internal.ServerError
+	var lookupErr *internal.LookupError
+	switch {
+	case errors.As(err, &serverErr):
+		code = serverErr.Code
+	case errors.As(err, &lookupErr):
+		code = lookupErr.ServerError
+	default:
+		return false
+	}
+	return code == pb.ServerError_ServiceNotReady || code == pb.ServerError_TooManyRequests
+}
+
+// serverErrorResult maps the error code sent by the broker to the matching result
+func serverErrorResult(code pb.ServerError) Result {
+	switch code {
+	case pb.ServerError_MetadataError:
+		return BrokerMetadataError
+	case pb.ServerError_PersistenceError:
+		return BrokerPersistenceError
+	case pb.ServerError_AuthenticationError:
+		return AuthenticationError
+	case pb.ServerError_AuthorizationError:
+		return AuthorizationError
+	case pb.ServerError_ConsumerBusy:
+		return ConsumerBusy
+	case pb.ServerError_ServiceNotReady:
+		return ServiceUnitNotReady
+	case pb.ServerError_ProducerBlockedQuotaExceededError:
+		return ProducerBlockedQuotaExceededError
+	case pb.ServerError_ProducerBlockedQuotaExceededException:
+		return ProducerBlockedQuotaExceededException
+	case pb.ServerError_ChecksumError:
+		return ChecksumError
+	case pb.ServerError_UnsupportedVersionError:
+		return UnsupportedVersionError
+	case pb.ServerError_TopicNotFound:
+		return TopicNotFound
+	case pb.ServerError_SubscriptionNotFound:
+		return SubscriptionNotFound
+	case pb.ServerError_ConsumerNotFound:
+		return ConsumerNotFound
+	case pb.ServerError_TooManyRequests:
+		return TooManyLookupRequestException
+	case pb.ServerError_TopicTerminatedError:
+		return TopicTerminated
+	case pb.ServerError_ProducerBusy:
+		return ProducerBusy
+	case pb.ServerError_InvalidTopicName:
+		return InvalidTopicName
+	case pb.ServerError_IncompatibleSchema:
+		return IncompatibleSchema
+	case pb.ServerError_ConsumerAssignError:
+		return ConsumerAssignError
+	case pb.ServerError_TransactionCoordinatorNotFound:
+		return TransactionCoordinatorNotFound
+	case pb.ServerError_InvalidTxnStatus:
+		return InvalidTxnStatus
+	case pb.ServerError_ProducerFenced:
+		return ProducerFenced
+	default:
+		return UnknownError
+	}
+}
+
 func getResultStr(r Result) string {
 	switch r {
 	case Ok:
@@ -201,6 +358,22 @@ func getResultStr(r Result) string {
 		return "AddToBatchFailed"
 	case SeekFailed:
 		return "SeekFailed"
+	case ProducerBusy:
+		return "ProducerBusy"
+	case IncompatibleSchema:
+		return "IncompatibleSchema"
+	case ConsumerAssignError:
+		return "ConsumerAssignError"
+	case TransactionCoordinatorNotFound:
+		return "TransactionCoordinatorNotFound"
+	case InvalidTxnStatus:
+		return "InvalidTxnStatus"
+	case SchemaSerializationError:
+		return "SchemaSerializationError"
+	case PayloadCodecError:
+		return "PayloadCodecError"
+	case ProducerFenced:
+		return "ProducerFenced"
 	default:
 		return fmt.Sprintf("Result(%d)", r)
 	}
@@ -0,0 +1,171 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package pulsar
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	pkgerrors "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/pulsar-client-go/pulsar/internal"
+	pb "github.com/apache/pulsar-client-go/pulsar/internal/pulsar_proto"
+	"github.com/apache/pulsar-client-go/pulsar/log"
+)
+
+func TestToClientError(t *testing.T) {
+	serverErr := &internal.ServerError{Code: pb.ServerError_TopicNotFound, Message: "topic not found"}
+
+	tests := []struct {
+		err    error
+		result Result
+	}{
+		{serverErr, TopicNotFound},
+		{&internal.ServerError{Code: pb.ServerError_ProducerBusy}, ProducerBusy},
+		{&internal.ServerError{Code: pb.ServerError_AuthorizationError}, AuthorizationError},
+		{&internal.LookupError{ServerError: pb.ServerError_ServiceNotReady}, LookupError},
+		{internal.ErrRequestTimeOut, TimeoutError},
+		{internal.ErrMaxLookupRedirect, LookupError},
+		{internal.ErrConnectionFailed, ConnectError},
+		{internal.ErrConnectionClosed, ConnectError},
+	}
+
+	for _, test := range tests {
+		err := toClientError(test.err, "create producer")
+
+		var e *Error
+		if assert.True(t, errors.As(err, &e)) {
+			assert.Equal(t, test.result, e.Result())
+		}
+		assert.True(t, errors.Is(err, test.err))
+	}
+
+	var cause *internal.ServerError
+	assert.True(t, errors.As(toClientError(serverErr, "subscribe"), &cause))
+	assert.Equal(t, "topic not found", cause.Message)
+}
+
+func TestToClientErrorOfWrappedErrors(t *testing.T) {
+	// the consumers of several topics wrap the subscription errors
+	wrapped := pkgerrors.Wrapf(&internal.ServerError{Code: pb.ServerError_IncompatibleSchema},
+		"unable to subscribe to topic=%s", "my-topic")
+	err := toClientError(wrapped, "subscribe")
+
+	var e *Error
+	if assert.True(t, errors.As(err, &e)) {
+		assert.Equal(t, IncompatibleSchema, e.Result())
+	}
+	assert.Contains(t, err.Error(), "my-topic")
+
+	typed := pkgerrors.Wrap(newError(IncompatibleSchema, "incompatible schema"), "unable to subscribe")
+	assert.Equal(t, typed, toClientError(typed, "subscribe"))
+}
+
+func TestToClientErrorKeepsTypedErrors(t *testing.T) {
+	assert.Nil(t, toClientError(nil, "subscribe"))
+
+	err := newError(InvalidConfiguration, "topic is required")
+	assert.Equal(t, err, toClientError(err, "subscribe"))
+
+	other := errors.New("other")
+	assert.Equal(t, other, toClientError(other, "subscribe"))
+}
+
+func TestIsRetriableError(t *testing.T) {
+	assert.True(t, isRetriableError(&internal.ServerError{Code: pb.ServerError_ServiceNotReady}))
+	assert.True(t, isRetriableError(&internal.ServerError{Code: pb.ServerError_TooManyRequests}))
+	assert.True(t, isRetriableError(&internal.LookupError{ServerError: pb.ServerError_TooManyRequests}))
+	assert.True(t, isRetriableError(internal.ErrConnectionClosed))
+	assert.True(t, isRetriableError(wrapError(ConnectError, "subscribe", internal.ErrConnectionClosed)))
+	assert.True(t, isRetriableError(wrapError(ConnectError, "create producer",
+		&internal.ServerError{Code: pb.ServerError_ServiceNotReady})))
+	assert.True(t, isRetriableError(pkgerrors.Wrap(&internal.LookupError{ServerError: pb.ServerError_TooManyRequests},
+		"lookup")))
+
+	assert.False(t, isRetriableError(nil))
+	assert.False(t, isRetriableError(&internal.ServerError{Code: pb.ServerError_AuthorizationError}))
+	assert.False(t, isRetriableError(&internal.ServerError{Code: pb.ServerError_TopicTerminatedError}))
+	assert.False(t, isRetriableError(&internal.LookupError{ServerError: pb.ServerError_MetadataError}))
+	assert.False(t, isRetriableError(internal.ErrRequestTimeOut))
+}
+
+func TestRetryOnRetriableErrors(t *testing.T) {
+	c := &client{
+		operationTimeout: time.Second,
+		backoffPolicy:    newDefaultBackoff,
+		log:              log.DefaultNopLogger(),
+	}
+
+	attempts := 0
+	err := c.retryOnRetriableErrors(context.Background(), func() error {
+		attempts++
+		if attempts < 3 {
+			return &internal.ServerError{Code: pb.ServerError_ServiceNotReady}
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, attempts)
+
+	// fatal errors are returned right away
+	attempts = 0
+	err = c.retryOnRetriableErrors(context.Background(), func() error {
+		attempts++
+		return &internal.ServerError{Code: pb.ServerError_AuthorizationError}
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 1, attempts)
+
+	// retriable errors are returned once the operation timeout is reached
+	c.operationTimeout = 500 * time.Millisecond
+	start := time.Now()
+	err = c.retryOnRetriableErrors(context.Background(), func() error {
+		return &internal.ServerError{Code: pb.ServerError_TooManyRequests}
+	})
+	assert.True(t, isRetriableError(err))
+	assert.True(t, time.Since(start) < c.operationTimeout)
+
+	// the retries stop once the context is done
+	c.operationTimeout = time.Minute
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start = time.Now()
+	err = c.retryOnRetriableErrors(ctx, func() error {
+		return &internal.ServerError{Code: pb.ServerError_TooManyRequests}
+	})
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.True(t, time.Since(start) < time.Second)
+}
+
+func TestTopicsError(t *testing.T) {
+	assert.Nil(t, newTopicsError("unsubscribe from", 2, map[string]error{}))
+
+	cause := newError(ConsumerNotFound, "consumer not found")
+	err := newTopicsError("unsubscribe from", 3, map[string]error{"topic-b": cause, "topic-a": cause})
+
+	var e *TopicsError
+	if assert.True(t, errors.As(err, &e)) {
+		assert.Equal(t, 2, len(e.Errors))
+		assert.Equal(t, cause, e.Errors["topic-a"])
+	}
+	assert.True(t, strings.HasPrefix(err.Error(), "unable to unsubscribe from 2 of 3 topics; topic=topic-a: "))
+}
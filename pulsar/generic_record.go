@@ -0,0 +1,135 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package pulsar
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// GenericRecord is a message value decoded without knowing its type at compile time, eg. by an AutoConsumeSchema
+type GenericRecord interface {
+	// SchemaType returns the type of the schema the value was decoded with
+	SchemaType() SchemaType
+
+	// Fields returns the names of the fields of a record value, sorted by name
+	Fields() []string
+
+	// Field returns the value of the field, or nil if the record has no such field
+	Field(name string) interface{}
+
+	// NativeObject returns the decoded value: a map[string]interface{} for the AVRO and JSON records,
+	// a Go value for the primitive types and a []byte when the topic has no schema
+	NativeObject() interface{}
+}
+
+type genericRecord struct {
+	schemaType SchemaType
+	fields     map[string]interface{}
+	native     interface{}
+}
+
+func (r *genericRecord) SchemaType() SchemaType {
+	return r.schemaType
+}
+
+func (r *genericRecord) Fields() []string {
+	fields := make([]string, 0, len(r.fields))
+	for name := range r.fields {
+		fields = append(fields, name)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+func (r *genericRecord) Field(name string) interface{} {
+	return r.fields[name]
+}
+
+func (r *genericRecord) NativeObject() interface{} {
+	return r.native
+}
+
+// newGenericRecord decodes the data with the schema
+func newGenericRecord(info *SchemaInfo, data []byte) (GenericRecord, error) {
+	record := &genericRecord{schemaType: info.Type}
+	switch info.Type {
+	case AVRO:
+		codec, err := initAvroCodec(info.Schema)
+		if err != nil {
+			return nil, err
+		}
+		native, _, err := codec.NativeFromBinary(data)
+		if err != nil {
+			return nil, err
+		}
+		record.native = native
+	case JSON:
+		var native interface{}
+		if err := json.Unmarshal(data, &native); err != nil {
+			return nil, err
+		}
+		record.native = native
+	case NONE, BYTES:
+		record.native = data
+	default:
+		native, err := decodePrimitive(info.Type, data)
+		if err != nil {
+			return nil, err
+		}
+		record.native = native
+	}
+
+	if fields, ok := record.native.(map[string]interface{}); ok {
+		record.fields = fields
+	}
+	return record, nil
+}
+
+func decodePrimitive(schemaType SchemaType, data []byte) (interface{}, error) {
+	var schema Schema
+	var v interface{}
+	switch schemaType {
+	case STRING:
+		schema, v = NewStringSchema(nil), new(string)
+	case BOOLEAN:
+		schema, v = NewBooleanSchema(nil), new(bool)
+	case INT8:
+		schema, v = NewInt8Schema(nil), new(int8)
+	case INT16:
+		schema, v = NewInt16Schema(nil), new(int16)
+	case INT32:
+		schema, v = NewInt32Schema(nil), new(int32)
+	case INT64:
+		schema, v = NewInt64Schema(nil), new(int64)
+	case FLOAT:
+		schema, v = NewFloatSchema(nil), new(float32)
+	case DOUBLE:
+		schema, v = NewDoubleSchema(nil), new(float64)
+	default:
+		return nil, newError(OperationNotSupported, fmt.Sprintf("can't decode a generic record of schema type %d",
+			schemaType))
+	}
+
+	if err := schema.Decode(data, v); err != nil {
+		return nil, err
+	}
+	return reflect.ValueOf(v).Elem().Interface(), nil
+}
@@ -0,0 +1,83 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package pulsar
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenericRecordAvro(t *testing.T) {
+	as := NewAvroSchema(exampleSchemaDef, nil)
+	data, err := as.Encode(testAvro{ID: 100, Name: "pulsar"})
+	assert.Nil(t, err)
+
+	record, err := newGenericRecord(as.GetSchemaInfo(), data)
+	assert.Nil(t, err)
+	assert.Equal(t, AVRO, record.SchemaType())
+	assert.Equal(t, []string{"ID", "Name"}, record.Fields())
+	assert.Equal(t, int32(100), record.Field("ID"))
+	assert.Equal(t, "pulsar", record.Field("Name"))
+	assert.Nil(t, record.Field("Unknown"))
+}
+
+func TestGenericRecordJSON(t *testing.T) {
+	js := NewJSONSchema(exampleSchemaDef, nil)
+	data, err := js.Encode(testJSON{ID: 100, Name: "pulsar"})
+	assert.Nil(t, err)
+
+	record, err := newGenericRecord(js.GetSchemaInfo(), data)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"id", "name"}, record.Fields())
+	assert.Equal(t, float64(100), record.Field("id"))
+}
+
+func TestGenericRecordPrimitive(t *testing.T) {
+	record, err := newGenericRecord(NewInt32Schema(nil).GetSchemaInfo(), []byte{0x00, 0x00, 0x01, 0x00})
+	assert.Nil(t, err)
+	assert.Equal(t, int32(256), record.NativeObject())
+	assert.Empty(t, record.Fields())
+
+	record, err = newGenericRecord(&SchemaInfo{Type: NONE}, []byte("raw"))
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("raw"), record.NativeObject())
+}
+
+func TestAutoConsumeSchemaDecodesWithMessageSchema(t *testing.T) {
+	as := NewAvroSchema(exampleSchemaDef, nil)
+	data, err := as.Encode(testAvro{ID: 100, Name: "pulsar"})
+	assert.Nil(t, err)
+
+	var requested []byte
+	schema := NewAutoConsumeSchema().atVersion("my-topic", []byte{0x01},
+		func(topic string, schemaVersion []byte) (*SchemaInfo, error) {
+			assert.Equal(t, "my-topic", topic)
+			requested = schemaVersion
+			return as.GetSchemaInfo(), nil
+		})
+
+	msg := &message{payLoad: data, schema: schema}
+	var record GenericRecord
+	assert.Nil(t, msg.GetSchemaValue(&record))
+	assert.Equal(t, []byte{0x01}, requested)
+	assert.Equal(t, "pulsar", record.Field("Name"))
+
+	var s string
+	assert.NotNil(t, msg.GetSchemaValue(&s))
+}
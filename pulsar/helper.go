@@ -18,10 +18,9 @@
 package pulsar
 
 import (
+	"context"
 	"fmt"
 
-	pkgerrors "github.com/pkg/errors"
-
 	"github.com/gogo/protobuf/proto"
 
 	"github.com/apache/pulsar-client-go/pulsar/internal"
@@ -58,7 +57,7 @@ func validateTopicNames(topics ...string) ([]*internal.TopicName, error) {
 	for i, t := range topics {
 		tn, err := internal.ParseTopicName(t)
 		if err != nil {
-			return nil, pkgerrors.Wrapf(err, "invalid topic name: %s", t)
+			return nil, wrapError(InvalidTopicName, fmt.Sprintf("invalid topic name: %s", t), err)
 		}
 		tns[i] = tn
 	}
@@ -77,3 +76,20 @@ func toKeyValues(metadata map[string]string) []*pb.KeyValue {
 
 	return kvs
 }
+
+// runWithCtx runs f in the background and waits until it returns or the context is done, in which case the
+// context error is returned while f keeps running to completion
+func runWithCtx(ctx context.Context, f func()) error {
+	doneCh := make(chan struct{})
+	go func() {
+		defer close(doneCh)
+		f()
+	}()
+
+	select {
+	case <-doneCh:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
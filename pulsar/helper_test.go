@@ -18,7 +18,9 @@
 package pulsar
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -44,3 +46,21 @@ func TestToKeyValues(t *testing.T) {
 		assert.Equal(t, v, *kv.Value)
 	}
 }
+
+func TestRunWithCtx(t *testing.T) {
+	assert.Nil(t, runWithCtx(context.Background(), func() {}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	releaseCh := make(chan struct{})
+	doneCh := make(chan struct{})
+	err := runWithCtx(ctx, func() {
+		<-releaseCh
+		close(doneCh)
+	})
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	// the function keeps running after the context is done
+	close(releaseCh)
+	<-doneCh
+}
@@ -20,12 +20,15 @@ package pulsar
 import (
 	"fmt"
 	"math/big"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gogo/protobuf/proto"
 
+	"github.com/apache/pulsar-client-go/pulsar/internal"
 	pb "github.com/apache/pulsar-client-go/pulsar/internal/pulsar_proto"
 )
 
@@ -87,6 +90,12 @@ func (id messageID) greater(other messageID) bool {
 	return id.batchIdx > other.batchIdx
 }
 
+// isSentinel returns true if the id is the earliest or the latest message id, which stand for a position in every
+// partition rather than for a message
+func (id messageID) isSentinel() bool {
+	return id.equal(earliestMessageID.(messageID)) || id.equal(latestMessageID.(messageID))
+}
+
 func (id messageID) equal(other messageID) bool {
 	return id.ledgerID == other.ledgerID &&
 		id.entryID == other.entryID &&
@@ -98,18 +107,93 @@ func (id messageID) greaterEqual(other messageID) bool {
 }
 
 func (id messageID) Serialize() []byte {
-	msgID := &pb.MessageIdData{
+	data, _ := proto.Marshal(id.toMessageIDData())
+	return data
+}
+
+// toMessageIDData converts the id to its wire representation, to be sent as is in the commands
+func (id messageID) toMessageIDData() *pb.MessageIdData {
+	return &pb.MessageIdData{
 		LedgerId:   proto.Uint64(uint64(id.ledgerID)),
 		EntryId:    proto.Uint64(uint64(id.entryID)),
 		BatchIndex: proto.Int32(id.batchIdx),
 		Partition:  proto.Int32(id.partitionIdx),
 	}
-	data, _ := proto.Marshal(msgID)
-	return data
+}
+
+func (id messageID) LedgerID() int64 {
+	return id.ledgerID
+}
+
+func (id messageID) EntryID() int64 {
+	return id.entryID
+}
+
+func (id messageID) BatchIdx() int32 {
+	return id.batchIdx
+}
+
+func (id messageID) PartitionIdx() int32 {
+	return id.partitionIdx
 }
 
 func (id messageID) String() string {
-	return fmt.Sprintf("%d:%d:%d", id.ledgerID, id.entryID, id.partitionIdx)
+	return fmt.Sprintf("%d:%d:%d:%d", id.ledgerID, id.entryID, id.batchIdx, id.partitionIdx)
+}
+
+func (id messageID) Less(other MessageID) bool {
+	return compareMessageIDs(id, other) < 0
+}
+
+func (id messageID) Equal(other MessageID) bool {
+	return compareMessageIDs(id, other) == 0
+}
+
+// compareMessageIDs orders the message ids by ledger, entry, partition and batch index, like the Java client
+func compareMessageIDs(a, b MessageID) int {
+	if a.LedgerID() != b.LedgerID() {
+		return compareInt64(a.LedgerID(), b.LedgerID())
+	}
+	if a.EntryID() != b.EntryID() {
+		return compareInt64(a.EntryID(), b.EntryID())
+	}
+	if a.PartitionIdx() != b.PartitionIdx() {
+		return compareInt64(int64(a.PartitionIdx()), int64(b.PartitionIdx()))
+	}
+	return compareInt64(int64(a.BatchIdx()), int64(b.BatchIdx()))
+}
+
+func compareInt64(a, b int64) int {
+	if a < b {
+		return -1
+	} else if a > b {
+		return 1
+	}
+	return 0
+}
+
+func parseMessageID(s string) (MessageID, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 4 {
+		return nil, newError(InvalidMessage, fmt.Sprintf("invalid message id %q", s))
+	}
+	ledgerID, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return nil, wrapError(InvalidMessage, fmt.Sprintf("invalid ledger id in message id %q", s), err)
+	}
+	entryID, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return nil, wrapError(InvalidMessage, fmt.Sprintf("invalid entry id in message id %q", s), err)
+	}
+	batchIdx, err := strconv.ParseInt(parts[2], 10, 32)
+	if err != nil {
+		return nil, wrapError(InvalidMessage, fmt.Sprintf("invalid batch index in message id %q", s), err)
+	}
+	partitionIdx, err := strconv.ParseInt(parts[3], 10, 32)
+	if err != nil {
+		return nil, wrapError(InvalidMessage, fmt.Sprintf("invalid partition index in message id %q", s), err)
+	}
+	return newMessageID(ledgerID, entryID, int32(batchIdx), int32(partitionIdx)), nil
 }
 
 func deserializeMessageID(data []byte) (MessageID, error) {
@@ -151,13 +235,25 @@ func newTrackingMessageID(ledgerID int64, entryID int64, batchIdx int32, partiti
 }
 
 func toTrackingMessageID(msgID MessageID) (trackingMessageID, bool) {
-	if mid, ok := msgID.(messageID); ok {
+	// the ids of the received messages are acked the most, check them first
+	if mid, ok := msgID.(trackingMessageID); ok {
+		return mid, true
+	} else if mid, ok := msgID.(messageID); ok {
 		return trackingMessageID{
 			messageID:    mid,
 			receivedTime: time.Now(),
 		}, true
-	} else if mid, ok := msgID.(trackingMessageID); ok {
-		return mid, true
+	} else if msgID != nil {
+		// eg. an id implemented by the application
+		return trackingMessageID{
+			messageID: messageID{
+				ledgerID:     msgID.LedgerID(),
+				entryID:      msgID.EntryID(),
+				batchIdx:     msgID.BatchIdx(),
+				partitionIdx: msgID.PartitionIdx(),
+			},
+			receivedTime: time.Now(),
+		}, true
 	} else {
 		return trackingMessageID{}, false
 	}
@@ -184,6 +280,27 @@ type message struct {
 	replicatedFrom      string
 	redeliveryCount     uint32
 	schema              Schema
+	sharedPayload       *sharedPayload
+	released            int32
+	// the seeks of the partition consumer when the message was received
+	seeks int64
+}
+
+// sharedPayload is a pooled buffer the payloads of the messages of a batch are sliced from, it goes back to the
+// pool once all the messages referencing it are released
+type sharedPayload struct {
+	buffer internal.Buffer
+	refs   int32
+}
+
+func (p *sharedPayload) retain() {
+	atomic.AddInt32(&p.refs, 1)
+}
+
+func (p *sharedPayload) release() {
+	if atomic.AddInt32(&p.refs, -1) == 0 {
+		internal.PutReadBuffer(p.buffer)
+	}
 }
 
 func (msg *message) Topic() string {
@@ -198,6 +315,13 @@ func (msg *message) Payload() []byte {
 	return msg.payLoad
 }
 
+func (msg *message) Release() {
+	if msg.sharedPayload != nil && atomic.CompareAndSwapInt32(&msg.released, 0, 1) {
+		msg.payLoad = nil
+		msg.sharedPayload.release()
+	}
+}
+
 func (msg *message) ID() MessageID {
 	return msg.msgID
 }
@@ -45,6 +45,102 @@ func TestMessageId(t *testing.T) {
 	assert.Nil(t, id)
 }
 
+func TestMessageIDToMessageIDData(t *testing.T) {
+	data := newMessageID(1, 2, 3, 4).(messageID).toMessageIDData()
+	assert.Equal(t, uint64(1), data.GetLedgerId())
+	assert.Equal(t, uint64(2), data.GetEntryId())
+	assert.Equal(t, int32(3), data.GetBatchIndex())
+	assert.Equal(t, int32(4), data.GetPartition())
+}
+
+func TestToTrackingMessageIDKeepsTracker(t *testing.T) {
+	tracker := newAckTracker(2)
+	id := newTrackingMessageID(1, 2, 0, 4, tracker)
+
+	mid, ok := toTrackingMessageID(id)
+	assert.True(t, ok)
+	assert.Equal(t, tracker, mid.tracker)
+	assert.Equal(t, id.messageID, mid.messageID)
+}
+
+func TestMessageIDString(t *testing.T) {
+	id := newMessageID(1, 2, -1, 4)
+	assert.Equal(t, "1:2:-1:4", id.String())
+	assert.Equal(t, int64(1), id.LedgerID())
+	assert.Equal(t, int64(2), id.EntryID())
+	assert.Equal(t, int32(-1), id.BatchIdx())
+	assert.Equal(t, int32(4), id.PartitionIdx())
+
+	parsed, err := ParseMessageID(id.String())
+	assert.NoError(t, err)
+	assert.True(t, id.Equal(parsed))
+
+	for _, s := range []string{"", "1:2:3", "1:2:3:4:5", "a:2:3:4", "1:2:3:b"} {
+		_, err := ParseMessageID(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestMessageIDOrdering(t *testing.T) {
+	ids := []MessageID{
+		newMessageID(1, 1, -1, 0),
+		newMessageID(1, 2, 0, 0),
+		newMessageID(1, 2, 1, 0),
+		newMessageID(1, 2, 0, 1),
+		newMessageID(2, 0, -1, 0),
+	}
+	for i := 0; i < len(ids)-1; i++ {
+		assert.True(t, ids[i].Less(ids[i+1]), ids[i].String())
+		assert.False(t, ids[i+1].Less(ids[i]), ids[i].String())
+		assert.False(t, ids[i].Equal(ids[i+1]), ids[i].String())
+	}
+
+	// the tracking ids of the consumers compare with their plain ids
+	tracking := newTrackingMessageID(1, 2, 0, 0, nil)
+	assert.True(t, tracking.Equal(ids[1]))
+	assert.True(t, ids[1].Equal(tracking))
+	assert.False(t, tracking.Less(ids[1]))
+}
+
+func TestEarliestAndLatestMessageIDs(t *testing.T) {
+	id := newMessageID(1, 2, 0, 0)
+	assert.True(t, EarliestMessageID().Less(id))
+	assert.True(t, id.Less(LatestMessageID()))
+	assert.True(t, EarliestMessageID().Equal(EarliestMessageID()))
+
+	// the sentinels survive the serialization of the application checkpoints
+	latest, err := DeserializeMessageID(LatestMessageID().Serialize())
+	assert.NoError(t, err)
+	assert.True(t, latest.Equal(LatestMessageID()))
+	earliest, err := ParseMessageID(EarliestMessageID().String())
+	assert.NoError(t, err)
+	assert.True(t, earliest.Equal(EarliestMessageID()))
+}
+
+type customMessageID struct {
+	MessageID
+}
+
+func TestDeserializedMessageIDRoundTrip(t *testing.T) {
+	// the ids received by the consumers
+	id := newTrackingMessageID(1, 2, 3, 4, newAckTracker(5))
+	id2, err := DeserializeMessageID(id.Serialize())
+	assert.NoError(t, err)
+	assert.True(t, id.Equal(id2))
+
+	mid, ok := toTrackingMessageID(id2)
+	assert.True(t, ok)
+	assert.Equal(t, id.messageID, mid.messageID)
+
+	// the ids implemented by the applications
+	mid, ok = toTrackingMessageID(customMessageID{id2})
+	assert.True(t, ok)
+	assert.Equal(t, id.messageID, mid.messageID)
+
+	_, ok = toTrackingMessageID(nil)
+	assert.False(t, ok)
+}
+
 func TestAckTracker(t *testing.T) {
 	tracker := newAckTracker(1)
 	assert.Equal(t, true, tracker.ack(0))
@@ -33,6 +33,8 @@ import (
 const (
 	minExpire = 2 * time.Hour
 	maxExpire = 24 * time.Hour
+
+	athenzRoleAuthHeader = "Athenz-Role-Auth"
 )
 
 type athenzAuthProvider struct {
@@ -156,6 +158,14 @@ func (p *athenzAuthProvider) GetData() ([]byte, error) {
 	return []byte(tok), nil
 }
 
+func (p *athenzAuthProvider) GetHTTPHeaders() (map[string]string, error) {
+	tok, err := p.roleToken.RoleTokenValue()
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{athenzRoleAuthHeader: tok}, nil
+}
+
 func (p *athenzAuthProvider) Close() error {
 	return nil
 }
@@ -129,6 +129,14 @@ func (p *oauth2AuthProvider) GetData() ([]byte, error) {
 	return []byte(token.AccessToken), nil
 }
 
+func (p *oauth2AuthProvider) GetHTTPHeaders() (map[string]string, error) {
+	token, err := p.GetData()
+	if err != nil || token == nil {
+		return nil, err
+	}
+	return map[string]string{"Authorization": "Bearer " + string(token)}, nil
+}
+
 func (p *oauth2AuthProvider) Close() error {
 	return nil
 }
@@ -43,6 +43,37 @@ type Provider interface {
 	io.Closer
 }
 
+// ChallengeHandler is implemented by the authentication providers that need to evaluate
+// the challenges sent by the broker, eg. for multi-step mechanisms such as SASL.
+type ChallengeHandler interface {
+	// HandleChallenge evaluates the challenge data sent by the broker and returns the data
+	// that will be sent back in the auth response.
+	HandleChallenge(challenge []byte) ([]byte, error)
+}
+
+// SessionProvider is implemented by the authentication providers that keep a state per connection,
+// eg. the security context negotiated by SASL.
+type SessionProvider interface {
+	// NewSession returns the provider used to authenticate a single connection to the given host.
+	NewSession(host string) (Provider, error)
+}
+
+// HTTPAuthProvider is implemented by the authentication providers able to authenticate
+// the requests sent to the HTTP service of the brokers.
+type HTTPAuthProvider interface {
+	// GetHTTPHeaders returns the headers carrying the authentication data of an HTTP request.
+	GetHTTPHeaders() (map[string]string, error)
+}
+
+// HandleChallenge returns the data answering the given broker challenge. Providers that don't
+// implement ChallengeHandler answer with refreshed authentication data.
+func HandleChallenge(p Provider, challenge []byte) ([]byte, error) {
+	if h, ok := p.(ChallengeHandler); ok {
+		return h.HandleChallenge(challenge)
+	}
+	return p.GetData()
+}
+
 // NewProvider get/create an authentication data provider which provides the data
 // that this client will be sent to the broker.
 // Some authentication method need to auth between each client channel. So it need
@@ -58,6 +89,9 @@ func NewProvider(name string, params string) (Provider, error) {
 		return NewAuthenticationTLSWithParams(m), nil
 
 	case "token", "org.apache.pulsar.client.impl.auth.AuthenticationToken":
+		if m == nil {
+			return NewAuthenticationTokenWithParamString(params)
+		}
 		return NewAuthenticationTokenWithParams(m)
 
 	case "athenz", "org.apache.pulsar.client.impl.auth.AuthenticationAthenz":
@@ -66,6 +100,9 @@ func NewProvider(name string, params string) (Provider, error) {
 	case "oauth2", "org.apache.pulsar.client.impl.auth.oauth2.AuthenticationOAuth2":
 		return NewAuthenticationOAuth2WithParams(m)
 
+	case "sasl", "org.apache.pulsar.client.impl.auth.AuthenticationSasl":
+		return NewAuthenticationSaslWithParams(m, nil), nil
+
 	default:
 		return nil, errors.New(fmt.Sprintf("invalid auth provider '%s'", name))
 	}
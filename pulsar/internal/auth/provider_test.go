@@ -0,0 +1,51 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type challengeAuthProvider struct {
+	disabled
+	challenges [][]byte
+}
+
+func (p *challengeAuthProvider) HandleChallenge(challenge []byte) ([]byte, error) {
+	p.challenges = append(p.challenges, challenge)
+	return append([]byte("response-"), challenge...), nil
+}
+
+func TestHandleChallengeRefreshesData(t *testing.T) {
+	provider := NewAuthenticationToken("my-token")
+
+	data, err := HandleChallenge(provider, []byte("refresh"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("my-token"), data)
+}
+
+func TestHandleChallengeWithHandler(t *testing.T) {
+	provider := &challengeAuthProvider{}
+
+	data, err := HandleChallenge(provider, []byte("step-1"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("response-step-1"), data)
+	assert.Equal(t, [][]byte{[]byte("step-1")}, provider.challenges)
+}
@@ -0,0 +1,161 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package auth
+
+import (
+	"crypto/tls"
+	"errors"
+	"io"
+	"os"
+)
+
+const defaultSaslServerType = "broker"
+
+// SaslClient performs the client side of a SASL mechanism exchange, eg. the GSSAPI (Kerberos) handshake.
+// A client implementing io.Closer is closed with the connection it authenticated.
+type SaslClient interface {
+	// InitialResponse returns the data sent to the broker to start the exchange.
+	InitialResponse() ([]byte, error)
+
+	// EvaluateChallenge evaluates the challenge sent by the broker and returns the response.
+	EvaluateChallenge(challenge []byte) ([]byte, error)
+}
+
+// SaslClientFactory creates the SASL client negotiating the security context with the given server principal.
+type SaslClientFactory func(keytabPath, principal, serverPrincipal string) (SaslClient, error)
+
+type saslAuthProvider struct {
+	keytabPath    string
+	principal     string
+	serverType    string
+	clientFactory SaslClientFactory
+
+	// gssapi is the kerberos login used by the default GSSAPI clients
+	gssapi *gssapiLogin
+}
+
+// saslSession is the SASL authentication state of a single connection.
+type saslSession struct {
+	client SaslClient
+}
+
+// NewAuthenticationSaslWithParams initialize the SASL authentication provider with map param.
+func NewAuthenticationSaslWithParams(params map[string]string, clientFactory SaslClientFactory) Provider {
+	return newSaslAuthProvider(
+		params["keytab"],
+		params["principal"],
+		params["serverType"],
+		params["kerberosConfPath"],
+		clientFactory,
+	)
+}
+
+// NewAuthenticationSasl initialize the SASL authentication provider. The server principal of each
+// broker is built as `<serverType>/<broker host>`, serverType defaults to "broker".
+// Without clientFactory, the GSSAPI mechanism is used: the principal logs in with its keytab, using the
+// kerberos configuration of $KRB5_CONFIG or /etc/krb5.conf.
+func NewAuthenticationSasl(keytabPath, principal, serverType string, clientFactory SaslClientFactory) Provider {
+	return newSaslAuthProvider(keytabPath, principal, serverType, "", clientFactory)
+}
+
+func newSaslAuthProvider(keytabPath, principal, serverType, krb5ConfPath string,
+	clientFactory SaslClientFactory) *saslAuthProvider {
+	if serverType == "" {
+		serverType = defaultSaslServerType
+	}
+	p := &saslAuthProvider{
+		keytabPath:    keytabPath,
+		principal:     principal,
+		serverType:    serverType,
+		clientFactory: clientFactory,
+	}
+	if clientFactory == nil {
+		p.gssapi = newGssapiLogin(krb5ConfPath)
+		p.clientFactory = p.gssapi.newClient
+	}
+	return p
+}
+
+func (p *saslAuthProvider) Init() error {
+	if p.principal == "" {
+		return errors.New("missing principal for SASL authentication")
+	}
+	if p.gssapi != nil && p.keytabPath == "" {
+		return errors.New("missing keytab for GSSAPI authentication")
+	}
+	if p.keytabPath != "" {
+		if _, err := os.Stat(p.keytabPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *saslAuthProvider) Name() string {
+	return "sasl"
+}
+
+func (p *saslAuthProvider) GetTLSCertificate() (*tls.Certificate, error) {
+	return nil, nil
+}
+
+func (p *saslAuthProvider) GetData() ([]byte, error) {
+	return nil, errors.New("SASL authentication data is negotiated per connection")
+}
+
+func (p *saslAuthProvider) NewSession(host string) (Provider, error) {
+	client, err := p.clientFactory(p.keytabPath, p.principal, p.serverType+"/"+host)
+	if err != nil {
+		return nil, err
+	}
+	return &saslSession{client: client}, nil
+}
+
+func (p *saslAuthProvider) Close() error {
+	if p.gssapi != nil {
+		p.gssapi.close()
+	}
+	return nil
+}
+
+func (s *saslSession) Init() error {
+	return nil
+}
+
+func (s *saslSession) Name() string {
+	return "sasl"
+}
+
+func (s *saslSession) GetTLSCertificate() (*tls.Certificate, error) {
+	return nil, nil
+}
+
+func (s *saslSession) GetData() ([]byte, error) {
+	return s.client.InitialResponse()
+}
+
+func (s *saslSession) HandleChallenge(challenge []byte) ([]byte, error) {
+	return s.client.EvaluateChallenge(challenge)
+}
+
+func (s *saslSession) Close() error {
+	if closer, ok := s.client.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/jcmturner/gokrb5/v8/client"
+	"github.com/jcmturner/gokrb5/v8/config"
+	"github.com/jcmturner/gokrb5/v8/gssapi"
+	"github.com/jcmturner/gokrb5/v8/iana/keyusage"
+	"github.com/jcmturner/gokrb5/v8/keytab"
+	"github.com/jcmturner/gokrb5/v8/spnego"
+	"github.com/jcmturner/gokrb5/v8/types"
+)
+
+const defaultKrb5ConfPath = "/etc/krb5.conf"
+
+// saslNoSecurityLayer is the GSSAPI SASL security layer leaving the connection unprotected,
+// see RFC 4752, the connection is protected by TLS when needed
+const saslNoSecurityLayer = 0x01
+
+// gssapiLogin is the Kerberos login of the client principal, done with its keytab. It's shared by
+// the GSSAPI clients of all the connections, which get their service tickets from it.
+type gssapiLogin struct {
+	sync.Mutex
+	krb5ConfPath string
+	kerberos     *client.Client
+}
+
+// gssapiClient is the client side of the GSSAPI SASL mechanism, RFC 4752.
+type gssapiClient struct {
+	kerberos        *client.Client
+	serverPrincipal string
+	sessionKey      types.EncryptionKey
+	completed       bool
+}
+
+func newGssapiLogin(krb5ConfPath string) *gssapiLogin {
+	if krb5ConfPath == "" {
+		krb5ConfPath = os.Getenv("KRB5_CONFIG")
+	}
+	if krb5ConfPath == "" {
+		krb5ConfPath = defaultKrb5ConfPath
+	}
+	return &gssapiLogin{krb5ConfPath: krb5ConfPath}
+}
+
+func (l *gssapiLogin) newClient(keytabPath, principal, serverPrincipal string) (SaslClient, error) {
+	kerberos, err := l.login(keytabPath, principal)
+	if err != nil {
+		return nil, err
+	}
+	return &gssapiClient{
+		kerberos:        kerberos,
+		serverPrincipal: serverPrincipal,
+	}, nil
+}
+
+func (l *gssapiLogin) login(keytabPath, principal string) (*client.Client, error) {
+	l.Lock()
+	defer l.Unlock()
+
+	if l.kerberos != nil {
+		return l.kerberos, nil
+	}
+	if keytabPath == "" {
+		return nil, errors.New("missing keytab for GSSAPI authentication")
+	}
+	conf, err := config.Load(l.krb5ConfPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the kerberos configuration %s: %v", l.krb5ConfPath, err)
+	}
+	kt, err := keytab.Load(keytabPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the keytab %s: %v", keytabPath, err)
+	}
+
+	username, realm := principal, conf.LibDefaults.DefaultRealm
+	if i := strings.LastIndex(principal, "@"); i >= 0 {
+		username, realm = principal[:i], principal[i+1:]
+	}
+	kerberos := client.NewWithKeytab(username, realm, kt, conf)
+	if err := kerberos.Login(); err != nil {
+		return nil, fmt.Errorf("kerberos login of %s failed: %v", principal, err)
+	}
+	l.kerberos = kerberos
+	return kerberos, nil
+}
+
+func (l *gssapiLogin) close() {
+	l.Lock()
+	defer l.Unlock()
+
+	if l.kerberos != nil {
+		l.kerberos.Destroy()
+		l.kerberos = nil
+	}
+}
+
+// InitialResponse returns the kerberos AP-REQ establishing the security context with the broker.
+func (c *gssapiClient) InitialResponse() ([]byte, error) {
+	ticket, sessionKey, err := c.kerberos.GetServiceTicket(c.serverPrincipal)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get a service ticket for %s: %v", c.serverPrincipal, err)
+	}
+	token, err := spnego.NewKRB5TokenAPREQ(c.kerberos, ticket, sessionKey,
+		[]int{gssapi.ContextFlagInteg, gssapi.ContextFlagConf}, []int{})
+	if err != nil {
+		return nil, err
+	}
+	c.sessionKey = sessionKey
+	return token.Marshal()
+}
+
+// EvaluateChallenge answers the security layers offered by the broker once the security context is
+// established, the client always picks no security layer.
+func (c *gssapiClient) EvaluateChallenge(challenge []byte) ([]byte, error) {
+	if c.completed {
+		return nil, errors.New("unexpected challenge, the GSSAPI exchange is completed")
+	}
+
+	var offer gssapi.WrapToken
+	if err := offer.Unmarshal(challenge, true); err != nil {
+		return nil, fmt.Errorf("invalid GSSAPI security layers token: %v", err)
+	}
+	if _, err := offer.Verify(c.sessionKey, keyusage.GSSAPI_ACCEPTOR_SEAL); err != nil {
+		return nil, fmt.Errorf("invalid GSSAPI security layers token: %v", err)
+	}
+	if len(offer.Payload) != 4 || offer.Payload[0]&saslNoSecurityLayer == 0 {
+		return nil, errors.New("the broker requires a GSSAPI security layer")
+	}
+
+	reply, err := gssapi.NewInitiatorWrapToken([]byte{saslNoSecurityLayer, 0, 0, 0}, c.sessionKey)
+	if err != nil {
+		return nil, err
+	}
+	c.completed = true
+	return reply.Marshal()
+}
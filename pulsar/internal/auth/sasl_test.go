@@ -0,0 +1,167 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package auth
+
+import (
+	"testing"
+
+	"github.com/jcmturner/gokrb5/v8/gssapi"
+	"github.com/jcmturner/gokrb5/v8/iana/etypeID"
+	"github.com/jcmturner/gokrb5/v8/iana/keyusage"
+	"github.com/jcmturner/gokrb5/v8/types"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockSaslClient struct {
+	serverPrincipal string
+	steps           int
+}
+
+func (c *mockSaslClient) InitialResponse() ([]byte, error) {
+	return []byte("initial"), nil
+}
+
+func (c *mockSaslClient) EvaluateChallenge(challenge []byte) ([]byte, error) {
+	c.steps++
+	return append([]byte("step-"), challenge...), nil
+}
+
+type closingMockSaslClient struct {
+	mockSaslClient
+	closed bool
+}
+
+func (c *closingMockSaslClient) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestSaslAuthSessions(t *testing.T) {
+	var clients []*mockSaslClient
+	provider := NewAuthenticationSaslWithParams(map[string]string{
+		"principal": "client@EXAMPLE.COM",
+	}, func(keytabPath, principal, serverPrincipal string) (SaslClient, error) {
+		c := &mockSaslClient{serverPrincipal: serverPrincipal}
+		clients = append(clients, c)
+		return c, nil
+	})
+	assert.NoError(t, provider.Init())
+	assert.Equal(t, "sasl", provider.Name())
+
+	sp, ok := provider.(SessionProvider)
+	assert.True(t, ok)
+
+	session, err := sp.NewSession("broker-1.example.com")
+	assert.NoError(t, err)
+	data, err := session.GetData()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("initial"), data)
+
+	data, err = HandleChallenge(session, []byte("token"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("step-token"), data)
+
+	// each connection negotiates its own security context
+	_, err = sp.NewSession("broker-2.example.com")
+	assert.NoError(t, err)
+	assert.Len(t, clients, 2)
+	assert.Equal(t, "broker/broker-1.example.com", clients[0].serverPrincipal)
+	assert.Equal(t, "broker/broker-2.example.com", clients[1].serverPrincipal)
+	assert.Equal(t, 1, clients[0].steps)
+	assert.Equal(t, 0, clients[1].steps)
+}
+
+func TestSaslAuthInitErrors(t *testing.T) {
+	// the default GSSAPI client logs in with a keytab
+	provider := NewAuthenticationSasl("", "client@EXAMPLE.COM", "", nil)
+	assert.EqualError(t, provider.Init(), "missing keytab for GSSAPI authentication")
+
+	provider = NewAuthenticationSasl("", "", "",
+		func(keytabPath, principal, serverPrincipal string) (SaslClient, error) {
+			return &mockSaslClient{}, nil
+		})
+	assert.Error(t, provider.Init())
+
+	provider = NewAuthenticationSasl("/not/existing/keytab", "client@EXAMPLE.COM", "",
+		func(keytabPath, principal, serverPrincipal string) (SaslClient, error) {
+			return &mockSaslClient{}, nil
+		})
+	assert.Error(t, provider.Init())
+}
+
+func TestSaslAuthFromName(t *testing.T) {
+	for _, name := range []string{"sasl", "org.apache.pulsar.client.impl.auth.AuthenticationSasl"} {
+		provider, err := NewProvider(name,
+			`{"principal":"client@EXAMPLE.COM","keytab":"/not/existing/keytab","kerberosConfPath":"/etc/krb5.conf"}`)
+		assert.NoError(t, err)
+		assert.Equal(t, "sasl", provider.Name())
+
+		sasl := provider.(*saslAuthProvider)
+		assert.Equal(t, "client@EXAMPLE.COM", sasl.principal)
+		assert.Equal(t, "/not/existing/keytab", sasl.keytabPath)
+		assert.Equal(t, "/etc/krb5.conf", sasl.gssapi.krb5ConfPath)
+	}
+}
+
+func TestGssapiSecurityLayerNegotiation(t *testing.T) {
+	sessionKey := types.EncryptionKey{
+		KeyType:  etypeID.AES128_CTS_HMAC_SHA1_96,
+		KeyValue: []byte("0123456789abcdef"),
+	}
+	c := &gssapiClient{sessionKey: sessionKey}
+
+	// the broker offers no security layer, with a maximum message size of 64KB
+	offer := gssapi.WrapToken{
+		Flags:   0x01,
+		EC:      12,
+		Payload: []byte{saslNoSecurityLayer, 0x01, 0x00, 0x00},
+	}
+	assert.NoError(t, offer.SetCheckSum(sessionKey, keyusage.GSSAPI_ACCEPTOR_SEAL))
+	challenge, err := offer.Marshal()
+	assert.NoError(t, err)
+
+	response, err := c.EvaluateChallenge(challenge)
+	assert.NoError(t, err)
+	var reply gssapi.WrapToken
+	assert.NoError(t, reply.Unmarshal(response, false))
+	ok, err := reply.Verify(sessionKey, keyusage.GSSAPI_INITIATOR_SEAL)
+	assert.True(t, ok)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{saslNoSecurityLayer, 0, 0, 0}, reply.Payload)
+
+	_, err = c.EvaluateChallenge(challenge)
+	assert.Error(t, err)
+
+	// a token not signed with the session key is rejected
+	c = &gssapiClient{sessionKey: sessionKey}
+	offer.CheckSum[0]++
+	challenge, err = offer.Marshal()
+	assert.NoError(t, err)
+	_, err = c.EvaluateChallenge(challenge)
+	assert.Error(t, err)
+}
+
+func TestSaslSessionClose(t *testing.T) {
+	session := &saslSession{client: &mockSaslClient{}}
+	assert.NoError(t, session.Close())
+
+	closer := &closingMockSaslClient{}
+	session = &saslSession{client: closer}
+	assert.NoError(t, session.Close())
+	assert.True(t, closer.closed)
+}
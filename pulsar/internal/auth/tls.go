@@ -17,12 +17,23 @@
 
 package auth
 
-import "crypto/tls"
+import (
+	"crypto/tls"
+	"os"
+	"sync"
+	"time"
+)
 
 type tlsAuthProvider struct {
+	sync.Mutex
 	certificatePath string
 	privateKeyPath  string
 	tlsCertSupplier func() (*tls.Certificate, error)
+
+	// certificate loaded from the files, reloaded when they get rotated
+	cert        *tls.Certificate
+	certModTime time.Time
+	keyModTime  time.Time
 }
 
 // NewAuthenticationTLSWithParams initialize the authentication provider with map param.
@@ -57,18 +68,51 @@ func (p *tlsAuthProvider) Name() string {
 	return "tls"
 }
 
+// GetTLSCertificate returns the client certificate. When loaded from files, the certificate
+// is reloaded whenever the certificate or the key file has been modified, so that rotated
+// certificates are picked up by new connections without restarting the client.
 func (p *tlsAuthProvider) GetTLSCertificate() (*tls.Certificate, error) {
 	if p.tlsCertSupplier != nil {
 		return p.tlsCertSupplier()
 	}
+
+	certModTime, err := modTime(p.certificatePath)
+	if err != nil {
+		return nil, err
+	}
+	keyModTime, err := modTime(p.privateKeyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	p.Lock()
+	defer p.Unlock()
+	if p.cert != nil && certModTime.Equal(p.certModTime) && keyModTime.Equal(p.keyModTime) {
+		return p.cert, nil
+	}
+
 	cert, err := tls.LoadX509KeyPair(p.certificatePath, p.privateKeyPath)
-	return &cert, err
+	if err != nil {
+		return nil, err
+	}
+	p.cert = &cert
+	p.certModTime = certModTime
+	p.keyModTime = keyModTime
+	return p.cert, nil
 }
 
 func (p *tlsAuthProvider) GetData() ([]byte, error) {
 	return nil, nil
 }
 
-func (tlsAuthProvider) Close() error {
+func (p *tlsAuthProvider) Close() error {
 	return nil
 }
+
+func modTime(path string) (time.Time, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return info.ModTime(), nil
+}
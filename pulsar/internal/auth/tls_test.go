@@ -0,0 +1,73 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package auth
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const certsDir = "../../../integration-tests/certs/"
+
+func copyFile(t *testing.T, src, dst string, mtime time.Time) {
+	data, err := ioutil.ReadFile(src)
+	assert.NoError(t, err)
+	assert.NoError(t, ioutil.WriteFile(dst, data, 0600))
+	assert.NoError(t, os.Chtimes(dst, mtime, mtime))
+}
+
+func TestTLSAuthReloadsRotatedCertificate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "test_tls_auth")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	certPath := filepath.Join(dir, "cert.pem")
+	keyPath := filepath.Join(dir, "key.pem")
+	now := time.Now()
+	copyFile(t, certsDir+"client-cert.pem", certPath, now)
+	copyFile(t, certsDir+"client-key.pem", keyPath, now)
+
+	provider := NewAuthenticationTLS(certPath, keyPath)
+	assert.NoError(t, provider.Init())
+	assert.Equal(t, "tls", provider.Name())
+
+	cert, err := provider.GetTLSCertificate()
+	assert.NoError(t, err)
+	cached, err := provider.GetTLSCertificate()
+	assert.NoError(t, err)
+	assert.True(t, cert == cached)
+
+	// rotate the certificate
+	later := now.Add(time.Minute)
+	copyFile(t, certsDir+"broker-cert.pem", certPath, later)
+	copyFile(t, certsDir+"broker-key.pem", keyPath, later)
+
+	rotated, err := provider.GetTLSCertificate()
+	assert.NoError(t, err)
+	assert.NotEqual(t, cert.Certificate[0], rotated.Certificate[0])
+}
+
+func TestTLSAuthMissingFiles(t *testing.T) {
+	provider := NewAuthenticationTLS("/not/existing/cert.pem", "/not/existing/key.pem")
+	assert.Error(t, provider.Init())
+}
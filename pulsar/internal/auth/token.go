@@ -40,6 +40,24 @@ func NewAuthenticationTokenWithParams(params map[string]string) (Provider, error
 	}
 }
 
+// NewAuthenticationTokenWithParamString return a interface of Provider from the plain parameter
+// string used by the Java client, either "token:<token>" or "file:<path>". A string without
+// prefix is used as the token itself.
+func NewAuthenticationTokenWithParamString(params string) (Provider, error) {
+	switch {
+	case strings.HasPrefix(params, "token:"):
+		return NewAuthenticationToken(strings.TrimPrefix(params, "token:")), nil
+	case strings.HasPrefix(params, "file://"):
+		return NewAuthenticationTokenFromFile(strings.TrimPrefix(params, "file://")), nil
+	case strings.HasPrefix(params, "file:"):
+		return NewAuthenticationTokenFromFile(strings.TrimPrefix(params, "file:")), nil
+	case params != "":
+		return NewAuthenticationToken(params), nil
+	default:
+		return nil, errors.New("missing configuration for token auth")
+	}
+}
+
 // NewAuthenticationToken returns a token auth provider that will use the specified token to
 // talk with Pulsar brokers
 func NewAuthenticationToken(token string) Provider {
@@ -102,6 +120,14 @@ func (p *tokenAuthProvider) GetData() ([]byte, error) {
 	return []byte(t), nil
 }
 
+func (p *tokenAuthProvider) GetHTTPHeaders() (map[string]string, error) {
+	t, err := p.tokenSupplier()
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{"Authorization": "Bearer " + t}, nil
+}
+
 func (p *tokenAuthProvider) Close() error {
 	return nil
 }
@@ -0,0 +1,79 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package auth
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewProviderTokenParams(t *testing.T) {
+	provider, err := NewProvider("token", `{"token":"json-token"}`)
+	assert.NoError(t, err)
+	data, err := provider.GetData()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("json-token"), data)
+
+	provider, err = NewProvider("token", "token:plain-token")
+	assert.NoError(t, err)
+	data, err = provider.GetData()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("plain-token"), data)
+
+	_, err = NewProvider("token", "")
+	assert.Error(t, err)
+}
+
+func TestTokenFromFileParams(t *testing.T) {
+	f, err := ioutil.TempFile("", "test_token")
+	assert.NoError(t, err)
+	defer os.Remove(f.Name())
+	_, err = f.WriteString("file-token\n")
+	assert.NoError(t, err)
+	f.Close()
+
+	provider, err := NewProvider("token", "file://"+f.Name())
+	assert.NoError(t, err)
+	assert.NoError(t, provider.Init())
+	data, err := provider.GetData()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("file-token"), data)
+}
+
+func TestTokenFromSupplierRefresh(t *testing.T) {
+	count := 0
+	provider := NewAuthenticationTokenFromSupplier(func() (string, error) {
+		count++
+		if count == 1 {
+			return "token-1", nil
+		}
+		return "token-2", nil
+	})
+
+	data, err := provider.GetData()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("token-1"), data)
+
+	// the broker challenge will get the refreshed token
+	data, err = HandleChallenge(provider, []byte("refresh"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("token-2"), data)
+}
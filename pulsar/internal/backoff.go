@@ -18,9 +18,18 @@
 package internal
 
 import (
+	"fmt"
+	"math"
+	"math/rand"
 	"time"
 )
 
+// BackoffPolicy computes the delays between the attempts of a retry loop
+type BackoffPolicy interface {
+	// Next returns the delay to wait before the next attempt
+	Next() time.Duration
+}
+
 // Backoff
 type Backoff struct {
 	backoff time.Duration
@@ -43,3 +52,56 @@ func (b *Backoff) Next() time.Duration {
 
 	return b.backoff
 }
+
+// ExponentialBackoff multiplies the delay after each attempt, from the initial delay up to the max delay,
+// and randomizes each delay by the jitter factor to spread the retries of many clients.
+type ExponentialBackoff struct {
+	initialDelay time.Duration
+	maxDelay     time.Duration
+	multiplier   float64
+	jitter       float64
+
+	delay time.Duration
+}
+
+// NewExponentialBackoff creates an exponential backoff, the jitter is a factor between 0 and 1
+// of the delay by which each delay is randomly shortened or lengthened. The initial delay has to be positive
+// and not above the max delay, and the multiplier at least 1.
+func NewExponentialBackoff(initialDelay, maxDelay time.Duration, multiplier, jitter float64) (*ExponentialBackoff,
+	error) {
+	switch {
+	case initialDelay <= 0:
+		return nil, fmt.Errorf("invalid initial delay %v, it must be positive", initialDelay)
+	case maxDelay < initialDelay:
+		return nil, fmt.Errorf("invalid max delay %v, it must not be below the initial delay %v", maxDelay,
+			initialDelay)
+	case !(multiplier >= 1) || math.IsInf(multiplier, 0):
+		return nil, fmt.Errorf("invalid multiplier %v, it must be at least 1", multiplier)
+	case !(jitter >= 0 && jitter <= 1):
+		return nil, fmt.Errorf("invalid jitter %v, it must be between 0 and 1", jitter)
+	}
+	return &ExponentialBackoff{
+		initialDelay: initialDelay,
+		maxDelay:     maxDelay,
+		multiplier:   multiplier,
+		jitter:       jitter,
+	}, nil
+}
+
+// Next
+func (b *ExponentialBackoff) Next() time.Duration {
+	if b.delay == 0 {
+		b.delay = b.initialDelay
+	} else {
+		b.delay = time.Duration(float64(b.delay) * b.multiplier)
+	}
+	if b.delay > b.maxDelay {
+		b.delay = b.maxDelay
+	}
+
+	if b.jitter <= 0 {
+		return b.delay
+	}
+	// randomize the delay in [delay - jitter * delay, delay + jitter * delay]
+	return b.delay + time.Duration((rand.Float64()*2-1)*b.jitter*float64(b.delay))
+}
@@ -0,0 +1,58 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package internal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExponentialBackoff(t *testing.T) {
+	backoff, err := NewExponentialBackoff(100*time.Millisecond, time.Second, 2, 0)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 100*time.Millisecond, backoff.Next())
+	assert.Equal(t, 200*time.Millisecond, backoff.Next())
+	assert.Equal(t, 400*time.Millisecond, backoff.Next())
+	assert.Equal(t, 800*time.Millisecond, backoff.Next())
+	assert.Equal(t, time.Second, backoff.Next())
+	assert.Equal(t, time.Second, backoff.Next())
+}
+
+func TestExponentialBackoffJitter(t *testing.T) {
+	backoff, err := NewExponentialBackoff(time.Second, time.Second, 2, 0.2)
+	assert.Nil(t, err)
+
+	for i := 0; i < 100; i++ {
+		d := backoff.Next()
+		assert.True(t, d >= 800*time.Millisecond && d <= 1200*time.Millisecond, "delay %v", d)
+	}
+}
+
+func TestExponentialBackoffInvalid(t *testing.T) {
+	_, err := NewExponentialBackoff(0, time.Second, 2, 0)
+	assert.Error(t, err)
+	_, err = NewExponentialBackoff(time.Second, time.Millisecond, 2, 0)
+	assert.Error(t, err)
+	_, err = NewExponentialBackoff(time.Second, time.Minute, 0.5, 0)
+	assert.Error(t, err)
+	_, err = NewExponentialBackoff(time.Second, time.Minute, 2, 1.5)
+	assert.Error(t, err)
+}
@@ -33,7 +33,7 @@ type BuffersPool interface {
 
 // BatcherBuilderProvider defines func which returns the BatchBuilder.
 type BatcherBuilderProvider func(
-	maxMessages uint, maxBatchSize uint, producerName string, producerID uint64,
+	maxMessages uint, maxBatchSize uint, maxMessageSize uint32, producerName string, producerID uint64,
 	compressionType pb.CompressionType, level compression.Level,
 	bufferPool BuffersPool, logger log.Logger,
 ) (BatchBuilder, error)
@@ -47,7 +47,7 @@ type BatchBuilder interface {
 	Add(
 		metadata *pb.SingleMessageMetadata, sequenceIDGenerator *uint64,
 		payload []byte,
-		callback interface{}, replicateTo []string, deliverAt time.Time,
+		callback interface{}, replicateTo []string, deliverAt time.Time, txnID *TxnID,
 	) bool
 
 	// Flush all the messages buffered in the client and wait until all messages have been successfully persisted.
@@ -82,6 +82,9 @@ type batchContainer struct {
 	// without needing costly re-allocations.
 	maxBatchSize uint
 
+	// The max message size negotiated with the broker, a batch never grows past it
+	maxMessageSize uint32
+
 	producerName string
 	producerID   uint64
 
@@ -97,18 +100,24 @@ type batchContainer struct {
 
 // newBatchContainer init a batchContainer
 func newBatchContainer(
-	maxMessages uint, maxBatchSize uint, producerName string, producerID uint64,
+	maxMessages uint, maxBatchSize uint, maxMessageSize uint32, producerName string, producerID uint64,
 	compressionType pb.CompressionType, level compression.Level,
 	bufferPool BuffersPool, logger log.Logger,
 ) batchContainer {
 
+	// the batch is serialized in place, sizing the buffer for a full batch avoids growing it message by message
+	bufferSize := maxBatchSize
+	if bufferSize > uint(maxMessageSize) {
+		bufferSize = uint(maxMessageSize)
+	}
 	bc := batchContainer{
-		buffer:       NewBuffer(4096),
-		numMessages:  0,
-		maxMessages:  maxMessages,
-		maxBatchSize: maxBatchSize,
-		producerName: producerName,
-		producerID:   producerID,
+		buffer:         NewBuffer(int(bufferSize)),
+		numMessages:    0,
+		maxMessages:    maxMessages,
+		maxBatchSize:   maxBatchSize,
+		maxMessageSize: maxMessageSize,
+		producerName:   producerName,
+		producerID:     producerID,
 		cmdSend: baseCommand(
 			pb.BaseCommand_SEND,
 			&pb.CommandSend{
@@ -133,13 +142,13 @@ func newBatchContainer(
 
 // NewBatchBuilder init batch builder and return BatchBuilder pointer. Build a new batch message container.
 func NewBatchBuilder(
-	maxMessages uint, maxBatchSize uint, producerName string, producerID uint64,
+	maxMessages uint, maxBatchSize uint, maxMessageSize uint32, producerName string, producerID uint64,
 	compressionType pb.CompressionType, level compression.Level,
 	bufferPool BuffersPool, logger log.Logger,
 ) (BatchBuilder, error) {
 
 	bc := newBatchContainer(
-		maxMessages, maxBatchSize, producerName, producerID, compressionType,
+		maxMessages, maxBatchSize, maxMessageSize, producerName, producerID, compressionType,
 		level, bufferPool, logger,
 	)
 
@@ -153,14 +162,15 @@ func (bc *batchContainer) IsFull() bool {
 
 func (bc *batchContainer) hasSpace(payload []byte) bool {
 	msgSize := uint32(len(payload))
-	return bc.numMessages > 0 && (bc.buffer.ReadableBytes()+msgSize) > uint32(bc.maxBatchSize)
+	return bc.numMessages > 0 && ((bc.buffer.ReadableBytes()+msgSize) > uint32(bc.maxBatchSize) ||
+		(bc.buffer.ReadableBytes()+msgSize) > bc.maxMessageSize)
 }
 
 // Add will add single message to batch.
 func (bc *batchContainer) Add(
 	metadata *pb.SingleMessageMetadata, sequenceIDGenerator *uint64,
 	payload []byte,
-	callback interface{}, replicateTo []string, deliverAt time.Time,
+	callback interface{}, replicateTo []string, deliverAt time.Time, txnID *TxnID,
 ) bool {
 	if replicateTo != nil && bc.numMessages != 0 {
 		// If the current batch is not empty and we're trying to set the replication clusters,
@@ -170,6 +180,9 @@ func (bc *batchContainer) Add(
 		// There's already a message with cluster replication list. need to flush before next
 		// message can be sent
 		return false
+	} else if bc.numMessages != 0 && (txnID != nil || bc.cmdSend.Send.TxnidMostBits != nil) {
+		// The messages of a transaction are sent in their own batch
+		return false
 	} else if bc.hasSpace(payload) {
 		// The current batch is full. Producer has to call Flush() to
 		return false
@@ -187,12 +200,18 @@ func (bc *batchContainer) Add(
 		bc.msgMetadata.ProducerName = &bc.producerName
 		bc.msgMetadata.ReplicateTo = replicateTo
 		bc.msgMetadata.PartitionKey = metadata.PartitionKey
+		bc.msgMetadata.OrderingKey = metadata.OrderingKey
+		bc.msgMetadata.EventTime = metadata.EventTime
 
 		if deliverAt.UnixNano() > 0 {
 			bc.msgMetadata.DeliverAtTime = proto.Int64(int64(TimestampMillis(deliverAt)))
 		}
 
 		bc.cmdSend.Send.SequenceId = proto.Uint64(sequenceID)
+		if txnID != nil {
+			bc.cmdSend.Send.TxnidMostBits = proto.Uint64(txnID.MostSigBits)
+			bc.cmdSend.Send.TxnidLeastBits = proto.Uint64(txnID.LeastSigBits)
+		}
 	}
 	addSingleMessageToBatch(bc.buffer, metadata, payload)
 
@@ -204,9 +223,14 @@ func (bc *batchContainer) Add(
 func (bc *batchContainer) reset() {
 	bc.numMessages = 0
 	bc.buffer.Clear()
-	bc.callbacks = []interface{}{}
+	// the callbacks are handed over with the batch, the next batch likely holds as many
+	bc.callbacks = make([]interface{}, 0, len(bc.callbacks))
 	bc.msgMetadata.ReplicateTo = nil
 	bc.msgMetadata.DeliverAtTime = nil
+	bc.msgMetadata.OrderingKey = nil
+	bc.msgMetadata.EventTime = nil
+	bc.cmdSend.Send.TxnidMostBits = nil
+	bc.cmdSend.Send.TxnidLeastBits = nil
 }
 
 // Flush all the messages buffered in the client and wait until all messages have been successfully persisted.
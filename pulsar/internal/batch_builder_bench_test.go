@@ -0,0 +1,75 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package internal
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+
+	pb "github.com/apache/pulsar-client-go/pulsar/internal/pulsar_proto"
+	"github.com/apache/pulsar-client-go/pulsar/log"
+)
+
+// recyclingBuffersPool hands the same buffer back, like the producer does once the batch is persisted
+type recyclingBuffersPool struct {
+	buffer Buffer
+}
+
+func (p *recyclingBuffersPool) GetBuffer() Buffer {
+	p.buffer.Clear()
+	return p.buffer
+}
+
+func benchmarkBatchBuilder(b *testing.B, provider BatcherBuilderProvider, keys int) {
+	bb, err := provider(100, 128*1024, MaxMessageSize, "producer", 1, pb.CompressionType_NONE, 0,
+		&recyclingBuffersPool{buffer: NewBuffer(128 * 1024)}, log.DefaultNopLogger())
+	if err != nil {
+		b.Fatal(err)
+	}
+	partitionKeys := make([]*string, keys)
+	for i := range partitionKeys {
+		partitionKeys[i] = proto.String(strconv.Itoa(i))
+	}
+
+	var sequenceID uint64
+	payload := make([]byte, 100)
+	smm := &pb.SingleMessageMetadata{PayloadSize: proto.Int(len(payload))}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		smm.PartitionKey = partitionKeys[i%keys]
+		if !bb.Add(smm, &sequenceID, payload, nil, nil, time.Time{}, nil) || bb.IsFull() {
+			if bb.IsMultiBatches() {
+				bb.FlushBatches()
+			} else {
+				bb.Flush()
+			}
+		}
+	}
+}
+
+func BenchmarkBatchBuilder(b *testing.B) {
+	benchmarkBatchBuilder(b, NewBatchBuilder, 1)
+}
+
+func BenchmarkKeyBasedBatchBuilder(b *testing.B) {
+	benchmarkBatchBuilder(b, NewKeyBasedBatchBuilder, 10)
+}
@@ -0,0 +1,105 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package internal
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+
+	pb "github.com/apache/pulsar-client-go/pulsar/internal/pulsar_proto"
+	"github.com/apache/pulsar-client-go/pulsar/log"
+)
+
+type testBuffersPool struct{}
+
+func (p testBuffersPool) GetBuffer() Buffer {
+	return NewBuffer(1024)
+}
+
+func TestBatchBuilderRespectsMaxMessageSize(t *testing.T) {
+	for _, provider := range []BatcherBuilderProvider{NewBatchBuilder, NewKeyBasedBatchBuilder} {
+		bb, err := provider(1000, 128*1024, 1024, "producer", 1, pb.CompressionType_NONE, 0,
+			testBuffersPool{}, log.DefaultNopLogger())
+		assert.NoError(t, err)
+
+		var sequenceID uint64
+		payload := make([]byte, 400)
+		add := func() bool {
+			smm := &pb.SingleMessageMetadata{PayloadSize: proto.Int(len(payload))}
+			return bb.Add(smm, &sequenceID, payload, nil, nil, time.Now(), nil)
+		}
+
+		assert.True(t, add())
+		assert.True(t, add())
+		// the batch would grow past the max message size of the broker
+		assert.False(t, add())
+	}
+}
+
+func TestBatchBuilderTransaction(t *testing.T) {
+	bb, err := NewBatchBuilder(1000, 128*1024, MaxMessageSize, "producer", 1, pb.CompressionType_NONE, 0,
+		testBuffersPool{}, log.DefaultNopLogger())
+	assert.NoError(t, err)
+
+	var sequenceID uint64
+	payload := []byte("hello")
+	smm := &pb.SingleMessageMetadata{PayloadSize: proto.Int(len(payload))}
+	txnID := &TxnID{MostSigBits: 1, LeastSigBits: 2}
+
+	assert.True(t, bb.Add(smm, &sequenceID, payload, nil, nil, time.Now(), txnID))
+	// the messages of a transaction are not batched with other messages
+	assert.False(t, bb.Add(smm, &sequenceID, payload, nil, nil, time.Now(), nil))
+	assert.False(t, bb.Add(smm, &sequenceID, payload, nil, nil, time.Now(), txnID))
+
+	cmdSend := bb.(*batchContainer).cmdSend.Send
+	assert.Equal(t, uint64(1), cmdSend.GetTxnidMostBits())
+	assert.Equal(t, uint64(2), cmdSend.GetTxnidLeastBits())
+
+	batchData, _, _ := bb.Flush()
+	assert.NotNil(t, batchData)
+	assert.Nil(t, cmdSend.TxnidMostBits)
+
+	assert.True(t, bb.Add(smm, &sequenceID, payload, nil, nil, time.Now(), nil))
+	assert.True(t, bb.Add(smm, &sequenceID, payload, nil, nil, time.Now(), nil))
+	assert.Nil(t, cmdSend.TxnidMostBits)
+}
+
+func TestKeyBasedBatchBuilderFreeContainers(t *testing.T) {
+	builder, err := NewKeyBasedBatchBuilder(1000, 128*1024, MaxMessageSize, "producer", 1,
+		pb.CompressionType_NONE, 0, testBuffersPool{}, log.DefaultNopLogger())
+	assert.NoError(t, err)
+	bb := builder.(*keyBasedBatchContainer)
+
+	var sequenceID uint64
+	payload := []byte("hello")
+	for i := 0; i < 3; i++ {
+		for k := 0; k < 100; k++ {
+			key := fmt.Sprintf("key-%d-%d", i, k)
+			smm := &pb.SingleMessageMetadata{PayloadSize: proto.Int(len(payload)), PartitionKey: &key}
+			assert.True(t, bb.Add(smm, &sequenceID, payload, nil, nil, time.Now(), nil))
+		}
+		batches, _, _ := bb.FlushBatches()
+		assert.Len(t, batches, 100)
+		// the containers of the keys are only kept for reuse up to a bound
+		assert.Len(t, bb.freeContainers, maxFreeKeyContainers)
+	}
+}
@@ -19,6 +19,7 @@ package internal
 
 import (
 	"encoding/binary"
+	"sync"
 )
 
 // Buffer is a variable-sized buffer of bytes with Read and Write methods.
@@ -85,6 +86,28 @@ func NewBuffer(size int) Buffer {
 	}
 }
 
+// readBufferPool keeps the buffers the frames carrying a message payload are read into
+var readBufferPool = sync.Pool{}
+
+// GetReadBuffer returns an empty buffer, taken from the pool when possible, to read a frame of the given size into.
+// The buffer is given back with PutReadBuffer once the content isn't referenced anymore.
+func GetReadBuffer(size uint32) Buffer {
+	if b, ok := readBufferPool.Get().(Buffer); ok {
+		b.Clear()
+		b.ResizeIfNeeded(size)
+		return b
+	}
+	return NewBuffer(int(size))
+}
+
+// PutReadBuffer gives back to the pool a buffer obtained from GetReadBuffer, none of the slices
+// previously read from the buffer must be used after this call.
+func PutReadBuffer(b Buffer) {
+	if b != nil {
+		readBufferPool.Put(b)
+	}
+}
+
 func NewBufferWrapper(buf []byte) Buffer {
 	return &buffer{
 		data:      buf,
@@ -34,3 +34,14 @@ func TestBuffer(t *testing.T) {
 	assert.Equal(t, uint32(1019), b.WritableBytes())
 	assert.Equal(t, uint32(1024), b.Capacity())
 }
+
+func TestReadBufferPool(t *testing.T) {
+	b := GetReadBuffer(16)
+	b.Write([]byte("hello"))
+	PutReadBuffer(b)
+
+	b = GetReadBuffer(2048)
+	assert.Equal(t, uint32(0), b.ReadableBytes())
+	assert.True(t, b.WritableBytes() >= 2048)
+	PutReadBuffer(nil)
+}
@@ -49,15 +49,63 @@ func (h *ClientHandlers) Val(c Closable) bool {
 	return h.handlers[c]
 }
 
-func (h *ClientHandlers) Close() {
-	h.l.Lock()
+// Values returns a snapshot of the registered handlers
+func (h *ClientHandlers) Values() []Closable {
+	h.l.RLock()
+	defer h.l.RUnlock()
 	handlers := make([]Closable, 0, len(h.handlers))
 	for handler := range h.handlers {
 		handlers = append(handlers, handler)
 	}
-	h.l.Unlock()
+	return handlers
+}
 
-	for _, handler := range handlers {
+func (h *ClientHandlers) Close() {
+	for _, handler := range h.Values() {
 		handler.Close()
 	}
 }
+
+// IDHandlers is a concurrent-safe map of the partition producers or consumers of the client by their id
+type IDHandlers struct {
+	handlers map[uint64]Closable
+	l        *sync.RWMutex
+}
+
+func NewIDHandlers() IDHandlers {
+	return IDHandlers{
+		handlers: map[uint64]Closable{},
+		l:        &sync.RWMutex{},
+	}
+}
+
+func (h *IDHandlers) Add(id uint64, c Closable) {
+	h.l.Lock()
+	defer h.l.Unlock()
+	h.handlers[id] = c
+}
+
+func (h *IDHandlers) Del(id uint64) {
+	h.l.Lock()
+	defer h.l.Unlock()
+	delete(h.handlers, id)
+}
+
+// Get returns the handler registered with the id
+func (h *IDHandlers) Get(id uint64) (Closable, bool) {
+	h.l.RLock()
+	defer h.l.RUnlock()
+	c, ok := h.handlers[id]
+	return c, ok
+}
+
+// Values returns a snapshot of the registered handlers
+func (h *IDHandlers) Values() []Closable {
+	h.l.RLock()
+	defer h.l.RUnlock()
+	handlers := make([]Closable, 0, len(h.handlers))
+	for _, handler := range h.handlers {
+		handlers = append(handlers, handler)
+	}
+	return handlers
+}
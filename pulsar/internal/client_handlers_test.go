@@ -65,6 +65,24 @@ func TestClientHandlers_Del(t *testing.T) {
 	assert.Len(t, h.handlers, 0)
 }
 
+func TestClientHandlers_Values(t *testing.T) {
+	h := NewClientHandlers()
+	closable1 := &testClosable{h: &h, closed: false}
+	closable2 := &testClosable{h: &h, closed: false}
+	h.Add(closable1)
+	h.Add(closable2)
+
+	values := h.Values()
+	assert.ElementsMatch(t, []Closable{closable1, closable2}, values)
+
+	// the snapshot is not affected by the handlers removing themselves
+	for _, v := range values {
+		v.Close()
+	}
+	assert.Len(t, values, 2)
+	assert.Len(t, h.handlers, 0)
+}
+
 type testClosable struct {
 	h      *ClientHandlers
 	closed bool
@@ -74,3 +92,21 @@ func (t *testClosable) Close() {
 	t.closed = true
 	t.h.Del(t)
 }
+
+func TestIDHandlers(t *testing.T) {
+	h := NewIDHandlers()
+	closable1 := &testClosable{}
+	closable2 := &testClosable{}
+	h.Add(1, closable1)
+	h.Add(2, closable2)
+
+	c, ok := h.Get(1)
+	assert.True(t, ok)
+	assert.Equal(t, closable1, c)
+	assert.ElementsMatch(t, []Closable{closable1, closable2}, h.Values())
+
+	h.Del(1)
+	_, ok = h.Get(1)
+	assert.False(t, ok)
+	assert.ElementsMatch(t, []Closable{closable2}, h.Values())
+}
@@ -20,6 +20,7 @@ package internal
 import (
 	"errors"
 	"fmt"
+	"sync"
 
 	"github.com/gogo/protobuf/proto"
 
@@ -47,12 +48,46 @@ var ErrEOM = errors.New("EOF")
 
 var ErrConnectionClosed = errors.New("connection closed")
 
+// ErrConnectionFailed is returned when the connection to the broker could not be established
+var ErrConnectionFailed = errors.New("connection error")
+
+// ServerError is returned when the broker responds to a request with an error
+type ServerError struct {
+	Code    pb.ServerError
+	Message string
+}
+
+func (e *ServerError) Error() string {
+	return fmt.Sprintf("server error: %s: %s", e.Code, e.Message)
+}
+
 func NewMessageReader(headersAndPayload Buffer) *MessageReader {
 	return &MessageReader{
 		buffer: headersAndPayload,
 	}
 }
 
+var messageReaderPool = sync.Pool{
+	New: func() interface{} {
+		return &MessageReader{}
+	},
+}
+
+// AcquireMessageReader returns a reader from the pool, its metadata objects are reused between messages.
+// The reader must be given back with Release once the metadata it returned isn't used anymore.
+func AcquireMessageReader(headersAndPayload Buffer) *MessageReader {
+	r := messageReaderPool.Get().(*MessageReader)
+	r.buffer = headersAndPayload
+	return r
+}
+
+// Release gives back the reader to the pool
+func (r *MessageReader) Release() {
+	r.buffer = nil
+	r.batched = false
+	messageReaderPool.Put(r)
+}
+
 func NewMessageReaderFromArray(headersAndPayload []byte) *MessageReader {
 	return NewMessageReader(NewBufferWrapper(headersAndPayload))
 }
@@ -68,10 +103,14 @@ func NewMessageReaderFromArray(headersAndPayload []byte) *MessageReader {
 // [MAGIC_NUMBER][CHECKSUM] [METADATA_SIZE][METADATA] [METADATA_SIZE][METADATA][PAYLOAD]
 // [METADATA_SIZE][METADATA][PAYLOAD]
 //
+// The metadata returned by the reader is overwritten by the next read of the same kind.
 type MessageReader struct {
 	buffer Buffer
 	// true if we are parsing a batched message - set after parsing the message metadata
 	batched bool
+
+	meta       pb.MessageMetadata
+	singleMeta pb.SingleMessageMetadata
 }
 
 // ReadChecksum
@@ -106,16 +145,13 @@ func (r *MessageReader) ReadMessageMetadata() (*pb.MessageMetadata, error) {
 
 	size := r.buffer.ReadUint32()
 	data := r.buffer.Read(size)
-	var meta pb.MessageMetadata
-	if err := proto.Unmarshal(data, &meta); err != nil {
+	if err := proto.Unmarshal(data, &r.meta); err != nil {
 		return nil, ErrCorruptedMessage
 	}
 
-	if meta.NumMessagesInBatch != nil {
-		r.batched = true
-	}
+	r.batched = r.meta.NumMessagesInBatch != nil
 
-	return &meta, nil
+	return &r.meta, nil
 }
 
 func (r *MessageReader) ReadMessage() (*pb.SingleMessageMetadata, []byte, error) {
@@ -141,12 +177,11 @@ func (r *MessageReader) readSingleMessage() (*pb.SingleMessageMetadata, []byte,
 	// [METADATA_SIZE][METADATA][PAYLOAD]
 
 	size := r.buffer.ReadUint32()
-	var meta pb.SingleMessageMetadata
-	if err := proto.Unmarshal(r.buffer.Read(size), &meta); err != nil {
+	if err := proto.Unmarshal(r.buffer.Read(size), &r.singleMeta); err != nil {
 		return nil, nil, err
 	}
 
-	return &meta, r.buffer.Read(uint32(meta.GetPayloadSize())), nil
+	return &r.singleMeta, r.buffer.Read(uint32(r.singleMeta.GetPayloadSize())), nil
 }
 
 func (r *MessageReader) ResetBuffer(buffer Buffer) {
@@ -192,8 +227,20 @@ func baseCommand(cmdType pb.BaseCommand_Type, msg proto.Message) *pb.BaseCommand
 		cmd.GetTopicsOfNamespace = msg.(*pb.CommandGetTopicsOfNamespace)
 	case pb.BaseCommand_GET_LAST_MESSAGE_ID:
 		cmd.GetLastMessageId = msg.(*pb.CommandGetLastMessageId)
+	case pb.BaseCommand_GET_SCHEMA:
+		cmd.GetSchema = msg.(*pb.CommandGetSchema)
+	case pb.BaseCommand_GET_OR_CREATE_SCHEMA:
+		cmd.GetOrCreateSchema = msg.(*pb.CommandGetOrCreateSchema)
 	case pb.BaseCommand_AUTH_RESPONSE:
 		cmd.AuthResponse = msg.(*pb.CommandAuthResponse)
+	case pb.BaseCommand_NEW_TXN:
+		cmd.NewTxn = msg.(*pb.CommandNewTxn)
+	case pb.BaseCommand_ADD_PARTITION_TO_TXN:
+		cmd.AddPartitionToTxn = msg.(*pb.CommandAddPartitionToTxn)
+	case pb.BaseCommand_ADD_SUBSCRIPTION_TO_TXN:
+		cmd.AddSubscriptionToTxn = msg.(*pb.CommandAddSubscriptionToTxn)
+	case pb.BaseCommand_END_TXN:
+		cmd.EndTxn = msg.(*pb.CommandEndTxn)
 	default:
 		panic(fmt.Sprintf("Missing command type: %v", cmdType))
 	}
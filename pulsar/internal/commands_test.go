@@ -131,6 +131,30 @@ func TestReadMessagesBatchSize10(t *testing.T) {
 	assert.Equal(t, ErrEOM, err)
 }
 
+func TestAcquiredMessageReaderIsReset(t *testing.T) {
+	reader := AcquireMessageReader(NewBufferWrapper(rawBatchMessage10))
+	meta, err := reader.ReadMessageMetadata()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 10, int(meta.GetNumMessagesInBatch()))
+	reader.Release()
+
+	// a reused reader must not consider the next message as a batch
+	reader = AcquireMessageReader(NewBufferWrapper(rawCompatSingleMessage))
+	defer reader.Release()
+	meta, err = reader.ReadMessageMetadata()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Nil(t, meta.NumMessagesInBatch)
+
+	ssm, payload, err := reader.ReadMessage()
+	assert.Nil(t, err)
+	assert.Nil(t, ssm)
+	assert.Equal(t, "hello", string(payload))
+}
+
 // Raw single message in old format
 // metadata properties:<key:"a" value:"1" > properties:<key:"b" value:"2" >
 // payload = "hello"
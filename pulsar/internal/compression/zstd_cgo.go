@@ -25,7 +25,6 @@ package compression
 
 import (
 	"github.com/DataDog/zstd"
-	log "github.com/sirupsen/logrus"
 )
 
 type zstdCGoProvider struct {
@@ -62,7 +61,7 @@ func (z *zstdCGoProvider) CompressMaxSize(originalSize int) int {
 func (z *zstdCGoProvider) Compress(dst, src []byte) []byte {
 	out, err := z.ctx.CompressLevel(dst, src, z.zstdLevel)
 	if err != nil {
-		log.WithError(err).Fatal("Failed to compress")
+		panic("Failed to compress: " + err.Error())
 	}
 
 	return out
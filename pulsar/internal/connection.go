@@ -18,6 +18,7 @@
 package internal
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"errors"
@@ -50,6 +51,10 @@ type TLSOptions struct {
 	TrustCertsFilePath      string
 	AllowInsecureConnection bool
 	ValidateHostname        bool
+
+	// SNIProxyURL is the address of a proxy routing the TLS connections to the brokers based on
+	// the SNI host name, when set all the connections are dialed to it
+	SNIProxyURL *url.URL
 }
 
 // ConnectionListener is a user of a connection (eg. a producer or
@@ -59,6 +64,9 @@ type ConnectionListener interface {
 	// ReceivedSendReceipt receive and process the return value of the send command.
 	ReceivedSendReceipt(response *pb.CommandSendReceipt)
 
+	// ReceivedSendError processes the failure of the broker to persist a batch
+	ReceivedSendError(response *pb.CommandSendError)
+
 	// ConnectionClosed close the TCP connection.
 	ConnectionClosed()
 }
@@ -67,19 +75,41 @@ type ConnectionListener interface {
 type Connection interface {
 	SendRequest(requestID uint64, req *pb.BaseCommand, callback func(*pb.BaseCommand, error))
 	SendRequestNoWait(req *pb.BaseCommand) error
+	DeletePendingRequest(requestID uint64)
 	WriteData(data Buffer)
-	RegisterListener(id uint64, listener ConnectionListener)
+
+	// RegisterListener registers the producer to be notified once when the connection is closed,
+	// it fails with ErrConnectionClosed when the connection is already closed
+	RegisterListener(id uint64, listener ConnectionListener) error
 	UnregisterListener(id uint64)
-	AddConsumeHandler(id uint64, handler ConsumerHandler)
+
+	// AddConsumeHandler registers the consumer to receive its messages and to be notified once when the
+	// connection is closed, it fails with ErrConnectionClosed when the connection is already closed
+	AddConsumeHandler(id uint64, handler ConsumerHandler) error
 	DeleteConsumeHandler(id uint64)
+
 	ID() string
 	GetMaxMessageSize() int32
+
+	// Ping sends a PING command to the broker and waits for its PONG
+	Ping(ctx context.Context) error
+
 	Close()
 }
 
 type ConsumerHandler interface {
 	MessageReceived(response *pb.CommandMessage, headersAndPayload Buffer) error
 
+	// AckResponse receives the outcome of an ack the broker answers, eg. a transactional one.
+	AckResponse(response *pb.CommandAckResponse)
+
+	// ActiveConsumerChange receives whether the consumer became the active one of its Failover subscription.
+	ActiveConsumerChange(isActive bool)
+
+	// WithholdPermits asks the consumer to stop, or resume, giving permits to the broker while too many of its
+	// messages wait to be passed to MessageReceived. It is called from the read loop and must not block.
+	WithholdPermits(withhold bool)
+
 	// ConnectionClosed close the TCP connection.
 	ConnectionClosed()
 }
@@ -105,7 +135,72 @@ func (s connectionState) String() string {
 	}
 }
 
-const keepAliveInterval = 30 * time.Second
+// DefaultKeepAliveInterval is the default interval between the PING commands sent to the broker
+const DefaultKeepAliveInterval = 30 * time.Second
+
+// DefaultWriteMaxPendingBytes is the default size up to which the queued frames are coalesced into a single write
+const DefaultWriteMaxPendingBytes = 64 * 1024
+
+// ConnectionEvents receives the lifecycle events of the connections to the brokers, eg. for the telemetry
+// of the application. The logical address of the broker is given.
+type ConnectionEvents interface {
+	// ConnectionCreated is called once the connection is established and its handshake completed
+	ConnectionCreated(broker string)
+
+	// ConnectionClosed is called once a connection which was created is closed
+	ConnectionClosed(broker string)
+}
+
+// SocketOptions configures the TCP sockets of the connections to the brokers
+type SocketOptions struct {
+	// DisableNoDelay lets the OS delay the small writes, TCP_NODELAY is set otherwise
+	DisableNoDelay bool
+
+	// SendBufferSize and ReceiveBufferSize set SO_SNDBUF and SO_RCVBUF, the OS defaults are kept when 0
+	SendBufferSize    int
+	ReceiveBufferSize int
+
+	// KeepAlive is the period of the TCP keep-alive probes, 15 seconds when 0, the probes are disabled
+	// when it is negative
+	KeepAlive time.Duration
+
+	// Dial replaces the TCP dialer, eg. to go through a SOCKS proxy, the other options are only applied
+	// to the TCP connections it returns
+	Dial func(ctx context.Context, network, addr string) (net.Conn, error)
+}
+
+// apply sets the options on the TCP connection
+func (o SocketOptions) apply(cnx net.Conn) error {
+	tcpCnx, ok := cnx.(*net.TCPConn)
+	if !ok {
+		return nil
+	}
+	if err := tcpCnx.SetNoDelay(!o.DisableNoDelay); err != nil {
+		return err
+	}
+	if o.SendBufferSize > 0 {
+		if err := tcpCnx.SetWriteBuffer(o.SendBufferSize); err != nil {
+			return err
+		}
+	}
+	if o.ReceiveBufferSize > 0 {
+		if err := tcpCnx.SetReadBuffer(o.ReceiveBufferSize); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteBatching configures how a connection coalesces the queued frames, eg. the messages of the producers,
+// into larger vectored writes
+type WriteBatching struct {
+	// FlushInterval is how long the first frame waits for more frames before being written,
+	// only the frames already queued are coalesced when it is 0
+	FlushInterval time.Duration
+
+	// MaxPendingBytes writes the coalesced frames as soon as they reach this size
+	MaxPendingBytes int
+}
 
 type request struct {
 	id       *uint64
@@ -128,6 +223,8 @@ type connection struct {
 	logicalAddr  *url.URL
 	physicalAddr *url.URL
 	cnx          net.Conn
+	// the local and remote addresses, kept once connected so that they can be logged after the teardown
+	id string
 
 	writeBufferLock sync.Mutex
 	writeBuffer     Buffer
@@ -135,9 +232,14 @@ type connection struct {
 
 	lastDataReceivedLock sync.Mutex
 	lastDataReceivedTime time.Time
+	keepAliveInterval    time.Duration
 	pingTicker           *time.Ticker
 	pingCheckTicker      *time.Ticker
 
+	// the callers of Ping waiting for the next PONG
+	pongWaitersLock sync.Mutex
+	pongWaiters     []chan struct{}
+
 	log log.Logger
 
 	requestIDGenerator uint64
@@ -146,16 +248,29 @@ type connection struct {
 	incomingCmdCh      chan *incomingCmd
 	closeCh            chan interface{}
 	writeRequestsCh    chan Buffer
+	writeBatching      WriteBatching
+	socketOptions      SocketOptions
+	events             ConnectionEvents
+
+	// the frames waiting to be written in a single vectored write, only accessed by the run loop
+	pendingWrites     net.Buffers
+	pendingWritesSize int
+	// the copy of the frames waiting for the flush interval
+	delayedWrites []byte
 
 	pendingLock sync.Mutex
 	pendingReqs map[uint64]*request
-	listeners   map[uint64]ConnectionListener
+	// set once the pending requests were failed on close, the requests sent afterwards fail right away
+	pendingClosed bool
+	listeners     map[uint64]ConnectionListener
 
 	consumerHandlersLock sync.RWMutex
-	consumerHandlers     map[uint64]ConsumerHandler
+	consumerHandlers     map[uint64]*dispatchQueue
 
 	tlsOptions *TLSOptions
 	auth       auth.Provider
+	// authSession is the authentication state of this connection, closed with it
+	authSession auth.Provider
 
 	maxMessageSize int32
 	metrics        *Metrics
@@ -167,12 +282,23 @@ type connectionOptions struct {
 	physicalAddr      *url.URL
 	tls               *TLSOptions
 	connectionTimeout time.Duration
+	keepAliveInterval time.Duration
+	writeBatching     WriteBatching
+	socketOptions     SocketOptions
+	events            ConnectionEvents
 	auth              auth.Provider
 	logger            log.Logger
 	metrics           *Metrics
 }
 
 func newConnection(opts connectionOptions) *connection {
+	if opts.keepAliveInterval <= 0 {
+		opts.keepAliveInterval = DefaultKeepAliveInterval
+	}
+	if opts.writeBatching.MaxPendingBytes <= 0 {
+		opts.writeBatching.MaxPendingBytes = DefaultWriteMaxPendingBytes
+	}
+
 	cnx := &connection{
 		connectionTimeout:    opts.connectionTimeout,
 		logicalAddr:          opts.logicalAddr,
@@ -181,8 +307,9 @@ func newConnection(opts connectionOptions) *connection {
 		log:                  opts.logger.SubLogger(log.Fields{"remote_addr": opts.physicalAddr}),
 		pendingReqs:          make(map[uint64]*request),
 		lastDataReceivedTime: time.Now(),
-		pingTicker:           time.NewTicker(keepAliveInterval),
-		pingCheckTicker:      time.NewTicker(keepAliveInterval),
+		keepAliveInterval:    opts.keepAliveInterval,
+		pingTicker:           time.NewTicker(opts.keepAliveInterval),
+		pingCheckTicker:      time.NewTicker(opts.keepAliveInterval),
 		tlsOptions:           opts.tls,
 		auth:                 opts.auth,
 
@@ -196,8 +323,11 @@ func newConnection(opts connectionOptions) *connection {
 		// good to keep this above the number of partition producers assigned
 		// to a single connection.
 		writeRequestsCh:  make(chan Buffer, 256),
+		writeBatching:    opts.writeBatching,
+		socketOptions:    opts.socketOptions,
+		events:           opts.events,
 		listeners:        make(map[uint64]ConnectionListener),
-		consumerHandlers: make(map[uint64]ConsumerHandler),
+		consumerHandlers: make(map[uint64]*dispatchQueue),
 		metrics:          opts.metrics,
 	}
 	cnx.setState(connectionInit)
@@ -212,10 +342,14 @@ func (c *connection) start() {
 		if c.connect() {
 			if c.doHandshake() {
 				c.metrics.ConnectionsOpened.Inc()
+				if c.events != nil {
+					c.events.ConnectionCreated(c.logicalAddr.String())
+				}
 				c.run()
 			} else {
 				c.metrics.ConnectionsHandshakeErrors.Inc()
 				c.changeState(connectionClosed)
+				c.closeAuthSession()
 			}
 		} else {
 			c.metrics.ConnectionsEstablishmentErrors.Inc()
@@ -233,19 +367,25 @@ func (c *connection) connect() bool {
 		tlsConfig *tls.Config
 	)
 
-	if c.tlsOptions == nil {
-		// Clear text connection
-		cnx, err = net.DialTimeout("tcp", c.physicalAddr.Host, c.connectionTimeout)
-	} else {
-		// TLS connection
+	addr := c.physicalAddr.Host
+	if c.tlsOptions != nil {
 		tlsConfig, err = c.getTLSConfig()
 		if err != nil {
 			c.log.WithError(err).Warn("Failed to configure TLS ")
 			return false
 		}
+		addr = c.dialAddr()
+	}
 
-		d := &net.Dialer{Timeout: c.connectionTimeout}
-		cnx, err = tls.DialWithDialer(d, "tcp", c.physicalAddr.Host, tlsConfig)
+	cnx, err = c.dial(addr)
+	if err == nil {
+		if err = c.socketOptions.apply(cnx); err != nil {
+			cnx.Close()
+		}
+	}
+	if err == nil && tlsConfig != nil {
+		// TLS connection
+		cnx, err = c.tlsHandshake(cnx, addr, tlsConfig)
 	}
 
 	if err != nil {
@@ -256,6 +396,7 @@ func (c *connection) connect() bool {
 
 	c.Lock()
 	c.cnx = cnx
+	c.id = fmt.Sprintf("%s -> %s", cnx.LocalAddr(), cnx.RemoteAddr())
 	c.log = c.log.SubLogger(log.Fields{"local_addr": c.cnx.LocalAddr()})
 	c.log.Info("TCP connection established")
 	c.Unlock()
@@ -263,7 +404,72 @@ func (c *connection) connect() bool {
 	return true
 }
 
+// dial connects to the address within the connection timeout, with the dialer of the socket options if any
+func (c *connection) dial(addr string) (net.Conn, error) {
+	ctx := context.Background()
+	if c.connectionTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.connectionTimeout)
+		defer cancel()
+	}
+
+	if c.socketOptions.Dial != nil {
+		return c.socketOptions.Dial(ctx, "tcp", addr)
+	}
+	d := &net.Dialer{KeepAlive: c.socketOptions.KeepAlive}
+	return dialRotating(ctx, d, addr, c.log)
+}
+
+// tlsHandshake runs the TLS handshake on the TCP connection within the connection timeout,
+// the server name defaults to the host dialed like with tls.DialWithDialer
+func (c *connection) tlsHandshake(cnx net.Conn, addr string, tlsConfig *tls.Config) (net.Conn, error) {
+	if tlsConfig.ServerName == "" {
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			cnx.Close()
+			return nil, err
+		}
+		tlsConfig.ServerName = host
+	}
+
+	if c.connectionTimeout > 0 {
+		if err := cnx.SetDeadline(time.Now().Add(c.connectionTimeout)); err != nil {
+			cnx.Close()
+			return nil, err
+		}
+	}
+	tlsCnx := tls.Client(cnx, tlsConfig)
+	if err := tlsCnx.Handshake(); err != nil {
+		cnx.Close()
+		return nil, err
+	}
+	if err := cnx.SetDeadline(time.Time{}); err != nil {
+		cnx.Close()
+		return nil, err
+	}
+	return tlsCnx, nil
+}
+
 func (c *connection) doHandshake() bool {
+	if sp, ok := c.auth.(auth.SessionProvider); ok {
+		session, err := sp.NewSession(c.physicalAddr.Hostname())
+		if err != nil {
+			c.log.WithError(err).Warn("Failed to create auth session")
+			return false
+		}
+		c.Lock()
+		closed := c.getState() == connectionClosed
+		if !closed {
+			c.authSession = session
+		}
+		c.Unlock()
+		if closed {
+			session.Close()
+			return false
+		}
+		c.auth = session
+	}
+
 	// Send 'Connect' command to initiate handshake
 	authData, err := c.auth.GetData()
 	if err != nil {
@@ -273,7 +479,7 @@ func (c *connection) doHandshake() bool {
 
 	// During the initial handshake, the internal keep alive is not
 	// active yet, so we need to timeout write and read requests
-	c.cnx.SetDeadline(time.Now().Add(keepAliveInterval))
+	c.cnx.SetDeadline(time.Now().Add(c.keepAliveInterval))
 	cmdConnect := &pb.CommandConnect{
 		ProtocolVersion: proto.Int32(PulsarProtocolVersion),
 		ClientVersion:   proto.String(ClientVersionString),
@@ -284,11 +490,21 @@ func (c *connection) doHandshake() bool {
 		},
 	}
 
-	if c.logicalAddr.Host != c.physicalAddr.Host {
+	if c.logicalAddr.Host != c.physicalAddr.Host && !c.throughSNIProxy() {
+		// The SNI proxy routes on the TLS handshake, the other proxies need
+		// to be told which broker to forward the connection to
 		cmdConnect.ProxyToBrokerUrl = proto.String(c.logicalAddr.Host)
 	}
 	c.writeCommand(baseCommand(pb.BaseCommand_CONNECT, cmdConnect))
 	cmd, _, err := c.reader.readSingleCommand()
+	for err == nil && cmd.GetType() == pb.BaseCommand_AUTH_CHALLENGE {
+		// Multi-step authentication mechanisms exchange challenges before
+		// the broker accepts the connection
+		if err = c.respondAuthChallenge(cmd.GetAuthChallenge()); err != nil {
+			break
+		}
+		cmd, _, err = c.reader.readSingleCommand()
+	}
 	if err != nil {
 		c.log.WithError(err).Warn("Failed to perform initial handshake")
 		return false
@@ -321,7 +537,7 @@ func (c *connection) waitUntilReady() error {
 	for c.getState() != connectionReady {
 		c.log.Debugf("Wait until connection is ready. State: %s", c.getState().String())
 		if c.getState() == connectionClosed {
-			return errors.New("connection error")
+			return ErrConnectionFailed
 		}
 		// wait for a new connection state change
 		c.cond.Wait()
@@ -348,8 +564,9 @@ func (c *connection) run() {
 		// all the accesses to the pendingReqs should be happened in this run loop thread,
 		// including the final cleanup, to avoid the issue https://github.com/apache/pulsar-client-go/issues/239
 		c.pendingLock.Lock()
+		c.pendingClosed = true
 		for id, req := range c.pendingReqs {
-			req.callback(nil, errors.New("connection closed"))
+			req.callback(nil, ErrConnectionClosed)
 			delete(c.pendingReqs, id)
 		}
 		c.pendingLock.Unlock()
@@ -372,6 +589,15 @@ func (c *connection) run() {
 		}
 	}()
 
+	// the timer writing the pending frames once the flush interval elapsed, nil when none is pending
+	var flushTimer *time.Timer
+	var flushCh <-chan time.Time
+	defer func() {
+		if flushTimer != nil {
+			flushTimer.Stop()
+		}
+	}()
+
 	for {
 		select {
 		case <-c.closeCh:
@@ -384,7 +610,20 @@ func (c *connection) run() {
 			if data == nil {
 				return
 			}
-			c.internalWriteData(data)
+			if c.bufferWrite(data) {
+				if flushTimer != nil {
+					flushTimer.Stop()
+					flushTimer, flushCh = nil, nil
+				}
+				c.flushWrites()
+			} else if flushTimer == nil {
+				flushTimer = time.NewTimer(c.writeBatching.FlushInterval)
+				flushCh = flushTimer.C
+			}
+
+		case <-flushCh:
+			flushTimer, flushCh = nil, nil
+			c.flushWrites()
 
 		case <-c.pingTicker.C:
 			c.sendPing()
@@ -398,7 +637,7 @@ func (c *connection) runPingCheck() {
 		case <-c.closeCh:
 			return
 		case <-c.pingCheckTicker.C:
-			if c.lastDataReceived().Add(2 * keepAliveInterval).Before(time.Now()) {
+			if c.lastDataReceived().Add(2 * c.keepAliveInterval).Before(time.Now()) {
 				// We have not received a response to the previous Ping request, the
 				// connection to broker is stale
 				c.log.Warn("Detected stale connection to broker")
@@ -448,6 +687,49 @@ func (c *connection) internalWriteData(data Buffer) {
 	}
 }
 
+// bufferWrite adds the frame to the pending ones, it returns true when they are to be written right away: once
+// they reach the max pending bytes, or without a flush interval once the frames already queued are added
+func (c *connection) bufferWrite(data Buffer) bool {
+	c.pendingWritesSize += int(data.ReadableBytes())
+	if c.writeBatching.FlushInterval > 0 {
+		// the frame is copied since it waits for the flush, meanwhile its producer may return its buffer
+		// to the pool, eg. once the message timed out
+		c.delayedWrites = append(c.delayedWrites, data.ReadableSlice()...)
+		return c.pendingWritesSize >= c.writeBatching.MaxPendingBytes
+	}
+
+	c.pendingWrites = append(c.pendingWrites, data.ReadableSlice())
+	for c.pendingWritesSize < c.writeBatching.MaxPendingBytes {
+		select {
+		case next := <-c.writeRequestsCh:
+			if next != nil {
+				c.pendingWrites = append(c.pendingWrites, next.ReadableSlice())
+				c.pendingWritesSize += int(next.ReadableBytes())
+			}
+		default:
+			return true
+		}
+	}
+	return true
+}
+
+// flushWrites writes the pending frames in a single vectored write
+func (c *connection) flushWrites() {
+	buffers := c.pendingWrites
+	if len(c.delayedWrites) > 0 {
+		buffers = net.Buffers{c.delayedWrites}
+	}
+	c.log.Debugf("Write %d bytes", c.pendingWritesSize)
+	c.pendingWrites = nil
+	c.pendingWritesSize = 0
+	_, err := buffers.WriteTo(c.cnx)
+	c.delayedWrites = c.delayedWrites[:0]
+	if err != nil {
+		c.log.WithError(err).Warn("Failed to write on connection")
+		c.TriggerClose()
+	}
+}
+
 func (c *connection) writeCommand(cmd *pb.BaseCommand) {
 	// Wire format
 	// [FRAME_SIZE] [CMD_SIZE][CMD]
@@ -506,6 +788,21 @@ func (c *connection) internalReceivedCommand(cmd *pb.BaseCommand, headersAndPayl
 	case pb.BaseCommand_GET_SCHEMA_RESPONSE:
 		c.handleResponse(cmd.GetSchemaResponse.GetRequestId(), cmd)
 
+	case pb.BaseCommand_GET_OR_CREATE_SCHEMA_RESPONSE:
+		c.handleResponse(cmd.GetOrCreateSchemaResponse.GetRequestId(), cmd)
+
+	case pb.BaseCommand_NEW_TXN_RESPONSE:
+		c.handleResponse(cmd.NewTxnResponse.GetRequestId(), cmd)
+
+	case pb.BaseCommand_ADD_PARTITION_TO_TXN_RESPONSE:
+		c.handleResponse(cmd.AddPartitionToTxnResponse.GetRequestId(), cmd)
+
+	case pb.BaseCommand_ADD_SUBSCRIPTION_TO_TXN_RESPONSE:
+		c.handleResponse(cmd.AddSubscriptionToTxnResponse.GetRequestId(), cmd)
+
+	case pb.BaseCommand_END_TXN_RESPONSE:
+		c.handleResponse(cmd.EndTxnResponse.GetRequestId(), cmd)
+
 	case pb.BaseCommand_ERROR:
 		c.handleResponseError(cmd.GetError())
 
@@ -522,16 +819,21 @@ func (c *connection) internalReceivedCommand(cmd *pb.BaseCommand, headersAndPayl
 		c.handleSendReceipt(cmd.GetSendReceipt())
 
 	case pb.BaseCommand_SEND_ERROR:
+		c.handleSendError(cmd.GetSendError())
 
 	case pb.BaseCommand_MESSAGE:
 		c.handleMessage(cmd.GetMessage(), headersAndPayload)
 
+	case pb.BaseCommand_ACK_RESPONSE:
+		c.handleAckResponse(cmd.GetAckResponse())
+
 	case pb.BaseCommand_PING:
 		c.handlePing()
 	case pb.BaseCommand_PONG:
 		c.handlePong()
 
 	case pb.BaseCommand_ACTIVE_CONSUMER_CHANGE:
+		c.handleActiveConsumerChange(cmd.GetActiveConsumerChange())
 
 	default:
 		c.log.Errorf("Received invalid command type: %s", cmd.Type)
@@ -570,19 +872,30 @@ func (c *connection) SendRequestNoWait(req *pb.BaseCommand) error {
 }
 
 func (c *connection) internalSendRequest(req *request) {
+	// the check and the insert are done under the lock, so that the request is either failed by the close
+	// of the connection or failed here
 	c.pendingLock.Lock()
-	if req.id != nil {
-		c.pendingReqs[*req.id] = req
-	}
-	c.pendingLock.Unlock()
-	if c.getState() == connectionClosed {
+	if c.pendingClosed || c.getState() == connectionClosed {
+		c.pendingLock.Unlock()
 		c.log.Warnf("internalSendRequest failed for connectionClosed")
 		if req.callback != nil {
 			req.callback(req.cmd, ErrConnectionClosed)
 		}
-	} else {
-		c.writeCommand(req.cmd)
+		return
 	}
+	if req.id != nil {
+		c.pendingReqs[*req.id] = req
+	}
+	c.pendingLock.Unlock()
+	c.writeCommand(req.cmd)
+}
+
+// DeletePendingRequest stops tracking the request with the given id, a response received for it afterwards is
+// ignored. It's used when the caller gave up waiting for the response.
+func (c *connection) DeletePendingRequest(requestID uint64) {
+	c.pendingLock.Lock()
+	delete(c.pendingReqs, requestID)
+	c.pendingLock.Unlock()
 }
 
 func (c *connection) handleResponse(requestID uint64, response *pb.BaseCommand) {
@@ -594,7 +907,10 @@ func (c *connection) handleResponse(requestID uint64, response *pb.BaseCommand)
 		return
 	}
 
-	delete(c.pendingReqs, requestID)
+	if !isQueuedResponse(response) {
+		// a queued request stays pending until its final response
+		delete(c.pendingReqs, requestID)
+	}
 	c.pendingLock.Unlock()
 	request.callback(response, nil)
 }
@@ -613,8 +929,10 @@ func (c *connection) handleResponseError(serverError *pb.CommandError) {
 	delete(c.pendingReqs, requestID)
 	c.pendingLock.Unlock()
 
-	errMsg := fmt.Sprintf("server error: %s: %s", serverError.GetError(), serverError.GetMessage())
-	request.callback(nil, errors.New(errMsg))
+	request.callback(nil, &ServerError{
+		Code:    serverError.GetError(),
+		Message: serverError.GetMessage(),
+	})
 }
 
 func (c *connection) handleSendReceipt(response *pb.CommandSendReceipt) {
@@ -631,19 +949,50 @@ func (c *connection) handleSendReceipt(response *pb.CommandSendReceipt) {
 	}
 }
 
+func (c *connection) handleSendError(response *pb.CommandSendError) {
+	producerID := response.GetProducerId()
+
+	c.Lock()
+	producer, ok := c.listeners[producerID]
+	c.Unlock()
+
+	if ok {
+		producer.ReceivedSendError(response)
+	} else {
+		c.log.WithField("producerID", producerID).Warnf("Got unexpected send error for sequenceId %d: %s %s",
+			response.GetSequenceId(), response.GetError(), response.GetMessage())
+	}
+}
+
 func (c *connection) handleMessage(response *pb.CommandMessage, payload Buffer) {
 	c.log.Debug("Got Message: ", response)
 	consumerID := response.GetConsumerId()
-	if consumer, ok := c.consumerHandler(consumerID); ok {
-		err := consumer.MessageReceived(response, payload)
-		if err != nil {
-			c.log.
-				WithError(err).
-				WithField("consumerID", consumerID).
-				Error("handle message Id: ", response.MessageId)
-		}
+	c.consumerHandlersLock.RLock()
+	queue, ok := c.consumerHandlers[consumerID]
+	c.consumerHandlersLock.RUnlock()
+	if ok {
+		queue.push(response, payload)
 	} else {
 		c.log.WithField("consumerID", consumerID).Warn("Got unexpected message: ", response.MessageId)
+		PutReadBuffer(payload)
+	}
+}
+
+func (c *connection) handleAckResponse(response *pb.CommandAckResponse) {
+	consumerID := response.GetConsumerId()
+	if consumer, ok := c.consumerHandler(consumerID); ok {
+		consumer.AckResponse(response)
+	} else {
+		c.log.WithField("consumerID", consumerID).Warn("Got unexpected ack response: ", response)
+	}
+}
+
+func (c *connection) handleActiveConsumerChange(change *pb.CommandActiveConsumerChange) {
+	consumerID := change.GetConsumerId()
+	if consumer, ok := c.consumerHandler(consumerID); ok {
+		consumer.ActiveConsumerChange(change.GetIsActive())
+	} else {
+		c.log.WithField("consumerID", consumerID).Warn("Got unexpected active consumer change: ", change)
 	}
 }
 
@@ -667,6 +1016,50 @@ func (c *connection) sendPing() {
 
 func (c *connection) handlePong() {
 	c.log.Debug("Received PONG response")
+
+	c.pongWaitersLock.Lock()
+	waiters := c.pongWaiters
+	c.pongWaiters = nil
+	c.pongWaitersLock.Unlock()
+	for _, ch := range waiters {
+		close(ch)
+	}
+}
+
+func (c *connection) Ping(ctx context.Context) error {
+	if c.getState() == connectionClosed {
+		return ErrConnectionClosed
+	}
+
+	pongCh := make(chan struct{})
+	c.pongWaitersLock.Lock()
+	c.pongWaiters = append(c.pongWaiters, pongCh)
+	c.pongWaitersLock.Unlock()
+
+	c.sendPing()
+
+	select {
+	case <-pongCh:
+		return nil
+	case <-c.closeCh:
+		return ErrConnectionClosed
+	case <-ctx.Done():
+		c.removePongWaiter(pongCh)
+		return ctx.Err()
+	}
+}
+
+// removePongWaiter drops the waiter of a ping given up on, so that waiters don't pile up while the broker
+// doesn't answer
+func (c *connection) removePongWaiter(pongCh chan struct{}) {
+	c.pongWaitersLock.Lock()
+	defer c.pongWaitersLock.Unlock()
+	for i, waiter := range c.pongWaiters {
+		if waiter == pongCh {
+			c.pongWaiters = append(c.pongWaiters[:i], c.pongWaiters[i+1:]...)
+			return
+		}
+	}
 }
 
 func (c *connection) handlePing() {
@@ -677,12 +1070,17 @@ func (c *connection) handlePing() {
 func (c *connection) handleAuthChallenge(authChallenge *pb.CommandAuthChallenge) {
 	c.log.Debugf("Received auth challenge from broker: %s", authChallenge.GetChallenge().GetAuthMethodName())
 
-	// Get new credentials from the provider
-	authData, err := c.auth.GetData()
-	if err != nil {
+	if err := c.respondAuthChallenge(authChallenge); err != nil {
 		c.log.WithError(err).Warn("Failed to load auth credentials")
 		c.TriggerClose()
-		return
+	}
+}
+
+func (c *connection) respondAuthChallenge(authChallenge *pb.CommandAuthChallenge) error {
+	// Get new credentials from the provider
+	authData, err := auth.HandleChallenge(c.auth, authChallenge.GetChallenge().GetAuthData())
+	if err != nil {
+		return err
 	}
 
 	cmdAuthResponse := &pb.CommandAuthResponse{
@@ -695,18 +1093,22 @@ func (c *connection) handleAuthChallenge(authChallenge *pb.CommandAuthChallenge)
 	}
 
 	c.writeCommand(baseCommand(pb.BaseCommand_AUTH_RESPONSE, cmdAuthResponse))
+	return nil
 }
 
 func (c *connection) handleCloseConsumer(closeConsumer *pb.CommandCloseConsumer) {
 	consumerID := closeConsumer.GetConsumerId()
 	c.log.Infof("Broker notification of Closed consumer: %d", consumerID)
 
-	c.Lock()
-	defer c.Unlock()
+	// the handler is removed before being notified, as the consumer registers itself again when reconnecting
+	c.consumerHandlersLock.Lock()
+	queue, ok := c.consumerHandlers[consumerID]
+	delete(c.consumerHandlers, consumerID)
+	c.consumerHandlersLock.Unlock()
 
-	if consumer, ok := c.consumerHandler(consumerID); ok {
-		consumer.ConnectionClosed()
-		c.DeleteConsumeHandler(consumerID)
+	if ok {
+		queue.close()
+		queue.handler.ConnectionClosed()
 	} else {
 		c.log.WithField("consumerID", consumerID).Warnf("Consumer with ID not found while closing consumer")
 	}
@@ -716,21 +1118,29 @@ func (c *connection) handleCloseProducer(closeProducer *pb.CommandCloseProducer)
 	c.log.Infof("Broker notification of Closed producer: %d", closeProducer.GetProducerId())
 	producerID := closeProducer.GetProducerId()
 
+	// the listener is removed before being notified, as the producer registers itself again when reconnecting
 	c.Lock()
-	defer c.Unlock()
-	if producer, ok := c.listeners[producerID]; ok {
+	producer, ok := c.listeners[producerID]
+	delete(c.listeners, producerID)
+	c.Unlock()
+
+	if ok {
 		producer.ConnectionClosed()
-		delete(c.listeners, producerID)
 	} else {
 		c.log.WithField("producerID", producerID).Warn("Producer with ID not found while closing producer")
 	}
 }
 
-func (c *connection) RegisterListener(id uint64, listener ConnectionListener) {
+func (c *connection) RegisterListener(id uint64, listener ConnectionListener) error {
 	c.Lock()
 	defer c.Unlock()
 
+	// the listeners registered once the connection is closed would never be notified
+	if c.getState() == connectionClosed {
+		return ErrConnectionClosed
+	}
 	c.listeners[id] = listener
+	return nil
 }
 
 func (c *connection) UnregisterListener(id uint64) {
@@ -755,37 +1165,58 @@ func (c *connection) TriggerClose() {
 
 func (c *connection) Close() {
 	c.Lock()
-	defer c.Unlock()
-
 	c.cond.Broadcast()
 
 	if c.getState() == connectionClosed {
+		c.Unlock()
 		return
 	}
 
 	c.log.Info("Connection closed")
+	wasReady := c.getState() == connectionReady
 	// do not use changeState() since they share the same lock
 	c.setState(connectionClosed)
 	c.TriggerClose()
 	c.pingTicker.Stop()
 	c.pingCheckTicker.Stop()
 
-	for _, listener := range c.listeners {
+	listeners := c.listeners
+	c.listeners = make(map[uint64]ConnectionListener)
+	c.Unlock()
+	c.closeAuthSession()
+
+	c.consumerHandlersLock.Lock()
+	consumerHandlers := c.consumerHandlers
+	c.consumerHandlers = make(map[uint64]*dispatchQueue)
+	c.consumerHandlersLock.Unlock()
+
+	// each producer and consumer is notified once, out of the locks since they reconnect independently
+	// and may register themselves on another connection meanwhile
+	for _, listener := range listeners {
 		listener.ConnectionClosed()
 	}
-
-	consumerHandlers := make(map[uint64]ConsumerHandler)
-	c.consumerHandlersLock.RLock()
-	for id, handler := range c.consumerHandlers {
-		consumerHandlers[id] = handler
+	for _, queue := range consumerHandlers {
+		queue.close()
+		queue.handler.ConnectionClosed()
 	}
-	c.consumerHandlersLock.RUnlock()
 
-	for _, handler := range consumerHandlers {
-		handler.ConnectionClosed()
+	c.metrics.ConnectionsClosed.Inc()
+	if wasReady && c.events != nil {
+		c.events.ConnectionClosed(c.logicalAddr.String())
 	}
+}
 
-	c.metrics.ConnectionsClosed.Inc()
+func (c *connection) closeAuthSession() {
+	c.Lock()
+	session := c.authSession
+	c.authSession = nil
+	c.Unlock()
+
+	if session != nil {
+		if err := session.Close(); err != nil {
+			c.log.WithError(err).Warn("Failed to close the auth session")
+		}
+	}
 }
 
 func (c *connection) changeState(state connectionState) {
@@ -825,8 +1256,21 @@ func (c *connection) getTLSConfig() (*tls.Config, error) {
 		}
 	}
 
+	if c.throughSNIProxy() {
+		// The SNI host name selects the broker behind the proxy, so it's always
+		// sent and the certificate is the one of the broker
+		tlsConfig.ServerName = c.logicalAddr.Hostname()
+	}
+
 	if c.tlsOptions.ValidateHostname {
-		tlsConfig.ServerName = c.physicalAddr.Hostname()
+		if tlsConfig.ServerName == "" {
+			tlsConfig.ServerName = c.physicalAddr.Hostname()
+		}
+	} else if !c.tlsOptions.AllowInsecureConnection {
+		// The standard TLS handshake always checks the host name, so we need to take
+		// over the verification to only validate the certificate chain sent by the broker
+		tlsConfig.InsecureSkipVerify = true
+		tlsConfig.VerifyPeerCertificate = verifyCertificateChain(tlsConfig.RootCAs)
 	}
 
 	cert, err := c.auth.GetTLSCertificate()
@@ -841,27 +1285,85 @@ func (c *connection) getTLSConfig() (*tls.Config, error) {
 	return tlsConfig, nil
 }
 
-func (c *connection) AddConsumeHandler(id uint64, handler ConsumerHandler) {
+func (c *connection) throughSNIProxy() bool {
+	return c.tlsOptions != nil && c.tlsOptions.SNIProxyURL != nil
+}
+
+// dialAddr returns the address the TCP connection is established with
+func (c *connection) dialAddr() string {
+	if c.throughSNIProxy() {
+		return c.tlsOptions.SNIProxyURL.Host
+	}
+	return c.physicalAddr.Host
+}
+
+// verifyCertificateChain returns a function verifying the certificate chain presented by the broker against
+// the given root CAs (or the system pool when nil), without checking the host name.
+func verifyCertificateChain(rootCAs *x509.CertPool) func([][]byte, [][]*x509.Certificate) error {
+	return func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		if len(rawCerts) == 0 {
+			return errors.New("no certificate presented by the broker")
+		}
+
+		certs := make([]*x509.Certificate, len(rawCerts))
+		for i, raw := range rawCerts {
+			cert, err := x509.ParseCertificate(raw)
+			if err != nil {
+				return err
+			}
+			certs[i] = cert
+		}
+
+		opts := x509.VerifyOptions{
+			Roots:         rootCAs,
+			Intermediates: x509.NewCertPool(),
+		}
+		for _, cert := range certs[1:] {
+			opts.Intermediates.AddCert(cert)
+		}
+
+		_, err := certs[0].Verify(opts)
+		return err
+	}
+}
+
+func (c *connection) AddConsumeHandler(id uint64, handler ConsumerHandler) error {
 	c.consumerHandlersLock.Lock()
 	defer c.consumerHandlersLock.Unlock()
-	c.consumerHandlers[id] = handler
+
+	// the handlers added once the connection is closed would never be notified
+	if c.getState() == connectionClosed {
+		return ErrConnectionClosed
+	}
+	if queue, ok := c.consumerHandlers[id]; ok {
+		queue.close()
+	}
+	c.consumerHandlers[id] = newDispatchQueue(handler, consumerDispatchQueueSize,
+		c.log.SubLogger(log.Fields{"consumerID": id}))
+	return nil
 }
 
 func (c *connection) DeleteConsumeHandler(id uint64) {
 	c.consumerHandlersLock.Lock()
 	defer c.consumerHandlersLock.Unlock()
-	delete(c.consumerHandlers, id)
+	if queue, ok := c.consumerHandlers[id]; ok {
+		queue.close()
+		delete(c.consumerHandlers, id)
+	}
 }
 
 func (c *connection) consumerHandler(id uint64) (ConsumerHandler, bool) {
 	c.consumerHandlersLock.RLock()
 	defer c.consumerHandlersLock.RUnlock()
-	h, ok := c.consumerHandlers[id]
-	return h, ok
+	queue, ok := c.consumerHandlers[id]
+	if !ok {
+		return nil, false
+	}
+	return queue.handler, true
 }
 
 func (c *connection) ID() string {
-	return fmt.Sprintf("%s -> %s", c.cnx.LocalAddr(), c.cnx.RemoteAddr())
+	return c.id
 }
 
 func (c *connection) GetMaxMessageSize() int32 {
@@ -33,13 +33,23 @@ type ConnectionPool interface {
 	// GetConnection get a connection from ConnectionPool.
 	GetConnection(logicalAddr *url.URL, physicalAddr *url.URL) (Connection, error)
 
+	// Reset closes all the connections in the pool, the new connections are established with the given
+	// TLS options and authentication, eg. after the client switched to another cluster
+	Reset(tlsOptions *TLSOptions, auth auth.Provider)
+
 	// Close all the connections in the pool
 	Close()
 }
 
 type connectionPool struct {
-	pool                  sync.Map
-	connectionTimeout     time.Duration
+	pool              sync.Map
+	connectionTimeout time.Duration
+	keepAliveInterval time.Duration
+	writeBatching     WriteBatching
+	socketOptions     SocketOptions
+	events            ConnectionEvents
+
+	securityLock          sync.RWMutex
 	tlsOptions            *TLSOptions
 	auth                  auth.Provider
 	maxConnectionsPerHost int32
@@ -54,6 +64,10 @@ func NewConnectionPool(
 	tlsOptions *TLSOptions,
 	auth auth.Provider,
 	connectionTimeout time.Duration,
+	keepAliveInterval time.Duration,
+	writeBatching WriteBatching,
+	socketOptions SocketOptions,
+	events ConnectionEvents,
 	maxConnectionsPerHost int,
 	logger log.Logger,
 	metrics *Metrics) ConnectionPool {
@@ -61,6 +75,10 @@ func NewConnectionPool(
 		tlsOptions:            tlsOptions,
 		auth:                  auth,
 		connectionTimeout:     connectionTimeout,
+		keepAliveInterval:     keepAliveInterval,
+		writeBatching:         writeBatching,
+		socketOptions:         socketOptions,
+		events:                events,
 		maxConnectionsPerHost: int32(maxConnectionsPerHost),
 		log:                   logger,
 		metrics:               metrics,
@@ -84,15 +102,21 @@ func (p *connectionPool) GetConnection(logicalAddr *url.URL, physicalAddr *url.U
 	}
 
 	// Try to create a new connection
+	p.securityLock.RLock()
 	newConnection := newConnection(connectionOptions{
 		logicalAddr:       logicalAddr,
 		physicalAddr:      physicalAddr,
 		tls:               p.tlsOptions,
 		connectionTimeout: p.connectionTimeout,
+		keepAliveInterval: p.keepAliveInterval,
+		writeBatching:     p.writeBatching,
+		socketOptions:     p.socketOptions,
+		events:            p.events,
 		auth:              p.auth,
 		logger:            p.log,
 		metrics:           p.metrics,
 	})
+	p.securityLock.RUnlock()
 	newCnx, wasCached := p.pool.LoadOrStore(key, newConnection)
 	cnx := newCnx.(*connection)
 
@@ -112,6 +136,19 @@ func (p *connectionPool) GetConnection(logicalAddr *url.URL, physicalAddr *url.U
 	return cnx, nil
 }
 
+func (p *connectionPool) Reset(tlsOptions *TLSOptions, auth auth.Provider) {
+	p.securityLock.Lock()
+	p.tlsOptions = tlsOptions
+	p.auth = auth
+	p.securityLock.Unlock()
+
+	p.pool.Range(func(key, value interface{}) bool {
+		p.pool.Delete(key)
+		value.(Connection).Close()
+		return true
+	})
+}
+
 func (p *connectionPool) Close() {
 	p.pool.Range(func(key, value interface{}) bool {
 		value.(Connection).Close()
@@ -0,0 +1,335 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package internal
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+
+	pb "github.com/apache/pulsar-client-go/pulsar/internal/pulsar_proto"
+	"github.com/apache/pulsar-client-go/pulsar/log"
+)
+
+func newPipedConnection() (*connection, net.Conn) {
+	client, server := net.Pipe()
+	return &connection{
+		cnx:         client,
+		writeBuffer: NewBuffer(1024),
+		closeCh:     make(chan interface{}),
+		log:         log.DefaultNopLogger(),
+
+		listeners:        make(map[uint64]ConnectionListener),
+		consumerHandlers: make(map[uint64]*dispatchQueue),
+	}, server
+}
+
+// reconnectingHandler registers itself again on the connection when notified, like the producers and consumers do
+type reconnectingHandler struct {
+	cnx    *connection
+	id     uint64
+	closed int
+}
+
+func (h *reconnectingHandler) ReceivedSendReceipt(response *pb.CommandSendReceipt) {}
+
+func (h *reconnectingHandler) ReceivedSendError(response *pb.CommandSendError) {}
+
+func (h *reconnectingHandler) MessageReceived(response *pb.CommandMessage, headersAndPayload Buffer) error {
+	return nil
+}
+
+func (h *reconnectingHandler) AckResponse(response *pb.CommandAckResponse) {}
+
+func (h *reconnectingHandler) ActiveConsumerChange(isActive bool) {}
+
+func (h *reconnectingHandler) WithholdPermits(withhold bool) {}
+
+func (h *reconnectingHandler) ConnectionClosed() {
+	h.closed++
+	h.cnx.RegisterListener(h.id, h)
+	h.cnx.AddConsumeHandler(h.id, h)
+}
+
+func TestConnectionPing(t *testing.T) {
+	c, server := newPipedConnection()
+	defer server.Close()
+
+	// the broker side answers the PING
+	go func() {
+		buf := make([]byte, 1024)
+		if _, err := server.Read(buf); err == nil {
+			c.handlePong()
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	assert.Nil(t, c.Ping(ctx))
+	assert.Empty(t, c.pongWaiters)
+}
+
+func TestConnectionPingTimeout(t *testing.T) {
+	c, server := newPipedConnection()
+	defer server.Close()
+
+	// the PING is read but never answered
+	go func() {
+		buf := make([]byte, 1024)
+		_, _ = server.Read(buf)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, c.Ping(ctx))
+	assert.Empty(t, c.pongWaiters)
+}
+
+func TestConnectionHandleCloseConsumer(t *testing.T) {
+	c, server := newPipedConnection()
+	defer server.Close()
+
+	handler := &reconnectingHandler{cnx: c, id: 1}
+	c.AddConsumeHandler(1, handler)
+
+	c.handleCloseConsumer(&pb.CommandCloseConsumer{ConsumerId: proto.Uint64(1), RequestId: proto.Uint64(0)})
+	assert.Equal(t, 1, handler.closed)
+	_, ok := c.consumerHandler(1)
+	assert.True(t, ok)
+
+	// unknown consumers are ignored
+	c.handleCloseConsumer(&pb.CommandCloseConsumer{ConsumerId: proto.Uint64(2), RequestId: proto.Uint64(0)})
+	assert.Equal(t, 1, handler.closed)
+}
+
+func TestConnectionHandleCloseProducer(t *testing.T) {
+	c, server := newPipedConnection()
+	defer server.Close()
+
+	handler := &reconnectingHandler{cnx: c, id: 1}
+	c.RegisterListener(1, handler)
+
+	c.handleCloseProducer(&pb.CommandCloseProducer{ProducerId: proto.Uint64(1), RequestId: proto.Uint64(0)})
+	assert.Equal(t, 1, handler.closed)
+	assert.Contains(t, c.listeners, uint64(1))
+}
+
+func TestConnectionCloseNotifiesOnce(t *testing.T) {
+	c, server := newPipedConnection()
+	defer server.Close()
+	c.cond = sync.NewCond(c)
+	c.pingTicker = time.NewTicker(time.Minute)
+	c.pingCheckTicker = time.NewTicker(time.Minute)
+	c.metrics = NewMetricsProvider(map[string]string{})
+
+	producer := &reconnectingHandler{cnx: c, id: 1}
+	assert.Nil(t, c.RegisterListener(1, producer))
+	consumer := &reconnectingHandler{cnx: c, id: 2}
+	assert.Nil(t, c.AddConsumeHandler(2, consumer))
+
+	// the handlers register again while being notified, which is rejected on the closed connection
+	c.Close()
+	assert.Equal(t, 1, producer.closed)
+	assert.Equal(t, 1, consumer.closed)
+	assert.Empty(t, c.listeners)
+	assert.Empty(t, c.consumerHandlers)
+
+	c.Close()
+	assert.Equal(t, 1, producer.closed)
+	assert.Equal(t, 1, consumer.closed)
+
+	assert.Equal(t, ErrConnectionClosed, c.RegisterListener(3, producer))
+	assert.Equal(t, ErrConnectionClosed, c.AddConsumeHandler(3, consumer))
+}
+
+type recordedEvents struct {
+	created []string
+	closed  []string
+}
+
+func (e *recordedEvents) ConnectionCreated(broker string) {
+	e.created = append(e.created, broker)
+}
+
+func (e *recordedEvents) ConnectionClosed(broker string) {
+	e.closed = append(e.closed, broker)
+}
+
+func TestConnectionEventsOnClose(t *testing.T) {
+	events := &recordedEvents{}
+	logicalAddr, err := url.Parse("pulsar://broker-1:6650")
+	assert.Nil(t, err)
+
+	// a connection which never completed its handshake isn't reported as closed
+	c, server := newPipedConnection()
+	c.cond = sync.NewCond(c)
+	c.pingTicker = time.NewTicker(time.Minute)
+	c.pingCheckTicker = time.NewTicker(time.Minute)
+	c.metrics = NewMetricsProvider(map[string]string{})
+	c.logicalAddr = logicalAddr
+	c.events = events
+	c.Close()
+	server.Close()
+	assert.Empty(t, events.closed)
+
+	c, server = newPipedConnection()
+	defer server.Close()
+	c.cond = sync.NewCond(c)
+	c.pingTicker = time.NewTicker(time.Minute)
+	c.pingCheckTicker = time.NewTicker(time.Minute)
+	c.metrics = NewMetricsProvider(map[string]string{})
+	c.logicalAddr = logicalAddr
+	c.events = events
+	c.setState(connectionReady)
+	c.Close()
+	c.Close()
+	assert.Equal(t, []string{"pulsar://broker-1:6650"}, events.closed)
+}
+
+func TestConnectionSendRequestAfterPendingRequestsFailed(t *testing.T) {
+	c, server := newPipedConnection()
+	defer server.Close()
+	c.pendingReqs = make(map[uint64]*request)
+
+	// the run loop failed the pending requests, the connection isn't marked closed yet
+	c.pendingClosed = true
+
+	var err error
+	id := uint64(1)
+	c.internalSendRequest(&request{
+		id:       &id,
+		cmd:      baseCommand(pb.BaseCommand_PING, &pb.CommandPing{}),
+		callback: func(_ *pb.BaseCommand, e error) { err = e },
+	})
+	assert.Equal(t, ErrConnectionClosed, err)
+	assert.Empty(t, c.pendingReqs)
+}
+
+func TestConnectionWriteBatch(t *testing.T) {
+	c, server := newPipedConnection()
+	defer server.Close()
+	c.writeRequestsCh = make(chan Buffer, 10)
+	c.writeBatching = WriteBatching{MaxPendingBytes: DefaultWriteMaxPendingBytes}
+
+	received := make(chan []byte)
+	go func() {
+		data := make([]byte, 0, 9)
+		buf := make([]byte, 9)
+		for len(data) < 9 {
+			n, err := server.Read(buf)
+			if err != nil {
+				break
+			}
+			data = append(data, buf[:n]...)
+		}
+		received <- data
+	}()
+
+	// the frames queued meanwhile are written along with the first one
+	c.writeRequestsCh <- NewBufferWrapper([]byte("def"))
+	c.writeRequestsCh <- NewBufferWrapper([]byte("ghi"))
+	assert.True(t, c.bufferWrite(NewBufferWrapper([]byte("abc"))))
+	assert.Empty(t, c.writeRequestsCh)
+	c.flushWrites()
+	assert.Equal(t, "abcdefghi", string(<-received))
+}
+
+func TestConnectionWriteBatchMaxPendingBytes(t *testing.T) {
+	c, server := newPipedConnection()
+	defer server.Close()
+	c.writeRequestsCh = make(chan Buffer, 10)
+	c.writeBatching = WriteBatching{FlushInterval: time.Minute, MaxPendingBytes: 6}
+
+	go func() {
+		buf := make([]byte, 6)
+		for n := 0; n < 6; {
+			read, err := server.Read(buf[n:])
+			if err != nil {
+				return
+			}
+			n += read
+		}
+	}()
+
+	// the frames wait for the interval, the run loop handling the other events meanwhile, unless they reach
+	// the max pending bytes
+	c.writeRequestsCh <- NewBufferWrapper([]byte("ghi"))
+	assert.False(t, c.bufferWrite(NewBufferWrapper([]byte("abc"))))
+	assert.True(t, c.bufferWrite(NewBufferWrapper([]byte("def"))))
+	assert.Equal(t, 1, len(c.writeRequestsCh))
+	c.flushWrites()
+	assert.Empty(t, c.pendingWrites)
+	assert.Empty(t, c.delayedWrites)
+}
+
+func TestConnectionWriteBatchCopiesDelayedFrames(t *testing.T) {
+	c, server := newPipedConnection()
+	defer server.Close()
+	c.writeBatching = WriteBatching{FlushInterval: time.Minute, MaxPendingBytes: DefaultWriteMaxPendingBytes}
+
+	received := make(chan []byte)
+	go func() {
+		buf := make([]byte, 6)
+		n, _ := io.ReadFull(server, buf)
+		received <- buf[:n]
+	}()
+
+	// the producer reuses the buffer of a frame waiting for the flush interval
+	frame := []byte("abc")
+	assert.False(t, c.bufferWrite(NewBufferWrapper(frame)))
+	copy(frame, "xyz")
+	assert.False(t, c.bufferWrite(NewBufferWrapper([]byte("def"))))
+	c.flushWrites()
+	assert.Equal(t, "abcdef", string(<-received))
+}
+
+func TestSocketOptions(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer listener.Close()
+
+	c := newConnection(connectionOptions{
+		logicalAddr:       &url.URL{Scheme: "pulsar", Host: listener.Addr().String()},
+		physicalAddr:      &url.URL{Scheme: "pulsar", Host: listener.Addr().String()},
+		connectionTimeout: time.Second,
+		socketOptions: SocketOptions{
+			DisableNoDelay:    true,
+			SendBufferSize:    256 * 1024,
+			ReceiveBufferSize: 256 * 1024,
+			KeepAlive:         -1,
+		},
+		logger:  log.DefaultNopLogger(),
+		metrics: NewMetricsProvider(map[string]string{}),
+	})
+	assert.True(t, c.connect())
+	defer c.cnx.Close()
+	_, ok := c.cnx.(*net.TCPConn)
+	assert.True(t, ok)
+
+	// the options are only applied on TCP connections
+	client, server := net.Pipe()
+	defer server.Close()
+	assert.Nil(t, SocketOptions{SendBufferSize: 1024}.apply(client))
+}
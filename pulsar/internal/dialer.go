@@ -0,0 +1,79 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package internal
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync/atomic"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar/log"
+)
+
+// the lookup of the addresses of a host name, replaced in the tests
+var lookupIPAddr = net.DefaultResolver.LookupIPAddr
+
+// dialRotation picks the first address tried by each new connection
+var dialRotation uint32
+
+// dialRotating resolves the host name of the address on every call, so that the addresses of the brokers or
+// proxies behind a DNS name are refreshed on reconnection, and tries its addresses in turn starting from
+// the next one of each call, so that the connections are spread over all the addresses and skip the dead ones.
+// Like net.Dialer, the deadline of the context is shared between the addresses left to try.
+func dialRotating(ctx context.Context, d *net.Dialer, addr string, logger log.Logger) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	if net.ParseIP(host) != nil {
+		return d.DialContext(ctx, "tcp", addr)
+	}
+
+	ips, err := lookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	if len(ips) == 0 {
+		return nil, fmt.Errorf("no address found for host %s", host)
+	}
+
+	start := int(atomic.AddUint32(&dialRotation, 1) % uint32(len(ips)))
+	for i := 0; i < len(ips); i++ {
+		ip := ips[(start+i)%len(ips)]
+
+		dialCtx, cancel := ctx, func() {}
+		if deadline, ok := ctx.Deadline(); ok {
+			remaining := time.Until(deadline) / time.Duration(len(ips)-i)
+			dialCtx, cancel = context.WithTimeout(ctx, remaining)
+		}
+		cnx, dialErr := d.DialContext(dialCtx, "tcp", net.JoinHostPort(ip.String(), port))
+		cancel()
+		if dialErr == nil {
+			return cnx, nil
+		}
+
+		logger.WithError(dialErr).Warnf("Failed to connect to %s at %s", host, ip)
+		err = dialErr
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, err
+}
@@ -0,0 +1,93 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package internal
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/pulsar-client-go/pulsar/log"
+)
+
+func TestDialRotating(t *testing.T) {
+	first, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer first.Close()
+	port := first.Addr().(*net.TCPAddr).Port
+	second, err := net.Listen("tcp", "127.0.0.2:"+strconv.Itoa(port))
+	if err != nil {
+		t.Skip("127.0.0.2 is not a loopback address:", err)
+	}
+	defer second.Close()
+
+	resolved := 0
+	lookupIPAddr = func(ctx context.Context, host string) ([]net.IPAddr, error) {
+		resolved++
+		return []net.IPAddr{{IP: net.ParseIP("127.0.0.1")}, {IP: net.ParseIP("127.0.0.2")}}, nil
+	}
+	defer func() {
+		lookupIPAddr = net.DefaultResolver.LookupIPAddr
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	addr := "broker:" + strconv.Itoa(port)
+
+	// the addresses are resolved on each connection and used in turn
+	remotes := map[string]bool{}
+	for i := 0; i < 2; i++ {
+		cnx, err := dialRotating(ctx, &net.Dialer{}, addr, log.DefaultNopLogger())
+		assert.Nil(t, err)
+		remotes[cnx.RemoteAddr().(*net.TCPAddr).IP.String()] = true
+		cnx.Close()
+	}
+	assert.Equal(t, 2, resolved)
+	assert.Equal(t, map[string]bool{"127.0.0.1": true, "127.0.0.2": true}, remotes)
+
+	// the dead addresses are skipped
+	second.Close()
+	for i := 0; i < 2; i++ {
+		cnx, err := dialRotating(ctx, &net.Dialer{}, addr, log.DefaultNopLogger())
+		assert.Nil(t, err)
+		assert.Equal(t, "127.0.0.1", cnx.RemoteAddr().(*net.TCPAddr).IP.String())
+		cnx.Close()
+	}
+}
+
+func TestDialRotatingIP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer listener.Close()
+
+	lookupIPAddr = func(ctx context.Context, host string) ([]net.IPAddr, error) {
+		t.Error("Unexpected lookup of an IP address!")
+		return nil, nil
+	}
+	defer func() {
+		lookupIPAddr = net.DefaultResolver.LookupIPAddr
+	}()
+
+	cnx, err := dialRotating(context.Background(), &net.Dialer{}, listener.Addr().String(), log.DefaultNopLogger())
+	assert.Nil(t, err)
+	cnx.Close()
+}
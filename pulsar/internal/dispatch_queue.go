@@ -0,0 +1,138 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package internal
+
+import (
+	"sync"
+
+	pb "github.com/apache/pulsar-client-go/pulsar/internal/pulsar_proto"
+	"github.com/apache/pulsar-client-go/pulsar/log"
+)
+
+// consumerDispatchQueueSize is the number of frames queued for a consumer beyond which the consumer
+// withholds its permits
+const consumerDispatchQueueSize = 100
+
+type dispatchItem struct {
+	response          *pb.CommandMessage
+	headersAndPayload Buffer
+}
+
+// dispatchQueue passes the messages of a consumer to its handler from a goroutine of its own, so that a slow
+// handler doesn't stall the read loop of the connection and the other consumers sharing it.
+// The queue only holds the messages the broker pushed within the permits of the consumer, once it holds
+// more than its size the consumer is asked to withhold its permits until the handler catches up.
+type dispatchQueue struct {
+	handler ConsumerHandler
+	size    int
+	log     log.Logger
+
+	sync.Mutex
+	items       []dispatchItem
+	withholding bool
+	closed      bool
+	signalCh    chan struct{}
+}
+
+func newDispatchQueue(handler ConsumerHandler, size int, logger log.Logger) *dispatchQueue {
+	q := &dispatchQueue{
+		handler:  handler,
+		size:     size,
+		log:      logger,
+		signalCh: make(chan struct{}, 1),
+	}
+	go q.run()
+	return q
+}
+
+// push queues the message for the handler, the message is dropped when the queue is closed
+func (q *dispatchQueue) push(response *pb.CommandMessage, headersAndPayload Buffer) {
+	q.Lock()
+	defer q.Unlock()
+	if q.closed {
+		PutReadBuffer(headersAndPayload)
+		return
+	}
+
+	q.items = append(q.items, dispatchItem{response: response, headersAndPayload: headersAndPayload})
+	// the handler is notified under the lock to keep the notifications ordered
+	if !q.withholding && len(q.items) >= q.size {
+		q.withholding = true
+		q.handler.WithholdPermits(true)
+	}
+
+	select {
+	case q.signalCh <- struct{}{}:
+	default:
+	}
+}
+
+// pop returns the next message for the handler, ok is false when the queue is empty or closed
+func (q *dispatchQueue) pop() (item dispatchItem, ok bool) {
+	q.Lock()
+	defer q.Unlock()
+	if q.closed || len(q.items) == 0 {
+		return item, false
+	}
+
+	item = q.items[0]
+	q.items[0] = dispatchItem{}
+	q.items = q.items[1:]
+	if q.withholding && len(q.items) <= q.size/2 {
+		q.withholding = false
+		q.handler.WithholdPermits(false)
+	}
+	return item, true
+}
+
+func (q *dispatchQueue) run() {
+	for range q.signalCh {
+		for {
+			item, ok := q.pop()
+			if !ok {
+				break
+			}
+			if err := q.handler.MessageReceived(item.response, item.headersAndPayload); err != nil {
+				q.log.
+					WithError(err).
+					WithField("consumerID", item.response.GetConsumerId()).
+					Error("handle message Id: ", item.response.MessageId)
+			}
+		}
+	}
+}
+
+// close drops the queued messages and stops the goroutine of the queue, the handler may still be processing
+// the message it was passed last
+func (q *dispatchQueue) close() {
+	q.Lock()
+	defer q.Unlock()
+	if q.closed {
+		return
+	}
+	q.closed = true
+	for _, item := range q.items {
+		PutReadBuffer(item.headersAndPayload)
+	}
+	q.items = nil
+	if q.withholding {
+		q.withholding = false
+		q.handler.WithholdPermits(false)
+	}
+	close(q.signalCh)
+}
@@ -0,0 +1,137 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package internal
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+
+	pb "github.com/apache/pulsar-client-go/pulsar/internal/pulsar_proto"
+	"github.com/apache/pulsar-client-go/pulsar/log"
+)
+
+// blockingHandler records the messages it receives, each of them waiting for the unblock channel when set
+type blockingHandler struct {
+	reconnectingHandler
+	unblockCh chan struct{}
+
+	sync.Mutex
+	received  []uint64
+	withholds []bool
+}
+
+func (h *blockingHandler) MessageReceived(response *pb.CommandMessage, headersAndPayload Buffer) error {
+	if h.unblockCh != nil {
+		<-h.unblockCh
+	}
+	h.Lock()
+	defer h.Unlock()
+	h.received = append(h.received, response.GetMessageId().GetEntryId())
+	return nil
+}
+
+func (h *blockingHandler) WithholdPermits(withhold bool) {
+	h.Lock()
+	defer h.Unlock()
+	h.withholds = append(h.withholds, withhold)
+}
+
+func (h *blockingHandler) receivedCount() int {
+	h.Lock()
+	defer h.Unlock()
+	return len(h.received)
+}
+
+func (h *blockingHandler) withholdsCopy() []bool {
+	h.Lock()
+	defer h.Unlock()
+	return append([]bool(nil), h.withholds...)
+}
+
+func commandMessage(consumerID, entryID uint64) *pb.CommandMessage {
+	return &pb.CommandMessage{
+		ConsumerId: proto.Uint64(consumerID),
+		MessageId:  &pb.MessageIdData{LedgerId: proto.Uint64(1), EntryId: proto.Uint64(entryID)},
+	}
+}
+
+func TestDispatchQueueWithholdsPermits(t *testing.T) {
+	handler := &blockingHandler{unblockCh: make(chan struct{})}
+	q := newDispatchQueue(handler, 4, log.DefaultNopLogger())
+	defer q.close()
+
+	for i := 0; i < 5; i++ {
+		q.push(commandMessage(1, uint64(i)), NewBuffer(0))
+	}
+	// the handler holds the first message, the queue reached its size with the next ones
+	assert.Equal(t, []bool{true}, handler.withholdsCopy())
+
+	// the permits are given again once the queue is down to half its size
+	handler.unblockCh <- struct{}{}
+	handler.unblockCh <- struct{}{}
+	assert.Eventually(t, func() bool { return len(handler.withholdsCopy()) == 2 }, time.Second, time.Millisecond)
+	assert.Equal(t, []bool{true, false}, handler.withholdsCopy())
+
+	close(handler.unblockCh)
+	assert.Eventually(t, func() bool { return handler.receivedCount() == 5 }, time.Second, time.Millisecond)
+	handler.Lock()
+	assert.Equal(t, []uint64{0, 1, 2, 3, 4}, handler.received)
+	handler.Unlock()
+}
+
+func TestDispatchQueueClose(t *testing.T) {
+	handler := &blockingHandler{unblockCh: make(chan struct{})}
+	q := newDispatchQueue(handler, 2, log.DefaultNopLogger())
+
+	for i := 0; i < 3; i++ {
+		q.push(commandMessage(1, uint64(i)), NewBuffer(0))
+	}
+
+	// the queued messages are dropped and the permits aren't withheld anymore
+	q.close()
+	assert.Equal(t, []bool{true, false}, handler.withholdsCopy())
+	q.push(commandMessage(1, 3), NewBuffer(0))
+
+	close(handler.unblockCh)
+	time.Sleep(10 * time.Millisecond)
+	assert.LessOrEqual(t, handler.receivedCount(), 1)
+}
+
+func TestConnectionSlowConsumerDoesNotBlockOthers(t *testing.T) {
+	c, server := newPipedConnection()
+	defer server.Close()
+
+	slow := &blockingHandler{unblockCh: make(chan struct{})}
+	defer close(slow.unblockCh)
+	fast := &blockingHandler{}
+	assert.Nil(t, c.AddConsumeHandler(1, slow))
+	assert.Nil(t, c.AddConsumeHandler(2, fast))
+	defer c.DeleteConsumeHandler(1)
+	defer c.DeleteConsumeHandler(2)
+
+	for i := 0; i < 3; i++ {
+		c.handleMessage(commandMessage(1, uint64(i)), NewBuffer(0))
+		c.handleMessage(commandMessage(2, uint64(i)), NewBuffer(0))
+	}
+	assert.Eventually(t, func() bool { return fast.receivedCount() == 3 }, time.Second, time.Millisecond)
+	assert.Equal(t, 0, slow.receivedCount())
+}
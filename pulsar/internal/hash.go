@@ -17,16 +17,27 @@
 
 package internal
 
-import "github.com/spaolacci/murmur3"
+import (
+	"unicode"
+	"unicode/utf16"
 
-// JavaStringHash and Java String.hashCode() equivalent
+	"github.com/spaolacci/murmur3"
+)
+
+// JavaStringHash and Java String.hashCode() equivalent, computed over the UTF-16 code units of the string
 func JavaStringHash(s string) uint32 {
 	var h uint32
-	for i, size := 0, len(s); i < size; i++ {
-		h = 31*h + uint32(s[i])
+	for _, r := range s {
+		if r1, r2 := utf16.EncodeRune(r); r1 != unicode.ReplacementChar {
+			h = 31*h + uint32(r1)
+			h = 31*h + uint32(r2)
+		} else {
+			h = 31*h + uint32(r)
+		}
 	}
 
-	return h
+	// Maintain compatibility with values used in Java client
+	return h & 0x7fffffff
 }
 
 // Murmur3_32Hash use Murmur3 hashing function
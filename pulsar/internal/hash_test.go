@@ -33,12 +33,20 @@ var javaHashValues = []testProvider{
 	{"", 0x0},
 	{"hello", 0x5e918d2},
 	{"test", 0x364492},
+	// the values of the Java client, whose String.hashCode() is masked to a positive int
+	{"polygenelubricants", 0x0},
+	{"pulsar-client-go", 0x6716ebda},
+	{"héllo", 0x62519ce},
+	{"日本語", 0x18b8997},
+	{"😀key", 0x4c1c721c},
 }
 
 var murmurHashValues = []testProvider{
 	{"", 0x0},
 	{"hello", 0x248bfa47},
 	{"test", 0x3a6bd213},
+	{"héllo", 0x3c9fa068},
+	{"日本語", 0x25a47297},
 }
 
 func TestJavaHash(t *testing.T) {
@@ -0,0 +1,196 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package internal
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar/internal/auth"
+	"github.com/apache/pulsar-client-go/pulsar/log"
+)
+
+// HTTPClient sends requests to the HTTP service of the brokers
+type HTTPClient interface {
+	// Get sends a GET request to the given endpoint, an absolute path with an optional query,
+	// of any of the service hosts and decodes the JSON response into obj
+	Get(endpoint string, obj interface{}) error
+
+	Close()
+}
+
+// HTTPError is returned for the requests answered with another status than 200 OK
+type HTTPError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *HTTPError) Error() string {
+	return e.Message
+}
+
+type httpClient struct {
+	hc                  *http.Client
+	serviceNameResolver ServiceNameResolver
+	auth                auth.Provider
+	requestTimeout      time.Duration
+	log                 log.Logger
+}
+
+// NewHTTPClient creates a client of the HTTP service of the brokers, the TLS options are
+// only used by https service URLs.
+func NewHTTPClient(serviceURL *url.URL, serviceNameResolver ServiceNameResolver, tlsOptions *TLSOptions,
+	authProvider auth.Provider, requestTimeout time.Duration, logger log.Logger) (HTTPClient, error) {
+	transport := &http.Transport{}
+	if tlsOptions != nil {
+		tlsConfig, err := getHTTPTLSConfig(tlsOptions, authProvider)
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	return &httpClient{
+		hc: &http.Client{
+			Transport: transport,
+			Timeout:   requestTimeout,
+		},
+		serviceNameResolver: serviceNameResolver,
+		auth:                authProvider,
+		requestTimeout:      requestTimeout,
+		log:                 logger.SubLogger(log.Fields{"serviceURL": serviceURL}),
+	}, nil
+}
+
+func (c *httpClient) Get(endpoint string, obj interface{}) error {
+	err := c.get(endpoint, obj)
+	if _, ok := err.(net.Error); ok {
+		// Retry on the other hosts as the request is not specific to a particular broker
+		backoff := Backoff{100 * time.Millisecond}
+		startTime := time.Now()
+
+		for time.Since(startTime) < c.requestTimeout {
+			retryTime := backoff.Next()
+			c.log.Debugf("Retrying HTTP request in {%v} with timeout in {%v}", retryTime, c.requestTimeout)
+			time.Sleep(retryTime)
+
+			err = c.get(endpoint, obj)
+			if _, ok := err.(net.Error); !ok {
+				// We either succeeded or encountered a non connection error
+				break
+			}
+		}
+	}
+	return err
+}
+
+func (c *httpClient) get(endpoint string, obj interface{}) error {
+	host, err := c.serviceNameResolver.ResolveHost()
+	if err != nil {
+		return err
+	}
+
+	u, err := host.Parse(endpoint)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	if p, ok := c.auth.(auth.HTTPAuthProvider); ok {
+		headers, err := p.GetHTTPHeaders()
+		if err != nil {
+			return err
+		}
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+	}
+
+	resp, err := c.hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		c.log.Warnf("HTTP request to %s failed with status %d: %s", u.String(), resp.StatusCode, body)
+		return &HTTPError{
+			StatusCode: resp.StatusCode,
+			Message:    fmt.Sprintf("HTTP request to %s failed with status %s", endpoint, resp.Status),
+		}
+	}
+
+	return json.Unmarshal(body, obj)
+}
+
+func (c *httpClient) Close() {
+	if t, ok := c.hc.Transport.(*http.Transport); ok {
+		t.CloseIdleConnections()
+	}
+}
+
+func getHTTPTLSConfig(tlsOptions *TLSOptions, authProvider auth.Provider) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: tlsOptions.AllowInsecureConnection,
+	}
+
+	if tlsOptions.TrustCertsFilePath != "" {
+		caCerts, err := ioutil.ReadFile(tlsOptions.TrustCertsFilePath)
+		if err != nil {
+			return nil, err
+		}
+
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(caCerts) {
+			return nil, errors.New("failed to parse root CAs certificates")
+		}
+	}
+
+	if !tlsOptions.ValidateHostname && !tlsOptions.AllowInsecureConnection {
+		tlsConfig.InsecureSkipVerify = true
+		tlsConfig.VerifyPeerCertificate = verifyCertificateChain(tlsConfig.RootCAs)
+	}
+
+	// Ask the provider on every handshake, so that rotated certificates are picked up
+	tlsConfig.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+		cert, err := authProvider.GetTLSCertificate()
+		if err != nil || cert == nil {
+			return &tls.Certificate{}, err
+		}
+		return cert, nil
+	}
+
+	return tlsConfig, nil
+}
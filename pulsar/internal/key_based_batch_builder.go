@@ -38,6 +38,9 @@ import (
  * [(k1, v1), (k1, v2), (k1, v3)], [(k2, v1), (k2, v2), (k2, v3)], [(k3, v1), (k3, v2), (k3, v3)]
  */
 
+// maxFreeKeyContainers bounds the containers kept for reuse, each one holds a buffer sized for a full batch
+const maxFreeKeyContainers = 16
+
 // keyBasedBatches is a simple concurrent-safe map for the batchContainer type
 type keyBasedBatches struct {
 	containers map[string]*batchContainer
@@ -52,6 +55,9 @@ type keyBasedBatchContainer struct {
 	batchContainer
 	compressionType pb.CompressionType
 	level           compression.Level
+	// the containers of the keys of the flushed batches, reused with their metadata, command and buffer
+	// for the next keys, up to maxFreeKeyContainers
+	freeContainers []*batchContainer
 }
 
 // newKeyBasedBatches init a keyBasedBatches
@@ -83,7 +89,7 @@ func (h *keyBasedBatches) Val(key string) *batchContainer {
 // NewKeyBasedBatchBuilder init batch builder and return BatchBuilder
 // pointer. Build a new key based batch message container.
 func NewKeyBasedBatchBuilder(
-	maxMessages uint, maxBatchSize uint, producerName string, producerID uint64,
+	maxMessages uint, maxBatchSize uint, maxMessageSize uint32, producerName string, producerID uint64,
 	compressionType pb.CompressionType, level compression.Level,
 	bufferPool BuffersPool, logger log.Logger,
 ) (BatchBuilder, error) {
@@ -91,7 +97,7 @@ func NewKeyBasedBatchBuilder(
 	bb := &keyBasedBatchContainer{
 		batches: newKeyBasedBatches(),
 		batchContainer: newBatchContainer(
-			maxMessages, maxBatchSize, producerName, producerID,
+			maxMessages, maxBatchSize, maxMessageSize, producerName, producerID,
 			compressionType, level, bufferPool, logger,
 		),
 		compressionType: compressionType,
@@ -116,14 +122,15 @@ func (bc *keyBasedBatchContainer) IsMultiBatches() bool {
 
 func (bc *keyBasedBatchContainer) hasSpace(payload []byte) bool {
 	msgSize := uint32(len(payload))
-	return bc.numMessages > 0 && (bc.buffer.ReadableBytes()+msgSize) > uint32(bc.maxBatchSize)
+	return bc.numMessages > 0 && ((bc.buffer.ReadableBytes()+msgSize) > uint32(bc.maxBatchSize) ||
+		(bc.buffer.ReadableBytes()+msgSize) > bc.maxMessageSize)
 }
 
 // Add will add single message to key-based batch with message key.
 func (bc *keyBasedBatchContainer) Add(
 	metadata *pb.SingleMessageMetadata, sequenceIDGenerator *uint64,
 	payload []byte,
-	callback interface{}, replicateTo []string, deliverAt time.Time,
+	callback interface{}, replicateTo []string, deliverAt time.Time, txnID *TxnID,
 ) bool {
 	if replicateTo != nil && bc.numMessages != 0 {
 		// If the current batch is not empty and we're trying to set the replication clusters,
@@ -133,6 +140,9 @@ func (bc *keyBasedBatchContainer) Add(
 		// There's already a message with cluster replication list. need to flush before next
 		// message can be sent
 		return false
+	} else if bc.numMessages != 0 && (txnID != nil || bc.cmdSend.Send.TxnidMostBits != nil) {
+		// The messages of a transaction are sent in their own batch
+		return false
 	} else if bc.hasSpace(payload) {
 		// The current batch is full. Producer has to call Flush() to
 		return false
@@ -141,19 +151,24 @@ func (bc *keyBasedBatchContainer) Add(
 	var msgKey = getMessageKey(metadata)
 	batchPart := bc.batches.Val(msgKey)
 	if batchPart == nil {
-		// create batchContainer for new key
-		t := newBatchContainer(
-			bc.maxMessages, bc.maxBatchSize, bc.producerName, bc.producerID,
-			bc.compressionType, bc.level, bc.buffersPool, bc.log,
-		)
-		batchPart = &t
-		bc.batches.Add(msgKey, &t)
+		if n := len(bc.freeContainers); n > 0 {
+			batchPart = bc.freeContainers[n-1]
+			bc.freeContainers = bc.freeContainers[:n-1]
+		} else {
+			// create batchContainer for new key
+			t := newBatchContainer(
+				bc.maxMessages, bc.maxBatchSize, bc.maxMessageSize, bc.producerName, bc.producerID,
+				bc.compressionType, bc.level, bc.buffersPool, bc.log,
+			)
+			batchPart = &t
+		}
+		bc.batches.Add(msgKey, batchPart)
 	}
 
 	// add message to batch container
 	batchPart.Add(
 		metadata, sequenceIDGenerator, payload, callback, replicateTo,
-		deliverAt,
+		deliverAt, txnID,
 	)
 	addSingleMessageToBatch(bc.buffer, metadata, payload)
 
@@ -163,17 +178,24 @@ func (bc *keyBasedBatchContainer) Add(
 }
 
 func (bc *keyBasedBatchContainer) reset() {
-	bc.batches.l.RLock()
-	defer bc.batches.l.RUnlock()
-	for _, container := range bc.batches.containers {
+	bc.batches.l.Lock()
+	defer bc.batches.l.Unlock()
+	for key, container := range bc.batches.containers {
+		delete(bc.batches.containers, key)
+		if len(bc.freeContainers) >= maxFreeKeyContainers {
+			if err := container.Close(); err != nil {
+				bc.log.WithError(err).Warn("Failed to close the batch container of a key")
+			}
+			continue
+		}
 		container.reset()
+		bc.freeContainers = append(bc.freeContainers, container)
 	}
 	bc.numMessages = 0
 	bc.buffer.Clear()
-	bc.callbacks = []interface{}{}
+	bc.callbacks = make([]interface{}, 0, len(bc.callbacks))
 	bc.msgMetadata.ReplicateTo = nil
 	bc.msgMetadata.DeliverAtTime = nil
-	bc.batches.containers = map[string]*batchContainer{}
 }
 
 // Flush all the messages buffered in multiple batches and wait until all
@@ -196,7 +218,6 @@ func (bc *keyBasedBatchContainer) FlushBatches() (
 	callbacks = make([][]interface{}, batchesLen)
 
 	bc.batches.l.RLock()
-	defer bc.batches.l.RUnlock()
 	for k := range bc.batches.containers {
 		sortedKeys = append(sortedKeys, k)
 	}
@@ -211,6 +232,7 @@ func (bc *keyBasedBatchContainer) FlushBatches() (
 		}
 		idx++
 	}
+	bc.batches.l.RUnlock()
 
 	bc.reset()
 	return batchesData, sequenceIDs, callbacks
@@ -223,6 +245,11 @@ func (bc *keyBasedBatchContainer) Flush() (
 }
 
 func (bc *keyBasedBatchContainer) Close() error {
+	for _, container := range bc.freeContainers {
+		if err := container.Close(); err != nil {
+			bc.log.WithError(err).Warn("Failed to close the batch container of a key")
+		}
+	}
 	return bc.compressionProvider.Close()
 }
 
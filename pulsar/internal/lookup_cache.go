@@ -0,0 +1,121 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package internal
+
+import (
+	"net/url"
+	"sync"
+	"time"
+)
+
+// CachedLookupService is a LookupService keeping the topic lookup results for a while
+type CachedLookupService interface {
+	LookupService
+
+	// Invalidate drops the cached lookup result of the topic if it still points to the given broker,
+	// eg. after the connection to the broker failed or the broker closed the producer or consumer.
+	// All the results of the topic are dropped when logicalAddr is nil.
+	Invalidate(topic string, logicalAddr *url.URL)
+
+	// InvalidateAll drops all the cached lookup results
+	InvalidateAll()
+}
+
+type lookupCacheEntry struct {
+	result *LookupResult
+	expiry time.Time
+}
+
+type cachedLookupService struct {
+	LookupService
+
+	sync.Mutex
+	ttl       time.Duration
+	entries   map[string]*lookupCacheEntry
+	lastPrune time.Time
+}
+
+// NewCachedLookupService wraps the lookup service to cache the lookup results for the given ttl,
+// the results are not cached when the ttl is not positive.
+func NewCachedLookupService(lookupService LookupService, ttl time.Duration) CachedLookupService {
+	return &cachedLookupService{
+		LookupService: lookupService,
+		ttl:           ttl,
+		entries:       make(map[string]*lookupCacheEntry),
+		lastPrune:     time.Now(),
+	}
+}
+
+func (c *cachedLookupService) Lookup(topic string) (*LookupResult, error) {
+	if c.ttl <= 0 {
+		return c.LookupService.Lookup(topic)
+	}
+
+	now := time.Now()
+	c.Lock()
+	entry, ok := c.entries[topic]
+	c.Unlock()
+	if ok && now.Before(entry.expiry) {
+		return entry.result, nil
+	}
+
+	result, err := c.LookupService.Lookup(topic)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Lock()
+	defer c.Unlock()
+	c.entries[topic] = &lookupCacheEntry{
+		result: result,
+		expiry: now.Add(c.ttl),
+	}
+	c.pruneExpired(now)
+	return result, nil
+}
+
+func (c *cachedLookupService) Invalidate(topic string, logicalAddr *url.URL) {
+	c.Lock()
+	defer c.Unlock()
+	entry, ok := c.entries[topic]
+	if !ok {
+		return
+	}
+	if logicalAddr == nil || entry.result.LogicalAddr.String() == logicalAddr.String() {
+		delete(c.entries, topic)
+	}
+}
+
+func (c *cachedLookupService) InvalidateAll() {
+	c.Lock()
+	defer c.Unlock()
+	c.entries = make(map[string]*lookupCacheEntry)
+}
+
+// pruneExpired removes, at most once per ttl, the results of the topics that aren't looked up anymore
+func (c *cachedLookupService) pruneExpired(now time.Time) {
+	if now.Sub(c.lastPrune) < c.ttl {
+		return
+	}
+	for topic, entry := range c.entries {
+		if !now.Before(entry.expiry) {
+			delete(c.entries, topic)
+		}
+	}
+	c.lastPrune = now
+}
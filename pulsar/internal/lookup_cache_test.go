@@ -0,0 +1,138 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package internal
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	pb "github.com/apache/pulsar-client-go/pulsar/internal/pulsar_proto"
+)
+
+type countingLookupService struct {
+	lookups int
+	broker  string
+	err     error
+}
+
+func (s *countingLookupService) Lookup(topic string) (*LookupResult, error) {
+	s.lookups++
+	if s.err != nil {
+		return nil, s.err
+	}
+	addr, _ := url.Parse(s.broker)
+	return &LookupResult{LogicalAddr: addr, PhysicalAddr: addr}, nil
+}
+
+func (s *countingLookupService) GetPartitionedTopicMetadata(topic string) (*pb.CommandPartitionedTopicMetadataResponse,
+	error) {
+	return nil, nil
+}
+
+func (s *countingLookupService) GetTopicsOfNamespace(namespace string,
+	mode pb.CommandGetTopicsOfNamespace_Mode) ([]string, error) {
+	return nil, nil
+}
+
+func (s *countingLookupService) GetSchema(topic string, schemaVersion []byte) (*pb.Schema, error) {
+	return nil, nil
+}
+
+func TestLookupCacheHit(t *testing.T) {
+	ls := &countingLookupService{broker: "pulsar://broker-1:6650"}
+	cache := NewCachedLookupService(ls, time.Minute)
+
+	for i := 0; i < 3; i++ {
+		lr, err := cache.Lookup("my-topic")
+		assert.NoError(t, err)
+		assert.Equal(t, "pulsar://broker-1:6650", lr.LogicalAddr.String())
+	}
+	assert.Equal(t, 1, ls.lookups)
+
+	_, err := cache.Lookup("other-topic")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, ls.lookups)
+}
+
+func TestLookupCacheDisabled(t *testing.T) {
+	ls := &countingLookupService{broker: "pulsar://broker-1:6650"}
+	cache := NewCachedLookupService(ls, 0)
+
+	cache.Lookup("my-topic")
+	cache.Lookup("my-topic")
+	assert.Equal(t, 2, ls.lookups)
+}
+
+func TestLookupCacheExpiry(t *testing.T) {
+	ls := &countingLookupService{broker: "pulsar://broker-1:6650"}
+	cache := NewCachedLookupService(ls, 10*time.Millisecond)
+
+	cache.Lookup("my-topic")
+	time.Sleep(20 * time.Millisecond)
+	cache.Lookup("my-topic")
+	assert.Equal(t, 2, ls.lookups)
+}
+
+func TestLookupCacheErrorsNotCached(t *testing.T) {
+	ls := &countingLookupService{err: errors.New("lookup failed")}
+	cache := NewCachedLookupService(ls, time.Minute)
+
+	_, err := cache.Lookup("my-topic")
+	assert.Error(t, err)
+	_, err = cache.Lookup("my-topic")
+	assert.Error(t, err)
+	assert.Equal(t, 2, ls.lookups)
+}
+
+func TestLookupCacheInvalidate(t *testing.T) {
+	ls := &countingLookupService{broker: "pulsar://broker-1:6650"}
+	cache := NewCachedLookupService(ls, time.Minute)
+
+	cache.Lookup("my-topic")
+
+	// the result pointing to another broker is kept
+	other, _ := url.Parse("pulsar://broker-2:6650")
+	cache.Invalidate("my-topic", other)
+	cache.Lookup("my-topic")
+	assert.Equal(t, 1, ls.lookups)
+
+	failed, _ := url.Parse("pulsar://broker-1:6650")
+	cache.Invalidate("my-topic", failed)
+	ls.broker = "pulsar://broker-2:6650"
+	lr, err := cache.Lookup("my-topic")
+	assert.NoError(t, err)
+	assert.Equal(t, "pulsar://broker-2:6650", lr.LogicalAddr.String())
+	assert.Equal(t, 2, ls.lookups)
+
+	// another producer of the topic reconnecting from the same failed broker reuses the new result
+	cache.Invalidate("my-topic", failed)
+	cache.Lookup("my-topic")
+	assert.Equal(t, 2, ls.lookups)
+
+	cache.Invalidate("my-topic", nil)
+	cache.Lookup("my-topic")
+	assert.Equal(t, 3, ls.lookups)
+
+	cache.InvalidateAll()
+	cache.Lookup("my-topic")
+	assert.Equal(t, 4, ls.lookups)
+}
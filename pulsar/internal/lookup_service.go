@@ -18,8 +18,13 @@
 package internal
 
 import (
+	"context"
+	"encoding/binary"
 	"errors"
+	"fmt"
+	"net/http"
 	"net/url"
+	"strings"
 
 	"github.com/gogo/protobuf/proto"
 
@@ -42,23 +47,32 @@ type LookupService interface {
 	// GetPartitionedTopicMetadata perform a CommandPartitionedTopicMetadata request for
 	// the given topic, returns the CommandPartitionedTopicMetadataResponse as the result.
 	GetPartitionedTopicMetadata(topic string) (*pb.CommandPartitionedTopicMetadataResponse, error)
+
+	// GetTopicsOfNamespace returns the topics of the given namespace, filtered by their persistence.
+	GetTopicsOfNamespace(namespace string, mode pb.CommandGetTopicsOfNamespace_Mode) ([]string, error)
+
+	// GetSchema returns the schema of the topic at the given version, or its latest schema when the
+	// version is empty.
+	GetSchema(topic string, schemaVersion []byte) (*pb.Schema, error)
 }
 
 type lookupService struct {
 	rpcClient           RPCClient
 	serviceNameResolver ServiceNameResolver
 	tlsEnabled          bool
+	listenerName        string
 	log                 log.Logger
 	metrics             *Metrics
 }
 
 // NewLookupService init a lookup service struct and return an object of LookupService.
 func NewLookupService(rpcClient RPCClient, serviceURL *url.URL, serviceNameResolver ServiceNameResolver,
-	tlsEnabled bool, logger log.Logger, metrics *Metrics) LookupService {
+	tlsEnabled bool, listenerName string, logger log.Logger, metrics *Metrics) LookupService {
 	return &lookupService{
 		rpcClient:           rpcClient,
 		serviceNameResolver: serviceNameResolver,
 		tlsEnabled:          tlsEnabled,
+		listenerName:        listenerName,
 		log:                 logger.SubLogger(log.Fields{"serviceURL": serviceURL}),
 		metrics:             metrics,
 	}
@@ -89,16 +103,49 @@ func (ls *lookupService) getBrokerAddress(lr *pb.CommandLookupTopicResponse) (lo
 	return logicalAddress, physicalAddr, nil
 }
 
+// advertisedListenerName returns the listener the brokers should answer the lookups with, or nil to
+// get the default advertised addresses
+func (ls *lookupService) advertisedListenerName() *string {
+	if ls.listenerName == "" {
+		return nil
+	}
+	return proto.String(ls.listenerName)
+}
+
 // Follow brokers redirect up to certain number of times
 const lookupResultMaxRedirect = 20
 
+// ErrMaxLookupRedirect is returned when the brokers keep redirecting a lookup
+var ErrMaxLookupRedirect = errors.New("exceeded max number of redirection during topic lookup")
+
+// LookupError is returned when the broker fails to look up a topic
+type LookupError struct {
+	ServerError pb.ServerError
+	Message     string
+}
+
+func (e *LookupError) Error() string {
+	if e.Message == "" {
+		return e.ServerError.String()
+	}
+	return fmt.Sprintf("%s: %s", e.ServerError.String(), e.Message)
+}
+
 func (ls *lookupService) Lookup(topic string) (*LookupResult, error) {
 	ls.metrics.LookupRequestsCount.Inc()
+	// the broker is asked for the fully qualified name, the invalid names fail without a request
+	topicName, err := ParseTopicName(topic)
+	if err != nil {
+		return nil, err
+	}
+	topic = topicName.Name
+
 	id := ls.rpcClient.NewRequestID()
-	res, err := ls.rpcClient.RequestToAnyBroker(id, pb.BaseCommand_LOOKUP, &pb.CommandLookupTopic{
-		RequestId:     &id,
-		Topic:         &topic,
-		Authoritative: proto.Bool(false),
+	res, err := ls.rpcClient.RequestToAnyBroker(context.Background(), id, pb.BaseCommand_LOOKUP, &pb.CommandLookupTopic{
+		RequestId:              &id,
+		Topic:                  &topic,
+		Authoritative:          proto.Bool(false),
+		AdvertisedListenerName: ls.advertisedListenerName(),
 	})
 	if err != nil {
 		return nil, err
@@ -107,7 +154,11 @@ func (ls *lookupService) Lookup(topic string) (*LookupResult, error) {
 
 	for i := 0; i < lookupResultMaxRedirect; i++ {
 		lr := res.Response.LookupTopicResponse
-		switch *lr.Response {
+		if lr == nil {
+			return nil, fmt.Errorf("unexpected response to the lookup of topic %s: %s", topic, res.Response.GetType())
+		}
+
+		switch lr.GetResponse() {
 
 		case pb.CommandLookupTopicResponse_Redirect:
 			logicalAddress, physicalAddr, err := ls.getBrokerAddress(lr)
@@ -119,11 +170,13 @@ func (ls *lookupService) Lookup(topic string) (*LookupResult, error) {
 				topic, lr.BrokerServiceUrl, lr.BrokerServiceUrlTls, lr.ProxyThroughServiceUrl)
 
 			id := ls.rpcClient.NewRequestID()
-			res, err = ls.rpcClient.Request(logicalAddress, physicalAddr, id, pb.BaseCommand_LOOKUP, &pb.CommandLookupTopic{
-				RequestId:     &id,
-				Topic:         &topic,
-				Authoritative: lr.Authoritative,
-			})
+			res, err = ls.rpcClient.Request(context.Background(), logicalAddress, physicalAddr, id,
+				pb.BaseCommand_LOOKUP, &pb.CommandLookupTopic{
+					RequestId:              &id,
+					Topic:                  &topic,
+					Authoritative:          lr.Authoritative,
+					AdvertisedListenerName: ls.advertisedListenerName(),
+				})
 			if err != nil {
 				return nil, err
 			}
@@ -151,11 +204,17 @@ func (ls *lookupService) Lookup(topic string) (*LookupResult, error) {
 				"error":   lr.GetError(),
 				"message": lr.GetMessage(),
 			}).Warn("Failed to lookup topic")
-			return nil, errors.New(lr.GetError().String())
+			return nil, &LookupError{
+				ServerError: lr.GetError(),
+				Message:     lr.GetMessage(),
+			}
+
+		default:
+			return nil, fmt.Errorf("unknown lookup response type %d for topic %s", lr.GetResponse(), topic)
 		}
 	}
 
-	return nil, errors.New("exceeded max number of redirection during topic lookup")
+	return nil, ErrMaxLookupRedirect
 }
 
 func (ls *lookupService) GetPartitionedTopicMetadata(topic string) (*pb.CommandPartitionedTopicMetadataResponse,
@@ -167,7 +226,7 @@ func (ls *lookupService) GetPartitionedTopicMetadata(topic string) (*pb.CommandP
 	}
 
 	id := ls.rpcClient.NewRequestID()
-	res, err := ls.rpcClient.RequestToAnyBroker(id, pb.BaseCommand_PARTITIONED_METADATA,
+	res, err := ls.rpcClient.RequestToAnyBroker(context.Background(), id, pb.BaseCommand_PARTITIONED_METADATA,
 		&pb.CommandPartitionedTopicMetadata{
 			RequestId: &id,
 			Topic:     &topicName.Name,
@@ -179,3 +238,234 @@ func (ls *lookupService) GetPartitionedTopicMetadata(topic string) (*pb.CommandP
 
 	return res.Response.PartitionMetadataResponse, nil
 }
+
+func (ls *lookupService) GetTopicsOfNamespace(namespace string, mode pb.CommandGetTopicsOfNamespace_Mode) ([]string,
+	error) {
+	id := ls.rpcClient.NewRequestID()
+	res, err := ls.rpcClient.RequestToAnyBroker(context.Background(), id, pb.BaseCommand_GET_TOPICS_OF_NAMESPACE,
+		&pb.CommandGetTopicsOfNamespace{
+			RequestId: proto.Uint64(id),
+			Namespace: proto.String(namespace),
+			Mode:      mode.Enum(),
+		})
+	if err != nil {
+		return nil, err
+	}
+	if res.Response.Error != nil {
+		return []string{}, errors.New(res.Response.GetError().String())
+	}
+
+	return res.Response.GetTopicsOfNamespaceResponse.GetTopics(), nil
+}
+
+func (ls *lookupService) GetSchema(topic string, schemaVersion []byte) (*pb.Schema, error) {
+	topicName, err := ParseTopicName(topic)
+	if err != nil {
+		return nil, err
+	}
+
+	id := ls.rpcClient.NewRequestID()
+	cmd := &pb.CommandGetSchema{
+		RequestId: proto.Uint64(id),
+		Topic:     proto.String(topicName.Name),
+	}
+	if len(schemaVersion) > 0 {
+		cmd.SchemaVersion = schemaVersion
+	}
+	res, err := ls.rpcClient.RequestToAnyBroker(context.Background(), id, pb.BaseCommand_GET_SCHEMA, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	schemaResponse := res.Response.GetSchemaResponse
+	if schemaResponse.ErrorCode != nil {
+		return nil, &ServerError{Code: schemaResponse.GetErrorCode(), Message: schemaResponse.GetErrorMessage()}
+	}
+	ls.log.Debugf("Got topic{%s} schema response: %+v", topic, schemaResponse)
+
+	return schemaResponse.GetSchema(), nil
+}
+
+// lookupData is the response of the HTTP lookup endpoint
+type lookupData struct {
+	BrokerURL    string `json:"brokerUrl"`
+	BrokerURLTLS string `json:"brokerUrlTls"`
+	HTTPURL      string `json:"httpUrl"`
+	HTTPURLTLS   string `json:"httpUrlTls"`
+}
+
+// partitionedTopicMetadata is the response of the HTTP partitions endpoint
+type partitionedTopicMetadata struct {
+	Partitions int `json:"partitions"`
+}
+
+// schemaData is the response of the HTTP schema endpoint
+type schemaData struct {
+	Type       string            `json:"type"`
+	Data       string            `json:"data"`
+	Properties map[string]string `json:"properties"`
+}
+
+type httpLookupService struct {
+	httpClient HTTPClient
+	tlsEnabled bool
+	log        log.Logger
+	metrics    *Metrics
+}
+
+// NewHTTPLookupService init a lookup service using the REST endpoints of the brokers, for the http and
+// https service URLs.
+func NewHTTPLookupService(httpClient HTTPClient, serviceURL *url.URL, tlsEnabled bool, logger log.Logger,
+	metrics *Metrics) LookupService {
+	return &httpLookupService{
+		httpClient: httpClient,
+		tlsEnabled: tlsEnabled,
+		log:        logger.SubLogger(log.Fields{"serviceURL": serviceURL}),
+		metrics:    metrics,
+	}
+}
+
+func (h *httpLookupService) Lookup(topic string) (*LookupResult, error) {
+	h.metrics.LookupRequestsCount.Inc()
+	topicName, err := ParseTopicName(topic)
+	if err != nil {
+		return nil, err
+	}
+
+	basePath := "/lookup/v2/topic/"
+	if isLegacyTopicName(topicName) {
+		basePath = "/lookup/v2/destination/"
+	}
+
+	ld := &lookupData{}
+	if err := h.httpClient.Get(basePath+lookupName(topicName), ld); err != nil {
+		return nil, httpLookupError(err)
+	}
+	h.log.Debugf("Successfully looked up topic{%s} on broker. %s / %s", topic, ld.BrokerURL, ld.BrokerURLTLS)
+
+	var brokerURL string
+	if h.tlsEnabled {
+		brokerURL = ld.BrokerURLTLS
+	} else {
+		brokerURL = ld.BrokerURL
+	}
+	addr, err := url.ParseRequestURI(brokerURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LookupResult{
+		LogicalAddr:  addr,
+		PhysicalAddr: addr,
+	}, nil
+}
+
+func (h *httpLookupService) GetPartitionedTopicMetadata(topic string) (*pb.CommandPartitionedTopicMetadataResponse,
+	error) {
+	h.metrics.PartitionedTopicMetadataRequestsCount.Inc()
+	topicName, err := ParseTopicName(topic)
+	if err != nil {
+		return nil, err
+	}
+
+	format := "/admin/v2/%s/partitions"
+	if isLegacyTopicName(topicName) {
+		format = "/admin/%s/partitions"
+	}
+
+	tm := &partitionedTopicMetadata{}
+	if err := h.httpClient.Get(fmt.Sprintf(format, lookupName(topicName)), tm); err != nil {
+		return nil, httpLookupError(err)
+	}
+	h.log.Debugf("Got topic{%s} partitioned metadata response: %+v", topic, tm)
+
+	return &pb.CommandPartitionedTopicMetadataResponse{
+		Partitions: proto.Uint32(uint32(tm.Partitions)),
+		Response:   pb.CommandPartitionedTopicMetadataResponse_Success.Enum(),
+	}, nil
+}
+
+func (h *httpLookupService) GetTopicsOfNamespace(namespace string, mode pb.CommandGetTopicsOfNamespace_Mode) ([]string,
+	error) {
+	endpoint := fmt.Sprintf("/admin/v2/namespaces/%s/topics?mode=%s", namespace, mode.String())
+	if strings.Count(namespace, "/") == 2 {
+		// legacy namespace including the cluster name
+		endpoint = fmt.Sprintf("/admin/namespaces/%s/destinations?mode=%s", namespace, mode.String())
+	}
+
+	topics := []string{}
+	if err := h.httpClient.Get(endpoint, &topics); err != nil {
+		return nil, httpLookupError(err)
+	}
+	return topics, nil
+}
+
+func (h *httpLookupService) GetSchema(topic string, schemaVersion []byte) (*pb.Schema, error) {
+	topicName, err := ParseTopicName(topic)
+	if err != nil {
+		return nil, err
+	}
+
+	localName := strings.TrimPrefix(topicName.Name, topicName.Domain+"://"+topicName.Namespace+"/")
+	endpoint := fmt.Sprintf("/admin/v2/schemas/%s/%s/schema", topicName.Namespace, url.PathEscape(localName))
+	if len(schemaVersion) == 8 {
+		endpoint += fmt.Sprintf("/%d", int64(binary.BigEndian.Uint64(schemaVersion)))
+	}
+
+	sd := &schemaData{}
+	if err := h.httpClient.Get(endpoint, sd); err != nil {
+		return nil, err
+	}
+	h.log.Debugf("Got topic{%s} schema response: %+v", topic, sd)
+
+	schemaType, err := parseSchemaType(sd.Type)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.Schema{
+		Name:       proto.String(topicName.Name),
+		SchemaData: []byte(sd.Data),
+		Type:       schemaType.Enum(),
+		Properties: ConvertFromStringMap(sd.Properties),
+	}, nil
+}
+
+// httpLookupError converts the statuses of the brokers which can't serve the HTTP lookups for a while, eg. during
+// the unload of the namespace bundle of the topic, into the lookup errors the binary protocol answers with
+func httpLookupError(err error) error {
+	httpErr, ok := err.(*HTTPError)
+	if !ok {
+		return err
+	}
+	switch httpErr.StatusCode {
+	case http.StatusServiceUnavailable:
+		return &LookupError{ServerError: pb.ServerError_ServiceNotReady, Message: httpErr.Message}
+	case http.StatusTooManyRequests:
+		return &LookupError{ServerError: pb.ServerError_TooManyRequests, Message: httpErr.Message}
+	}
+	return err
+}
+
+// parseSchemaType parses the schema type names of the REST endpoints, eg. AVRO or BOOLEAN
+func parseSchemaType(name string) (pb.Schema_Type, error) {
+	if strings.EqualFold(name, "BOOLEAN") {
+		return pb.Schema_Bool, nil
+	}
+	for n, v := range pb.Schema_Type_value {
+		if strings.EqualFold(name, n) {
+			return pb.Schema_Type(v), nil
+		}
+	}
+	return pb.Schema_None, fmt.Errorf("unknown schema type %s", name)
+}
+
+func isLegacyTopicName(tn *TopicName) bool {
+	return strings.Count(tn.Namespace, "/") == 2
+}
+
+// lookupName returns the topic name in the form used by the REST endpoints:
+// <domain>/<namespace>/<encoded local name>
+func lookupName(tn *TopicName) string {
+	localName := strings.TrimPrefix(tn.Name, tn.Domain+"://"+tn.Namespace+"/")
+	return tn.Domain + "/" + tn.Namespace + "/" + url.PathEscape(localName)
+}
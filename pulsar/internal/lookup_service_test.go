@@ -18,12 +18,19 @@
 package internal
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/gogo/protobuf/proto"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/apache/pulsar-client-go/pulsar/internal/auth"
 	pb "github.com/apache/pulsar-client-go/pulsar/internal/pulsar_proto"
 	"github.com/apache/pulsar-client-go/pulsar/log"
 )
@@ -51,8 +58,8 @@ func (c *mockedLookupRPCClient) NewConsumerID() uint64 {
 	return 1
 }
 
-func (c *mockedLookupRPCClient) RequestToAnyBroker(requestID uint64, cmdType pb.BaseCommand_Type,
-	message proto.Message) (*RPCResult, error) {
+func (c *mockedLookupRPCClient) RequestToAnyBroker(ctx context.Context, requestID uint64,
+	cmdType pb.BaseCommand_Type, message proto.Message) (*RPCResult, error) {
 	assert.Equal(c.t, cmdType, pb.BaseCommand_LOOKUP)
 
 	expectedRequest := &c.expectedRequests[0]
@@ -71,8 +78,8 @@ func (c *mockedLookupRPCClient) RequestToAnyBroker(requestID uint64, cmdType pb.
 	}, nil
 }
 
-func (c *mockedLookupRPCClient) Request(logicalAddr *url.URL, physicalAddr *url.URL, requestID uint64,
-	cmdType pb.BaseCommand_Type, message proto.Message) (*RPCResult, error) {
+func (c *mockedLookupRPCClient) Request(ctx context.Context, logicalAddr *url.URL, physicalAddr *url.URL,
+	requestID uint64, cmdType pb.BaseCommand_Type, message proto.Message) (*RPCResult, error) {
 	assert.Equal(c.t, cmdType, pb.BaseCommand_LOOKUP)
 	expectedRequest := &c.expectedRequests[0]
 	c.expectedRequests = c.expectedRequests[1:]
@@ -93,8 +100,15 @@ func (c *mockedLookupRPCClient) Request(logicalAddr *url.URL, physicalAddr *url.
 	}, nil
 }
 
-func (c *mockedLookupRPCClient) RequestOnCnx(cnx Connection, requestID uint64, cmdType pb.BaseCommand_Type,
-	message proto.Message) (*RPCResult, error) {
+func (c *mockedLookupRPCClient) RequestOnCnx(ctx context.Context, cnx Connection, requestID uint64,
+	cmdType pb.BaseCommand_Type, message proto.Message) (*RPCResult, error) {
+	assert.Fail(c.t, "Shouldn't be called")
+	return nil, nil
+}
+
+func (c *mockedLookupRPCClient) RequestWithQueuedCallback(ctx context.Context, logicalAddr *url.URL,
+	physicalAddr *url.URL, requestID uint64, cmdType pb.BaseCommand_Type, message proto.Message,
+	onQueued func()) (*RPCResult, error) {
 	assert.Fail(c.t, "Shouldn't be called")
 	return nil, nil
 }
@@ -112,7 +126,7 @@ func responseType(r pb.CommandLookupTopicResponse_LookupType) *pb.CommandLookupT
 func TestLookupSuccess(t *testing.T) {
 	url, err := url.Parse("pulsar://example:6650")
 	assert.NoError(t, err)
-	serviceNameResolver := NewPulsarServiceNameResolver(url)
+	serviceNameResolver := newTestServiceNameResolver(t, url)
 
 	ls := NewLookupService(&mockedLookupRPCClient{
 		t: t,
@@ -120,7 +134,7 @@ func TestLookupSuccess(t *testing.T) {
 		expectedRequests: []pb.CommandLookupTopic{
 			{
 				RequestId:     proto.Uint64(1),
-				Topic:         proto.String("my-topic"),
+				Topic:         proto.String("persistent://public/default/my-topic"),
 				Authoritative: proto.Bool(false),
 			},
 		},
@@ -132,7 +146,7 @@ func TestLookupSuccess(t *testing.T) {
 				BrokerServiceUrl: proto.String("pulsar://broker-1:6650"),
 			},
 		},
-	}, url, serviceNameResolver, false, log.DefaultNopLogger(), NewMetricsProvider(map[string]string{}))
+	}, url, serviceNameResolver, false, "", log.DefaultNopLogger(), NewMetricsProvider(map[string]string{}))
 
 	lr, err := ls.Lookup("my-topic")
 	assert.NoError(t, err)
@@ -142,10 +156,23 @@ func TestLookupSuccess(t *testing.T) {
 	assert.Equal(t, "pulsar://broker-1:6650", lr.PhysicalAddr.String())
 }
 
+func TestLookupInvalidTopicName(t *testing.T) {
+	url, err := url.Parse("pulsar://example:6650")
+	assert.NoError(t, err)
+	serviceNameResolver := newTestServiceNameResolver(t, url)
+
+	// no request is sent for an invalid name
+	ls := NewLookupService(&mockedLookupRPCClient{t: t}, url, serviceNameResolver, false, "",
+		log.DefaultNopLogger(), NewMetricsProvider(map[string]string{}))
+
+	_, err = ls.Lookup("persistent://invalid tenant/default/my-topic")
+	assert.Error(t, err)
+}
+
 func TestTlsLookupSuccess(t *testing.T) {
 	url, err := url.Parse("pulsar+ssl://example:6651")
 	assert.NoError(t, err)
-	serviceNameResolver := NewPulsarServiceNameResolver(url)
+	serviceNameResolver := newTestServiceNameResolver(t, url)
 
 	ls := NewLookupService(&mockedLookupRPCClient{
 		t: t,
@@ -153,7 +180,7 @@ func TestTlsLookupSuccess(t *testing.T) {
 		expectedRequests: []pb.CommandLookupTopic{
 			{
 				RequestId:     proto.Uint64(1),
-				Topic:         proto.String("my-topic"),
+				Topic:         proto.String("persistent://public/default/my-topic"),
 				Authoritative: proto.Bool(false),
 			},
 		},
@@ -165,7 +192,7 @@ func TestTlsLookupSuccess(t *testing.T) {
 				BrokerServiceUrlTls: proto.String("pulsar+ssl://broker-1:6651"),
 			},
 		},
-	}, url, serviceNameResolver, true, log.DefaultNopLogger(), NewMetricsProvider(map[string]string{}))
+	}, url, serviceNameResolver, true, "", log.DefaultNopLogger(), NewMetricsProvider(map[string]string{}))
 
 	lr, err := ls.Lookup("my-topic")
 	assert.NoError(t, err)
@@ -178,7 +205,7 @@ func TestTlsLookupSuccess(t *testing.T) {
 func TestLookupWithProxy(t *testing.T) {
 	url, err := url.Parse("pulsar://example:6650")
 	assert.NoError(t, err)
-	serviceNameResolver := NewPulsarServiceNameResolver(url)
+	serviceNameResolver := newTestServiceNameResolver(t, url)
 
 	ls := NewLookupService(&mockedLookupRPCClient{
 		t: t,
@@ -186,7 +213,7 @@ func TestLookupWithProxy(t *testing.T) {
 		expectedRequests: []pb.CommandLookupTopic{
 			{
 				RequestId:     proto.Uint64(1),
-				Topic:         proto.String("my-topic"),
+				Topic:         proto.String("persistent://public/default/my-topic"),
 				Authoritative: proto.Bool(false),
 			},
 		},
@@ -199,7 +226,7 @@ func TestLookupWithProxy(t *testing.T) {
 				ProxyThroughServiceUrl: proto.Bool(true),
 			},
 		},
-	}, url, serviceNameResolver, false, log.DefaultNopLogger(), NewMetricsProvider(map[string]string{}))
+	}, url, serviceNameResolver, false, "", log.DefaultNopLogger(), NewMetricsProvider(map[string]string{}))
 
 	lr, err := ls.Lookup("my-topic")
 	assert.NoError(t, err)
@@ -219,7 +246,7 @@ func TestTlsLookupWithProxy(t *testing.T) {
 		expectedRequests: []pb.CommandLookupTopic{
 			{
 				RequestId:     proto.Uint64(1),
-				Topic:         proto.String("my-topic"),
+				Topic:         proto.String("persistent://public/default/my-topic"),
 				Authoritative: proto.Bool(false),
 			},
 		},
@@ -232,7 +259,7 @@ func TestTlsLookupWithProxy(t *testing.T) {
 				ProxyThroughServiceUrl: proto.Bool(true),
 			},
 		},
-	}, url, NewPulsarServiceNameResolver(url), true, log.DefaultNopLogger(), NewMetricsProvider(map[string]string{}))
+	}, url, newTestServiceNameResolver(t, url), true, "", log.DefaultNopLogger(), NewMetricsProvider(map[string]string{}))
 
 	lr, err := ls.Lookup("my-topic")
 	assert.NoError(t, err)
@@ -253,12 +280,12 @@ func TestLookupWithRedirect(t *testing.T) {
 		expectedRequests: []pb.CommandLookupTopic{
 			{
 				RequestId:     proto.Uint64(1),
-				Topic:         proto.String("my-topic"),
+				Topic:         proto.String("persistent://public/default/my-topic"),
 				Authoritative: proto.Bool(false),
 			},
 			{
 				RequestId:     proto.Uint64(2),
-				Topic:         proto.String("my-topic"),
+				Topic:         proto.String("persistent://public/default/my-topic"),
 				Authoritative: proto.Bool(true),
 			},
 		},
@@ -276,7 +303,7 @@ func TestLookupWithRedirect(t *testing.T) {
 				BrokerServiceUrl: proto.String("pulsar://broker-1:6650"),
 			},
 		},
-	}, url, NewPulsarServiceNameResolver(url), false, log.DefaultNopLogger(), NewMetricsProvider(map[string]string{}))
+	}, url, newTestServiceNameResolver(t, url), false, "", log.DefaultNopLogger(), NewMetricsProvider(map[string]string{}))
 
 	lr, err := ls.Lookup("my-topic")
 	assert.NoError(t, err)
@@ -297,12 +324,12 @@ func TestTlsLookupWithRedirect(t *testing.T) {
 		expectedRequests: []pb.CommandLookupTopic{
 			{
 				RequestId:     proto.Uint64(1),
-				Topic:         proto.String("my-topic"),
+				Topic:         proto.String("persistent://public/default/my-topic"),
 				Authoritative: proto.Bool(false),
 			},
 			{
 				RequestId:     proto.Uint64(2),
-				Topic:         proto.String("my-topic"),
+				Topic:         proto.String("persistent://public/default/my-topic"),
 				Authoritative: proto.Bool(true),
 			},
 		},
@@ -320,7 +347,7 @@ func TestTlsLookupWithRedirect(t *testing.T) {
 				BrokerServiceUrlTls: proto.String("pulsar+ssl://broker-1:6651"),
 			},
 		},
-	}, url, NewPulsarServiceNameResolver(url), true, log.DefaultNopLogger(), NewMetricsProvider(map[string]string{}))
+	}, url, newTestServiceNameResolver(t, url), true, "", log.DefaultNopLogger(), NewMetricsProvider(map[string]string{}))
 
 	lr, err := ls.Lookup("my-topic")
 	assert.NoError(t, err)
@@ -340,7 +367,7 @@ func TestLookupWithInvalidUrlResponse(t *testing.T) {
 		expectedRequests: []pb.CommandLookupTopic{
 			{
 				RequestId:     proto.Uint64(1),
-				Topic:         proto.String("my-topic"),
+				Topic:         proto.String("persistent://public/default/my-topic"),
 				Authoritative: proto.Bool(false),
 			},
 		},
@@ -353,7 +380,7 @@ func TestLookupWithInvalidUrlResponse(t *testing.T) {
 				ProxyThroughServiceUrl: proto.Bool(false),
 			},
 		},
-	}, url, NewPulsarServiceNameResolver(url), false, log.DefaultNopLogger(), NewMetricsProvider(map[string]string{}))
+	}, url, newTestServiceNameResolver(t, url), false, "", log.DefaultNopLogger(), NewMetricsProvider(map[string]string{}))
 
 	lr, err := ls.Lookup("my-topic")
 	assert.Error(t, err)
@@ -370,7 +397,7 @@ func TestLookupWithLookupFailure(t *testing.T) {
 		expectedRequests: []pb.CommandLookupTopic{
 			{
 				RequestId:     proto.Uint64(1),
-				Topic:         proto.String("my-topic"),
+				Topic:         proto.String("persistent://public/default/my-topic"),
 				Authoritative: proto.Bool(false),
 			},
 		},
@@ -381,13 +408,76 @@ func TestLookupWithLookupFailure(t *testing.T) {
 				Authoritative: proto.Bool(true),
 			},
 		},
-	}, url, NewPulsarServiceNameResolver(url), false, log.DefaultNopLogger(), NewMetricsProvider(map[string]string{}))
+	}, url, newTestServiceNameResolver(t, url), false, "", log.DefaultNopLogger(), NewMetricsProvider(map[string]string{}))
 
 	lr, err := ls.Lookup("my-topic")
 	assert.Error(t, err)
 	assert.Nil(t, lr)
 }
 
+func TestLookupFailureError(t *testing.T) {
+	url, err := url.Parse("pulsar://example:6650")
+	assert.NoError(t, err)
+
+	ls := NewLookupService(&mockedLookupRPCClient{
+		t: t,
+
+		expectedRequests: []pb.CommandLookupTopic{
+			{
+				RequestId:     proto.Uint64(1),
+				Topic:         proto.String("persistent://public/default/my-topic"),
+				Authoritative: proto.Bool(false),
+			},
+		},
+		mockedResponses: []pb.CommandLookupTopicResponse{
+			{
+				RequestId: proto.Uint64(1),
+				Response:  responseType(pb.CommandLookupTopicResponse_Failed),
+				Error:     pb.ServerError_TopicNotFound.Enum(),
+				Message:   proto.String("topic not found"),
+			},
+		},
+	}, url, newTestServiceNameResolver(t, url), false, "", log.DefaultNopLogger(), NewMetricsProvider(map[string]string{}))
+
+	lr, err := ls.Lookup("my-topic")
+	assert.Nil(t, lr)
+	lookupErr, ok := err.(*LookupError)
+	assert.True(t, ok)
+	assert.Equal(t, pb.ServerError_TopicNotFound, lookupErr.ServerError)
+	assert.Equal(t, "TopicNotFound: topic not found", lookupErr.Error())
+}
+
+func TestLookupWithTooManyRedirects(t *testing.T) {
+	url, err := url.Parse("pulsar://example:6650")
+	assert.NoError(t, err)
+
+	rpcClient := &mockedLookupRPCClient{
+		t:           t,
+		expectedURL: "pulsar://broker-2:6650",
+	}
+	// the initial lookup plus one request per followed redirect
+	for i := 1; i <= lookupResultMaxRedirect+1; i++ {
+		rpcClient.expectedRequests = append(rpcClient.expectedRequests, pb.CommandLookupTopic{
+			RequestId:     proto.Uint64(uint64(i)),
+			Topic:         proto.String("persistent://public/default/my-topic"),
+			Authoritative: proto.Bool(i > 1),
+		})
+		rpcClient.mockedResponses = append(rpcClient.mockedResponses, pb.CommandLookupTopicResponse{
+			RequestId:        proto.Uint64(uint64(i)),
+			Response:         responseType(pb.CommandLookupTopicResponse_Redirect),
+			Authoritative:    proto.Bool(true),
+			BrokerServiceUrl: proto.String("pulsar://broker-2:6650"),
+		})
+	}
+
+	ls := NewLookupService(rpcClient, url, newTestServiceNameResolver(t, url), false, "", log.DefaultNopLogger(),
+		NewMetricsProvider(map[string]string{}))
+
+	lr, err := ls.Lookup("my-topic")
+	assert.Nil(t, lr)
+	assert.Equal(t, ErrMaxLookupRedirect, err)
+}
+
 type mockedPartitionedTopicMetadataRPCClient struct {
 	requestIDGenerator uint64
 	t                  *testing.T
@@ -409,8 +499,8 @@ func (m mockedPartitionedTopicMetadataRPCClient) NewConsumerID() uint64 {
 	return 1
 }
 
-func (m mockedPartitionedTopicMetadataRPCClient) RequestToAnyBroker(requestID uint64, cmdType pb.BaseCommand_Type,
-	message proto.Message) (*RPCResult, error) {
+func (m mockedPartitionedTopicMetadataRPCClient) RequestToAnyBroker(ctx context.Context, requestID uint64,
+	cmdType pb.BaseCommand_Type, message proto.Message) (*RPCResult, error) {
 	assert.Equal(m.t, cmdType, pb.BaseCommand_PARTITIONED_METADATA)
 
 	expectedRequest := &m.expectedRequests[0]
@@ -429,8 +519,15 @@ func (m mockedPartitionedTopicMetadataRPCClient) RequestToAnyBroker(requestID ui
 	}, nil
 }
 
-func (m mockedPartitionedTopicMetadataRPCClient) Request(logicalAddr *url.URL, physicalAddr *url.URL, requestID uint64,
-	cmdType pb.BaseCommand_Type, message proto.Message) (*RPCResult, error) {
+func (m mockedPartitionedTopicMetadataRPCClient) Request(ctx context.Context, logicalAddr *url.URL,
+	physicalAddr *url.URL, requestID uint64, cmdType pb.BaseCommand_Type, message proto.Message) (*RPCResult, error) {
+	assert.Fail(m.t, "Shouldn't be called")
+	return nil, nil
+}
+
+func (m mockedPartitionedTopicMetadataRPCClient) RequestWithQueuedCallback(ctx context.Context,
+	logicalAddr *url.URL, physicalAddr *url.URL, requestID uint64, cmdType pb.BaseCommand_Type,
+	message proto.Message, onQueued func()) (*RPCResult, error) {
 	assert.Fail(m.t, "Shouldn't be called")
 	return nil, nil
 }
@@ -441,7 +538,7 @@ func (m mockedPartitionedTopicMetadataRPCClient) RequestOnCnxNoWait(cnx Connecti
 	return nil
 }
 
-func (m mockedPartitionedTopicMetadataRPCClient) RequestOnCnx(cnx Connection, requestID uint64,
+func (m mockedPartitionedTopicMetadataRPCClient) RequestOnCnx(ctx context.Context, cnx Connection, requestID uint64,
 	cmdType pb.BaseCommand_Type, message proto.Message) (*RPCResult, error) {
 	assert.Fail(m.t, "Shouldn't be called")
 	return nil, nil
@@ -450,7 +547,7 @@ func (m mockedPartitionedTopicMetadataRPCClient) RequestOnCnx(cnx Connection, re
 func TestGetPartitionedTopicMetadataSuccess(t *testing.T) {
 	url, err := url.Parse("pulsar://example:6650")
 	assert.NoError(t, err)
-	serviceNameResolver := NewPulsarServiceNameResolver(url)
+	serviceNameResolver := newTestServiceNameResolver(t, url)
 
 	ls := NewLookupService(&mockedPartitionedTopicMetadataRPCClient{
 		t: t,
@@ -468,7 +565,7 @@ func TestGetPartitionedTopicMetadataSuccess(t *testing.T) {
 				Response:   pb.CommandPartitionedTopicMetadataResponse_Success.Enum(),
 			},
 		},
-	}, url, serviceNameResolver, false, log.DefaultNopLogger(), NewMetricsProvider(map[string]string{}))
+	}, url, serviceNameResolver, false, "", log.DefaultNopLogger(), NewMetricsProvider(map[string]string{}))
 
 	metadata, err := ls.GetPartitionedTopicMetadata("my-topic")
 	assert.NoError(t, err)
@@ -479,7 +576,7 @@ func TestGetPartitionedTopicMetadataSuccess(t *testing.T) {
 func TestLookupSuccessWithMultipleHosts(t *testing.T) {
 	url, err := url.Parse("pulsar://host1,host2,host3:6650")
 	assert.NoError(t, err)
-	serviceNameResolver := NewPulsarServiceNameResolver(url)
+	serviceNameResolver := newTestServiceNameResolver(t, url)
 
 	ls := NewLookupService(&mockedLookupRPCClient{
 		t: t,
@@ -487,7 +584,7 @@ func TestLookupSuccessWithMultipleHosts(t *testing.T) {
 		expectedRequests: []pb.CommandLookupTopic{
 			{
 				RequestId:     proto.Uint64(1),
-				Topic:         proto.String("my-topic"),
+				Topic:         proto.String("persistent://public/default/my-topic"),
 				Authoritative: proto.Bool(false),
 			},
 		},
@@ -499,12 +596,250 @@ func TestLookupSuccessWithMultipleHosts(t *testing.T) {
 				BrokerServiceUrl: proto.String("pulsar://broker-1:6650"),
 			},
 		},
-	}, url, serviceNameResolver, false, log.DefaultNopLogger(), NewMetricsProvider(map[string]string{}))
+	}, url, serviceNameResolver, false, "", log.DefaultNopLogger(), NewMetricsProvider(map[string]string{}))
+
+	lr, err := ls.Lookup("my-topic")
+	assert.NoError(t, err)
+	assert.NotNil(t, lr)
+
+	assert.Equal(t, "pulsar://broker-1:6650", lr.LogicalAddr.String())
+	assert.Equal(t, "pulsar://broker-1:6650", lr.PhysicalAddr.String())
+}
+
+func TestLookupWithListenerName(t *testing.T) {
+	url, err := url.Parse("pulsar://example:6650")
+	assert.NoError(t, err)
+
+	ls := NewLookupService(&mockedLookupRPCClient{
+		t:           t,
+		expectedURL: "pulsar://broker-2-external:6650",
+
+		expectedRequests: []pb.CommandLookupTopic{
+			{
+				RequestId:              proto.Uint64(1),
+				Topic:                  proto.String("persistent://public/default/my-topic"),
+				Authoritative:          proto.Bool(false),
+				AdvertisedListenerName: proto.String("external"),
+			},
+			{
+				RequestId:              proto.Uint64(2),
+				Topic:                  proto.String("persistent://public/default/my-topic"),
+				Authoritative:          proto.Bool(true),
+				AdvertisedListenerName: proto.String("external"),
+			},
+		},
+		mockedResponses: []pb.CommandLookupTopicResponse{
+			{
+				RequestId:        proto.Uint64(1),
+				Response:         responseType(pb.CommandLookupTopicResponse_Redirect),
+				Authoritative:    proto.Bool(true),
+				BrokerServiceUrl: proto.String("pulsar://broker-2-external:6650"),
+			},
+			{
+				RequestId:        proto.Uint64(2),
+				Response:         responseType(pb.CommandLookupTopicResponse_Connect),
+				Authoritative:    proto.Bool(true),
+				BrokerServiceUrl: proto.String("pulsar://broker-1-external:6650"),
+			},
+		},
+	}, url, newTestServiceNameResolver(t, url), false, "external", log.DefaultNopLogger(),
+		NewMetricsProvider(map[string]string{}))
 
 	lr, err := ls.Lookup("my-topic")
 	assert.NoError(t, err)
 	assert.NotNil(t, lr)
 
+	assert.Equal(t, "pulsar://broker-1-external:6650", lr.LogicalAddr.String())
+	assert.Equal(t, "pulsar://broker-1-external:6650", lr.PhysicalAddr.String())
+}
+
+func TestLookupTopicListenerNameEncoding(t *testing.T) {
+	cmd := &pb.CommandLookupTopic{
+		RequestId:              proto.Uint64(1),
+		Topic:                  proto.String("my-topic"),
+		AdvertisedListenerName: proto.String("external"),
+	}
+	data, err := cmd.Marshal()
+	assert.NoError(t, err)
+	assert.Equal(t, cmd.Size(), len(data))
+
+	decoded := &pb.CommandLookupTopic{}
+	assert.NoError(t, decoded.Unmarshal(data))
+	assert.Equal(t, "external", decoded.GetAdvertisedListenerName())
+}
+
+func newHTTPLookupServiceForTest(t *testing.T, handler http.HandlerFunc) (LookupService, func()) {
+	server := httptest.NewServer(handler)
+	serviceURL, err := url.Parse(server.URL)
+	assert.NoError(t, err)
+
+	httpClient, err := NewHTTPClient(serviceURL, newTestServiceNameResolver(t, serviceURL), nil,
+		auth.NewAuthenticationToken("my-token"), 5*time.Second, log.DefaultNopLogger())
+	assert.NoError(t, err)
+
+	ls := NewHTTPLookupService(httpClient, serviceURL, false, log.DefaultNopLogger(),
+		NewMetricsProvider(map[string]string{}))
+	return ls, func() {
+		httpClient.Close()
+		server.Close()
+	}
+}
+
+func TestHTTPLookupSuccess(t *testing.T) {
+	ls, closeFn := newHTTPLookupServiceForTest(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/lookup/v2/topic/persistent/public/default/my-topic", r.URL.Path)
+		assert.Equal(t, "Bearer my-token", r.Header.Get("Authorization"))
+		json.NewEncoder(w).Encode(lookupData{
+			BrokerURL:    "pulsar://broker-1:6650",
+			BrokerURLTLS: "pulsar+ssl://broker-1:6651",
+		})
+	})
+	defer closeFn()
+
+	lr, err := ls.Lookup("my-topic")
+	assert.NoError(t, err)
 	assert.Equal(t, "pulsar://broker-1:6650", lr.LogicalAddr.String())
 	assert.Equal(t, "pulsar://broker-1:6650", lr.PhysicalAddr.String())
 }
+
+func TestHTTPLookupLegacyTopic(t *testing.T) {
+	ls, closeFn := newHTTPLookupServiceForTest(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/lookup/v2/destination/persistent/my-tenant/my-cluster/my-ns/my-topic", r.URL.Path)
+		json.NewEncoder(w).Encode(lookupData{BrokerURL: "pulsar://broker-1:6650"})
+	})
+	defer closeFn()
+
+	lr, err := ls.Lookup("persistent://my-tenant/my-cluster/my-ns/my-topic")
+	assert.NoError(t, err)
+	assert.Equal(t, "pulsar://broker-1:6650", lr.LogicalAddr.String())
+}
+
+func TestHTTPLookupFailure(t *testing.T) {
+	ls, closeFn := newHTTPLookupServiceForTest(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "not found", http.StatusNotFound)
+	})
+	defer closeFn()
+
+	lr, err := ls.Lookup("my-topic")
+	assert.Error(t, err)
+	assert.Nil(t, lr)
+}
+
+func TestHTTPLookupServiceNotReady(t *testing.T) {
+	// the lookups failing while the bundle is unloaded are reported like the binary ones to be retried
+	for status, code := range map[int]pb.ServerError{
+		http.StatusServiceUnavailable: pb.ServerError_ServiceNotReady,
+		http.StatusTooManyRequests:    pb.ServerError_TooManyRequests,
+	} {
+		status := status
+		ls, closeFn := newHTTPLookupServiceForTest(t, func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "bundle unloading", status)
+		})
+
+		_, err := ls.Lookup("my-topic")
+		var lookupErr *LookupError
+		assert.True(t, errors.As(err, &lookupErr))
+		assert.Equal(t, code, lookupErr.ServerError)
+
+		_, err = ls.GetPartitionedTopicMetadata("my-topic")
+		assert.True(t, errors.As(err, &lookupErr))
+		assert.Equal(t, code, lookupErr.ServerError)
+		closeFn()
+	}
+
+	ls, closeFn := newHTTPLookupServiceForTest(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "not found", http.StatusNotFound)
+	})
+	defer closeFn()
+	_, err := ls.GetTopicsOfNamespace("public/default", pb.CommandGetTopicsOfNamespace_PERSISTENT)
+	var httpErr *HTTPError
+	assert.True(t, errors.As(err, &httpErr))
+	assert.Equal(t, http.StatusNotFound, httpErr.StatusCode)
+}
+
+func TestHTTPGetPartitionedTopicMetadata(t *testing.T) {
+	ls, closeFn := newHTTPLookupServiceForTest(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/admin/v2/persistent/public/default/my-topic/partitions", r.URL.Path)
+		json.NewEncoder(w).Encode(partitionedTopicMetadata{Partitions: 3})
+	})
+	defer closeFn()
+
+	tm, err := ls.GetPartitionedTopicMetadata("my-topic")
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(3), tm.GetPartitions())
+}
+
+func TestHTTPGetTopicsOfNamespace(t *testing.T) {
+	ls, closeFn := newHTTPLookupServiceForTest(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/admin/v2/namespaces/public/default/topics", r.URL.Path)
+		assert.Equal(t, "PERSISTENT", r.URL.Query().Get("mode"))
+		json.NewEncoder(w).Encode([]string{"persistent://public/default/my-topic"})
+	})
+	defer closeFn()
+
+	topics, err := ls.GetTopicsOfNamespace("public/default", pb.CommandGetTopicsOfNamespace_PERSISTENT)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"persistent://public/default/my-topic"}, topics)
+}
+
+type mockedSchemaRPCClient struct {
+	mockedLookupRPCClient
+
+	requests []*pb.CommandGetSchema
+	response *pb.CommandGetSchemaResponse
+}
+
+func (c *mockedSchemaRPCClient) RequestToAnyBroker(ctx context.Context, requestID uint64,
+	cmdType pb.BaseCommand_Type, message proto.Message) (*RPCResult, error) {
+	assert.Equal(c.t, pb.BaseCommand_GET_SCHEMA, cmdType)
+	c.requests = append(c.requests, message.(*pb.CommandGetSchema))
+	return &RPCResult{
+		Response: &pb.BaseCommand{GetSchemaResponse: c.response},
+	}, nil
+}
+
+func TestGetSchema(t *testing.T) {
+	url, err := url.Parse("pulsar://example:6650")
+	assert.NoError(t, err)
+
+	rpcClient := &mockedSchemaRPCClient{
+		mockedLookupRPCClient: mockedLookupRPCClient{t: t},
+		response: &pb.CommandGetSchemaResponse{
+			Schema: &pb.Schema{
+				Name:       proto.String("my-topic"),
+				SchemaData: []byte("schema"),
+				Type:       pb.Schema_Avro.Enum(),
+			},
+		},
+	}
+	ls := NewLookupService(rpcClient, url, newTestServiceNameResolver(t, url), false, "", log.DefaultNopLogger(),
+		NewMetricsProvider(map[string]string{}))
+
+	schema, err := ls.GetSchema("my-topic", []byte{0, 0, 0, 0, 0, 0, 0, 1})
+	assert.NoError(t, err)
+	assert.Equal(t, pb.Schema_Avro, schema.GetType())
+	assert.Equal(t, "persistent://public/default/my-topic", rpcClient.requests[0].GetTopic())
+	assert.Equal(t, []byte{0, 0, 0, 0, 0, 0, 0, 1}, rpcClient.requests[0].GetSchemaVersion())
+
+	rpcClient.response = &pb.CommandGetSchemaResponse{
+		ErrorCode:    pb.ServerError_TopicNotFound.Enum(),
+		ErrorMessage: proto.String("schema not found"),
+	}
+	_, err = ls.GetSchema("my-topic", nil)
+	var serverErr *ServerError
+	assert.True(t, errors.As(err, &serverErr))
+	assert.Equal(t, pb.ServerError_TopicNotFound, serverErr.Code)
+	assert.Nil(t, rpcClient.requests[1].SchemaVersion)
+}
+
+func TestHTTPGetSchema(t *testing.T) {
+	ls, closeFn := newHTTPLookupServiceForTest(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/admin/v2/schemas/public/default/my-topic/schema/2", r.URL.Path)
+		json.NewEncoder(w).Encode(schemaData{Type: "BOOLEAN"})
+	})
+	defer closeFn()
+
+	schema, err := ls.GetSchema("my-topic", []byte{0, 0, 0, 0, 0, 0, 0, 2})
+	assert.NoError(t, err)
+	assert.Equal(t, pb.Schema_Bool, schema.GetType())
+}
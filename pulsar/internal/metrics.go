@@ -36,10 +36,15 @@ type Metrics struct {
 	bytesReceived      *prometheus.CounterVec
 	prefetchedMessages *prometheus.GaugeVec
 	prefetchedBytes    *prometheus.GaugeVec
+	unackedMessages    *prometheus.GaugeVec
 	acksCounter        *prometheus.CounterVec
+	acksFailed         *prometheus.CounterVec
 	nacksCounter       *prometheus.CounterVec
 	dlqCounter         *prometheus.CounterVec
 	processingTime     *prometheus.HistogramVec
+	activeChanges      *prometheus.CounterVec
+	eventsQueueFull    *prometheus.CounterVec
+	duplicatesDropped  *prometheus.CounterVec
 
 	producersOpened     *prometheus.CounterVec
 	producersClosed     *prometheus.CounterVec
@@ -67,6 +72,8 @@ type TopicMetrics struct {
 	BytesPending             prometheus.Gauge
 	PublishErrorsTimeout     prometheus.Counter
 	PublishErrorsMsgTooLarge prometheus.Counter
+	PublishErrorsReceipt     prometheus.Counter
+	PublishErrorsSend        prometheus.Counter
 	PublishLatency           prometheus.Observer
 	PublishRPCLatency        prometheus.Observer
 
@@ -74,10 +81,15 @@ type TopicMetrics struct {
 	BytesReceived      prometheus.Counter
 	PrefetchedMessages prometheus.Gauge
 	PrefetchedBytes    prometheus.Gauge
+	UnackedMessages    prometheus.Gauge
 	AcksCounter        prometheus.Counter
+	AcksFailed         prometheus.Counter
 	NacksCounter       prometheus.Counter
 	DlqCounter         prometheus.Counter
 	ProcessingTime     prometheus.Observer
+	ActiveChanges      prometheus.Counter
+	EventsQueueFull    prometheus.Counter
+	DuplicatesDropped  prometheus.Counter
 
 	ProducersOpened     prometheus.Counter
 	ProducersClosed     prometheus.Counter
@@ -202,12 +214,24 @@ func NewMetricsProvider(userDefinedLabels map[string]string) *Metrics {
 			ConstLabels: constLabels,
 		}, topicLabelNames),
 
+		unackedMessages: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name:        "pulsar_client_consumer_unacked_messages",
+			Help:        "Number of messages received by the consumer and not acked or nacked yet",
+			ConstLabels: constLabels,
+		}, topicLabelNames),
+
 		acksCounter: prometheus.NewCounterVec(prometheus.CounterOpts{
 			Name:        "pulsar_client_consumer_acks",
 			Help:        "Counter of messages acked by client",
 			ConstLabels: constLabels,
 		}, topicLabelNames),
 
+		acksFailed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name:        "pulsar_client_consumer_acks_failed",
+			Help:        "Counter of acks the broker reported as failed",
+			ConstLabels: constLabels,
+		}, topicLabelNames),
+
 		nacksCounter: prometheus.NewCounterVec(prometheus.CounterOpts{
 			Name:        "pulsar_client_consumer_nacks",
 			Help:        "Counter of messages nacked by client",
@@ -227,6 +251,24 @@ func NewMetricsProvider(userDefinedLabels map[string]string) *Metrics {
 			ConstLabels: constLabels,
 		}, topicLabelNames),
 
+		activeChanges: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name:        "pulsar_client_consumer_active_changes",
+			Help:        "Counter of the changes of the active consumer of Failover subscriptions notified by the broker",
+			ConstLabels: constLabels,
+		}, topicLabelNames),
+
+		eventsQueueFull: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name:        "pulsar_client_consumer_events_queue_full",
+			Help:        "Counter of the consumer requests, eg. seeks and redeliveries, waiting for a full events queue",
+			ConstLabels: constLabels,
+		}, topicLabelNames),
+
+		duplicatesDropped: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name:        "pulsar_client_consumer_duplicates_dropped",
+			Help:        "Counter of the messages dropped by the consumers because they were already delivered",
+			ConstLabels: constLabels,
+		}, topicLabelNames),
+
 		readersOpened: prometheus.NewCounterVec(prometheus.CounterOpts{
 			Name:        "pulsar_client_readers_opened",
 			Help:        "Counter of readers created by the client",
@@ -294,10 +336,15 @@ func NewMetricsProvider(userDefinedLabels map[string]string) *Metrics {
 	prometheus.DefaultRegisterer.Register(metrics.bytesReceived)
 	prometheus.DefaultRegisterer.Register(metrics.prefetchedMessages)
 	prometheus.DefaultRegisterer.Register(metrics.prefetchedBytes)
+	prometheus.DefaultRegisterer.Register(metrics.unackedMessages)
 	prometheus.DefaultRegisterer.Register(metrics.acksCounter)
+	prometheus.DefaultRegisterer.Register(metrics.acksFailed)
 	prometheus.DefaultRegisterer.Register(metrics.nacksCounter)
 	prometheus.DefaultRegisterer.Register(metrics.dlqCounter)
 	prometheus.DefaultRegisterer.Register(metrics.processingTime)
+	prometheus.DefaultRegisterer.Register(metrics.activeChanges)
+	prometheus.DefaultRegisterer.Register(metrics.eventsQueueFull)
+	prometheus.DefaultRegisterer.Register(metrics.duplicatesDropped)
 
 	prometheus.DefaultRegisterer.Register(metrics.producersOpened)
 	prometheus.DefaultRegisterer.Register(metrics.producersClosed)
@@ -334,6 +381,8 @@ func (mp *Metrics) GetTopicMetrics(t string) *TopicMetrics {
 		BytesPending:             mp.bytesPending.With(labels),
 		PublishErrorsTimeout:     mp.publishErrors.With(mergeMaps(labels, map[string]string{"error": "timeout"})),
 		PublishErrorsMsgTooLarge: mp.publishErrors.With(mergeMaps(labels, map[string]string{"error": "msg_too_large"})),
+		PublishErrorsReceipt:     mp.publishErrors.With(mergeMaps(labels, map[string]string{"error": "unexpected_receipt"})),
+		PublishErrorsSend:        mp.publishErrors.With(mergeMaps(labels, map[string]string{"error": "send_error"})),
 		PublishLatency:           mp.publishLatency.With(labels),
 		PublishRPCLatency:        mp.publishRPCLatency.With(labels),
 
@@ -341,10 +390,15 @@ func (mp *Metrics) GetTopicMetrics(t string) *TopicMetrics {
 		BytesReceived:      mp.bytesReceived.With(labels),
 		PrefetchedMessages: mp.prefetchedMessages.With(labels),
 		PrefetchedBytes:    mp.prefetchedBytes.With(labels),
+		UnackedMessages:    mp.unackedMessages.With(labels),
 		AcksCounter:        mp.acksCounter.With(labels),
+		AcksFailed:         mp.acksFailed.With(labels),
 		NacksCounter:       mp.nacksCounter.With(labels),
 		DlqCounter:         mp.dlqCounter.With(labels),
 		ProcessingTime:     mp.processingTime.With(labels),
+		ActiveChanges:      mp.activeChanges.With(labels),
+		EventsQueueFull:    mp.eventsQueueFull.With(labels),
+		DuplicatesDropped:  mp.duplicatesDropped.With(labels),
 
 		ProducersOpened:     mp.producersOpened.With(labels),
 		ProducersClosed:     mp.producersClosed.With(labels),
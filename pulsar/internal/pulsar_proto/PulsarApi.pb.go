@@ -99,6 +99,10 @@ const (
 	ServerError_ConsumerAssignError                   ServerError = 19
 	ServerError_TransactionCoordinatorNotFound        ServerError = 20
 	ServerError_InvalidTxnStatus                      ServerError = 21
+	ServerError_NotAllowedError                       ServerError = 22
+	ServerError_TransactionConflict                   ServerError = 23
+	ServerError_TransactionNotFound                   ServerError = 24
+	ServerError_ProducerFenced                        ServerError = 25
 )
 
 var ServerError_name = map[int32]string{
@@ -124,6 +128,10 @@ var ServerError_name = map[int32]string{
 	19: "ConsumerAssignError",
 	20: "TransactionCoordinatorNotFound",
 	21: "InvalidTxnStatus",
+	22: "NotAllowedError",
+	23: "TransactionConflict",
+	24: "TransactionNotFound",
+	25: "ProducerFenced",
 }
 
 var ServerError_value = map[string]int32{
@@ -149,6 +157,10 @@ var ServerError_value = map[string]int32{
 	"ConsumerAssignError":                   19,
 	"TransactionCoordinatorNotFound":        20,
 	"InvalidTxnStatus":                      21,
+	"NotAllowedError":                       22,
+	"TransactionConflict":                   23,
+	"TransactionNotFound":                   24,
+	"ProducerFenced":                        25,
 }
 
 func (x ServerError) Enum() *ServerError {
@@ -344,6 +356,49 @@ func (KeySharedMode) EnumDescriptor() ([]byte, []int) {
 	return fileDescriptor_39529ba7ad9caeb8, []int{4}
 }
 
+type ProducerAccessMode int32
+
+const (
+	ProducerAccessMode_Shared           ProducerAccessMode = 0
+	ProducerAccessMode_Exclusive        ProducerAccessMode = 1
+	ProducerAccessMode_WaitForExclusive ProducerAccessMode = 2
+)
+
+var ProducerAccessMode_name = map[int32]string{
+	0: "Shared",
+	1: "Exclusive",
+	2: "WaitForExclusive",
+}
+
+var ProducerAccessMode_value = map[string]int32{
+	"Shared":           0,
+	"Exclusive":        1,
+	"WaitForExclusive": 2,
+}
+
+func (x ProducerAccessMode) Enum() *ProducerAccessMode {
+	p := new(ProducerAccessMode)
+	*p = x
+	return p
+}
+
+func (x ProducerAccessMode) String() string {
+	return proto.EnumName(ProducerAccessMode_name, int32(x))
+}
+
+func (x *ProducerAccessMode) UnmarshalJSON(data []byte) error {
+	value, err := proto.UnmarshalJSONEnum(ProducerAccessMode_value, data, "ProducerAccessMode")
+	if err != nil {
+		return err
+	}
+	*x = ProducerAccessMode(value)
+	return nil
+}
+
+func (ProducerAccessMode) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_39529ba7ad9caeb8, []int{5}
+}
+
 type TxnAction int32
 
 const (
@@ -381,7 +436,7 @@ func (x *TxnAction) UnmarshalJSON(data []byte) error {
 }
 
 func (TxnAction) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_39529ba7ad9caeb8, []int{5}
+	return fileDescriptor_39529ba7ad9caeb8, []int{6}
 }
 
 type Schema_Type int32
@@ -2554,11 +2609,13 @@ type CommandLookupTopic struct {
 	OriginalPrincipal *string `protobuf:"bytes,4,opt,name=original_principal,json=originalPrincipal" json:"original_principal,omitempty"`
 	// Original auth role and auth Method that was passed
 	// to the proxy.
-	OriginalAuthData     *string  `protobuf:"bytes,5,opt,name=original_auth_data,json=originalAuthData" json:"original_auth_data,omitempty"`
-	OriginalAuthMethod   *string  `protobuf:"bytes,6,opt,name=original_auth_method,json=originalAuthMethod" json:"original_auth_method,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	OriginalAuthData   *string `protobuf:"bytes,5,opt,name=original_auth_data,json=originalAuthData" json:"original_auth_data,omitempty"`
+	OriginalAuthMethod *string `protobuf:"bytes,6,opt,name=original_auth_method,json=originalAuthMethod" json:"original_auth_method,omitempty"`
+	//
+	AdvertisedListenerName *string  `protobuf:"bytes,7,opt,name=advertised_listener_name,json=advertisedListenerName" json:"advertised_listener_name,omitempty"`
+	XXX_NoUnkeyedLiteral   struct{} `json:"-"`
+	XXX_unrecognized       []byte   `json:"-"`
+	XXX_sizecache          int32    `json:"-"`
 }
 
 func (m *CommandLookupTopic) Reset()         { *m = CommandLookupTopic{} }
@@ -2638,6 +2695,13 @@ func (m *CommandLookupTopic) GetOriginalAuthMethod() string {
 	return ""
 }
 
+func (m *CommandLookupTopic) GetAdvertisedListenerName() string {
+	if m != nil && m.AdvertisedListenerName != nil {
+		return *m.AdvertisedListenerName
+	}
+	return ""
+}
+
 type CommandLookupTopicResponse struct {
 	BrokerServiceUrl    *string                                `protobuf:"bytes,1,opt,name=brokerServiceUrl" json:"brokerServiceUrl,omitempty"`
 	BrokerServiceUrlTls *string                                `protobuf:"bytes,2,opt,name=brokerServiceUrlTls" json:"brokerServiceUrlTls,omitempty"`
@@ -2764,10 +2828,15 @@ type CommandProducer struct {
 	Epoch *uint64 `protobuf:"varint,8,opt,name=epoch,def=0" json:"epoch,omitempty"`
 	// Indicate the name of the producer is generated or user provided
 	// Use default true here is in order to be forward compatible with the client
-	UserProvidedProducerName *bool    `protobuf:"varint,9,opt,name=user_provided_producer_name,json=userProvidedProducerName,def=1" json:"user_provided_producer_name,omitempty"`
-	XXX_NoUnkeyedLiteral     struct{} `json:"-"`
-	XXX_unrecognized         []byte   `json:"-"`
-	XXX_sizecache            int32    `json:"-"`
+	UserProvidedProducerName *bool `protobuf:"varint,9,opt,name=user_provided_producer_name,json=userProvidedProducerName,def=1" json:"user_provided_producer_name,omitempty"`
+	// Require that this producers will be the only producer allowed on the topic
+	ProducerAccessMode *ProducerAccessMode `protobuf:"varint,10,opt,name=producer_access_mode,json=producerAccessMode,enum=pulsar.proto.ProducerAccessMode,def=0" json:"producer_access_mode,omitempty"`
+	// Topic epoch is used to fence off producers that reconnects after a new
+	// exclusive producer has already taken over.
+	TopicEpoch           *uint64  `protobuf:"varint,11,opt,name=topic_epoch,json=topicEpoch" json:"topic_epoch,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *CommandProducer) Reset()         { *m = CommandProducer{} }
@@ -2806,6 +2875,7 @@ var xxx_messageInfo_CommandProducer proto.InternalMessageInfo
 const Default_CommandProducer_Encrypted bool = false
 const Default_CommandProducer_Epoch uint64 = 0
 const Default_CommandProducer_UserProvidedProducerName bool = true
+const Default_CommandProducer_ProducerAccessMode ProducerAccessMode = ProducerAccessMode_Shared
 
 func (m *CommandProducer) GetTopic() string {
 	if m != nil && m.Topic != nil {
@@ -2870,6 +2940,20 @@ func (m *CommandProducer) GetUserProvidedProducerName() bool {
 	return Default_CommandProducer_UserProvidedProducerName
 }
 
+func (m *CommandProducer) GetProducerAccessMode() ProducerAccessMode {
+	if m != nil && m.ProducerAccessMode != nil {
+		return *m.ProducerAccessMode
+	}
+	return Default_CommandProducer_ProducerAccessMode
+}
+
+func (m *CommandProducer) GetTopicEpoch() uint64 {
+	if m != nil && m.TopicEpoch != nil {
+		return *m.TopicEpoch
+	}
+	return 0
+}
+
 type CommandSend struct {
 	ProducerId     *uint64 `protobuf:"varint,1,req,name=producer_id,json=producerId" json:"producer_id,omitempty"`
 	SequenceId     *uint64 `protobuf:"varint,2,req,name=sequence_id,json=sequenceId" json:"sequence_id,omitempty"`
@@ -3873,8 +3957,15 @@ type CommandProducerSuccess struct {
 	ProducerName *string `protobuf:"bytes,2,req,name=producer_name,json=producerName" json:"producer_name,omitempty"`
 	// The last sequence id that was stored by this producer in the previous session
 	// This will only be meaningful if deduplication has been enabled.
-	LastSequenceId       *int64   `protobuf:"varint,3,opt,name=last_sequence_id,json=lastSequenceId,def=-1" json:"last_sequence_id,omitempty"`
-	SchemaVersion        []byte   `protobuf:"bytes,4,opt,name=schema_version,json=schemaVersion" json:"schema_version,omitempty"`
+	LastSequenceId *int64 `protobuf:"varint,3,opt,name=last_sequence_id,json=lastSequenceId,def=-1" json:"last_sequence_id,omitempty"`
+	SchemaVersion  []byte `protobuf:"bytes,4,opt,name=schema_version,json=schemaVersion" json:"schema_version,omitempty"`
+	// The topic epoch assigned by the broker. This field will only be set if we
+	// were requiring exclusive access when creating the producer.
+	TopicEpoch *uint64 `protobuf:"varint,5,opt,name=topic_epoch,json=topicEpoch" json:"topic_epoch,omitempty"`
+	// If producer is not "ready", the client will avoid to timeout the request
+	// for creating the producer. Instead it will wait indefinitely until it gets
+	// a subsequent  `CommandProducerSuccess` with `producer_ready==true`.
+	ProducerReady        *bool    `protobuf:"varint,6,opt,name=producer_ready,json=producerReady,def=1" json:"producer_ready,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -3914,6 +4005,7 @@ func (m *CommandProducerSuccess) XXX_DiscardUnknown() {
 var xxx_messageInfo_CommandProducerSuccess proto.InternalMessageInfo
 
 const Default_CommandProducerSuccess_LastSequenceId int64 = -1
+const Default_CommandProducerSuccess_ProducerReady bool = true
 
 func (m *CommandProducerSuccess) GetRequestId() uint64 {
 	if m != nil && m.RequestId != nil {
@@ -3943,6 +4035,20 @@ func (m *CommandProducerSuccess) GetSchemaVersion() []byte {
 	return nil
 }
 
+func (m *CommandProducerSuccess) GetTopicEpoch() uint64 {
+	if m != nil && m.TopicEpoch != nil {
+		return *m.TopicEpoch
+	}
+	return 0
+}
+
+func (m *CommandProducerSuccess) GetProducerReady() bool {
+	if m != nil && m.ProducerReady != nil {
+		return *m.ProducerReady
+	}
+	return Default_CommandProducerSuccess_ProducerReady
+}
+
 type CommandError struct {
 	RequestId            *uint64      `protobuf:"varint,1,req,name=request_id,json=requestId" json:"request_id,omitempty"`
 	Error                *ServerError `protobuf:"varint,2,req,name=error,enum=pulsar.proto.ServerError" json:"error,omitempty"`
@@ -6282,6 +6388,7 @@ func init() {
 	proto.RegisterEnum("pulsar.proto.AuthMethod", AuthMethod_name, AuthMethod_value)
 	proto.RegisterEnum("pulsar.proto.ProtocolVersion", ProtocolVersion_name, ProtocolVersion_value)
 	proto.RegisterEnum("pulsar.proto.KeySharedMode", KeySharedMode_name, KeySharedMode_value)
+	proto.RegisterEnum("pulsar.proto.ProducerAccessMode", ProducerAccessMode_name, ProducerAccessMode_value)
 	proto.RegisterEnum("pulsar.proto.TxnAction", TxnAction_name, TxnAction_value)
 	proto.RegisterEnum("pulsar.proto.Schema_Type", Schema_Type_name, Schema_Type_value)
 	proto.RegisterEnum("pulsar.proto.CommandSubscribe_SubType", CommandSubscribe_SubType_name, CommandSubscribe_SubType_value)
@@ -6361,359 +6468,366 @@ func init() {
 func init() { proto.RegisterFile("PulsarApi.proto", fileDescriptor_39529ba7ad9caeb8) }
 
 var fileDescriptor_39529ba7ad9caeb8 = []byte{
-	// 5618 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xdc, 0x3c, 0x4d, 0x8f, 0x1b, 0xc9,
-	0x75, 0x6a, 0x7e, 0xcc, 0x90, 0x8f, 0x43, 0xb2, 0x54, 0x1a, 0x49, 0xbd, 0x5a, 0xad, 0x34, 0xdb,
-	0x5a, 0x69, 0x67, 0xb5, 0x2b, 0x45, 0x1a, 0x69, 0xe5, 0x5d, 0xed, 0x3a, 0x5e, 0x0e, 0x87, 0x92,
-	0x98, 0x99, 0x21, 0xc7, 0x45, 0x8e, 0x9c, 0x75, 0x6c, 0xb4, 0x7b, 0xba, 0x4b, 0x9c, 0xc6, 0x34,
-	0xbb, 0xe9, 0xee, 0xe6, 0xec, 0xcc, 0x02, 0x09, 0x72, 0x31, 0x72, 0x31, 0x10, 0xe4, 0x94, 0x43,
-	0x80, 0x04, 0x39, 0xe5, 0xec, 0x5b, 0x00, 0x03, 0xb9, 0x05, 0x31, 0x90, 0x1c, 0x72, 0xc8, 0x21,
-	0x3e, 0x39, 0x58, 0xe4, 0xe3, 0x60, 0x18, 0x48, 0xfe, 0x41, 0x50, 0xd5, 0xd5, 0x5f, 0x64, 0x93,
-	0x9c, 0xd9, 0x75, 0x60, 0x67, 0x4f, 0xec, 0x7e, 0xf5, 0xde, 0xab, 0x57, 0xef, 0xbd, 0x7a, 0xf5,
-	0xea, 0x55, 0x35, 0xa1, 0xbe, 0x37, 0xb6, 0x3c, 0xcd, 0x6d, 0x8c, 0xcc, 0xfb, 0x23, 0xd7, 0xf1,
-	0x1d, 0xbc, 0x32, 0xe2, 0x80, 0xe0, 0x4d, 0xf9, 0x22, 0x07, 0x4b, 0x3d, 0xfd, 0x90, 0x0e, 0x35,
-	0x8c, 0xa1, 0x60, 0x6b, 0x43, 0x2a, 0x4b, 0x6b, 0xb9, 0xf5, 0x32, 0xe1, 0xcf, 0xf8, 0x26, 0x54,
-	0x3c, 0xde, 0xaa, 0x1a, 0x9a, 0xaf, 0xc9, 0xf9, 0xb5, 0xdc, 0xfa, 0x0a, 0x81, 0x00, 0xb4, 0xa5,
-	0xf9, 0x1a, 0xbe, 0x07, 0x05, 0xff, 0x74, 0x44, 0xe5, 0xc2, 0x5a, 0x6e, 0xbd, 0xb6, 0xf1, 0xda,
-	0xfd, 0x24, 0xf3, 0xfb, 0x01, 0xe3, 0xfb, 0xfd, 0xd3, 0x11, 0x25, 0x1c, 0x0d, 0x3f, 0x01, 0x18,
-	0xb9, 0xce, 0x88, 0xba, 0xbe, 0x49, 0x3d, 0xb9, 0xb8, 0x96, 0x5f, 0xaf, 0x6c, 0x5c, 0x49, 0x13,
-	0x6d, 0xd3, 0xd3, 0x97, 0x9a, 0x35, 0xa6, 0x24, 0x81, 0xa9, 0xfc, 0x54, 0x82, 0x02, 0x63, 0x83,
-	0x4b, 0x50, 0xe8, 0x38, 0x36, 0x45, 0x17, 0x30, 0xc0, 0x52, 0xcf, 0x77, 0x4d, 0x7b, 0x80, 0x24,
-	0x06, 0xfd, 0x3d, 0xcf, 0xb1, 0x51, 0x0e, 0xaf, 0x40, 0x69, 0x8f, 0xb1, 0x39, 0x18, 0xbf, 0x42,
-	0x79, 0x06, 0x6f, 0x1c, 0xbb, 0x0e, 0x2a, 0xb0, 0xa7, 0x4d, 0xc7, 0xb1, 0x50, 0x91, 0x3d, 0xb5,
-	0x6d, 0xff, 0x03, 0xb4, 0x84, 0xcb, 0x50, 0x6c, 0xdb, 0xfe, 0xc3, 0x27, 0x68, 0x59, 0x3c, 0x3e,
-	0xda, 0x40, 0x25, 0xf1, 0xf8, 0xe4, 0x31, 0x2a, 0xb3, 0xc7, 0x67, 0x96, 0xa3, 0xf9, 0x08, 0x58,
-	0x6f, 0x5b, 0xce, 0xf8, 0xc0, 0xa2, 0xa8, 0xc2, 0x38, 0x6c, 0x69, 0x3e, 0x45, 0x2b, 0xec, 0xa9,
-	0x6f, 0x0e, 0x29, 0xaa, 0xe2, 0x2a, 0x94, 0xd9, 0x93, 0xe7, 0x6b, 0xc3, 0x11, 0xaa, 0x31, 0x31,
-	0xc2, 0x71, 0xa0, 0xba, 0xf2, 0x63, 0x09, 0xaa, 0xbb, 0xd4, 0xf3, 0xb4, 0x01, 0x6d, 0x1b, 0x5c,
-	0x6d, 0xd7, 0xa0, 0x64, 0x51, 0x63, 0x40, 0xdd, 0xb6, 0xc1, 0xf5, 0x5d, 0x20, 0xd1, 0x3b, 0x96,
-	0x61, 0x99, 0xda, 0xbe, 0x7b, 0xda, 0x36, 0xe4, 0x1c, 0x6f, 0x0a, 0x5f, 0xf1, 0x1a, 0x94, 0x47,
-	0x9a, 0xeb, 0x9b, 0xbe, 0xe9, 0xd8, 0x72, 0x7e, 0x4d, 0x5a, 0x2f, 0x3e, 0xcd, 0xdd, 0x7b, 0x48,
-	0x62, 0x20, 0xbe, 0x05, 0x95, 0x03, 0xcd, 0xd7, 0x0f, 0x55, 0xd3, 0x36, 0xe8, 0x89, 0x5c, 0x88,
-	0x70, 0x80, 0x83, 0xdb, 0x0c, 0xaa, 0x6c, 0xc4, 0xc2, 0x61, 0x04, 0xf9, 0x23, 0x7a, 0x2a, 0x6c,
-	0xce, 0x1e, 0xf1, 0x2a, 0x14, 0x8f, 0x59, 0x13, 0xef, 0xbc, 0x4c, 0x82, 0x17, 0xe5, 0x09, 0xac,
-	0x6c, 0xd3, 0xd3, 0x1d, 0xc7, 0x1e, 0x9c, 0x89, 0xae, 0x10, 0xd2, 0x6d, 0x40, 0xa9, 0x6d, 0xfb,
-	0x44, 0xb3, 0x07, 0x94, 0x61, 0x78, 0xbe, 0xe6, 0xfa, 0x9c, 0xaa, 0x48, 0x82, 0x17, 0xc6, 0x89,
-	0xda, 0xc1, 0x50, 0x8b, 0x84, 0x3d, 0x2a, 0x16, 0xd4, 0x5a, 0xb6, 0xee, 0x9e, 0x8e, 0xd8, 0x90,
-	0xb6, 0xe9, 0xa9, 0xb7, 0xa8, 0xb7, 0x15, 0xd1, 0x1b, 0xde, 0x80, 0xd2, 0x90, 0xfa, 0x9a, 0xf0,
-	0xd5, 0x79, 0xce, 0x15, 0xe1, 0x29, 0xbf, 0x5a, 0x86, 0xba, 0x30, 0xce, 0xae, 0x80, 0xe1, 0x5b,
-	0x50, 0x1d, 0xb9, 0x8e, 0x31, 0xd6, 0xa9, 0xab, 0x26, 0xe6, 0xc4, 0x4a, 0x08, 0xec, 0x84, 0x73,
-	0x83, 0xfe, 0x70, 0x4c, 0x6d, 0x9d, 0xaa, 0x66, 0x68, 0x2b, 0x08, 0x41, 0x6d, 0x03, 0xbf, 0x09,
-	0x2b, 0xa3, 0xf1, 0x81, 0x65, 0x7a, 0x87, 0xaa, 0x6f, 0x0e, 0x29, 0x9f, 0x3d, 0x05, 0x52, 0x11,
-	0x30, 0xe6, 0x2e, 0x13, 0xf3, 0xa1, 0x70, 0xd6, 0xf9, 0x80, 0xdf, 0x86, 0xba, 0x4b, 0x47, 0x96,
-	0xa9, 0x6b, 0x3e, 0x35, 0xd4, 0x57, 0xae, 0x33, 0x94, 0x8b, 0x6b, 0xd2, 0x7a, 0x99, 0xd4, 0x62,
-	0xf0, 0x33, 0xd7, 0x19, 0xf2, 0x91, 0x84, 0xde, 0xa1, 0x32, 0x1d, 0x2e, 0x71, 0xb4, 0x95, 0x08,
-	0xb8, 0x4d, 0x4f, 0x99, 0xa0, 0x11, 0x99, 0xea, 0x3b, 0xf2, 0xf2, 0x5a, 0x7e, 0xbd, 0x4c, 0x2a,
-	0x11, 0xac, 0xef, 0xe0, 0x16, 0x54, 0x74, 0x67, 0x38, 0x72, 0xa9, 0xe7, 0x31, 0xe7, 0x2b, 0xad,
-	0x49, 0xeb, 0xb5, 0x8d, 0x37, 0xd2, 0x92, 0x36, 0x63, 0x04, 0x36, 0x57, 0x9f, 0x16, 0x3a, 0xdd,
-	0x4e, 0x8b, 0x24, 0xe9, 0xf0, 0x7d, 0xb8, 0x38, 0xb6, 0x43, 0x00, 0x35, 0x54, 0xcf, 0xfc, 0x9c,
-	0xca, 0xe5, 0x35, 0x69, 0xbd, 0xfa, 0x54, 0x7a, 0x40, 0x50, 0xb2, 0xad, 0x67, 0x7e, 0x4e, 0xf1,
-	0x63, 0xb8, 0x6c, 0x8f, 0x87, 0xea, 0x30, 0xb0, 0x8f, 0xa7, 0x9a, 0xb6, 0xca, 0x1d, 0x59, 0xae,
-	0x70, 0xcf, 0x96, 0x1e, 0x12, 0x6c, 0x8f, 0x87, 0xc2, 0x7c, 0x5e, 0xdb, 0xde, 0x64, 0x8d, 0x78,
-	0x0d, 0x80, 0x1e, 0x53, 0xdb, 0x0f, 0xd4, 0xbe, 0xb2, 0x26, 0xad, 0x17, 0x18, 0xfb, 0x32, 0x07,
-	0x72, 0xbd, 0xb7, 0xa0, 0x4e, 0x23, 0x17, 0x63, 0x7a, 0xf1, 0xe4, 0x2a, 0x57, 0xfe, 0xf5, 0xf4,
-	0x90, 0xd2, 0x7e, 0x48, 0x6a, 0x34, 0xed, 0x97, 0x6f, 0xa7, 0xd8, 0x68, 0xd6, 0xc0, 0x91, 0x6b,
-	0x81, 0x19, 0x62, 0x70, 0xc3, 0x1a, 0x38, 0xf8, 0x1d, 0x40, 0x09, 0xc4, 0x91, 0xe6, 0x6a, 0x43,
-	0xb9, 0xbe, 0x26, 0xad, 0xaf, 0x90, 0x04, 0x83, 0x3d, 0x06, 0xc6, 0xb7, 0xa1, 0x26, 0x42, 0xee,
-	0x31, 0x75, 0xb9, 0xb2, 0x11, 0x47, 0xac, 0x06, 0xd0, 0x97, 0x01, 0x10, 0x7f, 0x02, 0xaf, 0xa5,
-	0x0c, 0xab, 0x1e, 0x3c, 0x79, 0xac, 0x52, 0x5b, 0x77, 0x0c, 0x6a, 0xc8, 0x17, 0xd7, 0xa4, 0xf5,
-	0xd2, 0xd3, 0xe2, 0x2b, 0xcd, 0xf2, 0x28, 0xb9, 0x92, 0xb4, 0xf5, 0xe6, 0x93, 0xc7, 0xad, 0x00,
-	0x89, 0x59, 0xdd, 0x71, 0x0d, 0xca, 0x42, 0x28, 0xf7, 0x0c, 0xcc, 0xbb, 0xa9, 0x84, 0x30, 0xe6,
-	0x18, 0x77, 0xa0, 0x6e, 0x50, 0xcb, 0x3c, 0xa6, 0xae, 0xaa, 0x09, 0x6d, 0x5e, 0x5a, 0x93, 0xd6,
-	0xf3, 0xa4, 0x2a, 0xc0, 0x8d, 0x40, 0x9d, 0x37, 0xa1, 0x32, 0xd4, 0xdc, 0x23, 0xea, 0xaa, 0x7c,
-	0x31, 0x58, 0x65, 0xc6, 0x21, 0x10, 0x80, 0x78, 0xd8, 0x7e, 0x17, 0x90, 0x7f, 0x62, 0x9b, 0x86,
-	0x6a, 0x51, 0xcd, 0xf3, 0xd5, 0x03, 0xd3, 0xf7, 0xe4, 0x2b, 0xa1, 0x5d, 0x6a, 0xbc, 0x69, 0x87,
-	0xb5, 0x6c, 0x9a, 0xbe, 0x87, 0xdf, 0x81, 0x7a, 0x80, 0x3c, 0x74, 0x42, 0xdc, 0xab, 0x21, 0x6e,
-	0x95, 0xb7, 0xec, 0x3a, 0x02, 0xf5, 0x21, 0x5c, 0x3a, 0x34, 0x07, 0x87, 0xd4, 0xf3, 0xd5, 0xe4,
-	0x5c, 0x94, 0x43, 0xf4, 0x8b, 0xa2, 0xb5, 0x17, 0xcd, 0x4a, 0xe5, 0x97, 0x39, 0xb8, 0xdc, 0x33,
-	0xed, 0x81, 0x45, 0x27, 0x67, 0x7d, 0x7a, 0x32, 0x4a, 0x67, 0x9e, 0x8c, 0x53, 0x73, 0x2c, 0x97,
-	0x3d, 0xc7, 0x46, 0xda, 0xa9, 0xe5, 0x68, 0xc2, 0xe9, 0xf3, 0x3c, 0xde, 0x55, 0x04, 0x8c, 0x3b,
-	0xfb, 0x5d, 0xa8, 0x32, 0xf7, 0xd7, 0x74, 0x36, 0xa7, 0x9d, 0xb1, 0xcf, 0xc3, 0x77, 0x64, 0xc6,
-	0x95, 0xa8, 0xad, 0x3b, 0xf6, 0x27, 0x5c, 0xbc, 0x98, 0xe1, 0xe2, 0x73, 0x1d, 0x64, 0xe9, 0xcb,
-	0x38, 0xc8, 0xf2, 0xb4, 0x83, 0x4c, 0xc4, 0x40, 0x16, 0x16, 0x52, 0x31, 0x50, 0xf9, 0xcf, 0x3c,
-	0xd4, 0x9a, 0xce, 0x70, 0xa8, 0xd9, 0x46, 0xd3, 0xb1, 0x6d, 0xaa, 0xfb, 0xcc, 0xc1, 0x75, 0xcb,
-	0x64, 0xb2, 0x87, 0x0e, 0x1e, 0x44, 0xd7, 0x6a, 0x00, 0x0d, 0x1d, 0xfc, 0x43, 0xa8, 0x68, 0x63,
-	0xff, 0x50, 0x1d, 0x52, 0xff, 0xd0, 0x31, 0xb8, 0x4e, 0x6b, 0x1b, 0x72, 0xda, 0x1c, 0x8d, 0xb1,
-	0x7f, 0xb8, 0xcb, 0xdb, 0x09, 0x68, 0xd1, 0x33, 0x5e, 0x07, 0x94, 0x20, 0x0d, 0x22, 0xb8, 0x08,
-	0x8f, 0x31, 0x16, 0x8f, 0xe1, 0xaf, 0x43, 0x99, 0x63, 0x8a, 0x15, 0x83, 0x8d, 0xaf, 0xc4, 0x00,
-	0x7c, 0x91, 0x7e, 0x0f, 0x10, 0xef, 0x46, 0x77, 0xac, 0x48, 0xd4, 0x60, 0x45, 0x95, 0x1e, 0x90,
-	0x7a, 0xd8, 0x14, 0xca, 0x7b, 0x0f, 0x2e, 0x8d, 0x5c, 0xe7, 0xe4, 0x54, 0xf5, 0x1d, 0xf5, 0xc0,
-	0x75, 0xd8, 0x64, 0x18, 0xbb, 0x96, 0x88, 0xb7, 0x88, 0x37, 0xf5, 0x9d, 0x4d, 0xde, 0xb0, 0xef,
-	0x5a, 0xf8, 0x1e, 0x60, 0xc7, 0x35, 0x07, 0xa6, 0xad, 0x59, 0xea, 0xc8, 0x35, 0x6d, 0xdd, 0x1c,
-	0x69, 0x16, 0x57, 0x71, 0x99, 0x5c, 0x0c, 0x5b, 0xf6, 0xc2, 0x06, 0xfc, 0x5e, 0x02, 0x3d, 0x96,
-	0xb8, 0x14, 0x30, 0x0f, 0x5b, 0x1a, 0xa1, 0xe4, 0x0f, 0x60, 0x35, 0x8d, 0x2d, 0x94, 0x58, 0xe6,
-	0xf8, 0x38, 0x89, 0x2f, 0x54, 0xf6, 0x2d, 0xa8, 0xbe, 0xa2, 0x9a, 0x3f, 0x76, 0xa9, 0xfa, 0xca,
-	0xd2, 0x06, 0x9e, 0x0c, 0x6b, 0xd2, 0x7a, 0x65, 0xe3, 0x5a, 0x5a, 0xdf, 0xcf, 0x02, 0x94, 0x67,
-	0x0c, 0x83, 0xac, 0xbc, 0x4a, 0xbc, 0x29, 0x6d, 0x58, 0x49, 0xb6, 0xe2, 0x0f, 0xe1, 0xb2, 0x37,
-	0x1e, 0x8d, 0x1c, 0xd7, 0xf7, 0x02, 0x11, 0x5c, 0xfa, 0xca, 0xa5, 0xde, 0xa1, 0x2c, 0x25, 0x5d,
-	0xef, 0x52, 0x88, 0xc3, 0x44, 0x21, 0x01, 0x86, 0xf2, 0x67, 0x12, 0xa0, 0xb4, 0xcf, 0x50, 0x83,
-	0x87, 0x45, 0xea, 0xb2, 0x48, 0x34, 0xe1, 0x35, 0x01, 0x34, 0xb4, 0x42, 0x96, 0xcd, 0x72, 0x33,
-	0x6d, 0xb6, 0x0e, 0x68, 0xa8, 0x9d, 0x84, 0xcb, 0x4b, 0x38, 0x31, 0x59, 0xf0, 0xaa, 0x0d, 0xb5,
-	0x13, 0x11, 0x1f, 0xd8, 0xdc, 0x54, 0xfe, 0x52, 0x82, 0x4b, 0x42, 0xa6, 0x40, 0x54, 0x6f, 0xe4,
-	0xd8, 0x1e, 0xcd, 0x74, 0x66, 0x69, 0xda, 0x99, 0x37, 0xa0, 0xe4, 0x0a, 0x12, 0x2e, 0xce, 0x54,
-	0x60, 0x09, 0x4d, 0x47, 0x22, 0xbc, 0xcc, 0xa1, 0xe4, 0x67, 0x0d, 0x45, 0xf9, 0x6b, 0x09, 0x56,
-	0x13, 0x02, 0x36, 0x0f, 0x35, 0xcb, 0xa2, 0x2c, 0xeb, 0xca, 0x52, 0x9c, 0x34, 0xad, 0xb8, 0xc7,
-	0x50, 0xd6, 0x43, 0x9a, 0x05, 0x22, 0xc6, 0x88, 0xe7, 0x94, 0xf1, 0xdb, 0x50, 0x8a, 0x5c, 0x34,
-	0x6b, 0x8e, 0x4a, 0x8b, 0xe7, 0x68, 0x2e, 0x3d, 0x47, 0x95, 0x7f, 0x94, 0xa0, 0xba, 0x4d, 0x4f,
-	0x7b, 0x87, 0x9a, 0x4b, 0x0d, 0x16, 0xc9, 0x71, 0x03, 0xaa, 0x47, 0x11, 0xc0, 0x31, 0x82, 0xdc,
-	0xad, 0xb6, 0xf1, 0xfa, 0x54, 0x20, 0x8f, 0x51, 0x48, 0x9a, 0x82, 0x2d, 0x04, 0x87, 0x9a, 0x77,
-	0xc8, 0xb3, 0x56, 0x2f, 0x3b, 0x91, 0x0c, 0x93, 0x5a, 0x92, 0xc0, 0xc4, 0xdf, 0x82, 0xab, 0x9a,
-	0x65, 0x39, 0x9f, 0x75, 0xc7, 0x7e, 0xf7, 0x55, 0x97, 0x85, 0xc9, 0xad, 0x60, 0x99, 0x3c, 0x4d,
-	0x87, 0xf2, 0x59, 0x58, 0xca, 0x2f, 0x97, 0x23, 0xcf, 0xef, 0x8d, 0x0f, 0x3c, 0xdd, 0x35, 0x0f,
-	0x78, 0xda, 0xec, 0x3b, 0x23, 0x53, 0x17, 0x0e, 0x1f, 0xbc, 0x60, 0x05, 0x56, 0xbc, 0x00, 0x85,
-	0xe7, 0x0e, 0x22, 0x5b, 0x4f, 0xc1, 0xf0, 0x27, 0xb0, 0xec, 0x8d, 0x0f, 0xd8, 0x02, 0xcc, 0x97,
-	0x9b, 0xda, 0xc6, 0x9d, 0xa9, 0x84, 0x2d, 0xd5, 0xd5, 0xfd, 0x5e, 0x80, 0x4d, 0x42, 0x32, 0x16,
-	0xdf, 0x75, 0xc7, 0xf6, 0xc6, 0x43, 0xea, 0xb2, 0xf8, 0x5e, 0x08, 0x72, 0xdc, 0x10, 0xd4, 0x36,
-	0xf0, 0x1b, 0x00, 0x2e, 0x8b, 0xf6, 0x9e, 0xcf, 0xda, 0x8b, 0xbc, 0xbd, 0x2c, 0x20, 0x6d, 0x83,
-	0x2d, 0x8d, 0x11, 0x3d, 0x37, 0xb1, 0x48, 0x3f, 0x43, 0x20, 0x37, 0xf0, 0x6d, 0xa8, 0x8d, 0x5c,
-	0xd3, 0x71, 0x4d, 0xff, 0x54, 0xb5, 0xe8, 0x31, 0x0d, 0xc2, 0x60, 0x91, 0x54, 0x43, 0xe8, 0x0e,
-	0x03, 0xe2, 0x1b, 0xb0, 0x6c, 0x8c, 0x5d, 0xed, 0xc0, 0xa2, 0x3c, 0xee, 0x95, 0x9e, 0x16, 0x7c,
-	0x77, 0x4c, 0x49, 0x08, 0xc4, 0x2d, 0x40, 0x7c, 0x47, 0x11, 0x4d, 0x67, 0x33, 0x08, 0x78, 0x95,
-	0x49, 0xdb, 0xa7, 0xb6, 0x62, 0xa4, 0xc6, 0x89, 0x22, 0x58, 0x6a, 0x0f, 0x01, 0x67, 0xdb, 0x43,
-	0xb0, 0x11, 0xb8, 0x54, 0x33, 0xd4, 0x68, 0x89, 0xe6, 0xf9, 0x69, 0x89, 0x54, 0x19, 0xb4, 0x19,
-	0x02, 0xf1, 0x7b, 0xb0, 0x14, 0x24, 0x71, 0x3c, 0x27, 0xad, 0x6c, 0xac, 0x66, 0x6d, 0x97, 0x89,
-	0xc0, 0xc1, 0x3f, 0x80, 0xba, 0x69, 0x9b, 0xbe, 0xa9, 0x59, 0x7b, 0x8e, 0x17, 0xec, 0xf9, 0xaa,
-	0x7c, 0x11, 0xbc, 0xbf, 0xc0, 0x8a, 0xed, 0x34, 0xd5, 0xd3, 0xa5, 0x1d, 0xcd, 0xa7, 0x9e, 0x4f,
-	0x26, 0xd9, 0xe1, 0x4f, 0xe0, 0x7a, 0x9c, 0xf7, 0x27, 0x3d, 0x47, 0xf5, 0x7c, 0xcd, 0xa7, 0x3c,
-	0x97, 0x2d, 0x91, 0x6b, 0x11, 0x4e, 0x2f, 0x81, 0xd2, 0x63, 0x18, 0xf8, 0x09, 0xac, 0xbe, 0x72,
-	0x5c, 0x9d, 0xed, 0x1a, 0x46, 0xa6, 0xae, 0xea, 0x2e, 0xd5, 0xb8, 0xa0, 0xf5, 0x84, 0x81, 0x30,
-	0xc7, 0xe8, 0x33, 0x84, 0xa6, 0x68, 0xc7, 0x5d, 0xb8, 0x95, 0xb6, 0x95, 0xeb, 0x58, 0xd6, 0x81,
-	0xa6, 0x1f, 0xa9, 0xcc, 0x9a, 0x81, 0x08, 0x54, 0xe7, 0x99, 0x2f, 0xcf, 0x6b, 0x6e, 0x26, 0x8d,
-	0x44, 0x04, 0xee, 0x96, 0x40, 0xed, 0x51, 0x3d, 0x3d, 0xeb, 0xa9, 0xaf, 0xf1, 0x14, 0xb8, 0x32,
-	0x7b, 0xd6, 0x53, 0x5f, 0x23, 0x69, 0x0a, 0x65, 0x13, 0x96, 0x85, 0xff, 0xb3, 0xdd, 0x7c, 0xeb,
-	0x44, 0xb7, 0xc6, 0x9e, 0x79, 0x1c, 0x96, 0x1a, 0x38, 0x1e, 0x92, 0xd8, 0xce, 0xfe, 0x99, 0x66,
-	0x5a, 0xce, 0x31, 0x75, 0x51, 0x0e, 0xd7, 0x00, 0xb6, 0xe9, 0xa9, 0x2a, 0x5a, 0xf3, 0xca, 0xbb,
-	0x50, 0x9f, 0xd0, 0x3e, 0x23, 0x0e, 0xf4, 0x8f, 0x2e, 0x30, 0xe2, 0x96, 0xe6, 0x5a, 0x26, 0x7b,
-	0x93, 0x94, 0xff, 0x90, 0xe0, 0xa6, 0x30, 0xde, 0x5e, 0x98, 0x81, 0x51, 0x83, 0x2b, 0x2a, 0xca,
-	0x49, 0xb3, 0x27, 0x7f, 0x7a, 0xd6, 0xe5, 0x26, 0x67, 0x5d, 0x76, 0x6e, 0x91, 0x3f, 0x5f, 0x6e,
-	0x51, 0x38, 0x67, 0x6e, 0x51, 0x9c, 0x95, 0x5b, 0x28, 0x7f, 0x9b, 0x83, 0xb7, 0x17, 0x8c, 0x33,
-	0x5a, 0x4f, 0x6f, 0x00, 0x44, 0xd9, 0xa8, 0xc7, 0x17, 0x84, 0x2a, 0x49, 0x40, 0x16, 0x8d, 0xfc,
-	0x7b, 0x89, 0x75, 0x36, 0xcf, 0x27, 0xcb, 0x27, 0x99, 0x93, 0x65, 0x91, 0x1c, 0xf7, 0x77, 0x1c,
-	0xe7, 0x68, 0x3c, 0xe2, 0xc1, 0x30, 0x5e, 0x91, 0x7f, 0x07, 0x8a, 0xd4, 0x75, 0x1d, 0x97, 0xeb,
-	0x66, 0xba, 0xda, 0xc5, 0xd7, 0xd3, 0x16, 0x43, 0x20, 0x01, 0x1e, 0x96, 0x61, 0x59, 0x38, 0xb8,
-	0x50, 0x4f, 0xf8, 0xaa, 0xdc, 0x06, 0x88, 0xbb, 0xc0, 0x15, 0xe6, 0x7a, 0xba, 0x4e, 0x3d, 0x2f,
-	0xf0, 0x36, 0xe6, 0x61, 0xcc, 0xdb, 0x94, 0x1f, 0xe5, 0x00, 0x0b, 0x91, 0x05, 0x3a, 0xb7, 0xff,
-	0x97, 0xf2, 0x8a, 0x77, 0xa1, 0xca, 0xec, 0xc5, 0x22, 0xaa, 0xe6, 0x9b, 0xc7, 0x81, 0x82, 0xa2,
-	0x35, 0x29, 0xdd, 0x36, 0xc3, 0x85, 0x0a, 0xe7, 0x73, 0xa1, 0xe2, 0x39, 0x5d, 0x68, 0x69, 0xa6,
-	0x0b, 0xfd, 0x3c, 0x0f, 0xd7, 0xa6, 0xf5, 0x10, 0x79, 0xcd, 0x5d, 0x40, 0x41, 0xca, 0xcd, 0x6c,
-	0x60, 0xea, 0x74, 0xdf, 0xb5, 0x44, 0x32, 0x31, 0x05, 0xc7, 0x0f, 0xe0, 0xd2, 0x24, 0xac, 0x6f,
-	0x79, 0x62, 0xcf, 0x96, 0xd5, 0x84, 0xbb, 0x53, 0x4e, 0xf5, 0x28, 0xd3, 0xa9, 0x32, 0x24, 0xcb,
-	0xf6, 0xa3, 0xb4, 0xa1, 0x0a, 0x0b, 0x0d, 0x55, 0x9c, 0x63, 0xa8, 0xc8, 0x27, 0x97, 0xce, 0xef,
-	0x93, 0xcb, 0x29, 0x9f, 0xe4, 0x3b, 0xc6, 0x60, 0x07, 0x73, 0xe8, 0x3a, 0xe3, 0xc1, 0xa1, 0xea,
-	0x05, 0x6a, 0xe0, 0xfb, 0x98, 0x52, 0x7a, 0xc7, 0xc8, 0xb7, 0x33, 0x01, 0x5a, 0xac, 0x2c, 0xe5,
-	0x51, 0xca, 0xab, 0x57, 0xa0, 0x44, 0xa8, 0x61, 0xba, 0x54, 0x67, 0xb1, 0xaf, 0x02, 0xcb, 0x22,
-	0x9b, 0x47, 0x52, 0xc2, 0xc7, 0x73, 0xca, 0xff, 0xe4, 0xa0, 0x1e, 0x4e, 0x4b, 0x51, 0x5f, 0x9b,
-	0xe1, 0xe0, 0x37, 0xa1, 0x12, 0x95, 0xe5, 0xe2, 0x8a, 0x5b, 0x08, 0x9a, 0xca, 0x46, 0xf2, 0x19,
-	0xd9, 0x48, 0xba, 0xac, 0x57, 0x10, 0x1b, 0xf5, 0x64, 0x59, 0xef, 0x16, 0x94, 0x45, 0x49, 0x86,
-	0x1a, 0x69, 0xcd, 0xc7, 0xf0, 0x54, 0x92, 0xb0, 0x74, 0xc6, 0x24, 0x21, 0x5e, 0xfd, 0x97, 0xcf,
-	0xb0, 0xfa, 0x5f, 0x85, 0x22, 0x1d, 0x39, 0xfa, 0x61, 0xb0, 0xa7, 0x66, 0x6b, 0x60, 0xf0, 0x8e,
-	0x9b, 0xf0, 0xfa, 0xd8, 0xa3, 0xae, 0x3a, 0x72, 0x9d, 0x63, 0xd3, 0xa0, 0x86, 0x9a, 0x1e, 0x52,
-	0x39, 0xb1, 0xf2, 0xca, 0x0c, 0x71, 0x4f, 0xe0, 0xed, 0x25, 0x06, 0xa9, 0xfc, 0x71, 0x0e, 0x2a,
-	0x61, 0xde, 0x40, 0x6d, 0x63, 0x52, 0xb3, 0xd2, 0x94, 0x66, 0x17, 0x16, 0x3b, 0xdf, 0x82, 0x95,
-	0x64, 0xa5, 0x2e, 0xdc, 0x05, 0x3c, 0x24, 0x95, 0x44, 0x81, 0x2e, 0xb3, 0x0e, 0x54, 0x38, 0x47,
-	0x1d, 0xa8, 0x78, 0xbe, 0x3a, 0xd0, 0xd2, 0x9c, 0x3a, 0xd0, 0xdf, 0x4b, 0x51, 0x68, 0x65, 0x2a,
-	0x20, 0x54, 0xa7, 0xe6, 0xc8, 0xff, 0x35, 0x68, 0xe2, 0x29, 0x40, 0x22, 0x03, 0xcd, 0x2f, 0xce,
-	0x40, 0xcb, 0xc3, 0x28, 0xf9, 0x9c, 0x31, 0x8e, 0xc2, 0x9c, 0x71, 0xfc, 0x55, 0xbc, 0x5b, 0x66,
-	0xe3, 0xe0, 0x73, 0xfd, 0xd7, 0x30, 0x8a, 0x28, 0xae, 0xe4, 0x33, 0x4f, 0x76, 0xe6, 0xc6, 0x95,
-	0x02, 0x9f, 0xb4, 0xd1, 0x5a, 0xf7, 0x17, 0x52, 0x54, 0x03, 0x12, 0x03, 0x9f, 0xdc, 0x57, 0x48,
-	0x53, 0xfb, 0x8a, 0xb4, 0x12, 0x99, 0x78, 0x67, 0x57, 0xe2, 0x7b, 0x80, 0x5c, 0x2a, 0x0a, 0x94,
-	0xa7, 0xaa, 0xee, 0x8c, 0x6d, 0x9f, 0x9b, 0x81, 0xd7, 0x98, 0xeb, 0x71, 0x53, 0x93, 0xb5, 0x28,
-	0xff, 0x54, 0x00, 0x08, 0x37, 0xce, 0xfa, 0xd1, 0x62, 0xc9, 0x3e, 0x82, 0x12, 0xcb, 0x53, 0x79,
-	0xa1, 0x33, 0xc7, 0x75, 0xb3, 0x96, 0xb9, 0x1a, 0x34, 0xf4, 0xa3, 0xfb, 0x0d, 0xfd, 0x28, 0xd8,
-	0x4f, 0x69, 0xc1, 0xc3, 0x94, 0x6f, 0xe4, 0xcf, 0x31, 0xac, 0x1e, 0xa0, 0x63, 0xcd, 0x32, 0x8d,
-	0x20, 0x3d, 0x4e, 0x26, 0x22, 0xeb, 0x33, 0x05, 0x78, 0x19, 0x11, 0x04, 0xb6, 0xaa, 0x1f, 0xa7,
-	0x01, 0x4c, 0xa0, 0xa9, 0x03, 0xb9, 0x6b, 0x53, 0xa1, 0x2c, 0x3a, 0xf7, 0x49, 0xd5, 0x3d, 0xb3,
-	0x26, 0xf3, 0xd2, 0x39, 0x26, 0xf3, 0x72, 0xf6, 0x64, 0x56, 0xde, 0x81, 0x65, 0xa1, 0x38, 0x96,
-	0x5f, 0xb7, 0x6d, 0xc3, 0x3c, 0x36, 0x8d, 0xb1, 0x66, 0xa1, 0x0b, 0xec, 0xbd, 0x39, 0x1e, 0x8e,
-	0x2d, 0xbe, 0xf6, 0x21, 0x49, 0xf9, 0x53, 0x09, 0xea, 0x13, 0x63, 0xc4, 0x37, 0xe0, 0xda, 0xfe,
-	0xc4, 0x39, 0x42, 0xd3, 0x71, 0xdd, 0x31, 0xdf, 0xb6, 0xa0, 0x0b, 0xf8, 0x0a, 0xe0, 0x2d, 0x9a,
-	0x38, 0x94, 0xe0, 0x54, 0x48, 0xc2, 0xab, 0x80, 0x9a, 0x87, 0x54, 0x3f, 0xf2, 0xc6, 0xc3, 0x5d,
-	0xd3, 0x1b, 0x6a, 0xbe, 0x7e, 0x88, 0x72, 0xf8, 0x35, 0xb8, 0xcc, 0x0f, 0x15, 0xb6, 0x68, 0x8f,
-	0xba, 0xa6, 0x66, 0x99, 0x9f, 0xd3, 0x80, 0x20, 0x8f, 0x2f, 0x41, 0x7d, 0x8b, 0x86, 0xc5, 0xfb,
-	0x00, 0x58, 0x50, 0xfe, 0x35, 0x0e, 0x2b, 0x0d, 0xfd, 0x28, 0xca, 0x50, 0x16, 0xba, 0x55, 0x96,
-	0x32, 0x73, 0xe7, 0x50, 0x66, 0x7e, 0x46, 0x64, 0xfc, 0x35, 0xe6, 0xac, 0x07, 0xf0, 0x7a, 0x34,
-	0x32, 0xa6, 0xff, 0xa6, 0x90, 0xbe, 0x79, 0xc8, 0x8f, 0xf7, 0x16, 0x0e, 0x51, 0x81, 0xb2, 0xe9,
-	0xa9, 0x1a, 0xa7, 0xe5, 0x63, 0x8b, 0x56, 0xd6, 0x92, 0xe9, 0x05, 0x2c, 0x95, 0x97, 0xd1, 0xba,
-	0xf4, 0xcc, 0x72, 0x3e, 0x5b, 0xcc, 0xf3, 0x0e, 0xd4, 0x84, 0x78, 0x7b, 0xd4, 0x1d, 0x06, 0x4a,
-	0xcb, 0xad, 0x57, 0xc9, 0x04, 0x54, 0xe9, 0x47, 0x56, 0xd9, 0xb7, 0xbd, 0xa8, 0xb4, 0xb2, 0x90,
-	0xfd, 0xfc, 0x94, 0x5a, 0xf9, 0xa9, 0x94, 0x58, 0x46, 0xe9, 0xd1, 0x57, 0xe5, 0xf7, 0x95, 0x96,
-	0x8e, 0x07, 0xb0, 0x1a, 0xd2, 0xa6, 0x4e, 0x1d, 0xf9, 0xda, 0x41, 0x70, 0xa8, 0x8f, 0xf8, 0xf0,
-	0x51, 0xf9, 0x08, 0x64, 0x21, 0x3c, 0xa1, 0x9a, 0x7e, 0x48, 0x8d, 0x96, 0x6d, 0x74, 0x5f, 0xf5,
-	0xc3, 0x54, 0x6b, 0xee, 0x48, 0x94, 0x97, 0x51, 0xb9, 0xb1, 0x69, 0x39, 0x1e, 0x8d, 0x32, 0xb7,
-	0x85, 0x2b, 0xcf, 0x02, 0x95, 0x4e, 0xf0, 0x0d, 0x7d, 0xec, 0x2b, 0x9b, 0xea, 0x4f, 0x24, 0xb8,
-	0x13, 0x8d, 0x56, 0xac, 0x00, 0xfb, 0xb6, 0xa6, 0x1f, 0xd9, 0xce, 0x67, 0xfc, 0xe8, 0xdd, 0x88,
-	0x92, 0x94, 0x85, 0x5d, 0x7d, 0x0c, 0x95, 0xd8, 0x4c, 0xcc, 0xe3, 0x16, 0x86, 0x71, 0x88, 0xec,
-	0xe4, 0x29, 0xdf, 0x8f, 0x56, 0x43, 0xb1, 0xe7, 0x9b, 0x10, 0x5d, 0x9a, 0xf4, 0x8a, 0x38, 0x71,
-	0xcc, 0x2d, 0x4e, 0x1c, 0x95, 0x9f, 0x48, 0x70, 0x65, 0x22, 0x9d, 0x3e, 0x63, 0x3f, 0x53, 0xe9,
-	0x71, 0x2e, 0xe3, 0xd4, 0xfb, 0x3d, 0x40, 0x96, 0x36, 0x91, 0x9e, 0x30, 0x47, 0xcd, 0xf3, 0x6b,
-	0x06, 0x35, 0xd6, 0x16, 0x27, 0x27, 0x19, 0x87, 0x99, 0x85, 0x8c, 0xc3, 0x4c, 0xe5, 0x04, 0x56,
-	0x84, 0xc8, 0x41, 0x08, 0x5f, 0x20, 0x68, 0x14, 0xd3, 0x72, 0xe7, 0xcf, 0x4d, 0xf2, 0xe9, 0xdc,
-	0xa4, 0x1a, 0x4d, 0xe0, 0x3d, 0xd3, 0x1e, 0x24, 0x5f, 0x1d, 0x7b, 0x90, 0x74, 0x46, 0x61, 0xfd,
-	0x9e, 0xaf, 0xf9, 0x0b, 0x15, 0xb9, 0xa8, 0x6a, 0xaa, 0xfc, 0x77, 0x01, 0xae, 0x67, 0x31, 0x26,
-	0xd9, 0x3b, 0xc4, 0xa9, 0x0e, 0x3e, 0x00, 0xe0, 0x03, 0x53, 0x75, 0xc7, 0xa0, 0xe2, 0x68, 0x6c,
-	0x8e, 0x16, 0xca, 0x1c, 0xb9, 0xe9, 0x18, 0x6c, 0x77, 0x53, 0x0d, 0x28, 0x63, 0x7d, 0xf0, 0x2d,
-	0x10, 0x07, 0x86, 0xd9, 0xd9, 0x0d, 0x80, 0xa1, 0x37, 0x20, 0x9a, 0x4f, 0xbb, 0xe2, 0x14, 0x52,
-	0x22, 0x09, 0x08, 0xdb, 0x6e, 0x0f, 0xbd, 0x81, 0xd8, 0xfe, 0x8d, 0xc6, 0x3e, 0xc3, 0x2a, 0x72,
-	0xac, 0x29, 0xb8, 0xc0, 0x65, 0x94, 0xd1, 0xb4, 0xe3, 0x49, 0x42, 0x80, 0x9b, 0x82, 0x63, 0x05,
-	0x52, 0x85, 0x61, 0xb1, 0x3f, 0x4d, 0x17, 0x8b, 0xef, 0x02, 0xd2, 0x8e, 0x35, 0xd3, 0xd2, 0x0e,
-	0xac, 0x28, 0xe4, 0x07, 0xc7, 0x8e, 0x53, 0x70, 0xbc, 0x0e, 0xf5, 0x31, 0x9b, 0xe2, 0xf1, 0xdc,
-	0xe6, 0xdb, 0xa3, 0x02, 0x99, 0x04, 0xe3, 0x4d, 0xb8, 0x7e, 0x60, 0x39, 0x0c, 0x14, 0xda, 0xa3,
-	0x6b, 0xef, 0x0b, 0x1c, 0x4f, 0x9c, 0x86, 0x95, 0xc8, 0x5c, 0x1c, 0xe6, 0x64, 0x9a, 0x61, 0xb0,
-	0xa4, 0x82, 0x57, 0x7f, 0xcb, 0x24, 0x7c, 0x65, 0x8b, 0x94, 0x1e, 0x1e, 0x64, 0xf5, 0x4c, 0x5b,
-	0x0f, 0xee, 0x24, 0x94, 0xc9, 0x04, 0x14, 0x63, 0x71, 0x99, 0xaa, 0xca, 0x5b, 0x83, 0x1b, 0x53,
-	0x6c, 0x81, 0x0b, 0xf4, 0xd4, 0x3a, 0x19, 0x99, 0x2e, 0x35, 0x78, 0x55, 0x56, 0x22, 0x13, 0x50,
-	0x61, 0xb3, 0x4d, 0x4d, 0x3f, 0xb2, 0x9c, 0x01, 0xaf, 0xbf, 0x16, 0x48, 0x02, 0xa2, 0x7c, 0x0a,
-	0x57, 0x85, 0xc7, 0x3d, 0xa7, 0xfe, 0x8e, 0xe6, 0x25, 0x2a, 0xde, 0x5f, 0x35, 0xb4, 0xfe, 0x28,
-	0xae, 0x63, 0x4e, 0xf2, 0x8e, 0x1c, 0xba, 0x09, 0x75, 0x1e, 0x36, 0x12, 0xcb, 0x9b, 0xb4, 0x38,
-	0xa9, 0xaf, 0x5a, 0x29, 0x41, 0x17, 0xc8, 0xf1, 0x0b, 0x29, 0x4a, 0x50, 0x9e, 0x53, 0x9f, 0xaf,
-	0x63, 0x5e, 0xf7, 0x15, 0xf3, 0x1a, 0x6f, 0xa4, 0xe9, 0x0b, 0x27, 0xd5, 0x75, 0x28, 0xdb, 0x21,
-	0xae, 0x08, 0x7d, 0x31, 0x00, 0x77, 0xa0, 0x30, 0x64, 0x93, 0x2d, 0x3f, 0xa7, 0x04, 0x9f, 0xd5,
-	0xeb, 0xfd, 0x5d, 0xc7, 0xa0, 0x4f, 0x61, 0xaf, 0x45, 0x7a, 0xed, 0x5e, 0xbf, 0xd5, 0xe9, 0x13,
-	0xce, 0x47, 0x79, 0x04, 0x05, 0x7e, 0xd6, 0x54, 0x83, 0x44, 0x1b, 0xba, 0x80, 0x31, 0xd4, 0x3a,
-	0xdd, 0x8e, 0x9a, 0x80, 0x49, 0x78, 0x19, 0xf2, 0x8d, 0x9d, 0x1d, 0x94, 0x53, 0xbe, 0x07, 0xb7,
-	0xe6, 0x74, 0x75, 0xd6, 0xe8, 0x71, 0x05, 0x96, 0x78, 0x3d, 0x25, 0x58, 0xb9, 0xca, 0x44, 0xbc,
-	0x29, 0x76, 0xb4, 0x91, 0x7c, 0x4e, 0x7d, 0x71, 0x29, 0x70, 0x01, 0xab, 0xa8, 0x4e, 0x93, 0x4b,
-	0xd6, 0x69, 0xa6, 0xa3, 0x7e, 0x3e, 0x2b, 0xea, 0xff, 0x4a, 0x8a, 0x12, 0x90, 0xa8, 0xc3, 0xdf,
-	0x92, 0x08, 0x18, 0x2f, 0xb9, 0x85, 0x33, 0xd4, 0x6a, 0xa6, 0xc7, 0x5b, 0xcc, 0x1a, 0xef, 0x1f,
-	0x26, 0x87, 0xdb, 0x75, 0xf9, 0x61, 0x08, 0xfd, 0x2a, 0x7a, 0x8e, 0xa5, 0xcc, 0xf3, 0xb9, 0x34,
-	0x3f, 0x31, 0xf8, 0x07, 0x09, 0xd6, 0x66, 0xf5, 0xff, 0x5b, 0xa2, 0xf6, 0x33, 0xa6, 0x0b, 0x3f,
-	0x84, 0xaa, 0x18, 0x48, 0x87, 0x7e, 0xd6, 0x3f, 0xb1, 0x17, 0x49, 0x1d, 0x6c, 0x97, 0x54, 0xdf,
-	0xb7, 0x54, 0x8f, 0xea, 0x8e, 0x6d, 0x24, 0xb6, 0x56, 0x6c, 0xbb, 0xd4, 0xf7, 0xad, 0x5e, 0x00,
-	0xc7, 0x57, 0xa0, 0xe8, 0xeb, 0x61, 0x4e, 0xc3, 0x11, 0x0a, 0xbe, 0xde, 0x36, 0x94, 0x9f, 0x4b,
-	0x70, 0x39, 0xd5, 0xe7, 0x59, 0x35, 0xf6, 0xff, 0x60, 0x5f, 0xf7, 0x93, 0x78, 0x1e, 0x36, 0x8c,
-	0xf8, 0x68, 0xa4, 0xef, 0x9c, 0x41, 0xb5, 0xff, 0x57, 0xc3, 0x4b, 0x9f, 0x03, 0x15, 0x78, 0x9c,
-	0x4a, 0x40, 0x94, 0x7f, 0x8f, 0x9d, 0x79, 0x4a, 0xe6, 0xaf, 0x91, 0x69, 0x5e, 0xc0, 0x4a, 0xf2,
-	0xd0, 0xf5, 0xcb, 0xdf, 0x05, 0x50, 0xfe, 0x25, 0x5e, 0x1c, 0x1b, 0x86, 0x91, 0x64, 0xfa, 0x1b,
-	0xb5, 0xf3, 0xef, 0x4e, 0x88, 0x5e, 0xc8, 0xaa, 0x40, 0x25, 0xa5, 0x9d, 0x18, 0xd6, 0x7f, 0x49,
-	0xd1, 0x92, 0x98, 0x35, 0xac, 0xaf, 0x91, 0x2b, 0xfc, 0x9d, 0x14, 0x45, 0xbd, 0x96, 0x6d, 0xfc,
-	0x06, 0x4d, 0xf6, 0x04, 0x80, 0x45, 0x53, 0x4d, 0xf7, 0xc3, 0x00, 0x5d, 0xdb, 0xb8, 0x9a, 0x1e,
-	0x58, 0xff, 0xc4, 0x6e, 0xf0, 0x66, 0x52, 0xf6, 0xc3, 0xc7, 0x64, 0x08, 0x0d, 0x06, 0xf0, 0x35,
-	0x32, 0xce, 0x2f, 0xe2, 0x10, 0x1a, 0x8c, 0xad, 0x6b, 0x47, 0x31, 0xe9, 0x37, 0x35, 0xbc, 0x28,
-	0x56, 0x04, 0xa7, 0x5c, 0x22, 0x56, 0xa4, 0xad, 0x57, 0x3c, 0xb3, 0xf5, 0x12, 0x01, 0x77, 0x6a,
-	0x84, 0x5f, 0x23, 0x43, 0xfe, 0x38, 0x17, 0x85, 0xc9, 0x70, 0x98, 0xa9, 0x00, 0xfc, 0x5b, 0x13,
-	0x26, 0xa5, 0xf3, 0x84, 0xc9, 0x2f, 0x6d, 0xf5, 0x44, 0x78, 0xcd, 0x52, 0xc7, 0xd7, 0xc8, 0xf0,
-	0x3f, 0x5b, 0x87, 0xca, 0xa6, 0xe6, 0x51, 0x31, 0x5a, 0xbc, 0x21, 0xf6, 0xe2, 0xc1, 0xed, 0xc1,
-	0x1b, 0x69, 0xce, 0x09, 0xc4, 0xf4, 0xd7, 0x4d, 0xcb, 0x62, 0x47, 0x2f, 0x2a, 0x75, 0xd7, 0x33,
-	0xb7, 0x89, 0xe2, 0x18, 0x9c, 0x84, 0xc8, 0xf8, 0x63, 0x28, 0x47, 0x95, 0x00, 0x51, 0xf5, 0xbd,
-	0x31, 0x8f, 0x92, 0x1a, 0x24, 0x26, 0x60, 0xd4, 0x51, 0x45, 0x5b, 0x38, 0xc6, 0x8d, 0xf9, 0x37,
-	0xc4, 0x48, 0x4c, 0x80, 0x3f, 0x84, 0x52, 0x58, 0xdf, 0xe3, 0x2a, 0xa9, 0x64, 0x7c, 0xd5, 0x91,
-	0xac, 0x25, 0x92, 0x08, 0x1d, 0xdf, 0x83, 0x82, 0x47, 0xed, 0xe0, 0x94, 0xb5, 0x32, 0xa9, 0xfc,
-	0xe4, 0xd1, 0x2a, 0x47, 0xc3, 0x4d, 0x58, 0x61, 0xbf, 0xaa, 0x1b, 0x9c, 0xb4, 0x8a, 0x53, 0xf0,
-	0xb5, 0xd9, 0x64, 0x01, 0x1e, 0xa9, 0x78, 0x89, 0xe3, 0xd9, 0x6f, 0x02, 0x70, 0x26, 0x81, 0xd9,
-	0x4b, 0xf3, 0x46, 0x1b, 0x1e, 0x86, 0x92, 0xb2, 0x17, 0x9d, 0x8b, 0x3e, 0x89, 0xed, 0x5f, 0x9e,
-	0x63, 0xa1, 0xf0, 0xa2, 0x59, 0x74, 0x35, 0xe2, 0x2e, 0xe4, 0x35, 0xfd, 0x48, 0x5c, 0x8a, 0x96,
-	0x67, 0x1d, 0xb7, 0x11, 0x86, 0xc4, 0xd4, 0xf2, 0xca, 0x72, 0x3e, 0xe3, 0x45, 0xa0, 0x59, 0x6a,
-	0x79, 0x66, 0x39, 0x9f, 0x11, 0x8e, 0x86, 0x37, 0xa1, 0x32, 0x8e, 0x8f, 0x24, 0xc4, 0xcd, 0xc0,
-	0x6c, 0xad, 0x24, 0x8e, 0x2e, 0x48, 0x92, 0x88, 0x0d, 0xcb, 0x0b, 0x6a, 0xbc, 0xbc, 0x76, 0x34,
-	0x6b, 0x58, 0xa2, 0x0e, 0x4c, 0x42, 0x64, 0xfc, 0x20, 0x9c, 0x3f, 0xb5, 0xac, 0x78, 0x92, 0x2c,
-	0xc9, 0x86, 0x13, 0xa8, 0x0d, 0x35, 0xdd, 0x72, 0x3c, 0x1a, 0xdd, 0x3a, 0xe0, 0xa5, 0xa6, 0xca,
-	0x86, 0x92, 0xed, 0xaf, 0xc9, 0xa3, 0x01, 0x52, 0xd5, 0x53, 0x27, 0x05, 0x11, 0xab, 0xb0, 0xd2,
-	0xc4, 0xaf, 0xfb, 0xcd, 0x65, 0x15, 0x16, 0xde, 0x04, 0xab, 0xe8, 0x70, 0xa0, 0xcb, 0xaf, 0x21,
-	0x07, 0x95, 0xeb, 0x50, 0x11, 0xc1, 0x05, 0xc0, 0xb7, 0xe6, 0x3a, 0x73, 0xa8, 0x90, 0xfa, 0x68,
-	0xa2, 0x52, 0x7e, 0x0f, 0x0a, 0x23, 0xd3, 0x1e, 0xf0, 0x6f, 0x62, 0x66, 0xd9, 0x70, 0xcf, 0xb4,
-	0x07, 0x84, 0xa3, 0x71, 0x74, 0xc7, 0x1e, 0xf0, 0x8f, 0x63, 0x66, 0xa2, 0x3b, 0x1c, 0xdd, 0xb1,
-	0x07, 0xf8, 0x8f, 0xe0, 0xa6, 0x3b, 0xff, 0x0c, 0x82, 0x7f, 0x42, 0x53, 0xd9, 0x78, 0x9c, 0xc9,
-	0x69, 0xc1, 0xf9, 0x05, 0x59, 0xc4, 0x1c, 0xff, 0x01, 0x5c, 0x8c, 0xb6, 0x52, 0xe1, 0xcd, 0x37,
-	0xf9, 0x32, 0xef, 0xf1, 0xde, 0xf9, 0xae, 0xcb, 0x4d, 0xf3, 0xc1, 0x5e, 0xe2, 0xbb, 0x93, 0xc9,
-	0x6b, 0x75, 0xfc, 0x9b, 0x9f, 0xca, 0xc6, 0xfb, 0x5f, 0xea, 0x4e, 0x1e, 0x99, 0xcd, 0x97, 0x4d,
-	0x22, 0x2b, 0xbe, 0x7d, 0xc5, 0x3f, 0x17, 0x9a, 0x35, 0x89, 0x92, 0xb7, 0xb4, 0x92, 0x44, 0xf8,
-	0xbb, 0x70, 0xc9, 0x9a, 0xbe, 0xc1, 0xc5, 0xbf, 0x25, 0xaa, 0xcc, 0x38, 0x62, 0xcf, 0xb8, 0xf1,
-	0x45, 0xb2, 0x98, 0xe0, 0x17, 0xf1, 0x3d, 0x68, 0x5e, 0xe8, 0x97, 0x5f, 0x9b, 0xe7, 0xea, 0xa9,
-	0x23, 0x81, 0x34, 0x21, 0xfe, 0x01, 0x5c, 0xd6, 0xb3, 0x8e, 0x0c, 0xe4, 0x6b, 0x9c, 0xe3, 0xdd,
-	0x33, 0x70, 0x0c, 0x25, 0xcd, 0x66, 0x84, 0xfb, 0x70, 0xd1, 0x9d, 0x3c, 0x0f, 0x94, 0x5f, 0xe7,
-	0xdc, 0xef, 0xcc, 0xf0, 0xc7, 0x09, 0x6c, 0x32, 0xcd, 0x20, 0x58, 0x2c, 0xe8, 0x91, 0x7c, 0x7d,
-	0xee, 0x62, 0x41, 0x8f, 0x08, 0x47, 0xc3, 0xdf, 0x06, 0x34, 0x98, 0xa8, 0x25, 0xcb, 0x6f, 0x70,
-	0xd2, 0xdb, 0xb3, 0x4a, 0xaf, 0xe9, 0xc2, 0xf3, 0x14, 0x39, 0x36, 0x41, 0x1e, 0xcc, 0x28, 0x4f,
-	0xcb, 0x37, 0xe6, 0x38, 0xff, 0xac, 0x9a, 0x36, 0x99, 0xc9, 0x0e, 0xab, 0x70, 0x25, 0x38, 0xe6,
-	0x8e, 0x62, 0x9b, 0xaa, 0xf3, 0x43, 0x72, 0xf9, 0x26, 0xef, 0xe8, 0x9d, 0x19, 0x2b, 0xc8, 0xf4,
-	0xa9, 0x3a, 0x59, 0xd5, 0xb2, 0xce, 0xda, 0xbf, 0x0f, 0xab, 0x83, 0x8c, 0x0a, 0xb0, 0xbc, 0x36,
-	0x87, 0x7d, 0x66, 0xc9, 0x38, 0x93, 0x0d, 0x1e, 0xc3, 0xf5, 0xc1, 0x9c, 0x02, 0xb3, 0xfc, 0x26,
-	0xef, 0xe6, 0xe1, 0xd9, 0xbb, 0x09, 0x55, 0x36, 0x97, 0x2d, 0xcb, 0x64, 0x06, 0x61, 0x21, 0x58,
-	0x56, 0xe6, 0xac, 0xed, 0x71, 0xb9, 0x38, 0x26, 0x60, 0x7e, 0x3b, 0x98, 0x2c, 0x23, 0xcb, 0xb7,
-	0xe6, 0xf8, 0xed, 0x54, 0xd1, 0x99, 0x4c, 0x33, 0x60, 0x33, 0x57, 0x4b, 0x7e, 0x4f, 0x23, 0xbf,
-	0x35, 0x67, 0xe6, 0xa6, 0xbe, 0xbc, 0x21, 0x69, 0x42, 0xdc, 0x82, 0x15, 0x2d, 0xf1, 0xe9, 0x90,
-	0x7c, 0x9b, 0x33, 0x7a, 0x73, 0x26, 0xa3, 0x48, 0xaa, 0x14, 0x19, 0x0b, 0x75, 0x5a, 0x7c, 0xb1,
-	0x44, 0xbe, 0x33, 0x27, 0xd4, 0x25, 0x2e, 0xa0, 0x90, 0x24, 0x91, 0x50, 0x55, 0xba, 0x04, 0x2c,
-	0xbf, 0x3d, 0x5f, 0x55, 0x13, 0x05, 0xe3, 0x69, 0x06, 0xd8, 0x82, 0xd7, 0x06, 0xb3, 0x0a, 0xcb,
-	0xf2, 0x3a, 0xe7, 0x7e, 0xff, 0x8c, 0xdc, 0xa3, 0x90, 0x3f, 0x93, 0x21, 0x7e, 0x04, 0x4b, 0x36,
-	0xaf, 0xc4, 0xca, 0x1b, 0x59, 0xf7, 0x24, 0xd2, 0xc5, 0x5a, 0x81, 0x8a, 0xb7, 0xa1, 0x66, 0xa7,
-	0xca, 0xb7, 0xf2, 0x23, 0x4e, 0x7c, 0x6b, 0x1e, 0x71, 0x28, 0xcc, 0x04, 0x29, 0xd3, 0xa2, 0x36,
-	0x59, 0x7b, 0x94, 0x1f, 0xcf, 0xd1, 0xe2, 0x74, 0xa5, 0x72, 0x9a, 0x01, 0xd3, 0xa2, 0x36, 0xab,
-	0xa2, 0x29, 0xbf, 0x3f, 0x47, 0x8b, 0x33, 0xeb, 0xa0, 0x64, 0x36, 0x43, 0x16, 0x48, 0xb4, 0x8c,
-	0xba, 0x99, 0xfc, 0x64, 0x5e, 0x9c, 0xca, 0x2a, 0xb4, 0x65, 0xb2, 0x61, 0x81, 0x44, 0x9b, 0x53,
-	0x96, 0x93, 0xbf, 0x31, 0x27, 0x90, 0xcc, 0xab, 0xe7, 0x91, 0xb9, 0x6c, 0x99, 0x6f, 0x50, 0xbe,
-	0x5d, 0x95, 0x3f, 0x98, 0xe3, 0x1b, 0xa2, 0x0a, 0x25, 0x50, 0x99, 0x6f, 0xd0, 0x54, 0x5d, 0x4a,
-	0xfe, 0x70, 0x8e, 0x6f, 0xa4, 0x4b, 0x58, 0x64, 0x82, 0x94, 0xf9, 0x06, 0x9d, 0x2c, 0x93, 0xc8,
-	0x4f, 0xe7, 0xf8, 0xc6, 0x74, 0x51, 0x65, 0x9a, 0x01, 0xf3, 0x0d, 0x3a, 0xab, 0xf8, 0x22, 0x7f,
-	0x34, 0xc7, 0x37, 0x66, 0x96, 0x6c, 0xc8, 0x6c, 0x86, 0xcc, 0x37, 0x68, 0xc6, 0xa6, 0x5f, 0xfe,
-	0x78, 0x8e, 0x6f, 0x64, 0x56, 0x09, 0x32, 0xd9, 0x30, 0xdf, 0xa0, 0x73, 0x6a, 0x0a, 0xf2, 0x37,
-	0xe7, 0xf8, 0xc6, 0xbc, 0x62, 0x04, 0x99, 0xcb, 0x56, 0xf9, 0x45, 0x49, 0xfc, 0x95, 0x48, 0x05,
-	0x96, 0x9b, 0xdd, 0x4e, 0xa7, 0xd5, 0xec, 0xa3, 0x1c, 0xae, 0x42, 0x59, 0xbc, 0xb4, 0xb6, 0x50,
-	0x9e, 0xbd, 0xf6, 0xf6, 0x37, 0x7b, 0x4d, 0xd2, 0xde, 0x6c, 0xa1, 0x02, 0xff, 0x57, 0x11, 0xd2,
-	0xdd, 0xda, 0x6f, 0xb6, 0x48, 0xf0, 0x0f, 0x22, 0xbd, 0x56, 0x67, 0x0b, 0x2d, 0x61, 0x04, 0x2b,
-	0xec, 0x49, 0x25, 0xad, 0x66, 0xab, 0xbd, 0xd7, 0x47, 0xcb, 0xb8, 0x06, 0xc0, 0x21, 0x2d, 0x42,
-	0xba, 0x04, 0x95, 0x58, 0x27, 0xbb, 0xad, 0x5e, 0xaf, 0xf1, 0xbc, 0x85, 0xca, 0xfc, 0x1c, 0xb7,
-	0xb9, 0x8d, 0x80, 0x71, 0x78, 0xb6, 0xd3, 0xfd, 0x0e, 0xaa, 0xe0, 0x3a, 0x54, 0xf6, 0x3b, 0x71,
-	0x57, 0x2b, 0xfc, 0x53, 0x90, 0xfd, 0x66, 0xb3, 0xd5, 0xeb, 0xa1, 0x2a, 0x2e, 0x43, 0x31, 0x60,
-	0x54, 0xc3, 0x18, 0x6a, 0xcd, 0x9d, 0x6e, 0xaf, 0xa5, 0x46, 0x82, 0xd4, 0x63, 0x58, 0xb3, 0xdb,
-	0xe9, 0xed, 0xef, 0xb6, 0x08, 0x42, 0x78, 0x15, 0x50, 0x88, 0xa1, 0x86, 0x8c, 0x2e, 0xb2, 0x0e,
-	0xf7, 0xda, 0x9d, 0xe7, 0x08, 0xf3, 0xa7, 0x6e, 0xe7, 0x39, 0xba, 0x84, 0x6f, 0xc3, 0x9b, 0xa4,
-	0xb5, 0xd5, 0xda, 0x69, 0xbf, 0x6c, 0x11, 0x75, 0xbf, 0xd3, 0x68, 0x6e, 0x77, 0xba, 0xdf, 0xd9,
-	0x69, 0x6d, 0x3d, 0x6f, 0x6d, 0xa9, 0x42, 0xe6, 0x1e, 0x5a, 0xc5, 0x32, 0xac, 0xee, 0x35, 0x48,
-	0xbf, 0xdd, 0x6f, 0x77, 0x3b, 0xbc, 0xa5, 0xdf, 0xd8, 0x6a, 0xf4, 0x1b, 0xe8, 0x32, 0x7e, 0x13,
-	0xde, 0xc8, 0x6a, 0x51, 0x49, 0xab, 0xb7, 0xd7, 0xed, 0xf4, 0x5a, 0xe8, 0x0a, 0xff, 0xf8, 0xa9,
-	0xdb, 0xdd, 0xde, 0xdf, 0x43, 0x57, 0xf1, 0x25, 0xa8, 0x07, 0xcf, 0x31, 0x82, 0xcc, 0x87, 0x20,
-	0x84, 0x57, 0x7b, 0xfd, 0x46, 0xbf, 0x87, 0x5e, 0xc3, 0xaf, 0xc3, 0xd5, 0x34, 0x2c, 0x26, 0xb8,
-	0xc6, 0xc4, 0x21, 0xad, 0x46, 0xf3, 0x45, 0x6b, 0x4b, 0x65, 0x7a, 0xee, 0x3e, 0x53, 0xfb, 0xdd,
-	0xbd, 0x76, 0x13, 0xbd, 0x1e, 0x98, 0xa5, 0xb5, 0x8d, 0xae, 0xe3, 0xab, 0x70, 0xe9, 0x79, 0xab,
-	0xaf, 0xee, 0x34, 0x7a, 0xfd, 0x70, 0x24, 0x6a, 0x7b, 0x0b, 0xbd, 0x81, 0xd7, 0xe0, 0x7a, 0x46,
-	0x43, 0xcc, 0xfe, 0x06, 0xbe, 0x06, 0x57, 0x1a, 0xcd, 0x7e, 0xfb, 0x65, 0xac, 0x53, 0xb5, 0xf9,
-	0xa2, 0xd1, 0x79, 0xde, 0x42, 0x37, 0x99, 0x5c, 0x8c, 0x9a, 0xf7, 0xd7, 0x63, 0x3d, 0x77, 0x1a,
-	0xbb, 0xad, 0xde, 0x5e, 0xa3, 0xd9, 0x42, 0x6b, 0xf8, 0x2d, 0x58, 0x9b, 0xd1, 0x18, 0xb3, 0x7f,
-	0x93, 0xb9, 0x07, 0xc3, 0xea, 0x35, 0x5f, 0xb4, 0x76, 0x1b, 0x48, 0x09, 0x25, 0x0d, 0xde, 0x63,
-	0xc4, 0x5b, 0x4c, 0x2f, 0x8d, 0xfd, 0xfe, 0x0b, 0xd6, 0xf9, 0xce, 0x4e, 0x8b, 0xf5, 0xff, 0x16,
-	0xbe, 0x08, 0x55, 0x0e, 0x8b, 0xd0, 0x6e, 0x33, 0x07, 0x6c, 0x34, 0xb7, 0x63, 0xc8, 0x1d, 0xa6,
-	0x1f, 0xc6, 0xb1, 0x4b, 0xd4, 0x26, 0x69, 0x35, 0xfa, 0xad, 0xb0, 0xaf, 0xb7, 0x99, 0xb9, 0xb2,
-	0x5a, 0x62, 0xe2, 0x75, 0xe6, 0x7c, 0x9d, 0xd6, 0x77, 0xd4, 0xfe, 0xef, 0x77, 0xd0, 0x06, 0xf3,
-	0x24, 0xf1, 0x12, 0xa3, 0x3c, 0x62, 0xfc, 0x1b, 0x5b, 0x5b, 0x6a, 0x64, 0x78, 0xb5, 0xdf, 0xe5,
-	0xf8, 0x8f, 0x19, 0xff, 0xac, 0x96, 0x98, 0xf8, 0x7d, 0xa6, 0x41, 0x86, 0x22, 0xfc, 0x7d, 0x2f,
-	0x49, 0xff, 0x84, 0x69, 0x70, 0x46, 0x63, 0xcc, 0xe2, 0x1b, 0x4c, 0x44, 0x66, 0x77, 0x46, 0xf2,
-	0x01, 0x13, 0x51, 0xbc, 0xc4, 0x28, 0x1f, 0x32, 0x11, 0x43, 0x68, 0xb7, 0x13, 0xcb, 0x83, 0x9e,
-	0x32, 0x11, 0xb3, 0x5a, 0x62, 0xe2, 0x8f, 0x98, 0x88, 0x09, 0x94, 0xa4, 0x30, 0xe8, 0x63, 0x26,
-	0xe2, 0x8c, 0xc6, 0x98, 0xc5, 0x37, 0xef, 0x6e, 0xf1, 0xef, 0x59, 0x92, 0x7f, 0x85, 0xc2, 0xff,
-	0xb6, 0xa8, 0xdb, 0x69, 0xa1, 0x0b, 0x2c, 0x06, 0xec, 0x7c, 0xf7, 0x71, 0xf0, 0x9f, 0x45, 0xdf,
-	0xdd, 0x69, 0x6f, 0xa2, 0x1c, 0x7f, 0xea, 0xf5, 0x59, 0xd8, 0x01, 0x58, 0xea, 0x75, 0x1a, 0x7b,
-	0x7b, 0x9f, 0xa2, 0xc2, 0xdd, 0xbf, 0x29, 0x40, 0x25, 0x51, 0xc1, 0x64, 0xa6, 0xde, 0xb7, 0xd9,
-	0x66, 0x5e, 0x5c, 0x35, 0xbe, 0xc0, 0xfc, 0x21, 0xdc, 0x08, 0x27, 0xee, 0x30, 0xef, 0x51, 0xd7,
-	0x33, 0x3d, 0x9f, 0xda, 0xba, 0xb8, 0xa8, 0x9c, 0x63, 0x5e, 0xc6, 0x12, 0x4a, 0x6a, 0xfb, 0xa6,
-	0x1e, 0x5f, 0x94, 0x46, 0x79, 0x7c, 0x05, 0x70, 0x23, 0xf8, 0x9a, 0xe8, 0xf3, 0x04, 0xbc, 0xc0,
-	0xfa, 0x0a, 0x37, 0x1c, 0x9b, 0x63, 0xef, 0x14, 0x15, 0xd9, 0xe4, 0x15, 0xdf, 0xf9, 0x74, 0x1c,
-	0x9f, 0x50, 0xcd, 0x38, 0x45, 0x4b, 0x2c, 0x82, 0x84, 0x95, 0x94, 0xcd, 0xe0, 0x66, 0xd4, 0xb7,
-	0xc7, 0x8e, 0xaf, 0xb5, 0x4e, 0x74, 0x4a, 0x0d, 0x1a, 0x14, 0x8e, 0xd0, 0x32, 0x7e, 0x07, 0x6e,
-	0xcf, 0x45, 0x3b, 0xd1, 0x69, 0x70, 0x37, 0xbb, 0xc4, 0x86, 0x14, 0xde, 0xc1, 0x0e, 0xa8, 0xcb,
-	0xcc, 0x20, 0xfb, 0xb6, 0xf8, 0x9b, 0x00, 0x6a, 0x88, 0x2b, 0x00, 0x41, 0x23, 0x30, 0x7c, 0xbe,
-	0x9d, 0xe8, 0x38, 0xfe, 0x33, 0x67, 0x6c, 0x1b, 0xa8, 0xc2, 0xac, 0x9f, 0x8c, 0xfb, 0x51, 0xcb,
-	0x0a, 0xbf, 0xe0, 0x1d, 0x5e, 0x25, 0x0b, 0xa1, 0x55, 0x36, 0xb2, 0xbe, 0xe3, 0xec, 0x6a, 0xf6,
-	0x29, 0x09, 0xea, 0xd5, 0x1e, 0xaa, 0x31, 0x26, 0x9c, 0x6f, 0x9f, 0xba, 0x43, 0xd3, 0xd6, 0xfc,
-	0x70, 0x30, 0x75, 0xa6, 0x9a, 0x68, 0x30, 0x4c, 0x35, 0x3c, 0xe2, 0xb6, 0x6d, 0x7e, 0xaf, 0x3e,
-	0x10, 0x45, 0x1b, 0x52, 0x74, 0x91, 0xa9, 0xb6, 0xcd, 0x6f, 0xa1, 0x6b, 0xbe, 0x79, 0x60, 0x89,
-	0xe4, 0x15, 0x61, 0x66, 0x8b, 0x50, 0x88, 0x86, 0xe7, 0x99, 0x03, 0x31, 0x94, 0x4b, 0x58, 0x81,
-	0x1b, 0x7d, 0x57, 0xb3, 0xbd, 0xa0, 0x46, 0xdf, 0x74, 0x1c, 0xd7, 0x60, 0x3d, 0x3b, 0xb1, 0xac,
-	0xab, 0xc9, 0xae, 0x4e, 0xf8, 0x17, 0xb8, 0x63, 0x0f, 0x5d, 0xbe, 0xbb, 0x0d, 0x90, 0xf8, 0x27,
-	0x07, 0x16, 0x39, 0xe2, 0x0f, 0xe8, 0x83, 0xff, 0xca, 0xba, 0x04, 0xf5, 0x18, 0xf6, 0xa9, 0xae,
-	0xbd, 0x7c, 0x18, 0xf8, 0x4a, 0x0c, 0x6c, 0x30, 0xf7, 0xf0, 0x50, 0xee, 0xee, 0x9f, 0x4b, 0x50,
-	0xdf, 0x9b, 0xf8, 0x9b, 0x84, 0x25, 0xc8, 0x1d, 0x3f, 0x40, 0x17, 0xf8, 0x2f, 0xa3, 0x64, 0xbf,
-	0x1b, 0x28, 0xc7, 0x7f, 0x1f, 0xa1, 0x3c, 0xff, 0x7d, 0x8c, 0x0a, 0xfc, 0xf7, 0x7d, 0x54, 0xe4,
-	0xbf, 0x4f, 0xd0, 0x12, 0xff, 0xfd, 0x06, 0x5a, 0xe6, 0xbf, 0x1f, 0xa0, 0x12, 0xff, 0xfd, 0x30,
-	0x58, 0x07, 0x8f, 0x1f, 0x3e, 0x40, 0x10, 0x3c, 0x3c, 0x44, 0x95, 0xe0, 0x61, 0x03, 0xad, 0x04,
-	0x0f, 0x8f, 0x50, 0x35, 0x78, 0x78, 0x8c, 0x6a, 0xc1, 0xc3, 0xfb, 0xa8, 0x7e, 0xf7, 0xdd, 0xe4,
-	0x97, 0xfe, 0xe2, 0xea, 0x54, 0x63, 0xbf, 0xdf, 0x55, 0x7b, 0x7b, 0x3b, 0xed, 0xbe, 0xf8, 0x4c,
-	0xb7, 0xdf, 0x6e, 0x6e, 0x7f, 0x8a, 0xa4, 0xbb, 0x0a, 0x94, 0xa3, 0x03, 0x0d, 0xd6, 0xd0, 0xec,
-	0xee, 0xee, 0x72, 0xa4, 0x32, 0x14, 0x1b, 0x9b, 0x5d, 0xd2, 0x47, 0xd2, 0xe6, 0xc6, 0xcf, 0xbe,
-	0xb8, 0x21, 0xfd, 0xf3, 0x17, 0x37, 0xa4, 0x7f, 0xfb, 0xe2, 0x86, 0x04, 0x8a, 0xe3, 0x0e, 0xee,
-	0x6b, 0x23, 0x4d, 0x3f, 0xa4, 0x61, 0xca, 0xa1, 0x3b, 0xc3, 0xa1, 0x63, 0xdf, 0xd7, 0xc2, 0xff,
-	0x4e, 0x7b, 0x91, 0xff, 0xdf, 0x00, 0x00, 0x00, 0xff, 0xff, 0xd0, 0xd4, 0x9d, 0x54, 0x4f, 0x4d,
-	0x00, 0x00,
+	// 5739 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xdc, 0x7c, 0xdd, 0x6f, 0x1c, 0x47,
+	0x72, 0xb8, 0x66, 0x3f, 0xc8, 0xdd, 0x5a, 0x2e, 0x39, 0x6a, 0x51, 0xd2, 0xe8, 0xc3, 0x12, 0x3d,
+	0xb2, 0x64, 0x5a, 0xb6, 0xf5, 0x93, 0x28, 0x59, 0x67, 0xcb, 0xbe, 0xdf, 0x79, 0xb9, 0x5c, 0x49,
+	0x1b, 0x52, 0xbb, 0xbc, 0xde, 0xa5, 0x1c, 0x3b, 0x77, 0x98, 0x1b, 0xce, 0xb4, 0x96, 0x03, 0xce,
+	0xce, 0xec, 0xcd, 0xcc, 0xd2, 0xa4, 0x81, 0x04, 0x41, 0x80, 0x20, 0x2f, 0x07, 0x04, 0x79, 0xca,
+	0x43, 0x80, 0x04, 0xf9, 0x13, 0xf2, 0x16, 0x20, 0x40, 0xf2, 0x14, 0xe4, 0x80, 0xe4, 0x21, 0x0f,
+	0x79, 0xc8, 0x3d, 0x5d, 0x60, 0xe4, 0xe3, 0xe1, 0x70, 0x41, 0xfe, 0x83, 0x04, 0xd5, 0xf3, 0xbd,
+	0x3b, 0x3b, 0x4b, 0xda, 0x17, 0x9c, 0xe3, 0xa7, 0x9d, 0xa9, 0xae, 0xaa, 0xae, 0xae, 0xaa, 0xae,
+	0xae, 0xae, 0xee, 0x59, 0x58, 0xd9, 0x1d, 0x9b, 0xae, 0xea, 0x34, 0x46, 0xc6, 0xbd, 0x91, 0x63,
+	0x7b, 0x36, 0x59, 0x1a, 0x71, 0x80, 0xff, 0x26, 0x7f, 0x59, 0x80, 0x85, 0x9e, 0x76, 0xc0, 0x86,
+	0x2a, 0x21, 0x50, 0xb2, 0xd4, 0x21, 0x93, 0x84, 0xb5, 0xc2, 0x7a, 0x95, 0xf2, 0x67, 0x72, 0x13,
+	0x6a, 0x2e, 0x6f, 0x55, 0x74, 0xd5, 0x53, 0xa5, 0xe2, 0x5a, 0x61, 0x7d, 0x89, 0x82, 0x0f, 0xda,
+	0x52, 0x3d, 0x95, 0xbc, 0x0b, 0x25, 0xef, 0x64, 0xc4, 0xa4, 0xd2, 0x5a, 0x61, 0x7d, 0x79, 0xe3,
+	0xca, 0xbd, 0x24, 0xf3, 0x7b, 0x3e, 0xe3, 0x7b, 0xfd, 0x93, 0x11, 0xa3, 0x1c, 0x8d, 0x3c, 0x06,
+	0x18, 0x39, 0xf6, 0x88, 0x39, 0x9e, 0xc1, 0x5c, 0xa9, 0xbc, 0x56, 0x5c, 0xaf, 0x6d, 0x5c, 0x4a,
+	0x13, 0x6d, 0xb3, 0x93, 0x97, 0xaa, 0x39, 0x66, 0x34, 0x81, 0x29, 0xff, 0x95, 0x00, 0x25, 0x64,
+	0x43, 0x2a, 0x50, 0xea, 0xd8, 0x16, 0x13, 0xcf, 0x11, 0x80, 0x85, 0x9e, 0xe7, 0x18, 0xd6, 0x40,
+	0x14, 0x10, 0xfa, 0x1b, 0xae, 0x6d, 0x89, 0x05, 0xb2, 0x04, 0x95, 0x5d, 0x64, 0xb3, 0x3f, 0x7e,
+	0x25, 0x16, 0x11, 0xde, 0x38, 0x72, 0x6c, 0xb1, 0x84, 0x4f, 0x9b, 0xb6, 0x6d, 0x8a, 0x65, 0x7c,
+	0x6a, 0x5b, 0xde, 0xfb, 0xe2, 0x02, 0xa9, 0x42, 0xb9, 0x6d, 0x79, 0x0f, 0x1e, 0x8b, 0x8b, 0xc1,
+	0xe3, 0xc3, 0x0d, 0xb1, 0x12, 0x3c, 0x3e, 0x7e, 0x24, 0x56, 0xf1, 0xf1, 0xa9, 0x69, 0xab, 0x9e,
+	0x08, 0xd8, 0xdb, 0x96, 0x3d, 0xde, 0x37, 0x99, 0x58, 0x43, 0x0e, 0x5b, 0xaa, 0xc7, 0xc4, 0x25,
+	0x7c, 0xea, 0x1b, 0x43, 0x26, 0xd6, 0x49, 0x1d, 0xaa, 0xf8, 0xe4, 0x7a, 0xea, 0x70, 0x24, 0x2e,
+	0xa3, 0x18, 0xe1, 0x38, 0xc4, 0x15, 0xf9, 0x27, 0x02, 0xd4, 0x5f, 0x30, 0xd7, 0x55, 0x07, 0xac,
+	0xad, 0x73, 0xb5, 0x5d, 0x85, 0x8a, 0xc9, 0xf4, 0x01, 0x73, 0xda, 0x3a, 0xd7, 0x77, 0x89, 0x46,
+	0xef, 0x44, 0x82, 0x45, 0x66, 0x79, 0xce, 0x49, 0x5b, 0x97, 0x0a, 0xbc, 0x29, 0x7c, 0x25, 0x6b,
+	0x50, 0x1d, 0xa9, 0x8e, 0x67, 0x78, 0x86, 0x6d, 0x49, 0xc5, 0x35, 0x61, 0xbd, 0xfc, 0xa4, 0xf0,
+	0xee, 0x03, 0x1a, 0x03, 0xc9, 0x2d, 0xa8, 0xed, 0xab, 0x9e, 0x76, 0xa0, 0x18, 0x96, 0xce, 0x8e,
+	0xa5, 0x52, 0x84, 0x03, 0x1c, 0xdc, 0x46, 0xa8, 0xbc, 0x11, 0x0b, 0x47, 0x44, 0x28, 0x1e, 0xb2,
+	0x93, 0xc0, 0xe6, 0xf8, 0x48, 0x56, 0xa1, 0x7c, 0x84, 0x4d, 0xbc, 0xf3, 0x2a, 0xf5, 0x5f, 0xe4,
+	0xc7, 0xb0, 0xb4, 0xcd, 0x4e, 0x76, 0x6c, 0x6b, 0x70, 0x2a, 0xba, 0x52, 0x48, 0xb7, 0x01, 0x95,
+	0xb6, 0xe5, 0x51, 0xd5, 0x1a, 0x30, 0xc4, 0x70, 0x3d, 0xd5, 0xf1, 0x38, 0x55, 0x99, 0xfa, 0x2f,
+	0xc8, 0x89, 0x59, 0xfe, 0x50, 0xcb, 0x14, 0x1f, 0x65, 0x13, 0x96, 0x5b, 0x96, 0xe6, 0x9c, 0x8c,
+	0x70, 0x48, 0xdb, 0xec, 0xc4, 0x9d, 0xd7, 0xdb, 0x52, 0xd0, 0x1b, 0xd9, 0x80, 0xca, 0x90, 0x79,
+	0x6a, 0xe0, 0xab, 0x79, 0xce, 0x15, 0xe1, 0xc9, 0xbf, 0x5c, 0x84, 0x95, 0xc0, 0x38, 0x2f, 0x02,
+	0x18, 0xb9, 0x05, 0xf5, 0x91, 0x63, 0xeb, 0x63, 0x8d, 0x39, 0x4a, 0x62, 0x4e, 0x2c, 0x85, 0xc0,
+	0x4e, 0x38, 0x37, 0xd8, 0x8f, 0xc7, 0xcc, 0xd2, 0x98, 0x62, 0x84, 0xb6, 0x82, 0x10, 0xd4, 0xd6,
+	0xc9, 0xeb, 0xb0, 0x34, 0x1a, 0xef, 0x9b, 0x86, 0x7b, 0xa0, 0x78, 0xc6, 0x90, 0xf1, 0xd9, 0x53,
+	0xa2, 0xb5, 0x00, 0x86, 0xee, 0x32, 0x31, 0x1f, 0x4a, 0xa7, 0x9d, 0x0f, 0xe4, 0x4d, 0x58, 0x71,
+	0xd8, 0xc8, 0x34, 0x34, 0xd5, 0x63, 0xba, 0xf2, 0xca, 0xb1, 0x87, 0x52, 0x79, 0x4d, 0x58, 0xaf,
+	0xd2, 0xe5, 0x18, 0xfc, 0xd4, 0xb1, 0x87, 0x7c, 0x24, 0xa1, 0x77, 0x28, 0xa8, 0xc3, 0x05, 0x8e,
+	0xb6, 0x14, 0x01, 0xb7, 0xd9, 0x09, 0x0a, 0x1a, 0x91, 0x29, 0x9e, 0x2d, 0x2d, 0xae, 0x15, 0xd7,
+	0xab, 0xb4, 0x16, 0xc1, 0xfa, 0x36, 0x69, 0x41, 0x4d, 0xb3, 0x87, 0x23, 0x87, 0xb9, 0x2e, 0x3a,
+	0x5f, 0x65, 0x4d, 0x58, 0x5f, 0xde, 0x78, 0x2d, 0x2d, 0x69, 0x33, 0x46, 0xc0, 0xb9, 0xfa, 0xa4,
+	0xd4, 0xe9, 0x76, 0x5a, 0x34, 0x49, 0x47, 0xee, 0xc1, 0xf9, 0xb1, 0x15, 0x02, 0x98, 0xae, 0xb8,
+	0xc6, 0x17, 0x4c, 0xaa, 0xae, 0x09, 0xeb, 0xf5, 0x27, 0xc2, 0x7d, 0x2a, 0x26, 0xdb, 0x7a, 0xc6,
+	0x17, 0x8c, 0x3c, 0x82, 0x8b, 0xd6, 0x78, 0xa8, 0x0c, 0x7d, 0xfb, 0xb8, 0x8a, 0x61, 0x29, 0xdc,
+	0x91, 0xa5, 0x1a, 0xf7, 0x6c, 0xe1, 0x01, 0x25, 0xd6, 0x78, 0x18, 0x98, 0xcf, 0x6d, 0x5b, 0x9b,
+	0xd8, 0x48, 0xd6, 0x00, 0xd8, 0x11, 0xb3, 0x3c, 0x5f, 0xed, 0x4b, 0x6b, 0xc2, 0x7a, 0x09, 0xd9,
+	0x57, 0x39, 0x90, 0xeb, 0xbd, 0x05, 0x2b, 0x2c, 0x72, 0x31, 0xd4, 0x8b, 0x2b, 0xd5, 0xb9, 0xf2,
+	0xaf, 0xa7, 0x87, 0x94, 0xf6, 0x43, 0xba, 0xcc, 0x52, 0xef, 0x68, 0x86, 0x04, 0x1b, 0xd5, 0x1c,
+	0xd8, 0xd2, 0xb2, 0x6f, 0x86, 0x18, 0xdc, 0x30, 0x07, 0x36, 0x79, 0x0b, 0xc4, 0x04, 0xe2, 0x48,
+	0x75, 0xd4, 0xa1, 0xb4, 0xb2, 0x26, 0xac, 0x2f, 0xd1, 0x04, 0x83, 0x5d, 0x04, 0x93, 0xdb, 0xb0,
+	0x1c, 0x84, 0xdc, 0x23, 0xe6, 0x70, 0x65, 0x8b, 0x1c, 0xb1, 0xee, 0x43, 0x5f, 0xfa, 0x40, 0xf2,
+	0x31, 0x5c, 0x49, 0x19, 0x56, 0xd9, 0x7f, 0xfc, 0x48, 0x61, 0x96, 0x66, 0xeb, 0x4c, 0x97, 0xce,
+	0xaf, 0x09, 0xeb, 0x95, 0x27, 0xe5, 0x57, 0xaa, 0xe9, 0x32, 0x7a, 0x29, 0x69, 0xeb, 0xcd, 0xc7,
+	0x8f, 0x5a, 0x3e, 0x12, 0x5a, 0xdd, 0x76, 0x74, 0x86, 0x21, 0x94, 0x7b, 0x06, 0xe1, 0xdd, 0xd4,
+	0x42, 0x18, 0x3a, 0xc6, 0x1d, 0x58, 0xd1, 0x99, 0x69, 0x1c, 0x31, 0x47, 0x51, 0x03, 0x6d, 0x5e,
+	0x58, 0x13, 0xd6, 0x8b, 0xb4, 0x1e, 0x80, 0x1b, 0xbe, 0x3a, 0x6f, 0x42, 0x6d, 0xa8, 0x3a, 0x87,
+	0xcc, 0x51, 0xf8, 0x62, 0xb0, 0x8a, 0xc6, 0xa1, 0xe0, 0x83, 0x78, 0xd8, 0x7e, 0x1b, 0x44, 0xef,
+	0xd8, 0x32, 0x74, 0xc5, 0x64, 0xaa, 0xeb, 0x29, 0xfb, 0x86, 0xe7, 0x4a, 0x97, 0x42, 0xbb, 0x2c,
+	0xf3, 0xa6, 0x1d, 0x6c, 0xd9, 0x34, 0x3c, 0x97, 0xbc, 0x05, 0x2b, 0x3e, 0xf2, 0xd0, 0x0e, 0x71,
+	0x2f, 0x87, 0xb8, 0x75, 0xde, 0xf2, 0xc2, 0x0e, 0x50, 0x1f, 0xc0, 0x85, 0x03, 0x63, 0x70, 0xc0,
+	0x5c, 0x4f, 0x49, 0xce, 0x45, 0x29, 0x44, 0x3f, 0x1f, 0xb4, 0xf6, 0xa2, 0x59, 0x29, 0xff, 0xa2,
+	0x00, 0x17, 0x7b, 0x86, 0x35, 0x30, 0xd9, 0xe4, 0xac, 0x4f, 0x4f, 0x46, 0xe1, 0xd4, 0x93, 0x71,
+	0x6a, 0x8e, 0x15, 0xb2, 0xe7, 0xd8, 0x48, 0x3d, 0x31, 0x6d, 0x35, 0x70, 0xfa, 0x22, 0x8f, 0x77,
+	0xb5, 0x00, 0xc6, 0x9d, 0xfd, 0x2e, 0xd4, 0xd1, 0xfd, 0x55, 0x0d, 0xe7, 0xb4, 0x3d, 0xf6, 0xa4,
+	0x52, 0xd2, 0x8c, 0x4b, 0x51, 0x5b, 0x77, 0xec, 0x4d, 0xb8, 0x78, 0x39, 0xc3, 0xc5, 0x73, 0x1d,
+	0x64, 0xe1, 0xab, 0x38, 0xc8, 0xe2, 0xb4, 0x83, 0x4c, 0xc4, 0x40, 0x0c, 0x0b, 0xa9, 0x18, 0x28,
+	0xff, 0x7b, 0x11, 0x96, 0x9b, 0xf6, 0x70, 0xa8, 0x5a, 0x7a, 0xd3, 0xb6, 0x2c, 0xa6, 0x79, 0xe8,
+	0xe0, 0x9a, 0x69, 0xa0, 0xec, 0xa1, 0x83, 0xfb, 0xd1, 0xb5, 0xee, 0x43, 0x43, 0x07, 0xff, 0x00,
+	0x6a, 0xea, 0xd8, 0x3b, 0x50, 0x86, 0xcc, 0x3b, 0xb0, 0x75, 0xae, 0xd3, 0xe5, 0x0d, 0x29, 0x6d,
+	0x8e, 0xc6, 0xd8, 0x3b, 0x78, 0xc1, 0xdb, 0x29, 0xa8, 0xd1, 0x33, 0x59, 0x07, 0x31, 0x41, 0xea,
+	0x47, 0xf0, 0x20, 0x3c, 0xc6, 0x58, 0x3c, 0x86, 0x5f, 0x83, 0x2a, 0xc7, 0x0c, 0x56, 0x0c, 0x1c,
+	0x5f, 0x05, 0x01, 0x7c, 0x91, 0x7e, 0x07, 0x44, 0xde, 0x8d, 0x66, 0x9b, 0x91, 0xa8, 0xfe, 0x8a,
+	0x2a, 0xdc, 0xa7, 0x2b, 0x61, 0x53, 0x28, 0xef, 0xbb, 0x70, 0x61, 0xe4, 0xd8, 0xc7, 0x27, 0x8a,
+	0x67, 0x2b, 0xfb, 0x8e, 0x8d, 0x93, 0x61, 0xec, 0x98, 0x41, 0xbc, 0x15, 0x79, 0x53, 0xdf, 0xde,
+	0xe4, 0x0d, 0x7b, 0x8e, 0x49, 0xde, 0x05, 0x62, 0x3b, 0xc6, 0xc0, 0xb0, 0x54, 0x53, 0x19, 0x39,
+	0x86, 0xa5, 0x19, 0x23, 0xd5, 0xe4, 0x2a, 0xae, 0xd2, 0xf3, 0x61, 0xcb, 0x6e, 0xd8, 0x40, 0xde,
+	0x49, 0xa0, 0xc7, 0x12, 0x57, 0x7c, 0xe6, 0x61, 0x4b, 0x23, 0x94, 0xfc, 0x3e, 0xac, 0xa6, 0xb1,
+	0x03, 0x25, 0x56, 0x39, 0x3e, 0x49, 0xe2, 0x07, 0x2a, 0xfb, 0x1e, 0xd4, 0x5f, 0x31, 0xd5, 0x1b,
+	0x3b, 0x4c, 0x79, 0x65, 0xaa, 0x03, 0x57, 0x82, 0x35, 0x61, 0xbd, 0xb6, 0x71, 0x35, 0xad, 0xef,
+	0xa7, 0x3e, 0xca, 0x53, 0xc4, 0xa0, 0x4b, 0xaf, 0x12, 0x6f, 0x72, 0x1b, 0x96, 0x92, 0xad, 0xe4,
+	0x03, 0xb8, 0xe8, 0x8e, 0x47, 0x23, 0xdb, 0xf1, 0x5c, 0x5f, 0x04, 0x87, 0xbd, 0x72, 0x98, 0x7b,
+	0x20, 0x09, 0x49, 0xd7, 0xbb, 0x10, 0xe2, 0xa0, 0x28, 0xd4, 0xc7, 0x90, 0xff, 0x48, 0x00, 0x31,
+	0xed, 0x33, 0x4c, 0xe7, 0x61, 0x91, 0x39, 0x18, 0x89, 0x26, 0xbc, 0xc6, 0x87, 0x86, 0x56, 0xc8,
+	0xb2, 0x59, 0x61, 0xa6, 0xcd, 0xd6, 0x41, 0x1c, 0xaa, 0xc7, 0xe1, 0xf2, 0x12, 0x4e, 0x4c, 0x0c,
+	0x5e, 0xcb, 0x43, 0xf5, 0x38, 0x88, 0x0f, 0x38, 0x37, 0xe5, 0x3f, 0x15, 0xe0, 0x42, 0x20, 0x93,
+	0x2f, 0xaa, 0x3b, 0xb2, 0x2d, 0x97, 0x65, 0x3a, 0xb3, 0x30, 0xed, 0xcc, 0x1b, 0x50, 0x71, 0x02,
+	0x12, 0x2e, 0xce, 0x54, 0x60, 0x09, 0x4d, 0x47, 0x23, 0xbc, 0xcc, 0xa1, 0x14, 0x67, 0x0d, 0x45,
+	0xfe, 0x73, 0x01, 0x56, 0x13, 0x02, 0x36, 0x0f, 0x54, 0xd3, 0x64, 0x98, 0x75, 0x65, 0x29, 0x4e,
+	0x98, 0x56, 0xdc, 0x23, 0xa8, 0x6a, 0x21, 0xcd, 0x1c, 0x11, 0x63, 0xc4, 0x33, 0xca, 0xf8, 0x7d,
+	0xa8, 0x44, 0x2e, 0x9a, 0x35, 0x47, 0x85, 0xf9, 0x73, 0xb4, 0x90, 0x9e, 0xa3, 0xf2, 0xdf, 0x0b,
+	0x50, 0xdf, 0x66, 0x27, 0xbd, 0x03, 0xd5, 0x61, 0x3a, 0x46, 0x72, 0xd2, 0x80, 0xfa, 0x61, 0x04,
+	0xb0, 0x75, 0x3f, 0x77, 0x5b, 0xde, 0xb8, 0x36, 0x15, 0xc8, 0x63, 0x14, 0x9a, 0xa6, 0xc0, 0x85,
+	0xe0, 0x40, 0x75, 0x0f, 0x78, 0xd6, 0xea, 0x66, 0x27, 0x92, 0x61, 0x52, 0x4b, 0x13, 0x98, 0xe4,
+	0x7b, 0x70, 0x59, 0x35, 0x4d, 0xfb, 0xf3, 0xee, 0xd8, 0xeb, 0xbe, 0xea, 0x62, 0x98, 0xdc, 0xf2,
+	0x97, 0xc9, 0x93, 0x74, 0x28, 0x9f, 0x85, 0x25, 0xff, 0x62, 0x31, 0xf2, 0xfc, 0xde, 0x78, 0xdf,
+	0xd5, 0x1c, 0x63, 0x9f, 0xa7, 0xcd, 0x9e, 0x3d, 0x32, 0xb4, 0xc0, 0xe1, 0xfd, 0x17, 0x22, 0xc3,
+	0x92, 0xeb, 0xa3, 0xf0, 0xdc, 0x21, 0xc8, 0xd6, 0x53, 0x30, 0xf2, 0x31, 0x2c, 0xba, 0xe3, 0x7d,
+	0x5c, 0x80, 0xf9, 0x72, 0xb3, 0xbc, 0x71, 0x67, 0x2a, 0x61, 0x4b, 0x75, 0x75, 0xaf, 0xe7, 0x63,
+	0xd3, 0x90, 0x0c, 0xe3, 0xbb, 0x66, 0x5b, 0xee, 0x78, 0xc8, 0x1c, 0x8c, 0xef, 0x25, 0x3f, 0xc7,
+	0x0d, 0x41, 0x6d, 0x9d, 0xbc, 0x06, 0xe0, 0x60, 0xb4, 0x77, 0x3d, 0x6c, 0x2f, 0xf3, 0xf6, 0x6a,
+	0x00, 0x69, 0xeb, 0xb8, 0x34, 0x46, 0xf4, 0xdc, 0xc4, 0x41, 0xfa, 0x19, 0x02, 0xb9, 0x81, 0x6f,
+	0xc3, 0xf2, 0xc8, 0x31, 0x6c, 0xc7, 0xf0, 0x4e, 0x14, 0x93, 0x1d, 0x31, 0x3f, 0x0c, 0x96, 0x69,
+	0x3d, 0x84, 0xee, 0x20, 0x90, 0xdc, 0x80, 0x45, 0x7d, 0xec, 0xa8, 0xfb, 0x26, 0xe3, 0x71, 0xaf,
+	0xf2, 0xa4, 0xe4, 0x39, 0x63, 0x46, 0x43, 0x20, 0x69, 0x81, 0xc8, 0x77, 0x14, 0xd1, 0x74, 0x36,
+	0xfc, 0x80, 0x57, 0x9b, 0xb4, 0x7d, 0x6a, 0x2b, 0x46, 0x97, 0x39, 0x51, 0x04, 0x4b, 0xed, 0x21,
+	0xe0, 0x74, 0x7b, 0x08, 0x1c, 0x81, 0xc3, 0x54, 0x5d, 0x89, 0x96, 0x68, 0x9e, 0x9f, 0x56, 0x68,
+	0x1d, 0xa1, 0xcd, 0x10, 0x48, 0xde, 0x81, 0x05, 0x3f, 0x89, 0xe3, 0x39, 0x69, 0x6d, 0x63, 0x35,
+	0x6b, 0xbb, 0x4c, 0x03, 0x1c, 0xf2, 0x23, 0x58, 0x31, 0x2c, 0xc3, 0x33, 0x54, 0x73, 0xd7, 0x76,
+	0xfd, 0x3d, 0x5f, 0x9d, 0x2f, 0x82, 0xf7, 0xe6, 0x58, 0xb1, 0x9d, 0xa6, 0x7a, 0xb2, 0xb0, 0xa3,
+	0x7a, 0xcc, 0xf5, 0xe8, 0x24, 0x3b, 0xf2, 0x31, 0x5c, 0x8f, 0xf3, 0xfe, 0xa4, 0xe7, 0x28, 0xae,
+	0xa7, 0x7a, 0x8c, 0xe7, 0xb2, 0x15, 0x7a, 0x35, 0xc2, 0xe9, 0x25, 0x50, 0x7a, 0x88, 0x41, 0x1e,
+	0xc3, 0xea, 0x2b, 0xdb, 0xd1, 0x70, 0xd7, 0x30, 0x32, 0x34, 0x45, 0x73, 0x98, 0xca, 0x05, 0x5d,
+	0x49, 0x18, 0x88, 0x70, 0x8c, 0x3e, 0x22, 0x34, 0x83, 0x76, 0xd2, 0x85, 0x5b, 0x69, 0x5b, 0x39,
+	0xb6, 0x69, 0xee, 0xab, 0xda, 0xa1, 0x82, 0xd6, 0xf4, 0x45, 0x60, 0x9a, 0x24, 0x86, 0x79, 0xcd,
+	0xcd, 0xa4, 0x91, 0x68, 0x80, 0xbb, 0x15, 0xa0, 0xf6, 0x98, 0x96, 0x9e, 0xf5, 0xcc, 0x53, 0xa5,
+	0xf3, 0x59, 0x96, 0x4f, 0x45, 0x0a, 0x9a, 0xa6, 0x90, 0x37, 0x61, 0x31, 0xf0, 0x7f, 0xdc, 0xcd,
+	0xb7, 0x8e, 0x35, 0x73, 0xec, 0x1a, 0x47, 0x61, 0xa9, 0x81, 0xe3, 0x89, 0x02, 0xee, 0xec, 0x9f,
+	0xaa, 0x86, 0x69, 0x1f, 0x31, 0x47, 0x2c, 0x90, 0x65, 0x80, 0x6d, 0x76, 0xa2, 0x04, 0xad, 0x45,
+	0xf9, 0x6d, 0x58, 0x99, 0xd0, 0x3e, 0x12, 0xfb, 0xfa, 0x17, 0xcf, 0x21, 0x71, 0x4b, 0x75, 0x4c,
+	0x03, 0xdf, 0x04, 0xf9, 0xdf, 0x04, 0xb8, 0x19, 0x18, 0x6f, 0x37, 0xcc, 0xc0, 0x98, 0xce, 0x15,
+	0x15, 0xe5, 0xa4, 0xd9, 0x93, 0x3f, 0x3d, 0xeb, 0x0a, 0x93, 0xb3, 0x2e, 0x3b, 0xb7, 0x28, 0x9e,
+	0x2d, 0xb7, 0x28, 0x9d, 0x31, 0xb7, 0x28, 0xcf, 0xca, 0x2d, 0xe4, 0xbf, 0x2c, 0xc0, 0x9b, 0x73,
+	0xc6, 0x19, 0xad, 0xa7, 0x37, 0x00, 0xa2, 0x6c, 0xd4, 0xe5, 0x0b, 0x42, 0x9d, 0x26, 0x20, 0xf3,
+	0x46, 0xfe, 0x83, 0xc4, 0x3a, 0x5b, 0xe4, 0x93, 0xe5, 0xe3, 0xcc, 0xc9, 0x32, 0x4f, 0x8e, 0x7b,
+	0x3b, 0xb6, 0x7d, 0x38, 0x1e, 0xf1, 0x60, 0x18, 0xaf, 0xc8, 0xff, 0x0f, 0xca, 0xcc, 0x71, 0x6c,
+	0x87, 0xeb, 0x66, 0xba, 0xda, 0xc5, 0xd7, 0xd3, 0x16, 0x22, 0x50, 0x1f, 0x0f, 0x4b, 0x39, 0x81,
+	0x83, 0x07, 0xea, 0x09, 0x5f, 0xe5, 0xdb, 0x00, 0x71, 0x17, 0xa4, 0x86, 0xae, 0xa7, 0x69, 0xcc,
+	0x75, 0x7d, 0x6f, 0x43, 0x0f, 0x43, 0x6f, 0x93, 0xff, 0xa6, 0x00, 0x24, 0x10, 0x39, 0x40, 0xe7,
+	0xf6, 0xff, 0x4a, 0x5e, 0xf1, 0x36, 0xd4, 0xd1, 0x5e, 0x18, 0x51, 0x55, 0xcf, 0x38, 0xf2, 0x15,
+	0x14, 0xad, 0x49, 0xe9, 0xb6, 0x19, 0x2e, 0x54, 0x3a, 0x9b, 0x0b, 0x95, 0xcf, 0xe8, 0x42, 0x0b,
+	0x33, 0xd3, 0xd3, 0xf7, 0x41, 0x52, 0xf5, 0x23, 0xe6, 0x78, 0x06, 0x56, 0x0d, 0x4c, 0xc3, 0xf5,
+	0x98, 0x15, 0x2e, 0x29, 0x7e, 0xce, 0x7c, 0x29, 0x6e, 0xdf, 0x09, 0x9a, 0x71, 0x71, 0x91, 0x7f,
+	0x56, 0x84, 0xab, 0xd3, 0x1a, 0x8c, 0xfc, 0xed, 0x2e, 0x88, 0x7e, 0xb2, 0x8e, 0xd6, 0x33, 0x34,
+	0xb6, 0xe7, 0x98, 0x41, 0x1a, 0x32, 0x05, 0x27, 0xf7, 0xe1, 0xc2, 0x24, 0xac, 0x6f, 0xba, 0xc1,
+	0x6e, 0x2f, 0xab, 0x89, 0x74, 0xa7, 0xdc, 0xf1, 0x61, 0xa6, 0x3b, 0x66, 0x48, 0x96, 0xed, 0x81,
+	0x69, 0x13, 0x97, 0xe6, 0x9a, 0xb8, 0x9c, 0x63, 0xe2, 0xc8, 0x9b, 0x17, 0xce, 0xee, 0xcd, 0x8b,
+	0x29, 0x6f, 0xe6, 0x7b, 0x4d, 0x7f, 0xef, 0x73, 0xe0, 0xd8, 0xe3, 0xc1, 0x81, 0xe2, 0xfa, 0x6a,
+	0xe0, 0x3b, 0xa0, 0x4a, 0x7a, 0xaf, 0xc9, 0x37, 0x42, 0x3e, 0x5a, 0xac, 0x2c, 0xf9, 0x61, 0x6a,
+	0x3e, 0x2c, 0x41, 0x85, 0x32, 0xdd, 0x70, 0x98, 0x86, 0x51, 0xb3, 0x06, 0x8b, 0xc1, 0x3e, 0x40,
+	0x14, 0x12, 0xb3, 0xa3, 0x20, 0xff, 0x67, 0x11, 0x56, 0xc2, 0x09, 0x1d, 0x54, 0xe6, 0x66, 0x4c,
+	0x8d, 0x9b, 0x50, 0x8b, 0x0a, 0x7a, 0x71, 0xad, 0x2e, 0x04, 0x4d, 0xe5, 0x31, 0xc5, 0x8c, 0x3c,
+	0x26, 0x5d, 0x10, 0x2c, 0x05, 0x5b, 0xfc, 0x64, 0x41, 0xf0, 0x16, 0x54, 0x83, 0x62, 0x0e, 0xd3,
+	0xd3, 0x9a, 0x8f, 0xe1, 0xa9, 0xf4, 0x62, 0xe1, 0x94, 0xe9, 0x45, 0x9c, 0x37, 0x2c, 0x9e, 0x22,
+	0x6f, 0xb8, 0x0c, 0x65, 0x36, 0xb2, 0xb5, 0x03, 0xa9, 0x12, 0xae, 0x9e, 0xfe, 0x3b, 0x69, 0xc2,
+	0xb5, 0xb1, 0xcb, 0x1c, 0x65, 0xe4, 0xd8, 0x47, 0x86, 0xce, 0x74, 0x25, 0x3d, 0xa4, 0x6a, 0x62,
+	0xcd, 0x96, 0x10, 0x71, 0x37, 0xc0, 0xdb, 0x4d, 0x0e, 0xf2, 0x33, 0x58, 0x8d, 0xc8, 0x54, 0x1e,
+	0xb2, 0x94, 0x21, 0x66, 0xd9, 0xc0, 0x9d, 0x68, 0x2d, 0x2d, 0x59, 0x48, 0xd9, 0xe0, 0x88, 0x98,
+	0x5b, 0x3f, 0x09, 0x56, 0x52, 0x4a, 0x46, 0x53, 0x6d, 0x68, 0x25, 0x3f, 0x8f, 0xf0, 0xe5, 0xaf,
+	0xf9, 0xd5, 0x04, 0x0e, 0x6a, 0x21, 0x44, 0xfe, 0xdd, 0x02, 0xd4, 0xc2, 0x74, 0x87, 0x59, 0xfa,
+	0xa4, 0x59, 0x85, 0x29, 0xb3, 0xce, 0xad, 0xd1, 0xbe, 0x01, 0x4b, 0xc9, 0x02, 0x63, 0xb8, 0x79,
+	0x79, 0x40, 0x6b, 0x89, 0xba, 0x62, 0x66, 0xf9, 0xaa, 0x74, 0x86, 0xf2, 0x55, 0xf9, 0x6c, 0xe5,
+	0xab, 0x85, 0x9c, 0xf2, 0xd5, 0xdf, 0x0a, 0x40, 0x12, 0x2a, 0xa0, 0x4c, 0x63, 0xc6, 0xc8, 0xfb,
+	0x15, 0x68, 0xe2, 0x09, 0x40, 0x22, 0x71, 0x2e, 0xce, 0x4f, 0x9c, 0xab, 0xc3, 0xf0, 0x75, 0xd6,
+	0x38, 0x4a, 0x39, 0xe3, 0xf8, 0xb3, 0x78, 0x93, 0x8f, 0xe3, 0xe0, 0x81, 0xe6, 0x57, 0x30, 0x8a,
+	0x28, 0xa8, 0x15, 0xd7, 0x0a, 0x67, 0x0d, 0x6a, 0x25, 0x1e, 0x31, 0xa2, 0x25, 0xfa, 0x4f, 0x84,
+	0xa8, 0x74, 0x15, 0x0c, 0x7c, 0x72, 0x3b, 0x24, 0x4c, 0x6d, 0x87, 0xd2, 0x4a, 0x44, 0xf1, 0x4e,
+	0xaf, 0xc4, 0x77, 0x40, 0x74, 0x58, 0x50, 0x57, 0x3d, 0x51, 0x34, 0x7b, 0x6c, 0x79, 0x52, 0x31,
+	0x2c, 0x8d, 0xaf, 0xc4, 0x4d, 0x4d, 0x6c, 0x91, 0xff, 0xa1, 0x04, 0x10, 0xee, 0xf7, 0xb5, 0xc3,
+	0xf9, 0x92, 0x7d, 0x08, 0x15, 0x4c, 0xaf, 0x79, 0x7d, 0xb6, 0xb0, 0x56, 0x98, 0x9e, 0xab, 0x31,
+	0xb3, 0x7b, 0x0d, 0xed, 0xd0, 0xdf, 0x06, 0xaa, 0xfe, 0xc3, 0x94, 0x6f, 0x14, 0xcf, 0x30, 0xac,
+	0x1e, 0x88, 0x47, 0xaa, 0x69, 0xe8, 0x7e, 0x56, 0x9f, 0xcc, 0x9f, 0xd6, 0x67, 0x0a, 0xf0, 0x32,
+	0x22, 0xf0, 0x6d, 0xb5, 0x72, 0x94, 0x06, 0xa0, 0x40, 0x53, 0xe7, 0x88, 0x57, 0xa7, 0xe2, 0x68,
+	0x74, 0x5c, 0x95, 0x2a, 0xd7, 0x66, 0x4d, 0xe6, 0x85, 0x33, 0x4c, 0xe6, 0xc5, 0xec, 0xc9, 0x2c,
+	0xbf, 0x05, 0x8b, 0x81, 0xe2, 0x70, 0x5b, 0xd0, 0xb6, 0x74, 0xe3, 0xc8, 0xd0, 0xc7, 0xaa, 0x29,
+	0x9e, 0xc3, 0xf7, 0xe6, 0x78, 0x38, 0x36, 0xf9, 0xc2, 0x2b, 0x0a, 0xf2, 0x1f, 0x0a, 0xb0, 0x32,
+	0x31, 0x46, 0x72, 0x03, 0xae, 0xee, 0x4d, 0x1c, 0x7f, 0x34, 0x6d, 0xc7, 0x19, 0xf3, 0xdd, 0x96,
+	0x78, 0x8e, 0x5c, 0x02, 0xb2, 0xc5, 0x12, 0x67, 0x29, 0x9c, 0x4a, 0x14, 0xc8, 0x2a, 0x88, 0xcd,
+	0x03, 0xa6, 0x1d, 0xba, 0xe3, 0xe1, 0x0b, 0xc3, 0x1d, 0xe2, 0x01, 0x88, 0x58, 0x20, 0x57, 0xe0,
+	0x22, 0x3f, 0x0b, 0xd9, 0x62, 0x3d, 0xe6, 0x18, 0xaa, 0x69, 0x7c, 0xc1, 0x7c, 0x82, 0x22, 0xb9,
+	0x00, 0x2b, 0x5b, 0x2c, 0x3c, 0x73, 0xf0, 0x81, 0x25, 0xf9, 0x9f, 0xe3, 0xb0, 0xd2, 0xd0, 0x0e,
+	0xa3, 0xf4, 0x68, 0xae, 0x5b, 0x65, 0x29, 0xb3, 0x70, 0x06, 0x65, 0x16, 0x67, 0x44, 0xc6, 0x5f,
+	0x61, 0xaa, 0xbd, 0x0f, 0xd7, 0xa2, 0x91, 0xa1, 0xfe, 0x9b, 0x81, 0xf4, 0xcd, 0x03, 0x7e, 0x2a,
+	0x39, 0x77, 0x88, 0x32, 0x54, 0x0d, 0x57, 0x51, 0x39, 0xad, 0x54, 0x48, 0x2e, 0xeb, 0x15, 0xc3,
+	0xf5, 0x59, 0xca, 0x2f, 0xa3, 0x75, 0xe9, 0xa9, 0x69, 0x7f, 0x3e, 0x9f, 0xe7, 0x1d, 0x58, 0x0e,
+	0xc4, 0xdb, 0x65, 0xce, 0xd0, 0x57, 0x5a, 0x61, 0xbd, 0x4e, 0x27, 0xa0, 0x72, 0x3f, 0xb2, 0xca,
+	0x9e, 0xe5, 0x46, 0x15, 0xa1, 0xb9, 0xec, 0xf3, 0x77, 0x02, 0x78, 0x9a, 0x1e, 0x2f, 0xa3, 0xec,
+	0xf0, 0xeb, 0xf2, 0xfb, 0x5a, 0x4b, 0xc7, 0x7d, 0x58, 0x0d, 0x69, 0x53, 0x87, 0xa5, 0x7c, 0xed,
+	0xa0, 0x24, 0xd4, 0x47, 0x7c, 0x66, 0x2a, 0x7f, 0x08, 0x52, 0x20, 0x3c, 0x65, 0xaa, 0x76, 0xc0,
+	0xf4, 0x96, 0xa5, 0x77, 0x5f, 0xf5, 0xc3, 0x3c, 0x2f, 0x77, 0x24, 0xf2, 0xcb, 0xa8, 0x4a, 0xda,
+	0x34, 0x6d, 0x97, 0x45, 0x69, 0xe3, 0xdc, 0x95, 0x67, 0x8e, 0x4a, 0x27, 0xf8, 0x86, 0x3e, 0xf6,
+	0xb5, 0x4d, 0xf5, 0x07, 0x02, 0xdc, 0x89, 0x46, 0x1b, 0xac, 0x00, 0x7b, 0x96, 0xaa, 0x1d, 0x5a,
+	0xf6, 0xe7, 0xfc, 0xc6, 0x80, 0x1e, 0x25, 0x29, 0x73, 0xbb, 0xfa, 0x08, 0x6a, 0xb1, 0x99, 0xd0,
+	0xe3, 0xe6, 0x86, 0x71, 0x88, 0xec, 0xe4, 0xca, 0x3f, 0x8c, 0x56, 0xc3, 0x60, 0xab, 0x3a, 0x21,
+	0xba, 0x30, 0xe9, 0x15, 0x71, 0xd6, 0x5a, 0x98, 0x9f, 0xb5, 0xca, 0xff, 0x2d, 0xc0, 0xa5, 0x89,
+	0x5c, 0xfe, 0x94, 0xfd, 0x4c, 0xe5, 0xe6, 0x85, 0x8c, 0xc3, 0xfa, 0x77, 0x40, 0x34, 0xd5, 0x89,
+	0xf4, 0x04, 0x1d, 0xb5, 0xc8, 0x6f, 0x47, 0x2c, 0x9b, 0x6a, 0x32, 0x39, 0xc9, 0x38, 0x83, 0x2d,
+	0x65, 0x9d, 0xc1, 0x4e, 0xe4, 0xab, 0xe5, 0xc9, 0x7c, 0x95, 0xbc, 0x0d, 0xcb, 0xa1, 0x14, 0x8a,
+	0xc3, 0x54, 0xfd, 0x44, 0x5a, 0x48, 0x24, 0xd9, 0x91, 0xd8, 0x14, 0x9b, 0xe4, 0x63, 0x58, 0x0a,
+	0x14, 0xe0, 0x2f, 0x08, 0x73, 0x86, 0x1d, 0x45, 0xc8, 0xc2, 0xd9, 0x33, 0x9d, 0x62, 0x3a, 0xd3,
+	0xa9, 0x47, 0xe1, 0x60, 0xd7, 0xb0, 0x06, 0xc9, 0x57, 0xdb, 0x1a, 0x24, 0x5d, 0x3b, 0xf0, 0x25,
+	0xac, 0xfd, 0xcd, 0x35, 0xcb, 0xbc, 0xd2, 0xb1, 0xfc, 0x5f, 0x25, 0xb8, 0x9e, 0xc5, 0x98, 0x66,
+	0x6f, 0x76, 0xa7, 0x3a, 0x78, 0x1f, 0x80, 0x0f, 0x4c, 0xc1, 0xd3, 0xca, 0xe0, 0x7c, 0x30, 0x47,
+	0x0b, 0x55, 0x8e, 0xdc, 0xc4, 0x7d, 0xc6, 0x2d, 0xa8, 0xfb, 0x94, 0xb1, 0x3e, 0xf8, 0x6e, 0x8e,
+	0x03, 0xc3, 0x5c, 0xef, 0x06, 0xc0, 0xd0, 0x1d, 0x50, 0xd5, 0x63, 0xdd, 0xe0, 0x28, 0x56, 0xa0,
+	0x09, 0x08, 0x56, 0x0e, 0x86, 0xee, 0x20, 0xd8, 0xc9, 0x8e, 0xc6, 0x1e, 0x62, 0x95, 0x39, 0xd6,
+	0x14, 0x3c, 0xc0, 0x45, 0xca, 0x68, 0x12, 0x4b, 0x0b, 0x11, 0x6e, 0x0a, 0x8e, 0x85, 0xfd, 0x64,
+	0x75, 0x3c, 0xd8, 0x6a, 0xa7, 0x60, 0xc8, 0x4f, 0x3d, 0x52, 0x0d, 0x13, 0xeb, 0xde, 0xe1, 0x02,
+	0xe2, 0x9f, 0xbd, 0x4e, 0xc1, 0xc9, 0x3a, 0xac, 0x8c, 0x31, 0x60, 0xc4, 0x91, 0x82, 0xef, 0xf4,
+	0x4a, 0x74, 0x12, 0x4c, 0x36, 0xe1, 0xfa, 0xbe, 0x69, 0x23, 0x28, 0xb4, 0x47, 0xd7, 0xda, 0x0b,
+	0x70, 0xdc, 0xe0, 0x48, 0xb0, 0x42, 0x73, 0x71, 0xd0, 0xc9, 0x54, 0x5d, 0x77, 0x98, 0xeb, 0xf2,
+	0xed, 0x5b, 0x95, 0x86, 0xaf, 0xb8, 0xe4, 0x69, 0xe1, 0x69, 0x5e, 0xcf, 0xb0, 0x34, 0xff, 0x62,
+	0x46, 0x95, 0x4e, 0x40, 0xf1, 0x1a, 0x1a, 0x4f, 0x52, 0xeb, 0xbc, 0x95, 0x3f, 0x23, 0x6d, 0xa0,
+	0xa7, 0xd6, 0xf1, 0xc8, 0x70, 0x98, 0xce, 0x4b, 0xd3, 0x02, 0x9d, 0x80, 0x06, 0x36, 0xdb, 0x54,
+	0xb5, 0x43, 0xd3, 0x1e, 0xf0, 0x22, 0x74, 0x89, 0x26, 0x20, 0xf2, 0xa7, 0x70, 0x39, 0xf0, 0xb8,
+	0x67, 0xcc, 0xdb, 0x51, 0xdd, 0x44, 0xd9, 0xff, 0xeb, 0x06, 0xea, 0xdf, 0x8f, 0x8b, 0xb9, 0x93,
+	0xbc, 0x23, 0x87, 0x6e, 0xc2, 0x0a, 0x0f, 0x42, 0x89, 0xc5, 0x52, 0x98, 0xbf, 0x45, 0xa8, 0x9b,
+	0x29, 0x41, 0xe7, 0xc8, 0xf1, 0x73, 0x21, 0x4a, 0x77, 0x9e, 0x31, 0x8f, 0xaf, 0x8a, 0x6e, 0xf7,
+	0x15, 0x7a, 0x8d, 0x3b, 0x52, 0xb5, 0xb9, 0x93, 0xea, 0x3a, 0x54, 0xad, 0x10, 0x37, 0x08, 0xa4,
+	0x31, 0x80, 0x74, 0xa0, 0xc4, 0x37, 0xfb, 0xc5, 0x9c, 0x73, 0x88, 0xac, 0x5e, 0xef, 0xf1, 0xad,
+	0x3f, 0xec, 0xb6, 0x68, 0xaf, 0xdd, 0xeb, 0xb7, 0x3a, 0x7d, 0xca, 0xf9, 0xc8, 0x0f, 0xa1, 0x84,
+	0x2d, 0x98, 0x1f, 0xc7, 0x6d, 0xe2, 0x39, 0x42, 0x60, 0xb9, 0xd3, 0xed, 0x28, 0x09, 0x98, 0x40,
+	0x16, 0xa1, 0xd8, 0xd8, 0xd9, 0x11, 0x0b, 0xf2, 0x0f, 0xe0, 0x56, 0x4e, 0x57, 0xa7, 0x8d, 0x1e,
+	0x97, 0x60, 0x81, 0x07, 0x6a, 0x7f, 0x1d, 0xac, 0xd2, 0xe0, 0x4d, 0xb6, 0xa2, 0x6d, 0xe9, 0x33,
+	0xe6, 0x05, 0x37, 0x23, 0xe7, 0xb0, 0x8a, 0x4a, 0x4e, 0x85, 0x64, 0xc9, 0x69, 0x7a, 0x0d, 0x29,
+	0x66, 0xac, 0x21, 0xf2, 0x2f, 0x05, 0x90, 0x26, 0x3b, 0xfc, 0x86, 0x44, 0xc0, 0x78, 0x01, 0x2f,
+	0x9d, 0xa2, 0xec, 0x34, 0x3d, 0xde, 0x72, 0xd6, 0x78, 0x7f, 0x3b, 0x39, 0xdc, 0xae, 0xc3, 0x4f,
+	0x84, 0xd8, 0xd7, 0xd1, 0x73, 0x2c, 0x65, 0x71, 0xad, 0x30, 0x4f, 0x4a, 0xf9, 0xef, 0x04, 0x58,
+	0x9b, 0xd5, 0xff, 0x37, 0x44, 0xed, 0xa7, 0x4b, 0x3e, 0xe4, 0x1f, 0x43, 0x3d, 0x18, 0x48, 0x87,
+	0x7d, 0xde, 0x3f, 0xb6, 0xe6, 0x49, 0xed, 0x6f, 0xbe, 0x14, 0xcf, 0x33, 0xf1, 0x68, 0xcd, 0xb6,
+	0xf4, 0xc4, 0x46, 0x0d, 0x37, 0x5f, 0x7d, 0xcf, 0xec, 0xf9, 0x70, 0x72, 0x09, 0xca, 0x9e, 0x16,
+	0x66, 0x48, 0x1c, 0xa1, 0xe4, 0x69, 0x6d, 0x5d, 0xfe, 0x99, 0x00, 0x17, 0x53, 0x7d, 0x9e, 0x56,
+	0x63, 0xff, 0x07, 0x76, 0x89, 0x7f, 0x11, 0xcf, 0xc3, 0x86, 0x1e, 0x9f, 0x0f, 0xf5, 0xed, 0x53,
+	0xa8, 0xf6, 0x7f, 0x6b, 0x78, 0xe9, 0xc3, 0xb0, 0x12, 0x8f, 0x53, 0x09, 0x88, 0xfc, 0xaf, 0xb1,
+	0x33, 0x4f, 0xc9, 0xfc, 0x2d, 0x32, 0xcd, 0x73, 0x58, 0x4a, 0x9e, 0x3c, 0x7f, 0xf5, 0x0b, 0x11,
+	0xf2, 0x3f, 0xc5, 0x8b, 0x63, 0x43, 0xd7, 0x93, 0x4c, 0x7f, 0xad, 0x76, 0xfe, 0xff, 0x13, 0xa2,
+	0x97, 0xb2, 0xea, 0x59, 0x49, 0x69, 0x27, 0x86, 0xf5, 0x1f, 0x02, 0xdc, 0xca, 0x19, 0xd6, 0xb7,
+	0xc8, 0x15, 0xfe, 0x5a, 0x88, 0xa2, 0x5e, 0xcb, 0xd2, 0x7f, 0x8d, 0x26, 0x7b, 0x0c, 0x80, 0xd1,
+	0x54, 0xd5, 0x02, 0x83, 0xe1, 0xc0, 0x2e, 0xa7, 0x07, 0xd6, 0x3f, 0xb6, 0x1a, 0xbc, 0x99, 0x56,
+	0xbd, 0xf0, 0x31, 0x19, 0x42, 0xfd, 0x01, 0x7c, 0x8b, 0x8c, 0xf3, 0xf3, 0x38, 0x84, 0xfa, 0x63,
+	0xeb, 0x5a, 0x51, 0x4c, 0xfa, 0x75, 0x0d, 0x2f, 0x8a, 0x15, 0xfe, 0x81, 0x9d, 0xff, 0x32, 0x61,
+	0xbd, 0xf2, 0xa9, 0xad, 0x97, 0x08, 0xb8, 0x53, 0x23, 0xfc, 0x16, 0x19, 0xf2, 0x27, 0x05, 0xb8,
+	0x36, 0x31, 0xcc, 0x54, 0x00, 0xfe, 0xc6, 0x84, 0x49, 0xe1, 0x2c, 0x61, 0xf2, 0x2b, 0x5b, 0x3d,
+	0x11, 0x5e, 0xb3, 0xd4, 0xf1, 0x2d, 0x32, 0xfc, 0x4f, 0xd7, 0xa1, 0xb6, 0xa9, 0xba, 0x2c, 0x18,
+	0x2d, 0xd9, 0x08, 0xf6, 0xe2, 0xfe, 0x15, 0xca, 0x1b, 0x69, 0xce, 0x09, 0xc4, 0xf4, 0x27, 0x5e,
+	0x8b, 0xc1, 0x8e, 0x3e, 0xa8, 0xfb, 0x5d, 0xcf, 0xdc, 0x26, 0x06, 0x27, 0xfa, 0x34, 0x44, 0x26,
+	0x1f, 0x41, 0x35, 0x78, 0x64, 0x61, 0x0d, 0xf9, 0x46, 0x1e, 0x25, 0xd3, 0x69, 0x4c, 0x80, 0xd4,
+	0x51, 0x7d, 0x5c, 0x2a, 0xe5, 0x50, 0x47, 0xd7, 0xe4, 0x68, 0x4c, 0x40, 0x3e, 0x80, 0x4a, 0x58,
+	0x8b, 0xe3, 0x2a, 0xa9, 0x6d, 0xbc, 0x96, 0x49, 0x1c, 0x56, 0x26, 0x69, 0x84, 0x8e, 0x1f, 0xc0,
+	0xb9, 0xf8, 0xfd, 0xd2, 0x02, 0x27, 0xbb, 0x92, 0xdd, 0x27, 0x1e, 0xd4, 0x72, 0x34, 0xd2, 0x84,
+	0x25, 0xfc, 0x55, 0x1c, 0xff, 0xdc, 0x36, 0x38, 0xd0, 0x5f, 0x9b, 0x4d, 0xe6, 0xe3, 0xd1, 0x9a,
+	0x1b, 0xbf, 0x90, 0xef, 0x02, 0x70, 0x26, 0xbe, 0xd9, 0x2b, 0x79, 0xa3, 0x0d, 0x8f, 0x56, 0x69,
+	0xd5, 0x0d, 0x1f, 0xd1, 0x42, 0xa1, 0xfd, 0xab, 0x39, 0x16, 0x0a, 0x6f, 0xdb, 0x85, 0xc8, 0xe4,
+	0x2e, 0x14, 0x55, 0xed, 0x30, 0xb8, 0x19, 0x2e, 0xcd, 0x3a, 0xbc, 0xa3, 0x88, 0x84, 0x6a, 0x79,
+	0x65, 0xda, 0x9f, 0x4b, 0xb5, 0x1c, 0xb5, 0xe0, 0x51, 0x09, 0xe5, 0x68, 0x64, 0x13, 0x6a, 0xe3,
+	0xf8, 0x80, 0x43, 0x5a, 0xca, 0xd1, 0x4a, 0xe2, 0x20, 0x84, 0x26, 0x89, 0x70, 0x58, 0xae, 0x5f,
+	0x31, 0x96, 0xea, 0x39, 0xc3, 0x0a, 0xaa, 0xca, 0x34, 0x44, 0x26, 0xf7, 0xc3, 0xf9, 0xb3, 0x9c,
+	0x15, 0x4f, 0x92, 0x25, 0xd9, 0x70, 0x02, 0xb5, 0xf1, 0xd2, 0xb7, 0xed, 0xb2, 0xe8, 0x02, 0x05,
+	0x2f, 0x35, 0xd5, 0x36, 0xe4, 0x6c, 0x7f, 0x4d, 0x1e, 0x34, 0xe0, 0xc5, 0xf0, 0xc4, 0x6b, 0xcc,
+	0x2a, 0xac, 0x34, 0x49, 0xe2, 0x3c, 0x56, 0x61, 0xe1, 0x2d, 0x60, 0x15, 0xbe, 0x92, 0x2e, 0xbf,
+	0x8b, 0xcd, 0xd9, 0x2a, 0xa1, 0x22, 0xfc, 0x5b, 0x90, 0x6f, 0xe4, 0x3a, 0x73, 0xa8, 0x90, 0x95,
+	0x51, 0x1a, 0x80, 0x36, 0x1c, 0x19, 0xd6, 0x40, 0x22, 0x39, 0x36, 0xc4, 0x82, 0x31, 0xe5, 0x68,
+	0x1c, 0xdd, 0xb6, 0x06, 0xd2, 0x85, 0x3c, 0x74, 0x9b, 0xa3, 0xdb, 0xd6, 0x80, 0xfc, 0x0e, 0xdc,
+	0x74, 0xf2, 0x4f, 0x34, 0xf8, 0x77, 0x44, 0xb5, 0x8d, 0x47, 0x99, 0x9c, 0xe6, 0x9c, 0x86, 0xd0,
+	0x79, 0xcc, 0xc9, 0x6f, 0xc1, 0xf9, 0x68, 0x2b, 0x15, 0x5e, 0xff, 0x93, 0x2e, 0xf2, 0x1e, 0xdf,
+	0x3d, 0xdb, 0x9d, 0xc1, 0x69, 0x3e, 0xc4, 0x85, 0x2b, 0x53, 0xc0, 0x70, 0x9d, 0xe0, 0x1f, 0x3e,
+	0xd5, 0x36, 0xde, 0xfb, 0x4a, 0x17, 0x13, 0xe9, 0x6c, 0xbe, 0x38, 0x89, 0xcc, 0xf8, 0x22, 0x99,
+	0x74, 0x39, 0x67, 0x12, 0x25, 0x2f, 0x9c, 0x25, 0x89, 0xc8, 0x67, 0x70, 0xc1, 0x9c, 0xbe, 0x8c,
+	0xc6, 0x3f, 0xa8, 0xaa, 0x6d, 0xac, 0xcf, 0xe5, 0x15, 0x4a, 0x99, 0xc5, 0x84, 0x3c, 0x8f, 0x2f,
+	0x83, 0xf3, 0x42, 0xbf, 0x74, 0x25, 0xcf, 0xd5, 0x93, 0x98, 0x34, 0x4d, 0x48, 0x7e, 0x04, 0x17,
+	0xb5, 0xac, 0x23, 0x03, 0xe9, 0x2a, 0xe7, 0x78, 0xf7, 0x14, 0x1c, 0x43, 0x49, 0xb3, 0x19, 0x91,
+	0x3e, 0x9c, 0x77, 0x26, 0x4f, 0x17, 0xa5, 0x6b, 0x9c, 0xfb, 0x9d, 0x19, 0xfe, 0x38, 0x81, 0x4d,
+	0xa7, 0x19, 0xf8, 0x8b, 0x05, 0x3b, 0x94, 0xae, 0xe7, 0x2e, 0x16, 0xec, 0x90, 0x72, 0x34, 0xf2,
+	0x7d, 0x10, 0x07, 0x13, 0xb5, 0x64, 0xe9, 0x35, 0x4e, 0x7a, 0x7b, 0x56, 0xe9, 0x35, 0x85, 0x4c,
+	0xa7, 0xc8, 0x89, 0x01, 0xd2, 0x60, 0x46, 0x79, 0x5a, 0xba, 0x91, 0xe3, 0xfc, 0xb3, 0x6a, 0xda,
+	0x74, 0x26, 0x3b, 0xa2, 0xc0, 0x25, 0xff, 0xd0, 0x3c, 0x8a, 0x6d, 0x8a, 0xc6, 0x8f, 0xdc, 0xa5,
+	0x9b, 0xbc, 0xa3, 0xb7, 0x66, 0xac, 0x20, 0xd3, 0x67, 0xf4, 0x74, 0x55, 0xcd, 0x80, 0x92, 0x1f,
+	0xc2, 0xea, 0x20, 0xa3, 0x02, 0x2c, 0xad, 0xe5, 0xb0, 0xcf, 0x2c, 0x19, 0x67, 0xb2, 0x21, 0x63,
+	0xb8, 0x3e, 0xc8, 0x29, 0x30, 0x4b, 0xaf, 0xf3, 0x6e, 0x1e, 0x9c, 0xbe, 0x9b, 0x50, 0x65, 0xb9,
+	0x6c, 0x31, 0x93, 0x19, 0x84, 0x85, 0x60, 0x49, 0xce, 0x59, 0xdb, 0xe3, 0x72, 0x71, 0x4c, 0x80,
+	0x7e, 0x3b, 0x98, 0x2c, 0x23, 0x4b, 0xb7, 0x72, 0xfc, 0x76, 0xaa, 0xe8, 0x4c, 0xa7, 0x19, 0xe0,
+	0xcc, 0x55, 0x93, 0x1f, 0x15, 0x49, 0x6f, 0xe4, 0xcc, 0xdc, 0xd4, 0xe7, 0x47, 0x34, 0x4d, 0x48,
+	0x5a, 0xb0, 0xa4, 0x26, 0xbe, 0x9f, 0x92, 0x6e, 0x73, 0x46, 0xaf, 0xcf, 0x64, 0x14, 0x49, 0x95,
+	0x22, 0xc3, 0x50, 0xa7, 0xc6, 0xd7, 0x54, 0xa4, 0x3b, 0x39, 0xa1, 0x2e, 0x71, 0x9d, 0x85, 0x26,
+	0x89, 0x02, 0x55, 0xa5, 0x4b, 0xc0, 0xd2, 0x9b, 0xf9, 0xaa, 0x4a, 0x63, 0xd3, 0x69, 0x06, 0xc4,
+	0x84, 0x2b, 0x83, 0x59, 0x85, 0x65, 0x69, 0x9d, 0x73, 0xbf, 0x77, 0x4a, 0xee, 0x51, 0xc8, 0x9f,
+	0xc9, 0x90, 0x3c, 0x84, 0x05, 0x8b, 0x57, 0x62, 0xa5, 0x8d, 0xac, 0x5b, 0x17, 0xe9, 0x62, 0x6d,
+	0x80, 0x4a, 0xb6, 0x61, 0xd9, 0x4a, 0x95, 0x6f, 0xa5, 0x87, 0x9c, 0xf8, 0x56, 0x1e, 0x71, 0x28,
+	0xcc, 0x04, 0x29, 0x6a, 0x51, 0x9d, 0xac, 0x3d, 0x4a, 0x8f, 0x72, 0xb4, 0x38, 0x5d, 0xa9, 0x9c,
+	0x66, 0x80, 0x5a, 0x54, 0x67, 0x55, 0x34, 0xa5, 0xf7, 0x72, 0xb4, 0x38, 0xb3, 0x0e, 0x4a, 0x67,
+	0x33, 0xc4, 0x40, 0xa2, 0x66, 0xd4, 0xcd, 0xa4, 0xc7, 0x79, 0x71, 0x2a, 0x83, 0x80, 0x66, 0xb2,
+	0xc1, 0x40, 0xa2, 0xe6, 0x94, 0xe5, 0xa4, 0xef, 0xe4, 0x04, 0x92, 0xbc, 0x7a, 0x1e, 0xcd, 0x65,
+	0x8b, 0xbe, 0xc1, 0xf8, 0x76, 0x55, 0x7a, 0x3f, 0xc7, 0x37, 0x82, 0x2a, 0x54, 0x80, 0x8a, 0xbe,
+	0xc1, 0x52, 0x75, 0x29, 0xe9, 0x83, 0x1c, 0xdf, 0x48, 0x97, 0xb0, 0xe8, 0x04, 0x29, 0xfa, 0x06,
+	0x9b, 0x2c, 0x93, 0x48, 0x4f, 0x72, 0x7c, 0x63, 0xba, 0xa8, 0x32, 0xcd, 0x00, 0x7d, 0x83, 0xcd,
+	0x2a, 0xbe, 0x48, 0x1f, 0xe6, 0xf8, 0xc6, 0xcc, 0x92, 0x0d, 0x9d, 0xcd, 0x10, 0x7d, 0x83, 0x65,
+	0x6c, 0xfa, 0xa5, 0x8f, 0x72, 0x7c, 0x23, 0xb3, 0x4a, 0x90, 0xc9, 0x06, 0x7d, 0x83, 0xe5, 0xd4,
+	0x14, 0xa4, 0xef, 0xe6, 0xf8, 0x46, 0x5e, 0x31, 0x82, 0xe6, 0xb2, 0x95, 0x7f, 0x5e, 0x09, 0xfe,
+	0x4f, 0x05, 0xef, 0xd6, 0x77, 0x3b, 0x9d, 0x56, 0xb3, 0x2f, 0x16, 0xf0, 0xb3, 0xa7, 0xe0, 0xa5,
+	0xb5, 0x25, 0x16, 0xf1, 0xb5, 0xb7, 0xb7, 0xd9, 0x6b, 0xd2, 0xf6, 0x66, 0x4b, 0x2c, 0xf1, 0xbf,
+	0x56, 0xa1, 0xdd, 0xad, 0xbd, 0x66, 0x8b, 0xfa, 0x7f, 0xa3, 0xd2, 0x6b, 0x75, 0xb6, 0xc4, 0x05,
+	0x22, 0xc2, 0x12, 0x3e, 0x29, 0xb4, 0xd5, 0x6c, 0xb5, 0x77, 0xfb, 0xe2, 0x22, 0x1e, 0xe7, 0x72,
+	0x48, 0x8b, 0xd2, 0x2e, 0x15, 0x2b, 0xd8, 0xc9, 0x8b, 0x56, 0xaf, 0xd7, 0x78, 0xd6, 0x12, 0xab,
+	0xfc, 0x1c, 0xb7, 0xb9, 0x2d, 0x02, 0x72, 0x78, 0xba, 0xd3, 0xfd, 0x44, 0xac, 0x91, 0x15, 0xa8,
+	0xed, 0x75, 0xe2, 0xae, 0x96, 0x90, 0xa0, 0xb7, 0xd7, 0x6c, 0xb6, 0x7a, 0x3d, 0xb1, 0x8e, 0xff,
+	0xc2, 0xe2, 0x33, 0x5a, 0xc6, 0x73, 0xe1, 0xe6, 0x4e, 0xb7, 0xd7, 0x52, 0x22, 0x41, 0x56, 0x62,
+	0x58, 0xb3, 0xdb, 0xe9, 0xed, 0xbd, 0x68, 0x51, 0x51, 0xc4, 0x3b, 0x91, 0x21, 0x86, 0x12, 0x32,
+	0x3a, 0x8f, 0x1d, 0xee, 0xb6, 0x3b, 0xcf, 0x44, 0xc2, 0x9f, 0xba, 0x9d, 0x67, 0xe2, 0x05, 0x72,
+	0x1b, 0x5e, 0xa7, 0xad, 0xad, 0xd6, 0x4e, 0xfb, 0x65, 0x8b, 0x2a, 0x7b, 0x9d, 0x46, 0x73, 0xbb,
+	0xd3, 0xfd, 0x64, 0xa7, 0xb5, 0xf5, 0xac, 0xb5, 0xa5, 0x04, 0x32, 0xf7, 0xc4, 0x55, 0x22, 0xc1,
+	0xea, 0x6e, 0x83, 0xf6, 0xdb, 0xfd, 0x76, 0xb7, 0xc3, 0x5b, 0xfa, 0x8d, 0xad, 0x46, 0xbf, 0x21,
+	0x5e, 0x24, 0xaf, 0xc3, 0x6b, 0x59, 0x2d, 0x0a, 0x6d, 0xf5, 0x76, 0xbb, 0x9d, 0x5e, 0x4b, 0xbc,
+	0xc4, 0xbf, 0x00, 0xeb, 0x76, 0xb7, 0xf7, 0x76, 0xc5, 0xcb, 0x78, 0xf9, 0xd2, 0x7f, 0x8e, 0x11,
+	0x24, 0x3e, 0x84, 0x40, 0x78, 0xa5, 0xd7, 0x6f, 0xf4, 0x7b, 0xe2, 0x15, 0x72, 0x0d, 0x2e, 0xa7,
+	0x61, 0x31, 0xc1, 0x55, 0x14, 0x87, 0xb6, 0x1a, 0xcd, 0xe7, 0xad, 0x2d, 0x05, 0xf5, 0xdc, 0x7d,
+	0xaa, 0xf4, 0xbb, 0xbb, 0xed, 0xa6, 0x78, 0xcd, 0x37, 0x4b, 0x6b, 0x5b, 0xbc, 0x4e, 0x2e, 0xc3,
+	0x85, 0x67, 0xad, 0xbe, 0xb2, 0xd3, 0xe8, 0xf5, 0xc3, 0x91, 0x28, 0xed, 0x2d, 0xf1, 0x35, 0xb2,
+	0x06, 0xd7, 0x33, 0x1a, 0x62, 0xf6, 0x37, 0xc8, 0x55, 0xb8, 0xd4, 0x68, 0xf6, 0xdb, 0x2f, 0x63,
+	0x9d, 0x2a, 0xcd, 0xe7, 0x8d, 0xce, 0xb3, 0x96, 0x78, 0x13, 0xe5, 0x42, 0x6a, 0xde, 0x5f, 0x0f,
+	0x7b, 0xee, 0x34, 0x5e, 0xb4, 0x7a, 0xbb, 0x8d, 0x66, 0x4b, 0x5c, 0x23, 0x6f, 0xc0, 0xda, 0x8c,
+	0xc6, 0x98, 0xfd, 0xeb, 0xe8, 0x1e, 0x88, 0xd5, 0x6b, 0x3e, 0x6f, 0xbd, 0x68, 0x88, 0x72, 0x28,
+	0xa9, 0xff, 0x1e, 0x23, 0xde, 0x42, 0xbd, 0x34, 0xf6, 0xfa, 0xcf, 0xb1, 0xf3, 0x9d, 0x9d, 0x16,
+	0xf6, 0xff, 0x06, 0x39, 0x0f, 0x75, 0x0e, 0x8b, 0xd0, 0x6e, 0xa3, 0x03, 0x36, 0x9a, 0xdb, 0x31,
+	0xe4, 0x0e, 0xea, 0x07, 0x39, 0x76, 0xa9, 0xd2, 0xa4, 0xad, 0x46, 0xbf, 0x15, 0xf6, 0xf5, 0x26,
+	0x9a, 0x2b, 0xab, 0x25, 0x26, 0x5e, 0x47, 0xe7, 0xeb, 0xb4, 0x3e, 0x51, 0xfa, 0xbf, 0xd9, 0x11,
+	0x37, 0xd0, 0x93, 0x82, 0x97, 0x18, 0xe5, 0x21, 0xf2, 0x6f, 0x6c, 0x6d, 0x29, 0x91, 0xe1, 0x95,
+	0x7e, 0x97, 0xe3, 0x3f, 0x42, 0xfe, 0x59, 0x2d, 0x31, 0xf1, 0x7b, 0xa8, 0x41, 0x44, 0x09, 0xfc,
+	0x7d, 0x37, 0x49, 0xff, 0x18, 0x35, 0x38, 0xa3, 0x31, 0x66, 0xf1, 0x1d, 0x14, 0x11, 0xed, 0x8e,
+	0x24, 0xef, 0xa3, 0x88, 0xc1, 0x4b, 0x8c, 0xf2, 0x01, 0x8a, 0x18, 0x42, 0xbb, 0x9d, 0x58, 0x1e,
+	0xf1, 0x09, 0x8a, 0x98, 0xd5, 0x12, 0x13, 0x7f, 0x88, 0x22, 0x26, 0x50, 0x92, 0xc2, 0x88, 0x1f,
+	0xa1, 0x88, 0x33, 0x1a, 0x63, 0x16, 0xdf, 0xbd, 0xbb, 0xc5, 0x3f, 0xcd, 0x49, 0xfe, 0x1f, 0x0c,
+	0xff, 0xef, 0xa6, 0x6e, 0xa7, 0x25, 0x9e, 0xc3, 0x18, 0xb0, 0xf3, 0xd9, 0x23, 0xff, 0x8f, 0x9b,
+	0x3e, 0xdb, 0x69, 0x6f, 0x8a, 0x05, 0xfe, 0xd4, 0xeb, 0x63, 0xd8, 0xc1, 0xaf, 0x2d, 0x3b, 0x8d,
+	0xdd, 0xdd, 0x4f, 0xc5, 0xd2, 0xdd, 0xdf, 0x2b, 0x43, 0x2d, 0x51, 0xc1, 0x44, 0x53, 0xef, 0x59,
+	0xb8, 0x99, 0x0f, 0x2e, 0x2e, 0x9f, 0x43, 0x7f, 0x08, 0x37, 0xc2, 0x89, 0x1b, 0xd1, 0xbb, 0xcc,
+	0x71, 0xf9, 0x57, 0x60, 0x5a, 0x70, 0xed, 0xb9, 0x80, 0x5e, 0x86, 0x09, 0x25, 0xb3, 0x3c, 0xfc,
+	0x96, 0x35, 0xba, 0xfa, 0x5c, 0xc4, 0x8b, 0xd5, 0x0d, 0xff, 0xc3, 0xa8, 0x2f, 0x12, 0xf0, 0x12,
+	0xf6, 0x15, 0x6e, 0x38, 0x36, 0xc7, 0xee, 0x89, 0x58, 0xc6, 0xc9, 0x1b, 0x7c, 0xb2, 0xd4, 0xb1,
+	0x3d, 0x7e, 0x69, 0x4f, 0x5c, 0xc0, 0x08, 0x12, 0x56, 0x52, 0x36, 0xfd, 0x9b, 0x51, 0xdf, 0x1f,
+	0xdb, 0x9e, 0xda, 0x3a, 0xd6, 0x18, 0xd3, 0x99, 0x5f, 0x38, 0x12, 0x17, 0xc9, 0x5b, 0x70, 0x3b,
+	0x17, 0xed, 0x58, 0x63, 0xfe, 0x4d, 0xef, 0x0a, 0x0e, 0x29, 0xbc, 0xd1, 0xed, 0x53, 0x57, 0xd1,
+	0x20, 0x7b, 0x56, 0xf0, 0x5f, 0x09, 0x4c, 0x0f, 0xae, 0x00, 0xf8, 0x8d, 0x80, 0xf8, 0x7c, 0x3b,
+	0xd1, 0xb1, 0xbd, 0xa7, 0xf6, 0xd8, 0xd2, 0xc5, 0x1a, 0x5a, 0x3f, 0x19, 0xf7, 0xa3, 0x96, 0x25,
+	0x7e, 0x5d, 0x3c, 0xbc, 0x4a, 0x16, 0x42, 0xeb, 0x38, 0xb2, 0xbe, 0x6d, 0xbf, 0x50, 0xad, 0x13,
+	0xea, 0xd7, 0xab, 0x5d, 0x71, 0x19, 0x99, 0x70, 0xbe, 0x7d, 0xe6, 0x0c, 0x0d, 0x4b, 0xf5, 0xc2,
+	0xc1, 0xac, 0xa0, 0x6a, 0xa2, 0xc1, 0xa0, 0x6a, 0x78, 0xc4, 0x6d, 0x5b, 0xfc, 0x96, 0xbe, 0x2f,
+	0x8a, 0x3a, 0x64, 0xe2, 0x79, 0x54, 0x6d, 0x9b, 0xdf, 0x69, 0x57, 0x3d, 0x63, 0xdf, 0x0c, 0x92,
+	0x57, 0x91, 0xa0, 0x2d, 0x42, 0x21, 0x1a, 0xae, 0x6b, 0x0c, 0x82, 0xa1, 0x5c, 0x20, 0x32, 0xdc,
+	0xe8, 0x3b, 0xaa, 0xe5, 0xfa, 0x35, 0xfa, 0xa6, 0x6d, 0x3b, 0x3a, 0xf6, 0x6c, 0xc7, 0xb2, 0xae,
+	0x26, 0xbb, 0x3a, 0xe6, 0x9f, 0x21, 0x8f, 0x5d, 0xf1, 0x22, 0x8e, 0xa0, 0x63, 0x7b, 0x0d, 0xfc,
+	0xb0, 0x3e, 0x94, 0xf3, 0x12, 0xf6, 0x93, 0x62, 0x67, 0xbd, 0x32, 0x0d, 0xcd, 0x13, 0x2f, 0x4f,
+	0x34, 0x44, 0xcc, 0x79, 0x28, 0x0e, 0x47, 0xf6, 0x14, 0xbd, 0x47, 0x17, 0xaf, 0xdc, 0xdd, 0x06,
+	0x48, 0x7c, 0x8a, 0x88, 0x41, 0x29, 0x7a, 0x0b, 0xfe, 0x8b, 0xec, 0x02, 0xac, 0xc4, 0xb0, 0x4f,
+	0x35, 0xf5, 0xe5, 0x03, 0xdf, 0x0d, 0x63, 0x60, 0x03, 0x3d, 0xcf, 0x15, 0x0b, 0x77, 0xff, 0x58,
+	0x80, 0x95, 0xdd, 0x89, 0xbf, 0xa1, 0x58, 0x80, 0xc2, 0xd1, 0x7d, 0xf1, 0x1c, 0xff, 0x45, 0x4a,
+	0xfc, 0xdd, 0x10, 0x0b, 0xfc, 0xf7, 0xa1, 0x58, 0xe4, 0xbf, 0x8f, 0xc4, 0x12, 0xff, 0x7d, 0x4f,
+	0x2c, 0xf3, 0xdf, 0xc7, 0xe2, 0x02, 0xff, 0xfd, 0x8e, 0xb8, 0xc8, 0x7f, 0xdf, 0x17, 0x2b, 0xfc,
+	0xf7, 0x03, 0x7f, 0x89, 0x3d, 0x7a, 0x70, 0x5f, 0x04, 0xff, 0xe1, 0x81, 0x58, 0xf3, 0x1f, 0x36,
+	0xc4, 0x25, 0xff, 0xe1, 0xa1, 0x58, 0xf7, 0x1f, 0x1e, 0x89, 0xcb, 0xfe, 0xc3, 0x7b, 0xe2, 0xca,
+	0xdd, 0xb7, 0x93, 0xff, 0xa4, 0x10, 0xdc, 0xca, 0x6a, 0xec, 0xf5, 0xbb, 0x4a, 0x6f, 0x77, 0xa7,
+	0xdd, 0x0f, 0x3e, 0x83, 0xee, 0xb7, 0x9b, 0xdb, 0x9f, 0x8a, 0xc2, 0xdd, 0x16, 0x90, 0xe9, 0x8f,
+	0xbb, 0x12, 0x1f, 0x4a, 0x9f, 0x4b, 0x7f, 0x43, 0xcd, 0xb5, 0xf1, 0x89, 0x6a, 0x78, 0x4f, 0x6d,
+	0x27, 0x86, 0x16, 0xee, 0xca, 0x50, 0x8d, 0x8e, 0x5c, 0x90, 0xba, 0xd9, 0x7d, 0xf1, 0x82, 0xf7,
+	0x55, 0x85, 0x72, 0x63, 0xb3, 0x4b, 0xfb, 0xa2, 0xb0, 0xb9, 0xf1, 0xd3, 0x2f, 0x6f, 0x08, 0xff,
+	0xf8, 0xe5, 0x0d, 0xe1, 0x5f, 0xbe, 0xbc, 0x21, 0x80, 0x6c, 0x3b, 0x83, 0x7b, 0xea, 0x08, 0x2b,
+	0x2b, 0x61, 0x52, 0xa4, 0xd9, 0xc3, 0xa1, 0x6d, 0xdd, 0x53, 0xc3, 0xbf, 0xb8, 0x7b, 0x5e, 0xfc,
+	0x9f, 0x01, 0x00, 0x57, 0xbd, 0x69, 0x6e, 0xf6, 0x4e, 0x00, 0x00,
 }
 
 func (m *Schema) Marshal() (dAtA []byte, err error) {
@@ -8023,6 +8137,13 @@ func (m *CommandLookupTopic) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if m.AdvertisedListenerName != nil {
+		i -= len(*m.AdvertisedListenerName)
+		copy(dAtA[i:], *m.AdvertisedListenerName)
+		i = encodeVarintPulsarApi(dAtA, i, uint64(len(*m.AdvertisedListenerName)))
+		i--
+		dAtA[i] = 0x3a
+	}
 	if m.OriginalAuthMethod != nil {
 		i -= len(*m.OriginalAuthMethod)
 		copy(dAtA[i:], *m.OriginalAuthMethod)
@@ -8182,6 +8303,16 @@ func (m *CommandProducer) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if m.TopicEpoch != nil {
+		i = encodeVarintPulsarApi(dAtA, i, uint64(*m.TopicEpoch))
+		i--
+		dAtA[i] = 0x58
+	}
+	if m.ProducerAccessMode != nil {
+		i = encodeVarintPulsarApi(dAtA, i, uint64(*m.ProducerAccessMode))
+		i--
+		dAtA[i] = 0x50
+	}
 	if m.UserProvidedProducerName != nil {
 		i--
 		if *m.UserProvidedProducerName {
@@ -9053,6 +9184,21 @@ func (m *CommandProducerSuccess) MarshalToSizedBuffer(dAtA []byte) (int, error)
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if m.ProducerReady != nil {
+		i--
+		if *m.ProducerReady {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x30
+	}
+	if m.TopicEpoch != nil {
+		i = encodeVarintPulsarApi(dAtA, i, uint64(*m.TopicEpoch))
+		i--
+		dAtA[i] = 0x28
+	}
 	if m.SchemaVersion != nil {
 		i -= len(m.SchemaVersion)
 		copy(dAtA[i:], m.SchemaVersion)
@@ -11797,6 +11943,10 @@ func (m *CommandLookupTopic) Size() (n int) {
 		l = len(*m.OriginalAuthMethod)
 		n += 1 + l + sovPulsarApi(uint64(l))
 	}
+	if m.AdvertisedListenerName != nil {
+		l = len(*m.AdvertisedListenerName)
+		n += 1 + l + sovPulsarApi(uint64(l))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -11881,6 +12031,12 @@ func (m *CommandProducer) Size() (n int) {
 	if m.UserProvidedProducerName != nil {
 		n += 2
 	}
+	if m.ProducerAccessMode != nil {
+		n += 1 + sovPulsarApi(uint64(*m.ProducerAccessMode))
+	}
+	if m.TopicEpoch != nil {
+		n += 1 + sovPulsarApi(uint64(*m.TopicEpoch))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -12246,6 +12402,12 @@ func (m *CommandProducerSuccess) Size() (n int) {
 		l = len(m.SchemaVersion)
 		n += 1 + l + sovPulsarApi(uint64(l))
 	}
+	if m.TopicEpoch != nil {
+		n += 1 + sovPulsarApi(uint64(*m.TopicEpoch))
+	}
+	if m.ProducerReady != nil {
+		n += 2
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -17102,6 +17264,39 @@ func (m *CommandLookupTopic) Unmarshal(dAtA []byte) error {
 			s := string(dAtA[iNdEx:postIndex])
 			m.OriginalAuthMethod = &s
 			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AdvertisedListenerName", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPulsarApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPulsarApi
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPulsarApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			s := string(dAtA[iNdEx:postIndex])
+			m.AdvertisedListenerName = &s
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipPulsarApi(dAtA[iNdEx:])
@@ -17664,6 +17859,46 @@ func (m *CommandProducer) Unmarshal(dAtA []byte) error {
 			}
 			b := bool(v != 0)
 			m.UserProvidedProducerName = &b
+		case 10:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ProducerAccessMode", wireType)
+			}
+			var v ProducerAccessMode
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPulsarApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= ProducerAccessMode(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.ProducerAccessMode = &v
+		case 11:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TopicEpoch", wireType)
+			}
+			var v uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPulsarApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.TopicEpoch = &v
 		default:
 			iNdEx = preIndex
 			skippy, err := skipPulsarApi(dAtA[iNdEx:])
@@ -19863,6 +20098,47 @@ func (m *CommandProducerSuccess) Unmarshal(dAtA []byte) error {
 				m.SchemaVersion = []byte{}
 			}
 			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TopicEpoch", wireType)
+			}
+			var v uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPulsarApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.TopicEpoch = &v
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ProducerReady", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPulsarApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			b := bool(v != 0)
+			m.ProducerReady = &b
 		default:
 			iNdEx = preIndex
 			skippy, err := skipPulsarApi(dAtA[iNdEx:])
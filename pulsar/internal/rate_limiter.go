@@ -0,0 +1,97 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package internal
+
+import (
+	"sync"
+	"time"
+)
+
+// RateLimiter is a token bucket refilled continuously at a given rate per second, it holds at most one second of
+// tokens
+type RateLimiter interface {
+	// Reserve takes n tokens and returns how long to wait before they are available, zero when they are available
+	// right away. The bucket can go into debt so a reservation larger than the rate is granted once the bucket is
+	// full.
+	Reserve(n int) time.Duration
+
+	// Cancel returns the n tokens of a reservation which is not used
+	Cancel(n int)
+}
+
+type rateLimiter struct {
+	sync.Mutex
+	rate   float64
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+}
+
+// NewRateLimiter creates a full bucket refilled with the given number of tokens per second
+func NewRateLimiter(perSecond int) RateLimiter {
+	if perSecond <= 0 {
+		panic("Rate for rate limiter needs to be > 0")
+	}
+	return newRateLimiter(perSecond, time.Now)
+}
+
+func newRateLimiter(perSecond int, now func() time.Time) *rateLimiter {
+	return &rateLimiter{
+		rate:   float64(perSecond),
+		tokens: float64(perSecond),
+		last:   now(),
+		now:    now,
+	}
+}
+
+func (r *rateLimiter) Reserve(n int) time.Duration {
+	r.Lock()
+	defer r.Unlock()
+	r.refill()
+
+	// a reservation larger than the bucket waits for the bucket to be full
+	needed := float64(n)
+	if needed > r.rate {
+		needed = r.rate
+	}
+	var wait time.Duration
+	if r.tokens < needed {
+		wait = time.Duration((needed - r.tokens) / r.rate * float64(time.Second))
+	}
+	r.tokens -= float64(n)
+	return wait
+}
+
+func (r *rateLimiter) Cancel(n int) {
+	r.Lock()
+	defer r.Unlock()
+	r.refill()
+	r.tokens += float64(n)
+	if r.tokens > r.rate {
+		r.tokens = r.rate
+	}
+}
+
+func (r *rateLimiter) refill() {
+	now := r.now()
+	r.tokens += now.Sub(r.last).Seconds() * r.rate
+	if r.tokens > r.rate {
+		r.tokens = r.rate
+	}
+	r.last = now
+}
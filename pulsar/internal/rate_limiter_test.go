@@ -0,0 +1,62 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package internal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Now()
+	r := newRateLimiter(10, func() time.Time { return now })
+
+	// the bucket starts full
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, time.Duration(0), r.Reserve(1))
+	}
+	assert.Equal(t, 100*time.Millisecond, r.Reserve(1))
+	r.Cancel(1)
+
+	// the bucket is refilled over time
+	now = now.Add(300 * time.Millisecond)
+	assert.Equal(t, time.Duration(0), r.Reserve(3))
+	assert.Equal(t, 100*time.Millisecond, r.Reserve(1))
+
+	// never more than one second of tokens
+	now = now.Add(time.Hour)
+	assert.Equal(t, time.Duration(0), r.Reserve(10))
+	assert.Equal(t, 100*time.Millisecond, r.Reserve(1))
+}
+
+func TestRateLimiterLargeReservation(t *testing.T) {
+	now := time.Now()
+	r := newRateLimiter(10, func() time.Time { return now })
+
+	// larger than the rate, granted since the bucket is full
+	assert.Equal(t, time.Duration(0), r.Reserve(30))
+
+	// the next reservation waits for the debt to be paid back
+	assert.Equal(t, 2100*time.Millisecond, r.Reserve(1))
+	r.Cancel(1)
+
+	now = now.Add(3 * time.Second)
+	assert.Equal(t, time.Duration(0), r.Reserve(10))
+}
@@ -18,10 +18,10 @@
 package internal
 
 import (
+	"context"
 	"errors"
 	"net"
 	"net/url"
-	"sync"
 	"sync/atomic"
 	"time"
 
@@ -31,6 +31,9 @@ import (
 	"github.com/gogo/protobuf/proto"
 )
 
+// ErrRequestTimeOut is returned when the broker doesn't respond to a request within the operation timeout
+var ErrRequestTimeOut = errors.New("request timed out")
+
 type RPCResult struct {
 	Response *pb.BaseCommand
 	Cnx      Connection
@@ -44,15 +47,23 @@ type RPCClient interface {
 
 	NewConsumerID() uint64
 
-	// Send a request and block until the result is available
-	RequestToAnyBroker(requestID uint64, cmdType pb.BaseCommand_Type, message proto.Message) (*RPCResult, error)
+	// Send a request and block until the result is available, the operation timeout elapses or ctx is done
+	RequestToAnyBroker(ctx context.Context, requestID uint64, cmdType pb.BaseCommand_Type,
+		message proto.Message) (*RPCResult, error)
 
-	Request(logicalAddr *url.URL, physicalAddr *url.URL, requestID uint64,
+	Request(ctx context.Context, logicalAddr *url.URL, physicalAddr *url.URL, requestID uint64,
 		cmdType pb.BaseCommand_Type, message proto.Message) (*RPCResult, error)
 
+	// Send a request the broker may queue, like the creation of a producer waiting for the exclusive access to
+	// the topic. onQueued is called when the broker answers that the request is queued, the wait for the final
+	// response is then bounded only by ctx.
+	RequestWithQueuedCallback(ctx context.Context, logicalAddr *url.URL, physicalAddr *url.URL, requestID uint64,
+		cmdType pb.BaseCommand_Type, message proto.Message, onQueued func()) (*RPCResult, error)
+
 	RequestOnCnxNoWait(cnx Connection, cmdType pb.BaseCommand_Type, message proto.Message) error
 
-	RequestOnCnx(cnx Connection, requestID uint64, cmdType pb.BaseCommand_Type, message proto.Message) (*RPCResult, error)
+	RequestOnCnx(ctx context.Context, cnx Connection, requestID uint64, cmdType pb.BaseCommand_Type,
+		message proto.Message) (*RPCResult, error)
 }
 
 type rpcClient struct {
@@ -77,15 +88,15 @@ func NewRPCClient(serviceURL *url.URL, serviceNameResolver ServiceNameResolver,
 	}
 }
 
-func (c *rpcClient) RequestToAnyBroker(requestID uint64, cmdType pb.BaseCommand_Type,
+func (c *rpcClient) RequestToAnyBroker(ctx context.Context, requestID uint64, cmdType pb.BaseCommand_Type,
 	message proto.Message) (*RPCResult, error) {
 	host, err := c.serviceNameResolver.ResolveHost()
 	if err != nil {
 		c.log.Errorf("request host resolve failed with error: {%v}", err)
 		return nil, err
 	}
-	rpcResult, err := c.Request(host, host, requestID, cmdType, message)
-	if _, ok := err.(net.Error); ok || (err != nil && err.Error() == "connection error") {
+	rpcResult, err := c.Request(ctx, host, host, requestID, cmdType, message)
+	if _, ok := err.(net.Error); ok || err == ErrConnectionFailed {
 		// We can retry this kind of requests over a connection error because they're
 		// not specific to a particular broker.
 		backoff := Backoff{100 * time.Millisecond}
@@ -95,14 +106,18 @@ func (c *rpcClient) RequestToAnyBroker(requestID uint64, cmdType pb.BaseCommand_
 		for time.Since(startTime) < c.requestTimeout {
 			retryTime = backoff.Next()
 			c.log.Debugf("Retrying request in {%v} with timeout in {%v}", retryTime, c.requestTimeout)
-			time.Sleep(retryTime)
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(retryTime):
+			}
 			host, err = c.serviceNameResolver.ResolveHost()
 			if err != nil {
 				c.log.Errorf("Retrying request host resolve failed with error: {%v}", err)
 				continue
 			}
-			rpcResult, err = c.Request(host, host, requestID, cmdType, message)
-			if _, ok := err.(net.Error); ok || (err != nil && err.Error() == "connection error") {
+			rpcResult, err = c.Request(ctx, host, host, requestID, cmdType, message)
+			if _, ok := err.(net.Error); ok || err == ErrConnectionFailed {
 				continue
 			} else {
 				// We either succeeded or encountered a non connection error
@@ -113,7 +128,7 @@ func (c *rpcClient) RequestToAnyBroker(requestID uint64, cmdType pb.BaseCommand_
 	return rpcResult, err
 }
 
-func (c *rpcClient) Request(logicalAddr *url.URL, physicalAddr *url.URL, requestID uint64,
+func (c *rpcClient) Request(ctx context.Context, logicalAddr *url.URL, physicalAddr *url.URL, requestID uint64,
 	cmdType pb.BaseCommand_Type, message proto.Message) (*RPCResult, error) {
 	c.metrics.RPCRequestCount.Inc()
 	cnx, err := c.pool.GetConnection(logicalAddr, physicalAddr)
@@ -121,47 +136,80 @@ func (c *rpcClient) Request(logicalAddr *url.URL, physicalAddr *url.URL, request
 		return nil, err
 	}
 
+	return c.sendRequest(ctx, cnx, requestID, cmdType, message, nil)
+}
+
+func (c *rpcClient) RequestWithQueuedCallback(ctx context.Context, logicalAddr *url.URL, physicalAddr *url.URL,
+	requestID uint64, cmdType pb.BaseCommand_Type, message proto.Message, onQueued func()) (*RPCResult, error) {
+	c.metrics.RPCRequestCount.Inc()
+	cnx, err := c.pool.GetConnection(logicalAddr, physicalAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.sendRequest(ctx, cnx, requestID, cmdType, message, onQueued)
+}
+
+func (c *rpcClient) RequestOnCnx(ctx context.Context, cnx Connection, requestID uint64, cmdType pb.BaseCommand_Type,
+	message proto.Message) (*RPCResult, error) {
+	c.metrics.RPCRequestCount.Inc()
+	return c.sendRequest(ctx, cnx, requestID, cmdType, message, nil)
+}
+
+// sendRequest writes the command on cnx and waits for the response correlated by requestID. The wait is
+// bounded both by ctx and by the operation timeout, when either ends first the request is dropped from the
+// connection's pending requests so a late response is discarded. Once the broker answered that the request is
+// queued, onQueued is called and only ctx bounds the wait.
+func (c *rpcClient) sendRequest(parentCtx context.Context, cnx Connection, requestID uint64,
+	cmdType pb.BaseCommand_Type, message proto.Message, onQueued func()) (*RPCResult, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, c.requestTimeout)
+	defer cancel()
+
 	type Res struct {
 		*RPCResult
 		error
 	}
-	ch := make(chan Res, 10)
+	// buffered so that a response arriving after we gave up never blocks the connection
+	ch := make(chan Res, 1)
+	queuedCh := make(chan struct{}, 1)
 
 	cnx.SendRequest(requestID, baseCommand(cmdType, message), func(response *pb.BaseCommand, err error) {
+		if err == nil && isQueuedResponse(response) {
+			select {
+			case queuedCh <- struct{}{}:
+			default:
+			}
+			return
+		}
 		ch <- Res{&RPCResult{
 			Cnx:      cnx,
 			Response: response,
 		}, err}
-		close(ch)
 	})
 
-	select {
-	case res := <-ch:
-		return res.RPCResult, res.error
-	case <-time.After(c.requestTimeout):
-		return nil, errors.New("request timed out")
+	for {
+		select {
+		case res := <-ch:
+			return res.RPCResult, res.error
+		case <-queuedCh:
+			ctx = parentCtx
+			if onQueued != nil {
+				onQueued()
+			}
+		case <-ctx.Done():
+			cnx.DeletePendingRequest(requestID)
+			if ctx.Err() == context.DeadlineExceeded {
+				return nil, ErrRequestTimeOut
+			}
+			return nil, ctx.Err()
+		}
 	}
 }
 
-func (c *rpcClient) RequestOnCnx(cnx Connection, requestID uint64, cmdType pb.BaseCommand_Type,
-	message proto.Message) (*RPCResult, error) {
-	c.metrics.RPCRequestCount.Inc()
-	wg := sync.WaitGroup{}
-	wg.Add(1)
-
-	rpcResult := &RPCResult{
-		Cnx: cnx,
-	}
-
-	var rpcErr error
-	cnx.SendRequest(requestID, baseCommand(cmdType, message), func(response *pb.BaseCommand, err error) {
-		rpcResult.Response = response
-		rpcErr = err
-		wg.Done()
-	})
-
-	wg.Wait()
-	return rpcResult, rpcErr
+// isQueuedResponse tells whether the response only acknowledges the request, the broker sending the final
+// response later, eg. the producer waits for the exclusive access to the topic
+func isQueuedResponse(response *pb.BaseCommand) bool {
+	return response.ProducerSuccess != nil && !response.ProducerSuccess.GetProducerReady()
 }
 
 func (c *rpcClient) RequestOnCnxNoWait(cnx Connection, cmdType pb.BaseCommand_Type, message proto.Message) error {
@@ -0,0 +1,126 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package internal
+
+import (
+	"context"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	pb "github.com/apache/pulsar-client-go/pulsar/internal/pulsar_proto"
+	"github.com/apache/pulsar-client-go/pulsar/log"
+)
+
+// mockedConnection keeps the callbacks of the requests until a response is delivered by the test
+type mockedConnection struct {
+	sync.Mutex
+	pending map[uint64]func(*pb.BaseCommand, error)
+}
+
+func newMockedConnection() *mockedConnection {
+	return &mockedConnection{pending: make(map[uint64]func(*pb.BaseCommand, error))}
+}
+
+func (c *mockedConnection) SendRequest(requestID uint64, req *pb.BaseCommand,
+	callback func(*pb.BaseCommand, error)) {
+	c.Lock()
+	defer c.Unlock()
+	c.pending[requestID] = callback
+}
+
+func (c *mockedConnection) respond(requestID uint64, response *pb.BaseCommand) bool {
+	c.Lock()
+	callback, ok := c.pending[requestID]
+	delete(c.pending, requestID)
+	c.Unlock()
+	if ok {
+		callback(response, nil)
+	}
+	return ok
+}
+
+func (c *mockedConnection) DeletePendingRequest(requestID uint64) {
+	c.Lock()
+	defer c.Unlock()
+	delete(c.pending, requestID)
+}
+
+func (c *mockedConnection) SendRequestNoWait(req *pb.BaseCommand) error                   { return nil }
+func (c *mockedConnection) WriteData(data Buffer)                                         {}
+func (c *mockedConnection) RegisterListener(id uint64, listener ConnectionListener) error { return nil }
+func (c *mockedConnection) UnregisterListener(id uint64)                                  {}
+func (c *mockedConnection) AddConsumeHandler(id uint64, handler ConsumerHandler) error    { return nil }
+func (c *mockedConnection) DeleteConsumeHandler(id uint64)                                {}
+func (c *mockedConnection) ID() string                                                    { return "mocked" }
+func (c *mockedConnection) GetMaxMessageSize() int32                                      { return MaxMessageSize }
+func (c *mockedConnection) Ping(ctx context.Context) error                                { return nil }
+func (c *mockedConnection) Close()                                                        {}
+
+func newTestRPCClient(requestTimeout time.Duration) *rpcClient {
+	serviceURL, _ := url.Parse("pulsar://localhost:6650")
+	return NewRPCClient(serviceURL, nil, nil, requestTimeout, log.DefaultNopLogger(),
+		NewMetricsProvider(map[string]string{})).(*rpcClient)
+}
+
+func TestRequestOnCnxResponse(t *testing.T) {
+	c := newTestRPCClient(time.Minute)
+	cnx := newMockedConnection()
+
+	go func() {
+		for !cnx.respond(1, &pb.BaseCommand{Type: pb.BaseCommand_SUCCESS.Enum()}) {
+			time.Sleep(time.Millisecond)
+		}
+	}()
+
+	res, err := c.RequestOnCnx(context.Background(), cnx, 1, pb.BaseCommand_UNSUBSCRIBE,
+		&pb.CommandUnsubscribe{})
+	assert.Nil(t, err)
+	assert.Equal(t, pb.BaseCommand_SUCCESS, res.Response.GetType())
+	assert.Equal(t, cnx, res.Cnx)
+}
+
+func TestRequestOnCnxTimeout(t *testing.T) {
+	c := newTestRPCClient(10 * time.Millisecond)
+	cnx := newMockedConnection()
+
+	res, err := c.RequestOnCnx(context.Background(), cnx, 1, pb.BaseCommand_UNSUBSCRIBE,
+		&pb.CommandUnsubscribe{})
+	assert.Nil(t, res)
+	assert.Equal(t, ErrRequestTimeOut, err)
+	assert.Empty(t, cnx.pending)
+}
+
+func TestRequestOnCnxCancel(t *testing.T) {
+	c := newTestRPCClient(time.Minute)
+	cnx := newMockedConnection()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+
+	res, err := c.RequestOnCnx(ctx, cnx, 1, pb.BaseCommand_UNSUBSCRIBE, &pb.CommandUnsubscribe{})
+	assert.Nil(t, res)
+	assert.Equal(t, context.Canceled, err)
+	assert.Empty(t, cnx.pending)
+
+	// the request is no longer tracked once the caller gave up
+	assert.False(t, cnx.respond(1, &pb.BaseCommand{Type: pb.BaseCommand_SUCCESS.Enum()}))
+}
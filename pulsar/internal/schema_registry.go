@@ -0,0 +1,165 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package internal
+
+import (
+	"container/list"
+	"context"
+	"sync"
+
+	"github.com/gogo/protobuf/proto"
+
+	pb "github.com/apache/pulsar-client-go/pulsar/internal/pulsar_proto"
+)
+
+// SchemaRegistry fetches and registers the schemas of the topics, keeping the most recently used schema
+// versions so that the messages of multi-version topics are decoded without fetching their schema each time
+type SchemaRegistry interface {
+	// GetSchema returns the schema of the topic at the given version, or its latest schema when the
+	// version is empty. The latest schema is never cached as it may change.
+	GetSchema(topic string, schemaVersion []byte) (*pb.Schema, error)
+
+	// GetOrCreateSchema registers the schema with the topic, if it's compatible with the schemas of the
+	// topic, and returns its version
+	GetOrCreateSchema(topic string, schema *pb.Schema) ([]byte, error)
+}
+
+type schemaCacheKey struct {
+	topic         string
+	schemaVersion string
+}
+
+type schemaCacheEntry struct {
+	key    schemaCacheKey
+	schema *pb.Schema
+}
+
+type schemaRegistry struct {
+	lookupService LookupService
+	rpcClient     RPCClient
+
+	sync.Mutex
+	size    int
+	entries map[schemaCacheKey]*list.Element
+	lru     *list.List
+}
+
+// NewSchemaRegistry returns a SchemaRegistry caching up to size schema versions
+func NewSchemaRegistry(lookupService LookupService, rpcClient RPCClient, size int) SchemaRegistry {
+	return &schemaRegistry{
+		lookupService: lookupService,
+		rpcClient:     rpcClient,
+		size:          size,
+		entries:       make(map[schemaCacheKey]*list.Element),
+		lru:           list.New(),
+	}
+}
+
+func (r *schemaRegistry) GetSchema(topic string, schemaVersion []byte) (*pb.Schema, error) {
+	key, err := newSchemaCacheKey(topic, schemaVersion)
+	if err != nil {
+		return nil, err
+	}
+	if len(schemaVersion) > 0 {
+		if schema, ok := r.get(key); ok {
+			return schema, nil
+		}
+	}
+
+	schema, err := r.lookupService.GetSchema(topic, schemaVersion)
+	if err != nil {
+		return nil, err
+	}
+	if len(schemaVersion) > 0 {
+		r.put(key, schema)
+	}
+	return schema, nil
+}
+
+func (r *schemaRegistry) GetOrCreateSchema(topic string, schema *pb.Schema) ([]byte, error) {
+	lr, err := r.lookupService.Lookup(topic)
+	if err != nil {
+		return nil, err
+	}
+
+	id := r.rpcClient.NewRequestID()
+	res, err := r.rpcClient.Request(context.Background(), lr.LogicalAddr, lr.PhysicalAddr, id,
+		pb.BaseCommand_GET_OR_CREATE_SCHEMA, &pb.CommandGetOrCreateSchema{
+			RequestId: proto.Uint64(id),
+			Topic:     proto.String(topic),
+			Schema:    schema,
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	response := res.Response.GetOrCreateSchemaResponse
+	if response.ErrorCode != nil {
+		return nil, &ServerError{Code: response.GetErrorCode(), Message: response.GetErrorMessage()}
+	}
+
+	schemaVersion := response.GetSchemaVersion()
+	if key, err := newSchemaCacheKey(topic, schemaVersion); err == nil {
+		r.put(key, schema)
+	}
+	return schemaVersion, nil
+}
+
+// newSchemaCacheKey returns the cache key of the schema version, the partitions of a topic share their schemas
+func newSchemaCacheKey(topic string, schemaVersion []byte) (schemaCacheKey, error) {
+	tn, err := ParseTopicName(topic)
+	if err != nil {
+		return schemaCacheKey{}, err
+	}
+	return schemaCacheKey{
+		topic:         TopicNameWithoutPartitionPart(tn),
+		schemaVersion: string(schemaVersion),
+	}, nil
+}
+
+func (r *schemaRegistry) get(key schemaCacheKey) (*pb.Schema, bool) {
+	r.Lock()
+	defer r.Unlock()
+	elem, ok := r.entries[key]
+	if !ok {
+		return nil, false
+	}
+	r.lru.MoveToFront(elem)
+	return elem.Value.(*schemaCacheEntry).schema, true
+}
+
+func (r *schemaRegistry) put(key schemaCacheKey, schema *pb.Schema) {
+	if r.size <= 0 {
+		return
+	}
+
+	r.Lock()
+	defer r.Unlock()
+	if elem, ok := r.entries[key]; ok {
+		elem.Value.(*schemaCacheEntry).schema = schema
+		r.lru.MoveToFront(elem)
+		return
+	}
+
+	r.entries[key] = r.lru.PushFront(&schemaCacheEntry{key: key, schema: schema})
+	if r.lru.Len() > r.size {
+		oldest := r.lru.Back()
+		r.lru.Remove(oldest)
+		delete(r.entries, oldest.Value.(*schemaCacheEntry).key)
+	}
+}
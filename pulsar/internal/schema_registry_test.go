@@ -0,0 +1,129 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package internal
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+
+	pb "github.com/apache/pulsar-client-go/pulsar/internal/pulsar_proto"
+)
+
+type schemaLookupService struct {
+	countingLookupService
+	schemaRequests [][]byte
+}
+
+func (s *schemaLookupService) GetSchema(topic string, schemaVersion []byte) (*pb.Schema, error) {
+	s.schemaRequests = append(s.schemaRequests, schemaVersion)
+	return &pb.Schema{
+		Name:       proto.String(topic),
+		SchemaData: schemaVersion,
+		Type:       pb.Schema_Avro.Enum(),
+	}, nil
+}
+
+func TestSchemaRegistryCachesVersions(t *testing.T) {
+	ls := &schemaLookupService{}
+	registry := NewSchemaRegistry(ls, nil, 10)
+
+	for i := 0; i < 3; i++ {
+		schema, err := registry.GetSchema("my-topic-partition-0", []byte{0x01})
+		assert.NoError(t, err)
+		assert.Equal(t, []byte{0x01}, schema.GetSchemaData())
+	}
+	// the partitions of a topic share their schemas
+	_, err := registry.GetSchema("my-topic-partition-1", []byte{0x01})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(ls.schemaRequests))
+
+	_, err = registry.GetSchema("my-topic", []byte{0x02})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(ls.schemaRequests))
+
+	// the latest schema is always fetched
+	_, err = registry.GetSchema("my-topic", nil)
+	assert.NoError(t, err)
+	_, err = registry.GetSchema("my-topic", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, len(ls.schemaRequests))
+}
+
+func TestSchemaRegistryEvictsLeastRecentlyUsed(t *testing.T) {
+	ls := &schemaLookupService{}
+	registry := NewSchemaRegistry(ls, nil, 2)
+
+	for _, v := range []byte{0x01, 0x02, 0x01, 0x03, 0x01, 0x02} {
+		_, err := registry.GetSchema("my-topic", []byte{v})
+		assert.NoError(t, err)
+	}
+	// 0x02 was evicted by 0x03 as 0x01 was used more recently
+	assert.Equal(t, [][]byte{{0x01}, {0x02}, {0x03}, {0x02}}, ls.schemaRequests)
+}
+
+type mockedSchemaRegistryRPCClient struct {
+	mockedLookupRPCClient
+
+	requests []*pb.CommandGetOrCreateSchema
+	response *pb.CommandGetOrCreateSchemaResponse
+}
+
+func (c *mockedSchemaRegistryRPCClient) Request(ctx context.Context, logicalAddr *url.URL, physicalAddr *url.URL,
+	requestID uint64, cmdType pb.BaseCommand_Type, message proto.Message) (*RPCResult, error) {
+	assert.Equal(c.t, pb.BaseCommand_GET_OR_CREATE_SCHEMA, cmdType)
+	assert.Equal(c.t, "pulsar://broker-1:6650", logicalAddr.String())
+	c.requests = append(c.requests, message.(*pb.CommandGetOrCreateSchema))
+	return &RPCResult{
+		Response: &pb.BaseCommand{GetOrCreateSchemaResponse: c.response},
+	}, nil
+}
+
+func TestSchemaRegistryGetOrCreateSchema(t *testing.T) {
+	ls := &schemaLookupService{countingLookupService: countingLookupService{broker: "pulsar://broker-1:6650"}}
+	rpcClient := &mockedSchemaRegistryRPCClient{
+		mockedLookupRPCClient: mockedLookupRPCClient{t: t},
+		response:              &pb.CommandGetOrCreateSchemaResponse{SchemaVersion: []byte{0x05}},
+	}
+	registry := NewSchemaRegistry(ls, rpcClient, 10)
+
+	schema := &pb.Schema{Name: proto.String("my-schema"), Type: pb.Schema_Json.Enum()}
+	version, err := registry.GetOrCreateSchema("persistent://public/default/my-topic", schema)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x05}, version)
+	assert.Equal(t, schema, rpcClient.requests[0].GetSchema())
+
+	// the registered schema is cached with its version
+	cached, err := registry.GetSchema("my-topic", version)
+	assert.NoError(t, err)
+	assert.Equal(t, schema, cached)
+	assert.Empty(t, ls.schemaRequests)
+
+	rpcClient.response = &pb.CommandGetOrCreateSchemaResponse{
+		ErrorCode:    pb.ServerError_IncompatibleSchema.Enum(),
+		ErrorMessage: proto.String("incompatible schema"),
+	}
+	_, err = registry.GetOrCreateSchema("my-topic", schema)
+	var serverErr *ServerError
+	assert.True(t, errors.As(err, &serverErr))
+	assert.Equal(t, pb.ServerError_IncompatibleSchema, serverErr.Code)
+}
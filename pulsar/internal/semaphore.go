@@ -19,8 +19,6 @@ package internal
 
 import (
 	"sync/atomic"
-
-	log "github.com/sirupsen/logrus"
 )
 
 type Semaphore interface {
@@ -53,7 +51,7 @@ type semaphore struct {
 
 func NewSemaphore(maxPermits int32) Semaphore {
 	if maxPermits <= 0 {
-		log.Fatal("Max permits for semaphore needs to be > 0")
+		panic("Max permits for semaphore needs to be > 0")
 	}
 
 	return &semaphore{
@@ -22,10 +22,9 @@ import (
 	"fmt"
 	"math/rand"
 	"net/url"
+	"sync"
 	"sync/atomic"
 	"time"
-
-	log "github.com/sirupsen/logrus"
 )
 
 type ServiceNameResolver interface {
@@ -38,22 +37,31 @@ type ServiceNameResolver interface {
 }
 
 type pulsarServiceNameResolver struct {
+	// guards the service url, which can be updated while the hosts are resolved
+	sync.RWMutex
+
 	ServiceURI   *PulsarServiceURI
 	ServiceURL   *url.URL
 	CurrentIndex int32
 	AddressList  []*url.URL
 }
 
-func NewPulsarServiceNameResolver(url *url.URL) ServiceNameResolver {
+// NewPulsarServiceNameResolver returns the resolver of the hosts of the service url, without url the resolver
+// resolves no host until UpdateServiceURL is called
+func NewPulsarServiceNameResolver(url *url.URL) (ServiceNameResolver, error) {
 	r := &pulsarServiceNameResolver{}
-	err := r.UpdateServiceURL(url)
-	if err != nil {
-		log.Errorf("create pulsar service name resolver failed : %v", err)
+	if url == nil {
+		return r, nil
+	}
+	if err := r.UpdateServiceURL(url); err != nil {
+		return nil, err
 	}
-	return r
+	return r, nil
 }
 
 func (r *pulsarServiceNameResolver) ResolveHost() (*url.URL, error) {
+	r.RLock()
+	defer r.RUnlock()
 	if r.AddressList == nil {
 		return nil, errors.New("no service url is provided yet")
 	}
@@ -80,8 +88,7 @@ func (r *pulsarServiceNameResolver) ResolveHostURI() (*PulsarServiceURI, error)
 func (r *pulsarServiceNameResolver) UpdateServiceURL(u *url.URL) error {
 	uri, err := NewPulsarServiceURIFromURL(u)
 	if err != nil {
-		log.Errorf("invalid service-url instance %s provided %v", u, err)
-		return err
+		return fmt.Errorf("invalid service url %s: %v", u, err)
 	}
 
 	hosts := uri.ServiceHosts
@@ -90,11 +97,12 @@ func (r *pulsarServiceNameResolver) UpdateServiceURL(u *url.URL) error {
 		hostURL := uri.URL.Scheme + "://" + host
 		u, err := url.Parse(hostURL)
 		if err != nil {
-			log.Errorf("invalid host-url %s provided %v", hostURL, err)
-			return err
+			return fmt.Errorf("invalid host url %s: %v", hostURL, err)
 		}
 		addresses = append(addresses, u)
 	}
+	r.Lock()
+	defer r.Unlock()
 	r.AddressList = addresses
 	r.ServiceURL = u
 	r.ServiceURI = uri
@@ -104,13 +112,19 @@ func (r *pulsarServiceNameResolver) UpdateServiceURL(u *url.URL) error {
 }
 
 func (r *pulsarServiceNameResolver) GetServiceURI() *PulsarServiceURI {
+	r.RLock()
+	defer r.RUnlock()
 	return r.ServiceURI
 }
 
 func (r *pulsarServiceNameResolver) GetServiceURL() *url.URL {
+	r.RLock()
+	defer r.RUnlock()
 	return r.ServiceURL
 }
 
 func (r *pulsarServiceNameResolver) GetAddressList() []*url.URL {
+	r.RLock()
+	defer r.RUnlock()
 	return r.AddressList
 }
@@ -24,8 +24,14 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
+func newTestServiceNameResolver(t *testing.T, u *url.URL) ServiceNameResolver {
+	resolver, err := NewPulsarServiceNameResolver(u)
+	assert.NoError(t, err)
+	return resolver
+}
+
 func TestResolveBeforeUpdateServiceUrl(t *testing.T) {
-	resolver := NewPulsarServiceNameResolver(nil)
+	resolver := newTestServiceNameResolver(t, nil)
 	u, err := resolver.ResolveHost()
 	assert.Nil(t, u)
 	assert.NotNil(t, err)
@@ -33,15 +39,22 @@ func TestResolveBeforeUpdateServiceUrl(t *testing.T) {
 }
 
 func TestResolveUriBeforeUpdateServiceUrl(t *testing.T) {
-	resolver := NewPulsarServiceNameResolver(nil)
+	resolver := newTestServiceNameResolver(t, nil)
 	u, err := resolver.ResolveHostURI()
 	assert.Nil(t, u)
 	assert.NotNil(t, err)
 	assert.EqualError(t, err, "no service url is provided yet")
 }
 
+func TestNewResolverInvalidServiceUrl(t *testing.T) {
+	url, _ := url.Parse("pulsar:///")
+	resolver, err := NewPulsarServiceNameResolver(url)
+	assert.Nil(t, resolver)
+	assert.Error(t, err)
+}
+
 func TestUpdateInvalidServiceUrl(t *testing.T) {
-	resolver := NewPulsarServiceNameResolver(nil)
+	resolver := newTestServiceNameResolver(t, nil)
 	url, _ := url.Parse("pulsar:///")
 	err := resolver.UpdateServiceURL(url)
 	assert.NotNil(t, err)
@@ -50,7 +63,7 @@ func TestUpdateInvalidServiceUrl(t *testing.T) {
 }
 
 func TestSimpleHostUrl(t *testing.T) {
-	resolver := NewPulsarServiceNameResolver(nil)
+	resolver := newTestServiceNameResolver(t, nil)
 	serviceURL, _ := url.Parse("pulsar://host1:6650")
 	err := resolver.UpdateServiceURL(serviceURL)
 	assert.Nil(t, err)
@@ -77,7 +90,7 @@ func TestSimpleHostUrl(t *testing.T) {
 }
 
 func TestMultipleHostsUrl(t *testing.T) {
-	resolver := NewPulsarServiceNameResolver(nil)
+	resolver := newTestServiceNameResolver(t, nil)
 	serviceURL, _ := url.Parse("pulsar://host1:6650,host2:6650")
 	err := resolver.UpdateServiceURL(serviceURL)
 	assert.Nil(t, err)
@@ -104,7 +117,7 @@ func TestMultipleHostsUrl(t *testing.T) {
 }
 
 func TestMultipleHostsTlsUrl(t *testing.T) {
-	resolver := NewPulsarServiceNameResolver(nil)
+	resolver := newTestServiceNameResolver(t, nil)
 	serviceURL, _ := url.Parse("pulsar+ssl://host1:6651,host2:6651")
 	err := resolver.UpdateServiceURL(serviceURL)
 	assert.Nil(t, err)
@@ -23,8 +23,6 @@ import (
 	"net"
 	"net/url"
 	"strings"
-
-	log "github.com/sirupsen/logrus"
 )
 
 const (
@@ -49,7 +47,6 @@ type PulsarServiceURI struct {
 func NewPulsarServiceURIFromURIString(uri string) (*PulsarServiceURI, error) {
 	u, err := fromString(uri)
 	if err != nil {
-		log.Error(err)
 		return nil, err
 	}
 	return u, nil
@@ -58,7 +55,6 @@ func NewPulsarServiceURIFromURIString(uri string) (*PulsarServiceURI, error) {
 func NewPulsarServiceURIFromURL(url *url.URL) (*PulsarServiceURI, error) {
 	u, err := fromURL(url)
 	if err != nil {
-		log.Error(err)
 		return nil, err
 	}
 	return u, nil
@@ -20,6 +20,7 @@ package internal
 import (
 	"errors"
 	"fmt"
+	"regexp"
 	"strconv"
 	"strings"
 )
@@ -29,6 +30,8 @@ type TopicName struct {
 	Domain    string
 	Tenant    string
 	Namespace string
+	// LocalName is the name of the topic in its namespace, including the partition suffix
+	LocalName string
 	Name      string
 	Partition int
 }
@@ -39,6 +42,9 @@ const (
 	partitionedTopicSuffix = "-partition-"
 )
 
+// namedEntityPattern matches the names allowed for the tenants, clusters and namespaces
+var namedEntityPattern = regexp.MustCompile(`^[-=:.\w]+$`)
+
 // ParseTopicName parse the given topic name and return TopicName.
 func ParseTopicName(topic string) (*TopicName, error) {
 	// The topic name can be in two different forms, one is fully qualified topic name,
@@ -92,6 +98,16 @@ func ParseTopicName(topic string) (*TopicName, error) {
 	} else {
 		return nil, errors.New("Invalid topic name: " + topic)
 	}
+	for _, part := range parts[:len(parts)-1] {
+		if !namedEntityPattern.MatchString(part) {
+			return nil, fmt.Errorf("Invalid topic name: %s, illegal name '%s' in namespace %s", topic, part,
+				tn.Namespace)
+		}
+	}
+	tn.LocalName = parts[len(parts)-1]
+	if tn.LocalName == "" {
+		return nil, errors.New("Invalid topic name: " + topic + ", the local name is empty")
+	}
 
 	tn.Name = topic
 	tn.Partition, err = getPartitionIndex(topic)
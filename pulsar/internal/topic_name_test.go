@@ -74,6 +74,12 @@ func TestParseTopicName(t *testing.T) {
 	assert.Equal(t, "my-tenant", topic.Tenant)
 	assert.Equal(t, "my-tenant/my-cluster/my-ns", topic.Namespace)
 	assert.Equal(t, -1, topic.Partition)
+
+	topic, err = ParseTopicName("my.tenant/my=ns:1/my-topic-partition-2")
+	assert.Nil(t, err)
+	assert.Equal(t, "my.tenant/my=ns:1", topic.Namespace)
+	assert.Equal(t, "my-topic-partition-2", topic.LocalName)
+	assert.Equal(t, 2, topic.Partition)
 }
 
 func TestParseTopicNameErrors(t *testing.T) {
@@ -94,6 +100,18 @@ func TestParseTopicNameErrors(t *testing.T) {
 
 	_, err = ParseTopicName("persistent://my-tenant/my-cluster/my-ns/my-topic-partition-xyz/invalid")
 	assert.NotNil(t, err)
+
+	_, err = ParseTopicName("persistent://my tenant/my-ns/my-topic")
+	assert.NotNil(t, err)
+
+	_, err = ParseTopicName("persistent://my-tenant/my-ns?/my-topic")
+	assert.NotNil(t, err)
+
+	_, err = ParseTopicName("persistent://my-tenant//my-topic")
+	assert.NotNil(t, err)
+
+	_, err = ParseTopicName("persistent://my-tenant/my-ns/")
+	assert.NotNil(t, err)
 }
 
 func TestTopicNameWithoutPartitionPart(t *testing.T) {
@@ -0,0 +1,205 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+
+	pb "github.com/apache/pulsar-client-go/pulsar/internal/pulsar_proto"
+	"github.com/apache/pulsar-client-go/pulsar/log"
+)
+
+// TransactionCoordinatorAssign is the partitioned topic whose partitions are owned by the transaction coordinators,
+// the coordinator with the id N is served by the broker owning the partition N.
+const TransactionCoordinatorAssign = "persistent://pulsar/system/transaction_coordinator_assign"
+
+// ErrTransactionCoordinatorNotEnabled is returned when the cluster doesn't run any transaction coordinator
+var ErrTransactionCoordinatorNotEnabled = errors.New("the transaction coordinator is not enabled on the cluster")
+
+// TxnID identifies a transaction, the most significant bits hold the id of its coordinator
+type TxnID struct {
+	MostSigBits  uint64
+	LeastSigBits uint64
+}
+
+func (id TxnID) String() string {
+	return fmt.Sprintf("(%d,%d)", id.MostSigBits, id.LeastSigBits)
+}
+
+// TransactionCoordinatorClient sends the transaction commands to the coordinators of the cluster
+type TransactionCoordinatorClient interface {
+	// Start discovers the transaction coordinators of the cluster
+	Start() error
+
+	// NewTransaction opens a new transaction on one of the coordinators, the coordinator aborts the
+	// transaction if it isn't ended within the timeout
+	NewTransaction(ctx context.Context, timeout time.Duration) (TxnID, error)
+
+	// AddPublishPartitionToTxn registers the partitions the transaction publishes messages to
+	AddPublishPartitionToTxn(ctx context.Context, id TxnID, partitions []string) error
+
+	// AddSubscriptionToTxn registers the subscription the transaction acknowledges messages on
+	AddSubscriptionToTxn(ctx context.Context, id TxnID, topic string, subscription string) error
+
+	// EndTxn commits or aborts the transaction
+	EndTxn(ctx context.Context, id TxnID, action pb.TxnAction) error
+}
+
+type transactionCoordinatorClient struct {
+	lookupService   LookupService
+	rpcClient       RPCClient
+	numCoordinators uint64
+	nextCoordinator uint64
+	log             log.Logger
+}
+
+// NewTransactionCoordinatorClient creates a client of the transaction coordinators found with the lookup service
+func NewTransactionCoordinatorClient(lookupService LookupService, rpcClient RPCClient,
+	logger log.Logger) TransactionCoordinatorClient {
+	return &transactionCoordinatorClient{
+		lookupService: lookupService,
+		rpcClient:     rpcClient,
+		log:           logger.SubLogger(log.Fields{"topic": TransactionCoordinatorAssign}),
+	}
+}
+
+func (tc *transactionCoordinatorClient) Start() error {
+	res, err := tc.lookupService.GetPartitionedTopicMetadata(TransactionCoordinatorAssign)
+	if err != nil {
+		return err
+	}
+	if res.Error != nil {
+		return &ServerError{Code: res.GetError(), Message: res.GetMessage()}
+	}
+	if res.GetPartitions() == 0 {
+		return ErrTransactionCoordinatorNotEnabled
+	}
+
+	atomic.StoreUint64(&tc.numCoordinators, uint64(res.GetPartitions()))
+	tc.log.Infof("Found %d transaction coordinators", res.GetPartitions())
+	return nil
+}
+
+func (tc *transactionCoordinatorClient) NewTransaction(ctx context.Context, timeout time.Duration) (TxnID, error) {
+	numCoordinators := atomic.LoadUint64(&tc.numCoordinators)
+	if numCoordinators == 0 {
+		return TxnID{}, ErrTransactionCoordinatorNotEnabled
+	}
+	coordinatorID := atomic.AddUint64(&tc.nextCoordinator, 1) % numCoordinators
+
+	requestID := tc.rpcClient.NewRequestID()
+	res, err := tc.request(ctx, coordinatorID, requestID, pb.BaseCommand_NEW_TXN, &pb.CommandNewTxn{
+		RequestId:     proto.Uint64(requestID),
+		TxnTtlSeconds: proto.Uint64(uint64(timeout.Seconds())),
+		TcId:          proto.Uint64(coordinatorID),
+	})
+	if err != nil {
+		return TxnID{}, err
+	}
+
+	r := res.Response.NewTxnResponse
+	if r.Error != nil {
+		return TxnID{}, &ServerError{Code: r.GetError(), Message: r.GetMessage()}
+	}
+	return TxnID{MostSigBits: r.GetTxnidMostBits(), LeastSigBits: r.GetTxnidLeastBits()}, nil
+}
+
+func (tc *transactionCoordinatorClient) AddPublishPartitionToTxn(ctx context.Context, id TxnID,
+	partitions []string) error {
+	requestID := tc.rpcClient.NewRequestID()
+	res, err := tc.request(ctx, id.MostSigBits, requestID, pb.BaseCommand_ADD_PARTITION_TO_TXN,
+		&pb.CommandAddPartitionToTxn{
+			RequestId:      proto.Uint64(requestID),
+			TxnidMostBits:  proto.Uint64(id.MostSigBits),
+			TxnidLeastBits: proto.Uint64(id.LeastSigBits),
+			Partitions:     partitions,
+		})
+	if err != nil {
+		return err
+	}
+
+	r := res.Response.AddPartitionToTxnResponse
+	if r.Error != nil {
+		return &ServerError{Code: r.GetError(), Message: r.GetMessage()}
+	}
+	return nil
+}
+
+func (tc *transactionCoordinatorClient) AddSubscriptionToTxn(ctx context.Context, id TxnID, topic string,
+	subscription string) error {
+	requestID := tc.rpcClient.NewRequestID()
+	res, err := tc.request(ctx, id.MostSigBits, requestID, pb.BaseCommand_ADD_SUBSCRIPTION_TO_TXN,
+		&pb.CommandAddSubscriptionToTxn{
+			RequestId:      proto.Uint64(requestID),
+			TxnidMostBits:  proto.Uint64(id.MostSigBits),
+			TxnidLeastBits: proto.Uint64(id.LeastSigBits),
+			Subscription: []*pb.Subscription{{
+				Topic:        proto.String(topic),
+				Subscription: proto.String(subscription),
+			}},
+		})
+	if err != nil {
+		return err
+	}
+
+	r := res.Response.AddSubscriptionToTxnResponse
+	if r.Error != nil {
+		return &ServerError{Code: r.GetError(), Message: r.GetMessage()}
+	}
+	return nil
+}
+
+func (tc *transactionCoordinatorClient) EndTxn(ctx context.Context, id TxnID, action pb.TxnAction) error {
+	requestID := tc.rpcClient.NewRequestID()
+	res, err := tc.request(ctx, id.MostSigBits, requestID, pb.BaseCommand_END_TXN, &pb.CommandEndTxn{
+		RequestId:      proto.Uint64(requestID),
+		TxnidMostBits:  proto.Uint64(id.MostSigBits),
+		TxnidLeastBits: proto.Uint64(id.LeastSigBits),
+		TxnAction:      action.Enum(),
+	})
+	if err != nil {
+		return err
+	}
+
+	r := res.Response.EndTxnResponse
+	if r.Error != nil {
+		return &ServerError{Code: r.GetError(), Message: r.GetMessage()}
+	}
+	return nil
+}
+
+// request sends the command to the broker serving the coordinator
+func (tc *transactionCoordinatorClient) request(ctx context.Context, coordinatorID uint64, requestID uint64,
+	cmdType pb.BaseCommand_Type, message proto.Message) (*RPCResult, error) {
+	if coordinatorID >= atomic.LoadUint64(&tc.numCoordinators) {
+		return nil, fmt.Errorf("unknown transaction coordinator %d", coordinatorID)
+	}
+
+	lr, err := tc.lookupService.Lookup(fmt.Sprintf("%s%s%d", TransactionCoordinatorAssign, partitionedTopicSuffix,
+		coordinatorID))
+	if err != nil {
+		return nil, err
+	}
+	return tc.rpcClient.Request(ctx, lr.LogicalAddr, lr.PhysicalAddr, requestID, cmdType, message)
+}
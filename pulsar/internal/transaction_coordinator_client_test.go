@@ -0,0 +1,150 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package internal
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+
+	pb "github.com/apache/pulsar-client-go/pulsar/internal/pulsar_proto"
+	"github.com/apache/pulsar-client-go/pulsar/log"
+)
+
+type mockedCoordinatorLookupService struct {
+	partitions int32
+	lookups    []string
+}
+
+func (s *mockedCoordinatorLookupService) Lookup(topic string) (*LookupResult, error) {
+	s.lookups = append(s.lookups, topic)
+	addr, _ := url.Parse("pulsar://broker-1:6650")
+	return &LookupResult{LogicalAddr: addr, PhysicalAddr: addr}, nil
+}
+
+func (s *mockedCoordinatorLookupService) GetPartitionedTopicMetadata(topic string) (
+	*pb.CommandPartitionedTopicMetadataResponse, error) {
+	return &pb.CommandPartitionedTopicMetadataResponse{Partitions: proto.Uint32(uint32(s.partitions))}, nil
+}
+
+func (s *mockedCoordinatorLookupService) GetTopicsOfNamespace(namespace string,
+	mode pb.CommandGetTopicsOfNamespace_Mode) ([]string, error) {
+	return nil, nil
+}
+
+func (s *mockedCoordinatorLookupService) GetSchema(topic string, schemaVersion []byte) (*pb.Schema, error) {
+	return nil, nil
+}
+
+type mockedCoordinatorRPCClient struct {
+	mockedLookupRPCClient
+
+	requests  []proto.Message
+	responses []*pb.BaseCommand
+}
+
+func (c *mockedCoordinatorRPCClient) Request(ctx context.Context, logicalAddr *url.URL, physicalAddr *url.URL,
+	requestID uint64, cmdType pb.BaseCommand_Type, message proto.Message) (*RPCResult, error) {
+	c.requests = append(c.requests, message)
+	response := c.responses[0]
+	c.responses = c.responses[1:]
+	return &RPCResult{Response: response}, nil
+}
+
+func newMockedCoordinatorClient(partitions int32, responses ...*pb.BaseCommand) (TransactionCoordinatorClient,
+	*mockedCoordinatorLookupService, *mockedCoordinatorRPCClient) {
+	ls := &mockedCoordinatorLookupService{partitions: partitions}
+	rpc := &mockedCoordinatorRPCClient{responses: responses}
+	return NewTransactionCoordinatorClient(ls, rpc, log.DefaultNopLogger()), ls, rpc
+}
+
+func TestTransactionCoordinatorNotEnabled(t *testing.T) {
+	tc, _, _ := newMockedCoordinatorClient(0)
+	assert.Equal(t, ErrTransactionCoordinatorNotEnabled, tc.Start())
+
+	_, err := tc.NewTransaction(context.Background(), time.Minute)
+	assert.Equal(t, ErrTransactionCoordinatorNotEnabled, err)
+}
+
+func TestTransactionCoordinatorNewTransaction(t *testing.T) {
+	tc, ls, rpc := newMockedCoordinatorClient(2,
+		&pb.BaseCommand{NewTxnResponse: &pb.CommandNewTxnResponse{
+			RequestId:      proto.Uint64(1),
+			TxnidMostBits:  proto.Uint64(1),
+			TxnidLeastBits: proto.Uint64(42),
+		}},
+		&pb.BaseCommand{NewTxnResponse: &pb.CommandNewTxnResponse{
+			RequestId: proto.Uint64(2),
+			Error:     pb.ServerError_TransactionCoordinatorNotFound.Enum(),
+			Message:   proto.String("not found"),
+		}},
+	)
+	assert.NoError(t, tc.Start())
+
+	id, err := tc.NewTransaction(context.Background(), time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, TxnID{MostSigBits: 1, LeastSigBits: 42}, id)
+	assert.Equal(t, []string{TransactionCoordinatorAssign + "-partition-1"}, ls.lookups)
+	assert.Equal(t, &pb.CommandNewTxn{
+		RequestId:     proto.Uint64(1),
+		TxnTtlSeconds: proto.Uint64(60),
+		TcId:          proto.Uint64(1),
+	}, rpc.requests[0])
+
+	// the transactions are spread over the coordinators
+	_, err = tc.NewTransaction(context.Background(), time.Minute)
+	assert.Equal(t, &ServerError{Code: pb.ServerError_TransactionCoordinatorNotFound, Message: "not found"}, err)
+	assert.Equal(t, TransactionCoordinatorAssign+"-partition-0", ls.lookups[1])
+}
+
+func TestTransactionCoordinatorCommands(t *testing.T) {
+	tc, ls, rpc := newMockedCoordinatorClient(2,
+		&pb.BaseCommand{AddPartitionToTxnResponse: &pb.CommandAddPartitionToTxnResponse{}},
+		&pb.BaseCommand{AddSubscriptionToTxnResponse: &pb.CommandAddSubscriptionToTxnResponse{}},
+		&pb.BaseCommand{EndTxnResponse: &pb.CommandEndTxnResponse{
+			Error: pb.ServerError_InvalidTxnStatus.Enum(),
+		}},
+	)
+	assert.NoError(t, tc.Start())
+
+	id := TxnID{MostSigBits: 1, LeastSigBits: 7}
+	assert.NoError(t, tc.AddPublishPartitionToTxn(context.Background(), id, []string{"my-topic"}))
+	assert.Equal(t, []string{"my-topic"}, rpc.requests[0].(*pb.CommandAddPartitionToTxn).Partitions)
+
+	assert.NoError(t, tc.AddSubscriptionToTxn(context.Background(), id, "my-topic", "my-sub"))
+	sub := rpc.requests[1].(*pb.CommandAddSubscriptionToTxn).Subscription[0]
+	assert.Equal(t, "my-topic", sub.GetTopic())
+	assert.Equal(t, "my-sub", sub.GetSubscription())
+
+	err := tc.EndTxn(context.Background(), id, pb.TxnAction_COMMIT)
+	assert.Equal(t, &ServerError{Code: pb.ServerError_InvalidTxnStatus}, err)
+	assert.Equal(t, pb.TxnAction_COMMIT, rpc.requests[2].(*pb.CommandEndTxn).GetTxnAction())
+
+	// the commands are sent to the coordinator of the transaction
+	for _, topic := range ls.lookups {
+		assert.Equal(t, TransactionCoordinatorAssign+"-partition-1", topic)
+	}
+
+	// the transaction of an unknown coordinator
+	err = tc.EndTxn(context.Background(), TxnID{MostSigBits: 5}, pb.TxnAction_ABORT)
+	assert.Error(t, err)
+}
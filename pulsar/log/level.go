@@ -0,0 +1,184 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package log
+
+// Level is the minimum level of the messages a logger writes
+type Level int
+
+const (
+	// DebugLevel writes all the messages, it is the default level
+	DebugLevel Level = iota
+	// InfoLevel drops the debug messages
+	InfoLevel
+	// WarnLevel only writes the warnings and errors
+	WarnLevel
+	// ErrorLevel only writes the errors
+	ErrorLevel
+)
+
+// NewLoggerWithLevel creates a logger dropping the messages of the given logger below the level,
+// on top of the level filtering the underlying logging library may do.
+func NewLoggerWithLevel(logger Logger, level Level) Logger {
+	if level <= DebugLevel {
+		return logger
+	}
+	return &levelLogger{
+		l:     logger,
+		level: level,
+	}
+}
+
+type levelLogger struct {
+	l     Logger
+	level Level
+}
+
+func (l *levelLogger) SubLogger(fs Fields) Logger {
+	return &levelLogger{
+		l:     l.l.SubLogger(fs),
+		level: l.level,
+	}
+}
+
+func (l *levelLogger) WithFields(fs Fields) Entry {
+	return levelEntry{
+		e:     l.l.WithFields(fs),
+		level: l.level,
+	}
+}
+
+func (l *levelLogger) WithField(name string, value interface{}) Entry {
+	return levelEntry{
+		e:     l.l.WithField(name, value),
+		level: l.level,
+	}
+}
+
+func (l *levelLogger) WithError(err error) Entry {
+	return levelEntry{
+		e:     l.l.WithError(err),
+		level: l.level,
+	}
+}
+
+func (l *levelLogger) Debug(args ...interface{}) {
+	if l.level <= DebugLevel {
+		l.l.Debug(args...)
+	}
+}
+
+func (l *levelLogger) Info(args ...interface{}) {
+	if l.level <= InfoLevel {
+		l.l.Info(args...)
+	}
+}
+
+func (l *levelLogger) Warn(args ...interface{}) {
+	if l.level <= WarnLevel {
+		l.l.Warn(args...)
+	}
+}
+
+func (l *levelLogger) Error(args ...interface{}) {
+	l.l.Error(args...)
+}
+
+func (l *levelLogger) Debugf(format string, args ...interface{}) {
+	if l.level <= DebugLevel {
+		l.l.Debugf(format, args...)
+	}
+}
+
+func (l *levelLogger) Infof(format string, args ...interface{}) {
+	if l.level <= InfoLevel {
+		l.l.Infof(format, args...)
+	}
+}
+
+func (l *levelLogger) Warnf(format string, args ...interface{}) {
+	if l.level <= WarnLevel {
+		l.l.Warnf(format, args...)
+	}
+}
+
+func (l *levelLogger) Errorf(format string, args ...interface{}) {
+	l.l.Errorf(format, args...)
+}
+
+type levelEntry struct {
+	e     Entry
+	level Level
+}
+
+func (l levelEntry) WithFields(fs Fields) Entry {
+	return levelEntry{
+		e:     l.e.WithFields(fs),
+		level: l.level,
+	}
+}
+
+func (l levelEntry) WithField(name string, value interface{}) Entry {
+	return levelEntry{
+		e:     l.e.WithField(name, value),
+		level: l.level,
+	}
+}
+
+func (l levelEntry) Debug(args ...interface{}) {
+	if l.level <= DebugLevel {
+		l.e.Debug(args...)
+	}
+}
+
+func (l levelEntry) Info(args ...interface{}) {
+	if l.level <= InfoLevel {
+		l.e.Info(args...)
+	}
+}
+
+func (l levelEntry) Warn(args ...interface{}) {
+	if l.level <= WarnLevel {
+		l.e.Warn(args...)
+	}
+}
+
+func (l levelEntry) Error(args ...interface{}) {
+	l.e.Error(args...)
+}
+
+func (l levelEntry) Debugf(format string, args ...interface{}) {
+	if l.level <= DebugLevel {
+		l.e.Debugf(format, args...)
+	}
+}
+
+func (l levelEntry) Infof(format string, args ...interface{}) {
+	if l.level <= InfoLevel {
+		l.e.Infof(format, args...)
+	}
+}
+
+func (l levelEntry) Warnf(format string, args ...interface{}) {
+	if l.level <= WarnLevel {
+		l.e.Warnf(format, args...)
+	}
+}
+
+func (l levelEntry) Errorf(format string, args ...interface{}) {
+	l.e.Errorf(format, args...)
+}
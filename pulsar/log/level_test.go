@@ -0,0 +1,65 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package log
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingLogger keeps the messages written by the logger and its entries
+type recordingLogger struct {
+	nopLogger
+	messages *[]string
+}
+
+func (l recordingLogger) SubLogger(fields Fields) Logger { return l }
+func (l recordingLogger) WithField(name string, value interface{}) Entry {
+	return recordingEntry{messages: l.messages}
+}
+func (l recordingLogger) Debug(args ...interface{}) { *l.messages = append(*l.messages, "debug") }
+func (l recordingLogger) Info(args ...interface{})  { *l.messages = append(*l.messages, "info") }
+func (l recordingLogger) Warn(args ...interface{})  { *l.messages = append(*l.messages, "warn") }
+func (l recordingLogger) Error(args ...interface{}) { *l.messages = append(*l.messages, "error") }
+
+type recordingEntry struct {
+	nopEntry
+	messages *[]string
+}
+
+func (e recordingEntry) Info(args ...interface{}) { *e.messages = append(*e.messages, "entry info") }
+func (e recordingEntry) Warn(args ...interface{}) { *e.messages = append(*e.messages, "entry warn") }
+
+func TestLoggerWithLevel(t *testing.T) {
+	var messages []string
+	logger := NewLoggerWithLevel(recordingLogger{messages: &messages}, WarnLevel).SubLogger(Fields{"topic": "t"})
+
+	logger.Debug("a")
+	logger.Info("b")
+	logger.Warn("c")
+	logger.Error("d")
+	logger.WithField("cnx", "1").Info("e")
+	logger.WithField("cnx", "1").Warn("f")
+	assert.Equal(t, []string{"warn", "error", "entry warn"}, messages)
+}
+
+func TestLoggerWithDefaultLevel(t *testing.T) {
+	logger := DefaultNopLogger()
+	assert.Equal(t, logger, NewLoggerWithLevel(logger, DebugLevel))
+}
@@ -49,6 +49,9 @@ type ProducerMessage struct {
 	// ReplicationClusters override the replication clusters for this message.
 	ReplicationClusters []string
 
+	// DisableReplication disables the replication for this message
+	DisableReplication bool
+
 	// SequenceID set the sequence id to assign to the current message
 	SequenceID *int64
 
@@ -63,6 +66,10 @@ type ProducerMessage struct {
 	//     through a `SubscriptionType=Shared` subscription. With other subscription
 	//     types, the messages will still be delivered immediately.
 	DeliverAt time.Time
+
+	// Publish the message within the transaction, the message is visible to the consumers once the
+	// transaction is committed. It requires a transaction created by the client of the producer.
+	Transaction Transaction
 }
 
 // Message abstraction used in Pulsar
@@ -80,6 +87,10 @@ type Message interface {
 	// Payload get the payload of the message
 	Payload() []byte
 
+	// Release gives the buffer of the payload back to the pool when the consumer was created with ReleasePayload,
+	// the payload must not be used afterwards. It does nothing otherwise.
+	Release()
+
 	// ID get the unique message ID associated with this message.
 	// The message id can be used to univocally refer to a message without having the keep the entire payload in memory.
 	ID() MessageID
@@ -120,19 +131,53 @@ type Message interface {
 type MessageID interface {
 	// Serialize the message id into a sequence of bytes that can be stored somewhere else
 	Serialize() []byte
+
+	// LedgerID returns the id of the ledger storing the message
+	LedgerID() int64
+
+	// EntryID returns the id of the ledger entry storing the message
+	EntryID() int64
+
+	// BatchIdx returns the index of the message in its batch, -1 if the message isn't batched
+	BatchIdx() int32
+
+	// PartitionIdx returns the index of the topic partition of the message, -1 if the topic isn't partitioned
+	PartitionIdx() int32
+
+	// String returns the message id in the "ledger:entry:batch:partition" form parsed by ParseMessageID
+	String() string
+
+	// Less checks whether the message id is ordered before the other one
+	Less(MessageID) bool
+
+	// Equal checks whether the message id identifies the same message as the other one
+	Equal(MessageID) bool
 }
 
-// DeserializeMessageID reconstruct a MessageID object from its serialized representation
+// ParseMessageID parses a message id in the "ledger:entry:batch:partition" form returned by MessageID.String
+func ParseMessageID(s string) (MessageID, error) {
+	return parseMessageID(s)
+}
+
+// DeserializeMessageID reconstruct a MessageID object from its serialized representation, eg. to restart a
+// Reader or Seek a consumer from an id persisted with MessageID.Serialize by this or another Pulsar client
 func DeserializeMessageID(data []byte) (MessageID, error) {
 	return deserializeMessageID(data)
 }
 
-// EarliestMessageID returns a messageID that points to the earliest message available in a topic
+var (
+	earliestMessageID = newMessageID(-1, -1, -1, -1)
+	latestMessageID   = newMessageID(math.MaxInt64, math.MaxInt64, -1, -1)
+)
+
+// EarliestMessageID returns a messageID that points to the earliest message available in a topic,
+// it is ordered before the id of any message of the topic
 func EarliestMessageID() MessageID {
-	return newMessageID(-1, -1, -1, -1)
+	return earliestMessageID
 }
 
-// LatestMessage returns a messageID that points to the latest message
+// LatestMessageID returns a messageID that points to the latest message available in a topic,
+// it is ordered after the id of any message of the topic
 func LatestMessageID() MessageID {
-	return newMessageID(math.MaxInt64, math.MaxInt64, -1, -1)
+	return latestMessageID
 }
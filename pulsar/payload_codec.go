@@ -0,0 +1,73 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package pulsar
+
+// PayloadCodecProperty is the message property naming the codec the payload was encoded with
+const PayloadCodecProperty = "pulsar.payload.codec"
+
+// PayloadCodec encodes the payloads of the messages, eg. for the encryption or compression schemes not built
+// in Pulsar. The producer encodes the payload of each message and names the codec in its PayloadCodecProperty,
+// the consumers decode the payloads with the codec of the same name.
+type PayloadCodec interface {
+	// Name identifies the codec in the messages, it must be the same for the producers and consumers
+	Name() string
+
+	// Encode returns the encoded payload of a message
+	Encode(payload []byte) ([]byte, error)
+
+	// Decode returns the payload of a message from its encoded payload
+	Decode(payload []byte) ([]byte, error)
+}
+
+// encodePayload encodes the payload with the codec, the properties of the message are copied to name the codec
+func encodePayload(codec PayloadCodec, payload []byte, properties map[string]string) ([]byte,
+	map[string]string, error) {
+	encoded, err := codec.Encode(payload)
+	if err != nil {
+		return nil, nil, wrapError(PayloadCodecError, "failed to encode the message payload", err)
+	}
+
+	codecProperties := make(map[string]string, len(properties)+1)
+	for k, v := range properties {
+		codecProperties[k] = v
+	}
+	codecProperties[PayloadCodecProperty] = codec.Name()
+	return encoded, codecProperties, nil
+}
+
+// decodePayload decodes the payload of the message with the codec named in its properties, the property is
+// removed once decoded. The messages without the property are left as is.
+func decodePayload(codecs []PayloadCodec, msg *message) error {
+	name, ok := msg.properties[PayloadCodecProperty]
+	if !ok {
+		return nil
+	}
+	for _, codec := range codecs {
+		if codec.Name() != name {
+			continue
+		}
+		payload, err := codec.Decode(msg.payLoad)
+		if err != nil {
+			return wrapError(PayloadCodecError, "failed to decode the message payload", err)
+		}
+		msg.payLoad = payload
+		delete(msg.properties, PayloadCodecProperty)
+		return nil
+	}
+	return newError(PayloadCodecError, "no payload codec named "+name)
+}
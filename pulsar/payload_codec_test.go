@@ -0,0 +1,79 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package pulsar
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// reverseCodec reverses the payloads, it fails on the empty ones
+type reverseCodec struct{}
+
+func (reverseCodec) Name() string {
+	return "reverse"
+}
+
+func (reverseCodec) Encode(payload []byte) ([]byte, error) {
+	return reverseCodec{}.Decode(payload)
+}
+
+func (reverseCodec) Decode(payload []byte) ([]byte, error) {
+	if len(payload) == 0 {
+		return nil, errors.New("empty payload")
+	}
+	reversed := make([]byte, len(payload))
+	for i, b := range payload {
+		reversed[len(payload)-1-i] = b
+	}
+	return reversed, nil
+}
+
+func TestPayloadCodec(t *testing.T) {
+	properties := map[string]string{"a": "1"}
+	encoded, encodedProperties, err := encodePayload(reverseCodec{}, []byte("hello"), properties)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("olleh"), encoded)
+	assert.Equal(t, map[string]string{"a": "1", PayloadCodecProperty: "reverse"}, encodedProperties)
+	// the properties of the application are left untouched
+	assert.Equal(t, map[string]string{"a": "1"}, properties)
+
+	_, _, err = encodePayload(reverseCodec{}, nil, nil)
+	var e *Error
+	assert.True(t, errors.As(err, &e))
+	assert.Equal(t, PayloadCodecError, e.Result())
+
+	msg := &message{payLoad: encoded, properties: encodedProperties}
+	assert.Nil(t, decodePayload([]PayloadCodec{reverseCodec{}}, msg))
+	assert.Equal(t, []byte("hello"), msg.payLoad)
+	assert.Equal(t, map[string]string{"a": "1"}, msg.properties)
+
+	// the messages without the property are left as is
+	assert.Nil(t, decodePayload([]PayloadCodec{reverseCodec{}}, msg))
+	assert.Equal(t, []byte("hello"), msg.payLoad)
+
+	// the messages encoded with an unknown codec keep their payload and property
+	msg = &message{payLoad: encoded, properties: map[string]string{PayloadCodecProperty: "other"}}
+	err = decodePayload([]PayloadCodec{reverseCodec{}}, msg)
+	assert.True(t, errors.As(err, &e))
+	assert.Equal(t, PayloadCodecError, e.Result())
+	assert.Equal(t, []byte("olleh"), msg.payLoad)
+	assert.Equal(t, "other", msg.properties[PayloadCodecProperty])
+}
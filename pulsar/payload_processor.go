@@ -0,0 +1,195 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package pulsar
+
+import (
+	"fmt"
+
+	"github.com/apache/pulsar-client-go/pulsar/internal"
+	pb "github.com/apache/pulsar-client-go/pulsar/internal/pulsar_proto"
+)
+
+// MessagePayloadProcessor decodes the entries received by a consumer into messages, eg. to consume the
+// topics written in a custom entry format. It is called from the goroutine of the connection and shouldn't
+// block.
+type MessagePayloadProcessor interface {
+	// Process decodes the uncompressed payload of an entry and passes the messages built with the context
+	// to consume, in order. An error discards the whole entry. The messages of the entry which aren't passed
+	// to consume are acked, a message passed twice is dropped.
+	Process(payload []byte, ctx MessagePayloadContext, consume func(Message)) error
+}
+
+// MessagePayloadContext describes the entry being processed and builds its messages
+type MessagePayloadContext interface {
+	// Topic returns the topic of the entry
+	Topic() string
+
+	// Properties returns the properties of the entry metadata, eg. to tell the entry formats apart
+	Properties() map[string]string
+
+	// IsBatch returns true when the entry holds a batch of messages
+	IsBatch() bool
+
+	// NumMessages returns the number of messages of the entry given by its metadata
+	NumMessages() int
+
+	// NewMessage builds the message at the index of the entry, between 0 and NumMessages()-1, with the
+	// given payload, key and properties. The other fields, like the publish time, come from the entry metadata.
+	NewMessage(index int, payload []byte, key string, properties map[string]string) (Message, error)
+
+	// DefaultProcess decodes the entry in the standard Pulsar format, eg. for the entries the processor doesn't
+	// handle. It may only be called once per entry.
+	DefaultProcess(consume func(Message)) error
+}
+
+type payloadContext struct {
+	pc          *partitionConsumer
+	reader      *internal.MessageReader
+	response    *pb.CommandMessage
+	msgMeta     *pb.MessageMetadata
+	schema      Schema
+	numMessages int
+	ackTracker  *ackTracker
+	// the indexes of the messages passed to consume
+	consumed []bool
+	// the pooled buffer the payloads are sliced from, nil unless the consumer releases the payloads
+	payload *sharedPayload
+}
+
+func (c *payloadContext) Topic() string {
+	return c.pc.topic
+}
+
+func (c *payloadContext) Properties() map[string]string {
+	return internal.ConvertToStringMap(c.msgMeta.GetProperties())
+}
+
+func (c *payloadContext) IsBatch() bool {
+	return c.msgMeta.NumMessagesInBatch != nil
+}
+
+func (c *payloadContext) NumMessages() int {
+	return c.numMessages
+}
+
+func (c *payloadContext) NewMessage(index int, payload []byte, key string,
+	properties map[string]string) (Message, error) {
+	if index < 0 || index >= c.numMessages {
+		return nil, fmt.Errorf("message index %d out of the %d messages of the entry", index, c.numMessages)
+	}
+	msg := c.newMessage(index, payload)
+	msg.key = key
+	msg.properties = properties
+	return msg, nil
+}
+
+func (c *payloadContext) DefaultProcess(consume func(Message)) error {
+	for i := 0; i < c.numMessages; i++ {
+		smm, payload, err := c.reader.ReadMessage()
+		if err != nil {
+			return err
+		}
+
+		msg := c.newMessage(i, payload)
+		if smm != nil {
+			msg.eventTime = timeFromUnixTimestampMillis(smm.GetEventTime())
+			msg.key = smm.GetPartitionKey()
+			msg.orderingKey = string(smm.GetOrderingKey())
+			msg.properties = internal.ConvertToStringMap(smm.GetProperties())
+		} else {
+			msg.eventTime = timeFromUnixTimestampMillis(c.msgMeta.GetEventTime())
+			msg.key = c.msgMeta.GetPartitionKey()
+			msg.orderingKey = string(c.msgMeta.GetOrderingKey())
+			msg.properties = internal.ConvertToStringMap(c.msgMeta.GetProperties())
+		}
+		consume(msg)
+	}
+	return nil
+}
+
+func (c *payloadContext) messageID(index int) trackingMessageID {
+	pbMsgID := c.response.GetMessageId()
+	msgID := newTrackingMessageID(
+		int64(pbMsgID.GetLedgerId()),
+		int64(pbMsgID.GetEntryId()),
+		int32(index),
+		c.pc.partitionIdx,
+		c.ackTracker)
+	// set the consumer so we know how to ack the message id
+	msgID.consumer = c.pc
+	return msgID
+}
+
+func (c *payloadContext) newMessage(index int, payload []byte) *message {
+	if c.pc.options.copyPayload {
+		payload = append([]byte(nil), payload...)
+	}
+	c.pc.metrics.BytesReceived.Add(float64(len(payload)))
+
+	msgID := c.messageID(index)
+
+	if c.payload != nil {
+		c.payload.retain()
+	}
+	return &message{
+		sharedPayload:       c.payload,
+		publishTime:         timeFromUnixTimestampMillis(c.msgMeta.GetPublishTime()),
+		producerName:        c.msgMeta.GetProducerName(),
+		topic:               c.pc.topic,
+		msgID:               msgID,
+		payLoad:             payload,
+		schema:              c.schema,
+		replicationClusters: c.msgMeta.GetReplicateTo(),
+		replicatedFrom:      c.msgMeta.GetReplicatedFrom(),
+		redeliveryCount:     c.response.GetRedeliveryCount(),
+	}
+}
+
+// markConsumed records the message passed to consume, it returns false when it already was
+func (c *payloadContext) markConsumed(msg *message) bool {
+	if c.consumed == nil {
+		c.consumed = make([]bool, c.numMessages)
+	}
+	index := msg.msgID.(trackingMessageID).batchIdx
+	if c.consumed[index] {
+		return false
+	}
+	c.consumed[index] = true
+	return true
+}
+
+// ackNotConsumed acks the messages of the entry the processor didn't pass to consume, so that the entry is
+// acked once the consumed messages are
+func (c *payloadContext) ackNotConsumed() {
+	for i := 0; i < c.numMessages; i++ {
+		if c.consumed != nil && c.consumed[i] {
+			continue
+		}
+		if msgID := c.messageID(i); msgID.ack() {
+			c.pc.queueAck(msgID)
+		}
+	}
+}
+
+// owns returns true if the message was built by the context
+func (c *payloadContext) owns(msg *message) bool {
+	msgID, ok := msg.msgID.(trackingMessageID)
+	return ok && msgID.consumer == c.pc && msgID.tracker == c.ackTracker &&
+		msgID.ledgerID == int64(c.response.GetMessageId().GetLedgerId()) &&
+		msgID.entryID == int64(c.response.GetMessageId().GetEntryId())
+}
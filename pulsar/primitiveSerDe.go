@@ -29,8 +29,9 @@ const (
 	maxBorrowSize = 10
 )
 
+// the primitive values are encoded in network byte order, like the Java client does
 var (
-	littleEndian = binary.LittleEndian
+	bigEndian = binary.BigEndian
 )
 
 type BinaryFreeList chan []byte
@@ -232,7 +233,7 @@ func readElement(r io.Reader, element interface{}) error {
 		return nil
 
 	case *int16:
-		rv, err := BinarySerializer.Uint16(r, littleEndian)
+		rv, err := BinarySerializer.Uint16(r, bigEndian)
 		if err != nil {
 			return err
 		}
@@ -240,7 +241,7 @@ func readElement(r io.Reader, element interface{}) error {
 		return nil
 
 	case *int32:
-		rv, err := BinarySerializer.Uint32(r, littleEndian)
+		rv, err := BinarySerializer.Uint32(r, bigEndian)
 		if err != nil {
 			return err
 		}
@@ -248,7 +249,7 @@ func readElement(r io.Reader, element interface{}) error {
 		return nil
 
 	case *int64:
-		rv, err := BinarySerializer.Uint64(r, littleEndian)
+		rv, err := BinarySerializer.Uint64(r, bigEndian)
 		if err != nil {
 			return err
 		}
@@ -267,7 +268,7 @@ func readElement(r io.Reader, element interface{}) error {
 		}
 		return nil
 	}
-	return binary.Read(r, littleEndian, element)
+	return binary.Read(r, bigEndian, element)
 }
 
 func writeElement(w io.Writer, element interface{}) error {
@@ -280,21 +281,21 @@ func writeElement(w io.Writer, element interface{}) error {
 		return nil
 
 	case int16:
-		err := BinarySerializer.PutUint16(w, littleEndian, uint16(e))
+		err := BinarySerializer.PutUint16(w, bigEndian, uint16(e))
 		if err != nil {
 			return err
 		}
 		return nil
 
 	case int32:
-		err := BinarySerializer.PutUint32(w, littleEndian, uint32(e))
+		err := BinarySerializer.PutUint32(w, bigEndian, uint32(e))
 		if err != nil {
 			return err
 		}
 		return nil
 
 	case int64:
-		err := BinarySerializer.PutUint64(w, littleEndian, uint64(e))
+		err := BinarySerializer.PutUint64(w, bigEndian, uint64(e))
 		if err != nil {
 			return err
 		}
@@ -312,5 +313,5 @@ func writeElement(w io.Writer, element interface{}) error {
 		}
 		return nil
 	}
-	return binary.Write(w, littleEndian, element)
+	return binary.Write(w, bigEndian, element)
 }
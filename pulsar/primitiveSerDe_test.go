@@ -33,17 +33,17 @@ func TestWriteElements(t *testing.T) {
 	}{
 		{int8(1), []byte{0x01}},
 		{uint8(2), []byte{0x02}},
-		{int16(4), []byte{0x04, 0x00}},
-		{uint16(16), []byte{0x10, 0x00}},
-		{int32(1), []byte{0x01, 0x00, 0x00, 0x00}},
-		{uint32(256), []byte{0x00, 0x01, 0x00, 0x00}},
+		{int16(4), []byte{0x00, 0x04}},
+		{uint16(16), []byte{0x00, 0x10}},
+		{int32(1), []byte{0x00, 0x00, 0x00, 0x01}},
+		{uint32(256), []byte{0x00, 0x00, 0x01, 0x00}},
 		{
 			int64(65536),
-			[]byte{0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00},
+			[]byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00},
 		},
 		{
 			uint64(4294967296),
-			[]byte{0x00, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00},
+			[]byte{0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00},
 		},
 		{
 			true,
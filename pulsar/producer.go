@@ -31,6 +31,101 @@ const (
 	Murmur3_32Hash
 )
 
+// MessageDeduplication is how a producer relies on the deduplication of its messages by the broker
+type MessageDeduplication int
+
+const (
+	// DefaultMessageDeduplication uses the deduplication configured on the broker without checking it
+	DefaultMessageDeduplication MessageDeduplication = iota
+	// GuaranteedMessageDeduplication checks that the broker deduplicates the messages of the topic, for
+	// exactly-once publishing, and fails the creation of the producer with ErrDeduplicationNotEnabled otherwise.
+	// The producer needs a Name, the messages are deduplicated by producer name and sequence id. The broker
+	// resuming the sequence ids of a producer name which already published proves the deduplication, otherwise
+	// the deduplication applied to the topic is queried through the admin API, which requires an http:// or
+	// https:// service URL and the permission to read the topic policies.
+	// The binary protocol doesn't expose the deduplication of a topic: with a pulsar:// or pulsar+ssl://
+	// service URL, only a producer name which already published on the topic can be created, the creation of
+	// a new one fails with InvalidConfiguration.
+	GuaranteedMessageDeduplication
+)
+
+// ProducerAccessMode is the access a producer requires on its topic
+type ProducerAccessMode int
+
+const (
+	// ProducerAccessModeShared lets several producers publish on the topic
+	ProducerAccessModeShared ProducerAccessMode = iota
+	// ProducerAccessModeExclusive requires the producer to be the only one of the topic, its creation fails with
+	// ProducerFenced when another producer is already connected
+	ProducerAccessModeExclusive
+	// ProducerAccessModeWaitForExclusive queues the creation of the producer until it gets the exclusive access
+	// to the topic, eg. for an application electing a leader among its instances
+	ProducerAccessModeWaitForExclusive
+)
+
+// ErrMessageTooLarge is the error of a message which, once compressed and serialized, exceeds the max message
+// size advertised by the broker
+var ErrMessageTooLarge = newError(MessageTooBig, "message size exceeds MaxMessageSize")
+
+// ErrDeduplicationNotEnabled is the error of a producer created with GuaranteedMessageDeduplication on a topic
+// whose namespace does not deduplicate the messages
+var ErrDeduplicationNotEnabled = newError(InvalidConfiguration, "message deduplication is not enabled on the namespace")
+
+// ErrPublishRateExceeded is the error of a message exceeding the PublishRateLimit of a producer which does not
+// block when its queue is full
+var ErrPublishRateExceeded = newError(ProducerBlockedQuotaExceededError, "publish rate limit exceeded")
+
+// PublishRateLimit is the max rate at which a producer publishes, a zero rate is not limited
+type PublishRateLimit struct {
+	// MessagesPerSecond is the max number of messages published per second
+	MessagesPerSecond int
+
+	// BytesPerSecond is the max number of payload bytes published per second
+	BytesPerSecond int
+}
+
+// SendErrorAction is what a producer does with a message the broker failed to persist
+type SendErrorAction int
+
+const (
+	// SendErrorFail completes the send of the message with the error of the broker
+	SendErrorFail SendErrorAction = iota
+
+	// SendErrorRetry publishes the message again
+	SendErrorRetry
+)
+
+// SendErrorHandler decides what a producer does with a message of a batch the broker failed to persist, eg. on a
+// checksum error or when the producer is not allowed to publish anymore. It is called with the number of times the
+// broker failed to persist the message.
+type SendErrorHandler func(msg *ProducerMessage, failures int, err error) SendErrorAction
+
+// FailOnSendError fails the messages the broker failed to persist
+func FailOnSendError() SendErrorHandler {
+	return func(msg *ProducerMessage, failures int, err error) SendErrorAction {
+		return SendErrorFail
+	}
+}
+
+// RetryOnSendError publishes again, at most maxRetries times, the messages the broker failed to persist
+func RetryOnSendError(maxRetries int) SendErrorHandler {
+	return func(msg *ProducerMessage, failures int, err error) SendErrorAction {
+		if failures <= maxRetries {
+			return SendErrorRetry
+		}
+		return SendErrorFail
+	}
+}
+
+// DeadLetterOnSendError hands the messages the broker failed to persist to the deadLetter function, eg. to store them
+// locally, before failing them
+func DeadLetterOnSendError(deadLetter func(msg *ProducerMessage, err error)) SendErrorHandler {
+	return func(msg *ProducerMessage, failures int, err error) SendErrorAction {
+		deadLetter(msg, err)
+		return SendErrorFail
+	}
+}
+
 type CompressionType int
 
 const (
@@ -76,11 +171,25 @@ type ProducerOptions struct {
 	// This properties will be visible in the topic stats
 	Properties map[string]string
 
+	// AccessMode is the access the producer requires on the topic (default ProducerAccessModeShared)
+	AccessMode ProducerAccessMode
+
+	// WaitingForExclusiveHandler is called with the topic partition when the broker queued the creation of a
+	// producer in ProducerAccessModeWaitForExclusive, behind the producer holding the exclusive access. The creation
+	// then waits, without the operation and creation timeouts, until the producer gets the access or the context
+	// given to CreateProducerWithCtx is done.
+	WaitingForExclusiveHandler func(topic string)
+
 	// SendTimeout set the timeout for a message that not be acknowledged by server since sent.
 	// Send and SendAsync returns an error after timeout.
 	// Default is 30 seconds, negative such as -1 to disable.
 	SendTimeout time.Duration
 
+	// SendErrorHandler decides whether each message of a batch the broker failed to persist, with a SEND_ERROR, is
+	// published again or fails. The built-in handlers are FailOnSendError, RetryOnSendError and
+	// DeadLetterOnSendError. Default is to fail the messages.
+	SendErrorHandler SendErrorHandler
+
 	// DisableBlockIfQueueFull control whether Send and SendAsync block if producer's message queue is full.
 	// Default is false, if set to true then Send and SendAsync return error when queue is full.
 	DisableBlockIfQueueFull bool
@@ -89,6 +198,12 @@ type ProducerOptions struct {
 	// acknowledgment from the broker.
 	MaxPendingMessages int
 
+	// PublishRateLimit limits the messages and the payload bytes the producer publishes per second, over all the
+	// partitions, so the producer stays within the quotas of the broker instead of being throttled by it.
+	// Send and SendAsync wait until the message fits in the limit, or fail with ErrPublishRateExceeded when
+	// DisableBlockIfQueueFull is set. Default is no limit.
+	PublishRateLimit PublishRateLimit
+
 	// HashingScheme change the `HashingScheme` used to chose the partition on where to publish a particular message.
 	// Standard hashing functions available are:
 	//
@@ -96,6 +211,11 @@ type ProducerOptions struct {
 	//  - `Murmur3_32Hash` : Use Murmur3 hashing function.
 	// 		https://en.wikipedia.org/wiki/MurmurHash">https://en.wikipedia.org/wiki/MurmurHash
 	//
+	// Both route a key to the same partition as the Java client configured with the same hashing scheme.
+	// Breaking change: `JavaStringHash` used to hash the UTF-8 bytes of the key without masking the sign bit, the
+	// keys with non-ASCII characters or a negative Java hash code are now routed to other partitions than by the
+	// previous versions, producers of both versions publishing the same keys don't keep their order.
+	//
 	// Default is `JavaStringHash`.
 	HashingScheme
 
@@ -145,14 +265,37 @@ type ProducerOptions struct {
 	// BatchingMaxMessages (see above) has been reached or the batch interval has elapsed.
 	BatchingMaxSize uint
 
+	// BatchingPartitionSwitchFrequencyByPublishDelay sets for how many BatchingMaxPublishDelay windows the default
+	// router keeps sending the messages without key to the same partition before switching to the next one,
+	// unless a batch fills up earlier. (default: 1)
+	BatchingPartitionSwitchFrequencyByPublishDelay int
+
 	// A chain of interceptors, These interceptors will be called at some points defined in ProducerInterceptor interface
 	Interceptors ProducerInterceptors
 
+	// MessageDeduplication sets whether the creation of the producer checks that the broker deduplicates the
+	// messages. Default is DefaultMessageDeduplication, without any check.
+	MessageDeduplication MessageDeduplication
+
 	Schema Schema
 
+	// PayloadCodec encodes the payload of each message, after the schema, and names the codec in the
+	// PayloadCodecProperty of the message for the consumers to decode it.
+	// Default is nil, the payloads are sent as is.
+	PayloadCodec PayloadCodec
+
 	// MaxReconnectToBroker set the maximum retry number of reconnectToBroker. (default: ultimate)
+	// Once the attempts are exhausted the producer of the partition is closed and ReconnectFailedHandler is called.
 	MaxReconnectToBroker *uint
 
+	// ReconnectFailedHandler is called with the topic partition and the last error when the producer gave up
+	// reconnecting to the broker after MaxReconnectToBroker attempts
+	ReconnectFailedHandler func(topic string, err error)
+
+	// BackoffPolicy creates the backoff policy between the attempts to reconnect the producer to the broker.
+	// (default: the BackoffPolicy of the client)
+	BackoffPolicy func() BackoffPolicy
+
 	// BatcherBuilderType sets the batch builder type (default DefaultBatchBuilder)
 	// This will be used to create batch container when batching is enabled.
 	// Options:
@@ -171,6 +314,8 @@ type Producer interface {
 
 	// Send a message
 	// This call will be blocking until is successfully acknowledged by the Pulsar broker.
+	// It returns the MessageID assigned by the broker: the ledger and entry of the receipt, with the partition
+	// the message was routed to and its index in the batch, the same as the consumers receive.
 	// Example:
 	// producer.Send(ctx, pulsar.ProducerMessage{ Payload: myPayload })
 	Send(context.Context, *ProducerMessage) (MessageID, error)
@@ -194,8 +339,43 @@ type Producer interface {
 	// persisted.
 	Flush() error
 
+	// FlushWithCtx flushes the messages like Flush, it returns the context error if the context is done before
+	// the messages are persisted
+	FlushWithCtx(context.Context) error
+
 	// Close the producer and releases resources allocated
-	// No more writes will be accepted from this producer. Waits until all pending write request are persisted. In case
-	// of errors, pending writes will not be retried.
+	// No more writes will be accepted from this producer. The current batch is flushed and Close waits, at most for
+	// the SendTimeout, until all pending write requests are persisted. The writes which are still pending then fail.
 	Close()
+
+	// CloseForcefully closes the producer without waiting for the pending write requests, they fail immediately
+	CloseForcefully()
+
+	// CloseWithCtx closes the producer, it returns the context error if the context is done before the close
+	// completes. The producer is closed anyway.
+	CloseWithCtx(context.Context) error
+
+	// BrokerAddress returns the address of the broker the producer is connected to, the distinct addresses
+	// of the brokers are comma separated when the producer has several partitions.
+	BrokerAddress() string
+
+	// ConnectedSince returns since when the producer is connected to its brokers, or the zero time while any of
+	// its partitions is disconnected.
+	ConnectedSince() time.Time
+
+	// LastDisconnectedTimestamp returns when the producer lost a connection to a broker for the last time,
+	// or the zero time if it never did.
+	LastDisconnectedTimestamp() time.Time
+
+	// LastSendTimestamp returns when the application sent a message for the last time, with Send or SendAsync,
+	// or the zero time if it never did.
+	LastSendTimestamp() time.Time
+
+	// PendingMessages returns the number of messages sent by the application that the broker didn't persist
+	// yet, including the ones still batched in the producer. Eg. it tells whether closing the producer waits.
+	PendingMessages() int
+
+	// PendingBytes returns the payload bytes of the pending messages of each partition of the topic, indexed by
+	// partition. A non-partitioned topic has a single value.
+	PendingBytes() []int64
 }
@@ -19,6 +19,7 @@ package pulsar
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -55,6 +56,8 @@ type producer struct {
 	tickerStop    chan struct{}
 	log           log.Logger
 	metrics       *internal.TopicMetrics
+	messagesRate  internal.RateLimiter
+	bytesRate     internal.RateLimiter
 }
 
 var partitionsAutoDiscoveryInterval = 1 * time.Minute
@@ -70,10 +73,17 @@ func getHashingFunction(s HashingScheme) func(string) uint32 {
 	}
 }
 
-func newProducer(client *client, options *ProducerOptions) (*producer, error) {
+func newProducer(ctx context.Context, client *client, options *ProducerOptions) (*producer, error) {
 	if options.Topic == "" {
 		return nil, newError(InvalidTopicName, "Topic name is required for producer")
 	}
+	tns, err := validateTopicNames(options.Topic)
+	if err != nil {
+		return nil, err
+	}
+	if options.MessageDeduplication == GuaranteedMessageDeduplication && options.Name == "" {
+		return nil, newError(InvalidConfiguration, "guaranteed message deduplication requires a producer name")
+	}
 
 	if options.SendTimeout == 0 {
 		options.SendTimeout = defaultSendTimeout
@@ -87,6 +97,18 @@ func newProducer(client *client, options *ProducerOptions) (*producer, error) {
 	if options.BatchingMaxPublishDelay <= 0 {
 		options.BatchingMaxPublishDelay = defaultBatchingMaxPublishDelay
 	}
+	if options.BatchingPartitionSwitchFrequencyByPublishDelay < 0 {
+		return nil, newError(InvalidConfiguration, "the batching partition switch frequency must not be negative")
+	}
+	if options.BatchingPartitionSwitchFrequencyByPublishDelay == 0 {
+		options.BatchingPartitionSwitchFrequencyByPublishDelay = 1
+	}
+	if options.BackoffPolicy == nil {
+		options.BackoffPolicy = client.backoffPolicy
+	}
+	if options.PublishRateLimit.MessagesPerSecond < 0 || options.PublishRateLimit.BytesPerSecond < 0 {
+		return nil, newError(InvalidConfiguration, "the publish rate limit must not be negative")
+	}
 
 	p := &producer{
 		options: options,
@@ -95,6 +117,12 @@ func newProducer(client *client, options *ProducerOptions) (*producer, error) {
 		log:     client.log.SubLogger(log.Fields{"topic": options.Topic}),
 		metrics: client.metrics.GetTopicMetrics(options.Topic),
 	}
+	if options.PublishRateLimit.MessagesPerSecond > 0 {
+		p.messagesRate = internal.NewRateLimiter(options.PublishRateLimit.MessagesPerSecond)
+	}
+	if options.PublishRateLimit.BytesPerSecond > 0 {
+		p.bytesRate = internal.NewRateLimiter(options.PublishRateLimit.BytesPerSecond)
+	}
 
 	if options.Interceptors == nil {
 		options.Interceptors = defaultProducerInterceptors
@@ -105,7 +133,7 @@ func newProducer(client *client, options *ProducerOptions) (*producer, error) {
 			getHashingFunction(options.HashingScheme),
 			options.BatchingMaxMessages,
 			options.BatchingMaxSize,
-			options.BatchingMaxPublishDelay,
+			options.BatchingMaxPublishDelay*time.Duration(options.BatchingPartitionSwitchFrequencyByPublishDelay),
 			options.DisableBatching)
 		p.messageRouter = func(message *ProducerMessage, metadata TopicMetadata) int {
 			return internalRouter(message, metadata.NumPartitions())
@@ -120,10 +148,19 @@ func newProducer(client *client, options *ProducerOptions) (*producer, error) {
 		}
 	}
 
-	err := p.internalCreatePartitionsProducers()
+	err = p.internalCreatePartitionsProducers(ctx)
 	if err != nil {
 		return nil, err
 	}
+	if options.MessageDeduplication == GuaranteedMessageDeduplication {
+		if err := p.checkDeduplication(tns[0]); err != nil {
+			for _, pp := range p.producers {
+				pp.CloseForcefully()
+			}
+			p.metrics.ProducersPartitions.Sub(float64(len(p.producers)))
+			return nil, err
+		}
+	}
 
 	ticker := time.NewTicker(partitionsAutoDiscoveryInterval)
 	p.ticker = ticker
@@ -134,7 +171,7 @@ func newProducer(client *client, options *ProducerOptions) (*producer, error) {
 			select {
 			case <-ticker.C:
 				p.log.Debug("Auto discovering new partitions")
-				p.internalCreatePartitionsProducers()
+				p.internalCreatePartitionsProducers(context.Background())
 			case <-p.tickerStop:
 				return
 			}
@@ -145,7 +182,18 @@ func newProducer(client *client, options *ProducerOptions) (*producer, error) {
 	return p, nil
 }
 
-func (p *producer) internalCreatePartitionsProducers() error {
+// checkDeduplication returns ErrDeduplicationNotEnabled unless the broker deduplicates the messages of the
+// producer, which the broker resuming its sequence ids proves since it only tracks them with the deduplication
+func (p *producer) checkDeduplication(tn *internal.TopicName) error {
+	for _, pp := range p.producers {
+		if pp.(*partitionProducer).resumedSequenceID {
+			return nil
+		}
+	}
+	return p.client.checkDeduplication(tn)
+}
+
+func (p *producer) internalCreatePartitionsProducers(ctx context.Context) error {
 	partitions, err := p.client.TopicPartitions(p.topic)
 	if err != nil {
 		return err
@@ -191,7 +239,7 @@ func (p *producer) internalCreatePartitionsProducers() error {
 		partition := partitions[partitionIdx]
 
 		go func(partitionIdx int, partition string) {
-			prod, e := newPartitionProducer(p.client, partition, p.options, partitionIdx, p.metrics)
+			prod, e := newPartitionProducer(ctx, p.client, partition, p.options, partitionIdx, p.metrics)
 			c <- ProducerError{
 				partition: partitionIdx,
 				prod:      prod,
@@ -200,24 +248,50 @@ func (p *producer) internalCreatePartitionsProducers() error {
 		}(partitionIdx, partition)
 	}
 
-	for i := 0; i < partitionsToAdd; i++ {
-		pe, ok := <-c
-		if ok {
+	var timeoutCh <-chan time.Time
+	if p.options.AccessMode != ProducerAccessModeWaitForExclusive {
+		// a producer waiting for the exclusive access is only bounded by ctx
+		var stopTimer func()
+		timeoutCh, stopTimer = p.client.creationTimeoutCh()
+		defer stopTimer()
+	}
+
+	nextProducer := func() func() {
+		if pe := <-c; pe.err == nil {
+			return pe.prod.Close
+		}
+		return nil
+	}
+
+wait:
+	for pending := partitionsToAdd; pending > 0; pending-- {
+		select {
+		case pe := <-c:
 			if pe.err != nil {
 				err = pe.err
 			} else {
 				p.producers[pe.partition] = pe.prod
 			}
+		case <-timeoutCh:
+			err = newError(TimeoutError, fmt.Sprintf("timed out creating the producers of %d partitions", pending))
+			go closeLatePartitions(pending, nextProducer)
+			break wait
+		case <-ctx.Done():
+			err = ctx.Err()
+			go closeLatePartitions(pending, nextProducer)
+			break wait
 		}
 	}
 
 	if err != nil {
-		// Since there were some failures, cleanup all the partitions that succeeded in creating the producers
-		for _, producer := range p.producers {
+		// Since there were some failures, cleanup the partitions that succeeded in creating the producers
+		// and keep producing to the partitions which already existed
+		for _, producer := range p.producers[oldNumPartitions:] {
 			if producer != nil {
 				producer.Close()
 			}
 		}
+		p.producers = oldProducers
 		return err
 	}
 
@@ -243,14 +317,71 @@ func (p *producer) NumPartitions() uint32 {
 }
 
 func (p *producer) Send(ctx context.Context, msg *ProducerMessage) (MessageID, error) {
+	if err := p.limitPublishRate(ctx, msg); err != nil {
+		return nil, err
+	}
 	return p.getPartition(msg).Send(ctx, msg)
 }
 
 func (p *producer) SendAsync(ctx context.Context, msg *ProducerMessage,
 	callback func(MessageID, *ProducerMessage, error)) {
+	if err := p.limitPublishRate(ctx, msg); err != nil {
+		if callback != nil {
+			callback(nil, msg, err)
+		}
+		return
+	}
 	p.getPartition(msg).SendAsync(ctx, msg, callback)
 }
 
+// limitPublishRate waits until the message fits in the publish rate limit, it fails right away when the producer
+// does not block or when the context is done first
+func (p *producer) limitPublishRate(ctx context.Context, msg *ProducerMessage) error {
+	if p.messagesRate == nil && p.bytesRate == nil {
+		return nil
+	}
+
+	var wait time.Duration
+	if p.messagesRate != nil {
+		wait = p.messagesRate.Reserve(1)
+	}
+	if p.bytesRate != nil {
+		if d := p.bytesRate.Reserve(len(msg.Payload)); d > wait {
+			wait = d
+		}
+	}
+	if wait == 0 {
+		return nil
+	}
+
+	cancel := func() {
+		if p.messagesRate != nil {
+			p.messagesRate.Cancel(1)
+		}
+		if p.bytesRate != nil {
+			p.bytesRate.Cancel(len(msg.Payload))
+		}
+	}
+	if p.options.DisableBlockIfQueueFull {
+		cancel()
+		return ErrPublishRateExceeded
+	}
+
+	if ctx == nil {
+		// the sends never required a context, a nil one waits for the rate limit like the background context
+		ctx = context.Background()
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		cancel()
+		return ctx.Err()
+	}
+}
+
 func (p *producer) getPartition(msg *ProducerMessage) Producer {
 	// Since partitions can only increase, it's ok if the producers list
 	// is updated in between. The numPartition is updated only after the list.
@@ -279,11 +410,15 @@ func (p *producer) LastSequenceID() int64 {
 }
 
 func (p *producer) Flush() error {
+	return p.FlushWithCtx(context.Background())
+}
+
+func (p *producer) FlushWithCtx(ctx context.Context) error {
 	p.RLock()
 	defer p.RUnlock()
 
 	for _, pp := range p.producers {
-		if err := pp.Flush(); err != nil {
+		if err := pp.FlushWithCtx(ctx); err != nil {
 			return err
 		}
 
@@ -292,6 +427,14 @@ func (p *producer) Flush() error {
 }
 
 func (p *producer) Close() {
+	p.close(false)
+}
+
+func (p *producer) CloseForcefully() {
+	p.close(true)
+}
+
+func (p *producer) close(force bool) {
 	p.Lock()
 	defer p.Unlock()
 	if p.ticker != nil {
@@ -300,10 +443,79 @@ func (p *producer) Close() {
 		p.ticker = nil
 	}
 
+	// the partitions wait for their receipts concurrently
+	var wg sync.WaitGroup
 	for _, pp := range p.producers {
-		pp.Close()
+		wg.Add(1)
+		go func(pp Producer) {
+			defer wg.Done()
+			if force {
+				pp.CloseForcefully()
+			} else {
+				pp.Close()
+			}
+		}(pp)
 	}
+	wg.Wait()
 	p.client.handlers.Del(p)
 	p.metrics.ProducersPartitions.Sub(float64(len(p.producers)))
 	p.metrics.ProducersClosed.Inc()
 }
+
+func (p *producer) CloseWithCtx(ctx context.Context) error {
+	return runWithCtx(ctx, p.Close)
+}
+
+func (p *producer) connectionHolders() []connectionHolder {
+	p.RLock()
+	defer p.RUnlock()
+	holders := make([]connectionHolder, len(p.producers))
+	for i, pp := range p.producers {
+		holders[i] = pp
+	}
+	return holders
+}
+
+func (p *producer) BrokerAddress() string {
+	return brokerAddresses(p.connectionHolders())
+}
+
+func (p *producer) ConnectedSince() time.Time {
+	return connectedSince(p.connectionHolders())
+}
+
+func (p *producer) LastDisconnectedTimestamp() time.Time {
+	return lastDisconnectedTimestamp(p.connectionHolders())
+}
+
+func (p *producer) LastSendTimestamp() time.Time {
+	p.RLock()
+	defer p.RUnlock()
+	var last time.Time
+	for _, pp := range p.producers {
+		if t := pp.LastSendTimestamp(); t.After(last) {
+			last = t
+		}
+	}
+	return last
+}
+
+func (p *producer) PendingMessages() int {
+	p.RLock()
+	defer p.RUnlock()
+	n := 0
+	for _, pp := range p.producers {
+		n += pp.PendingMessages()
+	}
+	return n
+}
+
+func (p *producer) PendingBytes() []int64 {
+	p.RLock()
+	defer p.RUnlock()
+	bytes := make([]int64, 0, len(p.producers))
+	for _, pp := range p.producers {
+		bytes = append(bytes, pp.PendingBytes()...)
+	}
+	return bytes
+}
@@ -19,6 +19,7 @@ package pulsar
 
 import (
 	"context"
+	"net/url"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -48,11 +49,14 @@ var (
 	errFailAddToBatch  = newError(AddToBatchFailed, "message add to batch failed")
 	errSendTimeout     = newError(TimeoutError, "message send timeout")
 	errSendQueueIsFull = newError(ProducerQueueIsFull, "producer send queue is full")
-	errMessageTooLarge = newError(MessageTooBig, "message size exceeds MaxMessageSize")
+	errProducerClosed  = newError(AlreadyClosedError, "producer already been closed")
 
 	buffersPool sync.Pool
 )
 
+// localCluster is the special replication cluster name that restricts a message to the local cluster
+const localCluster = "__local__"
+
 type partitionProducer struct {
 	state  ua.Int32
 	client *client
@@ -60,6 +64,10 @@ type partitionProducer struct {
 	log    log.Logger
 	cnx    internal.Connection
 
+	// the address of the broker the producer is connected to, as returned by the lookup
+	brokerAddr *url.URL
+	connectionTracker
+
 	options             *ProducerOptions
 	producerName        string
 	producerID          uint64
@@ -70,6 +78,8 @@ type partitionProducer struct {
 	// Channel where app is posting messages to be published
 	eventsChan      chan interface{}
 	connectClosedCh chan connectionClosed
+	// the requests being queued to eventsChan, the events loop fails the ones queued while it closes
+	enqueuing ua.Int32
 
 	publishSemaphore internal.Semaphore
 	pendingQueue     internal.BlockingQueue
@@ -77,11 +87,24 @@ type partitionProducer struct {
 	schemaInfo       *SchemaInfo
 	partitionIdx     int32
 	metrics          *internal.TopicMetrics
+	// the metadata of the message being added to the batch
+	singleMetadata pb.SingleMessageMetadata
+	// the messages sent by the application and not persisted yet, and when the last one was sent
+	pendingMessages   ua.Int64
+	pendingBytes      ua.Int64
+	lastSendTimestamp ua.Int64
+	// the topic epoch assigned by the broker to an exclusive producer, sent back when it reconnects
+	topicEpoch *uint64
+	// the broker returned the last sequence id of the producer name on creation, it only tracks it when it
+	// deduplicates the messages
+	resumedSequenceID bool
+	// the context of the reconnections, canceled once the producer is closed
+	reconnectCtx    context.Context
+	cancelReconnect context.CancelFunc
 }
 
-func newPartitionProducer(client *client, topic string, options *ProducerOptions, partitionIdx int,
-	metrics *internal.TopicMetrics) (
-	*partitionProducer, error) {
+func newPartitionProducer(ctx context.Context, client *client, topic string, options *ProducerOptions,
+	partitionIdx int, metrics *internal.TopicMetrics) (*partitionProducer, error) {
 	var batchingMaxPublishDelay time.Duration
 	if options.BatchingMaxPublishDelay != 0 {
 		batchingMaxPublishDelay = options.BatchingMaxPublishDelay
@@ -96,7 +119,10 @@ func newPartitionProducer(client *client, topic string, options *ProducerOptions
 		maxPendingMessages = options.MaxPendingMessages
 	}
 
-	logger := client.log.SubLogger(log.Fields{"topic": topic})
+	logger := client.log.SubLogger(log.Fields{
+		"topic":     topic,
+		"partition": partitionIdx,
+	})
 
 	p := &partitionProducer{
 		client:           client,
@@ -113,6 +139,7 @@ func newPartitionProducer(client *client, topic string, options *ProducerOptions
 		partitionIdx:     int32(partitionIdx),
 		metrics:          metrics,
 	}
+	p.reconnectCtx, p.cancelReconnect = context.WithCancel(context.Background())
 	p.setProducerState(producerInit)
 
 	if options.Schema != nil && options.Schema.GetSchemaInfo() != nil {
@@ -125,7 +152,9 @@ func newPartitionProducer(client *client, topic string, options *ProducerOptions
 		p.producerName = options.Name
 	}
 
-	err := p.grabCnx()
+	err := client.retryOnRetriableErrors(ctx, func() error {
+		return p.grabCnx(ctx)
+	})
 	if err != nil {
 		logger.WithError(err).Error("Failed to create producer")
 		return nil, err
@@ -138,6 +167,7 @@ func newPartitionProducer(client *client, topic string, options *ProducerOptions
 
 	p.log.WithField("cnx", p.cnx.ID()).Info("Created producer")
 	p.setProducerState(producerReady)
+	client.producers.Add(p.producerID, p)
 
 	if p.options.SendTimeout > 0 {
 		go p.failTimeoutMessages()
@@ -147,7 +177,9 @@ func newPartitionProducer(client *client, topic string, options *ProducerOptions
 	return p, nil
 }
 
-func (p *partitionProducer) grabCnx() error {
+// grabCnx connects the producer to the broker serving its topic, ctx bounds the wait of a producer queued for the
+// exclusive access to the topic
+func (p *partitionProducer) grabCnx(ctx context.Context) error {
 	lr, err := p.client.lookupService.Lookup(p.topic)
 	if err != nil {
 		p.log.WithError(err).Warn("Failed to lookup topic")
@@ -181,6 +213,10 @@ func (p *partitionProducer) grabCnx() error {
 		ProducerId: proto.Uint64(p.producerID),
 		Schema:     pbSchema,
 	}
+	cmdProducer.ProducerAccessMode = toProtoProducerAccessMode(p.options.AccessMode).Enum()
+	if p.topicEpoch != nil {
+		cmdProducer.TopicEpoch = proto.Uint64(*p.topicEpoch)
+	}
 
 	if p.producerName != "" {
 		cmdProducer.ProducerName = proto.String(p.producerName)
@@ -189,18 +225,34 @@ func (p *partitionProducer) grabCnx() error {
 	if len(p.options.Properties) > 0 {
 		cmdProducer.Metadata = toKeyValues(p.options.Properties)
 	}
-	res, err := p.client.rpcClient.Request(lr.LogicalAddr, lr.PhysicalAddr, id, pb.BaseCommand_PRODUCER, cmdProducer)
+	queued := false
+	res, err := p.client.rpcClient.RequestWithQueuedCallback(ctx, lr.LogicalAddr, lr.PhysicalAddr, id,
+		pb.BaseCommand_PRODUCER, cmdProducer, func() {
+			queued = true
+			p.log.Info("Waiting for the exclusive access to the topic")
+			if p.options.WaitingForExclusiveHandler != nil {
+				p.options.WaitingForExclusiveHandler(p.topic)
+			}
+		})
 	if err != nil {
 		p.log.WithError(err).Error("Failed to create producer")
+		if queued || ctx.Err() != nil {
+			// the broker still holds the queued producer, or may create the one the request was abandoned for
+			p.closeAbandonedProducer(lr)
+		}
+		p.client.lookupService.Invalidate(p.topic, lr.LogicalAddr)
 		return err
 	}
 
 	p.producerName = res.Response.ProducerSuccess.GetProducerName()
+	if res.Response.ProducerSuccess.TopicEpoch != nil {
+		p.topicEpoch = proto.Uint64(res.Response.ProducerSuccess.GetTopicEpoch())
+	}
 	if p.options.DisableBatching {
 		provider, _ := GetBatcherBuilderProvider(DefaultBatchBuilder)
 		p.batchBuilder, err = provider(p.options.BatchingMaxMessages, p.options.BatchingMaxSize,
-			p.producerName, p.producerID, pb.CompressionType(p.options.CompressionType),
-			compression.Level(p.options.CompressionLevel),
+			uint32(res.Cnx.GetMaxMessageSize()), p.producerName, p.producerID,
+			pb.CompressionType(p.options.CompressionType), compression.Level(p.options.CompressionLevel),
 			p,
 			p.log)
 		if err != nil {
@@ -213,8 +265,8 @@ func (p *partitionProducer) grabCnx() error {
 		}
 
 		p.batchBuilder, err = provider(p.options.BatchingMaxMessages, p.options.BatchingMaxSize,
-			p.producerName, p.producerID, pb.CompressionType(p.options.CompressionType),
-			compression.Level(p.options.CompressionLevel),
+			uint32(res.Cnx.GetMaxMessageSize()), p.producerName, p.producerID,
+			pb.CompressionType(p.options.CompressionType), compression.Level(p.options.CompressionLevel),
 			p,
 			p.log)
 		if err != nil {
@@ -223,11 +275,18 @@ func (p *partitionProducer) grabCnx() error {
 	}
 
 	if p.sequenceIDGenerator == nil {
-		nextSequenceID := uint64(res.Response.ProducerSuccess.GetLastSequenceId() + 1)
+		lastSequenceID := res.Response.ProducerSuccess.GetLastSequenceId()
+		p.resumedSequenceID = lastSequenceID >= 0
+		nextSequenceID := uint64(lastSequenceID + 1)
 		p.sequenceIDGenerator = &nextSequenceID
 	}
 	p.cnx = res.Cnx
-	p.cnx.RegisterListener(p.producerID, p)
+	p.brokerAddr = lr.LogicalAddr
+	if err := p.cnx.RegisterListener(p.producerID, p); err != nil {
+		p.log.WithError(err).Warn("Connection closed while creating the producer")
+		return err
+	}
+	p.connected(lr.LogicalAddr.String())
 	p.log.WithField("cnx", res.Cnx.ID()).Debug("Connected producer")
 
 	pendingItems := p.pendingQueue.ReadableSlice()
@@ -240,6 +299,32 @@ func (p *partitionProducer) grabCnx() error {
 	return nil
 }
 
+// closeAbandonedProducer closes on the broker the producer whose creation was given up
+func (p *partitionProducer) closeAbandonedProducer(lr *internal.LookupResult) {
+	cnx, err := p.client.cnxPool.GetConnection(lr.LogicalAddr, lr.PhysicalAddr)
+	if err != nil {
+		return
+	}
+	cmdClose := &pb.CommandCloseProducer{
+		ProducerId: proto.Uint64(p.producerID),
+		RequestId:  proto.Uint64(p.client.rpcClient.NewRequestID()),
+	}
+	if err := p.client.rpcClient.RequestOnCnxNoWait(cnx, pb.BaseCommand_CLOSE_PRODUCER, cmdClose); err != nil {
+		p.log.WithError(err).Warn("Failed to close the abandoned producer")
+	}
+}
+
+func toProtoProducerAccessMode(mode ProducerAccessMode) pb.ProducerAccessMode {
+	switch mode {
+	case ProducerAccessModeExclusive:
+		return pb.ProducerAccessMode_Exclusive
+	case ProducerAccessModeWaitForExclusive:
+		return pb.ProducerAccessMode_WaitForExclusive
+	default:
+		return pb.ProducerAccessMode_Shared
+	}
+}
+
 type connectionClosed struct{}
 
 func (p *partitionProducer) GetBuffer() internal.Buffer {
@@ -253,13 +338,19 @@ func (p *partitionProducer) GetBuffer() internal.Buffer {
 func (p *partitionProducer) ConnectionClosed() {
 	// Trigger reconnection in the produce goroutine
 	p.log.WithField("cnx", p.cnx.ID()).Warn("Connection was closed")
+	p.disconnected()
 	p.connectClosedCh <- connectionClosed{}
 }
 
-func (p *partitionProducer) reconnectToBroker() {
+// reconnectToBroker retries to connect to the broker, it fails once the reconnection attempts are exhausted. A
+// producer in ProducerAccessModeWaitForExclusive may wait for the exclusive access again, without any timeout,
+// until the producer is closed.
+func (p *partitionProducer) reconnectToBroker() error {
 	var (
 		maxRetry int
-		backoff  = internal.Backoff{}
+		attempt  int
+		backoff  = p.options.BackoffPolicy()
+		err      error
 	)
 
 	if p.options.MaxReconnectToBroker == nil {
@@ -268,27 +359,38 @@ func (p *partitionProducer) reconnectToBroker() {
 		maxRetry = int(*p.options.MaxReconnectToBroker)
 	}
 
+	// the topic may have moved to another broker
+	p.client.lookupService.Invalidate(p.topic, p.brokerAddr)
+
 	for maxRetry != 0 {
 		if p.getProducerState() != producerReady {
 			// Producer is already closing
-			return
+			return nil
 		}
 
 		d := backoff.Next()
 		p.log.Info("Reconnecting to broker in ", d)
-		time.Sleep(d)
+		select {
+		case <-time.After(d):
+		case <-p.reconnectCtx.Done():
+			continue
+		}
 
-		err := p.grabCnx()
+		attempt++
+		p.client.reconnectAttempt(p.topic, attempt)
+
+		err = p.grabCnx(p.reconnectCtx)
 		if err == nil {
 			// Successfully reconnected
 			p.log.WithField("cnx", p.cnx.ID()).Info("Reconnected producer to broker")
-			return
+			return nil
 		}
 
 		if maxRetry > 0 {
 			maxRetry--
 		}
 	}
+	return reconnectFailedError(err)
 }
 
 func (p *partitionProducer) runEventsLoop() {
@@ -302,10 +404,21 @@ func (p *partitionProducer) runEventsLoop() {
 				p.internalFlush(v)
 			case *closeProducer:
 				p.internalClose(v)
+				p.failLateRequests()
 				return
 			}
 		case <-p.connectClosedCh:
-			p.reconnectToBroker()
+			if err := p.reconnectToBroker(); err != nil {
+				p.log.WithError(err).Error("Closing the producer which gave up reconnecting")
+				wg := sync.WaitGroup{}
+				wg.Add(1)
+				p.internalClose(&closeProducer{waitGroup: &wg, force: true})
+				p.failLateRequests()
+				if p.options.ReconnectFailedHandler != nil {
+					p.options.ReconnectFailedHandler(p.topic, err)
+				}
+				return
+			}
 		case <-p.batchFlushTicker.C:
 			if p.batchBuilder.IsMultiBatches() {
 				p.internalFlushCurrentBatches()
@@ -325,16 +438,23 @@ func (p *partitionProducer) Name() string {
 }
 
 func (p *partitionProducer) internalSend(request *sendRequest) {
-	p.log.Debug("Received send request: ", *request)
-
 	msg := request.msg
 
+	// the application stopped waiting for the message before it could be sent
+	if request.ctx != nil && request.ctx.Err() != nil {
+		p.failSend(request, request.ctx.Err())
+		return
+	}
+
 	payload := msg.Payload
 	var schemaPayload []byte
 	var err error
-	if p.options.Schema != nil {
+	if p.options.Schema != nil && msg.Value != nil {
 		schemaPayload, err = p.options.Schema.Encode(msg.Value)
 		if err != nil {
+			err = wrapError(SchemaSerializationError, "failed to encode the message value", err)
+			p.failSend(request, err)
+			p.log.WithError(err).Errorf("Schema encode message failed %v", msg.Value)
 			return
 		}
 	}
@@ -343,11 +463,21 @@ func (p *partitionProducer) internalSend(request *sendRequest) {
 		payload = schemaPayload
 	}
 
-	// if msg is too large
-	if len(payload) > int(p.cnx.GetMaxMessageSize()) {
-		p.publishSemaphore.Release()
-		request.callback(nil, request.msg, errMessageTooLarge)
-		p.log.WithError(errMessageTooLarge).
+	properties := msg.Properties
+	if p.options.PayloadCodec != nil {
+		payload, properties, err = encodePayload(p.options.PayloadCodec, payload, msg.Properties)
+		if err != nil {
+			p.failSend(request, err)
+			p.log.WithError(err).Error("Payload codec encode message failed")
+			return
+		}
+	}
+
+	// the compressed payloads are checked once their batch is serialized, a batch too large is split so
+	// that only the messages which don't fit on their own fail
+	if p.options.CompressionType == NoCompression && len(payload) > int(p.cnx.GetMaxMessageSize()) {
+		p.failSend(request, ErrMessageTooLarge)
+		p.log.WithError(ErrMessageTooLarge).
 			WithField("size", len(payload)).
 			WithField("properties", msg.Properties).
 			Errorf("MaxMessageSize %d", int(p.cnx.GetMaxMessageSize()))
@@ -360,14 +490,27 @@ func (p *partitionProducer) internalSend(request *sendRequest) {
 		deliverAt = time.Now().Add(msg.DeliverAfter)
 	}
 
-	sendAsBatch := !p.options.DisableBatching &&
-		msg.ReplicationClusters == nil &&
-		deliverAt.UnixNano() < 0
+	replicationClusters := msg.ReplicationClusters
+	if msg.DisableReplication {
+		replicationClusters = []string{localCluster}
+	}
 
-	smm := &pb.SingleMessageMetadata{
-		PayloadSize: proto.Int(len(payload)),
+	var txnID *internal.TxnID
+	if msg.Transaction != nil {
+		txnID = &msg.Transaction.(*transaction).id
 	}
 
+	sendAsBatch := !p.options.DisableBatching &&
+		!request.alone &&
+		replicationClusters == nil &&
+		deliverAt.UnixNano() < 0 &&
+		txnID == nil
+
+	// the metadata is serialized when the message is added to the batch, it is reused by the next message
+	smm := &p.singleMetadata
+	smm.Reset()
+	smm.PayloadSize = proto.Int(len(payload))
+
 	if msg.EventTime.UnixNano() != 0 {
 		smm.EventTime = proto.Uint64(internal.TimestampMillis(msg.EventTime))
 	}
@@ -380,8 +523,8 @@ func (p *partitionProducer) internalSend(request *sendRequest) {
 		smm.OrderingKey = []byte(msg.OrderingKey)
 	}
 
-	if msg.Properties != nil {
-		smm.Properties = internal.ConvertFromStringMap(msg.Properties)
+	if properties != nil {
+		smm.Properties = internal.ConvertFromStringMap(properties)
 	}
 
 	if msg.SequenceID != nil {
@@ -390,10 +533,14 @@ func (p *partitionProducer) internalSend(request *sendRequest) {
 	}
 
 	if !sendAsBatch {
-		p.internalFlushCurrentBatch()
+		if p.batchBuilder.IsMultiBatches() {
+			p.internalFlushCurrentBatches()
+		} else {
+			p.internalFlushCurrentBatch()
+		}
 	}
 	added := p.batchBuilder.Add(smm, p.sequenceIDGenerator, payload, request,
-		msg.ReplicationClusters, deliverAt)
+		replicationClusters, deliverAt, txnID)
 	if !added {
 		// The current batch is full.. flush it and retry
 		if p.batchBuilder.IsMultiBatches() {
@@ -404,9 +551,8 @@ func (p *partitionProducer) internalSend(request *sendRequest) {
 
 		// after flushing try again to add the current payload
 		if ok := p.batchBuilder.Add(smm, p.sequenceIDGenerator, payload, request,
-			msg.ReplicationClusters, deliverAt); !ok {
-			p.publishSemaphore.Release()
-			request.callback(nil, request.msg, errFailAddToBatch)
+			replicationClusters, deliverAt, txnID); !ok {
+			p.failSend(request, errFailAddToBatch)
 			p.log.WithField("size", len(payload)).
 				WithField("properties", msg.Properties).
 				Error("unable to add message to batch")
@@ -432,11 +578,57 @@ type pendingItem struct {
 	completed    bool
 }
 
+// failSend completes the send request of a message which is not published
+func (p *partitionProducer) failSend(request *sendRequest, err error) {
+	p.publishSemaphore.Release()
+	p.removePending(len(request.msg.Payload))
+	if request.callback != nil {
+		request.callback(nil, request.msg, err)
+	}
+}
+
+// failTooLargeBatch handles the serialized batch when its frame exceeds the max message size advertised by
+// the broker, which would otherwise close the connection, it returns false when it fits. The messages of a
+// batch too large are sent again one per batch, a single message too large fails.
+func (p *partitionProducer) failTooLargeBatch(batchData internal.Buffer, sendRequests []interface{}) bool {
+	// the frame starts with its size, the broker allows a padding for the commands and metadata
+	frameSize := int(batchData.ReadableBytes()) - 4
+	maxFrameSize := int(p.cnx.GetMaxMessageSize()) + internal.MessageFramePadding
+	if frameSize <= maxFrameSize {
+		return false
+	}
+
+	buffersPool.Put(batchData)
+	if len(sendRequests) > 1 {
+		p.log.WithField("size", frameSize).
+			WithField("messages", len(sendRequests)).
+			Warnf("Splitting the batch exceeding MaxMessageSize %d", int(p.cnx.GetMaxMessageSize()))
+		for _, i := range sendRequests {
+			sr := i.(*sendRequest)
+			sr.alone = true
+			p.internalSend(sr)
+		}
+		return true
+	}
+
+	p.log.WithError(ErrMessageTooLarge).
+		WithField("size", frameSize).
+		Errorf("MaxMessageSize %d", int(p.cnx.GetMaxMessageSize()))
+	for _, i := range sendRequests {
+		p.metrics.PublishErrorsMsgTooLarge.Inc()
+		p.failSend(i.(*sendRequest), ErrMessageTooLarge)
+	}
+	return true
+}
+
 func (p *partitionProducer) internalFlushCurrentBatch() {
 	batchData, sequenceID, callbacks := p.batchBuilder.Flush()
 	if batchData == nil {
 		return
 	}
+	if p.failTooLargeBatch(batchData, callbacks) {
+		return
+	}
 
 	p.pendingQueue.Put(&pendingItem{
 		sentAt:       time.Now(),
@@ -510,8 +702,7 @@ func (p *partitionProducer) failTimeoutMessages() {
 				if sr.msg != nil {
 					size := len(sr.msg.Payload)
 					p.publishSemaphore.Release()
-					p.metrics.MessagesPending.Dec()
-					p.metrics.BytesPending.Sub(float64(size))
+					p.removePending(size)
 					p.metrics.PublishErrorsTimeout.Inc()
 					p.log.WithError(errSendTimeout).
 						WithField("size", size).
@@ -543,7 +734,7 @@ func (p *partitionProducer) internalFlushCurrentBatches() {
 	}
 
 	for i := range batchesData {
-		if batchesData[i] == nil {
+		if batchesData[i] == nil || p.failTooLargeBatch(batchesData[i], callbacks[i]) {
 			continue
 		}
 		p.pendingQueue.Put(&pendingItem{
@@ -594,8 +785,7 @@ func (p *partitionProducer) internalFlush(fr *flushRequest) {
 }
 
 func (p *partitionProducer) Send(ctx context.Context, msg *ProducerMessage) (MessageID, error) {
-	wg := sync.WaitGroup{}
-	wg.Add(1)
+	doneCh := make(chan struct{})
 
 	var err error
 	var msgID MessageID
@@ -603,11 +793,16 @@ func (p *partitionProducer) Send(ctx context.Context, msg *ProducerMessage) (Mes
 	p.internalSendAsync(ctx, msg, func(ID MessageID, message *ProducerMessage, e error) {
 		err = e
 		msgID = ID
-		wg.Done()
+		close(doneCh)
 	}, true)
 
-	wg.Wait()
-	return msgID, err
+	// the message may still be published after the context is done, its result is then discarded
+	select {
+	case <-doneCh:
+		return msgID, err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
 }
 
 func (p *partitionProducer) SendAsync(ctx context.Context, msg *ProducerMessage,
@@ -617,6 +812,16 @@ func (p *partitionProducer) SendAsync(ctx context.Context, msg *ProducerMessage,
 
 func (p *partitionProducer) internalSendAsync(ctx context.Context, msg *ProducerMessage,
 	callback func(MessageID, *ProducerMessage, error), flushImmediately bool) {
+	if msg.Transaction != nil {
+		var err error
+		if callback, err = p.registerTxnSend(ctx, msg, callback); err != nil {
+			if callback != nil {
+				callback(nil, msg, err)
+			}
+			return
+		}
+	}
+
 	sr := &sendRequest{
 		ctx:              ctx,
 		msg:              msg,
@@ -626,6 +831,16 @@ func (p *partitionProducer) internalSendAsync(ctx context.Context, msg *Producer
 	}
 	p.options.Interceptors.BeforeSend(p, msg)
 
+	// counted before the state is checked, so that the events loop doesn't stop while the request is queued
+	p.enqueuing.Inc()
+	defer p.enqueuing.Dec()
+	if p.getProducerState() != producerReady {
+		if callback != nil {
+			callback(nil, msg, errProducerClosed)
+		}
+		return
+	}
+
 	if p.options.DisableBlockIfQueueFull {
 		if !p.publishSemaphore.TryAcquire() {
 			if callback != nil {
@@ -637,31 +852,105 @@ func (p *partitionProducer) internalSendAsync(ctx context.Context, msg *Producer
 		p.publishSemaphore.Acquire()
 	}
 
-	p.metrics.MessagesPending.Inc()
-	p.metrics.BytesPending.Add(float64(len(sr.msg.Payload)))
+	p.addPending(len(sr.msg.Payload))
+	p.lastSendTimestamp.Store(time.Now().UnixNano())
 
 	p.eventsChan <- sr
 }
 
-func (p *partitionProducer) ReceivedSendReceipt(response *pb.CommandSendReceipt) {
+func (p *partitionProducer) addPending(size int) {
+	p.pendingMessages.Inc()
+	p.pendingBytes.Add(int64(size))
+	p.metrics.MessagesPending.Inc()
+	p.metrics.BytesPending.Add(float64(size))
+}
+
+func (p *partitionProducer) removePending(size int) {
+	p.pendingMessages.Dec()
+	p.pendingBytes.Sub(int64(size))
+	p.metrics.MessagesPending.Dec()
+	p.metrics.BytesPending.Sub(float64(size))
+}
+
+// LastSendTimestamp returns when the application sent the last message, or the zero time if it never did
+func (p *partitionProducer) LastSendTimestamp() time.Time {
+	if t := p.lastSendTimestamp.Load(); t != 0 {
+		return time.Unix(0, t)
+	}
+	return time.Time{}
+}
+
+// PendingMessages returns the number of messages sent by the application and not persisted yet
+func (p *partitionProducer) PendingMessages() int {
+	return int(p.pendingMessages.Load())
+}
+
+// PendingBytes returns the payload bytes of the messages not persisted yet, the partition has a single value
+func (p *partitionProducer) PendingBytes() []int64 {
+	return []int64{p.pendingBytes.Load()}
+}
+
+// registerTxnSend registers the partition with the transaction of the message and returns the callback
+// completing the send within the transaction
+func (p *partitionProducer) registerTxnSend(ctx context.Context, msg *ProducerMessage,
+	callback func(MessageID, *ProducerMessage, error)) (func(MessageID, *ProducerMessage, error), error) {
+	txn, ok := msg.Transaction.(*transaction)
+	if !ok {
+		return callback, newError(InvalidConfiguration, "the transaction was not created by the client")
+	}
+	if err := txn.registerPartition(ctx, p.topic); err != nil {
+		return callback, err
+	}
+	if err := txn.registerOp(); err != nil {
+		return callback, err
+	}
+
+	return func(id MessageID, m *ProducerMessage, err error) {
+		if callback != nil {
+			callback(id, m, err)
+		}
+		txn.endOp(err)
+	}, nil
+}
+
+// expectedPendingItem returns the head of the pending queue if it is the batch of the sequence id the broker
+// responded for, the kind of response is only logged
+func (p *partitionProducer) expectedPendingItem(sequenceID uint64, kind string) (*pendingItem, bool) {
 	pi, ok := p.pendingQueue.Peek().(*pendingItem)
 
 	if !ok {
-		// if we receive a receipt although the pending queue is empty, the state of the broker and the producer differs.
-		// At that point, it is better to close the connection to the broker to reconnect to a broker hopping it solves
-		// the state discrepancy.
-		p.log.Warnf("Received ack for %v although the pending queue is empty, closing connection", response.GetMessageId())
-		p.cnx.Close()
-		return
+		// the response is a duplicate or is for a batch which has already timed out, there is no callback to complete
+		p.metrics.PublishErrorsReceipt.Inc()
+		p.log.Warnf("Ignoring %s on sequenceId %v, the pending queue is empty", kind, sequenceID)
+		return nil, false
+	}
+
+	if sequenceID < pi.sequenceID {
+		// the batch has already been completed, completing the head of the queue with the response would give the
+		// callbacks a wrong result
+		p.metrics.PublishErrorsReceipt.Inc()
+		p.log.Warnf("Ignoring %s on sequenceId %v - expected: %v, the batch was already completed",
+			kind, sequenceID, pi.sequenceID)
+		return nil, false
 	}
 
-	if pi.sequenceID != response.GetSequenceId() {
-		// if we receive a receipt that is not the one expected, the state of the broker and the producer differs.
-		// At that point, it is better to close the connection to the broker to reconnect to a broker hopping it solves
-		// the state discrepancy.
-		p.log.Warnf("Received ack for %v on sequenceId %v - expected: %v, closing connection", response.GetMessageId(),
-			response.GetSequenceId(), pi.sequenceID)
+	if sequenceID > pi.sequenceID {
+		// the broker responded for a batch sent after the head of the queue, the head was lost on the way. The state
+		// of the broker and the producer differs, resync them by reconnecting: the pending queue is sent again once
+		// the producer is reconnected and the broker deduplicates the batches it already persisted.
+		p.metrics.PublishErrorsReceipt.Inc()
+		p.log.Warnf("Received %s on sequenceId %v - expected: %v, resending the pending queue",
+			kind, sequenceID, pi.sequenceID)
 		p.cnx.Close()
+		return nil, false
+	}
+
+	return pi, true
+}
+
+func (p *partitionProducer) ReceivedSendReceipt(response *pb.CommandSendReceipt) {
+	pi, ok := p.expectedPendingItem(response.GetSequenceId(), "ack")
+	if !ok {
 		return
 	}
 
@@ -682,10 +971,8 @@ func (p *partitionProducer) ReceivedSendReceipt(response *pb.CommandSendReceipt)
 
 			p.metrics.PublishLatency.Observe(float64(now-sr.publishTime.UnixNano()) / 1.0e9)
 			p.metrics.MessagesPublished.Inc()
-			p.metrics.MessagesPending.Dec()
-			payloadSize := float64(len(sr.msg.Payload))
-			p.metrics.BytesPublished.Add(payloadSize)
-			p.metrics.BytesPending.Sub(payloadSize)
+			p.metrics.BytesPublished.Add(float64(len(sr.msg.Payload)))
+			p.removePending(len(sr.msg.Payload))
 		}
 
 		if sr.callback != nil || len(p.options.Interceptors) > 0 {
@@ -710,19 +997,76 @@ func (p *partitionProducer) ReceivedSendReceipt(response *pb.CommandSendReceipt)
 	buffersPool.Put(pi.batchData)
 }
 
+func (p *partitionProducer) ReceivedSendError(response *pb.CommandSendError) {
+	pi, ok := p.expectedPendingItem(response.GetSequenceId(), "send error")
+	if !ok {
+		return
+	}
+	p.pendingQueue.Poll()
+
+	err := toClientError(&internal.ServerError{Code: response.GetError(), Message: response.GetMessage()}, "send")
+	p.log.WithError(err).Warnf("Broker failed to persist the batch of sequenceId %v", response.GetSequenceId())
+
+	pi.Lock()
+	defer pi.Unlock()
+	for _, i := range pi.sendRequests {
+		sr := i.(*sendRequest)
+		if sr.msg == nil {
+			// a flush waiting for the batch
+			if sr.callback != nil {
+				sr.callback(nil, nil, err)
+			}
+			continue
+		}
+
+		p.metrics.PublishErrorsSend.Inc()
+		sr.sendErrors++
+		if p.options.SendErrorHandler != nil &&
+			p.options.SendErrorHandler(sr.msg, sr.sendErrors, err) == SendErrorRetry && p.republish(sr) {
+			continue
+		}
+		p.failSend(sr, err)
+	}
+
+	pi.completed = true
+	buffersPool.Put(pi.batchData)
+}
+
+// republish queues the send request of a message again, it returns false when the producer is closing or when its
+// queue is full
+func (p *partitionProducer) republish(sr *sendRequest) bool {
+	p.enqueuing.Inc()
+	defer p.enqueuing.Dec()
+	if p.getProducerState() != producerReady {
+		return false
+	}
+	select {
+	case p.eventsChan <- sr:
+		return true
+	default:
+		return false
+	}
+}
+
 func (p *partitionProducer) internalClose(req *closeProducer) {
 	defer req.waitGroup.Done()
-	if !p.casProducerState(producerReady, producerClosing) {
+	if p.getProducerState() == producerClosed {
 		return
 	}
+	p.setProducerState(producerClosing)
 
 	p.log.Info("Closing producer")
 
+	if !req.force {
+		p.waitPendingReceipts()
+	}
+
 	id := p.client.rpcClient.NewRequestID()
-	_, err := p.client.rpcClient.RequestOnCnx(p.cnx, id, pb.BaseCommand_CLOSE_PRODUCER, &pb.CommandCloseProducer{
-		ProducerId: &p.producerID,
-		RequestId:  &id,
-	})
+	_, err := p.client.rpcClient.RequestOnCnx(context.Background(), p.cnx, id,
+		pb.BaseCommand_CLOSE_PRODUCER, &pb.CommandCloseProducer{
+			ProducerId: &p.producerID,
+			RequestId:  &id,
+		})
 
 	if err != nil {
 		p.log.WithError(err).Warn("Failed to close producer")
@@ -735,8 +1079,115 @@ func (p *partitionProducer) internalClose(req *closeProducer) {
 	}
 
 	p.setProducerState(producerClosed)
+	p.cancelReconnect()
 	p.cnx.UnregisterListener(p.producerID)
+	p.client.producers.Del(p.producerID)
 	p.batchFlushTicker.Stop()
+
+	// no receipt is received anymore, fail what was not persisted
+	p.failPendingMessages(errProducerClosed)
+}
+
+// waitPendingReceipts flushes the current batch and waits, at most for the send timeout or for the operation
+// timeout when the send timeout is disabled, for the receipts of all the pending batches. It stops waiting if the
+// connection is closed since the receipts won't be received then.
+func (p *partitionProducer) waitPendingReceipts() {
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	p.internalFlush(&flushRequest{&wg, nil})
+
+	doneCh := make(chan struct{})
+	go func() {
+		// the flush is completed by failPendingMessages if the receipts are not received
+		wg.Wait()
+		close(doneCh)
+	}()
+
+	timeout := p.options.SendTimeout
+	if timeout <= 0 {
+		timeout = p.client.operationTimeout
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-doneCh:
+	case <-timer.C:
+		p.log.Warnf("Timed out waiting for the receipts of %d pending messages", p.PendingMessages())
+	case <-p.connectClosedCh:
+		p.log.Warnf("Connection closed while waiting for the receipts of %d pending messages", p.PendingMessages())
+	}
+}
+
+// failPendingMessages fails the messages of the current batch, of the batches waiting for their receipt and of
+// the send requests not processed yet
+func (p *partitionProducer) failPendingMessages(err error) {
+	var batches [][]interface{}
+	if p.batchBuilder != nil {
+		if p.batchBuilder.IsMultiBatches() {
+			_, _, batches = p.batchBuilder.FlushBatches()
+		} else {
+			_, _, callbacks := p.batchBuilder.Flush()
+			batches = append(batches, callbacks)
+		}
+	}
+	for _, sendRequests := range batches {
+		p.failSendRequests(sendRequests, err)
+	}
+
+	for item := p.pendingQueue.Poll(); item != nil; item = p.pendingQueue.Poll() {
+		pi := item.(*pendingItem)
+		pi.Lock()
+		if !pi.completed {
+			p.failSendRequests(pi.sendRequests, err)
+			pi.completed = true
+			buffersPool.Put(pi.batchData)
+		}
+		pi.Unlock()
+	}
+
+	p.failQueuedRequests(err)
+}
+
+// failQueuedRequests fails the requests queued to the events loop and not processed yet
+func (p *partitionProducer) failQueuedRequests(err error) {
+	for {
+		select {
+		case i := <-p.eventsChan:
+			switch v := i.(type) {
+			case *sendRequest:
+				p.failSend(v, err)
+			case *flushRequest:
+				v.err = err
+				v.waitGroup.Done()
+			case *closeProducer:
+				v.waitGroup.Done()
+			}
+		default:
+			return
+		}
+	}
+}
+
+// failLateRequests fails the requests queued once the producer is closed, by the application which checked the
+// state of the producer before it was closed, until no request is being queued anymore
+func (p *partitionProducer) failLateRequests() {
+	for p.enqueuing.Load() > 0 {
+		p.failQueuedRequests(errProducerClosed)
+		time.Sleep(time.Millisecond)
+	}
+	p.failQueuedRequests(errProducerClosed)
+}
+
+func (p *partitionProducer) failSendRequests(sendRequests []interface{}, err error) {
+	for _, i := range sendRequests {
+		sr := i.(*sendRequest)
+		if sr.msg != nil {
+			p.failSend(sr, err)
+		} else if sr.callback != nil {
+			sr.callback(nil, nil, err)
+		}
+	}
 }
 
 func (p *partitionProducer) LastSequenceID() int64 {
@@ -744,16 +1195,39 @@ func (p *partitionProducer) LastSequenceID() int64 {
 }
 
 func (p *partitionProducer) Flush() error {
+	return p.FlushWithCtx(context.Background())
+}
+
+func (p *partitionProducer) FlushWithCtx(ctx context.Context) error {
 	wg := sync.WaitGroup{}
 	wg.Add(1)
 
 	cp := &flushRequest{&wg, nil}
-	p.eventsChan <- cp
+	if err := p.queueFlush(ctx, cp); err != nil {
+		return err
+	}
 
-	wg.Wait()
+	if err := runWithCtx(ctx, wg.Wait); err != nil {
+		return err
+	}
 	return cp.err
 }
 
+func (p *partitionProducer) queueFlush(ctx context.Context, cp *flushRequest) error {
+	p.enqueuing.Inc()
+	defer p.enqueuing.Dec()
+	if p.getProducerState() != producerReady {
+		return errProducerClosed
+	}
+
+	select {
+	case p.eventsChan <- cp:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 func (p *partitionProducer) getProducerState() producerState {
 	return producerState(p.state.Load())
 }
@@ -769,30 +1243,51 @@ func (p *partitionProducer) casProducerState(oldState, newState producerState) b
 }
 
 func (p *partitionProducer) Close() {
-	if p.getProducerState() != producerReady {
+	p.close(false)
+}
+
+func (p *partitionProducer) CloseForcefully() {
+	p.close(true)
+}
+
+func (p *partitionProducer) close(force bool) {
+	// stop accepting new messages, the ones already queued are sent before the producer is closed
+	if !p.casProducerState(producerReady, producerClosing) {
 		// Producer is closing
 		return
 	}
+	// the events loop may be reconnecting, eg. waiting for the exclusive access to the topic
+	p.cancelReconnect()
 
 	wg := sync.WaitGroup{}
 	wg.Add(1)
 
-	cp := &closeProducer{&wg}
+	cp := &closeProducer{waitGroup: &wg, force: force}
 	p.eventsChan <- cp
 
 	wg.Wait()
 }
 
+func (p *partitionProducer) CloseWithCtx(ctx context.Context) error {
+	return runWithCtx(ctx, p.Close)
+}
+
 type sendRequest struct {
 	ctx              context.Context
 	msg              *ProducerMessage
 	callback         func(MessageID, *ProducerMessage, error)
 	publishTime      time.Time
 	flushImmediately bool
+	// alone sends the message in a batch of its own, once the batch it was added to was too large
+	alone bool
+	// the number of times the broker failed to persist the message
+	sendErrors int
 }
 
 type closeProducer struct {
 	waitGroup *sync.WaitGroup
+	// force fails the pending messages instead of waiting for their receipts
+	force bool
 }
 
 type flushRequest struct {
@@ -0,0 +1,66 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package pulsar
+
+import (
+	"testing"
+
+	"github.com/apache/pulsar-client-go/pulsar/internal"
+	pb "github.com/apache/pulsar-client-go/pulsar/internal/pulsar_proto"
+	"github.com/apache/pulsar-client-go/pulsar/log"
+)
+
+// discardConnection is a connection dropping the data written to it
+type discardConnection struct {
+	maxSizeConnection
+}
+
+func (c *discardConnection) WriteData(data internal.Buffer) {}
+
+func BenchmarkProducerInternalSend(b *testing.B) {
+	p := &partitionProducer{
+		log:                 log.DefaultNopLogger(),
+		options:             &ProducerOptions{},
+		cnx:                 &discardConnection{maxSizeConnection{maxMessageSize: internal.MaxMessageSize}},
+		publishSemaphore:    internal.NewSemaphore(1000),
+		pendingQueue:        internal.NewBlockingQueue(1000),
+		sequenceIDGenerator: new(uint64),
+		metrics:             internal.NewMetricsProvider(map[string]string{}).GetTopicMetrics("topic"),
+	}
+	bb, err := internal.NewBatchBuilder(100, 128*1024, internal.MaxMessageSize, "producer", 1,
+		pb.CompressionType_NONE, 0, p, p.log)
+	if err != nil {
+		b.Fatal(err)
+	}
+	p.batchBuilder = bb
+
+	request := &sendRequest{msg: &ProducerMessage{
+		Payload:    make([]byte, 100),
+		Key:        "key",
+		Properties: map[string]string{"a": "b"},
+	}}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		p.internalSend(request)
+		// the batches are persisted right away
+		for item := p.pendingQueue.Poll(); item != nil; item = p.pendingQueue.Poll() {
+			buffersPool.Put(item.(*pendingItem).batchData)
+		}
+	}
+}
@@ -0,0 +1,430 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package pulsar
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/pulsar-client-go/pulsar/internal"
+	pb "github.com/apache/pulsar-client-go/pulsar/internal/pulsar_proto"
+	"github.com/apache/pulsar-client-go/pulsar/log"
+)
+
+func TestSendReceiptMessageIDs(t *testing.T) {
+	p := &partitionProducer{
+		log:              log.DefaultNopLogger(),
+		options:          &ProducerOptions{},
+		publishSemaphore: internal.NewSemaphore(10),
+		pendingQueue:     internal.NewBlockingQueue(10),
+		partitionIdx:     3,
+		metrics:          internal.NewMetricsProvider(map[string]string{}).GetTopicMetrics("topic"),
+	}
+
+	ids := make([]MessageID, 3)
+	sendRequests := make([]interface{}, 0, 4)
+	for i := range ids {
+		idx := i
+		p.publishSemaphore.Acquire()
+		p.addPending(len("hello"))
+		sendRequests = append(sendRequests, &sendRequest{
+			msg: &ProducerMessage{Payload: []byte("hello")},
+			callback: func(id MessageID, msg *ProducerMessage, err error) {
+				assert.NoError(t, err)
+				ids[idx] = id
+			},
+		})
+	}
+	// a flush waiting for the batch does not shift the batch indexes
+	flushed := false
+	sendRequests = append(sendRequests, &sendRequest{
+		callback: func(id MessageID, msg *ProducerMessage, err error) {
+			flushed = true
+		},
+	})
+	p.pendingQueue.Put(&pendingItem{sequenceID: 7, sendRequests: sendRequests})
+
+	p.ReceivedSendReceipt(&pb.CommandSendReceipt{
+		SequenceId: proto.Uint64(7),
+		MessageId:  &pb.MessageIdData{LedgerId: proto.Uint64(11), EntryId: proto.Uint64(22)},
+	})
+
+	assert.True(t, flushed)
+	assert.Equal(t, 0, p.PendingMessages())
+	assert.Equal(t, []int64{0}, p.PendingBytes())
+	for i, id := range ids {
+		assert.Equal(t, int64(11), id.LedgerID())
+		assert.Equal(t, int64(22), id.EntryID())
+		assert.Equal(t, int32(i), id.BatchIdx())
+		assert.Equal(t, int32(3), id.PartitionIdx())
+	}
+	assert.Equal(t, int64(7), p.lastSequenceID)
+}
+
+// closeCountingConnection is a connection counting how many times it is closed
+type closeCountingConnection struct {
+	internal.Connection
+	closed int
+}
+
+func (c *closeCountingConnection) Close() {
+	c.closed++
+}
+
+func TestSendReceiptOutOfOrder(t *testing.T) {
+	cnx := &closeCountingConnection{}
+	p := &partitionProducer{
+		log:              log.DefaultNopLogger(),
+		options:          &ProducerOptions{},
+		cnx:              cnx,
+		publishSemaphore: internal.NewSemaphore(10),
+		pendingQueue:     internal.NewBlockingQueue(10),
+		metrics:          internal.NewMetricsProvider(map[string]string{}).GetTopicMetrics("topic"),
+	}
+	receipt := func(sequenceID uint64) *pb.CommandSendReceipt {
+		return &pb.CommandSendReceipt{
+			SequenceId: proto.Uint64(sequenceID),
+			MessageId:  &pb.MessageIdData{LedgerId: proto.Uint64(1), EntryId: proto.Uint64(sequenceID)},
+		}
+	}
+
+	// a receipt without any pending batch is ignored
+	p.ReceivedSendReceipt(receipt(4))
+	assert.Equal(t, 0, cnx.closed)
+
+	completed := 0
+	p.publishSemaphore.Acquire()
+	p.addPending(len("hello"))
+	p.pendingQueue.Put(&pendingItem{sequenceID: 5, sendRequests: []interface{}{&sendRequest{
+		msg: &ProducerMessage{Payload: []byte("hello")},
+		callback: func(id MessageID, msg *ProducerMessage, err error) {
+			assert.NoError(t, err)
+			assert.Equal(t, int64(5), id.EntryID())
+			completed++
+		},
+	}}})
+
+	// a receipt of an already completed batch does not complete the head of the queue
+	p.ReceivedSendReceipt(receipt(4))
+	assert.Equal(t, 0, completed)
+	assert.Equal(t, 1, p.pendingQueue.Size())
+	assert.Equal(t, 0, cnx.closed)
+
+	// a receipt past the head of the queue resyncs the producer with the broker
+	p.ReceivedSendReceipt(receipt(6))
+	assert.Equal(t, 0, completed)
+	assert.Equal(t, 1, p.pendingQueue.Size())
+	assert.Equal(t, 1, cnx.closed)
+
+	p.ReceivedSendReceipt(receipt(5))
+	assert.Equal(t, 1, completed)
+	assert.Equal(t, 0, p.pendingQueue.Size())
+	assert.Equal(t, 0, p.PendingMessages())
+}
+
+func TestSendError(t *testing.T) {
+	p := &partitionProducer{
+		log:              log.DefaultNopLogger(),
+		options:          &ProducerOptions{SendErrorHandler: RetryOnSendError(1)},
+		eventsChan:       make(chan interface{}, 10),
+		publishSemaphore: internal.NewSemaphore(10),
+		pendingQueue:     internal.NewBlockingQueue(10),
+		metrics:          internal.NewMetricsProvider(map[string]string{}).GetTopicMetrics("topic"),
+	}
+	p.setProducerState(producerReady)
+	sendError := func(sequenceID uint64) *pb.CommandSendError {
+		return &pb.CommandSendError{
+			SequenceId: proto.Uint64(sequenceID),
+			Error:      pb.ServerError_ChecksumError.Enum(),
+			Message:    proto.String("checksum error"),
+		}
+	}
+
+	var sendErr error
+	p.publishSemaphore.Acquire()
+	p.addPending(len("hello"))
+	sr := &sendRequest{
+		msg: &ProducerMessage{Payload: []byte("hello")},
+		callback: func(id MessageID, msg *ProducerMessage, err error) {
+			sendErr = err
+		},
+	}
+	var flushErr error
+	flush := &sendRequest{
+		callback: func(id MessageID, msg *ProducerMessage, err error) {
+			flushErr = err
+		},
+	}
+	p.pendingQueue.Put(&pendingItem{sequenceID: 1, sendRequests: []interface{}{sr, flush}})
+
+	// the message is published again
+	p.ReceivedSendError(sendError(1))
+	assert.NoError(t, sendErr)
+	assert.Error(t, flushErr)
+	assert.Equal(t, sr, <-p.eventsChan)
+	assert.Equal(t, 0, p.pendingQueue.Size())
+	assert.Equal(t, 1, p.PendingMessages())
+
+	// until the retries are exhausted
+	p.pendingQueue.Put(&pendingItem{sequenceID: 2, sendRequests: []interface{}{sr}})
+	p.ReceivedSendError(sendError(2))
+	assert.Equal(t, ChecksumError, sendErr.(*Error).Result())
+	assert.Equal(t, 0, len(p.eventsChan))
+	assert.Equal(t, 0, p.PendingMessages())
+}
+
+func TestSendErrorHandlers(t *testing.T) {
+	msg := &ProducerMessage{Payload: []byte("hello")}
+	err := newError(ChecksumError, "checksum error")
+
+	assert.Equal(t, SendErrorFail, FailOnSendError()(msg, 1, err))
+
+	assert.Equal(t, SendErrorRetry, RetryOnSendError(2)(msg, 1, err))
+	assert.Equal(t, SendErrorRetry, RetryOnSendError(2)(msg, 2, err))
+	assert.Equal(t, SendErrorFail, RetryOnSendError(2)(msg, 3, err))
+
+	var deadLetters []*ProducerMessage
+	handler := DeadLetterOnSendError(func(msg *ProducerMessage, err error) {
+		deadLetters = append(deadLetters, msg)
+	})
+	assert.Equal(t, SendErrorFail, handler(msg, 1, err))
+	assert.Equal(t, []*ProducerMessage{msg}, deadLetters)
+}
+
+func TestSendAfterClose(t *testing.T) {
+	p := &partitionProducer{
+		log:              log.DefaultNopLogger(),
+		options:          &ProducerOptions{},
+		publishSemaphore: internal.NewSemaphore(10),
+		metrics:          internal.NewMetricsProvider(map[string]string{}).GetTopicMetrics("topic"),
+	}
+	p.setProducerState(producerClosing)
+
+	var sendErr error
+	p.internalSendAsync(context.Background(), &ProducerMessage{Payload: []byte("hello")},
+		func(id MessageID, msg *ProducerMessage, err error) {
+			sendErr = err
+		}, false)
+	assert.Equal(t, errProducerClosed, sendErr)
+	assert.Equal(t, 0, p.PendingMessages())
+}
+
+func TestWaitPendingReceipts(t *testing.T) {
+	bb, err := internal.NewBatchBuilder(10, 1024, internal.MaxMessageSize, "producer", 1, pb.CompressionType_NONE, 0,
+		&partitionProducer{}, log.DefaultNopLogger())
+	assert.NoError(t, err)
+	p := &partitionProducer{
+		log:              log.DefaultNopLogger(),
+		options:          &ProducerOptions{SendTimeout: 10 * time.Second},
+		batchBuilder:     bb,
+		publishSemaphore: internal.NewSemaphore(10),
+		pendingQueue:     internal.NewBlockingQueue(10),
+		metrics:          internal.NewMetricsProvider(map[string]string{}).GetTopicMetrics("topic"),
+	}
+
+	var sendErr error
+	p.publishSemaphore.Acquire()
+	p.addPending(len("hello"))
+	p.pendingQueue.Put(&pendingItem{sequenceID: 1, sendRequests: []interface{}{&sendRequest{
+		msg: &ProducerMessage{Payload: []byte("hello")},
+		callback: func(id MessageID, msg *ProducerMessage, err error) {
+			sendErr = err
+		},
+	}}})
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		p.ReceivedSendReceipt(&pb.CommandSendReceipt{
+			SequenceId: proto.Uint64(1),
+			MessageId:  &pb.MessageIdData{LedgerId: proto.Uint64(1), EntryId: proto.Uint64(1)},
+		})
+	}()
+
+	start := time.Now()
+	p.waitPendingReceipts()
+	assert.True(t, time.Since(start) < p.options.SendTimeout)
+	assert.NoError(t, sendErr)
+	assert.Equal(t, 0, p.PendingMessages())
+
+	// the pending messages fail once the send timeout is reached
+	p.options.SendTimeout = 10 * time.Millisecond
+	p.publishSemaphore.Acquire()
+	p.addPending(len("hello"))
+	p.pendingQueue.Put(&pendingItem{sequenceID: 2, sendRequests: []interface{}{&sendRequest{
+		msg: &ProducerMessage{Payload: []byte("hello")},
+		callback: func(id MessageID, msg *ProducerMessage, err error) {
+			sendErr = err
+		},
+	}}})
+	p.waitPendingReceipts()
+	assert.NoError(t, sendErr)
+	assert.Equal(t, 1, p.PendingMessages())
+
+	p.failPendingMessages(errProducerClosed)
+	assert.Equal(t, errProducerClosed, sendErr)
+	assert.Equal(t, 0, p.PendingMessages())
+	assert.Equal(t, 0, p.pendingQueue.Size())
+}
+
+func TestFailPendingMessages(t *testing.T) {
+	p := &partitionProducer{
+		log:              log.DefaultNopLogger(),
+		options:          &ProducerOptions{},
+		eventsChan:       make(chan interface{}, 10),
+		publishSemaphore: internal.NewSemaphore(10),
+		pendingQueue:     internal.NewBlockingQueue(10),
+		metrics:          internal.NewMetricsProvider(map[string]string{}).GetTopicMetrics("topic"),
+	}
+
+	errs := make([]error, 0)
+	callback := func(id MessageID, msg *ProducerMessage, err error) {
+		errs = append(errs, err)
+	}
+	p.publishSemaphore.Acquire()
+	p.addPending(len("hello"))
+	p.pendingQueue.Put(&pendingItem{sequenceID: 1, sendRequests: []interface{}{
+		&sendRequest{msg: &ProducerMessage{Payload: []byte("hello")}, callback: callback},
+		&sendRequest{callback: callback},
+	}})
+	p.publishSemaphore.Acquire()
+	p.addPending(len("world"))
+	p.eventsChan <- &sendRequest{msg: &ProducerMessage{Payload: []byte("world")}, callback: callback}
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	fr := &flushRequest{&wg, nil}
+	p.eventsChan <- fr
+
+	p.failPendingMessages(errProducerClosed)
+	wg.Wait()
+	assert.Equal(t, errProducerClosed, fr.err)
+	assert.Equal(t, []error{errProducerClosed, errProducerClosed, errProducerClosed}, errs)
+	assert.Equal(t, 0, p.PendingMessages())
+	assert.Equal(t, 0, p.pendingQueue.Size())
+	assert.Equal(t, 0, len(p.eventsChan))
+}
+
+// maxSizeConnection is a connection to a broker advertising the given max message size
+type maxSizeConnection struct {
+	internal.Connection
+	maxMessageSize int32
+}
+
+func (c *maxSizeConnection) GetMaxMessageSize() int32 {
+	return c.maxMessageSize
+}
+
+func TestFailTooLargeBatch(t *testing.T) {
+	p := &partitionProducer{
+		log:              log.DefaultNopLogger(),
+		cnx:              &maxSizeConnection{maxMessageSize: 1024},
+		publishSemaphore: internal.NewSemaphore(10),
+		metrics:          internal.NewMetricsProvider(map[string]string{}).GetTopicMetrics("topic"),
+	}
+
+	var errs []error
+	sendRequests := make([]interface{}, 1)
+	for i := range sendRequests {
+		p.publishSemaphore.Acquire()
+		p.addPending(len("hello"))
+		sendRequests[i] = &sendRequest{
+			msg: &ProducerMessage{Payload: []byte("hello")},
+			callback: func(id MessageID, msg *ProducerMessage, err error) {
+				errs = append(errs, err)
+			},
+		}
+	}
+
+	// the frame size prefix and the padding of the broker are not counted
+	fits := internal.NewBuffer(4 + 1024 + internal.MessageFramePadding)
+	fits.Write(make([]byte, 4+1024+internal.MessageFramePadding))
+	assert.False(t, p.failTooLargeBatch(fits, sendRequests))
+	assert.Empty(t, errs)
+
+	tooLarge := internal.NewBuffer(4 + 1025 + internal.MessageFramePadding)
+	tooLarge.Write(make([]byte, 4+1025+internal.MessageFramePadding))
+	assert.True(t, p.failTooLargeBatch(tooLarge, sendRequests))
+	assert.Equal(t, []error{ErrMessageTooLarge}, errs)
+	assert.Equal(t, 0, p.PendingMessages())
+}
+
+func TestPendingMessages(t *testing.T) {
+	p := &partitionProducer{
+		log:              log.DefaultNopLogger(),
+		options:          &ProducerOptions{},
+		eventsChan:       make(chan interface{}, 2),
+		publishSemaphore: internal.NewSemaphore(10),
+		metrics:          internal.NewMetricsProvider(map[string]string{}).GetTopicMetrics("topic"),
+	}
+	p.setProducerState(producerReady)
+	assert.True(t, p.LastSendTimestamp().IsZero())
+	assert.Equal(t, 0, p.PendingMessages())
+
+	before := time.Now()
+	p.SendAsync(context.Background(), &ProducerMessage{Payload: []byte("hello")}, nil)
+	p.SendAsync(context.Background(), &ProducerMessage{Payload: []byte("hi")}, nil)
+	assert.False(t, p.LastSendTimestamp().Before(before))
+	assert.Equal(t, 2, p.PendingMessages())
+	assert.Equal(t, []int64{7}, p.PendingBytes())
+
+	p.failSend((<-p.eventsChan).(*sendRequest), errSendTimeout)
+	assert.Equal(t, 1, p.PendingMessages())
+	assert.Equal(t, []int64{2}, p.PendingBytes())
+}
+
+func TestFailLateRequests(t *testing.T) {
+	p := &partitionProducer{
+		log:              log.DefaultNopLogger(),
+		options:          &ProducerOptions{},
+		eventsChan:       make(chan interface{}, 10),
+		publishSemaphore: internal.NewSemaphore(1),
+		metrics:          internal.NewMetricsProvider(map[string]string{}).GetTopicMetrics("topic"),
+	}
+	p.setProducerState(producerClosed)
+
+	// a send which checked the state before the producer was closed is queued once the events loop closed it
+	p.enqueuing.Inc()
+	doneCh := make(chan struct{})
+	go func() {
+		p.failLateRequests()
+		close(doneCh)
+	}()
+
+	errCh := make(chan error, 1)
+	p.publishSemaphore.Acquire()
+	p.addPending(len("hello"))
+	p.eventsChan <- &sendRequest{
+		msg: &ProducerMessage{Payload: []byte("hello")},
+		callback: func(id MessageID, msg *ProducerMessage, err error) {
+			errCh <- err
+		},
+	}
+	p.enqueuing.Dec()
+
+	<-doneCh
+	assert.Equal(t, errProducerClosed, <-errCh)
+	assert.Equal(t, 0, p.PendingMessages())
+	assert.True(t, p.publishSemaphore.TryAcquire())
+
+	// the requests checking the state once the producer is closed fail right away
+	assert.Equal(t, errProducerClosed, p.FlushWithCtx(context.Background()))
+}
@@ -19,6 +19,8 @@ package pulsar
 
 import (
 	"context"
+	"crypto/rand"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -28,6 +30,7 @@ import (
 	"time"
 
 	"github.com/apache/pulsar-client-go/pulsar/internal"
+	"github.com/apache/pulsar-client-go/pulsar/pulsartest"
 	"github.com/stretchr/testify/assert"
 
 	log "github.com/sirupsen/logrus"
@@ -58,7 +61,11 @@ func TestProducerConnectError(t *testing.T) {
 	assert.Nil(t, producer)
 	assert.NotNil(t, err)
 
-	assert.Equal(t, err.Error(), "connection error")
+	var e *Error
+	if assert.True(t, errors.As(err, &e)) {
+		assert.Equal(t, ConnectError, e.Result())
+	}
+	assert.True(t, errors.Is(err, internal.ErrConnectionFailed))
 }
 
 func TestProducerNoTopic(t *testing.T) {
@@ -96,6 +103,10 @@ func TestSimpleProducer(t *testing.T) {
 	assert.NotNil(t, producer)
 	defer producer.Close()
 
+	assert.NotEmpty(t, producer.BrokerAddress())
+	assert.False(t, producer.ConnectedSince().IsZero())
+	assert.True(t, producer.LastDisconnectedTimestamp().IsZero())
+
 	for i := 0; i < 10; i++ {
 		ID, err := producer.Send(context.Background(), &ProducerMessage{
 			Payload: []byte("hello"),
@@ -255,6 +266,42 @@ func TestEventTime(t *testing.T) {
 	assert.Equal(t, eventTime.Unix(), actualEventTime.Unix())
 }
 
+func TestMessageKeysAndReplication(t *testing.T) {
+	client, err := NewClient(ClientOptions{
+		URL: serviceURL,
+	})
+	assert.NoError(t, err)
+	defer client.Close()
+
+	topicName := newTopicName()
+	producer, err := client.CreateProducer(ProducerOptions{
+		Topic: topicName,
+	})
+	assert.Nil(t, err)
+	defer producer.Close()
+
+	consumer, err := client.Subscribe(ConsumerOptions{
+		Topic:            topicName,
+		SubscriptionName: "subName",
+	})
+	assert.Nil(t, err)
+	defer consumer.Close()
+
+	ID, err := producer.Send(context.Background(), &ProducerMessage{
+		Payload:            []byte("test-keys"),
+		Key:                "my-key",
+		OrderingKey:        "my-ordering-key",
+		DisableReplication: true,
+	})
+	assert.Nil(t, err)
+	assert.NotNil(t, ID)
+
+	msg, err := consumer.Receive(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, "my-key", msg.Key())
+	assert.Equal(t, "my-ordering-key", msg.OrderingKey())
+}
+
 func TestFlushInProducer(t *testing.T) {
 	client, err := NewClient(ClientOptions{
 		URL: serviceURL,
@@ -877,11 +924,123 @@ func TestMaxMessageSize(t *testing.T) {
 			assert.NoError(t, err)
 			assert.NotNil(t, ID)
 		} else {
-			assert.Equal(t, errMessageTooLarge, err)
+			assert.Equal(t, ErrMessageTooLarge, err)
 		}
 	}
 }
 
+func TestMaxMessageSizeCompressed(t *testing.T) {
+	client, err := NewClient(ClientOptions{
+		URL: serviceURL,
+	})
+	assert.NoError(t, err)
+	defer client.Close()
+	producer, err := client.CreateProducer(ProducerOptions{
+		Topic:           newTopicName(),
+		CompressionType: LZ4,
+	})
+	assert.NoError(t, err)
+	defer producer.Close()
+
+	// the size is checked once the payload is compressed
+	serverMaxMessageSize := 1024 * 1024
+	ID, err := producer.Send(context.Background(), &ProducerMessage{
+		Payload: make([]byte, serverMaxMessageSize+1),
+	})
+	assert.NoError(t, err)
+	assert.NotNil(t, ID)
+
+	random := make([]byte, serverMaxMessageSize+1)
+	rand.Read(random)
+	_, err = producer.Send(context.Background(), &ProducerMessage{
+		Payload: random,
+	})
+	assert.Equal(t, ErrMessageTooLarge, err)
+}
+
+func TestProducerGuaranteedDeduplication(t *testing.T) {
+	broker, err := pulsartest.NewBroker()
+	assert.Nil(t, err)
+	defer broker.Close()
+	client, err := NewClient(ClientOptions{URL: broker.URL()})
+	assert.Nil(t, err)
+	defer client.Close()
+
+	deduplicated, notDeduplicated := newTopicName(), newTopicName()
+	assert.Nil(t, broker.EnableDeduplication(deduplicated))
+
+	_, err = client.CreateProducer(ProducerOptions{
+		Topic:                deduplicated,
+		MessageDeduplication: GuaranteedMessageDeduplication,
+	})
+	assert.Equal(t, InvalidConfiguration, err.(*Error).Result())
+
+	for _, topic := range []string{deduplicated, notDeduplicated} {
+		producer, err := client.CreateProducer(ProducerOptions{Topic: topic, Name: "my-producer"})
+		assert.Nil(t, err)
+		_, err = producer.Send(context.Background(), &ProducerMessage{Payload: []byte("hello")})
+		assert.Nil(t, err)
+		producer.Close()
+	}
+
+	// the broker resuming the sequence ids of the producer proves the deduplication, without the admin API
+	producer, err := client.CreateProducer(ProducerOptions{
+		Topic:                deduplicated,
+		Name:                 "my-producer",
+		MessageDeduplication: GuaranteedMessageDeduplication,
+	})
+	assert.Nil(t, err)
+	producer.Close()
+
+	// the deduplication of the other topic can't be checked with a pulsar:// service URL
+	_, err = client.CreateProducer(ProducerOptions{
+		Topic:                notDeduplicated,
+		Name:                 "my-producer",
+		MessageDeduplication: GuaranteedMessageDeduplication,
+	})
+	assert.Equal(t, InvalidConfiguration, err.(*Error).Result())
+}
+
+func TestMaxMessageSizeSplitBatch(t *testing.T) {
+	broker, err := pulsartest.NewBroker()
+	assert.Nil(t, err)
+	defer broker.Close()
+	client, err := NewClient(ClientOptions{URL: broker.URL()})
+	assert.Nil(t, err)
+	defer client.Close()
+
+	topic := newTopicName()
+	producer, err := client.CreateProducer(ProducerOptions{
+		Topic:                   topic,
+		CompressionType:         LZ4,
+		BatchingMaxSize:         4 * internal.MaxMessageSize,
+		BatchingMaxPublishDelay: time.Minute,
+	})
+	assert.Nil(t, err)
+	defer producer.Close()
+
+	// the batch of the first random payloads fits before compression but is too large once compressed, it
+	// is split, only the payload which doesn't fit on its own fails
+	sizes := []int{internal.MaxMessageSize / 2, internal.MaxMessageSize/2 - 1024, internal.MaxMessageSize + 1}
+	errs := make([]error, len(sizes))
+	var wg sync.WaitGroup
+	for i, size := range sizes {
+		idx := i
+		random := make([]byte, size)
+		rand.Read(random)
+		wg.Add(1)
+		producer.SendAsync(context.Background(), &ProducerMessage{Payload: random},
+			func(id MessageID, msg *ProducerMessage, err error) {
+				errs[idx] = err
+				wg.Done()
+			})
+	}
+	assert.Nil(t, producer.Flush())
+	wg.Wait()
+	assert.Equal(t, []error{nil, nil, ErrMessageTooLarge}, errs)
+	assert.Equal(t, 2, broker.Entries(topic))
+}
+
 func TestSendTimeout(t *testing.T) {
 	quotaURL := adminURL + "/admin/v2/namespaces/public/default/backlogQuota"
 	quotaFmt := `{"limit": "%d", "policy": "producer_request_hold"}`
@@ -1031,3 +1190,156 @@ func TestProducerWithInterceptors(t *testing.T) {
 	assert.Equal(t, 10, metric.sendn)
 	assert.Equal(t, 10, metric.ackn)
 }
+
+func TestPublishRateLimit(t *testing.T) {
+	p := &producer{
+		options:      &ProducerOptions{},
+		messagesRate: internal.NewRateLimiter(10),
+		bytesRate:    internal.NewRateLimiter(100),
+	}
+	ctx := context.Background()
+
+	for i := 0; i < 10; i++ {
+		assert.NoError(t, p.limitPublishRate(ctx, &ProducerMessage{Payload: []byte("hello")}))
+	}
+	start := time.Now()
+	assert.NoError(t, p.limitPublishRate(ctx, &ProducerMessage{Payload: []byte("hello")}))
+	assert.True(t, time.Since(start) >= 50*time.Millisecond)
+
+	// the bytes are limited as well
+	start = time.Now()
+	assert.NoError(t, p.limitPublishRate(ctx, &ProducerMessage{Payload: make([]byte, 100)}))
+	assert.True(t, time.Since(start) >= 400*time.Millisecond)
+
+	// a producer which does not block fails right away
+	p.options.DisableBlockIfQueueFull = true
+	assert.Equal(t, ErrPublishRateExceeded, p.limitPublishRate(ctx, &ProducerMessage{Payload: make([]byte, 100)}))
+
+	p.options.DisableBlockIfQueueFull = false
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, p.limitPublishRate(ctx, &ProducerMessage{Payload: make([]byte, 100)}))
+
+	// a nil context waits like the background context
+	var nilCtx context.Context
+	assert.NoError(t, p.limitPublishRate(nilCtx, &ProducerMessage{Payload: make([]byte, 100)}))
+}
+
+func TestProducerWaitForExclusive(t *testing.T) {
+	broker, err := pulsartest.NewBroker()
+	assert.Nil(t, err)
+	defer broker.Close()
+	client, err := NewClient(ClientOptions{URL: broker.URL()})
+	assert.Nil(t, err)
+	defer client.Close()
+
+	topic := newTopicName()
+	leader, err := client.CreateProducer(ProducerOptions{Topic: topic, AccessMode: ProducerAccessModeExclusive})
+	assert.Nil(t, err)
+
+	_, err = client.CreateProducer(ProducerOptions{Topic: topic, AccessMode: ProducerAccessModeExclusive})
+	assert.Equal(t, ProducerFenced, err.(*Error).Result())
+
+	// the creation given up while waiting fails with the context error
+	ctx, cancel := context.WithCancel(context.Background())
+	_, err = client.CreateProducerWithCtx(ctx, ProducerOptions{
+		Topic:                      topic,
+		AccessMode:                 ProducerAccessModeWaitForExclusive,
+		WaitingForExclusiveHandler: func(string) { cancel() },
+	})
+	assert.Equal(t, context.Canceled, err)
+
+	waiting := make(chan string, 1)
+	type result struct {
+		producer Producer
+		err      error
+	}
+	created := make(chan result, 1)
+	go func() {
+		producer, err := client.CreateProducer(ProducerOptions{
+			Topic:      topic,
+			AccessMode: ProducerAccessModeWaitForExclusive,
+			WaitingForExclusiveHandler: func(topic string) {
+				waiting <- topic
+			},
+		})
+		created <- result{producer, err}
+	}()
+
+	select {
+	case waitingTopic := <-waiting:
+		assert.Equal(t, "persistent://public/default/"+topic, waitingTopic)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the producer is not waiting for the exclusive access")
+	}
+	select {
+	case <-created:
+		t.Fatal("the producer was created while the topic has an exclusive producer")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	leader.Close()
+	select {
+	case res := <-created:
+		assert.Nil(t, res.err)
+		defer res.producer.Close()
+		_, err = res.producer.Send(context.Background(), &ProducerMessage{Payload: []byte("hello")})
+		assert.Nil(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the waiting producer did not get the exclusive access")
+	}
+}
+
+func TestProducerCloseWhileWaitingForExclusive(t *testing.T) {
+	broker, err := pulsartest.NewBroker()
+	assert.Nil(t, err)
+	defer broker.Close()
+	client, err := NewClient(ClientOptions{URL: broker.URL()})
+	assert.Nil(t, err)
+	defer client.Close()
+
+	topic := newTopicName()
+	leader, err := client.CreateProducer(ProducerOptions{Topic: topic, AccessMode: ProducerAccessModeWaitForExclusive})
+	assert.Nil(t, err)
+
+	// the follower connects on a connection of its own
+	followerClient, err := NewClient(ClientOptions{URL: broker.URL()})
+	assert.Nil(t, err)
+	defer followerClient.Close()
+	waiting := make(chan struct{}, 2)
+	created := make(chan Producer, 1)
+	go func() {
+		producer, err := followerClient.CreateProducer(ProducerOptions{
+			Topic:                      topic,
+			AccessMode:                 ProducerAccessModeWaitForExclusive,
+			WaitingForExclusiveHandler: func(string) { waiting <- struct{}{} },
+		})
+		assert.Nil(t, err)
+		created <- producer
+	}()
+	select {
+	case <-waiting:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the producer is not waiting for the exclusive access")
+	}
+
+	// the follower gets the exclusive access, the leader waits for it once reconnected
+	leader.(*producer).producers[0].(*partitionProducer).cnx.Close()
+	select {
+	case follower := <-created:
+		defer follower.Close()
+	case <-time.After(5 * time.Second):
+		t.Fatal("the waiting producer did not get the exclusive access")
+	}
+
+	closed := make(chan struct{})
+	go func() {
+		leader.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the producer waiting for the exclusive access was not closed")
+	}
+}
@@ -0,0 +1,969 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package pulsartest provides an in-memory broker speaking enough of the Pulsar binary protocol for the
+// producers, consumers and readers of the client to run against it, so that the code using them can be
+// unit tested without a Pulsar cluster.
+//
+// The broker keeps the messages of each topic in memory, for as long as it runs, and dispatches them to
+// the consumers of the subscriptions as a real broker would, honoring the flow permits, the acks and the
+// redelivery requests. Authentication, schemas, compaction and transactions are not supported.
+package pulsartest
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"sort"
+	"sync"
+
+	"github.com/gogo/protobuf/proto"
+
+	"github.com/apache/pulsar-client-go/pulsar/internal"
+	pb "github.com/apache/pulsar-client-go/pulsar/internal/pulsar_proto"
+)
+
+const serverVersion = "pulsartest"
+
+// Broker is an in-memory Pulsar broker listening on a local TCP port
+type Broker struct {
+	listener net.Listener
+	url      string
+	wg       sync.WaitGroup
+
+	sync.Mutex
+	closed     bool
+	conns      map[*serverConn]struct{}
+	topics     map[string]*topic
+	partitions map[string]int
+	ledgers    uint64
+	producers  uint64
+}
+
+// NewBroker starts a broker on a random local port, the clients connect to it through the URL of the broker
+func NewBroker() (*Broker, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	b := &Broker{
+		listener:   listener,
+		url:        "pulsar://" + listener.Addr().String(),
+		conns:      make(map[*serverConn]struct{}),
+		topics:     make(map[string]*topic),
+		partitions: make(map[string]int),
+	}
+	b.wg.Add(1)
+	go b.accept()
+	return b, nil
+}
+
+// URL returns the service URL of the broker, eg. to use as the URL of the client options
+func (b *Broker) URL() string {
+	return b.url
+}
+
+// Close stops the broker and closes the connections of its clients
+func (b *Broker) Close() error {
+	b.Lock()
+	if b.closed {
+		b.Unlock()
+		return nil
+	}
+	b.closed = true
+	err := b.listener.Close()
+	for c := range b.conns {
+		c.cnx.Close()
+	}
+	b.Unlock()
+
+	b.wg.Wait()
+	return err
+}
+
+// CreatePartitionedTopic declares the topic as partitioned, the topics are otherwise created non partitioned
+// when they are first used
+func (b *Broker) CreatePartitionedTopic(topicName string, partitions int) error {
+	if partitions <= 0 {
+		return fmt.Errorf("invalid number of partitions: %d", partitions)
+	}
+	tn, err := internal.ParseTopicName(topicName)
+	if err != nil {
+		return err
+	}
+
+	b.Lock()
+	defer b.Unlock()
+	if _, ok := b.partitions[tn.Name]; ok {
+		return fmt.Errorf("topic %s already exists", tn.Name)
+	}
+	b.partitions[tn.Name] = partitions
+	return nil
+}
+
+// UpdatePartitionedTopic adds partitions to the partitioned topic, the number of partitions can't decrease
+func (b *Broker) UpdatePartitionedTopic(topicName string, partitions int) error {
+	tn, err := internal.ParseTopicName(topicName)
+	if err != nil {
+		return err
+	}
+
+	b.Lock()
+	defer b.Unlock()
+	current, ok := b.partitions[tn.Name]
+	if !ok {
+		return fmt.Errorf("topic %s is not partitioned", tn.Name)
+	}
+	if partitions < current {
+		return fmt.Errorf("the partitions of topic %s cannot decrease from %d to %d", tn.Name, current, partitions)
+	}
+	b.partitions[tn.Name] = partitions
+	return nil
+}
+
+// EnableDeduplication deduplicates the messages published on the topic by producer name and sequence id, like
+// a broker with the deduplication enabled, the producers resume the sequence ids of their name
+func (b *Broker) EnableDeduplication(topicName string) error {
+	tn, err := internal.ParseTopicName(topicName)
+	if err != nil {
+		return err
+	}
+
+	b.Lock()
+	defer b.Unlock()
+	t := b.getTopic(tn.Name)
+	if t.sequenceIDs == nil {
+		t.sequenceIDs = make(map[string]int64)
+	}
+	return nil
+}
+
+// Entries returns the number of entries published on the topic, or on the given partition of the topic,
+// a batch of messages being stored as a single entry
+func (b *Broker) Entries(topicName string) int {
+	tn, err := internal.ParseTopicName(topicName)
+	if err != nil {
+		return 0
+	}
+
+	b.Lock()
+	defer b.Unlock()
+	if t, ok := b.topics[tn.Name]; ok {
+		return len(t.entries)
+	}
+	return 0
+}
+
+func (b *Broker) accept() {
+	defer b.wg.Done()
+	for {
+		cnx, err := b.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		c := &serverConn{
+			broker:    b,
+			cnx:       cnx,
+			reader:    bufio.NewReader(cnx),
+			producers: make(map[uint64]*producer),
+			consumers: make(map[uint64]*consumer),
+		}
+		b.Lock()
+		if b.closed {
+			b.Unlock()
+			cnx.Close()
+			return
+		}
+		b.conns[c] = struct{}{}
+		b.wg.Add(1)
+		b.Unlock()
+
+		go c.run()
+	}
+}
+
+// getTopic returns the topic, creating it on its first use, the caller must hold the lock of the broker
+func (b *Broker) getTopic(name string) *topic {
+	t, ok := b.topics[name]
+	if !ok {
+		b.ledgers++
+		t = &topic{
+			name:          name,
+			ledgerID:      b.ledgers,
+			subscriptions: make(map[string]*subscription),
+		}
+		b.topics[name] = t
+	}
+	return t
+}
+
+// fullTopicName returns the fully qualified name of the topic, the clients may send the short names
+func fullTopicName(name string) string {
+	if tn, err := internal.ParseTopicName(name); err == nil {
+		return tn.Name
+	}
+	return name
+}
+
+type entry struct {
+	id                uint64
+	numMessages       int
+	batched           bool
+	publishTime       uint64
+	headersAndPayload []byte
+}
+
+type topic struct {
+	name          string
+	ledgerID      uint64
+	entries       []*entry
+	subscriptions map[string]*subscription
+	// the highest sequence id published by each producer name, nil unless the topic deduplicates the messages
+	sequenceIDs map[string]int64
+
+	// exclusive is the producer holding the exclusive access to the topic, the producers waiting for it are
+	// queued in waiting, and shared counts the producers in the shared access mode
+	exclusive *producer
+	waiting   []*producer
+	shared    int
+	epoch     uint64
+}
+
+type producer struct {
+	id         uint64
+	requestID  uint64
+	name       string
+	conn       *serverConn
+	topic      *topic
+	accessMode pb.ProducerAccessMode
+	ready      bool
+}
+
+// success answers the creation of the producer, a producer not ready yet is queued for the exclusive access
+func (p *producer) success() {
+	lastSequenceID, ok := p.topic.sequenceIDs[p.name]
+	if !ok {
+		lastSequenceID = -1
+	}
+	success := &pb.CommandProducerSuccess{
+		RequestId:      proto.Uint64(p.requestID),
+		ProducerName:   proto.String(p.name),
+		LastSequenceId: proto.Int64(lastSequenceID),
+		ProducerReady:  proto.Bool(p.ready),
+	}
+	if p.ready && p.accessMode != pb.ProducerAccessMode_Shared {
+		success.TopicEpoch = proto.Uint64(p.topic.epoch)
+	}
+	p.conn.writeCommand(&pb.BaseCommand{
+		Type:            pb.BaseCommand_PRODUCER_SUCCESS.Enum(),
+		ProducerSuccess: success,
+	}, nil)
+}
+
+// addProducer gives the producer the access it requires on the topic or queues it for the exclusive access, it
+// returns false when the access is not granted
+func (t *topic) addProducer(p *producer) bool {
+	switch p.accessMode {
+	case pb.ProducerAccessMode_Shared:
+		if t.exclusive != nil {
+			return false
+		}
+		t.shared++
+		p.ready = true
+	case pb.ProducerAccessMode_Exclusive:
+		if t.exclusive != nil || t.shared > 0 {
+			return false
+		}
+		t.grantExclusive(p)
+	case pb.ProducerAccessMode_WaitForExclusive:
+		if t.exclusive != nil || t.shared > 0 {
+			t.waiting = append(t.waiting, p)
+			return true
+		}
+		t.grantExclusive(p)
+	}
+	return true
+}
+
+func (t *topic) grantExclusive(p *producer) {
+	t.epoch++
+	t.exclusive = p
+	p.ready = true
+}
+
+// removeProducer releases the access of the producer, the first producer waiting for the exclusive access gets it
+// once the topic has no other producer
+func (t *topic) removeProducer(p *producer) {
+	switch {
+	case !p.ready:
+		for i, w := range t.waiting {
+			if w == p {
+				t.waiting = append(t.waiting[:i], t.waiting[i+1:]...)
+				break
+			}
+		}
+		return
+	case t.exclusive == p:
+		t.exclusive = nil
+	default:
+		t.shared--
+	}
+
+	if t.exclusive == nil && t.shared == 0 && len(t.waiting) > 0 {
+		next := t.waiting[0]
+		t.waiting = t.waiting[1:]
+		t.grantExclusive(next)
+		next.success()
+	}
+}
+
+func (t *topic) messageID(e *entry) *pb.MessageIdData {
+	return &pb.MessageIdData{
+		LedgerId: proto.Uint64(t.ledgerID),
+		EntryId:  proto.Uint64(e.id),
+	}
+}
+
+// position returns the index of the first entry to dispatch when starting from the message id
+func (t *topic) position(id *pb.MessageIdData) uint64 {
+	switch {
+	case id.GetLedgerId() == math.MaxUint64:
+		return 0
+	case id.GetLedgerId() == math.MaxInt64 || id.GetEntryId() > uint64(len(t.entries)):
+		return uint64(len(t.entries))
+	default:
+		return id.GetEntryId()
+	}
+}
+
+type subscription struct {
+	topic   *topic
+	name    string
+	subType pb.CommandSubscribe_SubType
+	durable bool
+
+	// cursor is the next entry never dispatched, the entries to dispatch again are queued in redeliveries
+	cursor          uint64
+	redeliveries    []uint64
+	unacked         map[uint64]*consumer
+	redeliveryCount map[uint64]uint32
+
+	consumers []*consumer
+	next      int
+}
+
+type consumer struct {
+	id      uint64
+	conn    *serverConn
+	sub     *subscription
+	permits int
+}
+
+// dispatch pushes the pending entries to the consumers having permits, the caller must hold the lock of the broker
+func (s *subscription) dispatch() {
+	for {
+		c := s.nextConsumer()
+		if c == nil {
+			return
+		}
+
+		var e *entry
+		if len(s.redeliveries) > 0 {
+			e = s.topic.entries[s.redeliveries[0]]
+			s.redeliveries = s.redeliveries[1:]
+		} else if s.cursor < uint64(len(s.topic.entries)) {
+			e = s.topic.entries[s.cursor]
+			s.cursor++
+		} else {
+			return
+		}
+
+		c.permits -= e.numMessages
+		s.unacked[e.id] = c
+		c.conn.writeCommand(&pb.BaseCommand{
+			Type: pb.BaseCommand_MESSAGE.Enum(),
+			Message: &pb.CommandMessage{
+				ConsumerId:      proto.Uint64(c.id),
+				MessageId:       s.topic.messageID(e),
+				RedeliveryCount: proto.Uint32(s.redeliveryCount[e.id]),
+			},
+		}, e.headersAndPayload)
+	}
+}
+
+// nextConsumer returns the consumer receiving the next entry, the exclusive and failover subscriptions dispatch
+// to their first consumer only while the shared ones go round robin over the consumers having permits
+func (s *subscription) nextConsumer() *consumer {
+	if len(s.consumers) == 0 {
+		return nil
+	}
+	if s.subType == pb.CommandSubscribe_Exclusive || s.subType == pb.CommandSubscribe_Failover {
+		if s.consumers[0].permits > 0 {
+			return s.consumers[0]
+		}
+		return nil
+	}
+
+	for i := 0; i < len(s.consumers); i++ {
+		c := s.consumers[(s.next+i)%len(s.consumers)]
+		if c.permits > 0 {
+			s.next = (s.next + i + 1) % len(s.consumers)
+			return c
+		}
+	}
+	return nil
+}
+
+// redeliver queues again the unacked entries of the consumer, all of them when ids is empty
+func (s *subscription) redeliver(c *consumer, ids []*pb.MessageIdData) {
+	requeue := func(id uint64) {
+		if s.unacked[id] == c {
+			delete(s.unacked, id)
+			s.redeliveryCount[id]++
+			s.redeliveries = append(s.redeliveries, id)
+		}
+	}
+
+	if len(ids) == 0 {
+		for id := range s.unacked {
+			requeue(id)
+		}
+	} else {
+		for _, id := range ids {
+			requeue(id.GetEntryId())
+		}
+	}
+	sort.Slice(s.redeliveries, func(i, j int) bool { return s.redeliveries[i] < s.redeliveries[j] })
+}
+
+func (s *subscription) ack(ackType pb.CommandAck_AckType, ids []*pb.MessageIdData) {
+	for _, id := range ids {
+		entryID := id.GetEntryId()
+		if ackType == pb.CommandAck_Individual {
+			delete(s.unacked, entryID)
+			delete(s.redeliveryCount, entryID)
+			continue
+		}
+
+		for unacked := range s.unacked {
+			if unacked <= entryID {
+				delete(s.unacked, unacked)
+				delete(s.redeliveryCount, unacked)
+			}
+		}
+		redeliveries := s.redeliveries[:0]
+		for _, redelivery := range s.redeliveries {
+			if redelivery > entryID {
+				redeliveries = append(redeliveries, redelivery)
+			}
+		}
+		s.redeliveries = redeliveries
+	}
+}
+
+// seek moves the cursor of the subscription, the consumer asks again for permits once the seek succeeded
+func (s *subscription) seek(c *consumer, position uint64) {
+	s.cursor = position
+	s.redeliveries = nil
+	s.unacked = make(map[uint64]*consumer)
+	s.redeliveryCount = make(map[uint64]uint32)
+	c.permits = 0
+}
+
+func (s *subscription) removeConsumer(c *consumer) {
+	for i, other := range s.consumers {
+		if other == c {
+			s.consumers = append(s.consumers[:i], s.consumers[i+1:]...)
+			break
+		}
+	}
+	s.redeliver(c, nil)
+
+	if len(s.consumers) == 0 && !s.durable {
+		delete(s.topic.subscriptions, s.name)
+		return
+	}
+	s.dispatch()
+}
+
+type serverConn struct {
+	broker *Broker
+	cnx    net.Conn
+	reader *bufio.Reader
+
+	writeLock sync.Mutex
+
+	// producers and consumers are guarded by the lock of the broker
+	producers map[uint64]*producer
+	consumers map[uint64]*consumer
+}
+
+func (c *serverConn) run() {
+	defer c.broker.wg.Done()
+	defer c.close()
+
+	for {
+		cmd, headersAndPayload, err := c.readCommand()
+		if err != nil {
+			return
+		}
+		c.handleCommand(cmd, headersAndPayload)
+	}
+}
+
+func (c *serverConn) close() {
+	c.cnx.Close()
+
+	b := c.broker
+	b.Lock()
+	defer b.Unlock()
+	delete(b.conns, c)
+	for _, prod := range c.producers {
+		prod.topic.removeProducer(prod)
+	}
+	for _, cons := range c.consumers {
+		cons.sub.removeConsumer(cons)
+	}
+}
+
+// readCommand reads a frame: [TOTAL_SIZE] [CMD_SIZE] [CMD] [HEADERS_AND_PAYLOAD]
+func (c *serverConn) readCommand() (*pb.BaseCommand, []byte, error) {
+	var sizes [8]byte
+	if _, err := io.ReadFull(c.reader, sizes[:]); err != nil {
+		return nil, nil, err
+	}
+	totalSize := binary.BigEndian.Uint32(sizes[:4])
+	cmdSize := binary.BigEndian.Uint32(sizes[4:])
+	if totalSize > internal.MaxFrameSize || totalSize < 4 || cmdSize > totalSize-4 {
+		return nil, nil, errors.New("invalid frame size")
+	}
+
+	frame := make([]byte, totalSize-4)
+	if _, err := io.ReadFull(c.reader, frame); err != nil {
+		return nil, nil, err
+	}
+	cmd := &pb.BaseCommand{}
+	if err := proto.Unmarshal(frame[:cmdSize], cmd); err != nil {
+		return nil, nil, err
+	}
+	return cmd, frame[cmdSize:], nil
+}
+
+func (c *serverConn) writeCommand(cmd *pb.BaseCommand, headersAndPayload []byte) {
+	data, err := proto.Marshal(cmd)
+	if err != nil {
+		panic(err)
+	}
+
+	frame := make([]byte, 8, 8+len(data)+len(headersAndPayload))
+	binary.BigEndian.PutUint32(frame[:4], uint32(4+len(data)+len(headersAndPayload)))
+	binary.BigEndian.PutUint32(frame[4:], uint32(len(data)))
+	frame = append(frame, data...)
+	frame = append(frame, headersAndPayload...)
+
+	c.writeLock.Lock()
+	defer c.writeLock.Unlock()
+	// a failed write closes the connection, its read loop then cleans up
+	if _, err := c.cnx.Write(frame); err != nil {
+		c.cnx.Close()
+	}
+}
+
+func (c *serverConn) writeSuccess(requestID uint64) {
+	c.writeCommand(&pb.BaseCommand{
+		Type:    pb.BaseCommand_SUCCESS.Enum(),
+		Success: &pb.CommandSuccess{RequestId: proto.Uint64(requestID)},
+	}, nil)
+}
+
+func (c *serverConn) writeError(requestID uint64, serverError pb.ServerError, msg string) {
+	c.writeCommand(&pb.BaseCommand{
+		Type: pb.BaseCommand_ERROR.Enum(),
+		Error: &pb.CommandError{
+			RequestId: proto.Uint64(requestID),
+			Error:     serverError.Enum(),
+			Message:   proto.String(msg),
+		},
+	}, nil)
+}
+
+func (c *serverConn) handleCommand(cmd *pb.BaseCommand, headersAndPayload []byte) {
+	b := c.broker
+	b.Lock()
+	defer b.Unlock()
+
+	switch cmd.GetType() {
+	case pb.BaseCommand_CONNECT:
+		c.writeCommand(&pb.BaseCommand{
+			Type: pb.BaseCommand_CONNECTED.Enum(),
+			Connected: &pb.CommandConnected{
+				ServerVersion:   proto.String(serverVersion),
+				ProtocolVersion: proto.Int32(cmd.GetConnect().GetProtocolVersion()),
+				MaxMessageSize:  proto.Int32(internal.MaxMessageSize),
+			},
+		}, nil)
+
+	case pb.BaseCommand_PING:
+		c.writeCommand(&pb.BaseCommand{Type: pb.BaseCommand_PONG.Enum(), Pong: &pb.CommandPong{}}, nil)
+
+	case pb.BaseCommand_PONG:
+
+	case pb.BaseCommand_LOOKUP:
+		c.writeCommand(&pb.BaseCommand{
+			Type: pb.BaseCommand_LOOKUP_RESPONSE.Enum(),
+			LookupTopicResponse: &pb.CommandLookupTopicResponse{
+				RequestId:        proto.Uint64(cmd.GetLookupTopic().GetRequestId()),
+				Response:         pb.CommandLookupTopicResponse_Connect.Enum(),
+				BrokerServiceUrl: proto.String(b.url),
+				Authoritative:    proto.Bool(true),
+			},
+		}, nil)
+
+	case pb.BaseCommand_PARTITIONED_METADATA:
+		metadata := cmd.GetPartitionMetadata()
+		c.writeCommand(&pb.BaseCommand{
+			Type: pb.BaseCommand_PARTITIONED_METADATA_RESPONSE.Enum(),
+			PartitionMetadataResponse: &pb.CommandPartitionedTopicMetadataResponse{
+				RequestId:  proto.Uint64(metadata.GetRequestId()),
+				Response:   pb.CommandPartitionedTopicMetadataResponse_Success.Enum(),
+				Partitions: proto.Uint32(uint32(b.partitions[fullTopicName(metadata.GetTopic())])),
+			},
+		}, nil)
+
+	case pb.BaseCommand_GET_TOPICS_OF_NAMESPACE:
+		c.handleGetTopicsOfNamespace(cmd.GetGetTopicsOfNamespace())
+
+	case pb.BaseCommand_PRODUCER:
+		c.handleProducer(cmd.GetProducer())
+
+	case pb.BaseCommand_SEND:
+		c.handleSend(cmd.GetSend(), headersAndPayload)
+
+	case pb.BaseCommand_CLOSE_PRODUCER:
+		closeProducer := cmd.GetCloseProducer()
+		if prod, ok := c.producers[closeProducer.GetProducerId()]; ok {
+			delete(c.producers, closeProducer.GetProducerId())
+			prod.topic.removeProducer(prod)
+		}
+		c.writeSuccess(closeProducer.GetRequestId())
+
+	case pb.BaseCommand_SUBSCRIBE:
+		c.handleSubscribe(cmd.GetSubscribe())
+
+	case pb.BaseCommand_FLOW:
+		flow := cmd.GetFlow()
+		if cons, ok := c.consumers[flow.GetConsumerId()]; ok {
+			cons.permits += int(flow.GetMessagePermits())
+			cons.sub.dispatch()
+		}
+
+	case pb.BaseCommand_ACK:
+		c.handleAck(cmd.GetAck())
+
+	case pb.BaseCommand_REDELIVER_UNACKNOWLEDGED_MESSAGES:
+		redeliver := cmd.GetRedeliverUnacknowledgedMessages()
+		if cons, ok := c.consumers[redeliver.GetConsumerId()]; ok {
+			cons.sub.redeliver(cons, redeliver.GetMessageIds())
+			cons.sub.dispatch()
+		}
+
+	case pb.BaseCommand_SEEK:
+		c.handleSeek(cmd.GetSeek())
+
+	case pb.BaseCommand_GET_LAST_MESSAGE_ID:
+		c.handleGetLastMessageID(cmd.GetGetLastMessageId())
+
+	case pb.BaseCommand_UNSUBSCRIBE:
+		unsubscribe := cmd.GetUnsubscribe()
+		if cons, ok := c.consumers[unsubscribe.GetConsumerId()]; ok {
+			if len(cons.sub.consumers) > 1 {
+				c.writeError(unsubscribe.GetRequestId(), pb.ServerError_ConsumerBusy,
+					"the subscription has other consumers")
+				return
+			}
+			delete(c.consumers, cons.id)
+			delete(cons.sub.topic.subscriptions, cons.sub.name)
+			cons.sub.consumers = nil
+		}
+		c.writeSuccess(unsubscribe.GetRequestId())
+
+	case pb.BaseCommand_CLOSE_CONSUMER:
+		closeConsumer := cmd.GetCloseConsumer()
+		if cons, ok := c.consumers[closeConsumer.GetConsumerId()]; ok {
+			delete(c.consumers, cons.id)
+			cons.sub.removeConsumer(cons)
+		}
+		c.writeSuccess(closeConsumer.GetRequestId())
+	}
+}
+
+func (c *serverConn) handleGetTopicsOfNamespace(getTopics *pb.CommandGetTopicsOfNamespace) {
+	b := c.broker
+	var topics []string
+	add := func(name string) {
+		tn, err := internal.ParseTopicName(name)
+		if err != nil || tn.Namespace != getTopics.GetNamespace() {
+			return
+		}
+		switch getTopics.GetMode() {
+		case pb.CommandGetTopicsOfNamespace_PERSISTENT:
+			if tn.Domain != "persistent" {
+				return
+			}
+		case pb.CommandGetTopicsOfNamespace_NON_PERSISTENT:
+			if tn.Domain != "non-persistent" {
+				return
+			}
+		}
+		topics = append(topics, name)
+	}
+
+	for name, partitions := range b.partitions {
+		for i := 0; i < partitions; i++ {
+			add(fmt.Sprintf("%s-partition-%d", name, i))
+		}
+	}
+	for name := range b.topics {
+		if tn, err := internal.ParseTopicName(name); err == nil && tn.Partition < 0 {
+			add(name)
+		}
+	}
+	sort.Strings(topics)
+
+	c.writeCommand(&pb.BaseCommand{
+		Type: pb.BaseCommand_GET_TOPICS_OF_NAMESPACE_RESPONSE.Enum(),
+		GetTopicsOfNamespaceResponse: &pb.CommandGetTopicsOfNamespaceResponse{
+			RequestId: proto.Uint64(getTopics.GetRequestId()),
+			Topics:    topics,
+		},
+	}, nil)
+}
+
+func (c *serverConn) handleProducer(cmd *pb.CommandProducer) {
+	b := c.broker
+	if _, ok := b.partitions[fullTopicName(cmd.GetTopic())]; ok {
+		c.writeError(cmd.GetRequestId(), pb.ServerError_TopicNotFound,
+			"the partitions of a partitioned topic must be produced to")
+		return
+	}
+
+	p := &producer{
+		id:         cmd.GetProducerId(),
+		requestID:  cmd.GetRequestId(),
+		name:       cmd.GetProducerName(),
+		conn:       c,
+		topic:      b.getTopic(fullTopicName(cmd.GetTopic())),
+		accessMode: cmd.GetProducerAccessMode(),
+	}
+	if p.name == "" {
+		b.producers++
+		p.name = fmt.Sprintf("%s-%d", serverVersion, b.producers)
+	}
+	if !p.topic.addProducer(p) {
+		serverError := pb.ServerError_ProducerFenced
+		if p.accessMode == pb.ProducerAccessMode_Shared {
+			serverError = pb.ServerError_ProducerBusy
+		}
+		c.writeError(p.requestID, serverError, "the topic has an exclusive producer")
+		return
+	}
+	c.producers[p.id] = p
+	p.success()
+}
+
+func (c *serverConn) handleSend(send *pb.CommandSend, headersAndPayload []byte) {
+	prod, ok := c.producers[send.GetProducerId()]
+	if !ok || !prod.ready {
+		c.writeCommand(&pb.BaseCommand{
+			Type: pb.BaseCommand_SEND_ERROR.Enum(),
+			SendError: &pb.CommandSendError{
+				ProducerId: proto.Uint64(send.GetProducerId()),
+				SequenceId: proto.Uint64(send.GetSequenceId()),
+				Error:      pb.ServerError_UnknownError.Enum(),
+				Message:    proto.String("unknown producer"),
+			},
+		}, nil)
+		return
+	}
+
+	t := prod.topic
+	if t.sequenceIDs != nil {
+		// the duplicates are acknowledged without being stored
+		last, ok := t.sequenceIDs[prod.name]
+		if ok && int64(send.GetSequenceId()) <= last {
+			c.writeCommand(&pb.BaseCommand{
+				Type: pb.BaseCommand_SEND_RECEIPT.Enum(),
+				SendReceipt: &pb.CommandSendReceipt{
+					ProducerId: proto.Uint64(send.GetProducerId()),
+					SequenceId: proto.Uint64(send.GetSequenceId()),
+					MessageId:  &pb.MessageIdData{LedgerId: proto.Uint64(math.MaxUint64), EntryId: proto.Uint64(math.MaxUint64)},
+				},
+			}, nil)
+			return
+		}
+		numMessages := int64(send.GetNumMessages())
+		if numMessages < 1 {
+			numMessages = 1
+		}
+		t.sequenceIDs[prod.name] = int64(send.GetSequenceId()) + numMessages - 1
+	}
+	e := &entry{
+		id:                uint64(len(t.entries)),
+		numMessages:       int(send.GetNumMessages()),
+		headersAndPayload: headersAndPayload,
+	}
+	if metadata, err := internal.NewMessageReaderFromArray(headersAndPayload).ReadMessageMetadata(); err == nil {
+		e.publishTime = metadata.GetPublishTime()
+		e.batched = metadata.NumMessagesInBatch != nil
+	}
+	t.entries = append(t.entries, e)
+
+	c.writeCommand(&pb.BaseCommand{
+		Type: pb.BaseCommand_SEND_RECEIPT.Enum(),
+		SendReceipt: &pb.CommandSendReceipt{
+			ProducerId: proto.Uint64(send.GetProducerId()),
+			SequenceId: proto.Uint64(send.GetSequenceId()),
+			MessageId:  t.messageID(e),
+		},
+	}, nil)
+
+	for _, sub := range t.subscriptions {
+		sub.dispatch()
+	}
+}
+
+func (c *serverConn) handleSubscribe(subscribe *pb.CommandSubscribe) {
+	b := c.broker
+	if _, ok := b.partitions[fullTopicName(subscribe.GetTopic())]; ok {
+		c.writeError(subscribe.GetRequestId(), pb.ServerError_TopicNotFound,
+			"the partitions of a partitioned topic must be subscribed to")
+		return
+	}
+
+	t := b.getTopic(fullTopicName(subscribe.GetTopic()))
+	sub, ok := t.subscriptions[subscribe.GetSubscription()]
+	if !ok {
+		sub = &subscription{
+			topic:           t,
+			name:            subscribe.GetSubscription(),
+			subType:         subscribe.GetSubType(),
+			durable:         subscribe.GetDurable(),
+			unacked:         make(map[uint64]*consumer),
+			redeliveryCount: make(map[uint64]uint32),
+		}
+		switch {
+		case subscribe.StartMessageId != nil:
+			sub.cursor = t.position(subscribe.GetStartMessageId())
+		case subscribe.GetInitialPosition() == pb.CommandSubscribe_Earliest:
+			sub.cursor = 0
+		default:
+			sub.cursor = uint64(len(t.entries))
+		}
+		t.subscriptions[sub.name] = sub
+	} else if sub.subType != subscribe.GetSubType() {
+		c.writeError(subscribe.GetRequestId(), pb.ServerError_ConsumerBusy,
+			"the subscription has a different type")
+		return
+	} else if sub.subType == pb.CommandSubscribe_Exclusive && len(sub.consumers) > 0 {
+		c.writeError(subscribe.GetRequestId(), pb.ServerError_ConsumerBusy,
+			"the exclusive subscription already has a consumer")
+		return
+	}
+
+	cons := &consumer{
+		id:   subscribe.GetConsumerId(),
+		conn: c,
+		sub:  sub,
+	}
+	sub.consumers = append(sub.consumers, cons)
+	c.consumers[cons.id] = cons
+	c.writeSuccess(subscribe.GetRequestId())
+}
+
+func (c *serverConn) handleAck(ack *pb.CommandAck) {
+	if cons, ok := c.consumers[ack.GetConsumerId()]; ok {
+		cons.sub.ack(ack.GetAckType(), ack.GetMessageId())
+	}
+
+	// the acks of a transaction are answered
+	if ack.TxnidMostBits != nil || ack.TxnidLeastBits != nil {
+		c.writeCommand(&pb.BaseCommand{
+			Type: pb.BaseCommand_ACK_RESPONSE.Enum(),
+			AckResponse: &pb.CommandAckResponse{
+				ConsumerId:     proto.Uint64(ack.GetConsumerId()),
+				TxnidLeastBits: proto.Uint64(ack.GetTxnidLeastBits()),
+				TxnidMostBits:  proto.Uint64(ack.GetTxnidMostBits()),
+			},
+		}, nil)
+	}
+}
+
+func (c *serverConn) handleSeek(seek *pb.CommandSeek) {
+	cons, ok := c.consumers[seek.GetConsumerId()]
+	if !ok {
+		c.writeError(seek.GetRequestId(), pb.ServerError_ConsumerNotFound, "unknown consumer")
+		return
+	}
+
+	t := cons.sub.topic
+	var position uint64
+	if seek.MessageId != nil {
+		position = t.position(seek.GetMessageId())
+	} else {
+		publishTime := seek.GetMessagePublishTime()
+		position = uint64(sort.Search(len(t.entries), func(i int) bool {
+			return t.entries[i].publishTime >= publishTime
+		}))
+	}
+	cons.sub.seek(cons, position)
+	c.writeSuccess(seek.GetRequestId())
+}
+
+func (c *serverConn) handleGetLastMessageID(getLastMessageID *pb.CommandGetLastMessageId) {
+	cons, ok := c.consumers[getLastMessageID.GetConsumerId()]
+	if !ok {
+		c.writeError(getLastMessageID.GetRequestId(), pb.ServerError_ConsumerNotFound, "unknown consumer")
+		return
+	}
+
+	t := cons.sub.topic
+	// the entry id of an empty topic is -1
+	lastMessageID := &pb.MessageIdData{
+		LedgerId: proto.Uint64(t.ledgerID),
+		EntryId:  proto.Uint64(math.MaxUint64),
+	}
+	if len(t.entries) > 0 {
+		last := t.entries[len(t.entries)-1]
+		lastMessageID = t.messageID(last)
+		if last.batched {
+			lastMessageID.BatchIndex = proto.Int32(int32(last.numMessages - 1))
+		}
+	}
+	c.writeCommand(&pb.BaseCommand{
+		Type: pb.BaseCommand_GET_LAST_MESSAGE_ID_RESPONSE.Enum(),
+		GetLastMessageIdResponse: &pb.CommandGetLastMessageIdResponse{
+			RequestId:     proto.Uint64(getLastMessageID.GetRequestId()),
+			LastMessageId: lastMessageID,
+		},
+	}, nil)
+}
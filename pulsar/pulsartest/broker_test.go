@@ -0,0 +1,224 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package pulsartest_test
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/apache/pulsar-client-go/pulsar/pulsartest"
+)
+
+func newClient(t *testing.T) (*pulsartest.Broker, pulsar.Client) {
+	broker, err := pulsartest.NewBroker()
+	require.NoError(t, err)
+
+	client, err := pulsar.NewClient(pulsar.ClientOptions{
+		URL:              broker.URL(),
+		OperationTimeout: 5 * time.Second,
+	})
+	require.NoError(t, err)
+	return broker, client
+}
+
+func produce(t *testing.T, client pulsar.Client, topic string, batching bool, n int) {
+	producer, err := client.CreateProducer(pulsar.ProducerOptions{
+		Topic:           topic,
+		DisableBatching: !batching,
+	})
+	require.NoError(t, err)
+	defer producer.Close()
+
+	for i := 0; i < n; i++ {
+		producer.SendAsync(context.Background(), &pulsar.ProducerMessage{
+			Payload: []byte(fmt.Sprintf("msg-%d", i)),
+		}, func(_ pulsar.MessageID, _ *pulsar.ProducerMessage, err error) {
+			assert.NoError(t, err)
+		})
+	}
+	assert.NoError(t, producer.Flush())
+}
+
+func receive(t *testing.T, consumer pulsar.Consumer) pulsar.Message {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	msg, err := consumer.Receive(ctx)
+	require.NoError(t, err)
+	return msg
+}
+
+func TestBrokerProduceConsume(t *testing.T) {
+	broker, client := newClient(t)
+	defer broker.Close()
+	defer client.Close()
+
+	consumer, err := client.Subscribe(pulsar.ConsumerOptions{
+		Topic:                       "my-topic",
+		SubscriptionName:            "my-sub",
+		SubscriptionInitialPosition: pulsar.SubscriptionPositionEarliest,
+	})
+	require.NoError(t, err)
+	defer consumer.Close()
+
+	produce(t, client, "my-topic", false, 10)
+	produce(t, client, "my-topic", true, 10)
+	assert.Equal(t, 11, broker.Entries("my-topic"))
+
+	for i := 0; i < 20; i++ {
+		msg := receive(t, consumer)
+		assert.Equal(t, fmt.Sprintf("msg-%d", i%10), string(msg.Payload()))
+		consumer.Ack(msg)
+	}
+}
+
+func TestBrokerPartitionedTopic(t *testing.T) {
+	broker, client := newClient(t)
+	defer broker.Close()
+	defer client.Close()
+
+	require.NoError(t, broker.CreatePartitionedTopic("my-partitioned-topic", 3))
+	partitions, err := client.TopicPartitions("my-partitioned-topic")
+	require.NoError(t, err)
+	assert.Len(t, partitions, 3)
+
+	consumer, err := client.Subscribe(pulsar.ConsumerOptions{
+		Topic:            "my-partitioned-topic",
+		SubscriptionName: "my-sub",
+		Type:             pulsar.Shared,
+	})
+	require.NoError(t, err)
+	defer consumer.Close()
+
+	produce(t, client, "my-partitioned-topic", false, 9)
+
+	var received []string
+	for i := 0; i < 9; i++ {
+		msg := receive(t, consumer)
+		received = append(received, string(msg.Payload()))
+		consumer.Ack(msg)
+	}
+	sort.Strings(received)
+	assert.Equal(t, []string{"msg-0", "msg-1", "msg-2", "msg-3", "msg-4", "msg-5", "msg-6", "msg-7", "msg-8"},
+		received)
+	for _, partition := range partitions {
+		assert.Equal(t, 3, broker.Entries(partition))
+	}
+
+	assert.Error(t, broker.UpdatePartitionedTopic("my-partitioned-topic", 2))
+	assert.Error(t, broker.UpdatePartitionedTopic("my-topic", 2))
+	require.NoError(t, broker.UpdatePartitionedTopic("my-partitioned-topic", 4))
+	partitions, err = client.TopicPartitions("my-partitioned-topic")
+	require.NoError(t, err)
+	assert.Len(t, partitions, 4)
+}
+
+func TestBrokerRedelivery(t *testing.T) {
+	broker, client := newClient(t)
+	defer broker.Close()
+	defer client.Close()
+
+	consumer, err := client.Subscribe(pulsar.ConsumerOptions{
+		Topic:               "my-topic",
+		SubscriptionName:    "my-sub",
+		Type:                pulsar.Shared,
+		NackRedeliveryDelay: 100 * time.Millisecond,
+	})
+	require.NoError(t, err)
+
+	produce(t, client, "my-topic", false, 2)
+
+	msg := receive(t, consumer)
+	assert.Equal(t, "msg-0", string(msg.Payload()))
+	consumer.Nack(msg)
+	msg = receive(t, consumer)
+	assert.Equal(t, "msg-1", string(msg.Payload()))
+	consumer.Ack(msg)
+
+	msg = receive(t, consumer)
+	assert.Equal(t, "msg-0", string(msg.Payload()))
+	assert.Equal(t, uint32(1), msg.RedeliveryCount())
+
+	// the unacked messages of a closed consumer are dispatched to the next one
+	consumer.Close()
+	consumer, err = client.Subscribe(pulsar.ConsumerOptions{
+		Topic:            "my-topic",
+		SubscriptionName: "my-sub",
+		Type:             pulsar.Shared,
+	})
+	require.NoError(t, err)
+	defer consumer.Close()
+
+	msg = receive(t, consumer)
+	assert.Equal(t, "msg-0", string(msg.Payload()))
+	assert.Equal(t, uint32(2), msg.RedeliveryCount())
+}
+
+func TestBrokerExclusiveSubscription(t *testing.T) {
+	broker, client := newClient(t)
+	defer broker.Close()
+	defer client.Close()
+
+	consumer, err := client.Subscribe(pulsar.ConsumerOptions{
+		Topic:            "my-topic",
+		SubscriptionName: "my-sub",
+	})
+	require.NoError(t, err)
+	defer consumer.Close()
+
+	_, err = client.Subscribe(pulsar.ConsumerOptions{
+		Topic:            "my-topic",
+		SubscriptionName: "my-sub",
+	})
+	assert.Error(t, err)
+}
+
+func TestBrokerReader(t *testing.T) {
+	broker, client := newClient(t)
+	defer broker.Close()
+	defer client.Close()
+
+	produce(t, client, "my-topic", false, 10)
+
+	reader, err := client.CreateReader(pulsar.ReaderOptions{
+		Topic:          "my-topic",
+		StartMessageID: pulsar.EarliestMessageID(),
+	})
+	require.NoError(t, err)
+	defer reader.Close()
+
+	var ids []pulsar.MessageID
+	for reader.HasNext() {
+		msg, err := reader.Next(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("msg-%d", len(ids)), string(msg.Payload()))
+		ids = append(ids, msg.ID())
+	}
+	require.Len(t, ids, 10)
+
+	require.NoError(t, reader.Seek(ids[4]))
+	msg, err := reader.Next(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "msg-4", string(msg.Payload()))
+}
@@ -34,7 +34,7 @@ type ReaderOptions struct {
 	// This argument is required when constructing the reader.
 	Topic string
 
-	// Name set the reader name.
+	// Name set the reader name. A random name is generated when it is not set.
 	Name string
 
 	// Attach a set of application defined properties to the reader
@@ -68,6 +68,14 @@ type ReaderOptions struct {
 	// SubscriptionRolePrefix set the subscription role prefix. The default prefix is "reader".
 	SubscriptionRolePrefix string
 
+	// Schema represents the schema implementation, used to decode the messages with Message.GetSchemaValue.
+	Schema Schema
+
+	// PayloadCodecs decode the payloads of the messages encoded by the producers with a PayloadCodec, eg. to read
+	// encrypted messages, as ConsumerOptions.PayloadCodecs.
+	// Default is nil, the payloads are read as received.
+	PayloadCodecs []PayloadCodec
+
 	// If enabled, the reader will read messages from the compacted topic rather than reading the full message backlog
 	// of the topic. This means that, if the topic has been compacted, the reader will only see the latest value for
 	// each key in the topic, up until the point in the topic message backlog that has been compacted. Beyond that
@@ -86,7 +94,9 @@ type Reader interface {
 	// Next read the next message in the topic, blocking until a message is available
 	Next(context.Context) (Message, error)
 
-	// HasNext check if there is any message available to read from the current position
+	// HasNext check if there is any message available to read from the current position. The id of the last
+	// message of the topic is asked to the broker once the reader has read up to the last one known, so that
+	// polling HasNext follows the topic as it grows. It returns false when the broker can't be reached.
 	HasNext() bool
 
 	// Close the reader and stop the broker to push more messages
@@ -96,7 +106,8 @@ type Reader interface {
 	// The message id can either be a specific message or represent the first or last messages in the topic.
 	//
 	// Note: this operation can only be done on non-partitioned topics. For these, one can rather perform the
-	//       seek() on the individual partitions.
+	//       seek() on the individual partitions. The reader seeking EarliestMessageID() or LatestMessageID()
+	//       reads the messages from that position, whatever its start message.
 	Seek(MessageID) error
 
 	// Reset the subscription associated with this reader to a specific message publish time.
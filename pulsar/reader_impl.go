@@ -33,8 +33,10 @@ const (
 
 type reader struct {
 	sync.Mutex
+	client              *client
 	pc                  *partitionConsumer
 	messageCh           chan ConsumerMessage
+	closeOnce           sync.Once
 	lastMessageInBroker trackingMessageID
 	log                 log.Logger
 	metrics             *internal.TopicMetrics
@@ -44,25 +46,15 @@ func newReader(client *client, options ReaderOptions) (Reader, error) {
 	if options.Topic == "" {
 		return nil, newError(InvalidConfiguration, "Topic is required")
 	}
+	if _, err := validateTopicNames(options.Topic); err != nil {
+		return nil, err
+	}
 
 	if options.StartMessageID == nil {
 		return nil, newError(InvalidConfiguration, "StartMessageID is required")
 	}
 
-	startMessageID, ok := toTrackingMessageID(options.StartMessageID)
-	if !ok {
-		// a custom type satisfying MessageID may not be a messageID or trackingMessageID
-		// so re-create messageID using its data
-		deserMsgID, err := deserializeMessageID(options.StartMessageID.Serialize())
-		if err != nil {
-			return nil, err
-		}
-		// de-serialized MessageID is a messageID
-		startMessageID = trackingMessageID{
-			messageID:    deserMsgID.(messageID),
-			receivedTime: time.Now(),
-		}
-	}
+	startMessageID, _ := toTrackingMessageID(options.StartMessageID)
 
 	subscriptionName := options.SubscriptionRolePrefix
 	if subscriptionName == "" {
@@ -70,6 +62,11 @@ func newReader(client *client, options ReaderOptions) (Reader, error) {
 	}
 	subscriptionName += "-" + generateRandomName()
 
+	consumerName := options.Name
+	if consumerName == "" {
+		consumerName = generateRandomName()
+	}
+
 	receiverQueueSize := options.ReceiverQueueSize
 	if receiverQueueSize <= 0 {
 		receiverQueueSize = defaultReceiverQueueSize
@@ -77,7 +74,7 @@ func newReader(client *client, options ReaderOptions) (Reader, error) {
 
 	consumerOptions := &partitionConsumerOpts{
 		topic:                      options.Topic,
-		consumerName:               options.Name,
+		consumerName:               consumerName,
 		subscription:               subscriptionName,
 		subscriptionType:           Exclusive,
 		receiverQueueSize:          receiverQueueSize,
@@ -86,11 +83,14 @@ func newReader(client *client, options ReaderOptions) (Reader, error) {
 		subscriptionMode:           nonDurable,
 		readCompacted:              options.ReadCompacted,
 		metadata:                   options.Properties,
+		schema:                     options.Schema,
+		payloadCodecs:              options.PayloadCodecs,
 		nackRedeliveryDelay:        defaultNackRedeliveryDelay,
 		replicateSubscriptionState: false,
 	}
 
 	reader := &reader{
+		client:    client,
 		messageCh: make(chan ConsumerMessage),
 		log:       client.log.SubLogger(log.Fields{"topic": options.Topic}),
 		metrics:   client.metrics.GetTopicMetrics(options.Topic),
@@ -102,7 +102,8 @@ func newReader(client *client, options ReaderOptions) (Reader, error) {
 		return nil, err
 	}
 
-	pc, err := newPartitionConsumer(nil, client, consumerOptions, reader.messageCh, dlq, reader.metrics)
+	pc, err := newPartitionConsumer(context.Background(), nil, client, consumerOptions, reader.messageCh, dlq,
+		reader.metrics)
 	if err != nil {
 		close(reader.messageCh)
 		return nil, err
@@ -122,14 +123,20 @@ func (r *reader) Next(ctx context.Context) (Message, error) {
 		select {
 		case cm, ok := <-r.messageCh:
 			if !ok {
-				return nil, newError(ConsumerClosed, "consumer closed")
+				return nil, ErrConsumerClosed
+			}
+			if seekedPast(cm) {
+				continue
 			}
 
 			// Acknowledge message immediately because the reader is based on non-durable subscription. When it reconnects,
 			// it will specify the subscription position anyway
 			msgID := cm.Message.ID()
 			if mid, ok := toTrackingMessageID(msgID); ok {
+				// guarded by the lock, HasNext and Seek use it
+				r.Lock()
 				r.pc.lastDequeuedMsg = mid
+				r.Unlock()
 				r.pc.AckID(mid)
 				return cm.Message, nil
 			}
@@ -141,22 +148,51 @@ func (r *reader) Next(ctx context.Context) (Message, error) {
 }
 
 func (r *reader) HasNext() bool {
-	if !r.lastMessageInBroker.Undefined() && r.hasMoreMessages() {
+	// the last message id known from the broker is only asked again once the reader caught up with it
+	r.Lock()
+	hasMore := !r.lastMessageInBroker.Undefined() && r.hasMoreMessages()
+	r.Unlock()
+	if hasMore {
 		return true
 	}
 
+	// asked without the lock, which Next takes for each message, as the request may be retried for long
+	lastMsgID, err := r.lastMessageIDInBroker()
+	if err != nil {
+		r.log.WithError(err).Error("Failed to get last message id from broker")
+		return false
+	}
+
+	r.Lock()
+	defer r.Unlock()
+	r.lastMessageInBroker = lastMsgID
+	return r.hasMoreMessages()
+}
+
+// lastMessageIDInBroker asks the broker for the id of the last message of the topic, the request is retried
+// with the backoff policy of the client, eg. while the reader reconnects, until the operation timeout
+func (r *reader) lastMessageIDInBroker() (trackingMessageID, error) {
+	backoff := r.client.backoffPolicy()
+	startTime := time.Now()
 	for {
 		lastMsgID, err := r.pc.getLastMessageID()
-		if err != nil {
-			r.log.WithError(err).Error("Failed to get last message id from broker")
-			continue
-		} else {
-			r.lastMessageInBroker = lastMsgID
-			break
+		if err == nil {
+			return lastMsgID, nil
+		}
+		if err == ErrConsumerClosed {
+			return trackingMessageID{}, err
 		}
-	}
 
-	return r.hasMoreMessages()
+		d := backoff.Next()
+		if time.Since(startTime)+d > r.client.operationTimeout {
+			return trackingMessageID{}, err
+		}
+		select {
+		case <-time.After(d):
+		case <-r.pc.closeCh:
+			return trackingMessageID{}, ErrConsumerClosed
+		}
+	}
 }
 
 func (r *reader) hasMoreMessages() bool {
@@ -173,8 +209,13 @@ func (r *reader) hasMoreMessages() bool {
 }
 
 func (r *reader) Close() {
-	r.pc.Close()
-	r.metrics.ReadersClosed.Inc()
+	r.closeOnce.Do(func() {
+		r.pc.Close()
+		// Next returns an error rather than blocking once the reader is closed
+		close(r.messageCh)
+		r.client.handlers.Del(r)
+		r.metrics.ReadersClosed.Inc()
+	})
 }
 
 func (r *reader) messageID(msgID MessageID) (trackingMessageID, bool) {
@@ -185,8 +226,8 @@ func (r *reader) messageID(msgID MessageID) (trackingMessageID, bool) {
 	}
 
 	partition := int(mid.partitionIdx)
-	// did we receive a valid partition index?
-	if partition < 0 {
+	// did we receive a valid partition index? the earliest and latest ids have none
+	if partition < 0 && !mid.isSentinel() {
 		r.log.Warnf("invalid partition index %d expected", partition)
 		return trackingMessageID{}, false
 	}
@@ -203,12 +244,12 @@ func (r *reader) Seek(msgID MessageID) error {
 		return nil
 	}
 
-	return r.pc.Seek(mid)
+	return r.pc.Seek(context.Background(), mid)
 }
 
 func (r *reader) SeekByTime(time time.Time) error {
 	r.Lock()
 	defer r.Unlock()
 
-	return r.pc.SeekByTime(time)
+	return r.pc.SeekByTime(context.Background(), time)
 }
@@ -19,11 +19,16 @@ package pulsar
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/pulsar-client-go/pulsar/internal"
+	"github.com/apache/pulsar-client-go/pulsar/pulsartest"
 )
 
 func TestReaderConfigErrors(t *testing.T) {
@@ -109,7 +114,11 @@ func TestReaderConnectError(t *testing.T) {
 	assert.Nil(t, reader)
 	assert.NotNil(t, err)
 
-	assert.Equal(t, err.Error(), "connection error")
+	var e *Error
+	if assert.True(t, errors.As(err, &e)) {
+		assert.Equal(t, ConnectError, e.Result())
+	}
+	assert.True(t, errors.Is(err, internal.ErrConnectionFailed))
 }
 
 func TestReaderOnSpecificMessage(t *testing.T) {
@@ -384,6 +393,7 @@ func TestReaderHasNext(t *testing.T) {
 }
 
 type myMessageID struct {
+	MessageID
 	data []byte
 }
 
@@ -423,7 +433,8 @@ func TestReaderOnSpecificMessageWithCustomMessageID(t *testing.T) {
 
 	// custom start message ID
 	myStartMsgID := &myMessageID{
-		data: msgIDs[4].Serialize(),
+		MessageID: msgIDs[4],
+		data:      msgIDs[4].Serialize(),
 	}
 
 	// attempt to create reader on 5th message (not included)
@@ -638,3 +649,190 @@ func TestReaderWithMultiHosts(t *testing.T) {
 
 	assert.Equal(t, 10, i)
 }
+
+func TestReaderNextAfterClose(t *testing.T) {
+	client, err := NewClient(ClientOptions{
+		URL: lookupURL,
+	})
+	assert.Nil(t, err)
+	defer client.Close()
+
+	reader, err := client.CreateReader(ReaderOptions{
+		Topic:          newTopicName(),
+		StartMessageID: EarliestMessageID(),
+	})
+	assert.Nil(t, err)
+	reader.Close()
+
+	_, err = reader.Next(context.Background())
+	assert.Equal(t, ErrConsumerClosed, err)
+}
+
+func TestReaderSeekSentinels(t *testing.T) {
+	broker, err := pulsartest.NewBroker()
+	assert.Nil(t, err)
+	defer broker.Close()
+	client, err := NewClient(ClientOptions{URL: broker.URL()})
+	assert.Nil(t, err)
+	defer client.Close()
+
+	topic := newTopicName()
+	ctx := context.Background()
+	producer, err := client.CreateProducer(ProducerOptions{Topic: topic, DisableBatching: true})
+	assert.Nil(t, err)
+	defer producer.Close()
+
+	msgIDs := [10]MessageID{}
+	for i := 0; i < 10; i++ {
+		msgIDs[i], err = producer.Send(ctx, &ProducerMessage{Payload: []byte(fmt.Sprintf("hello-%d", i))})
+		assert.NoError(t, err)
+	}
+
+	reader, err := client.CreateReader(ReaderOptions{
+		Topic:          topic,
+		StartMessageID: msgIDs[4],
+	})
+	assert.Nil(t, err)
+	defer reader.Close()
+
+	readAll := func(from int) {
+		for i := from; i < 10; i++ {
+			assert.True(t, reader.HasNext())
+			msg, err := reader.Next(ctx)
+			if assert.NoError(t, err) {
+				assert.Equal(t, fmt.Sprintf("hello-%d", i), string(msg.Payload()))
+			}
+		}
+		assert.False(t, reader.HasNext())
+	}
+	readAll(5)
+
+	// the messages before the start of the reader are read after seeking the earliest id
+	assert.Nil(t, reader.Seek(EarliestMessageID()))
+	readAll(0)
+
+	assert.Nil(t, reader.Seek(EarliestMessageID()))
+	assert.Nil(t, reader.Seek(LatestMessageID()))
+	assert.False(t, reader.HasNext())
+
+	_, err = producer.Send(ctx, &ProducerMessage{Payload: []byte("hello-10")})
+	assert.NoError(t, err)
+	assert.True(t, reader.HasNext())
+	msg, err := reader.Next(ctx)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "hello-10", string(msg.Payload()))
+	}
+}
+
+func TestReaderOptions(t *testing.T) {
+	broker, err := pulsartest.NewBroker()
+	assert.Nil(t, err)
+	defer broker.Close()
+	client, err := NewClient(ClientOptions{URL: broker.URL()})
+	assert.Nil(t, err)
+	defer client.Close()
+
+	topic := newTopicName()
+	ctx := context.Background()
+	producer, err := client.CreateProducer(ProducerOptions{Topic: topic, PayloadCodec: reverseCodec{}})
+	assert.Nil(t, err)
+	defer producer.Close()
+	_, err = producer.Send(ctx, &ProducerMessage{Payload: []byte("hello")})
+	assert.Nil(t, err)
+
+	r, err := client.CreateReader(ReaderOptions{
+		Topic:                  topic,
+		StartMessageID:         EarliestMessageID(),
+		ReceiverQueueSize:      5,
+		SubscriptionRolePrefix: "tail",
+		PayloadCodecs:          []PayloadCodec{reverseCodec{}},
+	})
+	assert.Nil(t, err)
+	defer r.Close()
+
+	pc := r.(*reader).pc
+	assert.Equal(t, 5, cap(pc.queueCh))
+	assert.True(t, strings.HasPrefix(pc.options.subscription, "tail-"))
+
+	msg, err := r.Next(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, "hello", string(msg.Payload()))
+}
+
+func TestReaderHasNextTailing(t *testing.T) {
+	broker, err := pulsartest.NewBroker()
+	assert.Nil(t, err)
+	defer broker.Close()
+	client, err := NewClient(ClientOptions{URL: broker.URL()})
+	assert.Nil(t, err)
+	defer client.Close()
+
+	topic := newTopicName()
+	ctx := context.Background()
+	producer, err := client.CreateProducer(ProducerOptions{Topic: topic, DisableBatching: true})
+	assert.Nil(t, err)
+	defer producer.Close()
+
+	reader, err := client.CreateReader(ReaderOptions{Topic: topic, StartMessageID: EarliestMessageID()})
+	assert.Nil(t, err)
+	assert.False(t, reader.HasNext())
+
+	// polling HasNext follows the messages sent after the reader caught up
+	for i := 0; i < 3; i++ {
+		_, err = producer.Send(ctx, &ProducerMessage{Payload: []byte(fmt.Sprintf("hello-%d", i))})
+		assert.Nil(t, err)
+		assert.True(t, reader.HasNext())
+		msg, err := reader.Next(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, fmt.Sprintf("hello-%d", i), string(msg.Payload()))
+		assert.False(t, reader.HasNext())
+	}
+
+	reader.Close()
+	assert.False(t, reader.HasNext())
+}
+
+func TestReaderHasNextWhileReading(t *testing.T) {
+	broker, err := pulsartest.NewBroker()
+	assert.Nil(t, err)
+	defer broker.Close()
+	client, err := NewClient(ClientOptions{URL: broker.URL()})
+	assert.Nil(t, err)
+	defer client.Close()
+
+	topic := newTopicName()
+	ctx := context.Background()
+	producer, err := client.CreateProducer(ProducerOptions{Topic: topic, DisableBatching: true})
+	assert.Nil(t, err)
+	defer producer.Close()
+
+	reader, err := client.CreateReader(ReaderOptions{Topic: topic, StartMessageID: EarliestMessageID()})
+	assert.Nil(t, err)
+	defer reader.Close()
+
+	// HasNext is polled by another goroutine than the one reading the messages
+	done := make(chan struct{})
+	polled := make(chan struct{})
+	go func() {
+		defer close(polled)
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				reader.HasNext()
+			}
+		}
+	}()
+
+	for i := 0; i < 10; i++ {
+		_, err = producer.Send(ctx, &ProducerMessage{Payload: []byte(fmt.Sprintf("hello-%d", i))})
+		assert.Nil(t, err)
+		msg, err := reader.Next(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, fmt.Sprintf("hello-%d", i), string(msg.Payload()))
+	}
+	close(done)
+	<-polled
+	assert.False(t, reader.HasNext())
+}
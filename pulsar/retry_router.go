@@ -117,6 +117,8 @@ func (r *retryRouter) getProducer() Producer {
 		return r.producer
 	}
 
+	createInitialSubscription(r.client, r.policy.RetryLetterTopic, r.policy.InitialSubscriptionName, r.log)
+
 	// Retry to create producer indefinitely
 	backoff := &internal.Backoff{}
 	for {
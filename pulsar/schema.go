@@ -20,8 +20,9 @@ package pulsar
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"reflect"
-	"unsafe"
+	"unicode/utf8"
 
 	log "github.com/sirupsen/logrus"
 
@@ -92,17 +93,26 @@ type JSONSchema struct {
 }
 
 func NewJSONSchema(jsonAvroSchemaDef string, properties map[string]string) *JSONSchema {
+	js, err := NewJSONSchemaWithValidation(jsonAvroSchemaDef, properties)
+	if err != nil {
+		log.Fatalf("init codec error:%v", err)
+	}
+	return js
+}
+
+// NewJSONSchemaWithValidation creates the JSON schema, it returns the error of an invalid schema definition
+func NewJSONSchemaWithValidation(jsonAvroSchemaDef string, properties map[string]string) (*JSONSchema, error) {
 	js := new(JSONSchema)
 	avroCodec, err := initAvroCodec(jsonAvroSchemaDef)
 	if err != nil {
-		log.Fatalf("init codec error:%v", err)
+		return nil, err
 	}
 	schemaDef := NewSchemaDefinition(avroCodec)
 	js.SchemaInfo.Schema = schemaDef.Codec.Schema()
 	js.SchemaInfo.Type = JSON
 	js.SchemaInfo.Properties = properties
 	js.SchemaInfo.Name = "JSON"
-	return js
+	return js, nil
 }
 
 func (js *JSONSchema) Encode(data interface{}) ([]byte, error) {
@@ -114,7 +124,10 @@ func (js *JSONSchema) Decode(data []byte, v interface{}) error {
 }
 
 func (js *JSONSchema) Validate(message []byte) error {
-	return js.Decode(message, nil)
+	if !json.Valid(message) {
+		return newError(InvalidMessage, "data received by JSONSchema is not valid JSON")
+	}
+	return nil
 }
 
 func (js *JSONSchema) GetSchemaInfo() *SchemaInfo {
@@ -127,10 +140,19 @@ type ProtoSchema struct {
 }
 
 func NewProtoSchema(protoAvroSchemaDef string, properties map[string]string) *ProtoSchema {
+	ps, err := NewProtoSchemaWithValidation(protoAvroSchemaDef, properties)
+	if err != nil {
+		log.Fatalf("init codec error:%v", err)
+	}
+	return ps
+}
+
+// NewProtoSchemaWithValidation creates the protobuf schema, it returns the error of an invalid schema definition
+func NewProtoSchemaWithValidation(protoAvroSchemaDef string, properties map[string]string) (*ProtoSchema, error) {
 	ps := new(ProtoSchema)
 	avroCodec, err := initAvroCodec(protoAvroSchemaDef)
 	if err != nil {
-		log.Fatalf("init codec error:%v", err)
+		return nil, err
 	}
 	schemaDef := NewSchemaDefinition(avroCodec)
 	ps.AvroCodec.Codec = schemaDef.Codec
@@ -138,7 +160,7 @@ func NewProtoSchema(protoAvroSchemaDef string, properties map[string]string) *Pr
 	ps.SchemaInfo.Type = PROTOBUF
 	ps.SchemaInfo.Properties = properties
 	ps.SchemaInfo.Name = "Proto"
-	return ps
+	return ps, nil
 }
 
 func (ps *ProtoSchema) Encode(data interface{}) ([]byte, error) {
@@ -163,10 +185,19 @@ type AvroSchema struct {
 }
 
 func NewAvroSchema(avroSchemaDef string, properties map[string]string) *AvroSchema {
+	as, err := NewAvroSchemaWithValidation(avroSchemaDef, properties)
+	if err != nil {
+		log.Fatalf("init codec error:%v", err)
+	}
+	return as
+}
+
+// NewAvroSchemaWithValidation creates the Avro schema, it returns the error of an invalid schema definition
+func NewAvroSchemaWithValidation(avroSchemaDef string, properties map[string]string) (*AvroSchema, error) {
 	as := new(AvroSchema)
 	avroCodec, err := initAvroCodec(avroSchemaDef)
 	if err != nil {
-		log.Fatalf("init codec error:%v", err)
+		return nil, err
 	}
 	schemaDef := NewSchemaDefinition(avroCodec)
 	as.AvroCodec.Codec = schemaDef.Codec
@@ -174,18 +205,16 @@ func NewAvroSchema(avroSchemaDef string, properties map[string]string) *AvroSche
 	as.SchemaInfo.Type = AVRO
 	as.SchemaInfo.Name = "Avro"
 	as.SchemaInfo.Properties = properties
-	return as
+	return as, nil
 }
 
 func (as *AvroSchema) Encode(data interface{}) ([]byte, error) {
 	textual, err := json.Marshal(data)
 	if err != nil {
-		log.Errorf("serialize data error:%s", err.Error())
 		return nil, err
 	}
 	native, _, err := as.Codec.NativeFromTextual(textual)
 	if err != nil {
-		log.Errorf("convert native Go form to binary Avro data error:%s", err.Error())
 		return nil, err
 	}
 	return as.Codec.BinaryFromNative(nil, native)
@@ -194,24 +223,22 @@ func (as *AvroSchema) Encode(data interface{}) ([]byte, error) {
 func (as *AvroSchema) Decode(data []byte, v interface{}) error {
 	native, _, err := as.Codec.NativeFromBinary(data)
 	if err != nil {
-		log.Errorf("convert binary Avro data back to native Go form error:%s", err.Error())
 		return err
 	}
 	textual, err := as.Codec.TextualFromNative(nil, native)
 	if err != nil {
-		log.Errorf("convert native Go form to textual Avro data error:%s", err.Error())
 		return err
 	}
 	err = json.Unmarshal(textual, v)
 	if err != nil {
-		log.Errorf("unSerialize textual error:%s", err.Error())
 		return err
 	}
 	return nil
 }
 
 func (as *AvroSchema) Validate(message []byte) error {
-	return as.Decode(message, nil)
+	_, _, err := as.Codec.NativeFromBinary(message)
+	return err
 }
 
 func (as *AvroSchema) GetSchemaInfo() *SchemaInfo {
@@ -232,22 +259,32 @@ func NewStringSchema(properties map[string]string) *StringSchema {
 }
 
 func (ss *StringSchema) Encode(v interface{}) ([]byte, error) {
-	return []byte(v.(string)), nil
+	s, ok := v.(string)
+	if !ok {
+		return nil, fmt.Errorf("StringSchema expects a string value, received: %T", v)
+	}
+	return []byte(s), nil
 }
 
+// Decode decodes the UTF-8 data into a *string or a **string
 func (ss *StringSchema) Decode(data []byte, v interface{}) error {
-	bh := (*reflect.SliceHeader)(unsafe.Pointer(&data))
-	sh := reflect.StringHeader{
-		Data: bh.Data,
-		Len:  bh.Len,
+	s := string(data)
+	switch e := v.(type) {
+	case *string:
+		*e = s
+	case **string:
+		*e = &s
+	default:
+		return fmt.Errorf("StringSchema decodes into a *string or a **string, received: %T", v)
 	}
-	shPtr := (*string)(unsafe.Pointer(&sh))
-	reflect.ValueOf(v).Elem().Set(reflect.ValueOf(shPtr))
 	return nil
 }
 
 func (ss *StringSchema) Validate(message []byte) error {
-	return ss.Decode(message, nil)
+	if !utf8.Valid(message) {
+		return newError(InvalidMessage, "data received by StringSchema is not valid UTF-8")
+	}
+	return nil
 }
 
 func (ss *StringSchema) GetSchemaInfo() *SchemaInfo {
@@ -268,7 +305,11 @@ func NewBytesSchema(properties map[string]string) *BytesSchema {
 }
 
 func (bs *BytesSchema) Encode(data interface{}) ([]byte, error) {
-	return data.([]byte), nil
+	b, ok := data.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("BytesSchema expects a []byte value, received: %T", data)
+	}
+	return b, nil
 }
 
 func (bs *BytesSchema) Decode(data []byte, v interface{}) error {
@@ -277,13 +318,52 @@ func (bs *BytesSchema) Decode(data []byte, v interface{}) error {
 }
 
 func (bs *BytesSchema) Validate(message []byte) error {
-	return bs.Decode(message, nil)
+	return nil
 }
 
 func (bs *BytesSchema) GetSchemaInfo() *SchemaInfo {
 	return &bs.SchemaInfo
 }
 
+type BooleanSchema struct {
+	SchemaInfo
+}
+
+func NewBooleanSchema(properties map[string]string) *BooleanSchema {
+	booleanSchema := new(BooleanSchema)
+	booleanSchema.SchemaInfo.Properties = properties
+	booleanSchema.SchemaInfo.Schema = ""
+	booleanSchema.SchemaInfo.Type = BOOLEAN
+	booleanSchema.SchemaInfo.Name = "BOOLEAN"
+	return booleanSchema
+}
+
+func (bs *BooleanSchema) Encode(value interface{}) ([]byte, error) {
+	v, ok := value.(bool)
+	if !ok {
+		return nil, fmt.Errorf("BooleanSchema expects a bool value, received: %T", value)
+	}
+	var buf bytes.Buffer
+	err := WriteElements(&buf, v)
+	return buf.Bytes(), err
+}
+
+func (bs *BooleanSchema) Decode(data []byte, v interface{}) error {
+	buf := bytes.NewReader(data)
+	return ReadElements(buf, v)
+}
+
+func (bs *BooleanSchema) Validate(message []byte) error {
+	if len(message) != 1 {
+		return newError(InvalidMessage, "size of data received by BooleanSchema is not 1")
+	}
+	return nil
+}
+
+func (bs *BooleanSchema) GetSchemaInfo() *SchemaInfo {
+	return &bs.SchemaInfo
+}
+
 type Int8Schema struct {
 	SchemaInfo
 }
@@ -298,8 +378,12 @@ func NewInt8Schema(properties map[string]string) *Int8Schema {
 }
 
 func (is8 *Int8Schema) Encode(value interface{}) ([]byte, error) {
+	v, ok := value.(int8)
+	if !ok {
+		return nil, fmt.Errorf("Int8Schema expects an int8 value, received: %T", value)
+	}
 	var buf bytes.Buffer
-	err := WriteElements(&buf, value.(int8))
+	err := WriteElements(&buf, v)
 	return buf.Bytes(), err
 }
 
@@ -333,8 +417,12 @@ func NewInt16Schema(properties map[string]string) *Int16Schema {
 }
 
 func (is16 *Int16Schema) Encode(value interface{}) ([]byte, error) {
+	v, ok := value.(int16)
+	if !ok {
+		return nil, fmt.Errorf("Int16Schema expects an int16 value, received: %T", value)
+	}
 	var buf bytes.Buffer
-	err := WriteElements(&buf, value.(int16))
+	err := WriteElements(&buf, v)
 	return buf.Bytes(), err
 }
 
@@ -368,8 +456,12 @@ func NewInt32Schema(properties map[string]string) *Int32Schema {
 }
 
 func (is32 *Int32Schema) Encode(value interface{}) ([]byte, error) {
+	v, ok := value.(int32)
+	if !ok {
+		return nil, fmt.Errorf("Int32Schema expects an int32 value, received: %T", value)
+	}
 	var buf bytes.Buffer
-	err := WriteElements(&buf, value.(int32))
+	err := WriteElements(&buf, v)
 	return buf.Bytes(), err
 }
 
@@ -403,8 +495,12 @@ func NewInt64Schema(properties map[string]string) *Int64Schema {
 }
 
 func (is64 *Int64Schema) Encode(value interface{}) ([]byte, error) {
+	v, ok := value.(int64)
+	if !ok {
+		return nil, fmt.Errorf("Int64Schema expects an int64 value, received: %T", value)
+	}
 	var buf bytes.Buffer
-	err := WriteElements(&buf, value.(int64))
+	err := WriteElements(&buf, v)
 	return buf.Bytes(), err
 }
 
@@ -444,7 +540,6 @@ func (fs *FloatSchema) Encode(value interface{}) ([]byte, error) {
 func (fs *FloatSchema) Decode(data []byte, v interface{}) error {
 	floatValue, err := BinarySerializer.Float32(data)
 	if err != nil {
-		log.Errorf("unSerialize float error:%s", err.Error())
 		return err
 	}
 	reflect.ValueOf(v).Elem().Set(reflect.ValueOf(floatValue))
@@ -482,7 +577,6 @@ func (ds *DoubleSchema) Encode(value interface{}) ([]byte, error) {
 func (ds *DoubleSchema) Decode(data []byte, v interface{}) error {
 	doubleValue, err := BinarySerializer.Float64(data)
 	if err != nil {
-		log.Errorf("unSerialize double value error:%s", err.Error())
 		return err
 	}
 	reflect.ValueOf(v).Elem().Set(reflect.ValueOf(doubleValue))
@@ -499,3 +593,71 @@ func (ds *DoubleSchema) Validate(message []byte) error {
 func (ds *DoubleSchema) GetSchemaInfo() *SchemaInfo {
 	return &ds.SchemaInfo
 }
+
+// AutoConsumeSchema decodes the messages received by a consumer into a GenericRecord, with the schema
+// the topic had when they were published:
+//
+//	var record pulsar.GenericRecord
+//	err := msg.GetSchemaValue(&record)
+type AutoConsumeSchema struct {
+	SchemaInfo
+}
+
+func NewAutoConsumeSchema() *AutoConsumeSchema {
+	autoConsumeSchema := new(AutoConsumeSchema)
+	autoConsumeSchema.SchemaInfo.Name = "AutoConsume"
+	autoConsumeSchema.SchemaInfo.Type = AutoConsume
+	autoConsumeSchema.SchemaInfo.Schema = ""
+	return autoConsumeSchema
+}
+
+func (acs *AutoConsumeSchema) Encode(v interface{}) ([]byte, error) {
+	return nil, newError(OperationNotSupported, "AutoConsumeSchema can't encode messages")
+}
+
+func (acs *AutoConsumeSchema) Decode(data []byte, v interface{}) error {
+	return newError(OperationNotSupported, "AutoConsumeSchema only decodes the messages received by a consumer")
+}
+
+func (acs *AutoConsumeSchema) Validate(message []byte) error {
+	return nil
+}
+
+func (acs *AutoConsumeSchema) GetSchemaInfo() *SchemaInfo {
+	return &acs.SchemaInfo
+}
+
+// atVersion returns the schema decoding the messages of the topic published with the given schema version
+func (acs *AutoConsumeSchema) atVersion(topic string, schemaVersion []byte,
+	getSchema func(topic string, schemaVersion []byte) (*SchemaInfo, error)) Schema {
+	return &versionedAutoConsumeSchema{
+		AutoConsumeSchema: acs,
+		topic:             topic,
+		schemaVersion:     schemaVersion,
+		getSchema:         getSchema,
+	}
+}
+
+type versionedAutoConsumeSchema struct {
+	*AutoConsumeSchema
+	topic         string
+	schemaVersion []byte
+	getSchema     func(topic string, schemaVersion []byte) (*SchemaInfo, error)
+}
+
+func (s *versionedAutoConsumeSchema) Decode(data []byte, v interface{}) error {
+	record, ok := v.(*GenericRecord)
+	if !ok {
+		return fmt.Errorf("AutoConsumeSchema decodes into a *GenericRecord, received: %T", v)
+	}
+	info, err := s.getSchema(s.topic, s.schemaVersion)
+	if err != nil {
+		return err
+	}
+	r, err := newGenericRecord(info, data)
+	if err != nil {
+		return err
+	}
+	*record = r
+	return nil
+}
@@ -19,7 +19,9 @@ package pulsar
 
 import (
 	"context"
+	"errors"
 	"log"
+	"reflect"
 	"testing"
 
 	"github.com/apache/pulsar-client-go/integration-tests/pb"
@@ -182,6 +184,97 @@ func TestAvroSchema(t *testing.T) {
 	defer consumer.Close()
 }
 
+func TestSchemaValidate(t *testing.T) {
+	as := NewAvroSchema(exampleSchemaDef, nil)
+	data, err := as.Encode(testAvro{ID: 100, Name: "pulsar"})
+	assert.Nil(t, err)
+	assert.Nil(t, as.Validate(data))
+	assert.NotNil(t, as.Validate([]byte{0x01}))
+
+	js := NewJSONSchema(exampleSchemaDef, nil)
+	data, err = js.Encode(testJSON{ID: 100, Name: "pulsar"})
+	assert.Nil(t, err)
+	assert.Nil(t, js.Validate(data))
+	assert.NotNil(t, js.Validate([]byte("{")))
+}
+
+func TestSchemaWithValidation(t *testing.T) {
+	as, err := NewAvroSchemaWithValidation(exampleSchemaDef, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, AVRO, as.GetSchemaInfo().Type)
+	js, err := NewJSONSchemaWithValidation(exampleSchemaDef, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, JSON, js.GetSchemaInfo().Type)
+	ps, err := NewProtoSchemaWithValidation(exampleSchemaDef, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, PROTOBUF, ps.GetSchemaInfo().Type)
+
+	invalidSchemaDef := `{"type":"record","name":"Example"}`
+	_, err = NewAvroSchemaWithValidation(invalidSchemaDef, nil)
+	assert.NotNil(t, err)
+	_, err = NewJSONSchemaWithValidation(invalidSchemaDef, nil)
+	assert.NotNil(t, err)
+	_, err = NewProtoSchemaWithValidation(invalidSchemaDef, nil)
+	assert.NotNil(t, err)
+}
+
+func TestProducerSchemaEncodeError(t *testing.T) {
+	client := createClient()
+	defer client.Close()
+
+	producer, err := client.CreateProducer(ProducerOptions{
+		Topic:  newTopicName(),
+		Schema: NewAvroSchema(exampleSchemaDef, nil),
+	})
+	assert.Nil(t, err)
+	defer producer.Close()
+
+	_, err = producer.Send(context.Background(), &ProducerMessage{
+		Value: "not a record",
+	})
+	var e *Error
+	assert.True(t, errors.As(err, &e))
+	assert.Equal(t, SchemaSerializationError, e.Result())
+}
+
+func TestPrimitiveSchemasEncoding(t *testing.T) {
+	// the encodings of the Java client
+	tests := []struct {
+		schema Schema
+		value  interface{}
+		data   []byte
+	}{
+		{NewStringSchema(nil), "pulsar", []byte("pulsar")},
+		{NewBytesSchema(nil), []byte{0x01, 0x02}, []byte{0x01, 0x02}},
+		{NewBooleanSchema(nil), true, []byte{0x01}},
+		{NewInt8Schema(nil), int8(-1), []byte{0xff}},
+		{NewInt16Schema(nil), int16(258), []byte{0x01, 0x02}},
+		{NewInt32Schema(nil), int32(16909060), []byte{0x01, 0x02, 0x03, 0x04}},
+		{NewInt64Schema(nil), int64(72623859790382856), []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08}},
+		{NewFloatSchema(nil), float32(1.5), []byte{0x3f, 0xc0, 0x00, 0x00}},
+		{NewDoubleSchema(nil), float64(1.5), []byte{0x3f, 0xf8, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}},
+	}
+
+	for _, test := range tests {
+		data, err := test.schema.Encode(test.value)
+		assert.Nil(t, err)
+		assert.Equal(t, test.data, data)
+		assert.Nil(t, test.schema.Validate(data))
+
+		v := reflect.New(reflect.TypeOf(test.value))
+		assert.Nil(t, test.schema.Decode(data, v.Interface()))
+		assert.Equal(t, test.value, v.Elem().Interface())
+	}
+}
+
+func TestPrimitiveSchemasEncodeWrongType(t *testing.T) {
+	for _, schema := range []Schema{NewStringSchema(nil), NewBytesSchema(nil), NewBooleanSchema(nil),
+		NewInt8Schema(nil), NewInt16Schema(nil), NewInt32Schema(nil), NewInt64Schema(nil)} {
+		_, err := schema.Encode(struct{}{})
+		assert.NotNil(t, err)
+	}
+}
+
 func TestStringSchema(t *testing.T) {
 	client := createClient()
 	defer client.Close()
@@ -217,6 +310,33 @@ func TestStringSchema(t *testing.T) {
 	defer consumer.Close()
 }
 
+func TestConsumerIncompatibleSchema(t *testing.T) {
+	client := createClient()
+	defer client.Close()
+
+	topic := newTopicName()
+	producer, err := client.CreateProducer(ProducerOptions{
+		Topic:  topic,
+		Schema: NewStringSchema(nil),
+	})
+	assert.Nil(t, err)
+	defer producer.Close()
+
+	for _, topics := range [][]string{{topic}, {topic, newTopicName()}} {
+		consumer, err := client.Subscribe(ConsumerOptions{
+			Topics:           topics,
+			SubscriptionName: "sub-incompatible",
+			Schema:           NewInt64Schema(nil),
+		})
+		assert.Nil(t, consumer)
+
+		var e *Error
+		if assert.True(t, errors.As(err, &e)) {
+			assert.Equal(t, IncompatibleSchema, e.Result())
+		}
+	}
+}
+
 func TestInt8Schema(t *testing.T) {
 	client := createClient()
 	defer client.Close()
@@ -0,0 +1,75 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package pulsar
+
+import (
+	"reflect"
+	"time"
+)
+
+// TableViewOptions abstraction TableView options to use.
+type TableViewOptions struct {
+	// Topic specify the (compacted) topic the table view is built from.
+	// This argument is required when constructing the table view.
+	Topic string
+
+	// Schema represents the schema implementation used to decode the values of the messages.
+	// The raw payloads are kept as []byte values when it is not set.
+	Schema Schema
+
+	// SchemaValueType is the type of the values decoded by the Schema, eg. reflect.TypeOf(MyValue{}).
+	// This argument is required when the Schema is set.
+	SchemaValueType reflect.Type
+
+	// AutoDiscoveryPeriod is the interval in which to poll for the partitions added to the topic.
+	// Default is 1 minute.
+	AutoDiscoveryPeriod time.Duration
+}
+
+// TableView keeps the latest value of each key of a topic, it reads the topic from the beginning and
+// then keeps itself up to date with the new messages. The messages without payload delete their key.
+type TableView interface {
+	// Size returns the number of keys in the table view
+	Size() int
+
+	// IsEmpty checks whether the table view has no key
+	IsEmpty() bool
+
+	// ContainsKey checks whether the table view has a value for the key
+	ContainsKey(key string) bool
+
+	// Get returns the value of the key, or nil if the table view has no value for it
+	Get(key string) interface{}
+
+	// Entries returns a copy of the keys and values of the table view
+	Entries() map[string]interface{}
+
+	// Keys returns the keys of the table view
+	Keys() []string
+
+	// ForEach applies the action to each key and value of the table view
+	ForEach(func(string, interface{}) error) error
+
+	// ForEachAndListen applies the action to each key and value of the table view and then to each
+	// later update, the value is nil when the key was deleted. The action is called from the goroutines
+	// reading the topic, one call at a time, it must not close the table view.
+	ForEachAndListen(func(string, interface{}) error) error
+
+	// Close the table view and stop reading the topic
+	Close()
+}
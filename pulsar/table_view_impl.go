@@ -0,0 +1,334 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package pulsar
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar/log"
+)
+
+type tableView struct {
+	sync.RWMutex
+	data map[string]interface{}
+
+	// serializes the updates with the registration of the listeners so that none is missed or seen twice
+	listenersMutex sync.Mutex
+	listeners      []*tableViewListener
+
+	client    *client
+	options   TableViewOptions
+	readers   []Reader
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+	closeOnce sync.Once
+	log       log.Logger
+}
+
+// tableViewListener serializes the calls of a listener, the updates wait for the listener to be
+// applied to the existing entries
+type tableViewListener struct {
+	sync.Mutex
+	action  func(string, interface{}) error
+	removed bool
+}
+
+func newTableView(client *client, options TableViewOptions) (TableView, error) {
+	if options.Topic == "" {
+		return nil, newError(InvalidConfiguration, "Topic is required")
+	}
+	if options.Schema != nil && options.SchemaValueType == nil {
+		return nil, newError(InvalidConfiguration, "SchemaValueType is required when Schema is set")
+	}
+
+	partitions, err := client.TopicPartitions(options.Topic)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	tv := &tableView{
+		data:    make(map[string]interface{}),
+		client:  client,
+		options: options,
+		cancel:  cancel,
+		log:     client.log.SubLogger(log.Fields{"topic": options.Topic}),
+	}
+
+	for _, partition := range partitions {
+		reader, err := newReader(client, ReaderOptions{
+			Topic:          partition,
+			StartMessageID: EarliestMessageID(),
+			ReadCompacted:  true,
+			Schema:         options.Schema,
+		})
+		if err != nil {
+			tv.Close()
+			return nil, err
+		}
+		tv.readers = append(tv.readers, reader)
+
+		// catch up with the existing messages before handing out the table view
+		for reader.HasNext() {
+			msg, err := reader.Next(ctx)
+			if err != nil {
+				tv.Close()
+				return nil, err
+			}
+			tv.handleMessage(msg)
+		}
+	}
+
+	for _, reader := range tv.readers {
+		tv.wg.Add(1)
+		go tv.watch(ctx, reader)
+	}
+
+	duration := options.AutoDiscoveryPeriod
+	if duration <= 0 {
+		duration = defaultAutoDiscoveryDuration
+	}
+	tv.wg.Add(1)
+	go tv.runPartitionDiscovery(ctx, duration)
+
+	return tv, nil
+}
+
+// runPartitionDiscovery reads the partitions added to the topic until the table view is closed
+func (tv *tableView) runPartitionDiscovery(ctx context.Context, period time.Duration) {
+	defer tv.wg.Done()
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			tv.log.Debug("Auto discovering new partitions")
+			if err := tv.discoverPartitions(ctx); err != nil {
+				tv.log.WithError(err).Warn("Failed to read the new partitions")
+			}
+		}
+	}
+}
+
+// discoverPartitions creates the readers of the partitions added to the topic, the partitions are never
+// removed so the new ones are the last ones
+func (tv *tableView) discoverPartitions(ctx context.Context) error {
+	partitions, err := tv.client.TopicPartitions(tv.options.Topic)
+	if err != nil {
+		return err
+	}
+	for i := len(tv.readers); i < len(partitions); i++ {
+		reader, err := newReader(tv.client, ReaderOptions{
+			Topic:          partitions[i],
+			StartMessageID: EarliestMessageID(),
+			ReadCompacted:  true,
+			Schema:         tv.options.Schema,
+		})
+		if err != nil {
+			return err
+		}
+		tv.log.WithField("partition", partitions[i]).Info("Reading the new partition")
+		tv.readers = append(tv.readers, reader)
+		tv.wg.Add(1)
+		go tv.watch(ctx, reader)
+	}
+	return nil
+}
+
+func (tv *tableView) Size() int {
+	tv.RLock()
+	defer tv.RUnlock()
+	return len(tv.data)
+}
+
+func (tv *tableView) IsEmpty() bool {
+	return tv.Size() == 0
+}
+
+func (tv *tableView) ContainsKey(key string) bool {
+	tv.RLock()
+	defer tv.RUnlock()
+	_, ok := tv.data[key]
+	return ok
+}
+
+func (tv *tableView) Get(key string) interface{} {
+	tv.RLock()
+	defer tv.RUnlock()
+	return tv.data[key]
+}
+
+func (tv *tableView) Entries() map[string]interface{} {
+	tv.RLock()
+	defer tv.RUnlock()
+	entries := make(map[string]interface{}, len(tv.data))
+	for k, v := range tv.data {
+		entries[k] = v
+	}
+	return entries
+}
+
+func (tv *tableView) Keys() []string {
+	tv.RLock()
+	defer tv.RUnlock()
+	keys := make([]string, 0, len(tv.data))
+	for k := range tv.data {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+func (tv *tableView) ForEach(action func(string, interface{}) error) error {
+	for k, v := range tv.Entries() {
+		if err := action(k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (tv *tableView) ForEachAndListen(action func(string, interface{}) error) error {
+	listener := &tableViewListener{action: action}
+	listener.Lock()
+	defer listener.Unlock()
+
+	// the listener is registered along with the snapshot of the entries, it's called outside of
+	// listenersMutex so that it can use the table view
+	tv.listenersMutex.Lock()
+	entries := tv.Entries()
+	tv.listeners = append(tv.listeners, listener)
+	tv.listenersMutex.Unlock()
+
+	for k, v := range entries {
+		if err := action(k, v); err != nil {
+			tv.removeListener(listener)
+			return err
+		}
+	}
+	return nil
+}
+
+// removeListener unregisters the listener, the caller holds the lock of the listener
+func (tv *tableView) removeListener(listener *tableViewListener) {
+	tv.listenersMutex.Lock()
+	defer tv.listenersMutex.Unlock()
+
+	listener.removed = true
+	for i, l := range tv.listeners {
+		if l == listener {
+			tv.listeners = append(tv.listeners[:i], tv.listeners[i+1:]...)
+			return
+		}
+	}
+}
+
+func (tv *tableView) Close() {
+	tv.closeOnce.Do(func() {
+		tv.cancel()
+		tv.wg.Wait()
+		for _, reader := range tv.readers {
+			reader.Close()
+		}
+		tv.client.handlers.Del(tv)
+	})
+}
+
+// watch applies the messages of the reader to the table view until it is closed, the reads which failed are
+// retried with a backoff
+func (tv *tableView) watch(ctx context.Context, reader Reader) {
+	defer tv.wg.Done()
+	var backoff BackoffPolicy
+	for {
+		msg, err := reader.Next(ctx)
+		if err == nil {
+			backoff = nil
+			tv.handleMessage(msg)
+			continue
+		}
+		if ctx.Err() != nil {
+			return
+		}
+
+		if backoff == nil {
+			backoff = tv.client.backoffPolicy()
+		}
+		d := backoff.Next()
+		tv.log.WithError(err).Errorf("Failed to read the topic %s, retrying in %v", reader.Topic(), d)
+		select {
+		case <-time.After(d):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (tv *tableView) handleMessage(msg Message) {
+	var value interface{}
+	if len(msg.Payload()) > 0 {
+		v, err := tv.decode(msg)
+		if err != nil {
+			tv.log.WithError(err).Errorf("Failed to decode the message %v of key %q", msg.ID(), msg.Key())
+			return
+		}
+		value = v
+	}
+
+	tv.listenersMutex.Lock()
+	tv.Lock()
+	if value == nil {
+		delete(tv.data, msg.Key())
+	} else {
+		tv.data[msg.Key()] = value
+	}
+	tv.Unlock()
+	listeners := make([]*tableViewListener, len(tv.listeners))
+	copy(listeners, tv.listeners)
+	tv.listenersMutex.Unlock()
+
+	for _, listener := range listeners {
+		listener.call(tv, msg.Key(), value)
+	}
+}
+
+func (l *tableViewListener) call(tv *tableView, key string, value interface{}) {
+	l.Lock()
+	defer l.Unlock()
+
+	if l.removed {
+		return
+	}
+	if err := l.action(key, value); err != nil {
+		tv.log.WithError(err).Errorf("Table view listener failed for the key %q", key)
+	}
+}
+
+func (tv *tableView) decode(msg Message) (interface{}, error) {
+	if tv.options.Schema == nil {
+		return msg.Payload(), nil
+	}
+	v := reflect.New(tv.options.SchemaValueType)
+	if err := msg.GetSchemaValue(v.Interface()); err != nil {
+		return nil, err
+	}
+	return v.Elem().Interface(), nil
+}
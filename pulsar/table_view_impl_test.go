@@ -0,0 +1,183 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package pulsar
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/pulsar-client-go/pulsar/log"
+)
+
+func TestTableViewHandleMessage(t *testing.T) {
+	tv := &tableView{
+		data: make(map[string]interface{}),
+		log:  log.DefaultNopLogger(),
+	}
+
+	tv.handleMessage(&message{key: "k1", payLoad: []byte("v1")})
+	tv.handleMessage(&message{key: "k2", payLoad: []byte("v2")})
+	tv.handleMessage(&message{key: "k1", payLoad: []byte("v3")})
+	assert.Equal(t, 2, tv.Size())
+	assert.Equal(t, []byte("v3"), tv.Get("k1"))
+
+	// a message without payload deletes the key
+	tv.handleMessage(&message{key: "k2"})
+	assert.False(t, tv.ContainsKey("k2"))
+	assert.Equal(t, map[string]interface{}{"k1": []byte("v3")}, tv.Entries())
+	assert.Equal(t, []string{"k1"}, tv.Keys())
+}
+
+func TestTableViewDecodesValues(t *testing.T) {
+	schema := NewJSONSchema(exampleSchemaDef, nil)
+	tv := &tableView{
+		data: make(map[string]interface{}),
+		options: TableViewOptions{
+			Schema:          schema,
+			SchemaValueType: reflect.TypeOf(testJSON{}),
+		},
+		log: log.DefaultNopLogger(),
+	}
+
+	payload, err := schema.Encode(&testJSON{ID: 1, Name: "pulsar"})
+	assert.Nil(t, err)
+	tv.handleMessage(&message{key: "k", payLoad: payload, schema: schema})
+	assert.Equal(t, testJSON{ID: 1, Name: "pulsar"}, tv.Get("k"))
+}
+
+func TestTableViewForEachAndListen(t *testing.T) {
+	tv := &tableView{
+		data: make(map[string]interface{}),
+		log:  log.DefaultNopLogger(),
+	}
+	tv.handleMessage(&message{key: "k1", payLoad: []byte("v1")})
+
+	seen := make(map[string]interface{})
+	err := tv.ForEachAndListen(func(k string, v interface{}) error {
+		seen[k] = v
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{"k1": []byte("v1")}, seen)
+
+	tv.handleMessage(&message{key: "k2", payLoad: []byte("v2")})
+	tv.handleMessage(&message{key: "k1"})
+	assert.Equal(t, map[string]interface{}{"k1": nil, "k2": []byte("v2")}, seen)
+}
+
+func TestTableViewListenerUsesTableView(t *testing.T) {
+	tv := &tableView{
+		data: make(map[string]interface{}),
+		log:  log.DefaultNopLogger(),
+	}
+	tv.handleMessage(&message{key: "k1", payLoad: []byte("v1")})
+
+	// the listeners are called without the table view locks held
+	var nested map[string]interface{}
+	err := tv.ForEachAndListen(func(k string, v interface{}) error {
+		if k != "k2" {
+			return nil
+		}
+		nested = make(map[string]interface{})
+		return tv.ForEachAndListen(func(k string, v interface{}) error {
+			nested[k] = v
+			return nil
+		})
+	})
+	assert.Nil(t, err)
+
+	done := make(chan struct{})
+	go func() {
+		tv.handleMessage(&message{key: "k2", payLoad: []byte("v2")})
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the listener calling the table view deadlocked")
+	}
+	assert.Equal(t, map[string]interface{}{"k1": []byte("v1"), "k2": []byte("v2")}, nested)
+}
+
+func TestTableViewForEachAndListenError(t *testing.T) {
+	tv := &tableView{
+		data: make(map[string]interface{}),
+		log:  log.DefaultNopLogger(),
+	}
+	tv.handleMessage(&message{key: "k1", payLoad: []byte("v1")})
+
+	calls := 0
+	err := tv.ForEachAndListen(func(k string, v interface{}) error {
+		calls++
+		return errors.New("listener failed")
+	})
+	assert.NotNil(t, err)
+	assert.Empty(t, tv.listeners)
+
+	tv.handleMessage(&message{key: "k2", payLoad: []byte("v2")})
+	assert.Equal(t, 1, calls)
+}
+
+// failingReader fails the first reads, then returns its messages
+type failingReader struct {
+	Reader
+	failures int
+	messages chan Message
+}
+
+func (r *failingReader) Topic() string {
+	return "topic"
+}
+
+func (r *failingReader) Next(ctx context.Context) (Message, error) {
+	if r.failures > 0 {
+		r.failures--
+		return nil, errors.New("read failed")
+	}
+	select {
+	case msg := <-r.messages:
+		return msg, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func TestTableViewWatchRetries(t *testing.T) {
+	tv := &tableView{
+		data:   make(map[string]interface{}),
+		client: &client{backoffPolicy: newDefaultBackoff},
+		log:    log.DefaultNopLogger(),
+	}
+	reader := &failingReader{failures: 2, messages: make(chan Message, 1)}
+	reader.messages <- &message{key: "k", payLoad: []byte("v")}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	tv.wg.Add(1)
+	go tv.watch(ctx, reader)
+	assert.Eventually(t, func() bool {
+		return tv.ContainsKey("k")
+	}, 5*time.Second, 10*time.Millisecond)
+
+	cancel()
+	tv.wg.Wait()
+}
@@ -0,0 +1,129 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package pulsar
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTableView(t *testing.T) {
+	client, err := NewClient(ClientOptions{
+		URL: lookupURL,
+	})
+
+	assert.Nil(t, err)
+	defer client.Close()
+
+	topic := newTopicName()
+	schema := NewJSONSchema(exampleSchemaDef, nil)
+	ctx := context.Background()
+
+	producer, err := client.CreateProducer(ProducerOptions{
+		Topic:  topic,
+		Schema: schema,
+	})
+	assert.Nil(t, err)
+	defer producer.Close()
+
+	for i := 0; i < 10; i++ {
+		_, err := producer.Send(ctx, &ProducerMessage{
+			Key:   fmt.Sprintf("key-%d", i%5),
+			Value: &testJSON{ID: i, Name: "pulsar"},
+		})
+		assert.Nil(t, err)
+	}
+
+	tv, err := client.CreateTableView(TableViewOptions{
+		Topic:           topic,
+		Schema:          schema,
+		SchemaValueType: reflect.TypeOf(testJSON{}),
+	})
+	assert.Nil(t, err)
+	defer tv.Close()
+
+	// the existing messages are read before the table view is returned
+	assert.Equal(t, 5, tv.Size())
+	assert.Equal(t, testJSON{ID: 7, Name: "pulsar"}, tv.Get("key-2"))
+
+	updates := make(chan string, 10)
+	err = tv.ForEachAndListen(func(key string, value interface{}) error {
+		updates <- key
+		return nil
+	})
+	assert.Nil(t, err)
+	for i := 0; i < 5; i++ {
+		<-updates
+	}
+
+	_, err = producer.Send(ctx, &ProducerMessage{
+		Key:   "key-new",
+		Value: &testJSON{ID: 10, Name: "pulsar"},
+	})
+	assert.Nil(t, err)
+
+	select {
+	case key := <-updates:
+		assert.Equal(t, "key-new", key)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the table view wasn't updated")
+	}
+	assert.Equal(t, testJSON{ID: 10, Name: "pulsar"}, tv.Get("key-new"))
+}
+
+func TestTableViewPartitionDiscovery(t *testing.T) {
+	client, err := NewClient(ClientOptions{
+		URL: lookupURL,
+	})
+	assert.Nil(t, err)
+	defer client.Close()
+
+	topic := newTopicName()
+	testURL := adminURL + "/" + "admin/v2/persistent/public/default/" + topic + "/partitions"
+	makeHTTPCall(t, http.MethodPut, testURL, "1")
+
+	tv, err := client.CreateTableView(TableViewOptions{
+		Topic:               topic,
+		AutoDiscoveryPeriod: 100 * time.Millisecond,
+	})
+	assert.Nil(t, err)
+	defer tv.Close()
+
+	makeHTTPCall(t, http.MethodPost, testURL, "2")
+	producer, err := client.CreateProducer(ProducerOptions{
+		Topic: topic + "-partition-1",
+	})
+	assert.Nil(t, err)
+	defer producer.Close()
+	_, err = producer.Send(context.Background(), &ProducerMessage{
+		Key:     "key",
+		Payload: []byte("value"),
+	})
+	assert.Nil(t, err)
+
+	// the table view reads the partition added after its creation
+	assert.Eventually(t, func() bool {
+		return tv.ContainsKey("key")
+	}, 10*time.Second, 100*time.Millisecond)
+}
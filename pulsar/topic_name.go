@@ -0,0 +1,86 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package pulsar
+
+import (
+	"fmt"
+
+	"github.com/apache/pulsar-client-go/pulsar/internal"
+)
+
+// TopicName is a topic name parsed into its parts
+type TopicName struct {
+	// Domain is "persistent" or "non-persistent"
+	Domain string
+
+	// Tenant is the tenant of the topic, eg. "public"
+	Tenant string
+
+	// Namespace is the namespace of the topic including its tenant, eg. "public/default",
+	// and its cluster for the legacy names
+	Namespace string
+
+	// LocalName is the name of the topic in its namespace, including the partition suffix
+	LocalName string
+
+	// Partition is the index of the partition named by the topic, -1 when it isn't a partition
+	Partition int
+}
+
+// ParseTopicName parses a fully qualified topic name, eg. "persistent://public/default/my-topic", or a short one
+// which defaults to the persistent domain and, when only the local name is given, to the "public/default"
+// namespace. The tenant, cluster and namespace names may only contain letters, digits and the "-=:._" characters.
+func ParseTopicName(topic string) (*TopicName, error) {
+	tn, err := internal.ParseTopicName(topic)
+	if err != nil {
+		return nil, wrapError(InvalidTopicName, fmt.Sprintf("invalid topic name: %s", topic), err)
+	}
+	return &TopicName{
+		Domain:    tn.Domain,
+		Tenant:    tn.Tenant,
+		Namespace: tn.Namespace,
+		LocalName: tn.LocalName,
+		Partition: tn.Partition,
+	}, nil
+}
+
+// String returns the fully qualified name of the topic
+func (t *TopicName) String() string {
+	return fmt.Sprintf("%s://%s/%s", t.Domain, t.Namespace, t.LocalName)
+}
+
+// IsPersistent returns true if the topic is in the persistent domain
+func (t *TopicName) IsPersistent() bool {
+	return t.Domain == "persistent"
+}
+
+// IsPartition returns true if the name is the one of a partition of a partitioned topic
+func (t *TopicName) IsPartition() bool {
+	return t.Partition >= 0
+}
+
+// PartitionedTopicName returns the fully qualified name of the partitioned topic of a partition,
+// or the name itself when it isn't a partition
+func (t *TopicName) PartitionedTopicName() string {
+	return internal.TopicNameWithoutPartitionPart(&internal.TopicName{Name: t.String(), Partition: t.Partition})
+}
+
+// PartitionName returns the fully qualified name of the partition of the topic at the index
+func (t *TopicName) PartitionName(index int) string {
+	return fmt.Sprintf("%s-partition-%d", t.PartitionedTopicName(), index)
+}
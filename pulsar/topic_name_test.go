@@ -0,0 +1,55 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package pulsar
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTopicName(t *testing.T) {
+	tn, err := ParseTopicName("my-topic")
+	assert.Nil(t, err)
+	assert.Equal(t, "persistent://public/default/my-topic", tn.String())
+	assert.Equal(t, "public", tn.Tenant)
+	assert.Equal(t, "public/default", tn.Namespace)
+	assert.Equal(t, "my-topic", tn.LocalName)
+	assert.True(t, tn.IsPersistent())
+	assert.False(t, tn.IsPartition())
+	assert.Equal(t, "persistent://public/default/my-topic", tn.PartitionedTopicName())
+	assert.Equal(t, "persistent://public/default/my-topic-partition-3", tn.PartitionName(3))
+
+	tn, err = ParseTopicName("non-persistent://my-tenant/my-ns/my-topic-partition-1")
+	assert.Nil(t, err)
+	assert.Equal(t, "non-persistent://my-tenant/my-ns/my-topic-partition-1", tn.String())
+	assert.False(t, tn.IsPersistent())
+	assert.True(t, tn.IsPartition())
+	assert.Equal(t, 1, tn.Partition)
+	assert.Equal(t, "non-persistent://my-tenant/my-ns/my-topic", tn.PartitionedTopicName())
+	assert.Equal(t, "non-persistent://my-tenant/my-ns/my-topic-partition-0", tn.PartitionName(0))
+
+	for _, topic := range []string{"", "invalid://public/default/my-topic", "my tenant/my-ns/my-topic",
+		"public/default/my-topic-partition-x", "public/default"} {
+		_, err = ParseTopicName(topic)
+		var e *Error
+		assert.True(t, errors.As(err, &e), topic)
+		assert.Equal(t, InvalidTopicName, e.Result(), topic)
+	}
+}
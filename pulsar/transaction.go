@@ -0,0 +1,269 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package pulsar
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar/internal"
+	pb "github.com/apache/pulsar-client-go/pulsar/internal/pulsar_proto"
+	"github.com/apache/pulsar-client-go/pulsar/log"
+)
+
+const defaultTransactionTimeout = time.Minute
+
+// TxnID identifies a transaction
+type TxnID struct {
+	MostSigBits  uint64
+	LeastSigBits uint64
+}
+
+func (id TxnID) String() string {
+	return fmt.Sprintf("(%d,%d)", id.MostSigBits, id.LeastSigBits)
+}
+
+// TxnState is the state of a transaction
+type TxnState int
+
+const (
+	// TxnOpen means the transaction accepts new messages and acknowledgments
+	TxnOpen TxnState = iota + 1
+	// TxnCommitting means the transaction is being committed
+	TxnCommitting
+	// TxnCommitted means the transaction was committed
+	TxnCommitted
+	// TxnAborting means the transaction is being aborted
+	TxnAborting
+	// TxnAborted means the transaction was aborted
+	TxnAborted
+	// TxnError means the transaction failed to be committed or aborted
+	TxnError
+)
+
+func (s TxnState) String() string {
+	switch s {
+	case TxnOpen:
+		return "Open"
+	case TxnCommitting:
+		return "Committing"
+	case TxnCommitted:
+		return "Committed"
+	case TxnAborting:
+		return "Aborting"
+	case TxnAborted:
+		return "Aborted"
+	case TxnError:
+		return "Error"
+	default:
+		return fmt.Sprintf("TxnState(%d)", s)
+	}
+}
+
+// Transaction groups messages published and acknowledged on several topics, which are all committed
+// or all aborted.
+type Transaction interface {
+	// ID returns the id of the transaction
+	ID() TxnID
+
+	// State returns the current state of the transaction
+	State() TxnState
+
+	// Commit makes the messages published within the transaction visible to the consumers
+	// and the acknowledgments permanent
+	Commit(ctx context.Context) error
+
+	// Abort discards the messages published within the transaction and the acknowledgments
+	Abort(ctx context.Context) error
+}
+
+type transaction struct {
+	sync.Mutex
+	id       internal.TxnID
+	state    TxnState
+	tcClient internal.TransactionCoordinatorClient
+	log      log.Logger
+
+	// the partitions and subscriptions already registered with the coordinator
+	registeredPartitions    map[string]bool
+	registeredSubscriptions map[string]bool
+
+	// the sends of the transaction which haven't completed yet and the first one which failed
+	pendingOps sync.WaitGroup
+	opErr      error
+}
+
+func newTransaction(id internal.TxnID, tcClient internal.TransactionCoordinatorClient,
+	logger log.Logger) *transaction {
+	return &transaction{
+		id:                      id,
+		state:                   TxnOpen,
+		tcClient:                tcClient,
+		log:                     logger.SubLogger(log.Fields{"txnID": id}),
+		registeredPartitions:    make(map[string]bool),
+		registeredSubscriptions: make(map[string]bool),
+	}
+}
+
+func (txn *transaction) ID() TxnID {
+	return TxnID{MostSigBits: txn.id.MostSigBits, LeastSigBits: txn.id.LeastSigBits}
+}
+
+func (txn *transaction) State() TxnState {
+	txn.Lock()
+	defer txn.Unlock()
+	return txn.state
+}
+
+func (txn *transaction) Commit(ctx context.Context) error {
+	return txn.end(ctx, pb.TxnAction_COMMIT, TxnCommitting, TxnCommitted)
+}
+
+func (txn *transaction) Abort(ctx context.Context) error {
+	return txn.end(ctx, pb.TxnAction_ABORT, TxnAborting, TxnAborted)
+}
+
+func (txn *transaction) end(ctx context.Context, action pb.TxnAction, ending, ended TxnState) error {
+	txn.Lock()
+	if txn.state != TxnOpen {
+		state := txn.state
+		txn.Unlock()
+		return newError(InvalidTxnStatus, fmt.Sprintf("transaction %v is %v", txn.id, state))
+	}
+	txn.state = ending
+	txn.Unlock()
+
+	// the transaction is ended once all its messages are persisted
+	if err := txn.waitPendingOps(ctx); err != nil {
+		txn.Lock()
+		txn.state = TxnError
+		txn.Unlock()
+		return err
+	}
+
+	txn.Lock()
+	opErr := txn.opErr
+	txn.Unlock()
+	if opErr != nil && action == pb.TxnAction_COMMIT {
+		// a transaction missing some of its messages can't be committed
+		if err := txn.tcClient.EndTxn(ctx, txn.id, pb.TxnAction_ABORT); err != nil {
+			txn.log.WithError(err).Warn("Failed to abort the transaction")
+		}
+		txn.Lock()
+		txn.state = TxnAborted
+		txn.Unlock()
+		return wrapError(InvalidTxnStatus, fmt.Sprintf("transaction %v was aborted as a message failed", txn.id),
+			opErr)
+	}
+
+	err := txn.tcClient.EndTxn(ctx, txn.id, action)
+
+	txn.Lock()
+	defer txn.Unlock()
+	if err != nil {
+		txn.state = TxnError
+		return toClientError(err, "end transaction")
+	}
+	txn.state = ended
+	return nil
+}
+
+// registerOp adds an operation, eg. a send, that the transaction waits for before being committed or aborted
+func (txn *transaction) registerOp() error {
+	txn.Lock()
+	defer txn.Unlock()
+	if txn.state != TxnOpen {
+		return newError(InvalidTxnStatus, fmt.Sprintf("transaction %v is %v", txn.id, txn.state))
+	}
+	txn.pendingOps.Add(1)
+	return nil
+}
+
+// endOp completes an operation registered with registerOp
+func (txn *transaction) endOp(err error) {
+	if err != nil {
+		txn.Lock()
+		if txn.opErr == nil {
+			txn.opErr = err
+		}
+		txn.Unlock()
+	}
+	txn.pendingOps.Done()
+}
+
+func (txn *transaction) waitPendingOps(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		txn.pendingOps.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// registerPartition registers the partition the transaction publishes messages to with the coordinator
+func (txn *transaction) registerPartition(ctx context.Context, partition string) error {
+	if registered, err := txn.isRegistered(txn.registeredPartitions, partition); registered || err != nil {
+		return err
+	}
+
+	if err := txn.tcClient.AddPublishPartitionToTxn(ctx, txn.id, []string{partition}); err != nil {
+		return toClientError(err, "add partition to transaction")
+	}
+	txn.setRegistered(txn.registeredPartitions, partition)
+	return nil
+}
+
+// registerSubscription registers the subscription the transaction acknowledges messages on with the coordinator
+func (txn *transaction) registerSubscription(ctx context.Context, topic string, subscription string) error {
+	key := topic + "/" + subscription
+	if registered, err := txn.isRegistered(txn.registeredSubscriptions, key); registered || err != nil {
+		return err
+	}
+
+	if err := txn.tcClient.AddSubscriptionToTxn(ctx, txn.id, topic, subscription); err != nil {
+		return toClientError(err, "add subscription to transaction")
+	}
+	txn.setRegistered(txn.registeredSubscriptions, key)
+	return nil
+}
+
+// isRegistered checks under the lock whether the key was already registered with the coordinator, the request
+// registering it is sent without the lock so that the state and the other registrations aren't blocked, a key
+// registered concurrently is registered twice which the coordinator ignores
+func (txn *transaction) isRegistered(registered map[string]bool, key string) (bool, error) {
+	txn.Lock()
+	defer txn.Unlock()
+	if txn.state != TxnOpen {
+		return false, newError(InvalidTxnStatus, fmt.Sprintf("transaction %v is %v", txn.id, txn.state))
+	}
+	return registered[key], nil
+}
+
+func (txn *transaction) setRegistered(registered map[string]bool, key string) {
+	txn.Lock()
+	defer txn.Unlock()
+	registered[key] = true
+}
@@ -0,0 +1,203 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package pulsar
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/pulsar-client-go/pulsar/internal"
+	pb "github.com/apache/pulsar-client-go/pulsar/internal/pulsar_proto"
+	"github.com/apache/pulsar-client-go/pulsar/log"
+)
+
+type mockedTCClient struct {
+	partitions    []string
+	subscriptions []string
+	endActions    []pb.TxnAction
+	endErr        error
+	// when set, the registration of the partitions signals it started then waits to be released
+	addPartition chan struct{}
+}
+
+func (c *mockedTCClient) Start() error {
+	return nil
+}
+
+func (c *mockedTCClient) NewTransaction(ctx context.Context, timeout time.Duration) (internal.TxnID, error) {
+	return internal.TxnID{MostSigBits: 1, LeastSigBits: 2}, nil
+}
+
+func (c *mockedTCClient) AddPublishPartitionToTxn(ctx context.Context, id internal.TxnID,
+	partitions []string) error {
+	if c.addPartition != nil {
+		c.addPartition <- struct{}{}
+		<-c.addPartition
+	}
+	c.partitions = append(c.partitions, partitions...)
+	return nil
+}
+
+func (c *mockedTCClient) AddSubscriptionToTxn(ctx context.Context, id internal.TxnID, topic string,
+	subscription string) error {
+	c.subscriptions = append(c.subscriptions, topic+"/"+subscription)
+	return nil
+}
+
+func (c *mockedTCClient) EndTxn(ctx context.Context, id internal.TxnID, action pb.TxnAction) error {
+	c.endActions = append(c.endActions, action)
+	return c.endErr
+}
+
+func TestTransactionNotEnabled(t *testing.T) {
+	c := &client{}
+	_, err := c.NewTransaction(time.Minute)
+	assert.Error(t, err)
+	assert.Equal(t, InvalidConfiguration, err.(*Error).Result())
+}
+
+func TestTransactionCommit(t *testing.T) {
+	tc := &mockedTCClient{}
+	c := &client{tcClient: tc, log: log.DefaultNopLogger()}
+
+	txn, err := c.NewTransaction(time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, TxnID{MostSigBits: 1, LeastSigBits: 2}, txn.ID())
+	assert.Equal(t, TxnOpen, txn.State())
+
+	// the partitions and subscriptions are registered once
+	impl := txn.(*transaction)
+	assert.NoError(t, impl.registerPartition(context.Background(), "topic-1"))
+	assert.NoError(t, impl.registerPartition(context.Background(), "topic-1"))
+	assert.NoError(t, impl.registerSubscription(context.Background(), "topic-2", "sub"))
+	assert.NoError(t, impl.registerSubscription(context.Background(), "topic-2", "sub"))
+	assert.Equal(t, []string{"topic-1"}, tc.partitions)
+	assert.Equal(t, []string{"topic-2/sub"}, tc.subscriptions)
+
+	assert.NoError(t, txn.Commit(context.Background()))
+	assert.Equal(t, TxnCommitted, txn.State())
+	assert.Equal(t, []pb.TxnAction{pb.TxnAction_COMMIT}, tc.endActions)
+
+	// a committed transaction can't be used anymore
+	err = txn.Abort(context.Background())
+	assert.Equal(t, InvalidTxnStatus, err.(*Error).Result())
+	err = impl.registerPartition(context.Background(), "topic-3")
+	assert.Equal(t, InvalidTxnStatus, err.(*Error).Result())
+}
+
+func TestTransactionAbortFailure(t *testing.T) {
+	tc := &mockedTCClient{endErr: &internal.ServerError{Code: pb.ServerError_InvalidTxnStatus}}
+	txn := newTransaction(internal.TxnID{}, tc, log.DefaultNopLogger())
+
+	err := txn.Abort(context.Background())
+	assert.Equal(t, InvalidTxnStatus, err.(*Error).Result())
+	assert.True(t, errors.Is(err, tc.endErr))
+	assert.Equal(t, TxnError, txn.State())
+}
+
+func TestTransactionWaitsForPendingSends(t *testing.T) {
+	tc := &mockedTCClient{}
+	txn := newTransaction(internal.TxnID{}, tc, log.DefaultNopLogger())
+	p := &partitionProducer{topic: "my-topic"}
+
+	var sendErr error
+	callback, err := p.registerTxnSend(context.Background(), &ProducerMessage{Transaction: txn},
+		func(id MessageID, msg *ProducerMessage, err error) {
+			sendErr = err
+		})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"my-topic"}, tc.partitions)
+
+	committed := make(chan error)
+	go func() {
+		committed <- txn.Commit(context.Background())
+	}()
+
+	select {
+	case <-committed:
+		assert.Fail(t, "the transaction shouldn't be committed before the send completes")
+	case <-time.After(100 * time.Millisecond):
+	}
+	assert.Equal(t, TxnCommitting, txn.State())
+
+	// no message can be added once the transaction is being committed
+	_, err = p.registerTxnSend(context.Background(), &ProducerMessage{Transaction: txn}, nil)
+	assert.Equal(t, InvalidTxnStatus, err.(*Error).Result())
+
+	callback(nil, nil, nil)
+	assert.NoError(t, <-committed)
+	assert.NoError(t, sendErr)
+	assert.Equal(t, TxnCommitted, txn.State())
+}
+
+func TestTransactionAbortedOnSendFailure(t *testing.T) {
+	tc := &mockedTCClient{}
+	txn := newTransaction(internal.TxnID{}, tc, log.DefaultNopLogger())
+	p := &partitionProducer{topic: "my-topic"}
+
+	callback, err := p.registerTxnSend(context.Background(), &ProducerMessage{Transaction: txn}, nil)
+	assert.NoError(t, err)
+	callback(nil, nil, errSendTimeout)
+
+	err = txn.Commit(context.Background())
+	assert.Equal(t, InvalidTxnStatus, err.(*Error).Result())
+	assert.True(t, errors.Is(err, errSendTimeout))
+	assert.Equal(t, TxnAborted, txn.State())
+	assert.Equal(t, []pb.TxnAction{pb.TxnAction_ABORT}, tc.endActions)
+}
+
+func TestTransactionCommitTimeout(t *testing.T) {
+	txn := newTransaction(internal.TxnID{}, &mockedTCClient{}, log.DefaultNopLogger())
+	assert.NoError(t, txn.registerOp())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, txn.Commit(ctx))
+	assert.Equal(t, TxnError, txn.State())
+}
+
+func TestTransactionRegisterWithoutLock(t *testing.T) {
+	tc := &mockedTCClient{addPartition: make(chan struct{})}
+	txn := newTransaction(internal.TxnID{MostSigBits: 1, LeastSigBits: 2}, tc, log.DefaultNopLogger())
+
+	registered := make(chan error, 1)
+	go func() {
+		registered <- txn.registerPartition(context.Background(), "topic-1")
+	}()
+	<-tc.addPartition
+
+	// the state is available while the partition is being registered
+	state := make(chan TxnState, 1)
+	go func() {
+		state <- txn.State()
+	}()
+	select {
+	case s := <-state:
+		assert.Equal(t, TxnOpen, s)
+	case <-time.After(time.Second):
+		t.Fatal("the state is blocked by the registration of the partition")
+	}
+
+	tc.addPartition <- struct{}{}
+	assert.NoError(t, <-registered)
+	assert.Equal(t, []string{"topic-1"}, tc.partitions)
+}
@@ -0,0 +1,158 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package pulsar
+
+import (
+	"sync"
+	"time"
+)
+
+// unackedTracker keeps the messages received from the broker that weren't acked or nacked yet,
+// with the time they were received and the deadline after which the broker is asked to redeliver them
+type unackedTracker struct {
+	sync.Mutex
+	// the ack timeout, the messages have no deadline when it is not positive
+	timeout time.Duration
+	// the delay added after the ack timeout of a message before it is redelivered, none when nil
+	backoff  RedeliveryBackoff
+	messages map[messageID]unackedEntry
+}
+
+type unackedEntry struct {
+	received        time.Time
+	deadline        time.Time
+	redeliveryCount uint32
+	// the ack timeout passed and the deadline is the end of the redelivery backoff
+	backingOff bool
+}
+
+// add tracks the message, it returns false when the message was already tracked
+func (t *unackedTracker) add(msgID messageID, receivedTime time.Time, redeliveryCount uint32) bool {
+	t.Lock()
+	defer t.Unlock()
+	if t.messages == nil {
+		t.messages = make(map[messageID]unackedEntry)
+	}
+	_, tracked := t.messages[msgID]
+	entry := unackedEntry{received: receivedTime, redeliveryCount: redeliveryCount}
+	if t.timeout > 0 {
+		entry.deadline = receivedTime.Add(t.timeout)
+	}
+	t.messages[msgID] = entry
+	return !tracked
+}
+
+// extend moves the deadline of the message, it returns false when the message isn't tracked
+func (t *unackedTracker) extend(msgID messageID, deadline time.Time) bool {
+	t.Lock()
+	defer t.Unlock()
+	entry, tracked := t.messages[msgID]
+	if !tracked {
+		return false
+	}
+	entry.deadline = deadline
+	entry.backingOff = false
+	t.messages[msgID] = entry
+	return true
+}
+
+// expired stops tracking and returns the messages whose deadline passed, the messages reaching their ack timeout
+// with a redelivery backoff stay tracked until the end of the backoff instead
+func (t *unackedTracker) expired(now time.Time) []messageID {
+	t.Lock()
+	defer t.Unlock()
+	var ids []messageID
+	for id, entry := range t.messages {
+		if !entry.deadline.IsZero() && !now.Before(entry.deadline) {
+			if t.backoff != nil && !entry.backingOff {
+				if delay := t.backoff(entry.redeliveryCount); delay > 0 {
+					entry.deadline = now.Add(delay)
+					entry.backingOff = true
+					t.messages[id] = entry
+					continue
+				}
+			}
+			delete(t.messages, id)
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func (t *unackedTracker) tracked(msgID messageID) bool {
+	t.Lock()
+	defer t.Unlock()
+	_, tracked := t.messages[msgID]
+	return tracked
+}
+
+// remove stops tracking the message, it returns false when the message wasn't tracked
+func (t *unackedTracker) remove(msgID messageID) bool {
+	t.Lock()
+	defer t.Unlock()
+	if _, tracked := t.messages[msgID]; !tracked {
+		return false
+	}
+	delete(t.messages, msgID)
+	return true
+}
+
+// removeUpTo stops tracking the messages up to and including the given one, it returns how many were tracked
+func (t *unackedTracker) removeUpTo(msgID messageID) int {
+	t.Lock()
+	defer t.Unlock()
+	n := 0
+	for id := range t.messages {
+		if !id.greater(msgID) {
+			delete(t.messages, id)
+			n++
+		}
+	}
+	return n
+}
+
+// clear stops tracking all the messages and returns how many were tracked
+func (t *unackedTracker) clear() int {
+	t.Lock()
+	defer t.Unlock()
+	n := len(t.messages)
+	t.messages = nil
+	return n
+}
+
+func (t *unackedTracker) size() int {
+	t.Lock()
+	defer t.Unlock()
+	return len(t.messages)
+}
+
+// oldestAge returns for how long the oldest tracked message has been received, or 0 when none is tracked
+func (t *unackedTracker) oldestAge(now time.Time) time.Duration {
+	t.Lock()
+	defer t.Unlock()
+	var oldest time.Time
+	for _, entry := range t.messages {
+		if oldest.IsZero() || entry.received.Before(oldest) {
+			oldest = entry.received
+		}
+	}
+	if oldest.IsZero() {
+		return 0
+	}
+	return now.Sub(oldest)
+}
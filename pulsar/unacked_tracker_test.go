@@ -0,0 +1,108 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package pulsar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnackedTracker(t *testing.T) {
+	tracker := &unackedTracker{}
+	assert.Equal(t, 0, tracker.size())
+	assert.Equal(t, time.Duration(0), tracker.oldestAge(time.Now()))
+	assert.False(t, tracker.remove(messageID{ledgerID: 1}))
+
+	now := time.Now()
+	assert.True(t, tracker.add(messageID{ledgerID: 1, entryID: 1}, now.Add(-2*time.Second), 0))
+	assert.True(t, tracker.add(messageID{ledgerID: 1, entryID: 2}, now.Add(-time.Second), 0))
+	assert.False(t, tracker.add(messageID{ledgerID: 1, entryID: 2}, now, 0))
+	assert.Equal(t, 2, tracker.size())
+	assert.Equal(t, 2*time.Second, tracker.oldestAge(now))
+
+	assert.True(t, tracker.remove(messageID{ledgerID: 1, entryID: 1}))
+	assert.Equal(t, 1, tracker.size())
+	assert.Equal(t, time.Duration(0), tracker.oldestAge(now))
+
+	assert.Equal(t, 1, tracker.clear())
+	assert.Equal(t, 0, tracker.size())
+
+	for i := 0; i < 5; i++ {
+		tracker.add(messageID{ledgerID: 1, entryID: int64(i)}, now, 0)
+	}
+	assert.Equal(t, 3, tracker.removeUpTo(messageID{ledgerID: 1, entryID: 2}))
+	assert.Equal(t, 2, tracker.size())
+}
+
+func TestUnackedTrackerDeadlines(t *testing.T) {
+	tracker := &unackedTracker{}
+	now := time.Now()
+	tracker.add(messageID{ledgerID: 1, entryID: 1}, now, 0)
+	assert.Empty(t, tracker.expired(now.Add(time.Hour)))
+
+	tracker = &unackedTracker{timeout: time.Second}
+	assert.False(t, tracker.extend(messageID{ledgerID: 1, entryID: 1}, now))
+	for i := 0; i < 3; i++ {
+		tracker.add(messageID{ledgerID: 1, entryID: int64(i)}, now, 0)
+	}
+	assert.True(t, tracker.extend(messageID{ledgerID: 1, entryID: 1}, now.Add(time.Minute)))
+	assert.Empty(t, tracker.expired(now))
+
+	expired := tracker.expired(now.Add(time.Second))
+	assert.ElementsMatch(t, []messageID{{ledgerID: 1, entryID: 0}, {ledgerID: 1, entryID: 2}}, expired)
+	assert.Equal(t, 1, tracker.size())
+	assert.Equal(t, time.Minute, tracker.oldestAge(now.Add(time.Minute)))
+
+	assert.Equal(t, []messageID{{ledgerID: 1, entryID: 1}}, tracker.expired(now.Add(time.Minute)))
+	assert.Equal(t, 0, tracker.size())
+}
+
+func TestUnackedTrackerRedeliveryBackoff(t *testing.T) {
+	tracker := &unackedTracker{
+		timeout: time.Second,
+		backoff: NewExponentialRedeliveryBackoff(time.Second, 10*time.Second, 2),
+	}
+	now := time.Now()
+	tracker.add(messageID{ledgerID: 1, entryID: 1}, now, 0)
+	tracker.add(messageID{ledgerID: 1, entryID: 2}, now, 2)
+
+	// the messages reaching the ack timeout are kept for the backoff of their redelivery count
+	assert.Empty(t, tracker.expired(now.Add(time.Second)))
+	assert.Equal(t, 2, tracker.size())
+	assert.Equal(t, []messageID{{ledgerID: 1, entryID: 1}}, tracker.expired(now.Add(2*time.Second)))
+	assert.Empty(t, tracker.expired(now.Add(4*time.Second)))
+	assert.Equal(t, []messageID{{ledgerID: 1, entryID: 2}}, tracker.expired(now.Add(5*time.Second)))
+
+	// extending the deadline starts a new ack timeout followed by the backoff
+	tracker.add(messageID{ledgerID: 1, entryID: 3}, now, 0)
+	assert.Empty(t, tracker.expired(now.Add(time.Second)))
+	assert.True(t, tracker.extend(messageID{ledgerID: 1, entryID: 3}, now.Add(3*time.Second)))
+	assert.Empty(t, tracker.expired(now.Add(3*time.Second)))
+	assert.Equal(t, []messageID{{ledgerID: 1, entryID: 3}}, tracker.expired(now.Add(4*time.Second)))
+}
+
+func TestExponentialRedeliveryBackoff(t *testing.T) {
+	backoff := NewExponentialRedeliveryBackoff(100*time.Millisecond, time.Second, 2)
+	assert.Equal(t, 100*time.Millisecond, backoff(0))
+	assert.Equal(t, 200*time.Millisecond, backoff(1))
+	assert.Equal(t, 800*time.Millisecond, backoff(3))
+	assert.Equal(t, time.Second, backoff(4))
+	assert.Equal(t, time.Second, backoff(1000))
+}